@@ -80,6 +80,9 @@ func parseFlags(cmd *command) {
 		if f.Name == "bookmarks" || f.Name == "b" {
 			bookmarksSet = true
 		}
+		if f.Name == "linearize" || f.Name == "lin" {
+			linearizeSet = true
+		}
 		if f.Name == "offline" || f.Name == "off" || f.Name == "o" {
 			offlineSet = true
 		}
@@ -126,15 +129,19 @@ func ensureDefaultConfig() (*model.Configuration, error) {
 func (m commandMap) process(cmdPrefix string, command string) (string, error) {
 	var cmdStr string
 
-	// Support command completion.
-	for k := range m {
-		if !strings.HasPrefix(k, cmdPrefix) {
-			continue
-		}
-		if len(cmdStr) > 0 {
-			return command, errAmbiguousCmd
+	// Support command completion unless there is an exact match eg. sign vs. signatures.
+	if _, ok := m[cmdPrefix]; ok {
+		cmdStr = cmdPrefix
+	} else {
+		for k := range m {
+			if !strings.HasPrefix(k, cmdPrefix) {
+				continue
+			}
+			if len(cmdStr) > 0 {
+				return command, errAmbiguousCmd
+			}
+			cmdStr = k
 		}
-		cmdStr = k
 	}
 
 	if cmdStr == "" {
@@ -155,6 +162,10 @@ func (m commandMap) process(cmdPrefix string, command string) (string, error) {
 		conf.Offline = offline
 	}
 
+	if linearizeSet {
+		conf.Linearize = linearize
+	}
+
 	if m[cmdStr].handler != nil {
 
 		if conf.Version != model.VersionStr && cmdStr != "reset" {
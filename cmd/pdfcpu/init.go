@@ -123,6 +123,65 @@ func initImagesCmdMap() commandMap {
 	return m
 }
 
+func initSignaturesCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":     {processListSignaturesCommand, nil, "", ""},
+		"validate": {processValidateSignaturesCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initRevisionsCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":    {processListRevisionsCommand, nil, "", ""},
+		"extract": {processExtractRevisionCommand, nil, "", ""},
+		"diff":    {processDiffRevisionsCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initLayersCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":    {processListLayersCommand, nil, "", ""},
+		"add":     {processAddLayersCommand, nil, "", ""},
+		"remove":  {processRemoveLayersCommand, nil, "", ""},
+		"show":    {processShowLayersCommand, nil, "", ""},
+		"hide":    {processHideLayersCommand, nil, "", ""},
+		"flatten": {processFlattenLayersCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initStructureCmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"list":   {processListStructureCommand, nil, "", ""},
+		"export": {processExportStructureCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
+func initPDFACmdMap() commandMap {
+	m := newCommandMap()
+	for k, v := range map[string]command{
+		"convert": {processPDFAConvertCommand, nil, "", ""},
+	} {
+		m.register(k, v)
+	}
+	return m
+}
+
 func initKeywordsCmdMap() commandMap {
 	m := newCommandMap()
 	for k, v := range map[string]command{
@@ -252,11 +311,16 @@ func initCommandMap() {
 	formCmdMap := initFormCmdMap()
 	imagesCmdMap := initImagesCmdMap()
 	keywordsCmdMap := initKeywordsCmdMap()
+	layersCmdMap := initLayersCmdMap()
 	pagesCmdMap := initPagesCmdMap()
+	pdfaCmdMap := initPDFACmdMap()
 	permissionsCmdMap := initPermissionsCmdMap()
 	portfolioCmdMap := initPortfolioCmdMap()
 	propertiesCmdMap := initPropertiesCmdMap()
+	revisionsCmdMap := initRevisionsCmdMap()
+	signaturesCmdMap := initSignaturesCmdMap()
 	stampCmdMap := initStampCmdMap()
+	structureCmdMap := initStructureCmdMap()
 	watermarkCmdMap := initWatermarkCmdMap()
 	pageModeCmdMap := initPageModeCmdMap()
 	pageLayoutCmdMap := initPageLayoutCmdMap()
@@ -289,6 +353,7 @@ func initCommandMap() {
 		"import":        {processImportImagesCommand, nil, usageImportImages, usageLongImportImages},
 		"info":          {processInfoCommand, nil, usageInfo, usageLongInfo},
 		"keywords":      {nil, keywordsCmdMap, usageKeywords, usageLongKeywords},
+		"layers":        {nil, layersCmdMap, usageLayers, usageLongLayers},
 		"merge":         {processMergeCommand, nil, usageMerge, usageLongMerge},
 		"ndown":         {processNDownCommand, nil, usageNDown, usageLongNDown},
 		"nup":           {processNUpCommand, nil, usageNUp, usageLongNUp},
@@ -297,15 +362,23 @@ func initCommandMap() {
 		"pagemode":      {nil, pageModeCmdMap, usagePageMode, usageLongPageMode},
 		"pages":         {nil, pagesCmdMap, usagePages, usageLongPages},
 		"paper":         {printPaperSizes, nil, usagePaper, usageLongPaper},
+		"pdfa":          {nil, pdfaCmdMap, usagePDFA, usageLongPDFA},
 		"permissions":   {nil, permissionsCmdMap, usagePerm, usageLongPerm},
 		"portfolio":     {nil, portfolioCmdMap, usagePortfolio, usageLongPortfolio},
 		"poster":        {processPosterCommand, nil, usagePoster, usageLongPoster},
 		"properties":    {nil, propertiesCmdMap, usageProperties, usageLongProperties},
+		"redact":        {processRedactCommand, nil, usageRedact, usageLongRedact},
+		"render":        {processRenderCommand, nil, usageRender, usageLongRender},
+		"repair":        {processRepairCommand, nil, usageRepair, usageLongRepair},
 		"resize":        {processResizeCommand, nil, usageResize, usageLongResize},
+		"revisions":     {nil, revisionsCmdMap, usageRevisions, usageLongRevisions},
 		"rotate":        {processRotateCommand, nil, usageRotate, usageLongRotate},
 		"selectedpages": {printSelectedPages, nil, usageSelectedPages, usageLongSelectedPages},
+		"sign":          {processSignCommand, nil, usageSign, usageLongSign},
+		"signatures":    {nil, signaturesCmdMap, usageSignatures, usageLongSignatures},
 		"split":         {processSplitCommand, nil, usageSplit, usageLongSplit},
 		"stamp":         {nil, stampCmdMap, usageStamp, usageLongStamp},
+		"structure":     {nil, structureCmdMap, usageStructure, usageLongStructure},
 		"trim":          {processTrimCommand, nil, usageTrim, usageLongTrim},
 		"validate":      {processValidateCommand, nil, usageValidate, usageLongValidate},
 		"watermark":     {nil, watermarkCmdMap, usageWatermark, usageLongWatermark},
@@ -334,6 +407,9 @@ func initFlags() {
 	flag.BoolVar(&dividerPage, "dividerPage", false, dividerPageUsage)
 	flag.BoolVar(&dividerPage, "d", false, dividerPageUsage)
 
+	dpiUsage := "render: resolution in dots per inch"
+	flag.IntVar(&dpi, "dpi", 150, dpiUsage)
+
 	fontsUsage := "include font info"
 	flag.BoolVar(&fonts, "fonts", false, fontsUsage)
 	flag.BoolVar(&fonts, "f", false, fontsUsage)
@@ -346,11 +422,15 @@ func initFlags() {
 	flag.StringVar(&key, "key", "256", keyUsage)
 	flag.StringVar(&key, "k", "256", keyUsage)
 
+	linearizeUsage := "write linearized file (fast web view)"
+	flag.BoolVar(&linearize, "linearize", false, linearizeUsage)
+	flag.BoolVar(&linearize, "lin", false, linearizeUsage)
+
 	linksUsage := "check for broken links"
 	flag.BoolVar(&links, "links", false, linksUsage)
 	flag.BoolVar(&links, "l", false, linksUsage)
 
-	modeUsage := "validate: strict|relaxed; extract: image|font|content|page|meta; encrypt: rc4|aes; stamp:text|image/pdf"
+	modeUsage := "validate: strict|relaxed|pdfa|pdfua; extract: image|font|content|page|text|meta; encrypt: rc4|aes; stamp:text|image/pdf; render: png|jpg; pdfa convert: 1b|2b|3b"
 	flag.StringVar(&mode, "mode", "", modeUsage)
 	flag.StringVar(&mode, "m", "", modeUsage)
 
@@ -389,6 +469,7 @@ func initFlags() {
 
 	flag.StringVar(&upw, "upw", "", "user password")
 	flag.StringVar(&opw, "opw", "", "owner password")
+	flag.StringVar(&kpw, "kpw", "", "key password")
 
 	flag.BoolVar(&verbose, "verbose", false, "")
 	flag.BoolVar(&verbose, "v", false, "")
@@ -27,14 +27,17 @@ import (
 var (
 	fileStats, mode, selectedPages           string
 	upw, opw, key, perm, unit, conf          string
+	kpw                                      string // Sign
 	verbose, veryVerbose                     bool
-	links, quiet, offline                    bool
+	links, quiet, offline, linearize         bool
 	replaceBookmarks                         bool // Import Bookmarks
 	all                                      bool // List Viewer Preferences
 	fonts                                    bool // Info
 	json                                     bool // List Viewer Preferences, Info
 	bookmarks, dividerPage, optimize, sorted bool // Merge
 	bookmarksSet, offlineSet, optimizeSet    bool
+	linearizeSet                             bool
+	dpi                                      int // Render
 	needStackTrace                           = true
 	cmdMap                                   commandMap
 )
@@ -36,6 +36,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
@@ -197,8 +198,15 @@ func printVersion(conf *model.Configuration) {
 	fmt.Fprintf(os.Stdout, "config: %s\n", conf.Path)
 }
 
-func process(cmd *cli.Command) {
-	out, err := cli.Process(cmd)
+func printOutput(out []string) {
+	if out != nil && !quiet {
+		for _, s := range out {
+			fmt.Fprintln(os.Stdout, s)
+		}
+	}
+}
+
+func exitOnError(err error) {
 	if err != nil {
 		if needStackTrace {
 			fmt.Fprintf(os.Stderr, "Fatal: %+v\n", err)
@@ -207,12 +215,12 @@ func process(cmd *cli.Command) {
 		}
 		os.Exit(1)
 	}
+}
 
-	if out != nil && !quiet {
-		for _, s := range out {
-			fmt.Fprintln(os.Stdout, s)
-		}
-	}
+func process(cmd *cli.Command) {
+	out, err := cli.Process(cmd)
+	exitOnError(err)
+	printOutput(out)
 	//os.Exit(0)
 }
 
@@ -338,6 +346,10 @@ func processValidateCommand(conf *model.Configuration) {
 		conf.ValidationMode = model.ValidationStrict
 	case "relaxed", "r":
 		conf.ValidationMode = model.ValidationRelaxed
+	case "pdfa", "p":
+		conf.ValidatePDFA = true
+	case "pdfua", "u":
+		conf.ValidatePDFUA = true
 	case "":
 	default:
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageValidate)
@@ -578,7 +590,7 @@ func modeCompletion(modePrefix string, modes []string) string {
 }
 
 func processExtractCommand(conf *model.Configuration) {
-	mode = modeCompletion(mode, []string{"image", "font", "page", "content", "meta"})
+	mode = modeCompletion(mode, []string{"image", "font", "page", "content", "text", "meta"})
 	if len(flag.Args()) != 2 || mode == "" {
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageExtract)
 		os.Exit(1)
@@ -612,6 +624,9 @@ func processExtractCommand(conf *model.Configuration) {
 	case "content":
 		cmd = cli.ExtractContentCommand(inFile, outDir, pages, conf)
 
+	case "text":
+		cmd = cli.ExtractTextCommand(inFile, outDir, pages, json, conf)
+
 	case "meta":
 		cmd = cli.ExtractMetadataCommand(inFile, outDir, conf)
 
@@ -873,21 +888,57 @@ func processSetPermissionsCommand(conf *model.Configuration) {
 	process(cli.SetPermissionsCommand(inFile, "", conf))
 }
 
+// pdfAndKeyFiles splits args into inFile, optional outFile and key or certificate files.
+func pdfAndKeyFiles(args []string, usage string) (string, string, []string) {
+	if len(args) == 0 {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usage)
+		os.Exit(1)
+	}
+
+	inFile, outFile := args[0], ""
+	var keyFiles []string
+
+	for _, arg := range args[1:] {
+		if hasPDFExtension(arg) && outFile == "" && len(keyFiles) == 0 {
+			outFile = arg
+			continue
+		}
+		if !sign.IsKeyFile(arg) {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usage)
+			os.Exit(1)
+		}
+		keyFiles = append(keyFiles, arg)
+	}
+
+	return inFile, outFile, keyFiles
+}
+
 func processDecryptCommand(conf *model.Configuration) {
-	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+	if len(flag.Args()) == 0 || selectedPages != "" {
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageDecrypt)
 		os.Exit(1)
 	}
 
-	inFile := flag.Arg(0)
+	inFile, outFile, keyFiles := pdfAndKeyFiles(flag.Args(), usageDecrypt)
+
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
 
-	outFile := inFile
-	if len(flag.Args()) == 2 {
-		outFile = flag.Arg(1)
-		ensurePDFExtension(outFile)
+	if outFile == "" {
+		outFile = inFile
+	}
+
+	if len(keyFiles) > 0 {
+		// Public-key security handler.
+		cred, err := sign.LoadCredentials(kpw, keyFiles...)
+		if err == nil {
+			err = api.SetRecipientKey(conf, cred)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	process(cli.DecryptCommand(inFile, outFile, conf))
@@ -936,13 +987,25 @@ func processEncryptCommand(conf *model.Configuration) {
 	if perm != "" {
 		perm = permCompletion(perm)
 	}
-	if len(flag.Args()) == 0 || len(flag.Args()) > 2 ||
+	if len(flag.Args()) == 0 ||
 		!(perm == "none" || perm == "print" || perm == "all") {
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageEncrypt)
 		os.Exit(1)
 	}
 
-	if conf.OwnerPW == "" {
+	inFile, outFile, certFiles := pdfAndKeyFiles(flag.Args(), usageEncrypt)
+
+	if len(certFiles) > 0 {
+		// Public-key security handler.
+		certs, err := sign.LoadCertificates(certFiles...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		conf.Recipients = certs
+	}
+
+	if conf.OwnerPW == "" && len(certFiles) == 0 {
 		fmt.Fprintln(os.Stderr, "missing non-empty owner password!")
 		fmt.Fprintf(os.Stderr, "%s\n\n", usageEncrypt)
 		os.Exit(1)
@@ -966,15 +1029,12 @@ func processEncryptCommand(conf *model.Configuration) {
 		conf.Permissions = model.PermissionsPrint
 	}
 
-	inFile := flag.Arg(0)
 	if conf.CheckFileNameExt {
 		ensurePDFExtension(inFile)
 	}
 
-	outFile := inFile
-	if len(flag.Args()) == 2 {
-		outFile = flag.Arg(1)
-		ensurePDFExtension(outFile)
+	if outFile == "" {
+		outFile = inFile
 	}
 
 	process(cli.EncryptCommand(inFile, outFile, conf))
@@ -1645,17 +1705,17 @@ func processListFontsCommand(conf *model.Configuration) {
 func processInstallFontsCommand(conf *model.Configuration) {
 	fileNames := []string{}
 	if len(flag.Args()) == 0 {
-		fmt.Fprintf(os.Stderr, "%s\n\n", "expecting a list of TrueType filenames (.ttf, .ttc) for installation.")
+		fmt.Fprintf(os.Stderr, "%s\n\n", "expecting a list of font filenames (.ttf, .ttc, .otf, .otc, .pfb, .pfa) for installation.")
 		os.Exit(1)
 	}
 	for _, arg := range flag.Args() {
-		if !types.MemberOf(filepath.Ext(arg), []string{".ttf", ".ttc"}) {
+		if !types.MemberOf(filepath.Ext(arg), []string{".ttf", ".ttc", ".otf", ".otc", ".pfb", ".pfa"}) {
 			continue
 		}
 		fileNames = append(fileNames, arg)
 	}
 	if len(fileNames) == 0 {
-		fmt.Fprintln(os.Stderr, "Please supply a *.ttf or *.tcc fontname!")
+		fmt.Fprintln(os.Stderr, "Please supply a *.ttf, *.ttc, *.otf, *.otc, *.pfb or *.pfa fontname!")
 		os.Exit(1)
 	}
 	process(cli.InstallFontsCommand(fileNames, conf))
@@ -2845,6 +2905,166 @@ func processResetViewerPreferencesCommand(conf *model.Configuration) {
 	process(cli.ResetViewerPreferencesCommand(inFile, "", conf))
 }
 
+func processRedactCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 3 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageRedact)
+		os.Exit(1)
+	}
+
+	processDisplayUnit(conf)
+
+	args := flag.Args()
+
+	var areas []types.Rectangle
+	if strings.HasPrefix(strings.TrimSpace(args[0]), "[") {
+		aa, err := api.RedactAreas(args[0], conf.Unit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		areas, args = aa, args[1:]
+	}
+
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageRedact)
+		os.Exit(1)
+	}
+
+	inFile := args[0]
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(args) == 2 {
+		outFile = args[1]
+		ensurePDFExtension(outFile)
+	}
+
+	selectedPages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.RedactCommand(inFile, outFile, selectedPages, areas, conf))
+}
+
+func processRenderCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 2 {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usageRender)
+		os.Exit(1)
+	}
+
+	if mode != "" {
+		mode = modeCompletion(mode, []string{"png", "jpg"})
+		if mode == "" {
+			fmt.Fprintf(os.Stderr, "%s\n\n", usageRender)
+			os.Exit(1)
+		}
+	}
+
+	if dpi <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid resolution: %d dpi\n", dpi)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+	outDir := flag.Arg(1)
+
+	pages, err := api.ParsePageSelection(selectedPages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "problem with flag selectedPages: %v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.RenderCommand(inFile, outDir, pages, dpi, mode, conf))
+}
+
+func processRepairCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "%s\n\n", usageRepair)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.RepairCommand(inFile, outFile, conf))
+}
+
+func revisionNr(s, usage string) int {
+	nr, err := strconv.Atoi(s)
+	if err != nil || nr < 1 {
+		fmt.Fprintf(os.Stderr, "revision is a numeric value >= 1\n\nusage: %s\n\n", usage)
+		os.Exit(1)
+	}
+	return nr
+}
+
+func processListRevisionsCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageRevisionsList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.ListRevisionsCommand(inFile, conf))
+}
+
+func processExtractRevisionCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 3 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageRevisionsExtract)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	nr := revisionNr(flag.Arg(1), usageRevisionsExtract)
+
+	outFile := flag.Arg(2)
+	ensurePDFExtension(outFile)
+
+	process(cli.ExtractRevisionCommand(inFile, outFile, nr, conf))
+}
+
+func processDiffRevisionsCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageRevisionsDiff)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	nr := 0
+	if len(flag.Args()) == 2 {
+		nr = revisionNr(flag.Arg(1), usageRevisionsDiff)
+	}
+
+	process(cli.DiffRevisionsCommand(inFile, nr, conf))
+}
+
 func processZoomCommand(conf *model.Configuration) {
 	if len(flag.Args()) < 2 || len(flag.Args()) > 3 {
 		fmt.Fprintf(os.Stderr, "%s\n", usageZoom)
@@ -2878,3 +3098,254 @@ func processZoomCommand(conf *model.Configuration) {
 
 	process(cli.ZoomCommand(inFile, outFile, selectedPages, zc, conf))
 }
+
+func processSignCommand(conf *model.Configuration) {
+	args := flag.Args()
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageSign)
+		os.Exit(1)
+	}
+
+	desc := ""
+	if !hasPDFExtension(args[0]) {
+		desc, args = args[0], args[1:]
+	}
+
+	details, err := sign.ParseDetails(desc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	inFile := args[0]
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	var keyFiles []string
+	outFile := ""
+	for _, arg := range args[1:] {
+		if outFile != "" {
+			fmt.Fprintf(os.Stderr, "%s\n", usageSign)
+			os.Exit(1)
+		}
+		if hasPDFExtension(arg) {
+			outFile = arg
+			continue
+		}
+		if !sign.IsKeyFile(arg) {
+			fmt.Fprintf(os.Stderr, "%s: unsupported key or certificate file\n", arg)
+			os.Exit(1)
+		}
+		keyFiles = append(keyFiles, arg)
+	}
+
+	if len(keyFiles) == 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", usageSign)
+		os.Exit(1)
+	}
+
+	cred, err := sign.LoadCredentials(kpw, keyFiles...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	process(cli.SignCommand(inFile, outFile, cred, details, conf))
+}
+
+func processPDFAConvertCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || len(flag.Args()) > 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usagePDFAConvert)
+		os.Exit(1)
+	}
+
+	part := 2
+	if mode != "" {
+		switch modeCompletion(mode, []string{"1b", "2b", "3b"}) {
+		case "1b":
+			part = 1
+		case "2b":
+			part = 2
+		case "3b":
+			part = 3
+		default:
+			fmt.Fprintf(os.Stderr, "usage: %s\n\n", usagePDFAConvert)
+			os.Exit(1)
+		}
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.PDFAConvertCommand(inFile, outFile, part, conf))
+}
+
+func processLayerNamesCommand(conf *model.Configuration, usage string, cmd func(inFile, outFile string, names []string, conf *model.Configuration) *cli.Command) {
+	if len(flag.Args()) < 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usage)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cmd(inFile, "", flag.Args()[1:], conf))
+}
+
+func processListLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.ListLayersCommand(inFile, conf))
+}
+
+func processAddLayersCommand(conf *model.Configuration) {
+	processLayerNamesCommand(conf, usageLayersAdd, cli.AddLayersCommand)
+}
+
+func processRemoveLayersCommand(conf *model.Configuration) {
+	processLayerNamesCommand(conf, usageLayersRemove, cli.RemoveLayersCommand)
+}
+
+func processShowLayersCommand(conf *model.Configuration) {
+	processLayerNamesCommand(conf, usageLayersShow, cli.ShowLayersCommand)
+}
+
+func processHideLayersCommand(conf *model.Configuration) {
+	processLayerNamesCommand(conf, usageLayersHide, cli.HideLayersCommand)
+}
+
+func processFlattenLayersCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageLayersFlatten)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFile := ""
+	if len(flag.Args()) == 2 {
+		outFile = flag.Arg(1)
+		ensurePDFExtension(outFile)
+	}
+
+	process(cli.FlattenLayersCommand(inFile, outFile, conf))
+}
+
+func processListStructureCommand(conf *model.Configuration) {
+	if len(flag.Args()) != 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageStructureList)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	process(cli.ListStructureCommand(inFile, conf))
+}
+
+func processExportStructureCommand(conf *model.Configuration) {
+	if len(flag.Args()) == 0 || len(flag.Args()) > 2 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageStructureExport)
+		os.Exit(1)
+	}
+
+	inFile := flag.Arg(0)
+	if conf.CheckFileNameExt {
+		ensurePDFExtension(inFile)
+	}
+
+	outFileJSON := "out.json"
+	if len(flag.Args()) == 2 {
+		outFileJSON = flag.Arg(1)
+		ensureJSONExtension(outFileJSON)
+	}
+
+	process(cli.ExportStructureCommand(inFile, outFileJSON, conf))
+}
+
+func processListSignaturesCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageSignaturesList)
+		os.Exit(1)
+	}
+
+	inFiles := []string{}
+	for _, arg := range flag.Args() {
+		if strings.Contains(arg, "*") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s", err)
+				os.Exit(1)
+			}
+			inFiles = append(inFiles, matches...)
+			continue
+		}
+		if conf.CheckFileNameExt {
+			ensurePDFExtension(arg)
+		}
+		inFiles = append(inFiles, arg)
+	}
+
+	process(cli.ListSignaturesCommand(inFiles, conf))
+}
+
+func processValidateSignaturesCommand(conf *model.Configuration) {
+	if len(flag.Args()) < 1 || selectedPages != "" {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageSignaturesValidate)
+		os.Exit(1)
+	}
+
+	inFiles, trustStore := []string{}, []string{}
+	for _, arg := range flag.Args() {
+		if strings.Contains(arg, "*") {
+			matches, err := filepath.Glob(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s", err)
+				os.Exit(1)
+			}
+			inFiles = append(inFiles, matches...)
+			continue
+		}
+		if !hasPDFExtension(arg) {
+			// Trust store: certificate file or directory.
+			trustStore = append(trustStore, arg)
+			continue
+		}
+		inFiles = append(inFiles, arg)
+	}
+
+	if len(inFiles) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: %s\n\n", usageSignaturesValidate)
+		os.Exit(1)
+	}
+
+	out, err := cli.Process(cli.ValidateSignaturesCommand(inFiles, trustStore, conf))
+
+	// Show the findings for all signatures, including the invalid ones.
+	printOutput(out)
+	exitOnError(err)
+}
@@ -47,6 +47,7 @@ The commands are:
    import        import/convert images to PDF
    info          print file info
    keywords      list, add, remove keywords
+   layers        list, add, remove, show, hide, flatten optional content groups
    merge         concatenate PDFs
    ndown         cut selected pages into n pages symmetrically
    nup           rearrange pages or images for reduced number of pages
@@ -55,15 +56,23 @@ The commands are:
    pagemode      list, set, reset page mode for opened document
    pages         insert, remove selected pages
    paper         print list of supported paper sizes
+   pdfa          convert to PDF/A
    permissions   list, set user access permissions
    portfolio     list, add, remove, extract portfolio entries with optional description
    poster        cut selected pages into poster by paper size or dimensions
    properties    list, add, remove document properties
+   redact        remove content of selected pages covered by areas or Redact annotations
+   render        render selected pages as png or jpg images
+   repair        rebuild the cross reference table of damaged files
    resize        scale selected pages
+   revisions     list, extract, diff revisions created by incremental updates
    rotate        rotate selected pages
    selectedpages print definition of the -pages flag
+   sign          digitally sign PDF using a PKCS#12 or PEM key
+   signatures    list, validate digital signatures
    split         split up a PDF by span or bookmark
    stamp         add, remove, update Unicode text, image or PDF stamps for selected pages
+   structure     list, export the logical structure of tagged PDFs
    trim          create trimmed version of selected pages
    validate      validate PDF against PDF 32000-1:2008 (PDF 1.7) + basic PDF 2.0 validation
    version       print version
@@ -82,6 +91,7 @@ common flags: -v(erbose)  ... turn on logging
               -vv         ... verbose logging
               -q(uiet)    ... disable output
               -o(ffline)  ... disable http traffic
+              -lin        ... linearize: write file for fast web view
               -c(onf)     ... set or disable config dir: $path|disable
               -opw        ... owner password
               -upw        ... user password
@@ -90,7 +100,7 @@ common flags: -v(erbose)  ... turn on logging
                                                   cm ... centimetres
                                                   mm ... millimetres`
 
-	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed] [-l(inks) -opt(imize)] inFile..." + generalFlags
+	usageValidate = "usage: pdfcpu validate [-m(ode) strict|relaxed|pdfa|pdfua] [-l(inks) -opt(imize)] inFile..." + generalFlags
 
 	usageLongValidate = `Check inFile for specification compliance.
 
@@ -102,6 +112,10 @@ common flags: -v(erbose)  ... turn on logging
 The validation modes are:
     strict ... validates against PDF 32000-1:2008 (PDF 1.7) and rudimentary against PDF 32000:2 (PDF 2.0)
    relaxed ... (default) like strict but doesn't complain about common seen spec violations.
+      pdfa ... like relaxed plus a rule-by-rule check for PDF/A-1b, PDF/A-2b or PDF/A-3b conformance
+               according to the PDF/A identification in the catalog metadata (assumes PDF/A-1b if missing).
+     pdfua ... like relaxed plus an accessibility check for PDF/UA-1 conformance covering tagged content,
+               structure tree references, alternate text for figures, document language and title and heading order.
 
 Validation turns off optimization unless in verbose mode.
 You can enforce optimization using -opt=true.`
@@ -202,11 +216,12 @@ Skip optimization before writing: -opt(imize)=false`
 
         e.g. -3,5,7- or 4-7,!6 or 1-,!5 or odd,n1`
 
-	usageExtract     = "usage: pdfcpu extract -m(ode) i(mage)|f(ont)|c(ontent)|p(age)|m(eta) [-p(ages) selectedPages] inFile outDir" + generalFlags
+	usageExtract     = "usage: pdfcpu extract -m(ode) i(mage)|f(ont)|c(ontent)|t(ext)|p(age)|m(eta) [-p(ages) selectedPages] [-j(son)] inFile outDir" + generalFlags
 	usageLongExtract = `Export inFile's images, fonts, content or pages into outDir.
 
       mode ... extraction mode
      pages ... Please refer to "pdfcpu selectedpages"
+      json ... text mode only: produce JSON including text positions
     inFile ... input PDF file
     outDir ... output directory
 
@@ -215,6 +230,7 @@ Skip optimization before writing: -opt(imize)=false`
   image ... extract images
    font ... extract font files (supported font types: TrueType)
 content ... extract raw page content
+   text ... extract page text in reading order
    page ... extract single page PDFs
    meta ... extract all metadata (page selection does not apply)
    
@@ -306,22 +322,31 @@ content ... extract raw page content
      11: Assemble document (security handlers >= rev.3)
      12: Print (security handlers >= rev.3)`
 
-	usageEncrypt     = "usage: pdfcpu encrypt [-m(ode) rc4|aes] [-key 40|128|256] [-perm none|print|all] [-upw userpw] -opw ownerpw inFile [outFile]" + generalFlags
-	usageLongEncrypt = `Setup password protection based on user and owner password.
+	usageEncrypt = "usage: pdfcpu encrypt [-m(ode) rc4|aes] [-key 40|128|256] [-perm none|print|all] [-upw userpw] -opw ownerpw inFile [outFile]" +
+		"\n       pdfcpu encrypt [-m(ode) rc4|aes] [-key 128|256] [-perm none|print|all] inFile [outFile] certFile..." + generalFlags
+	usageLongEncrypt = `Setup password protection based on user and owner password
+or encrypt for recipients based on their certificates (public-key security handler).
 
       mode ... algorithm (default=aes)
        key ... key length in bits (default=256)
       perm ... user access permissions
     inFile ... input PDF file
    outFile ... output PDF file
-   
-   PDF 2.0 files have to be encrypted using aes/256.`
+  certFile ... PEM or DER encoded recipient certificate
+
+   PDF 2.0 files have to be encrypted using aes/256.
+   Public-key encryption supports aes/128, aes/256 and rc4/128.`
 
-	usageDecrypt     = "usage: pdfcpu decrypt [-upw userpw] [-opw ownerpw] inFile [outFile]" + generalFlags
+	usageDecrypt     = "usage: pdfcpu decrypt [-upw userpw] [-opw ownerpw] [-kpw keypw] inFile [outFile] [keyFile [certFile]]" + generalFlags
 	usageLongDecrypt = `Remove password protection and reset permissions.
 
+       kpw ... password for keyFile
     inFile ... input PDF file
-   outFile ... output PDF file`
+   outFile ... output PDF file
+   keyFile ... PKCS#12 file (.p12, .pfx) or PEM encoded private key of a recipient
+  certFile ... PEM or DER encoded certificate of the recipient
+
+   Files encrypted for recipients (public-key security handler) require the private key of a recipient.`
 
 	usageChangeUserPW     = "usage: pdfcpu changeupw [-opw ownerpw] inFile upwOld upwNew" + generalFlags
 	usageLongChangeUserPW = `Change the user password also known as the open doc password.
@@ -471,6 +496,13 @@ content ... extract raw page content
 
    url:              Add link annotation for stamps only (omit https://)
 
+   layer:            name of the optional content group (layer) holding the watermark
+                     defaults: "Watermark" for stamps, "Background" for watermarks
+
+   print:            print the layer (on/off, true/false, t/f)
+
+   view:             show the layer in viewers (on/off, true/false, t/f)
+
 A color value: 3 color intensities, where 0.0 < i < 1.0, eg 1.0, 
                or the hex RGB value: #RRGGBB, eg #FF0000 = red
 
@@ -479,6 +511,7 @@ All configuration string parameters support completion.
 e.g. "pos:bl, off: 20 5"   "rot:45"                 "op:0.5, scale:0.5 abs, rot:0"
      "d:2"                 "scale:.75 abs, points:48"  "rot:-90, scale:0.75 rel"
      "f:Courier, scale:0.75, str: 0.5 0.0 0.0, rot:20"
+     "layer:Draft, print:off, view:on"
 
 
 `
@@ -962,7 +995,9 @@ Examples: pdfcpu grid out.pdf 1 10 in.pdf
 		"\n       " + usageFontsInstall +
 		"\n       " + usageFontsCheatSheet
 	usageLongFonts = `Print a list of supported fonts (includes the 14 PDF core fonts).
-Install given True Type fonts(.ttf) or True Type collections(.ttc) for usage in stamps/watermarks.
+Install given TrueType/OpenType fonts(.ttf, .otf), collections(.ttc, .otc)
+or Type 1 fonts(.pfb, .pfa) for usage in stamps/watermarks.
+Type 1 fonts need a corresponding .afm file located in the same directory.
 Create single page PDF cheat sheets in current dir.`
 
 	usageKeywordsList   = "pdfcpu keywords list    inFile"
@@ -1712,6 +1747,226 @@ Examples:
    pdfcpu zoom -unit cm -- "vmargin: 1, border:true, bgcolor:lightgray" in.pdf out.pdf ... zoom out to vertical margin of 1 cm
 `
 
+	usageRedact = "usage: pdfcpu redact [-p(ages) selectedPages] [-- areas] inFile [outFile]" + generalFlags
+
+	usageLongRedact = `Permanently remove text and images of selected pages covered by areas or Redact annotations.
+
+  pages ... Please refer to "pdfcpu selectedpages"
+  areas ... list of rectangles in user space "[x1 y1 x2 y2] ..."
+ inFile ... input PDF file
+outFile ... output PDF file
+
+   Glyphs intersecting an area are removed from the content.
+   Image pixels inside an area get blanked, inline images intersecting an area get removed.
+   Areas are filled black, areas of Redact annotations are filled using the annotation's interior color.
+   Applied Redact annotations are removed.
+
+Examples:
+   pdfcpu redact in.pdf out.pdf                                  ... apply all Redact annotations
+   pdfcpu redact -p 1 -- "[100 700 300 720]" in.pdf out.pdf      ... redact a 200x20 points area of page 1
+   pdfcpu redact -u cm -- "[1 1 5 2] [10 1 15 2]" in.pdf out.pdf ... redact 2 areas on all pages
+`
+
+	usageRender = "usage: pdfcpu render [-p(ages) selectedPages] [-m(ode) png|jpg] [-dpi n] inFile outDir" + generalFlags
+
+	usageLongRender = `Render selected pages as images.
+
+ pages ... Please refer to "pdfcpu selectedpages"
+  mode ... image format: png (default), jpg
+   dpi ... resolution in dots per inch (default 150)
+inFile ... input PDF file
+outDir ... output directory
+
+   Paths, shadings, images, text and annotation appearances are drawn honoring the page rotation.
+   Fonts that are not embedded get substituted.
+
+Examples:
+   pdfcpu render in.pdf out                    ... render all pages as png at 150 dpi
+   pdfcpu render -p 1 -dpi 300 in.pdf out      ... render page 1 as png at 300 dpi
+   pdfcpu render -p 1-3 -m jpg in.pdf out      ... render pages 1 to 3 as jpg
+`
+
+	usagePDFAConvert = "pdfcpu pdfa convert [-m(ode) 1b|2b|3b] inFile [outFile]"
+
+	usagePDFA = "usage: " + usagePDFAConvert + generalFlags
+
+	usageLongPDFA = `Manage PDF/A conformance.
+
+   mode ... conformance level: 1b, 2b (default), 3b
+ inFile ... input PDF file
+outFile ... output PDF file
+
+convert ... repair inFile for PDF/A conformance:
+               encryption, JavaScript and other forbidden actions are removed
+               image interpolation is turned off, LZW compressed streams get recompressed
+               an sRGB OutputIntent gets added unless present
+               fonts that are not embedded get embedded using matching installed user fonts
+               XMP metadata is generated from the document information dictionary
+
+   Anything that can't be repaired automatically is listed and no output file gets written.
+   Please refer to "pdfcpu validate -mode pdfa" for checking PDF/A conformance.
+
+Examples:
+   pdfcpu pdfa convert in.pdf out.pdf          ... convert to PDF/A-2b
+   pdfcpu pdfa convert -m 1b in.pdf out.pdf    ... convert to PDF/A-1b
+`
+
+	usageLayersList    = "pdfcpu layers list    inFile"
+	usageLayersAdd     = "pdfcpu layers add     inFile name..."
+	usageLayersRemove  = "pdfcpu layers remove  inFile name..."
+	usageLayersShow    = "pdfcpu layers show    inFile name..."
+	usageLayersHide    = "pdfcpu layers hide    inFile name..."
+	usageLayersFlatten = "pdfcpu layers flatten inFile [outFile]"
+
+	usageLayers = "usage: " + usageLayersList +
+		"\n       " + usageLayersAdd +
+		"\n       " + usageLayersRemove +
+		"\n       " + usageLayersShow +
+		"\n       " + usageLayersHide +
+		"\n       " + usageLayersFlatten + generalFlags
+
+	usageLongLayers = `Manage layers (optional content groups).
+
+ inFile ... input PDF file
+   name ... layer name
+outFile ... output PDF file
+
+   list ... print layers including default visibility and referencing pages
+    add ... add empty visible layers
+ remove ... remove layers along with their content
+   show ... make layers visible by default
+   hide ... make layers hidden by default
+flatten ... merge the content of visible layers into the page content,
+            remove the content of hidden layers and all optional content
+
+   Layer visibility is controlled by the default configuration used when the document is opened.
+
+Examples:
+   pdfcpu layers list in.pdf
+   pdfcpu layers hide in.pdf Watermark
+   pdfcpu layers remove in.pdf "Headers/Footers"
+   pdfcpu layers flatten in.pdf out.pdf
+`
+
+	usageStructureList   = "pdfcpu structure list   inFile"
+	usageStructureExport = "pdfcpu structure export inFile [outFileJSON]"
+
+	usageStructure = "usage: " + usageStructureList +
+		"\n       " + usageStructureExport + generalFlags
+
+	usageLongStructure = `Inspect the logical structure of a tagged PDF.
+
+     inFile ... input PDF file
+outFileJSON ... output JSON file (default: out.json)
+
+  list ... print the structure tree as indented list including
+             the role map, element types and their mapped standard types,
+             IDs, titles, language, alternate text, attributes,
+             page numbers and marked-content IDs (MCIDs)
+export ... write the structure tree as JSON
+
+Examples:
+   pdfcpu structure list in.pdf
+   pdfcpu structure export in.pdf structure.json
+`
+
+	usageRepair     = "usage: pdfcpu repair inFile [outFile]" + generalFlags
+	usageLongRepair = `Rebuild the cross reference table of inFile by scanning for objects and write the result to outFile.
+
+    inFile ... input PDF file
+   outFile ... output PDF file
+
+   Recovers truncated, concatenated or otherwise damaged files.
+   A missing trailer or root object gets replaced by the most recent document catalog found.
+   Wrong stream lengths get fixed. Each repair is reported.
+
+Examples:
+   pdfcpu repair in.pdf           ... repair in.pdf in place
+   pdfcpu repair in.pdf out.pdf   ... write the repaired file to out.pdf
+`
+
+	usageRevisionsList    = "pdfcpu revisions list    inFile"
+	usageRevisionsExtract = "pdfcpu revisions extract inFile revision outFile"
+	usageRevisionsDiff    = "pdfcpu revisions diff    inFile [revision]"
+
+	usageRevisions = "usage: " + usageRevisionsList +
+		"\n       " + usageRevisionsExtract +
+		"\n       " + usageRevisionsDiff + generalFlags
+
+	usageLongRevisions = `Inspect the revision history of a PDF file.
+
+   inFile ... input PDF file
+ revision ... revision number, 1 = original document
+  outFile ... output PDF file
+
+     list ... print each revision with its cross reference section, size,
+              the number of objects added, changed or freed and whether a signature covers it
+  extract ... write the document as it was at revision, eg. the version a signature was applied to
+     diff ... print the objects added, changed or freed by revision or by all incremental updates
+
+   A revision is the original document or an incremental update appended to it.
+   Extracted revisions are byte identical to the corresponding part of inFile.
+
+Examples:
+   pdfcpu revisions list in.pdf
+   pdfcpu revisions extract in.pdf 1 original.pdf
+   pdfcpu revisions diff in.pdf
+   pdfcpu revisions diff in.pdf 3
+`
+
+	usageSign = "usage: pdfcpu sign [-kpw keyPassword] [description] inFile keyFile [certFile...] [outFile]" + generalFlags
+
+	usageLongSign = `Sign inFile (PAdES B-B) and write the signature as incremental update.
+
+        kpw ... password for keyFile
+description ... field, name, reason, location, contact, page
+     inFile ... input PDF file
+    keyFile ... PKCS#12 file (.p12, .pfx) or PEM encoded private key
+   certFile ... PEM or DER encoded signing certificate followed by optional intermediate certificates
+    outFile ... output PDF file
+
+   The first empty signature field gets filled, otherwise a new invisible signature field is added to page 1.
+
+   field    ... fully qualified name of the signature field to be filled or created
+   name     ... name of the signer, defaults to the common name of the certificate
+   reason   ... reason for signing
+   location ... location of signing
+   contact  ... contact info of the signer
+   page     ... page of a new signature field
+
+Examples:
+   pdfcpu sign -kpw secret in.pdf key.p12 out.pdf
+   pdfcpu sign in.pdf key.pem cert.pem out.pdf
+   pdfcpu sign -kpw secret -- "field:Approval, reason:Approved, location:Berlin" in.pdf key.p12 out.pdf
+`
+
+	usageSignaturesList     = "pdfcpu signatures list      inFile..."
+	usageSignaturesValidate = "pdfcpu signatures validate  inFile... [certFile|certDir...]"
+
+	usageSignatures = "usage: " + usageSignaturesList +
+		"\n       " + usageSignaturesValidate + generalFlags
+
+	usageLongSignatures = `Manage digital signatures.
+
+    inFile ... input PDF file
+  certFile ... PEM or DER encoded trusted certificate
+   certDir ... directory containing trusted certificates (.pem, .crt, .cer, .der)
+
+   list ... print signature fields, signers and the file range covered by each signature
+validate ... verify each signature:
+               integrity   ... the signed bytes have not been altered
+               signature   ... the signature matches the signer certificate
+               certificate ... the signer certificate chains up to a trusted certificate
+               modified    ... objects of the signed revision changed by later incremental updates
+
+   Without certFile or certDir the system trust store is used.
+   Supported sub filters: adbe.pkcs7.detached, adbe.pkcs7.sha1, adbe.x509.rsa_sha1, ETSI.CAdES.detached, ETSI.RFC3161
+
+Examples:
+   pdfcpu signatures list contract.pdf
+   pdfcpu signatures validate contract.pdf trusted/
+`
+
 	usageConfigList  = "pdfcpu config list"
 	usageConfigReset = "pdfcpu config reset"
 
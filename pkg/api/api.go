@@ -104,6 +104,9 @@ func ReadContextFile(inFile string) (*model.Context, error) {
 
 // ValidateContext validates ctx.
 func ValidateContext(ctx *model.Context) error {
+	if err := pdfcpu.LoadLazyObjects(ctx); err != nil {
+		return err
+	}
 	if ctx.XRefTable.Version() == model.V20 {
 		logDisclaimerPDF20()
 	}
@@ -152,6 +155,16 @@ func ReadAndValidate(rs io.ReadSeeker, conf *model.Configuration) (ctx *model.Co
 		return nil, err
 	}
 
+	if ctx.Lazy() {
+		if cmdSupportingLazyRead(ctx.Cmd) {
+			return ctx, validate.XRefTableLazily(ctx)
+		}
+		// All other commands need the complete xRefTable.
+		if err := pdfcpu.LoadLazyObjects(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := ValidateContext(ctx); err != nil {
 		return nil, err
 	}
@@ -159,6 +172,13 @@ func ReadAndValidate(rs io.ReadSeeker, conf *model.Configuration) (ctx *model.Co
 	return ctx, nil
 }
 
+func cmdSupportingLazyRead(cmd model.CommandMode) bool {
+	return cmd == model.LISTINFO ||
+		cmd == model.LISTBOOKMARKS ||
+		cmd == model.EXPORTBOOKMARKS ||
+		cmd == model.EXTRACTPAGES
+}
+
 func cmdAssumingOptimization(cmd model.CommandMode) bool {
 	return cmd == model.OPTIMIZE ||
 		cmd == model.FILLFORMFIELDS ||
@@ -181,6 +201,11 @@ func ReadValidateAndOptimize(rs io.ReadSeeker, conf *model.Configuration) (ctx *
 		return nil, err
 	}
 
+	if ctx.Lazy() {
+		// Lazily read contexts are read only and load objects on first access.
+		return ctx, nil
+	}
+
 	// With the exception of commands utilizing structs provided the Optimize step
 	// command optimization of the cross reference table is optional but usually recommended.
 	// For large or complex files it may make sense to skip optimization and set conf.Optimize = false.
@@ -17,10 +17,13 @@
 package api
 
 import (
+	"crypto"
+	"crypto/x509"
 	"io"
 	"os"
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 	"github.com/pkg/errors"
 )
 
@@ -87,6 +90,89 @@ func EncryptFile(inFile, outFile string, conf *model.Configuration) (err error)
 	return Encrypt(f1, f2, conf)
 }
 
+func ensureRecipients(recipients []*x509.Certificate, conf *model.Configuration) (*model.Configuration, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("pdfcpu: missing recipient certificates for encryption")
+	}
+
+	if conf == nil {
+		return model.NewCertificateConfiguration(recipients, 256), nil
+	}
+
+	conf.Recipients = recipients
+	return conf, nil
+}
+
+// EncryptWithCertificates reads a PDF stream from rs and writes the PDF stream encrypted for recipients to w
+// using the public-key security handler.
+func EncryptWithCertificates(rs io.ReadSeeker, w io.Writer, recipients []*x509.Certificate, conf *model.Configuration) error {
+	conf, err := ensureRecipients(recipients, conf)
+	if err != nil {
+		return err
+	}
+
+	return Encrypt(rs, w, conf)
+}
+
+// EncryptWithCertificatesFile encrypts inFile for recipients using the public-key security handler and writes the result to outFile.
+func EncryptWithCertificatesFile(inFile, outFile string, recipients []*x509.Certificate, conf *model.Configuration) error {
+	conf, err := ensureRecipients(recipients, conf)
+	if err != nil {
+		return err
+	}
+
+	return EncryptFile(inFile, outFile, conf)
+}
+
+// SetRecipientKey configures the private key and certificate of cred for opening files encrypted
+// using the public-key security handler.
+func SetRecipientKey(conf *model.Configuration, cred *sign.Credentials) error {
+	if conf == nil {
+		return errors.New("pdfcpu: missing configuration")
+	}
+
+	if cred == nil {
+		return errors.New("pdfcpu: missing credentials")
+	}
+
+	key, ok := cred.Signer.(crypto.Decrypter)
+	if !ok {
+		return errors.New("pdfcpu: private key does not support decryption")
+	}
+
+	conf.RecipientKey = key
+	conf.RecipientCert = cred.Certificate
+
+	return nil
+}
+
+// DecryptWithPrivateKey reads a PDF stream encrypted using the public-key security handler from rs
+// and writes the decrypted PDF stream to w.
+func DecryptWithPrivateKey(rs io.ReadSeeker, w io.Writer, cred *sign.Credentials, conf *model.Configuration) error {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	if err := SetRecipientKey(conf, cred); err != nil {
+		return err
+	}
+
+	return Decrypt(rs, w, conf)
+}
+
+// DecryptWithPrivateKeyFile decrypts inFile encrypted using the public-key security handler and writes the result to outFile.
+func DecryptWithPrivateKeyFile(inFile, outFile string, cred *sign.Credentials, conf *model.Configuration) error {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	if err := SetRecipientKey(conf, cred); err != nil {
+		return err
+	}
+
+	return DecryptFile(inFile, outFile, conf)
+}
+
 // Decrypt reads a PDF stream from rs and writes the encrypted PDF stream to w.
 // A configuration containing at least the current passwords is required.
 func Decrypt(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
@@ -18,6 +18,7 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -371,6 +372,91 @@ func ExtractContentFile(inFile, outDir string, selectedPages []string, conf *mod
 	return ExtractContent(f, outDir, inFile, selectedPages, conf)
 }
 
+// ExtractText returns the text of selected pages of rs as text runs in reading order.
+func ExtractText(rs io.ReadSeeker, selectedPages []string, conf *model.Configuration) ([]pdfcpu.PageText, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ExtractText: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTTEXT
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var pageNrs []int
+	for p, v := range pages {
+		if v {
+			pageNrs = append(pageNrs, p)
+		}
+	}
+	sort.Ints(pageNrs)
+
+	var pp []pdfcpu.PageText
+	for _, p := range pageNrs {
+		pt, err := pdfcpu.ExtractPageText(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		pp = append(pp, *pt)
+	}
+
+	return pp, nil
+}
+
+func writeText(pt pdfcpu.PageText, outDir, fileName string, asJSON bool) error {
+	ext, bb := "txt", []byte(pt.String())
+	if asJSON {
+		var err error
+		if bb, err = json.MarshalIndent(pt, "", "\t"); err != nil {
+			return err
+		}
+		ext = "json"
+	}
+
+	outFile := filepath.Join(outDir, fmt.Sprintf("%s_Text_page_%d.%s", fileName, pt.Page, ext))
+	logWritingTo(outFile)
+
+	return os.WriteFile(outFile, bb, 0644)
+}
+
+// ExtractTextFile writes the text of selected pages of inFile into outDir as plain text or JSON including positions.
+func ExtractTextFile(inFile, outDir string, selectedPages []string, asJSON bool, conf *model.Configuration) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("extracting text from %s into %s/ ...\n", inFile, outDir)
+	}
+
+	pp, err := ExtractText(f, selectedPages, conf)
+	if err != nil {
+		return err
+	}
+
+	fileName := strings.TrimSuffix(filepath.Base(inFile), ".pdf")
+
+	for _, pt := range pp {
+		if err := writeText(pt, outDir, fileName, asJSON); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ExtractMetadata dumps all metadata dict entries for rs into outDir.
 func ExtractMetadata(rs io.ReadSeeker, outDir, fileName string, conf *model.Configuration) error {
 	if rs == nil {
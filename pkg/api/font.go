@@ -60,7 +60,7 @@ func ListFonts() ([]string, error) {
 	return append(sscf, ssuf...), nil
 }
 
-// InstallFonts installs true type fonts for embedding.
+// InstallFonts installs TrueType, OpenType and Type 1 fonts for embedding.
 func InstallFonts(fileNames []string) error {
 	if log.CLIEnabled() {
 		log.CLI.Printf("installing to %s...", font.UserFontDir)
@@ -68,20 +68,26 @@ func InstallFonts(fileNames []string) error {
 
 	for _, fn := range fileNames {
 		switch filepath.Ext(fn) {
-		case ".ttf":
+		case ".ttf", ".otf":
 			//log.CLI.Println(filepath.Base(fn))
 			if err := font.InstallTrueTypeFont(font.UserFontDir, fn); err != nil {
 				if log.CLIEnabled() {
 					log.CLI.Printf("%v", err)
 				}
 			}
-		case ".ttc":
+		case ".ttc", ".otc":
 			//log.CLI.Println(filepath.Base(fn))
 			if err := font.InstallTrueTypeCollection(font.UserFontDir, fn); err != nil {
 				if log.CLIEnabled() {
 					log.CLI.Printf("%v", err)
 				}
 			}
+		case ".pfb", ".pfa":
+			if err := font.InstallType1Font(font.UserFontDir, fn); err != nil {
+				if log.CLIEnabled() {
+					log.CLI.Printf("%v", err)
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,360 @@
+/*
+	Copyright 2021 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+var ErrNoLayers = errors.New("pdfcpu: no layers available")
+
+// Layers returns the optional content groups of rs.
+func Layers(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Layer, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: Layers: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTLAYERS
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.Layers(ctx)
+}
+
+// AddLayers adds empty layers to rs and writes the result to w.
+func AddLayers(rs io.ReadSeeker, w io.Writer, names []string, visible bool, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: AddLayers: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: AddLayers: missing w")
+	}
+
+	if len(names) == 0 {
+		return errors.New("pdfcpu: missing layer names")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.ADDLAYERS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := pdfcpu.AddLayer(ctx, name, visible); err != nil {
+			return err
+		}
+		if log.CLIEnabled() {
+			log.CLI.Printf("added layer: %s\n", name)
+		}
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// AddLayersFile adds empty layers to inFile and writes the result to outFile.
+func AddLayersFile(inFile, outFile string, names []string, visible bool, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return AddLayers(f1, f2, names, visible, conf)
+}
+
+// RemoveLayers deletes layers along with their content from rs and writes the result to w.
+func RemoveLayers(rs io.ReadSeeker, w io.Writer, names []string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RemoveLayers: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: RemoveLayers: missing w")
+	}
+
+	if len(names) == 0 {
+		return errors.New("pdfcpu: missing layer names")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.REMOVELAYERS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.RemoveLayers(ctx, names); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// RemoveLayersFile deletes layers along with their content from inFile and writes the result to outFile.
+func RemoveLayersFile(inFile, outFile string, names []string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return RemoveLayers(f1, f2, names, conf)
+}
+
+// SetLayerVisibility sets the default visibility of layers of rs and writes the result to w.
+func SetLayerVisibility(rs io.ReadSeeker, w io.Writer, names []string, visible bool, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: SetLayerVisibility: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: SetLayerVisibility: missing w")
+	}
+
+	if len(names) == 0 {
+		return errors.New("pdfcpu: missing layer names")
+	}
+
+	cmd := model.HIDELAYERS
+	if visible {
+		cmd = model.SHOWLAYERS
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = cmd
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := pdfcpu.SetLayerVisibility(ctx, names, visible); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// SetLayerVisibilityFile sets the default visibility of layers of inFile and writes the result to outFile.
+func SetLayerVisibilityFile(inFile, outFile string, names []string, visible bool, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return SetLayerVisibility(f1, f2, names, visible, conf)
+}
+
+// FlattenLayers merges visible layers of rs into the page content, drops hidden layers and writes the result to w.
+func FlattenLayers(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: FlattenLayers: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: FlattenLayers: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.FLATTENLAYERS
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ll, err := pdfcpu.Layers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ll) == 0 {
+		return ErrNoLayers
+	}
+
+	if err := pdfcpu.FlattenLayers(ctx); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// FlattenLayersFile merges visible layers of inFile into the page content, drops hidden layers and writes the result to outFile.
+func FlattenLayersFile(inFile, outFile string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return FlattenLayers(f1, f2, conf)
+}
@@ -101,16 +101,104 @@ func prepDestContext(destFile string, rs io.ReadSeeker, conf *model.Configuratio
 	return ctxDest, nil
 }
 
-func appendFile(fName string, ctxDest *model.Context, dividerPage bool) error {
-	f, err := os.Open(fName)
+// sourceFiles keeps at most one of the files streams get copied from open at any time.
+type sourceFiles struct {
+	cur *sourceFile
+}
+
+// sourceFile is an io.ReadSeeker for a file which gets reopened on demand.
+type sourceFile struct {
+	fName string
+	off   int64
+	f     *os.File
+	files *sourceFiles
+}
+
+func (files *sourceFiles) open(fName string) *sourceFile {
+	return &sourceFile{fName: fName, files: files}
+}
+
+func (files *sourceFiles) close() error {
+	if files.cur == nil {
+		return nil
+	}
+	return files.cur.close()
+}
+
+func (sf *sourceFile) ensureOpen() error {
+	if sf.f != nil {
+		return nil
+	}
+
+	if err := sf.files.close(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(sf.fName)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
+	if _, err := f.Seek(sf.off, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+
+	sf.f = f
+	sf.files.cur = sf
+
+	return nil
+}
+
+func (sf *sourceFile) close() error {
+	if sf.f == nil {
+		return nil
+	}
+	err := sf.f.Close()
+	sf.f = nil
+	sf.files.cur = nil
+	return err
+}
+
+// Read implements io.Reader.
+func (sf *sourceFile) Read(p []byte) (int, error) {
+	if err := sf.ensureOpen(); err != nil {
+		return 0, err
+	}
+	n, err := sf.f.Read(p)
+	sf.off += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (sf *sourceFile) Seek(offset int64, whence int) (int64, error) {
+	if err := sf.ensureOpen(); err != nil {
+		return 0, err
+	}
+	off, err := sf.f.Seek(offset, whence)
+	if err == nil {
+		sf.off = off
+	}
+	return off, err
+}
+
+// appendFile appends fName to ctxDest's page tree.
+// For stream writing fName needs to stay accessible until ctxDest has been written.
+func appendFile(fName string, ctxDest *model.Context, dividerPage bool, files *sourceFiles) error {
 	if log.CLIEnabled() {
 		log.CLI.Println(fName)
 	}
+
+	if files != nil {
+		return appendTo(files.open(fName), filepath.Base(fName), ctxDest, dividerPage)
+	}
+
+	f, err := os.Open(fName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
 	return appendTo(f, filepath.Base(fName), ctxDest, dividerPage)
 }
 
@@ -156,8 +244,15 @@ func Merge(destFile string, inFiles []string, w io.Writer, conf *model.Configura
 		return err
 	}
 
+	var files *sourceFiles
+	if conf.StreamWrite {
+		// Unmodified streams get copied from inFiles when writing ctxDest.
+		files = &sourceFiles{}
+		defer files.close()
+	}
+
 	for _, fName := range inFiles {
-		if err := appendFile(fName, ctxDest, dividerPage); err != nil {
+		if err := appendFile(fName, ctxDest, dividerPage, files); err != nil {
 			return err
 		}
 	}
@@ -196,6 +196,18 @@ func PageCount(rs io.ReadSeeker, conf *model.Configuration) (int, error) {
 		return 0, errors.New("pdfcpu: PageCount: missing rs")
 	}
 
+	if conf != nil && conf.LazyRead {
+		// Take the page count right from the page tree root.
+		ctx, err := ReadContext(rs, conf)
+		if err != nil {
+			return 0, err
+		}
+		if err := ctx.EnsurePageCount(); err != nil {
+			return 0, err
+		}
+		return ctx.PageCount, nil
+	}
+
 	ctx, err := ReadAndValidate(rs, conf)
 	if err != nil {
 		return 0, err
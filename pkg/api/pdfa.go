@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
+	"github.com/pkg/errors"
+)
+
+func pdfaConversionError(r *validate.PDFAReport, notes []string) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "pdfcpu: unable to convert to %s:", r.Level())
+	for _, s := range notes {
+		fmt.Fprintf(&sb, "\n%s", s)
+	}
+	for _, rule := range r.Rules {
+		for _, v := range rule.Violations {
+			fmt.Fprintf(&sb, "\n%s %s", rule.ID, v)
+		}
+		if rule.Skipped > 0 {
+			fmt.Fprintf(&sb, "\n%s ... and %d more", rule.ID, rule.Skipped)
+		}
+	}
+	return errors.New(sb.String())
+}
+
+// ConvertToPDFA converts rs into a PDF/A-1b, PDF/A-2b or PDF/A-3b file according to part and writes the result to w.
+// Whatever can not be repaired automatically is listed by the returned error, in which case nothing is written.
+func ConvertToPDFA(rs io.ReadSeeker, w io.Writer, part int, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ConvertToPDFA: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.PDFACONVERT
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	notes, err := pdfcpu.ConvertToPDFA(ctx, part)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := Write(ctx, &buf, conf); err != nil {
+		return err
+	}
+
+	// Check the result as written.
+	if ctx, err = ReadAndValidate(bytes.NewReader(buf.Bytes()), conf); err != nil {
+		return err
+	}
+
+	r, err := validate.PDFA(ctx, part)
+	if err != nil {
+		return err
+	}
+
+	if !r.Conformant() {
+		return pdfaConversionError(r, notes)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ConvertToPDFAFile converts inFile into a PDF/A-1b, PDF/A-2b or PDF/A-3b file according to part and writes the result to outFile.
+func ConvertToPDFAFile(inFile, outFile string, part int, conf *model.Configuration) (err error) {
+	if log.CLIEnabled() {
+		log.CLI.Printf("converting %s to PDF/A-%db\n", inFile, part)
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	var (
+		f1, f2 *os.File
+	)
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return ConvertToPDFA(f1, f2, part, conf)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// RedactAreas parses a list of rectangles in user space like "[x1 y1 x2 y2] [x1 y1 x2 y2]" using display unit u.
+func RedactAreas(s string, u types.DisplayUnit) ([]types.Rectangle, error) {
+	return pdfcpu.ParseRedactAreas(s, u)
+}
+
+// Redact permanently removes text and image content of selected pages of rs covered by areas or Redact annotations and writes the result to w.
+// Areas are filled black, areas of Redact annotations are filled using the annotation's interior color.
+// Finally all applied Redact annotations are removed.
+func Redact(rs io.ReadSeeker, w io.Writer, selectedPages []string, areas []types.Rectangle, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: Redact: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REDACT
+
+	ctx, err := ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	if err = pdfcpu.Redact(ctx, pages, areas); err != nil {
+		return err
+	}
+
+	return Write(ctx, w, conf)
+}
+
+// RedactFile permanently removes text and image content of selected pages of inFile covered by areas or Redact annotations and writes the result to outFile.
+func RedactFile(inFile, outFile string, selectedPages []string, areas []types.Rectangle, conf *model.Configuration) (err error) {
+	if log.CLIEnabled() {
+		log.CLI.Printf("redacting %s\n", inFile)
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	var (
+		f1, f2 *os.File
+	)
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REDACT
+
+	return Redact(f1, f2, selectedPages, areas, conf)
+}
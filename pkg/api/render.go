@@ -0,0 +1,144 @@
+/*
+	Copyright 2026 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/render"
+	"github.com/pkg/errors"
+)
+
+// DefaultRenderDPI is the resolution used for rendering pages unless specified otherwise.
+const DefaultRenderDPI = 150
+
+func renderContext(rs io.ReadSeeker, conf *model.Configuration) (*model.Context, error) {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.RENDER
+
+	return ReadAndValidate(rs, conf)
+}
+
+// RenderPage rasterizes page pageNr of rs at dpi.
+func RenderPage(rs io.ReadSeeker, pageNr int, dpi float64, conf *model.Configuration) (image.Image, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: RenderPage: missing rs")
+	}
+
+	ctx, err := renderContext(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageNr < 1 || pageNr > ctx.PageCount {
+		return nil, errors.Errorf("pdfcpu: RenderPage: invalid page number: %d", pageNr)
+	}
+
+	return render.Page(ctx, pageNr, dpi)
+}
+
+func writeRenderedPage(w io.Writer, img image.Image, format string) error {
+	if format == "jpg" {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+	}
+	return png.Encode(w, img)
+}
+
+// RenderPages rasterizes selected pages of rs at dpi and writes them as png or jpg images into outDir.
+func RenderPages(rs io.ReadSeeker, outDir, fileName string, selectedPages []string, dpi float64, format string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: RenderPages: missing rs")
+	}
+
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "jpg" {
+		return errors.Errorf("pdfcpu: RenderPages: unsupported image format: %s", format)
+	}
+
+	ctx, err := renderContext(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	pages, err := PagesForPageSelection(ctx.PageCount, selectedPages, true, true)
+	if err != nil {
+		return err
+	}
+
+	var pageNrs []int
+	for p, v := range pages {
+		if v {
+			pageNrs = append(pageNrs, p)
+		}
+	}
+	sort.Ints(pageNrs)
+
+	fileName = strings.TrimSuffix(filepath.Base(fileName), ".pdf")
+
+	for _, p := range pageNrs {
+		img, err := render.Page(ctx, p, dpi)
+		if err != nil {
+			return err
+		}
+
+		outFile := filepath.Join(outDir, fmt.Sprintf("%s_page_%d.%s", fileName, p, format))
+		logWritingTo(outFile)
+
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		if err := writeRenderedPage(f, img, format); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderPagesFile rasterizes selected pages of inFile at dpi and writes them as png or jpg images into outDir.
+func RenderPagesFile(inFile, outDir string, selectedPages []string, dpi float64, format string, conf *model.Configuration) error {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("rendering %s into %s/ ...\n", inFile, outDir)
+	}
+
+	return RenderPages(f, outDir, inFile, selectedPages, dpi, format, conf)
+}
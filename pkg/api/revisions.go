@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// Revisions returns the revisions of rs, the original document followed by its incremental updates,
+// along with the objects each revision added, changed or freed.
+func Revisions(rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Revision, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: Revisions: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTREVISIONS
+
+	return pdfcpu.Revisions(rs, conf)
+}
+
+// RevisionsFile returns the revisions of inFile.
+func RevisionsFile(inFile string, conf *model.Configuration) ([]pdfcpu.Revision, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Revisions(f, conf)
+}
+
+// ExtractRevision validates the document of rs as it was at revision nr and writes it to w.
+func ExtractRevision(rs io.ReadSeeker, w io.Writer, nr int, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExtractRevision: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTREVISION
+
+	r, err := pdfcpu.RevisionReader(rs, nr)
+	if err != nil {
+		return err
+	}
+
+	// The extracted revision is a document on its own.
+	if _, err := ReadAndValidate(r, conf); err != nil {
+		return errors.Wrapf(err, "pdfcpu: revision %d", nr)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// ExtractRevisionFile writes the document of inFile as it was at revision nr to outFile.
+func ExtractRevisionFile(inFile, outFile string, nr int, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+	defer f1.Close()
+
+	if f2, err = os.Create(outFile); err != nil {
+		return err
+	}
+	logWritingTo(outFile)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			os.Remove(outFile)
+			return
+		}
+		err = f2.Close()
+	}()
+
+	return ExtractRevision(f1, f2, nr, conf)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pkg/errors"
+)
+
+// Sign signs rs using cred and writes the result as incremental update to w.
+// The signature goes into the signature field of details or into a new invisible signature field.
+func Sign(rs io.ReadSeeker, w io.Writer, cred *sign.Credentials, details *sign.Details, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: Sign: missing rs")
+	}
+
+	if cred == nil {
+		return errors.New("pdfcpu: Sign: missing credentials")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SIGN
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Encrypt != nil {
+		return errors.New("pdfcpu: Sign: signing encrypted files not supported (Hint: Use pdfcpu decrypt then try again)")
+	}
+
+	if *ctx.HeaderVersion < model.V14 {
+		return errors.New("pdfcpu: Incremental writing unsupported for PDF version < V1.4 (Hint: Use pdfcpu optimize then try again)")
+	}
+
+	if err := sign.Prepare(ctx, cred, details); err != nil {
+		return err
+	}
+
+	// The signature covers the original file followed by the increment.
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rs); err != nil {
+		return err
+	}
+
+	if bb := buf.Bytes(); len(bb) > 0 && bb[len(bb)-1] != '\n' && bb[len(bb)-1] != '\r' {
+		buf.WriteString(ctx.Write.Eol)
+	}
+
+	offset := int64(buf.Len())
+	ctx.Write.Offset = offset
+
+	if err := WriteIncrement(ctx, &buf); err != nil {
+		return err
+	}
+
+	bb := buf.Bytes()
+	if err := sign.Embed(bb, offset, cred); err != nil {
+		return err
+	}
+
+	_, err = w.Write(bb)
+	return err
+}
+
+// SignFile signs inFile using cred and writes the result to outFile.
+func SignFile(inFile, outFile string, cred *sign.Credentials, details *sign.Details, conf *model.Configuration) (err error) {
+	if log.CLIEnabled() {
+		log.CLI.Printf("signing %s\n", inFile)
+	}
+
+	tmpFile := inFile + ".tmp"
+	if outFile != "" && inFile != outFile {
+		tmpFile = outFile
+		logWritingTo(outFile)
+	} else {
+		logWritingTo(inFile)
+	}
+
+	var (
+		f1, f2 *os.File
+	)
+
+	if f1, err = os.Open(inFile); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(tmpFile); err != nil {
+		f1.Close()
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			os.Remove(tmpFile)
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+		if outFile == "" || inFile == outFile {
+			err = os.Rename(tmpFile, inFile)
+		}
+	}()
+
+	return Sign(f1, f2, cred, details, conf)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pkg/errors"
+)
+
+func readSignatures(rs io.ReadSeeker, conf *model.Configuration) (*model.Context, []*sign.Signature, []byte, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, nil, err
+	}
+
+	bb, err := io.ReadAll(rs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ctx, err := ReadAndValidate(bytes.NewReader(bb), conf)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sigs, err := sign.Signatures(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return ctx, sigs, bb, nil
+}
+
+// Signatures returns the signature fields of rs.
+func Signatures(rs io.ReadSeeker, conf *model.Configuration) ([]*sign.Signature, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: Signatures: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTSIGNATURES
+
+	_, sigs, bb, err := readSignatures(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sig := range sigs {
+		if sig.Signed {
+			sig.CheckByteRange(bb)
+		}
+	}
+
+	return sigs, nil
+}
+
+// offset returns the file offset of the definition of object objNr.
+func offset(ctx *model.Context, objNr int) (int64, bool) {
+	entry, found := ctx.FindTableEntryLight(objNr)
+	if !found || entry.Free {
+		return 0, false
+	}
+	if entry.Compressed && entry.ObjectStream != nil {
+		return offset(ctx, *entry.ObjectStream)
+	}
+	if entry.Offset == nil {
+		return 0, false
+	}
+	return *entry.Offset, true
+}
+
+// modifiedObjects returns all objects of the revision signed by sig which have been redefined or deleted later on.
+func modifiedObjects(ctx *model.Context, sig *sign.Signature, bb []byte) ([]int, error) {
+	end := sig.SignedRevisionEnd()
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+
+	ctxRev, err := ReadContext(bytes.NewReader(bb[:end]), conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: signed revision")
+	}
+
+	var objNrs []int
+
+	for objNr, entry := range ctxRev.Table {
+		if objNr == 0 || entry.Free {
+			continue
+		}
+		off, found := offset(ctx, objNr)
+		if !found || off >= end {
+			objNrs = append(objNrs, objNr)
+		}
+	}
+
+	sort.Ints(objNrs)
+
+	return objNrs, nil
+}
+
+// ValidateSignatures verifies all signatures of rs against the certificates in roots.
+// If roots is nil, the system trust store is used.
+func ValidateSignatures(rs io.ReadSeeker, roots *x509.CertPool, conf *model.Configuration) ([]*sign.Signature, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ValidateSignatures: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VALIDATESIGNATURES
+
+	ctx, sigs, bb, err := readSignatures(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sig := range sigs {
+		if !sig.Signed {
+			continue
+		}
+		sig.Verify(bb, roots)
+		if end := sig.SignedRevisionEnd(); end <= 0 || end > int64(len(bb)) || sig.WholeFile {
+			continue
+		}
+		if sig.ModifiedObjects, err = modifiedObjects(ctx, sig, bb); err != nil {
+			return nil, err
+		}
+	}
+
+	return sigs, nil
+}
+
+// ValidateSignaturesFile verifies all signatures of inFile against the certificates in roots.
+func ValidateSignaturesFile(inFile string, roots *x509.CertPool, conf *model.Configuration) ([]*sign.Signature, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ValidateSignatures(f, roots, conf)
+}
@@ -0,0 +1,113 @@
+/*
+	Copyright 2021 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+var ErrNoStructTree = errors.New("pdfcpu: no structure tree available")
+
+// StructureTree returns rs's logical structure or nil for untagged files.
+func StructureTree(rs io.ReadSeeker, conf *model.Configuration) (*pdfcpu.StructTree, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: StructureTree: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTSTRUCTURE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.StructureTree(ctx)
+}
+
+// ExportStructureJSON extracts the logical structure of rs (originating from source) and writes the result to w.
+func ExportStructureJSON(rs io.ReadSeeker, w io.Writer, source string, conf *model.Configuration) error {
+	if rs == nil {
+		return errors.New("pdfcpu: ExportStructureJSON: missing rs")
+	}
+
+	if w == nil {
+		return errors.New("pdfcpu: ExportStructureJSON: missing w")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.EXPORTSTRUCTURE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	ok, err := pdfcpu.ExportStructureJSON(ctx, source, w)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNoStructTree
+	}
+
+	return nil
+}
+
+// ExportStructureFile extracts the logical structure of inFilePDF and writes the result to outFileJSON.
+func ExportStructureFile(inFilePDF, outFileJSON string, conf *model.Configuration) (err error) {
+	var f1, f2 *os.File
+
+	if f1, err = os.Open(inFilePDF); err != nil {
+		return err
+	}
+
+	if f2, err = os.Create(outFileJSON); err != nil {
+		f1.Close()
+		return err
+	}
+	logWritingTo(outFileJSON)
+
+	defer func() {
+		if err != nil {
+			f2.Close()
+			f1.Close()
+			return
+		}
+		if err = f2.Close(); err != nil {
+			return
+		}
+		if err = f1.Close(); err != nil {
+			return
+		}
+	}()
+
+	return ExportStructureJSON(f1, f2, inFilePDF, conf)
+}
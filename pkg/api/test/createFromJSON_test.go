@@ -354,3 +354,39 @@ func TestReadFormAndUpdateFormViaJson(t *testing.T) {
 	outFile = filepath.Join(outDir, "readFormAndUpdateFormCJK.pdf")
 	createPDF(t, "pass1", inFile, inFileJSON, outFile, conf)
 }
+
+func TestCreateTaggedViaJson(t *testing.T) {
+	msg := "TestCreateTaggedViaJson"
+
+	inFileJSON := filepath.Join(inDir, "json", "create", "tagged.json")
+	outFile := filepath.Join(outDir, "tagged.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	createPDF(t, msg, "", inFileJSON, outFile, conf)
+
+	r := validatePDFUAFile(t, msg, outFile)
+	for _, s := range []string{
+		"marked as tagged PDF with structure tree",
+		"content tagged or marked as artifact",
+		"marked content and structure tree match",
+		"document language specified",
+		"figures with alternate text",
+		"headings properly nested",
+	} {
+		if rule := pdfuaRule(r, s); rule == nil || !rule.Passed() {
+			t.Fatalf("%s: rule %q should pass:\n%s", msg, s, r)
+		}
+	}
+}
+
+func TestCreateFallbackFontsViaJson(t *testing.T) {
+	msg := "TestCreateFallbackFontsViaJson"
+
+	// Text boxes, table cells and the footer fall back to CFFTest for 中.
+	inFileJSON := filepath.Join(inDir, "json", "create", "fallbackFonts.json")
+	outFile := filepath.Join(outDir, "fallbackFonts.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	createPDF(t, msg, "", inFileJSON, outFile, conf)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package test
 
 import (
+	"crypto/x509"
 	"os"
 	"path/filepath"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 )
 
 func listPermissions(t *testing.T, fileName string) ([]string, error) {
@@ -230,3 +232,61 @@ func TestSetPermissions(t *testing.T) {
 		t.Fatalf("%s: got: %d want: %d", msg, uint16(*p), uint16(permNew))
 	}
 }
+
+func testPublicKeyEncryption(t *testing.T, aes bool, keyLength int) {
+	t.Helper()
+
+	msg := "testPublicKeyEncryption"
+
+	keyDir := filepath.Join(inDir, "sign")
+
+	cred1, err := sign.LoadCredentials("", filepath.Join(keyDir, "key.pem"), filepath.Join(keyDir, "cert.pem"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	cred2, err := sign.LoadCredentials("", filepath.Join(keyDir, "recipient.pem"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	inFile := filepath.Join(inDir, "5116.DCT_Filter.pdf")
+	outFile := filepath.Join(outDir, "pubsec.pdf")
+
+	conf := model.NewCertificateConfiguration(nil, keyLength)
+	conf.EncryptUsingAES = aes
+	if err := api.EncryptWithCertificatesFile(inFile, outFile, []*x509.Certificate{cred1.Certificate, cred2.Certificate}, conf); err != nil {
+		t.Fatalf("%s: encrypt %d: %v\n", msg, keyLength, err)
+	}
+
+	// Reading without private key should fail.
+	if err := api.ValidateFile(outFile, nil); err == nil {
+		t.Fatalf("%s: %d: read without private key\n", msg, keyLength)
+	}
+
+	// Each recipient is able to read.
+	for _, cred := range []*sign.Credentials{cred1, cred2} {
+		conf := model.NewDefaultConfiguration()
+		if err := api.SetRecipientKey(conf, cred); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if err := api.ValidateFile(outFile, conf); err != nil {
+			t.Fatalf("%s: %d: validate as %s: %v\n", msg, keyLength, cred.Certificate.Subject, err)
+		}
+	}
+
+	// Decrypt.
+	outFile2 := filepath.Join(outDir, "pubsecDecrypted.pdf")
+	if err := api.DecryptWithPrivateKeyFile(outFile, outFile2, cred2, nil); err != nil {
+		t.Fatalf("%s: %d: decrypt: %v\n", msg, keyLength, err)
+	}
+	if err := api.ValidateFile(outFile2, nil); err != nil {
+		t.Fatalf("%s: %d: validate decrypted: %v\n", msg, keyLength, err)
+	}
+}
+
+func TestPublicKeyEncryption(t *testing.T) {
+	testPublicKeyEncryption(t, true, 128)
+	testPublicKeyEncryption(t, true, 256)
+	testPublicKeyEncryption(t, false, 128)
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package test
 
 import (
+	"encoding/json"
 	"fmt"
+	"image/color"
+	"image/png"
 	"io"
 	"os"
 	"path/filepath"
@@ -25,7 +28,9 @@ import (
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
@@ -168,6 +173,120 @@ func TestExtractImagesLowLevel(t *testing.T) {
 	}
 }
 
+func TestExtractImagesJPX(t *testing.T) {
+	msg := "TestExtractImagesJPX"
+	inFile := filepath.Join(inDir, "testImage.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s readContext: %v\n", msg, err)
+	}
+
+	if err := api.OptimizeContext(ctx); err != nil {
+		t.Fatalf("%s optimizeContext: %v\n", msg, err)
+	}
+
+	stubs, err := pdfcpu.ExtractPageImages(ctx, 1, true)
+	if err != nil {
+		t.Fatalf("%s extractPageImages: %v\n", msg, err)
+	}
+
+	ii, err := pdfcpu.ExtractPageImages(ctx, 1, false)
+	if err != nil {
+		t.Fatalf("%s extractPageImages: %v\n", msg, err)
+	}
+
+	var found bool
+	for objNr, stub := range stubs {
+		if stub.Filter != filter.JPX {
+			continue
+		}
+		found = true
+		img := ii[objNr]
+		if img.FileType != "png" {
+			t.Fatalf("%s: want png, got %s\n", msg, img.FileType)
+		}
+		im, err := png.Decode(img)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if b := im.Bounds(); b.Dx() != stub.Width || b.Dy() != stub.Height {
+			t.Fatalf("%s: want %dx%d, got %dx%d\n", msg, stub.Width, stub.Height, b.Dx(), b.Dy())
+		}
+	}
+
+	if !found {
+		t.Fatalf("%s: missing JPXDecode image\n", msg)
+	}
+}
+
+func TestExtractImagesJBIG2(t *testing.T) {
+	msg := "TestExtractImagesJBIG2"
+	inFile := filepath.Join(inDir, "jbig2.pdf")
+
+	// Im0 is a generic region, Im1 a text region using a symbol dictionary provided by JBIG2Globals.
+	glyph := func(id, x, y int) bool {
+		if id == 0 {
+			return x < 4 && (x == 0 || y == 4)
+		}
+		return x < 6 && (x == y || x == 5-y)
+	}
+	want := map[string]struct {
+		w, h  int
+		black func(x, y int) bool
+	}{
+		"Im0": {37, 23, func(x, y int) bool {
+			return (x-18)*(x-18)+(y-11)*(y-11) < 80 || x == y || (x*7+y*3)%11 == 0
+		}},
+		"Im1": {20, 10, func(x, y int) bool {
+			return y >= 2 && y < 7 && (x >= 1 && glyph(0, x-1, y-2) || x >= 6 && glyph(1, x-6, y-2)) ||
+				y >= 3 && y < 8 && x >= 14 && glyph(0, x-14, y-3)
+		}},
+	}
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	got := map[string]bool{}
+	digest := func(img model.Image, singleImgPerPage bool, maxPageDigits int) error {
+		w, ok := want[img.Name]
+		if !ok {
+			return nil
+		}
+		got[img.Name] = true
+		im, err := png.Decode(img)
+		if err != nil {
+			return err
+		}
+		b := im.Bounds()
+		if b.Dx() != w.w || b.Dy() != w.h {
+			return fmt.Errorf("%s: want %dx%d, got %dx%d", img.Name, w.w, w.h, b.Dx(), b.Dy())
+		}
+		for y := 0; y < b.Dy(); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				gray := color.GrayModel.Convert(im.At(b.Min.X+x, b.Min.Y+y)).(color.Gray)
+				if (gray.Y < 128) != w.black(x, y) {
+					return fmt.Errorf("%s: pixel mismatch at (%d,%d)", img.Name, x, y)
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := api.ExtractImages(f, nil, digest, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	for name := range want {
+		if !got[name] {
+			t.Fatalf("%s: missing image %s\n", msg, name)
+		}
+	}
+}
+
 func TestExtractFonts(t *testing.T) {
 	msg := "TestExtractFonts"
 	// Extract fonts for all pages into outDir.
@@ -318,3 +437,69 @@ func TestExtractMetadataLowLevel(t *testing.T) {
 			md.ObjNr, md.ParentObjNr, md.ParentType, string(bb))
 	}
 }
+
+func TestExtractText(t *testing.T) {
+	msg := "TestExtractText"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	pp, err := api.ExtractText(f, []string{"2"}, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	if len(pp) != 1 || pp[0].Page != 2 {
+		t.Fatalf("%s: want text for page 2, got %d pages\n", msg, len(pp))
+	}
+
+	lines := strings.Split(pp[0].String(), "\n")
+	if len(lines) < 3 || lines[0] != "94" || lines[1] != "SOLITUDE" {
+		t.Fatalf("%s: unexpected text:\n%s\n", msg, pp[0])
+	}
+	if !strings.HasPrefix(lines[2], "This is a delicious evening") {
+		t.Fatalf("%s: unexpected line 3: %s\n", msg, lines[2])
+	}
+
+	// Runs are in reading order, top to bottom.
+	for i := 1; i < len(pp[0].Runs); i++ {
+		r0, r1 := pp[0].Runs[i-1], pp[0].Runs[i]
+		if r1.Line > r0.Line && r1.BBox.UR.Y > r0.BBox.UR.Y {
+			t.Fatalf("%s: run %d %q above run %d %q\n", msg, i, r1.Text, i-1, r0.Text)
+		}
+		if r1.BBox.Width() <= 0 || r1.BBox.Height() <= 0 {
+			t.Fatalf("%s: run %d: empty bounding box\n", msg, i)
+		}
+	}
+}
+
+func TestExtractTextFile(t *testing.T) {
+	msg := "TestExtractTextFile"
+	inFile := filepath.Join(inDir, "5116.DCT_Filter.pdf")
+
+	// Extract text of all pages into outDir.
+	if err := api.ExtractTextFile(inFile, outDir, nil, false, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	// Extract text including positions of page 1 into outDir.
+	if err := api.ExtractTextFile(inFile, outDir, []string{"1"}, true, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(outDir, "5116.DCT_Filter_Text_page_1.json"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	var pt pdfcpu.PageText
+	if err := json.Unmarshal(bb, &pt); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !strings.Contains(pt.String(), "Supporting the DCT Filters") {
+		t.Fatalf("%s: unexpected text:\n%s\n", msg, pt)
+	}
+}
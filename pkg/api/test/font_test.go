@@ -17,6 +17,7 @@ limitations under the License.
 package test
 
 import (
+	"bytes"
 	"fmt"
 
 	"path/filepath"
@@ -128,3 +129,134 @@ func TestUserFontDemoPDF(t *testing.T) {
 		}
 	}
 }
+
+func TestCFFUserFonts(t *testing.T) {
+	msg := "TestCFFUserFonts"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+
+	// CFFTest.otf is an OpenType font with CFF outlines, PdfcpuTestType1.pfb a Type 1 font.
+	for _, tt := range []struct {
+		fontName, text string
+	}{
+		{"CFFTest", "01Q中"},
+		{"PdfcpuTestType1", "HOAo Á€"},
+	} {
+		outFile := filepath.Join(outDir, tt.fontName+".pdf")
+		desc := fmt.Sprintf("font:%s, scale:1.0 rel, rot:0, fillc:#000000", tt.fontName)
+		if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, tt.text, desc, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, outFile, err)
+		}
+
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, outFile, err)
+		}
+
+		ctx, err := api.ReadContextFile(outFile)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, outFile, err)
+		}
+
+		// The font program is embedded as bare CFF.
+		found := false
+		for _, entry := range ctx.Table {
+			if sd, ok := entry.Object.(types.StreamDict); ok {
+				if st := sd.Subtype(); st != nil && *st == "CIDFontType0C" {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			t.Fatalf("%s %s: missing CIDFontType0C font program\n", msg, outFile)
+		}
+	}
+}
+
+func TestShapedUserFontText(t *testing.T) {
+	msg := "TestShapedUserFontText"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "shapedText.pdf")
+
+	// Roboto-Regular provides kerning via GPOS and ligatures via GSUB.
+	desc := "font:Roboto-Regular, scale:0.8 rel, rot:0, fillc:#000000"
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "AVATAR office", desc, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	// Kerned text gets rendered using TJ.
+	found := false
+	for _, entry := range ctx.Table {
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+		if err := sd.Decode(); err != nil {
+			continue
+		}
+		if bytes.Contains(sd.Content, []byte("] TJ")) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("%s %s: missing positioned text\n", msg, outFile)
+	}
+}
+
+func TestFallbackFonts(t *testing.T) {
+	msg := "TestFallbackFonts"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "fallbackFonts.pdf")
+
+	// Roboto-Regular lacks 中 which is provided by the fallback font CFFTest.
+	conf := model.NewDefaultConfiguration()
+	conf.FallbackFonts = []string{"CFFTest"}
+
+	desc := "font:Roboto-Regular, scale:0.8 rel, rot:0, fillc:#000000"
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "Roboto 中", desc, conf); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	// Both fonts get embedded and the text switches between them.
+	var cff, tf bool
+	for _, entry := range ctx.Table {
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+		if st := sd.Subtype(); st != nil && *st == "CIDFontType0C" {
+			cff = true
+			continue
+		}
+		if err := sd.Decode(); err != nil {
+			continue
+		}
+		if bytes.Contains(sd.Content, []byte("/F2 ")) && bytes.Contains(sd.Content, []byte("/F1 ")) {
+			tf = true
+		}
+	}
+	if !cff {
+		t.Fatalf("%s %s: missing fallback font program\n", msg, outFile)
+	}
+	if !tf {
+		t.Fatalf("%s %s: missing font switch\n", msg, outFile)
+	}
+}
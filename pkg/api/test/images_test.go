@@ -17,10 +17,16 @@ limitations under the License.
 package test
 
 import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
 func testUpdateImages(t *testing.T, msg string, inFile, imgFile, outFile string, objNr, pageNr int, id string) {
@@ -129,3 +135,63 @@ func TestUpdateImages(t *testing.T) {
 			tt.id)
 	}
 }
+
+func TestUpdateImagesCCITT(t *testing.T) {
+	msg := "TestUpdateImagesCCITT"
+	inFile := filepath.Join(samplesDir, "images", "test.pdf")
+	imgFile := filepath.Join(outDir, "bilevel.png")
+	outFile := filepath.Join(outDir, "imageUpdatedCCITT.pdf")
+
+	// Create a black and white image matching the dimensions of Im1.
+	img := image.NewGray(image.Rect(0, 0, 246, 206))
+	for y := 0; y < 206; y++ {
+		for x := 0; x < 246; x++ {
+			c := color.White
+			if (x/20+y/20)%2 == 0 {
+				c = color.Black
+			}
+			img.Set(x, y, c)
+		}
+	}
+	f, err := os.Create(imgFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	conf := model.NewDefaultConfiguration()
+	conf.OptimizeImages = true
+
+	if err := api.UpdateImagesFile(inFile, imgFile, outFile, 0, 1, "Im1", conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	f, err = os.Open(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	pageImages, err := api.Images(f, []string{"1"}, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for _, img := range pageImages[0] {
+		if img.Name != "Im1" {
+			continue
+		}
+		if img.Filter != filter.CCITTFax || img.Bpc != 1 {
+			t.Fatalf("%s: want 1 bit %s, got %d bit %s\n", msg, filter.CCITTFax, img.Bpc, img.Filter)
+		}
+		return
+	}
+	t.Fatalf("%s: missing Im1\n", msg)
+}
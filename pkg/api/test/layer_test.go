@@ -0,0 +1,347 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// wrapLayer assigns all of content bb to layer "Text".
+func wrapLayer(bb []byte) []byte {
+	return append(append([]byte("/OC /oc1 BDC\n"), bb...), []byte("\nEMC\n")...)
+}
+
+// createLayeredFile writes a copy of testWithText.pdf with the content of page 1 rewritten by layered
+// using the property name oc1 for layer "Text".
+func createLayeredFile(t *testing.T, msg, outFile string, layered func([]byte) []byte) {
+	t.Helper()
+
+	inFile := filepath.Join(inDir, "testWithText.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	ir, err := pdfcpu.AddLayer(ctx, "Text", true)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	d, _, inhPAttrs, err := ctx.PageDict(1, true)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	sd, _ := ctx.NewStreamDictForBuf(layered(bb))
+	if err := sd.Encode(); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	contents, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	d["Contents"] = *contents
+
+	res := inhPAttrs.Resources
+	if res == nil {
+		res = types.Dict{}
+	}
+	res["Properties"] = types.Dict{"oc1": *ir}
+	d["Resources"] = res
+
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+}
+
+func layersFile(t *testing.T, msg, inFile string) []pdfcpu.Layer {
+	t.Helper()
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	ll, err := api.Layers(f, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return ll
+}
+
+// pageOperators returns the content stream operators of a page.
+func pageOperators(t *testing.T, msg, inFile string, pageNr int) map[model.Operator]int {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil && err != model.ErrNoContent {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	ops, err := model.ParseContentStream(bb)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	m := map[model.Operator]int{}
+	for _, op := range ops {
+		m[op.Operator]++
+	}
+
+	return m
+}
+
+func TestLayers(t *testing.T) {
+	msg := "TestLayers"
+	inFile := filepath.Join(outDir, "layers.pdf")
+	createLayeredFile(t, msg, inFile, wrapLayer)
+
+	ll := layersFile(t, msg, inFile)
+	if len(ll) != 1 || ll[0].Name != "Text" || !ll[0].Visible || len(ll[0].Pages) != 1 || ll[0].Pages[0] != 1 {
+		t.Fatalf("%s %s: want visible layer Text on page 1, got: %+v\n", msg, inFile, ll)
+	}
+
+	if err := api.AddLayersFile(inFile, "", []string{"Text"}, true, nil); err == nil {
+		t.Fatalf("%s %s: want duplicate layer error\n", msg, inFile)
+	}
+
+	if err := api.SetLayerVisibilityFile(inFile, "", []string{"Unknown"}, false, nil); err == nil {
+		t.Fatalf("%s %s: want unknown layer error\n", msg, inFile)
+	}
+
+	// Flatten visible layer.
+	outFile := filepath.Join(outDir, "layersFlattenVisible.pdf")
+	if err := api.FlattenLayersFile(inFile, outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if ll := layersFile(t, msg, outFile); len(ll) != 0 {
+		t.Fatalf("%s %s: unexpected layers: %+v\n", msg, outFile, ll)
+	}
+	if m := pageOperators(t, msg, outFile, 1); m[model.OpLineTo] == 0 || m[model.OpBeginMarkedContentProps] > 0 {
+		t.Fatalf("%s %s: want content without optional content, got: %v\n", msg, outFile, m)
+	}
+
+	// Flatten hidden layer.
+	if err := api.SetLayerVisibilityFile(inFile, "", []string{"Text"}, false, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	if ll := layersFile(t, msg, inFile); len(ll) != 1 || ll[0].Visible {
+		t.Fatalf("%s %s: want hidden layer, got: %+v\n", msg, inFile, ll)
+	}
+	outFile = filepath.Join(outDir, "layersFlattenHidden.pdf")
+	if err := api.FlattenLayersFile(inFile, outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if m := pageOperators(t, msg, outFile, 1); m[model.OpLineTo] > 0 {
+		t.Fatalf("%s %s: unexpected layer content: %v\n", msg, outFile, m)
+	}
+
+	// Remove layer along with its content.
+	if err := api.AddLayersFile(inFile, "", []string{"Empty"}, true, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	outFile = filepath.Join(outDir, "layersRemove.pdf")
+	if err := api.RemoveLayersFile(inFile, outFile, []string{"Text"}, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if ll := layersFile(t, msg, outFile); len(ll) != 1 || ll[0].Name != "Empty" {
+		t.Fatalf("%s %s: want remaining layer Empty, got: %+v\n", msg, outFile, ll)
+	}
+	if m := pageOperators(t, msg, outFile, 1); m[model.OpLineTo] > 0 {
+		t.Fatalf("%s %s: unexpected layer content: %v\n", msg, outFile, m)
+	}
+}
+
+func TestRemoveLayerKeepsGraphicsState(t *testing.T) {
+	msg := "TestRemoveLayerKeepsGraphicsState"
+	inFile := filepath.Join(outDir, "layersGraphicsState.pdf")
+
+	// The hidden layer changes the CTM and fill color for the visible content following it.
+	createLayeredFile(t, msg, inFile, func(bb []byte) []byte {
+		hidden := "/OC /oc1 BDC\n1 0 0 1 50 50 cm 1 0 0 rg 0 0 10 10 re W f\nEMC\n"
+		return append([]byte(hidden), bb...)
+	})
+	if err := api.SetLayerVisibilityFile(inFile, "", []string{"Text"}, false, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	want := pageOperators(t, msg, filepath.Join(inDir, "testWithText.pdf"), 1)
+
+	for _, remove := range []bool{false, true} {
+		outFile := filepath.Join(outDir, "layersGraphicsStateFlatten.pdf")
+		var err error
+		if remove {
+			outFile = filepath.Join(outDir, "layersGraphicsStateRemove.pdf")
+			err = api.RemoveLayersFile(inFile, outFile, []string{"Text"}, nil)
+		} else {
+			err = api.FlattenLayersFile(inFile, outFile, nil)
+		}
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, outFile, err)
+		}
+
+		m := pageOperators(t, msg, outFile, 1)
+		for _, op := range []model.Operator{model.OpConcatMatrix, model.OpSetFillRGB, model.OpClip, model.OpEndPath} {
+			if m[op] != want[op]+1 {
+				t.Fatalf("%s %s: want %d %s, got: %d\n", msg, outFile, want[op]+1, op, m[op])
+			}
+		}
+		if m[model.OpFill] != want[model.OpFill] || m[model.OpBeginMarkedContentProps] > 0 {
+			t.Fatalf("%s %s: unexpected hidden content: %v\n", msg, outFile, m)
+		}
+	}
+}
+
+func TestRemoveWatermarkLayer(t *testing.T) {
+	msg := "TestRemoveWatermarkLayer"
+	inFile := filepath.Join(inDir, "bookletTestA6.pdf")
+	outFile := filepath.Join(outDir, "bookletTestA6NoWatermark.pdf")
+
+	ll := layersFile(t, msg, inFile)
+	if len(ll) != 1 || ll[0].Name != "Watermark" || len(ll[0].Pages) != 16 {
+		t.Fatalf("%s %s: want Watermark layer on 16 pages, got: %+v\n", msg, inFile, ll)
+	}
+
+	if err := api.RemoveLayersFile(inFile, outFile, []string{"Watermark"}, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if ll := layersFile(t, msg, outFile); len(ll) != 0 {
+		t.Fatalf("%s %s: unexpected layers: %+v\n", msg, outFile, ll)
+	}
+
+	if m := pageOperators(t, msg, outFile, 1); m[model.OpPaintXObject] > 0 {
+		t.Fatalf("%s %s: unexpected watermark XObject\n", msg, outFile)
+	}
+}
+
+func TestStampLayer(t *testing.T) {
+	msg := "TestStampLayer"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenStampLayer.pdf")
+
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "Draft", "layer:Draft, print:off", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if err := api.AddTextWatermarksFile(outFile, "", nil, false, "Confidential", "view:off", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	ll := layersFile(t, msg, outFile)
+	if len(ll) != 2 {
+		t.Fatalf("%s %s: want 2 layers, got: %+v\n", msg, outFile, ll)
+	}
+	if ll[0].Name != "Draft" || !ll[0].Visible {
+		t.Fatalf("%s %s: want visible layer Draft, got: %+v\n", msg, outFile, ll[0])
+	}
+	if ll[1].Name != "Background" || ll[1].Visible {
+		t.Fatalf("%s %s: want hidden layer Background, got: %+v\n", msg, outFile, ll[1])
+	}
+
+	ctx, err := api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	d, err := ctx.DereferenceDict(*types.NewIndirectRef(ll[0].ObjNr, 0))
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	usage := d.DictEntry("Usage")
+	if usage == nil {
+		t.Fatalf("%s %s: missing Usage for layer Draft\n", msg, outFile)
+	}
+	if s := usage.DictEntry("Print").NameEntry("PrintState"); s == nil || *s != "OFF" {
+		t.Fatalf("%s %s: want PrintState OFF for layer Draft\n", msg, outFile)
+	}
+}
+
+func TestStampExistingLayer(t *testing.T) {
+	msg := "TestStampExistingLayer"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenStampExistingLayer.pdf")
+
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "Draft", "layer:Draft, view:off", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	// Stamping into an existing layer without view or print keeps its usage.
+	if err := api.AddTextWatermarksFile(outFile, "", nil, true, "Draft", "layer:Draft, pos:tl", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	ll := layersFile(t, msg, outFile)
+	if len(ll) != 1 || ll[0].Name != "Draft" || ll[0].Visible {
+		t.Fatalf("%s %s: want hidden layer Draft, got: %+v\n", msg, outFile, ll)
+	}
+}
+
+func TestRemoveStampLayer(t *testing.T) {
+	msg := "TestRemoveStampLayer"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenRemoveStampLayer.pdf")
+
+	if err := api.AddTextWatermarksFile(inFile, outFile, nil, true, "Draft", "layer:Draft", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	ok, err := api.HasWatermarksFile(outFile, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if !ok {
+		t.Fatalf("%s %s: watermarks not detected\n", msg, outFile)
+	}
+
+	if err := api.RemoveWatermarksFile(outFile, "", nil, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if ok, err = api.HasWatermarksFile(outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if ok {
+		t.Fatalf("%s %s: watermarks not removed\n", msg, outFile)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func lazyConf(cmd model.CommandMode) *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.LazyRead = true
+	conf.Cmd = cmd
+	return conf
+}
+
+// lazyRead checks lazily reading fn against a full read.
+func lazyRead(t *testing.T, msg, fn string) {
+	t.Helper()
+
+	inFile := filepath.Join(inDir, fn)
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	defer f.Close()
+
+	want, err := api.PageCount(f, model.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	got, err := api.PageCount(f, lazyConf(model.VALIDATE))
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if got != want {
+		t.Fatalf("%s %s: page count want %d, got %d\n", msg, fn, want, got)
+	}
+
+	// Read only commands leave untouched objects unparsed.
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	ctx, err := api.ReadAndValidate(f, lazyConf(model.LISTINFO))
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if !ctx.Lazy() {
+		t.Fatalf("%s %s: want lazily read context\n", msg, fn)
+	}
+	unparsed := 0
+	for _, entry := range ctx.Table {
+		if !entry.Free && entry.Object == nil {
+			unparsed++
+		}
+	}
+	if unparsed == 0 {
+		t.Fatalf("%s %s: want unparsed objects\n", msg, fn)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	info, err := api.PDFInfo(f, fn, nil, false, lazyConf(model.LISTINFO))
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if info.PageCount != want {
+		t.Fatalf("%s %s: info page count want %d, got %d\n", msg, fn, want, info.PageCount)
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	if _, err := api.Bookmarks(f, lazyConf(model.LISTBOOKMARKS)); err != nil && err != api.ErrNoOutlines {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+
+	// Extracted pages get written with all referenced objects in place.
+	if err := api.ExtractPagesFile(inFile, outDir, []string{"1"}, lazyConf(model.EXTRACTPAGES)); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, fn, err)
+	}
+	outFile := filepath.Join(outDir, strings.TrimSuffix(fn, ".pdf")+"_page_1.pdf")
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+}
+
+func TestLazyRead(t *testing.T) {
+	msg := "TestLazyRead"
+
+	for _, fn := range []string{
+		"adobe_supplement_iso32000_1.pdf", // object streams
+		"Hybrid-PDF.pdf",
+		"TheGoProgrammingLanguageCh1.pdf",
+		"WaldenFull.pdf",
+	} {
+		lazyRead(t, msg, fn)
+	}
+
+	// Commands modifying a file load all objects.
+	inFile := filepath.Join(inDir, "adobe_supplement_iso32000_1.pdf")
+	outFile := filepath.Join(outDir, "lazyRotated.pdf")
+	if err := api.RotateFile(inFile, outFile, 90, nil, lazyConf(model.ROTATE)); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
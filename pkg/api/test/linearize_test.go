@@ -0,0 +1,477 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// linParms returns the integer entries of the linearization parameter dict.
+func linParms(t *testing.T, bb []byte) map[string]int {
+	t.Helper()
+
+	m := regexp.MustCompile(`^%PDF-\d\.\d\s+(?:%[^\r\n]*\s+)?\d+ 0 obj\s*<<(.*?/Linearized 1.*?)>>`).FindSubmatch(bb)
+	if m == nil {
+		t.Fatal("missing linearization parameter dict")
+	}
+
+	parms := map[string]int{}
+	for _, e := range regexp.MustCompile(`/([A-Z])\s*\[?\s*(\d+)`).FindAllSubmatch(m[1], -1) {
+		i, _ := strconv.Atoi(string(e[2]))
+		parms[string(e[1])] = i
+	}
+
+	return parms
+}
+
+func checkLinearized(t *testing.T, fName string, conf *model.Configuration) {
+	t.Helper()
+
+	bb, err := os.ReadFile(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	f, err := os.Open(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+	defer f.Close()
+
+	ctx, err := api.ReadAndValidate(f, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	if !ctx.Read.Linearized {
+		t.Fatalf("%s: expected linearized file\n", fName)
+	}
+
+	parms := linParms(t, bb)
+
+	if parms["L"] != len(bb) {
+		t.Fatalf("%s: L=%d, want %d\n", fName, parms["L"], len(bb))
+	}
+
+	if parms["N"] != ctx.PageCount {
+		t.Fatalf("%s: N=%d, want %d\n", fName, parms["N"], ctx.PageCount)
+	}
+
+	_, indRef, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+	if parms["O"] != indRef.ObjectNumber.Value() {
+		t.Fatalf("%s: O=%d, want %d\n", fName, parms["O"], indRef.ObjectNumber.Value())
+	}
+
+	// The primary hint stream.
+	if !regexp.MustCompile(`^\d+ 0 obj\s*<<[^>]*/S \d+`).Match(bb[parms["H"]:]) {
+		t.Fatalf("%s: H does not point to the hint stream\n", fName)
+	}
+
+	// The first page section ends where the main section starts.
+	if !bytes.HasPrefix(bb[parms["E"]:], []byte("1 0 obj")) {
+		t.Fatalf("%s: E does not point to the main section\n", fName)
+	}
+
+	// T points to the white-space preceding the first entry of the main cross reference table.
+	if !bytes.HasPrefix(bb[parms["T"]+1:], []byte("0000000000 65535 f")) {
+		t.Fatalf("%s: T does not point to the main cross reference table\n", fName)
+	}
+}
+
+func TestLinearize(t *testing.T) {
+	msg := "TestLinearize"
+
+	for _, fn := range []string{"Walden.pdf", "CenterOfWhy.pdf", "5116.DCT_Filter.pdf"} {
+		inFile := filepath.Join(inDir, fn)
+		outFile := filepath.Join(outDir, "lin_"+fn)
+
+		conf := model.NewDefaultConfiguration()
+		conf.Linearize = true
+		if err := api.OptimizeFile(inFile, outFile, conf); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+
+		checkLinearized(t, outFile, nil)
+		checkHintTables(t, outFile)
+	}
+}
+
+func TestLinearizeEncrypted(t *testing.T) {
+	msg := "TestLinearizeEncrypted"
+
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	for i, conf := range []*model.Configuration{
+		model.NewRC4Configuration("upw", "opw", 128),
+		model.NewAESConfiguration("upw", "opw", 256),
+	} {
+		outFile := filepath.Join(outDir, "lin_enc"+strconv.Itoa(i)+"_Walden.pdf")
+
+		conf.Linearize = true
+		if err := api.EncryptFile(inFile, outFile, conf); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+
+		conf = model.NewDefaultConfiguration()
+		conf.UserPW, conf.OwnerPW = "upw", "opw"
+		checkLinearized(t, outFile, conf)
+	}
+}
+
+// hintReader reads the bit fields of a hint stream.
+type hintReader struct {
+	t   *testing.T
+	bb  []byte
+	pos int // bit position
+}
+
+func (r *hintReader) read(n int) int {
+	r.t.Helper()
+	v := 0
+	for i := 0; i < n; i++ {
+		if r.pos/8 >= len(r.bb) {
+			r.t.Fatal("hint stream too short")
+		}
+		v = v<<1 | int(r.bb[r.pos/8]>>uint(7-r.pos%8)&1)
+		r.pos++
+	}
+	return v
+}
+
+func (r *hintReader) reads(count, n int) []int {
+	r.t.Helper()
+	ii := make([]int, count)
+	for i := range ii {
+		ii[i] = r.read(n)
+	}
+	r.align()
+	return ii
+}
+
+func (r *hintReader) align() {
+	r.pos = (r.pos + 7) / 8 * 8
+}
+
+// pageOffsetHints represents a page offset hint table, see F.4.1.
+type pageOffsetHints struct {
+	minObjs, firstPageOffset, objBits, minLen, lenBits      int
+	minContentOffset, contentOffsetBits, minContentLen      int
+	contentLenBits, sharedBits, idBits, numBits, denom      int
+	nObjs, lengths, nShared, contentOffsets, contentLengths []int
+	ids                                                     [][]int
+}
+
+func readPageOffsetHints(r *hintReader, pageCount int) pageOffsetHints {
+	h := pageOffsetHints{
+		minObjs: r.read(32), firstPageOffset: r.read(32), objBits: r.read(16),
+		minLen: r.read(32), lenBits: r.read(16),
+		minContentOffset: r.read(32), contentOffsetBits: r.read(16),
+		minContentLen: r.read(32), contentLenBits: r.read(16),
+		sharedBits: r.read(16), idBits: r.read(16), numBits: r.read(16), denom: r.read(16),
+	}
+
+	h.nObjs = r.reads(pageCount, h.objBits)
+	h.lengths = r.reads(pageCount, h.lenBits)
+	h.nShared = r.reads(pageCount, h.sharedBits)
+	for _, n := range h.nShared {
+		ids := make([]int, n)
+		for i := range ids {
+			ids[i] = r.read(h.idBits)
+		}
+		h.ids = append(h.ids, ids)
+	}
+	r.align()
+	r.reads(pageCount, h.numBits)
+	h.contentOffsets = r.reads(pageCount, h.contentOffsetBits)
+	h.contentLengths = r.reads(pageCount, h.contentLenBits)
+
+	for i := 0; i < pageCount; i++ {
+		h.nObjs[i] += h.minObjs
+		h.lengths[i] += h.minLen
+		h.contentOffsets[i] += h.minContentOffset
+		h.contentLengths[i] += h.minContentLen
+	}
+
+	return h
+}
+
+// sharedObjectHints represents a shared object hint table, see F.4.2.
+type sharedObjectHints struct {
+	firstObjNr, firstOffset, nFirstPage, nGroups int
+	lengths                                      []int
+}
+
+func readSharedObjectHints(r *hintReader) sharedObjectHints {
+	var h sharedObjectHints
+	h.firstObjNr, h.firstOffset = r.read(32), r.read(32)
+	h.nFirstPage, h.nGroups = r.read(32), r.read(32)
+	objBits := r.read(16)
+	minLen, lenBits := r.read(32), r.read(16)
+
+	h.lengths = r.reads(h.nGroups, lenBits)
+	for i := range h.lengths {
+		h.lengths[i] += minLen
+	}
+	for _, md5 := range r.reads(h.nGroups, 1) {
+		if md5 != 0 {
+			r.t.Fatal("unexpected MD5 signature")
+		}
+	}
+	for _, n := range r.reads(h.nGroups, objBits) {
+		if n != 0 {
+			r.t.Fatal("unexpected shared object group size")
+		}
+	}
+
+	return h
+}
+
+var objHeaderRE = regexp.MustCompile(`^(\d+) 0 obj`)
+
+// objNrAt returns the number of the object starting at offset off.
+func objNrAt(bb []byte, off int) int {
+	if off < 0 || off >= len(bb) {
+		return -1
+	}
+	m := objHeaderRE.FindSubmatch(bb[off:])
+	if m == nil {
+		return -1
+	}
+	i, _ := strconv.Atoi(string(m[1]))
+	return i
+}
+
+// pageObjNrs returns the objects used by a page, not including page objects, page tree nodes and the catalog.
+func pageObjNrs(t *testing.T, ctx *model.Context, pageNr int) types.IntSet {
+	t.Helper()
+
+	objNrs := types.IntSet{}
+
+	var walk func(o types.Object)
+	walk = func(o types.Object) {
+		switch o := o.(type) {
+		case types.IndirectRef:
+			objNr := o.ObjectNumber.Value()
+			if objNrs[objNr] {
+				return
+			}
+			o1, err := ctx.Dereference(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d, ok := o1.(types.Dict); ok {
+				if tp := d.Type(); tp != nil && (*tp == "Page" || *tp == "Pages" || *tp == "Catalog") {
+					return
+				}
+			}
+			objNrs[objNr] = true
+			walk(o1)
+		case types.Dict:
+			for k, v := range o {
+				if k != "Parent" {
+					walk(v)
+				}
+			}
+		case types.StreamDict:
+			walk(o.Dict)
+		case types.Array:
+			for _, v := range o {
+				walk(v)
+			}
+		}
+	}
+
+	d, _, _, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walk(d)
+
+	// Inherited page attributes.
+	for p := d.IndirectRefEntry("Parent"); p != nil; {
+		d1, err := ctx.DereferenceDict(*p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if _, found := d.Find(k); !found {
+				walk(d1[k])
+			}
+		}
+		p = d1.IndirectRefEntry("Parent")
+	}
+
+	return objNrs
+}
+
+// checkHintTables decodes the primary hint stream of a linearized file
+// and checks its tables against the object layout of the file.
+func checkHintTables(t *testing.T, fName string) {
+	t.Helper()
+
+	bb, err := os.ReadFile(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	ctx, err := api.ReadContextFile(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	parms := linParms(t, bb)
+
+	m := regexp.MustCompile(`^\d+ 0 obj\s*<<([^>]*)>>\s*stream\r?\n`).FindSubmatch(bb[parms["H"]:])
+	if m == nil {
+		t.Fatalf("%s: missing hint stream\n", fName)
+	}
+	hd := map[string]int{}
+	for _, e := range regexp.MustCompile(`/(\w+) (\d+)`).FindAllSubmatch(m[1], -1) {
+		hd[string(e[1])], _ = strconv.Atoi(string(e[2]))
+	}
+	start := parms["H"] + len(m[0])
+	data := bb[start : start+hd["Length"]]
+
+	n := ctx.PageCount
+	po := readPageOffsetHints(&hintReader{t: t, bb: data}, n)
+	so := readSharedObjectHints(&hintReader{t: t, bb: data[hd["S"]:]})
+
+	// Objects used per page and the number of pages using them.
+	used := make([]types.IntSet, n)
+	count := map[int]int{}
+	for i := range used {
+		used[i] = pageObjNrs(t, ctx, i+1)
+		for objNr := range used[i] {
+			if i > 0 {
+				count[objNr]++
+			}
+		}
+	}
+
+	// The private objects of pages 2..n and the objects shared by them.
+	shared := types.IntSet{}
+	private := make([]int, n)
+	for objNr, c := range count {
+		switch {
+		case used[0][objNr]:
+		case c > 1:
+			shared[objNr] = true
+		}
+	}
+	for i := 1; i < n; i++ {
+		for objNr := range used[i] {
+			if !used[0][objNr] && count[objNr] == 1 {
+				private[i]++
+			}
+		}
+	}
+
+	// Page entries.
+	off := po.firstPageOffset
+	for i := 0; i < n; i++ {
+		_, indRef, _, err := ctx.PageDict(i+1, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := objNrAt(bb, off); got != indRef.ObjectNumber.Value() {
+			t.Fatalf("%s page %d: want page obj#%d at offset %d, got obj#%d\n", fName, i+1, indRef.ObjectNumber.Value(), off, got)
+		}
+
+		objs := len(regexp.MustCompile(`(?:^|[\r\n])\d+ 0 obj`).FindAll(bb[off:off+po.lengths[i]], -1))
+		if objs != po.nObjs[i] {
+			t.Fatalf("%s page %d: want %d objects, got %d\n", fName, i+1, objs, po.nObjs[i])
+		}
+		if i > 0 && po.nObjs[i] != 1+private[i] {
+			t.Fatalf("%s page %d: want %d objects, got %d\n", fName, i+1, 1+private[i], po.nObjs[i])
+		}
+		if po.contentOffsets[i] != 0 || po.contentLengths[i] != po.lengths[i] {
+			t.Fatalf("%s page %d: unexpected content stream hints\n", fName, i+1)
+		}
+
+		off += po.lengths[i]
+		if i == 0 && off != parms["E"] {
+			t.Fatalf("%s: first page ends at %d, want E=%d\n", fName, off, parms["E"])
+		}
+		if objNrAt(bb, off) < 0 {
+			t.Fatalf("%s page %d: length %d does not end at an object boundary\n", fName, i+1, po.lengths[i])
+		}
+	}
+
+	// Shared object groups.
+	if so.nFirstPage != po.nObjs[0] {
+		t.Fatalf("%s: want %d first page groups, got %d\n", fName, po.nObjs[0], so.nFirstPage)
+	}
+	if so.nGroups != so.nFirstPage+len(shared) {
+		t.Fatalf("%s: want %d shared object groups, got %d\n", fName, so.nFirstPage+len(shared), so.nGroups)
+	}
+	if len(shared) > 0 && objNrAt(bb, so.firstOffset) != so.firstObjNr {
+		t.Fatalf("%s: want first shared obj#%d at offset %d\n", fName, so.firstObjNr, so.firstOffset)
+	}
+
+	groupObjNrs := make([]int, so.nGroups)
+	off = po.firstPageOffset
+	for i := 0; i < so.nGroups; i++ {
+		if i == so.nFirstPage {
+			off = so.firstOffset
+		}
+		groupObjNrs[i] = objNrAt(bb, off)
+		if i >= so.nFirstPage && (groupObjNrs[i] != so.firstObjNr+i-so.nFirstPage || !shared[groupObjNrs[i]]) {
+			t.Fatalf("%s: group %d: unexpected shared obj#%d\n", fName, i, groupObjNrs[i])
+		}
+		off += so.lengths[i]
+		if objNrAt(bb, off) < 0 && !bytes.HasPrefix(bb[off:], []byte("xref")) {
+			t.Fatalf("%s: group %d: length %d does not end at an object boundary\n", fName, i, so.lengths[i])
+		}
+	}
+
+	// Shared object references of pages 2..n.
+	for i := 1; i < n; i++ {
+		want := types.IntSet{}
+		for objNr := range used[i] {
+			if used[0][objNr] || shared[objNr] {
+				want[objNr] = true
+			}
+		}
+		got := types.IntSet{}
+		for _, id := range po.ids[i] {
+			if id >= so.nGroups {
+				t.Fatalf("%s page %d: invalid shared object id %d\n", fName, i+1, id)
+			}
+			got[groupObjNrs[id]] = true
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s page %d: want shared objects %v, got %v\n", fName, i+1, want, got)
+		}
+		for objNr := range want {
+			if !got[objNr] {
+				t.Fatalf("%s page %d: want shared objects %v, got %v\n", fName, i+1, want, got)
+			}
+		}
+	}
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package test
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
 func TestOptimize(t *testing.T) {
@@ -41,3 +44,51 @@ func TestOptimize(t *testing.T) {
 		t.Fatalf("%s: %v\n", msg, err)
 	}
 }
+
+func TestOptimizeImages(t *testing.T) {
+	msg := "TestOptimizeImages"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "mountainOptimizedImages.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	conf.OptimizeImages = true
+	conf.ImageQuality = 80
+
+	// Recompress the Flate encoded photo using DCT.
+	if err := api.OptimizeFile(inFile, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	fi1, err := os.Stat(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	fi2, err := os.Stat(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if fi2.Size() >= fi1.Size() {
+		t.Fatalf("%s: want smaller file, got %d >= %d\n", msg, fi2.Size(), fi1.Size())
+	}
+
+	f, err := os.Open(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	pageImages, err := api.Images(f, nil, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for _, m := range pageImages {
+		for _, img := range m {
+			if img.Filter != filter.DCT {
+				t.Errorf("%s: obj#%d: want %s, got %s\n", msg, img.ObjNr, filter.DCT, img.Filter)
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
+)
+
+func validatePDFA(t *testing.T, msg, fileName string, part int) *validate.PDFAReport {
+	t.Helper()
+	return validatePDFAFile(t, msg, filepath.Join(inDir, fileName), part)
+}
+
+func validatePDFAFile(t *testing.T, msg, inFile string, part int) *validate.PDFAReport {
+	t.Helper()
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	r, err := api.ValidatePDFA(f, part, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return r
+}
+
+func pdfaRule(r *validate.PDFAReport, id string) *validate.PDFARule {
+	for _, rule := range r.Rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+func TestValidatePDFA(t *testing.T) {
+	msg := "TestValidatePDFA"
+
+	for _, tt := range []struct {
+		fileName string
+		part     int
+		level    string
+		passed   []string
+		failed   []string
+	}{
+		// No PDF/A identification, fonts embedded.
+		{"Walden.pdf", 0, "PDF/A-1b", []string{"6.1.3", "6.3.4"}, []string{"6.7.11"}},
+		// Fonts not embedded, transparency groups, DeviceRGB images.
+		{"go.pdf", 1, "PDF/A-1b", []string{"6.1.3"}, []string{"6.2.3", "6.3.4", "6.4"}},
+		// PDF/A-2 allows transparency.
+		{"go.pdf", 2, "PDF/A-2b", []string{"6.1.3"}, []string{"6.2.4", "6.2.11.4"}},
+		// Transparency via ExtGState.
+		{"annotTest.pdf", 1, "PDF/A-1b", nil, []string{"6.4"}},
+	} {
+		r := validatePDFA(t, msg, tt.fileName, tt.part)
+
+		if r.Conformant() {
+			t.Fatalf("%s %s: unexpected conformance\n", msg, tt.fileName)
+		}
+		if r.Level() != tt.level {
+			t.Fatalf("%s %s: want %s, got %s\n", msg, tt.fileName, tt.level, r.Level())
+		}
+		for _, id := range tt.passed {
+			if rule := pdfaRule(r, id); rule == nil || !rule.Passed() {
+				t.Fatalf("%s %s: rule %s should pass:\n%s", msg, tt.fileName, id, r)
+			}
+		}
+		for _, id := range tt.failed {
+			if rule := pdfaRule(r, id); rule == nil || rule.Passed() {
+				t.Fatalf("%s %s: rule %s should fail:\n%s", msg, tt.fileName, id, r)
+			}
+		}
+	}
+}
+
+func TestValidateFilePDFA(t *testing.T) {
+	msg := "TestValidateFilePDFA"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidatePDFA = true
+
+	if err := api.ValidateFile(inFile, conf); err == nil {
+		t.Fatalf("%s %s: want PDF/A validation error\n", msg, inFile)
+	}
+
+	// ISO 32000 validation remains unaffected.
+	conf.ValidatePDFA = false
+	if err := api.ValidateFile(inFile, conf); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+}
+
+func TestConvertToPDFA(t *testing.T) {
+	msg := "TestConvertToPDFA"
+
+	for _, tt := range []struct {
+		fileName string
+		part     int
+	}{
+		{"Walden.pdf", 1},
+		{"Walden.pdf", 2},
+		{"Walden.pdf", 3},
+		{"FOSDEM14_HPC_devroom_14_GoCUDA.pdf", 2},
+	} {
+		inFile := filepath.Join(inDir, tt.fileName)
+		outFile := filepath.Join(outDir, "pdfa.pdf")
+
+		if err := api.ConvertToPDFAFile(inFile, outFile, tt.part, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, inFile, err)
+		}
+
+		r := validatePDFAFile(t, msg, outFile, 0)
+		if !r.Conformant() || !r.Claimed || r.Part != tt.part {
+			t.Fatalf("%s %s: want PDF/A-%db conformance:\n%s", msg, inFile, tt.part, r)
+		}
+	}
+}
+
+func TestConvertToPDFAFailure(t *testing.T) {
+	msg := "TestConvertToPDFAFailure"
+	inFile := filepath.Join(inDir, "go.pdf")
+	outFile := filepath.Join(outDir, "pdfa.pdf")
+
+	// Non embedded fonts without matching user fonts and transparency.
+	err := api.ConvertToPDFAFile(inFile, outFile, 1, nil)
+	if err == nil {
+		t.Fatalf("%s %s: want conversion error\n", msg, inFile)
+	}
+
+	for _, s := range []string{"font Arial: no matching user font installed", "6.3.4 page 1: font Arial not embedded", "6.4 "} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("%s %s: missing %q in:\n%v\n", msg, inFile, s, err)
+		}
+	}
+
+	if _, err := os.Stat(outFile); !os.IsNotExist(err) {
+		t.Fatalf("%s %s: unexpected output file\n", msg, inFile)
+	}
+}
+
+func TestConvertToPDFAEmbedFonts(t *testing.T) {
+	msg := "TestConvertToPDFAEmbedFonts"
+	inFile := filepath.Join(inDir, "bookletTestLandscape.pdf")
+
+	// Make Helvetica refer to the installed user font Roboto-Regular.
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	for _, e := range ctx.Table {
+		if e == nil || e.Object == nil {
+			continue
+		}
+		if d, ok := e.Object.(types.Dict); ok && d.Type() != nil && *d.Type() == "Font" {
+			if n := d.NameEntry("BaseFont"); n != nil && *n == "Helvetica" {
+				d["BaseFont"] = types.Name("Roboto-Regular")
+			}
+		}
+	}
+
+	inFile = filepath.Join(outDir, "roboto.pdf")
+	if err := api.WriteContextFile(ctx, inFile); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	outFile := filepath.Join(outDir, "pdfa.pdf")
+	if err := api.ConvertToPDFAFile(inFile, outFile, 2, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	if r := validatePDFAFile(t, msg, outFile, 0); !r.Conformant() {
+		t.Fatalf("%s %s: want PDF/A-2b conformance:\n%s", msg, outFile, r)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
+)
+
+const pdfuaXMP = `<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:pdfuaid="http://www.aiim.org/pdfua/ns/id/">
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">The Go Programming Language</rdf:li></rdf:Alt></dc:title>
+<pdfuaid:part>1</pdfuaid:part>
+</rdf:Description>
+</rdf:RDF>
+</x:xmpmeta>
+<?xpacket end="w"?>`
+
+func validatePDFUAFile(t *testing.T, msg, inFile string) *validate.PDFUAReport {
+	t.Helper()
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	r, err := api.ValidatePDFUA(f, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return r
+}
+
+func pdfuaRule(r *validate.PDFUAReport, description string) *validate.PDFUARule {
+	for _, rule := range r.Rules {
+		if rule.Description == description {
+			return rule
+		}
+	}
+	return nil
+}
+
+func TestValidatePDFUA(t *testing.T) {
+	msg := "TestValidatePDFUA"
+
+	const (
+		identification = "PDF/UA identification schema"
+		tagged         = "marked as tagged PDF with structure tree"
+		content        = "content tagged or marked as artifact"
+		markedContent  = "marked content and structure tree match"
+		title          = "document title displayed"
+		lang           = "document language specified"
+		figures        = "figures with alternate text"
+		headings       = "headings properly nested"
+	)
+
+	for _, tt := range []struct {
+		fileName string
+		passed   []string
+		failed   []string
+	}{
+		// Tagged, missing title display.
+		{"go.pdf", []string{tagged, content, markedContent, lang, figures, headings}, []string{identification, title}},
+		// Figures without Alt, first heading is H2.
+		{"Hybrid-PDF.pdf", []string{tagged, content, markedContent, lang}, []string{figures, headings}},
+		// Untagged content, missing language.
+		{"WaldenFull.pdf", []string{tagged, markedContent}, []string{content, lang}},
+		// Not tagged at all.
+		{"Walden.pdf", nil, []string{tagged, content, lang}},
+	} {
+		r := validatePDFUAFile(t, msg, filepath.Join(inDir, tt.fileName))
+
+		if r.Conformant() || r.Claimed {
+			t.Fatalf("%s %s: unexpected conformance\n", msg, tt.fileName)
+		}
+		for _, s := range tt.passed {
+			if rule := pdfuaRule(r, s); rule == nil || !rule.Passed() {
+				t.Fatalf("%s %s: rule %q should pass:\n%s", msg, tt.fileName, s, r)
+			}
+		}
+		for _, s := range tt.failed {
+			if rule := pdfuaRule(r, s); rule == nil || rule.Passed() {
+				t.Fatalf("%s %s: rule %q should fail:\n%s", msg, tt.fileName, s, r)
+			}
+		}
+	}
+}
+
+func TestValidatePDFUAConformant(t *testing.T) {
+	msg := "TestValidatePDFUAConformant"
+	inFile := filepath.Join(inDir, "go.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	sd, _ := ctx.NewStreamDictForBuf([]byte(pdfuaXMP))
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	if err := sd.Encode(); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	indRef, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+	rootDict["Metadata"] = *indRef
+	rootDict["ViewerPreferences"] = types.Dict{"DisplayDocTitle": types.Boolean(true)}
+
+	outFile := filepath.Join(outDir, "pdfua.pdf")
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	r := validatePDFUAFile(t, msg, outFile)
+	if !r.Conformant() || !r.Claimed {
+		t.Fatalf("%s %s: want PDF/UA-1 conformance:\n%s", msg, outFile, r)
+	}
+
+	// Dropping the structure tree content leaves all marked content unreferenced.
+	if ctx, err = api.ReadContextFile(outFile); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	if rootDict, err = ctx.Catalog(); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	d, err := ctx.DereferenceDict(rootDict["StructTreeRoot"])
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+	d.Delete("K")
+
+	outFile = filepath.Join(outDir, "pdfuaNoStructure.pdf")
+	if err := api.WriteContextFile(ctx, outFile); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	r = validatePDFUAFile(t, msg, outFile)
+	if rule := pdfuaRule(r, "marked content and structure tree match"); rule == nil || rule.Passed() {
+		t.Fatalf("%s %s: want unreferenced MCIDs:\n%s", msg, outFile, r)
+	}
+}
+
+func TestValidateFilePDFUA(t *testing.T) {
+	msg := "TestValidateFilePDFUA"
+	inFile := filepath.Join(inDir, "go.pdf")
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidatePDFUA = true
+
+	if err := api.ValidateFile(inFile, conf); err == nil {
+		t.Fatalf("%s %s: want PDF/UA validation error\n", msg, inFile)
+	}
+}
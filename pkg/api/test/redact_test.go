@@ -0,0 +1,409 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func pageText(t *testing.T, fileName string, pageNr string) string {
+	t.Helper()
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pp, err := api.ExtractText(f, []string{pageNr}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pp[0].String()
+}
+
+func TestRedactText(t *testing.T) {
+	msg := "TestRedactText"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "WaldenRedacted.pdf")
+
+	// Remove "licious evening, wh" from the first line of page 2.
+	areas, err := api.RedactAreas("[150 676 260 684]", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RedactFile(inFile, outFile, []string{"2"}, areas, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	lines := strings.Split(pageText(t, outFile, "2"), "\n")
+	if len(lines) < 3 || lines[2] != "This is a de en the whole body is one sense, and" {
+		t.Fatalf("%s: unexpected text: %v\n", msg, lines)
+	}
+}
+
+func TestRedactImage(t *testing.T) {
+	msg := "TestRedactImage"
+	inFile := filepath.Join(inDir, "mountain.pdf")
+	outFile := filepath.Join(outDir, "mountainRedacted.pdf")
+
+	areas, err := api.RedactAreas("[100 600 400 700]", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RedactFile(inFile, outFile, nil, areas, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+// pageXObject returns the decoded XObject named n of page 1.
+func pageXObject(t *testing.T, ctx *model.Context, n string) *types.StreamDict {
+	t.Helper()
+
+	_, _, inhPAttrs, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xo, err := ctx.DereferenceDict(inhPAttrs.Resources["XObject"])
+	if err != nil || xo == nil {
+		t.Fatalf("missing XObjects: %v\n", err)
+	}
+	sd, _, err := ctx.DereferenceStreamDict(xo[n])
+	if err != nil || sd == nil {
+		t.Fatalf("missing XObject %s: %v\n", n, err)
+	}
+	if err := sd.Decode(); err != nil {
+		t.Fatal(err)
+	}
+
+	return sd
+}
+
+func TestRedactImageColorSpace(t *testing.T) {
+	msg := "TestRedactImageColorSpace"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	tmpFile := filepath.Join(outDir, "WaldenCMYKImage.pdf")
+	outFile := filepath.Join(outDir, "WaldenCMYKImageRedacted.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// A 4x4 CMYK image with a Decode array and an 8x8 image mask, both painted at 100 600 using 100x100 points.
+	cmyk, err := ctx.NewStreamDictForBuf(bytes.Repeat([]byte{0x40}, 4*4*4))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	cmyk.Dict.Update("Type", types.Name("XObject"))
+	cmyk.Dict.Update("Subtype", types.Name("Image"))
+	cmyk.Dict.Update("Width", types.Integer(4))
+	cmyk.Dict.Update("Height", types.Integer(4))
+	cmyk.Dict.Update("ColorSpace", types.Name("DeviceCMYK"))
+	cmyk.Dict.Update("BitsPerComponent", types.Integer(8))
+	cmyk.Dict.Update("Decode", types.NewNumberArray(1, 0, 1, 0, 1, 0, 1, 0))
+	cmyk.Dict.Update("Intent", types.Name("Perceptual"))
+
+	mask, err := ctx.NewStreamDictForBuf(bytes.Repeat([]byte{0xFF}, 8))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	mask.Dict.Update("Type", types.Name("XObject"))
+	mask.Dict.Update("Subtype", types.Name("Image"))
+	mask.Dict.Update("Width", types.Integer(8))
+	mask.Dict.Update("Height", types.Integer(8))
+	mask.Dict.Update("ImageMask", types.Boolean(true))
+
+	xo := types.Dict{}
+	for n, sd := range map[string]*types.StreamDict{"ImCMYK": cmyk, "ImMask": mask} {
+		if err := sd.Encode(); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		ir, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		xo[n] = *ir
+	}
+
+	d, _, inhPAttrs, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	res := inhPAttrs.Resources.Clone().(types.Dict)
+	res["XObject"] = xo
+	d["Resources"] = res
+
+	sd, _ := ctx.NewStreamDictForBuf([]byte("q 100 0 0 100 100 600 cm /ImCMYK Do /ImMask Do Q"))
+	if err := sd.Encode(); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	ir, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d["Contents"] = *ir
+
+	if err := api.WriteContextFile(ctx, tmpFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Redact the lower left quadrant of both images.
+	areas, err := api.RedactAreas("[90 590 150 650]", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RedactFile(tmpFile, outFile, []string{"1"}, areas, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if ctx, err = api.ReadContextFile(outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	im := pageXObject(t, ctx, "ImCMYK")
+	if cs := im.NameEntry("ColorSpace"); cs == nil || *cs != "DeviceCMYK" {
+		t.Fatalf("%s: want DeviceCMYK, got: %v\n", msg, cs)
+	}
+	if a := im.ArrayEntry("Decode"); len(a) != 8 || im.NameEntry("Intent") == nil {
+		t.Fatalf("%s: missing Decode or Intent: %v\n", msg, im.Dict)
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			want := byte(0x40)
+			if x < 2 && y >= 2 {
+				want = 0
+			}
+			if got := im.Content[(y*4+x)*4]; got != want {
+				t.Fatalf("%s: CMYK sample %d,%d: want %02x got %02x\n", msg, x, y, want, got)
+			}
+		}
+	}
+
+	im = pageXObject(t, ctx, "ImMask")
+	if b := im.BooleanEntry("ImageMask"); b == nil || !*b {
+		t.Fatalf("%s: want image mask: %v\n", msg, im.Dict)
+	}
+	for y := 0; y < 8; y++ {
+		want := byte(0xFF)
+		if y >= 4 {
+			want = 0x0F
+		}
+		if got := im.Content[y]; got != want {
+			t.Fatalf("%s: mask row %d: want %02x got %02x\n", msg, y, want, got)
+		}
+	}
+}
+
+func TestRedactAnnotations(t *testing.T) {
+	msg := "TestRedactAnnotations"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	tmpFile := filepath.Join(outDir, "WaldenRedactAnnot.pdf")
+	outFile := filepath.Join(outDir, "WaldenRedactAnnotApplied.pdf")
+
+	// Mark the first line of page 2 for redaction.
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	d, pageIndRef, _, err := ctx.PageDict(2, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	indRef, err := ctx.IndRefForNewObject(types.Dict{
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Redact"),
+		"Rect":    types.NewNumberArray(80, 672, 480, 688),
+		"P":       *pageIndRef,
+		"IC":      types.NewNumberArray(1, 0, 0),
+		"DA":      types.StringLiteral("/Helv 0 Tf 0 g"),
+	})
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d["Annots"] = types.Array{*indRef}
+
+	if err := api.WriteContextFile(ctx, tmpFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RedactFile(tmpFile, outFile, nil, nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	s := pageText(t, outFile, "2")
+	if strings.Contains(s, "delicious") || !strings.Contains(s, "imbibes delight") {
+		t.Fatalf("%s: unexpected text:\n%s\n", msg, s)
+	}
+
+	ctx, err = api.ReadContextFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	d, _, _, err = ctx.PageDict(2, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if a := d.ArrayEntry("Annots"); len(a) > 0 {
+		t.Fatalf("%s: Redact annotation not removed\n", msg)
+	}
+}
+
+func TestRedactOverlappingAnnotations(t *testing.T) {
+	msg := "TestRedactOverlappingAnnotations"
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	tmpFile := filepath.Join(outDir, "WaldenOverlappingAnnots.pdf")
+	outFile := filepath.Join(outDir, "WaldenOverlappingAnnotsRedacted.pdf")
+
+	ctx, err := api.ReadContextFile(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	d, pageIndRef, _, err := ctx.PageDict(2, false)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	helv := types.Dict{"Helv": types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	}}
+
+	appearance := func(s string) types.IndirectRef {
+		sd, _ := ctx.NewStreamDictForBuf([]byte("BT /Helv 10 Tf 2 6 Td (" + s + ") Tj ET"))
+		sd.Dict.Update("Type", types.Name("XObject"))
+		sd.Dict.Update("Subtype", types.Name("Form"))
+		sd.Dict.Update("BBox", types.NewNumberArray(0, 0, 120, 20))
+		sd.Dict.Update("Resources", types.Dict{"Font": helv})
+		if err := sd.Encode(); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		ir, err := ctx.IndRefForNewObject(*sd)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		return *ir
+	}
+
+	newAnnot := func(d types.Dict) types.IndirectRef {
+		d["Type"] = types.Name("Annot")
+		d["P"] = *pageIndRef
+		ir, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		return *ir
+	}
+
+	// "secret" is inside the redaction area, "note" is not.
+	freeText := newAnnot(types.Dict{
+		"Subtype":  types.Name("FreeText"),
+		"Rect":     types.NewNumberArray(80, 600, 200, 620),
+		"Contents": types.StringLiteral("secret note"),
+		"DA":       types.StringLiteral("/Helv 10 Tf 0 g"),
+		"AP":       types.Dict{"N": appearance("secret note")},
+	})
+
+	widget := newAnnot(types.Dict{
+		"Subtype": types.Name("Widget"),
+		"FT":      types.Name("Tx"),
+		"T":       types.StringLiteral("name"),
+		"V":       types.StringLiteral("secret value"),
+		"Rect":    types.NewNumberArray(80, 560, 200, 580),
+		"DA":      types.StringLiteral("/Helv 10 Tf 0 g"),
+		"AP":      types.Dict{"N": appearance("secret value")},
+	})
+
+	text := newAnnot(types.Dict{
+		"Subtype":  types.Name("Text"),
+		"Rect":     types.NewNumberArray(300, 300, 320, 320),
+		"Contents": types.StringLiteral("unrelated"),
+	})
+
+	d["Annots"] = types.Array{freeText, widget, text}
+	ctx.RootDict["AcroForm"] = types.Dict{
+		"Fields": types.Array{widget},
+		"DA":     types.StringLiteral("/Helv 0 Tf 0 g"),
+		"DR":     types.Dict{"Font": helv},
+	}
+
+	if err := api.WriteContextFile(ctx, tmpFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	areas, err := api.RedactAreas("[70 590 111 630] [70 550 210 585]", types.POINTS)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.RedactFile(tmpFile, outFile, []string{"2"}, areas, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// The flattened appearances got redacted.
+	s := pageText(t, outFile, "2")
+	if strings.Contains(s, "secret") || !strings.Contains(s, "note") {
+		t.Fatalf("%s: unexpected text:\n%s\n", msg, s)
+	}
+
+	if ctx, err = api.ReadContextFile(outFile); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if d, _, _, err = ctx.PageDict(2, false); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	annots, err := ctx.DereferenceArray(d["Annots"])
+	if err != nil || len(annots) != 1 {
+		t.Fatalf("%s: want 1 remaining annotation, got: %v %v\n", msg, annots, err)
+	}
+	if d1, err := ctx.DereferenceDict(annots[0]); err != nil || d1.NameEntry("Subtype") == nil || *d1.NameEntry("Subtype") != "Text" {
+		t.Fatalf("%s: want remaining Text annotation, got: %v %v\n", msg, d1, err)
+	}
+	if _, found := ctx.RootDict.Find("AcroForm"); found {
+		t.Fatalf("%s: want form removed\n", msg)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+func renderPage(t *testing.T, msg, fileName string, pageNr int, dpi float64) image.Image {
+	t.Helper()
+	inFile := filepath.Join(inDir, fileName)
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	img, err := api.RenderPage(f, pageNr, dpi, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return img
+}
+
+func darkPixels(img image.Image, r image.Rectangle) int {
+	var n int
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 128 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestRenderPage(t *testing.T) {
+	msg := "TestRenderPage"
+
+	// A4 at 72 dpi.
+	img := renderPage(t, msg, "Walden.pdf", 1, 72)
+	if b := img.Bounds(); b.Dx() != 595 || b.Dy() != 842 {
+		t.Fatalf("%s: want 595x842, got %dx%d\n", msg, b.Dx(), b.Dy())
+	}
+
+	// The title "WALDEN" is drawn using an embedded font near the top of the page.
+	if n := darkPixels(img, image.Rect(150, 60, 450, 120)); n < 500 {
+		t.Fatalf("%s: missing title, %d dark pixels\n", msg, n)
+	}
+
+	// The page margins stay blank.
+	if n := darkPixels(img, image.Rect(0, 0, 595, 20)); n > 0 {
+		t.Fatalf("%s: unexpected content in top margin, %d dark pixels\n", msg, n)
+	}
+
+	// Twice the resolution.
+	img = renderPage(t, msg, "Walden.pdf", 1, 144)
+	if b := img.Bounds(); b.Dx() != 1191 || b.Dy() != 1684 {
+		t.Fatalf("%s: want 1191x1684, got %dx%d\n", msg, b.Dx(), b.Dy())
+	}
+}
+
+func TestRenderRotatedPage(t *testing.T) {
+	msg := "TestRenderRotatedPage"
+
+	// Page 1 has /Rotate 90.
+	img := renderPage(t, msg, "testRot.pdf", 1, 72)
+	if b := img.Bounds(); b.Dx() != 842 || b.Dy() != 595 {
+		t.Fatalf("%s: want 842x595, got %dx%d\n", msg, b.Dx(), b.Dy())
+	}
+}
+
+func TestRenderPagesFile(t *testing.T) {
+	msg := "TestRenderPagesFile"
+	inFile := filepath.Join(inDir, "go.pdf")
+
+	if err := api.RenderPagesFile(inFile, outDir, []string{"1-2"}, 50, "png", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	if err := api.RenderPagesFile(inFile, outDir, []string{"1"}, 50, "jpg", nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	for _, tt := range []struct {
+		fileName string
+		decode   func(*os.File) (image.Image, error)
+	}{
+		{"go_page_1.png", func(f *os.File) (image.Image, error) { return png.Decode(f) }},
+		{"go_page_2.png", func(f *os.File) (image.Image, error) { return png.Decode(f) }},
+		{"go_page_1.jpg", func(f *os.File) (image.Image, error) { return jpeg.Decode(f) }},
+	} {
+		f, err := os.Open(filepath.Join(outDir, tt.fileName))
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		_, err = tt.decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, tt.fileName, err)
+		}
+	}
+
+	if err := api.RenderPagesFile(inFile, outDir, nil, 50, "gif", nil); err == nil {
+		t.Fatalf("%s: want error for unsupported image format\n", msg)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// writeDamagedFile writes a damaged copy of inFile to outDir.
+func writeDamagedFile(t *testing.T, inFile, outFile string, damage func([]byte) []byte) string {
+	t.Helper()
+
+	bb, err := os.ReadFile(filepath.Join(inDir, inFile))
+	if err != nil {
+		t.Fatalf("%s: %v\n", inFile, err)
+	}
+
+	fName := filepath.Join(outDir, outFile)
+	if err := os.WriteFile(fName, damage(bb), 0644); err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	return fName
+}
+
+// truncateAtStartXRef cuts off the last cross reference section.
+func truncateAtStartXRef(bb []byte) []byte {
+	i := bytes.LastIndex(bb, []byte("startxref"))
+	m := regexp.MustCompile(`startxref\s+(\d+)`).FindSubmatch(bb[i:])
+	off, _ := strconv.Atoi(string(m[1]))
+	return bb[:off]
+}
+
+func pageCount(t *testing.T, fName string) int {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	return ctx.PageCount
+}
+
+// repairContext reads and validates fName reconstructing its xRefTable.
+func repairContext(t *testing.T, fName string) *model.Context {
+	t.Helper()
+
+	f, err := os.Open(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+	defer f.Close()
+
+	conf := model.NewDefaultConfiguration()
+	conf.Cmd = model.REPAIR
+
+	ctx, err := api.ReadAndValidate(f, conf)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	return ctx
+}
+
+func TestRepairTruncatedXRef(t *testing.T) {
+	msg := "TestRepairTruncatedXRef"
+
+	// Cross reference stream and cross reference section.
+	for _, fn := range []string{"Walden.pdf", "grid_example.pdf", "CenterOfWhy.pdf"} {
+		fName := writeDamagedFile(t, fn, "truncated_"+fn, truncateAtStartXRef)
+
+		// Reading never rebuilds the xRefTable behind the user's back.
+		if ctx, err := api.ReadContextFile(fName); err == nil && ctx.Read.XRefRebuilt {
+			t.Fatalf("%s %s: unexpected rebuilt xRefTable\n", msg, fName)
+		}
+
+		ctx := repairContext(t, fName)
+		if !ctx.Read.XRefRebuilt {
+			t.Fatalf("%s %s: expected rebuilt xRefTable\n", msg, fName)
+		}
+		if want := pageCount(t, filepath.Join(inDir, fn)); ctx.PageCount != want {
+			t.Fatalf("%s %s: pageCount want:%d got:%d\n", msg, fName, want, ctx.PageCount)
+		}
+	}
+}
+
+func TestRepairMissingTrailer(t *testing.T) {
+	msg := "TestRepairMissingTrailer"
+
+	fName := writeDamagedFile(t, "grid_example.pdf", "noTrailer.pdf", func(bb []byte) []byte {
+		return bb[:bytes.LastIndex(bb, []byte("xref"))]
+	})
+
+	outFile := filepath.Join(outDir, "noTrailerRepaired.pdf")
+	if err := api.RepairFile(fName, outFile, nil); err != nil {
+		t.Fatalf("%s repair: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s validate: %v\n", msg, err)
+	}
+
+	if want, got := pageCount(t, filepath.Join(inDir, "grid_example.pdf")), pageCount(t, outFile); got != want {
+		t.Fatalf("%s: pageCount want:%d got:%d\n", msg, want, got)
+	}
+}
+
+func TestRepairConcatenated(t *testing.T) {
+	msg := "TestRepairConcatenated"
+
+	// A scanner appending a document to a previous one.
+	walden, err := os.ReadFile(filepath.Join(inDir, "Walden.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	fName := writeDamagedFile(t, "CenterOfWhy.pdf", "concatenated.pdf", func(bb []byte) []byte {
+		return append(walden, bb...)
+	})
+
+	// The most recent document wins.
+	if want, got := pageCount(t, filepath.Join(inDir, "CenterOfWhy.pdf")), repairContext(t, fName).PageCount; got != want {
+		t.Fatalf("%s: pageCount want:%d got:%d\n", msg, want, got)
+	}
+}
+
+func TestRepairStreamLength(t *testing.T) {
+	msg := "TestRepairStreamLength"
+
+	inFile := filepath.Join(inDir, "grid_example.pdf")
+
+	// Corrupt all direct stream lengths without changing any object offsets.
+	re := regexp.MustCompile(`/Length \d+\s*[/>]`)
+	fName := writeDamagedFile(t, "grid_example.pdf", "wrongLength.pdf", func(bb []byte) []byte {
+		if !re.Match(bb) {
+			t.Fatalf("%s: no direct stream lengths\n", msg)
+		}
+		return re.ReplaceAllFunc(bb, func(s []byte) []byte {
+			s = bytes.Clone(s)
+			if i := len("/Length "); s[i] == '9' {
+				s[i] = '1'
+			} else {
+				s[i] = '9'
+			}
+			return s
+		})
+	})
+
+	outFile := filepath.Join(outDir, "wrongLengthRepaired.pdf")
+	if err := api.RepairFile(fName, outFile, nil); err != nil {
+		t.Fatalf("%s repair: %v\n", msg, err)
+	}
+
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s validate: %v\n", msg, err)
+	}
+
+	// Repairing an intact file preserves all streams.
+	outFile0 := filepath.Join(outDir, "repaired.pdf")
+	if err := api.RepairFile(inFile, outFile0, nil); err != nil {
+		t.Fatalf("%s repair: %v\n", msg, err)
+	}
+
+	if !sameStreams(rawStreams(t, outFile0), rawStreams(t, outFile)) {
+		t.Fatalf("%s: stream content mismatch\n", msg)
+	}
+}
+
+func TestRepairRedefinedObjectStream(t *testing.T) {
+	msg := "TestRepairRedefinedObjectStream"
+
+	// The most recent catalog lives in an object stream that gets redefined later on.
+	objs := "2 0 <</Type/Catalog/Pages 5 0 R>>"
+	bb := []byte("%PDF-1.7\n" +
+		"4 0 obj\n<</Type/Catalog/Pages 5 0 R>>\nendobj\n" +
+		"5 0 obj\n<</Type/Pages/Kids[6 0 R]/Count 1>>\nendobj\n" +
+		"6 0 obj\n<</Type/Page/Parent 5 0 R/MediaBox[0 0 200 200]>>\nendobj\n" +
+		fmt.Sprintf("1 0 obj\n<</Type/ObjStm/N 1/First 4/Length %d>>\nstream\n%s\nendstream\nendobj\n", len(objs), objs) +
+		"1 0 obj\n<</Foo 1>>\nendobj\n" +
+		"%%EOF\n")
+
+	fName := filepath.Join(outDir, "redefinedObjStm.pdf")
+	if err := os.WriteFile(fName, bb, 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	ctx := repairContext(t, fName)
+	if ctx.Root == nil || ctx.Root.ObjectNumber.Value() != 4 {
+		t.Fatalf("%s: want root obj#4 got: %v\n", msg, ctx.Root)
+	}
+	if ctx.PageCount != 1 {
+		t.Fatalf("%s: pageCount want:1 got:%d\n", msg, ctx.PageCount)
+	}
+}
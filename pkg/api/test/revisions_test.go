@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+)
+
+func copyToOutDir(t *testing.T, fn string) string {
+	t.Helper()
+
+	bb, err := os.ReadFile(filepath.Join(inDir, fn))
+	if err != nil {
+		t.Fatalf("%s: %v\n", fn, err)
+	}
+
+	fName := filepath.Join(outDir, fn)
+	if err := os.WriteFile(fName, bb, 0644); err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	return fName
+}
+
+func revisions(t *testing.T, fName string) []pdfcpu.Revision {
+	t.Helper()
+
+	rr, err := api.RevisionsFile(fName, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	return rr
+}
+
+func TestRevisions(t *testing.T) {
+	msg := "TestRevisions"
+
+	for _, fn := range []string{"Walden.pdf", "CenterOfWhy.pdf"} {
+		inFile := copyToOutDir(t, fn)
+
+		bb, err := os.ReadFile(inFile)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+
+		rr := revisions(t, inFile)
+		if len(rr) != 1 {
+			t.Fatalf("%s %s: got %d revisions, want 1\n", msg, fn, len(rr))
+		}
+		if len(rr[0].Added) == 0 || len(rr[0].Changed) > 0 || len(rr[0].Freed) > 0 {
+			t.Fatalf("%s %s: unexpected changes for original document\n", msg, fn)
+		}
+
+		// Add two annotations as incremental updates.
+		add2Annotations(t, msg, inFile, true)
+
+		rr = revisions(t, inFile)
+		if len(rr) != 3 {
+			t.Fatalf("%s %s: got %d revisions, want 3\n", msg, fn, len(rr))
+		}
+
+		ctx, err := api.ReadContextFile(inFile)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+		_, indRef, _, err := ctx.PageDict(1, false)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+
+		for _, r := range rr[1:] {
+			// Each update adds an annotation and rewrites page 1.
+			if len(r.Added) == 0 {
+				t.Fatalf("%s %s: revision %d: missing added objects\n", msg, fn, r.Nr)
+			}
+			if !slices.Contains(r.Changed, indRef.ObjectNumber.Value()) {
+				t.Fatalf("%s %s: revision %d: page 1 unchanged: %v\n", msg, fn, r.Nr, r.Changed)
+			}
+		}
+
+		ss, err := pdfcpu.ListRevisionChanges(rr, 0)
+		if err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+		if len(ss) != 9 {
+			t.Fatalf("%s %s: unexpected diff: %v\n", msg, fn, ss)
+		}
+
+		// Rollback to the original document.
+		outFile := filepath.Join(outDir, "rev1_"+fn)
+		if err := api.ExtractRevisionFile(inFile, outFile, 1, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+
+		bb1, err := os.ReadFile(outFile)
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if int64(len(bb1)) != rr[0].Size || !bytes.HasPrefix(bb, bb1) {
+			t.Fatalf("%s %s: revision 1 is not a prefix of the original file\n", msg, fn)
+		}
+		if err := api.ValidateFile(outFile, nil); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, fn, err)
+		}
+		if i := annotationCount(t, outFile); i != 0 {
+			t.Fatalf("%s %s: revision 1: got %d annotations, want 0\n", msg, fn, i)
+		}
+
+		if err := api.ExtractRevisionFile(inFile, outFile, 4, nil); err == nil {
+			t.Fatalf("%s %s: extracted missing revision 4\n", msg, fn)
+		}
+	}
+}
+
+func TestRevisionsSigned(t *testing.T) {
+	msg := "TestRevisionsSigned"
+
+	keyDir := filepath.Join(inDir, "sign")
+
+	cred, err := sign.LoadCredentials("test", filepath.Join(keyDir, "test.p12"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signedRevisions.pdf")
+
+	if err := api.SignFile(inFile, outFile, cred, nil, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Modify the signed document.
+	if err := api.AddAnnotationsFile(outFile, "", []string{"1"}, textAnn, nil, true); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	rr := revisions(t, outFile)
+	n := len(rr)
+	if n < 2 {
+		t.Fatalf("%s: got %d revisions, want at least 2\n", msg, n)
+	}
+
+	// The signature covers the revision before the modification.
+	if !rr[n-2].Signed || rr[n-1].Signed {
+		t.Fatalf("%s: signature covers wrong revision\n", msg)
+	}
+	if len(rr[n-1].Added) == 0 || len(rr[n-1].Changed) == 0 {
+		t.Fatalf("%s: missing changes after signing\n", msg)
+	}
+
+	ss, err := pdfcpu.ListRevisionChanges(rr, n)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(ss) != 4 {
+		t.Fatalf("%s: unexpected diff: %v\n", msg, ss)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+)
+
+var byteRangeRE = regexp.MustCompile(`/ByteRange\[(\d+) (\d+) (\d+) (\d+)\s*\]`)
+
+// checkByteRanges verifies that each signature covers the file up to the end of its increment except for /Contents.
+func checkByteRanges(t *testing.T, msg, fileName string, want int) {
+	t.Helper()
+
+	bb, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	mm := byteRangeRE.FindAllSubmatch(bb, -1)
+	if len(mm) != want {
+		t.Fatalf("%s: want %d signatures, got %d\n", msg, want, len(mm))
+	}
+
+	for i, m := range mm {
+		var br [4]int
+		for j := range br {
+			br[j], _ = strconv.Atoi(string(m[j+1]))
+		}
+		if br[0] != 0 || bb[br[1]] != '<' || bb[br[2]-1] != '>' {
+			t.Fatalf("%s: signature %d: corrupt byte range %v\n", msg, i, br)
+		}
+		if bytes.Count(bb[br[1]+1:br[2]-1], []byte("0")) == br[2]-br[1]-2 {
+			t.Fatalf("%s: signature %d: missing signature\n", msg, i)
+		}
+		if i == len(mm)-1 && br[2]+br[3] != len(bb) {
+			t.Fatalf("%s: signature %d: byte range does not cover whole file\n", msg, i)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	msg := "TestSign"
+
+	keyDir := filepath.Join(inDir, "sign")
+
+	cred, err := sign.LoadCredentials("test", filepath.Join(keyDir, "test.p12"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	details, err := sign.ParseDetails("reason:Test, location:Berlin, contact:test@pdfcpu.io")
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signed.pdf")
+
+	if err := api.SignFile(inFile, outFile, cred, details, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	checkByteRanges(t, msg, outFile, 1)
+
+	// Add a second signature using an EC key.
+	if cred, err = sign.LoadCredentials("", filepath.Join(keyDir, "ec.pem")); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	details = &sign.Details{Field: "Approval"}
+	if err := api.SignFile(outFile, "", cred, details, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	checkByteRanges(t, msg, outFile, 2)
+
+	// Signed fields may not be signed again.
+	if err := api.SignFile(outFile, filepath.Join(outDir, "signed2.pdf"), cred, details, nil); err == nil {
+		t.Fatalf("%s: signed field \"Approval\" twice\n", msg)
+	}
+}
+
+func TestValidateSignatures(t *testing.T) {
+	msg := "TestValidateSignatures"
+
+	keyDir := filepath.Join(inDir, "sign")
+
+	roots, err := sign.LoadTrustStore(filepath.Join(keyDir, "cert.pem"), filepath.Join(keyDir, "ec.pem"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	inFile := filepath.Join(inDir, "test.pdf")
+	outFile := filepath.Join(outDir, "signedValidate.pdf")
+
+	for i, fn := range []string{"test.p12", "ec.pem"} {
+		cred, err := sign.LoadCredentials("test", filepath.Join(keyDir, fn))
+		if err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+		if i > 0 {
+			inFile = outFile
+		}
+		if err := api.SignFile(inFile, outFile, cred, nil, nil); err != nil {
+			t.Fatalf("%s: %v\n", msg, err)
+		}
+	}
+
+	sigs, err := api.ValidateSignaturesFile(outFile, roots, nil)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if len(sigs) != 2 {
+		t.Fatalf("%s: want 2 signatures, got %d\n", msg, len(sigs))
+	}
+
+	for i, sig := range sigs {
+		if !sig.Valid() {
+			t.Fatalf("%s: signature %d invalid: %v\n", msg, i, sig.Problems)
+		}
+	}
+
+	// The second signature added a signature field to the revision signed first.
+	if sigs[0].WholeFile || len(sigs[0].ModifiedObjects) == 0 {
+		t.Fatalf("%s: missing modifications of signed revision\n", msg)
+	}
+	if !sigs[1].WholeFile || len(sigs[1].ModifiedObjects) > 0 {
+		t.Fatalf("%s: signature 1 must cover the whole file\n", msg)
+	}
+
+	// Without a trust store containing the signer the certificate chain may not be trusted.
+	if sigs, err = api.ValidateSignaturesFile(outFile, x509.NewCertPool(), nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for i, sig := range sigs {
+		if sig.Trusted || !sig.DigestOK || !sig.SignatureOK {
+			t.Fatalf("%s: signature %d: unexpected result %+v\n", msg, i, sig)
+		}
+	}
+
+	// Tamper with the signed revision.
+	bb, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	i := bytes.Index(bb, []byte("/Producer"))
+	if i < 0 {
+		t.Fatalf("%s: missing /Producer\n", msg)
+	}
+	bb[i+1] = 'p'
+
+	if sigs, err = api.ValidateSignatures(bytes.NewReader(bb), roots, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if sigs[0].DigestOK || sigs[0].Valid() {
+		t.Fatalf("%s: undetected modification\n", msg)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func streamWriteConf() *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.StreamWrite = true
+	return conf
+}
+
+// rawStreams returns the encoded content of all streams of fName.
+func rawStreams(t *testing.T, fName string) [][]byte {
+	t.Helper()
+
+	ctx, err := api.ReadContextFile(fName)
+	if err != nil {
+		t.Fatalf("%s: %v\n", fName, err)
+	}
+
+	var bb [][]byte
+	for _, entry := range ctx.Table {
+		if sd, ok := entry.Object.(types.StreamDict); ok {
+			bb = append(bb, sd.Raw)
+		}
+	}
+
+	return bb
+}
+
+func sameStreams(bb1, bb2 [][]byte) bool {
+	if len(bb1) != len(bb2) {
+		return false
+	}
+
+	m := map[string]int{}
+	for _, b := range bb1 {
+		m[string(b)]++
+	}
+	for _, b := range bb2 {
+		m[string(b)]--
+	}
+	for _, n := range m {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestStreamWrite(t *testing.T) {
+	msg := "TestStreamWrite"
+
+	inFile := filepath.Join(inDir, "CenterOfWhy.pdf")
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	// Stream content stays in the source file.
+	ctx, err := api.ReadValidateAndOptimize(f, streamWriteConf())
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	for objNr, entry := range ctx.Table {
+		if sd, ok := entry.Object.(types.StreamDict); ok && !sd.Detached() {
+			t.Fatalf("%s: obj#%d: stream content loaded\n", msg, objNr)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := api.WriteContext(ctx, &buf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	outFile := filepath.Join(outDir, "streamWrite.pdf")
+	if err := os.WriteFile(outFile, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Streams get copied byte for byte.
+	outFile1 := filepath.Join(outDir, "streamWrite1.pdf")
+	if err := api.OptimizeFile(inFile, outFile1, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !sameStreams(rawStreams(t, outFile), rawStreams(t, outFile1)) {
+		t.Fatalf("%s: stream content differs\n", msg)
+	}
+}
+
+func TestStreamWriteMerge(t *testing.T) {
+	msg := "TestStreamWriteMerge"
+
+	inFiles := []string{
+		filepath.Join(inDir, "Acroforms2.pdf"),
+		filepath.Join(inDir, "adobe_supplement_iso32000_1.pdf"),
+		filepath.Join(inDir, "CenterOfWhy.pdf"),
+		filepath.Join(inDir, "Wonderwall.pdf"),
+	}
+
+	outFile := filepath.Join(outDir, "streamWriteMerge.pdf")
+	if err := api.MergeCreateFile(inFiles, outFile, false, streamWriteConf()); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	outFile1 := filepath.Join(outDir, "streamWriteMerge1.pdf")
+	if err := api.MergeCreateFile(inFiles, outFile1, false, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if !sameStreams(rawStreams(t, outFile), rawStreams(t, outFile1)) {
+		t.Fatalf("%s: stream content differs\n", msg)
+	}
+}
+
+func TestStreamWriteEncrypt(t *testing.T) {
+	msg := "TestStreamWriteEncrypt"
+
+	inFile := filepath.Join(inDir, "5116.DCT_Filter.pdf")
+	outFile := filepath.Join(outDir, "streamWriteEnc.pdf")
+
+	conf := streamWriteConf()
+	conf.UserPW = "upw"
+	conf.OwnerPW = "opw"
+	if err := api.EncryptFile(inFile, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	conf = streamWriteConf()
+	conf.UserPW = "upw"
+	conf.OwnerPW = "opw"
+	if err := api.DecryptFile(outFile, "", conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+	if err := api.ValidateFile(outFile, nil); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
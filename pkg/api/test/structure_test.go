@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func structureTreeFile(t *testing.T, msg, inFile string) *pdfcpu.StructTree {
+	t.Helper()
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	st, err := api.StructureTree(f, nil)
+	if err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	return st
+}
+
+func findStructElem(ee []*pdfcpu.StructElem, typ string) *pdfcpu.StructElem {
+	for _, e := range ee {
+		if e.Type == typ {
+			return e
+		}
+		if e1 := findStructElem(e.Kids, typ); e1 != nil {
+			return e1
+		}
+	}
+	return nil
+}
+
+func TestStructureTree(t *testing.T) {
+	msg := "TestStructureTree"
+	inFile := filepath.Join(inDir, "Hybrid-PDF.pdf")
+
+	st := structureTreeFile(t, msg, inFile)
+	if st == nil {
+		t.Fatalf("%s %s: missing structure tree\n", msg, inFile)
+	}
+
+	if !st.Marked || st.Lang != "en-GB" {
+		t.Fatalf("%s %s: want marked content in en-GB, got: %t %s\n", msg, inFile, st.Marked, st.Lang)
+	}
+
+	if st.RoleMap["Standard"] != "P" {
+		t.Fatalf("%s %s: want role map entry Standard -> P, got: %v\n", msg, inFile, st.RoleMap)
+	}
+
+	e := findStructElem(st.Elements, "Standard")
+	if e == nil || e.Role != "P" || e.Page != 1 || len(e.Content) == 0 {
+		t.Fatalf("%s %s: want Standard paragraph with marked content on page 1, got: %+v\n", msg, inFile, e)
+	}
+	if e.Attributes["Layout"]["Placement"] != "Block" {
+		t.Fatalf("%s %s: want layout attribute Placement=Block, got: %v\n", msg, inFile, e.Attributes)
+	}
+
+	e = findStructElem(st.Elements, "Link")
+	if e == nil || len(e.Objects) == 0 || e.Objects[0].Type != "Link" {
+		t.Fatalf("%s %s: want Link referencing link annotation, got: %+v\n", msg, inFile, e)
+	}
+
+	// Untagged file.
+	inFile = filepath.Join(inDir, "Walden.pdf")
+	if st := structureTreeFile(t, msg, inFile); st != nil {
+		t.Fatalf("%s %s: unexpected structure tree\n", msg, inFile)
+	}
+}
+
+func TestExportStructure(t *testing.T) {
+	msg := "TestExportStructure"
+	inFile := filepath.Join(inDir, "go.pdf")
+
+	f, err := os.Open(inFile)
+	if err != nil {
+		t.Fatalf("%s open: %v\n", msg, err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if err := api.ExportStructureJSON(f, &buf, inFile, nil); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	st := pdfcpu.StructTree{}
+	if err := json.Unmarshal(buf.Bytes(), &st); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	if st.Header.Source != "go.pdf" || len(st.Elements) == 0 || st.RoleMap["Slide"] != "Part" {
+		t.Fatalf("%s %s: unexpected structure export:\n%s", msg, inFile, buf.String())
+	}
+
+	inFile = filepath.Join(inDir, "Walden.pdf")
+	outFile := filepath.Join(outDir, "Walden.json")
+	if err := api.ExportStructureFile(inFile, outFile, nil); err != api.ErrNoStructTree {
+		t.Fatalf("%s %s: want %v, got: %v\n", msg, inFile, api.ErrNoStructTree, err)
+	}
+}
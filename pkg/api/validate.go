@@ -25,6 +25,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/validate"
 	"github.com/pkg/errors"
 )
 
@@ -57,6 +58,14 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 		err = errors.Wrap(err, fmt.Sprintf("validation error (obj#:%d)%s", ctx.CurObj, s))
 	}
 
+	if err == nil && conf.ValidatePDFA {
+		err = checkPDFA(ctx)
+	}
+
+	if err == nil && conf.ValidatePDFUA {
+		err = checkPDFUA(ctx)
+	}
+
 	if err == nil {
 		if conf.Optimize {
 			if log.CLIEnabled() {
@@ -83,13 +92,94 @@ func Validate(rs io.ReadSeeker, conf *model.Configuration) error {
 	return err
 }
 
+func checkPDFA(ctx *model.Context) error {
+	r, err := validate.PDFA(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("%s", r)
+	}
+
+	if !r.Conformant() {
+		return errors.Errorf("pdfcpu: not %s conformant", r.Level())
+	}
+
+	return nil
+}
+
+// ValidatePDFA validates a PDF stream read from rs and checks it for PDF/A conformance.
+// Unless part > 0 rs is checked against the PDF/A part it claims to conform to.
+func ValidatePDFA(rs io.ReadSeeker, part int, conf *model.Configuration) (*validate.PDFAReport, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ValidatePDFA: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VALIDATE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return validate.PDFA(ctx, part)
+}
+
+func checkPDFUA(ctx *model.Context) error {
+	r, err := validate.PDFUA(ctx)
+	if err != nil {
+		return err
+	}
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("%s", r)
+	}
+
+	if !r.Conformant() {
+		return errors.New("pdfcpu: not PDF/UA-1 conformant")
+	}
+
+	return nil
+}
+
+// ValidatePDFUA validates a PDF stream read from rs and checks it for PDF/UA conformance.
+func ValidatePDFUA(rs io.ReadSeeker, conf *model.Configuration) (*validate.PDFUAReport, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: ValidatePDFUA: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VALIDATE
+
+	ctx, err := ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return validate.PDFUA(ctx)
+}
+
 // ValidateFile validates inFile.
 func ValidateFile(inFile string, conf *model.Configuration) error {
 	if conf == nil {
 		conf = model.NewDefaultConfiguration()
 	}
 
-	log.CLI.Printf("validating(mode=%s) %s ...\n", conf.ValidationModeString(), inFile)
+	mode := conf.ValidationModeString()
+	if conf.ValidatePDFA {
+		mode += ", pdfa"
+	}
+	if conf.ValidatePDFUA {
+		mode += ", pdfua"
+	}
+
+	log.CLI.Printf("validating(mode=%s) %s ...\n", mode, inFile)
 
 	f, err := os.Open(inFile)
 	if err != nil {
@@ -158,6 +158,11 @@ func ExtractContent(cmd *Command) ([]string, error) {
 	return nil, api.ExtractContentFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, cmd.Conf)
 }
 
+// ExtractText writes the text of selected pages of inFile into outDir.
+func ExtractText(cmd *Command) ([]string, error) {
+	return nil, api.ExtractTextFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, cmd.BoolVal1, cmd.Conf)
+}
+
 // ExtractMetadata dumps all metadata dict entries for inFile into outDir.
 func ExtractMetadata(cmd *Command) ([]string, error) {
 	return nil, api.ExtractMetadataFile(*cmd.InFile, *cmd.OutDir, cmd.Conf)
@@ -436,7 +441,97 @@ func ResetViewerPreferences(cmd *Command) ([]string, error) {
 	return nil, api.ResetViewerPreferencesFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
 }
 
+// Redact removes content of selected pages covered by areas or Redact annotations.
+func Redact(cmd *Command) ([]string, error) {
+	return nil, api.RedactFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.Areas, cmd.Conf)
+}
+
+// Render rasterizes selected pages of inFile into outDir.
+func Render(cmd *Command) ([]string, error) {
+	return nil, api.RenderPagesFile(*cmd.InFile, *cmd.OutDir, cmd.PageSelection, float64(cmd.IntVal), cmd.StringVal, cmd.Conf)
+}
+
+// PDFAConvert converts inFile into a PDF/A file.
+func PDFAConvert(cmd *Command) ([]string, error) {
+	return nil, api.ConvertToPDFAFile(*cmd.InFile, *cmd.OutFile, cmd.IntVal, cmd.Conf)
+}
+
+// ListStructure returns inFile's logical structure.
+func ListStructure(cmd *Command) ([]string, error) {
+	return ListStructureFile(*cmd.InFile, cmd.Conf)
+}
+
+// ExportStructure returns a representation of inFile's logical structure as outFileJSON.
+func ExportStructure(cmd *Command) ([]string, error) {
+	return nil, api.ExportStructureFile(*cmd.InFile, *cmd.OutFileJSON, cmd.Conf)
+}
+
+// ListLayers returns inFile's optional content groups.
+func ListLayers(cmd *Command) ([]string, error) {
+	return ListLayersFile(*cmd.InFile, cmd.Conf)
+}
+
+// AddLayers adds empty layers to inFile and writes the result to outFile.
+func AddLayers(cmd *Command) ([]string, error) {
+	return nil, api.AddLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, true, cmd.Conf)
+}
+
+// RemoveLayers deletes layers along with their content from inFile and writes the result to outFile.
+func RemoveLayers(cmd *Command) ([]string, error) {
+	return nil, api.RemoveLayersFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, cmd.Conf)
+}
+
+// ShowLayers makes layers of inFile visible by default and writes the result to outFile.
+func ShowLayers(cmd *Command) ([]string, error) {
+	return nil, api.SetLayerVisibilityFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, true, cmd.Conf)
+}
+
+// HideLayers makes layers of inFile hidden by default and writes the result to outFile.
+func HideLayers(cmd *Command) ([]string, error) {
+	return nil, api.SetLayerVisibilityFile(*cmd.InFile, *cmd.OutFile, cmd.StringVals, false, cmd.Conf)
+}
+
+// FlattenLayers merges the visible layers of inFile into the page content and writes the result to outFile.
+func FlattenLayers(cmd *Command) ([]string, error) {
+	return nil, api.FlattenLayersFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+}
+
+// Repair rebuilds the cross reference table of inFile and writes the result to outFile.
+func Repair(cmd *Command) ([]string, error) {
+	return nil, api.RepairFile(*cmd.InFile, *cmd.OutFile, cmd.Conf)
+}
+
+// ListRevisions returns the revisions of inFile.
+func ListRevisions(cmd *Command) ([]string, error) {
+	return ListRevisionsFile(*cmd.InFile, cmd.Conf)
+}
+
+// ExtractRevision writes the document of inFile as it was at a specific revision to outFile.
+func ExtractRevision(cmd *Command) ([]string, error) {
+	return nil, api.ExtractRevisionFile(*cmd.InFile, *cmd.OutFile, cmd.IntVal, cmd.Conf)
+}
+
+// DiffRevisions returns the objects added, changed or freed by the incremental updates of inFile.
+func DiffRevisions(cmd *Command) ([]string, error) {
+	return DiffRevisionsFile(*cmd.InFile, cmd.IntVal, cmd.Conf)
+}
+
 // Zoom in/out of selected pages either by zoom factor or corresponding margin.
 func Zoom(cmd *Command) ([]string, error) {
 	return nil, api.ZoomFile(*cmd.InFile, *cmd.OutFile, cmd.PageSelection, cmd.Zoom, cmd.Conf)
 }
+
+// Sign signs inFile and writes the result as incremental update to outFile.
+func Sign(cmd *Command) ([]string, error) {
+	return nil, api.SignFile(*cmd.InFile, *cmd.OutFile, cmd.Credentials, cmd.SignDetails, cmd.Conf)
+}
+
+// ListSignatures returns the signature fields of inFiles.
+func ListSignatures(cmd *Command) ([]string, error) {
+	return ListSignaturesFile(cmd.InFiles, cmd.Conf)
+}
+
+// ValidateSignatures verifies the signatures of inFiles.
+func ValidateSignatures(cmd *Command) ([]string, error) {
+	return ValidateSignaturesFile(cmd.InFiles, cmd.TrustStore, cmd.Conf)
+}
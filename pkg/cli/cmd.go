@@ -21,6 +21,8 @@ import (
 
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 // Command represents an execution context.
@@ -53,6 +55,10 @@ type Command struct {
 	PageBoundaries    *model.PageBoundaries
 	Resize            *model.Resize
 	Zoom              *model.Zoom
+	Areas             []types.Rectangle
+	SignDetails       *sign.Details
+	Credentials       *sign.Credentials
+	TrustStore        []string
 	Watermark         *model.Watermark
 	ViewerPreferences *model.ViewerPreferences
 	PageConf          *pdfcpu.PageConfiguration
@@ -140,6 +146,25 @@ var cmdMap = map[model.CommandMode]func(cmd *Command) ([]string, error){
 	model.SETVIEWERPREFERENCES:    processViewerPreferences,
 	model.RESETVIEWERPREFERENCES:  processViewerPreferences,
 	model.ZOOM:                    Zoom,
+	model.SIGN:                    Sign,
+	model.LISTSIGNATURES:          processSignatures,
+	model.VALIDATESIGNATURES:      processSignatures,
+	model.EXTRACTTEXT:             ExtractText,
+	model.REDACT:                  Redact,
+	model.RENDER:                  Render,
+	model.PDFACONVERT:             PDFAConvert,
+	model.LISTSTRUCTURE:           processStructure,
+	model.EXPORTSTRUCTURE:         processStructure,
+	model.LISTLAYERS:              processLayers,
+	model.ADDLAYERS:               processLayers,
+	model.REMOVELAYERS:            processLayers,
+	model.SHOWLAYERS:              processLayers,
+	model.HIDELAYERS:              processLayers,
+	model.FLATTENLAYERS:           processLayers,
+	model.REPAIR:                  Repair,
+	model.LISTREVISIONS:           processRevisions,
+	model.EXTRACTREVISION:         processRevisions,
+	model.DIFFREVISIONS:           processRevisions,
 }
 
 // ValidateCommand creates a new command to validate a file.
@@ -297,6 +322,21 @@ func ExtractContentCommand(inFile string, outDir string, pageSelection []string,
 		Conf:          conf}
 }
 
+// ExtractTextCommand creates a new command to extract page text as plain text or JSON.
+func ExtractTextCommand(inFile string, outDir string, pageSelection []string, json bool, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTTEXT
+	return &Command{
+		Mode:          model.EXTRACTTEXT,
+		InFile:        &inFile,
+		OutDir:        &outDir,
+		PageSelection: pageSelection,
+		BoolVal1:      json,
+		Conf:          conf}
+}
+
 // ExtractMetadataCommand creates a new command to extract metadata streams.
 func ExtractMetadataCommand(inFile string, outDir string, conf *model.Configuration) *Command {
 	if conf == nil {
@@ -1246,3 +1286,247 @@ func ZoomCommand(inFile, outFile string, pageSelection []string, zoom *model.Zoo
 		Zoom:          zoom,
 		Conf:          conf}
 }
+
+// RedactCommand creates a new command to redact areas and Redact annotations of selected pages.
+func RedactCommand(inFile, outFile string, pageSelection []string, areas []types.Rectangle, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REDACT
+	return &Command{
+		Mode:          model.REDACT,
+		InFile:        &inFile,
+		OutFile:       &outFile,
+		PageSelection: pageSelection,
+		Areas:         areas,
+		Conf:          conf}
+}
+
+// RenderCommand creates a new command to render selected pages as png or jpg images.
+func RenderCommand(inFile, outDir string, pageSelection []string, dpi int, format string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.RENDER
+	return &Command{
+		Mode:          model.RENDER,
+		InFile:        &inFile,
+		OutDir:        &outDir,
+		PageSelection: pageSelection,
+		IntVal:        dpi,
+		StringVal:     format,
+		Conf:          conf}
+}
+
+// PDFAConvertCommand creates a new command to convert a file into PDF/A-1b, PDF/A-2b or PDF/A-3b.
+func PDFAConvertCommand(inFile, outFile string, part int, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.PDFACONVERT
+	return &Command{
+		Mode:    model.PDFACONVERT,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		IntVal:  part,
+		Conf:    conf}
+}
+
+// ListStructureCommand creates a new command to list the logical structure of inFile.
+func ListStructureCommand(inFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTSTRUCTURE
+	return &Command{
+		Mode:   model.LISTSTRUCTURE,
+		InFile: &inFile,
+		Conf:   conf}
+}
+
+// ExportStructureCommand creates a new command to export the logical structure of inFile.
+func ExportStructureCommand(inFile, outFileJSON string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXPORTSTRUCTURE
+	return &Command{
+		Mode:        model.EXPORTSTRUCTURE,
+		InFile:      &inFile,
+		OutFileJSON: &outFileJSON,
+		Conf:        conf}
+}
+
+// ListLayersCommand creates a new command to list the layers of inFile.
+func ListLayersCommand(inFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTLAYERS
+	return &Command{
+		Mode:   model.LISTLAYERS,
+		InFile: &inFile,
+		Conf:   conf}
+}
+
+// AddLayersCommand creates a new command to add empty layers to inFile.
+func AddLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDLAYERS
+	return &Command{
+		Mode:       model.ADDLAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// RemoveLayersCommand creates a new command to remove layers along with their content from inFile.
+func RemoveLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REMOVELAYERS
+	return &Command{
+		Mode:       model.REMOVELAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// ShowLayersCommand creates a new command to make layers of inFile visible by default.
+func ShowLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SHOWLAYERS
+	return &Command{
+		Mode:       model.SHOWLAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// HideLayersCommand creates a new command to make layers of inFile hidden by default.
+func HideLayersCommand(inFile, outFile string, names []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.HIDELAYERS
+	return &Command{
+		Mode:       model.HIDELAYERS,
+		InFile:     &inFile,
+		OutFile:    &outFile,
+		StringVals: names,
+		Conf:       conf}
+}
+
+// FlattenLayersCommand creates a new command to merge the visible layers of inFile into the page content.
+func FlattenLayersCommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.FLATTENLAYERS
+	return &Command{
+		Mode:    model.FLATTENLAYERS,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
+// RepairCommand creates a new command to rebuild the cross reference table of a damaged file.
+func RepairCommand(inFile, outFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.REPAIR
+	return &Command{
+		Mode:    model.REPAIR,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		Conf:    conf}
+}
+
+// ListRevisionsCommand creates a new command to list the revisions of inFile.
+func ListRevisionsCommand(inFile string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTREVISIONS
+	return &Command{
+		Mode:   model.LISTREVISIONS,
+		InFile: &inFile,
+		Conf:   conf}
+}
+
+// ExtractRevisionCommand creates a new command to write the document of inFile as it was at revision nr to outFile.
+func ExtractRevisionCommand(inFile, outFile string, nr int, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.EXTRACTREVISION
+	return &Command{
+		Mode:    model.EXTRACTREVISION,
+		InFile:  &inFile,
+		OutFile: &outFile,
+		IntVal:  nr,
+		Conf:    conf}
+}
+
+// DiffRevisionsCommand creates a new command to list the objects added, changed or freed by revision nr of inFile.
+// nr = 0 covers all incremental updates.
+func DiffRevisionsCommand(inFile string, nr int, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.DIFFREVISIONS
+	return &Command{
+		Mode:   model.DIFFREVISIONS,
+		InFile: &inFile,
+		IntVal: nr,
+		Conf:   conf}
+}
+
+// SignCommand creates a new command to sign a file.
+func SignCommand(inFile, outFile string, cred *sign.Credentials, details *sign.Details, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SIGN
+	return &Command{
+		Mode:        model.SIGN,
+		InFile:      &inFile,
+		OutFile:     &outFile,
+		SignDetails: details,
+		Credentials: cred,
+		Conf:        conf}
+}
+
+// ListSignaturesCommand creates a new command to list the signature fields of inFiles.
+func ListSignaturesCommand(inFiles []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.LISTSIGNATURES
+	return &Command{
+		Mode:    model.LISTSIGNATURES,
+		InFiles: inFiles,
+		Conf:    conf}
+}
+
+// ValidateSignaturesCommand creates a new command to validate the signatures of inFiles against trustStore.
+func ValidateSignaturesCommand(inFiles, trustStore []string, conf *model.Configuration) *Command {
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.VALIDATESIGNATURES
+	return &Command{
+		Mode:       model.VALIDATESIGNATURES,
+		InFiles:    inFiles,
+		TrustStore: trustStore,
+		Conf:       conf}
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package cli
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -32,6 +33,7 @@ import (
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"github.com/pkg/errors"
 )
@@ -541,3 +543,237 @@ func ListBookmarksFile(inFile string, conf *model.Configuration) ([]string, erro
 
 	return listBookmarks(f, conf)
 }
+
+func listStructure(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: listStructure: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTSTRUCTURE
+
+	ctx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.StructureList(ctx)
+}
+
+// ListStructureFile returns the logical structure of inFile.
+func ListStructureFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return listStructure(f, conf)
+}
+
+func listLayers(rs io.ReadSeeker, conf *model.Configuration) ([]string, error) {
+	if rs == nil {
+		return nil, errors.New("pdfcpu: listLayers: missing rs")
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	} else {
+		conf.ValidationMode = model.ValidationRelaxed
+	}
+	conf.Cmd = model.LISTLAYERS
+
+	ctx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.ListLayers(ctx)
+}
+
+// ListLayersFile returns the optional content groups of inFile.
+func ListLayersFile(inFile string, conf *model.Configuration) ([]string, error) {
+	f, err := os.Open(inFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return listLayers(f, conf)
+}
+
+// ListRevisionsFile returns the revisions of inFile.
+func ListRevisionsFile(inFile string, conf *model.Configuration) ([]string, error) {
+	rr, err := api.RevisionsFile(inFile, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.ListRevisions(rr), nil
+}
+
+// DiffRevisionsFile returns the objects added, changed or freed by revision nr of inFile.
+// nr = 0 covers all incremental updates.
+func DiffRevisionsFile(inFile string, nr int, conf *model.Configuration) ([]string, error) {
+	rr, err := api.RevisionsFile(inFile, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return pdfcpu.ListRevisionChanges(rr, nr)
+}
+
+func signatureLines(sig *sign.Signature) []string {
+	ss := []string{}
+
+	if !sig.Signed {
+		return append(ss, fmt.Sprintf("%s: unsigned", sig.Field))
+	}
+
+	ss = append(ss, fmt.Sprintf("%s:", sig.Field))
+
+	add := func(k, v string) {
+		if v != "" {
+			ss = append(ss, fmt.Sprintf("%12s: %s", k, v))
+		}
+	}
+
+	add("Name", sig.Name)
+	if sig.Signer != nil {
+		add("Signer", sig.Signer.Subject.String())
+	}
+	if !sig.Time.IsZero() {
+		add("Time", sig.Time.Format(time.RFC3339))
+	}
+	add("Reason", sig.Reason)
+	add("Location", sig.Location)
+	add("Contact", sig.Contact)
+	add("SubFilter", sig.SubFilter)
+	add("ByteRange", fmt.Sprintf("%v", sig.ByteRange))
+
+	coverage := "partial revision"
+	switch {
+	case sig.WholeFile:
+		coverage = "whole file"
+	case sig.SignedRevisionEnd() > 0:
+		coverage = fmt.Sprintf("revision ending at offset %d, followed by incremental updates", sig.SignedRevisionEnd())
+	}
+	add("Coverage", coverage)
+
+	if !sig.Verified {
+		return ss
+	}
+
+	if !sig.WholeFile {
+		s := "none"
+		if len(sig.ModifiedObjects) > 0 {
+			s = fmt.Sprintf("%v", sig.ModifiedObjects)
+		}
+		add("Modified", s)
+	}
+
+	okStr := func(ok bool, s string) string {
+		if ok {
+			return "ok"
+		}
+		return s
+	}
+
+	add("Integrity", okStr(sig.DigestOK, "failed"))
+	add("Signature", okStr(sig.SignatureOK, "failed"))
+	add("Certificate", okStr(sig.Trusted, "untrusted"))
+	for _, p := range sig.Problems {
+		add("Problem", p)
+	}
+	status := "invalid"
+	if sig.Valid() {
+		status = "valid"
+	}
+	add("Status", status)
+
+	return ss
+}
+
+func signaturesFile(inFiles []string, validate func(f *os.File) ([]*sign.Signature, error)) ([]string, bool, error) {
+	log.SetCLILogger(nil)
+
+	ss := []string{}
+	valid := true
+
+	for _, fn := range inFiles {
+
+		f, err := os.Open(fn)
+		if err != nil {
+			if len(inFiles) > 1 {
+				ss = append(ss, fmt.Sprintf("\ncan't open %s: %v", fn, err))
+				valid = false
+				continue
+			}
+			return nil, false, err
+		}
+		defer f.Close()
+
+		sigs, err := validate(f)
+		if err != nil {
+			if len(inFiles) > 1 {
+				ss = append(ss, fmt.Sprintf("\n%s:\n%v", fn, err))
+				valid = false
+				continue
+			}
+			return nil, false, err
+		}
+
+		ss = append(ss, "\n"+fn+":\n")
+		if len(sigs) == 0 {
+			ss = append(ss, "no signature fields")
+			continue
+		}
+
+		for _, sig := range sigs {
+			ss = append(ss, signatureLines(sig)...)
+			if sig.Verified && sig.Signed && !sig.Valid() {
+				valid = false
+			}
+		}
+	}
+
+	return ss, valid, nil
+}
+
+// ListSignaturesFile returns a list of the signature fields of inFiles.
+func ListSignaturesFile(inFiles []string, conf *model.Configuration) ([]string, error) {
+	ss, _, err := signaturesFile(inFiles, func(f *os.File) ([]*sign.Signature, error) {
+		return api.Signatures(f, conf)
+	})
+	return ss, err
+}
+
+// ValidateSignaturesFile verifies the signatures of inFiles against the certificates in trustStore.
+// If trustStore is empty, the system trust store is used.
+func ValidateSignaturesFile(inFiles, trustStore []string, conf *model.Configuration) ([]string, error) {
+	var roots *x509.CertPool
+
+	if len(trustStore) > 0 {
+		var err error
+		if roots, err = sign.LoadTrustStore(trustStore...); err != nil {
+			return nil, err
+		}
+	}
+
+	ss, valid, err := signaturesFile(inFiles, func(f *os.File) ([]*sign.Signature, error) {
+		return api.ValidateSignatures(f, roots, conf)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !valid {
+		return ss, errors.New("pdfcpu: signature validation failed")
+	}
+
+	return ss, nil
+}
@@ -278,3 +278,70 @@ func processViewerPreferences(cmd *Command) (out []string, err error) {
 
 	return nil, nil
 }
+
+func processStructure(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTSTRUCTURE:
+		return ListStructure(cmd)
+
+	case model.EXPORTSTRUCTURE:
+		return ExportStructure(cmd)
+	}
+
+	return nil, nil
+}
+
+func processLayers(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTLAYERS:
+		return ListLayers(cmd)
+
+	case model.ADDLAYERS:
+		return AddLayers(cmd)
+
+	case model.REMOVELAYERS:
+		return RemoveLayers(cmd)
+
+	case model.SHOWLAYERS:
+		return ShowLayers(cmd)
+
+	case model.HIDELAYERS:
+		return HideLayers(cmd)
+
+	case model.FLATTENLAYERS:
+		return FlattenLayers(cmd)
+	}
+
+	return nil, nil
+}
+
+func processRevisions(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTREVISIONS:
+		return ListRevisions(cmd)
+
+	case model.EXTRACTREVISION:
+		return ExtractRevision(cmd)
+
+	case model.DIFFREVISIONS:
+		return DiffRevisions(cmd)
+	}
+
+	return nil, nil
+}
+
+func processSignatures(cmd *Command) (out []string, err error) {
+	switch cmd.Mode {
+
+	case model.LISTSIGNATURES:
+		return ListSignatures(cmd)
+
+	case model.VALIDATESIGNATURES:
+		return ValidateSignatures(cmd)
+	}
+
+	return nil, nil
+}
@@ -33,9 +33,14 @@ import (
 var inDir, outDir, resDir, fontDir, samplesDir string
 var conf *model.Configuration
 
-func isTrueType(filename string) bool {
+func isUserFont(filename string) bool {
 	s := strings.ToLower(filename)
-	return strings.HasSuffix(s, ".ttf") || strings.HasSuffix(s, ".ttc")
+	for _, ext := range []string{".ttf", ".ttc", ".otf", ".otc", ".pfb", ".pfa"} {
+		if strings.HasSuffix(s, ext) {
+			return true
+		}
+	}
+	return false
 }
 
 func userFonts(dir string) ([]string, error) {
@@ -45,7 +50,7 @@ func userFonts(dir string) ([]string, error) {
 	}
 	ff := []string(nil)
 	for _, f := range files {
-		if isTrueType(f.Name()) {
+		if isUserFont(f.Name()) {
 			fn := filepath.Join(dir, f.Name())
 			ff = append(ff, fn)
 		}
@@ -96,3 +96,13 @@ func TestExtractMetadataCommand(t *testing.T) {
 		t.Fatalf("%s %s: %v\n", msg, inFile, err)
 	}
 }
+
+func TestExtractTextCommand(t *testing.T) {
+	msg := "TestExtractTextCommand"
+	// Extract text of all pages including positions into outDir.
+	inFile := filepath.Join(inDir, "Walden.pdf")
+	cmd := cli.ExtractTextCommand(inFile, outDir, nil, true, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+}
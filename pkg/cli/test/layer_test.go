@@ -0,0 +1,60 @@
+/*
+	Copyright 2023 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestLayersCommand(t *testing.T) {
+	msg := "TestLayersCommand"
+	inFile := filepath.Join(inDir, "bookletTestA6.pdf")
+	outFile := filepath.Join(outDir, "bookletTestA6Layers.pdf")
+
+	cmd := cli.ListLayersCommand(inFile, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s list: %v\n", msg, err)
+	}
+
+	cmd = cli.FlattenLayersCommand(inFile, outFile, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s flatten: %v\n", msg, err)
+	}
+
+	cmd = cli.AddLayersCommand(outFile, "", []string{"Notes"}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s add: %v\n", msg, err)
+	}
+
+	cmd = cli.HideLayersCommand(outFile, "", []string{"Notes"}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s hide: %v\n", msg, err)
+	}
+
+	cmd = cli.ShowLayersCommand(outFile, "", []string{"Notes"}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s show: %v\n", msg, err)
+	}
+
+	cmd = cli.RemoveLayersCommand(outFile, "", []string{"Notes"}, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s remove: %v\n", msg, err)
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestRedactCommand(t *testing.T) {
+	msg := "TestRedactCommand"
+
+	inFile := filepath.Join(inDir, "testImage.pdf")
+	outFile := filepath.Join(outDir, "testImageRedacted.pdf")
+
+	areas, err := pdfcpu.ParseRedactAreas("[1 1 5 5] [10 10 15 12]", types.CENTIMETRES)
+	if err != nil {
+		t.Fatalf("%s invalid redaction areas: %v\n", msg, err)
+	}
+
+	cmd := cli.RedactCommand(inFile, outFile, nil, areas, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, outFile, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
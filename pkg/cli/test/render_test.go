@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestRenderCommand(t *testing.T) {
+	msg := "TestRenderCommand"
+
+	for _, tt := range []struct {
+		fileName string
+		pages    []string
+		dpi      int
+		format   string
+	}{
+		{"Walden.pdf", nil, 72, "png"},
+		{"annotTest.pdf", nil, 72, "png"},
+		{"VectorApple.pdf", nil, 150, "jpg"},
+		{"adobe_errata.pdf", []string{"1-3"}, 100, "png"},
+	} {
+		inFile := filepath.Join(inDir, tt.fileName)
+		cmd := cli.RenderCommand(inFile, outDir, tt.pages, tt.dpi, tt.format, conf)
+		if _, err := cli.Process(cmd); err != nil {
+			t.Fatalf("%s %s: %v\n", msg, inFile, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestRepairCommand(t *testing.T) {
+	msg := "TestRepairCommand"
+
+	bb, err := os.ReadFile(filepath.Join(inDir, "Walden.pdf"))
+	if err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	// Drop startxref.
+	inFile := filepath.Join(outDir, "WaldenTruncated.pdf")
+	if err := os.WriteFile(inFile, bb[:bytes.LastIndex(bb, []byte("startxref"))], 0644); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	outFile := filepath.Join(outDir, "WaldenRepaired.pdf")
+	cmd := cli.RepairCommand(inFile, outFile, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s %s: %v\n", msg, inFile, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
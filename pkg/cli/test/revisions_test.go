@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestRevisionsCommand(t *testing.T) {
+	msg := "TestRevisionsCommand"
+
+	// A linearized file followed by an incremental update.
+	inFile := filepath.Join(inDir, "WaldenFull.pdf")
+
+	cmd := cli.ListRevisionsCommand(inFile, conf)
+	ss, err := cli.Process(cmd)
+	if err != nil {
+		t.Fatalf("%s list: %v\n", msg, err)
+	}
+	if len(ss) != 4 {
+		t.Fatalf("%s list: got %d lines, want 4\n", msg, len(ss))
+	}
+
+	cmd = cli.DiffRevisionsCommand(inFile, 2, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s diff: %v\n", msg, err)
+	}
+
+	outFile := filepath.Join(outDir, "WaldenRevision1.pdf")
+	cmd = cli.ExtractRevisionCommand(inFile, outFile, 1, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s extract: %v\n", msg, err)
+	}
+
+	if err := validateFile(t, outFile, conf); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+
+	cmd = cli.ExtractRevisionCommand(inFile, outFile, 3, conf)
+	if _, err := cli.Process(cmd); err == nil {
+		t.Fatalf("%s extract: missing revision 3 got extracted\n", msg)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+	Copyright 2023 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/cli"
+)
+
+func TestListStructure(t *testing.T) {
+	msg := "TestListStructure"
+	inFile := filepath.Join(inDir, "Hybrid-PDF.pdf")
+
+	cmd := cli.ListStructureCommand(inFile, conf)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
+
+func TestExportStructure(t *testing.T) {
+	msg := "TestExportStructure"
+	inFile := filepath.Join(inDir, "go.pdf")
+	outFile := filepath.Join(outDir, "go.json")
+
+	cmd := cli.ExportStructureCommand(inFile, outFile, nil)
+	if _, err := cli.Process(cmd); err != nil {
+		t.Fatalf("%s: %v\n", msg, err)
+	}
+}
@@ -29,10 +29,62 @@ type ccittDecode struct {
 	baseFilter
 }
 
+type ccittParms struct {
+	// <0 : Pure two-dimensional encoding (Group 4)
+	// =0 : Pure one-dimensional encoding (Group 3, 1-D)
+	// >0 : Mixed one- and two-dimensional encoding (Group 3, 2-D)
+	k          int
+	cols       int
+	rows       int
+	blackIs1   bool
+	align      bool
+	endOfBlock bool
+}
+
+func (f ccittDecode) ccittParms() ccittParms {
+	p := ccittParms{k: f.parms["K"], cols: 1728, rows: f.parms["Rows"], endOfBlock: true}
+
+	if col, ok := f.parms["Columns"]; ok {
+		p.cols = col
+	}
+
+	p.blackIs1 = f.parms["BlackIs1"] == 1
+	p.align = f.parms["EncodedByteAlign"] == 1
+
+	if v, ok := f.parms["EndOfBlock"]; ok && v == 0 {
+		p.endOfBlock = false
+	}
+
+	return p
+}
+
 // Encode implements encoding for a CCITTDecode filter.
+// r delivers rows of 1 bit samples, each row padded to a byte boundary.
 func (f ccittDecode) Encode(r io.Reader) (io.Reader, error) {
-	// TODO
-	return nil, nil
+	if log.TraceEnabled() {
+		log.Trace.Println("EncodeCCITT begin")
+	}
+
+	p := f.ccittParms()
+	if p.cols <= 0 {
+		return nil, errors.Errorf("pdfcpu: ccitt: invalid DecodeParam \"Columns\": %d", p.cols)
+	}
+
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := encodeCCITT(bb, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if log.TraceEnabled() {
+		log.Trace.Printf("EncodeCCITT: encoded %d bytes.\n", len(data))
+	}
+
+	return bytes.NewBuffer(data), nil
 }
 
 // Decode implements decoding for a CCITTDecode filter.
@@ -45,47 +97,35 @@ func (f ccittDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error)
 		log.Trace.Println("DecodeCCITT begin")
 	}
 
-	var ok bool
+	p := f.ccittParms()
 
-	// <0 : Pure two-dimensional encoding (Group 4)
-	// =0 : Pure one-dimensional encoding (Group 3, 1-D)
-	// >0 : Mixed one- and two-dimensional encoding (Group 3, 2-D)
-	k := 0
-	k, ok = f.parms["K"]
-	if ok && k > 0 {
-		return nil, errors.New("pdfcpu: filter CCITTFax k > 0 currently unsupported")
-	}
-
-	cols := 1728
-	col, ok := f.parms["Columns"]
-	if ok {
-		cols = col
-	}
-
-	rows, ok := f.parms["Rows"]
-	if !ok {
+	if _, ok := f.parms["Rows"]; !ok {
 		return nil, errors.New("pdfcpu: ccitt: missing DecodeParam \"Rows\"")
 	}
 
-	blackIs1 := false
-	v, ok := f.parms["BlackIs1"]
-	if ok && v == 1 {
-		blackIs1 = true
-	}
-
-	encodedByteAlign := false
-	v, ok = f.parms["EncodedByteAlign"]
-	if ok && v == 1 {
-		encodedByteAlign = true
+	if p.k > 0 {
+		// Mixed one- and two-dimensional encoding is not supported by x/image/ccitt.
+		bb, err := getReaderBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		data, err := decodeCCITTGroup3(bb, p)
+		if err != nil {
+			return nil, err
+		}
+		if log.TraceEnabled() {
+			log.Trace.Printf("DecodeCCITT: decoded %d bytes.\n", len(data))
+		}
+		return bytes.NewBuffer(data), nil
 	}
 
-	opts := &ccitt.Options{Invert: blackIs1, Align: encodedByteAlign}
+	opts := &ccitt.Options{Invert: p.blackIs1, Align: p.align}
 
 	mode := ccitt.Group3
-	if k < 0 {
+	if p.k < 0 {
 		mode = ccitt.Group4
 	}
-	rd := ccitt.NewReader(r, ccitt.MSB, mode, cols, rows, opts)
+	rd := ccitt.NewReader(r, ccitt.MSB, mode, p.cols, p.rows, opts)
 
 	var b bytes.Buffer
 	written, err := io.Copy(&b, rd)
@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+)
+
+// ccittEOL is the end-of-line code (T.4 4.1.2).
+var ccittEOL = ccittBits{1, 12}
+
+// ccittVertical maps a1-b1 (offset by 3) to the corresponding vertical mode.
+var ccittVertical = [7]int{ccittVL3, ccittVL2, ccittVL1, ccittV0, ccittVR1, ccittVR2, ccittVR3}
+
+type ccittWriter struct {
+	buf bytes.Buffer
+	acc byte
+	n   int // number of bits in acc
+}
+
+func (w *ccittWriter) write(b ccittBits) {
+	for i := b.n - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | byte(b.code>>uint(i)&1)
+		w.n++
+		if w.n == 8 {
+			w.buf.WriteByte(w.acc)
+			w.acc, w.n = 0, 0
+		}
+	}
+}
+
+// align writes fill bits up to the next byte boundary.
+func (w *ccittWriter) align() {
+	for w.n != 0 {
+		w.write(ccittBits{0, 1})
+	}
+}
+
+func (w *ccittWriter) writeRun(run int, black bool) {
+	enc := ccittWhiteEnc
+	if black {
+		enc = ccittBlackEnc
+	}
+	for run >= 2560 {
+		w.write(enc[2560])
+		run -= 2560
+	}
+	if run >= 64 {
+		w.write(enc[run/64*64])
+		run %= 64
+	}
+	w.write(enc[run])
+}
+
+func (w *ccittWriter) bytes() []byte {
+	w.align()
+	return w.buf.Bytes()
+}
+
+// ccittChange returns the position of the first changing element right of x
+// or len(line) if there is none. The imaginary pixel left of a line is white.
+func ccittChange(line []byte, x int) int {
+	for i := x + 1; i < len(line); i++ {
+		var prev byte
+		if i > 0 {
+			prev = line[i-1]
+		}
+		if line[i] != prev {
+			return i
+		}
+	}
+	return len(line)
+}
+
+// ccittRefChanges returns the changing elements b1 and b2 on the reference line for a0 and its colour.
+func ccittRefChanges(ref []byte, a0 int, color byte) (int, int) {
+	b1 := ccittChange(ref, a0)
+	if b1 < len(ref) && ref[b1] == color {
+		b1 = ccittChange(ref, b1)
+	}
+	return b1, ccittChange(ref, b1)
+}
+
+// encode1D writes cur using modified Huffman coding (T.4 4.1).
+func (w *ccittWriter) encode1D(cur []byte) {
+	var color byte
+	for pos := 0; pos < len(cur); color ^= 1 {
+		end := pos
+		for end < len(cur) && cur[end] == color {
+			end++
+		}
+		w.writeRun(end-pos, color == 1)
+		pos = end
+	}
+}
+
+// encode2D writes cur using modified READ coding relative to ref (T.4 4.2).
+func (w *ccittWriter) encode2D(cur, ref []byte) {
+	var color byte
+	for a0 := -1; a0 < len(cur); {
+		a1 := ccittChange(cur, a0)
+		b1, b2 := ccittRefChanges(ref, a0, color)
+
+		switch {
+
+		case b2 < a1:
+			w.write(ccittModeEnc[ccittPass])
+			a0 = b2
+
+		case a1-b1 >= -3 && a1-b1 <= 3:
+			w.write(ccittModeEnc[ccittVertical[a1-b1+3]])
+			a0 = a1
+			color ^= 1
+
+		default:
+			a2 := ccittChange(cur, a1)
+			w.write(ccittModeEnc[ccittHorizontal])
+			w.writeRun(a1-max(a0, 0), color == 1)
+			w.writeRun(a2-a1, color == 0)
+			a0 = a2
+		}
+	}
+}
+
+// unpackRow expands a packed row into one byte per pixel with 1 meaning black.
+func unpackRow(cur, row []byte, blackIs1 bool) {
+	for x := range cur {
+		b := row[x/8] >> (7 - uint(x%8)) & 1
+		if !blackIs1 {
+			b ^= 1
+		}
+		cur[x] = b
+	}
+}
+
+// packRow is the inverse of unpackRow.
+func packRow(row, cur []byte, blackIs1 bool) {
+	for i := range row {
+		row[i] = 0
+	}
+	for x, b := range cur {
+		if !blackIs1 {
+			b ^= 1
+		}
+		row[x/8] |= b << (7 - uint(x%8))
+	}
+}
+
+// encodeCCITT encodes rows of 1 bit samples as Group 3 (k >= 0) or Group 4 (k < 0) data.
+func encodeCCITT(data []byte, p ccittParms) ([]byte, error) {
+	rowBytes := (p.cols + 7) / 8
+
+	rows := p.rows
+	if rows <= 0 {
+		rows = len(data) / rowBytes
+	}
+	if len(data) < rows*rowBytes {
+		return nil, errors.Errorf("pdfcpu: ccitt: want %d bytes of image data, got %d", rows*rowBytes, len(data))
+	}
+
+	cur, ref := make([]byte, p.cols), make([]byte, p.cols)
+	w := &ccittWriter{}
+
+	for y := 0; y < rows; y++ {
+		unpackRow(cur, data[y*rowBytes:], p.blackIs1)
+
+		if p.k < 0 {
+			if p.align {
+				w.align()
+			}
+			w.encode2D(cur, ref)
+		} else {
+			// Fill bits follow the EOL so that the line begins on a byte boundary.
+			w.write(ccittEOL)
+			oneD := p.k == 0 || y%p.k == 0
+			if p.k > 0 {
+				tag := ccittBits{0, 1}
+				if oneD {
+					tag.code = 1
+				}
+				w.write(tag)
+			}
+			if p.align {
+				w.align()
+			}
+			if oneD {
+				w.encode1D(cur)
+			} else {
+				w.encode2D(cur, ref)
+			}
+		}
+
+		cur, ref = ref, cur
+	}
+
+	if p.endOfBlock {
+		if p.k < 0 {
+			// EOFB
+			if p.align {
+				w.align()
+			}
+			w.write(ccittEOL)
+			w.write(ccittEOL)
+		} else {
+			// RTC
+			for i := 0; i < 6; i++ {
+				w.write(ccittEOL)
+				if p.k > 0 {
+					w.write(ccittBits{1, 1})
+				}
+			}
+		}
+	}
+
+	return w.bytes(), nil
+}
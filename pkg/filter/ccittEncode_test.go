@@ -0,0 +1,268 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"golang.org/x/image/ccitt"
+)
+
+// bilevelTestImage returns packed rows with shapes, noise, blank rows and runs exceeding 2560 pixels.
+func bilevelTestImage(w, h int) []byte {
+	rowBytes := (w + 7) / 8
+	bb := make([]byte, rowBytes*h)
+	rnd := rand.New(rand.NewSource(1))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var black bool
+			switch {
+			case y == 0:
+			case y == 1:
+				black = true
+			case y%7 == 0:
+				black = rnd.Intn(2) == 0
+			default:
+				dx, dy := x-w/3, y-h/2
+				black = dx*dx+dy*dy*16 < h*h*4 || (x/5+y)%11 == 0 && x > w-300
+			}
+			if !black {
+				bb[y*rowBytes+x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+	}
+	return bb
+}
+
+func TestCCITTEncodeDecode(t *testing.T) {
+	w, h := 3001, 60
+	want := bilevelTestImage(w, h)
+
+	for _, k := range []int{-1, 0, 1, 4} {
+		for _, blackIs1 := range []bool{false, true} {
+			for _, align := range []bool{false, true} {
+				for _, eob := range []bool{true, false} {
+					t.Run(fmt.Sprintf("K%d_BlackIs1=%t_Align=%t_EOB=%t", k, blackIs1, align, eob), func(t *testing.T) {
+						parms := map[string]int{"K": k, "Columns": w, "Rows": h}
+						raw := want
+						if blackIs1 {
+							parms["BlackIs1"] = 1
+							raw = make([]byte, len(want))
+							rowBytes := (w + 7) / 8
+							for i, b := range want {
+								raw[i] = ^b
+								if i%rowBytes == rowBytes-1 {
+									// Keep the padding bits clear.
+									raw[i] &= 0xFF << uint(rowBytes*8-w)
+								}
+							}
+						}
+						if align {
+							parms["EncodedByteAlign"] = 1
+						}
+						if !eob {
+							parms["EndOfBlock"] = 0
+						}
+
+						f := ccittDecode{baseFilter{parms}}
+
+						enc, err := f.Encode(bytes.NewReader(raw))
+						if err != nil {
+							t.Fatalf("encode: %v\n", err)
+						}
+						encoded, _ := getReaderBytes(enc)
+						if len(encoded) >= len(raw) {
+							t.Errorf("no compression: %d >= %d\n", len(encoded), len(raw))
+						}
+
+						dec, err := f.Decode(bytes.NewReader(encoded))
+						if err != nil {
+							t.Fatalf("decode: %v\n", err)
+						}
+						got, err := io.ReadAll(dec)
+						if err != nil {
+							t.Fatalf("%v\n", err)
+						}
+						compare(t, got, raw)
+					})
+				}
+			}
+		}
+	}
+}
+
+// gopherRows returns the rows of testdata/bw-gopher.png packed with 0 meaning black.
+func gopherRows(t *testing.T) ([]byte, int, int) {
+	t.Helper()
+	f, err := os.Open("testdata/bw-gopher.png")
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	rowBytes := (w + 7) / 8
+	bb := make([]byte, rowBytes*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if c := color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray); c.Y >= 0x80 {
+				bb[y*rowBytes+x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+	}
+	return bb, w, h
+}
+
+// withTagBits converts one-dimensional Group 3 data into K > 0 data by tagging each EOL as followed by a 1-D line.
+func withTagBits(bb []byte) []byte {
+	w := &ccittWriter{}
+	zeros := 0
+	for i := 0; i < len(bb)*8; i++ {
+		bit := bb[i/8] >> (7 - uint(i%8)) & 1
+		w.write(ccittBits{uint32(bit), 1})
+		if bit == 0 {
+			zeros++
+			continue
+		}
+		if zeros >= 11 {
+			w.write(ccittBits{1, 1})
+		}
+		zeros = 0
+	}
+	return w.bytes()
+}
+
+func TestCCITTFixtures(t *testing.T) {
+	want, w, h := gopherRows(t)
+
+	decode := func(t *testing.T, f ccittDecode, encoded []byte) {
+		t.Helper()
+		dec, err := f.Decode(bytes.NewReader(encoded))
+		if err != nil {
+			t.Fatalf("decode: %v\n", err)
+		}
+		got, err := io.ReadAll(dec)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		compare(t, got, want)
+	}
+
+	// Decode known-good data.
+	for _, tt := range []struct {
+		fileName string
+		k        int
+	}{
+		{"testdata/bw-gopher.ccitt_group3", 0},
+		{"testdata/bw-gopher.ccitt_group4", -1},
+	} {
+		t.Run(tt.fileName, func(t *testing.T) {
+			bb, err := os.ReadFile(tt.fileName)
+			if err != nil {
+				t.Fatalf("%v\n", err)
+			}
+			f := ccittDecode{baseFilter{map[string]int{"K": tt.k, "Columns": w, "Rows": h}}}
+			decode(t, f, bb)
+			if tt.k == 0 {
+				f.parms["K"] = 1
+				decode(t, f, withTagBits(bb))
+			}
+		})
+	}
+
+	// Check the encoder against the x/image/ccitt decoder.
+	for _, k := range []int{-1, 0} {
+		for _, align := range []bool{false, true} {
+			t.Run(fmt.Sprintf("xImage_K%d_Align=%t", k, align), func(t *testing.T) {
+				p := ccittParms{k: k, cols: w, rows: h, align: align, endOfBlock: true}
+				encoded, err := encodeCCITT(want, p)
+				if err != nil {
+					t.Fatalf("encode: %v\n", err)
+				}
+				mode := ccitt.Group3
+				if k < 0 {
+					mode = ccitt.Group4
+				}
+				got, err := io.ReadAll(ccitt.NewReader(bytes.NewReader(encoded), ccitt.MSB, mode, w, h, &ccitt.Options{Align: align}))
+				if err != nil {
+					t.Fatalf("decode: %v\n", err)
+				}
+				compare(t, got, want)
+			})
+		}
+	}
+}
+
+func TestDCTEncode(t *testing.T) {
+	w, h := 64, 48
+
+	for _, colors := range []int{1, 3} {
+		raw := make([]byte, w*h*colors)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				for c := 0; c < colors; c++ {
+					raw[(y*w+x)*colors+c] = byte(x*4 + c*40)
+				}
+			}
+		}
+
+		var sizes []int
+		for _, q := range []int{10, 95} {
+			f := dctDecode{baseFilter{map[string]int{"Columns": w, "Rows": h, "Colors": colors, "Quality": q}}}
+			r, err := f.Encode(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("encode: %v\n", err)
+			}
+			bb, _ := getReaderBytes(r)
+			sizes = append(sizes, len(bb))
+
+			img, err := jpeg.Decode(bytes.NewReader(bb))
+			if err != nil {
+				t.Fatalf("decode: %v\n", err)
+			}
+			if img.Bounds() != image.Rect(0, 0, w, h) {
+				t.Fatalf("unexpected bounds: %v\n", img.Bounds())
+			}
+			r1, _, _, _ := img.At(w/2, h/2).RGBA()
+			if d := int(r1>>8) - int(raw[(h/2*w+w/2)*colors]); d < -16 || d > 16 {
+				t.Errorf("colors=%d quality=%d: unexpected sample deviation %d\n", colors, q, d)
+			}
+		}
+
+		if sizes[0] >= sizes[1] {
+			t.Errorf("colors=%d: quality has no effect on size: %v\n", colors, sizes)
+		}
+	}
+
+	f := dctDecode{baseFilter{map[string]int{"Columns": w, "Rows": h, "Colors": 4}}}
+	if _, err := f.Encode(bytes.NewReader(make([]byte, w*h*4))); err == nil {
+		t.Error("want error for CMYK\n")
+	}
+}
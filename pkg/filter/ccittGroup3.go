@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var errCCITTCode = errors.New("pdfcpu: ccitt: invalid code")
+
+// ccittVerticalDelta maps vertical modes to a1-b1.
+var ccittVerticalDelta = map[int]int{
+	ccittV0:  0,
+	ccittVR1: 1,
+	ccittVR2: 2,
+	ccittVR3: 3,
+	ccittVL1: -1,
+	ccittVL2: -2,
+	ccittVL3: -3,
+}
+
+type ccittReader struct {
+	data []byte
+	pos  int // bit position
+}
+
+func (r *ccittReader) bit() (uint32, bool) {
+	if r.pos >= len(r.data)*8 {
+		return 0, false
+	}
+	b := r.data[r.pos/8] >> (7 - uint(r.pos%8)) & 1
+	r.pos++
+	return uint32(b), true
+}
+
+func (r *ccittReader) align() {
+	r.pos = (r.pos + 7) &^ 7
+}
+
+func (r *ccittReader) eof() bool {
+	return r.pos >= len(r.data)*8
+}
+
+// decode reads the next code of dec.
+func (r *ccittReader) decode(dec map[ccittBits]int) (int, error) {
+	var b ccittBits
+	for b.n < 13 {
+		bit, ok := r.bit()
+		if !ok {
+			return 0, io.ErrUnexpectedEOF
+		}
+		b.code = b.code<<1 | bit
+		b.n++
+		if v, ok := dec[b]; ok {
+			return v, nil
+		}
+	}
+	return 0, errCCITTCode
+}
+
+// run reads a run length consisting of optional make-up codes and a terminating code.
+func (r *ccittReader) run(black bool) (int, error) {
+	dec := ccittWhiteDec
+	if black {
+		dec = ccittBlackDec
+	}
+	n := 0
+	for {
+		v, err := r.decode(dec)
+		if err != nil {
+			return 0, err
+		}
+		n += v
+		if v < 64 {
+			return n, nil
+		}
+	}
+}
+
+// skipEOL consumes fill bits followed by an EOL and reports whether there was one.
+func (r *ccittReader) skipEOL() bool {
+	pos, zeros := r.pos, 0
+	for {
+		b, ok := r.bit()
+		if !ok {
+			break
+		}
+		if b == 1 {
+			if zeros >= 11 {
+				return true
+			}
+			break
+		}
+		zeros++
+	}
+	r.pos = pos
+	return false
+}
+
+func fill(line []byte, from, to int, color byte) {
+	for i := from; i < to; i++ {
+		line[i] = color
+	}
+}
+
+func (r *ccittReader) decode1D(cur []byte) error {
+	var color byte
+	for pos := 0; pos < len(cur); color ^= 1 {
+		n, err := r.run(color == 1)
+		if err != nil {
+			return err
+		}
+		if pos+n > len(cur) {
+			return errors.New("pdfcpu: ccitt: run exceeds line width")
+		}
+		fill(cur, pos, pos+n, color)
+		pos += n
+	}
+	return nil
+}
+
+func (r *ccittReader) decode2D(cur, ref []byte) error {
+	var color byte
+	for a0 := -1; a0 < len(cur); {
+		mode, err := r.decode(ccittModeDec)
+		if err != nil {
+			return err
+		}
+
+		b1, b2 := ccittRefChanges(ref, a0, color)
+		start := max(a0, 0)
+
+		switch mode {
+
+		case ccittPass:
+			fill(cur, start, b2, color)
+			a0 = b2
+
+		case ccittHorizontal:
+			n1, err := r.run(color == 1)
+			if err != nil {
+				return err
+			}
+			n2, err := r.run(color == 0)
+			if err != nil {
+				return err
+			}
+			a1, a2 := start+n1, start+n1+n2
+			if a2 > len(cur) {
+				return errors.New("pdfcpu: ccitt: run exceeds line width")
+			}
+			fill(cur, start, a1, color)
+			fill(cur, a1, a2, color^1)
+			a0 = a2
+
+		case ccittExt:
+			return errors.New("pdfcpu: ccitt: extension codes unsupported")
+
+		default:
+			a1 := b1 + ccittVerticalDelta[mode]
+			if a1 < start || a1 > len(cur) {
+				return errCCITTCode
+			}
+			fill(cur, start, a1, color)
+			a0 = a1
+			color ^= 1
+		}
+	}
+	return nil
+}
+
+// decodeCCITTGroup3 decodes mixed one- and two-dimensional (k > 0) Group 3 data.
+// Decoding stops after p.rows rows, at RTC or at the end of data.
+func decodeCCITTGroup3(data []byte, p ccittParms) ([]byte, error) {
+	r := &ccittReader{data: data}
+
+	row := make([]byte, (p.cols+7)/8)
+	cur, ref := make([]byte, p.cols), make([]byte, p.cols)
+
+	var b bytes.Buffer
+
+	for y := 0; p.rows <= 0 || y < p.rows; y++ {
+		eol := r.skipEOL()
+
+		twoD := false
+		if p.k > 0 {
+			tag, ok := r.bit()
+			if !ok {
+				break
+			}
+			twoD = tag == 0
+		}
+
+		if eol && p.rows <= 0 && r.skipEOL() {
+			// RTC, fill bits and leading zeros of the line may look alike.
+			break
+		}
+
+		if p.align {
+			// Fill bits preceding the line.
+			r.align()
+		}
+
+		if r.eof() {
+			break
+		}
+
+		var err error
+		if twoD {
+			err = r.decode2D(cur, ref)
+		} else {
+			err = r.decode1D(cur)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		packRow(row, cur, p.blackIs1)
+		b.Write(row)
+
+		cur, ref = ref, cur
+	}
+
+	return b.Bytes(), nil
+}
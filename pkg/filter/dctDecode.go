@@ -19,8 +19,11 @@ package filter
 import (
 	"bytes"
 	"encoding/gob"
+	"image"
 	"image/jpeg"
 	"io"
+
+	"github.com/pkg/errors"
 )
 
 type dctDecode struct {
@@ -28,9 +31,56 @@ type dctDecode struct {
 }
 
 // Encode implements encoding for a DCTDecode filter.
+// r delivers interleaved 8 bit samples of an image with "Columns" x "Rows" pixels and 1 (gray) or 3 (RGB) "Colors".
+// The optional "Quality" ranges from 1 to 100 and defaults to 75.
 func (f dctDecode) Encode(r io.Reader) (io.Reader, error) {
+	w, h, colors := f.parms["Columns"], f.parms["Rows"], f.parms["Colors"]
+	if w <= 0 || h <= 0 {
+		return nil, errors.Errorf("pdfcpu: DCT: invalid image dimensions %dx%d", w, h)
+	}
+
+	if bpc, ok := f.parms["BitsPerComponent"]; ok && bpc != 8 {
+		return nil, errors.Errorf("pdfcpu: DCT: unsupported BitsPerComponent: %d", bpc)
+	}
+
+	q := jpeg.DefaultQuality
+	if v, ok := f.parms["Quality"]; ok && v > 0 {
+		q = min(v, 100)
+	}
+
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bb) < w*h*colors {
+		return nil, errors.Errorf("pdfcpu: DCT: want %d bytes of image data, got %d", w*h*colors, len(bb))
+	}
 
-	return nil, nil
+	var img image.Image
+
+	switch colors {
+
+	case 1:
+		img = &image.Gray{Pix: bb[:w*h], Stride: w, Rect: image.Rect(0, 0, w, h)}
+
+	case 3:
+		im := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i, j := 0, 0; i < w*h*3; i, j = i+3, j+4 {
+			im.Pix[j], im.Pix[j+1], im.Pix[j+2], im.Pix[j+3] = bb[i], bb[i+1], bb[i+2], 0xFF
+		}
+		img = im
+
+	default:
+		return nil, errors.Errorf("pdfcpu: DCT: unsupported number of colors: %d", colors)
+	}
+
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, &jpeg.Options{Quality: q}); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
 }
 
 // Decode implements decoding for a DCTDecode filter.
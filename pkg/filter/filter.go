@@ -21,7 +21,6 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/pdfcpu/pdfcpu/pkg/log"
 	"github.com/pkg/errors"
 )
 
@@ -51,8 +50,11 @@ type Filter interface {
 	DecodeLength(r io.Reader, maxLen int64) (io.Reader, error)
 }
 
+// Option configures a filter returned by NewFilter.
+type Option func(Filter) Filter
+
 // NewFilter returns a filter for given filterName and an optional parameter dictionary.
-func NewFilter(filterName string, parms map[string]int) (filter Filter, err error) {
+func NewFilter(filterName string, parms map[string]int, opts ...Option) (filter Filter, err error) {
 	switch filterName {
 
 	case ASCII85:
@@ -77,20 +79,21 @@ func NewFilter(filterName string, parms map[string]int) (filter Filter, err erro
 		filter = dctDecode{baseFilter{parms}}
 
 	case JBIG2:
-		// Unsupported
-		fallthrough
+		filter = jbig2Decode{baseFilter{parms}, nil}
 
 	case JPX:
-		// Unsupported
-		if log.InfoEnabled() {
-			log.Info.Printf("Filter not supported: <%s>", filterName)
-		}
-		err = ErrUnsupportedFilter
+		filter = jpxDecode{baseFilter{parms}}
 
 	default:
 		err = errors.Errorf("Invalid filter: <%s>", filterName)
 	}
 
+	if err == nil {
+		for _, opt := range opts {
+			filter = opt(filter)
+		}
+	}
+
 	return filter, err
 }
 
@@ -105,7 +108,7 @@ type baseFilter struct {
 }
 
 func SupportsDecodeParms(f string) bool {
-	return f == CCITTFax || f == LZW || f == Flate
+	return f == CCITTFax || f == LZW || f == Flate || f == JBIG2
 }
 
 func getReaderBytes(r io.Reader) ([]byte, error) {
@@ -38,8 +38,8 @@ func TestFilterSupport(t *testing.T) {
 		{filter.Flate, nil},
 		{filter.CCITTFax, nil},
 		{filter.DCT, nil},
-		{filter.JBIG2, filter.ErrUnsupportedFilter},
-		{filter.JPX, filter.ErrUnsupportedFilter},
+		{filter.JBIG2, nil},
+		{filter.JPX, nil},
 		{"INVALID_FILTER", errors.New("Invalid filter: <INVALID_FILTER>")},
 	}
 	for _, tt := range filtersTests {
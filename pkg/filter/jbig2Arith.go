@@ -0,0 +1,271 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+// MQ arithmetic decoder as defined in ITU-T T.88 Annex E.
+// The JPEG 2000 entropy decoder (ITU-T T.800 Annex C) uses the same coder.
+
+type qe struct {
+	qe         uint32
+	nmps, nlps uint8
+	switchFlag bool
+}
+
+// Table E.1 - Qe values and probability estimation.
+var qeTable = [47]qe{
+	{0x5601, 1, 1, true},
+	{0x3401, 2, 6, false},
+	{0x1801, 3, 9, false},
+	{0x0AC1, 4, 12, false},
+	{0x0521, 5, 29, false},
+	{0x0221, 38, 33, false},
+	{0x5601, 7, 6, true},
+	{0x5401, 8, 14, false},
+	{0x4801, 9, 14, false},
+	{0x3801, 10, 14, false},
+	{0x3001, 11, 17, false},
+	{0x2401, 12, 18, false},
+	{0x1C01, 13, 20, false},
+	{0x1601, 29, 21, false},
+	{0x5601, 15, 14, true},
+	{0x5401, 16, 14, false},
+	{0x5101, 17, 15, false},
+	{0x4801, 18, 16, false},
+	{0x3801, 19, 17, false},
+	{0x3401, 20, 18, false},
+	{0x3001, 21, 19, false},
+	{0x2801, 22, 19, false},
+	{0x2401, 23, 20, false},
+	{0x2201, 24, 21, false},
+	{0x1C01, 25, 22, false},
+	{0x1801, 26, 23, false},
+	{0x1601, 27, 24, false},
+	{0x1401, 28, 25, false},
+	{0x1201, 29, 26, false},
+	{0x1101, 30, 27, false},
+	{0x0AC1, 31, 28, false},
+	{0x09C1, 32, 29, false},
+	{0x08A1, 33, 30, false},
+	{0x0521, 34, 31, false},
+	{0x0441, 35, 32, false},
+	{0x02A1, 36, 33, false},
+	{0x0221, 37, 34, false},
+	{0x0141, 38, 35, false},
+	{0x0111, 39, 36, false},
+	{0x0085, 40, 37, false},
+	{0x0049, 41, 38, false},
+	{0x0025, 42, 39, false},
+	{0x0015, 43, 40, false},
+	{0x0009, 44, 41, false},
+	{0x0005, 45, 42, false},
+	{0x0001, 45, 43, false},
+	{0x5601, 46, 46, false},
+}
+
+// mqContexts holds adaptive probability states: index<<1 | mps.
+type mqContexts []uint8
+
+type mqDecoder struct {
+	data        []byte
+	bp          int
+	end         int
+	chigh, clow uint32
+	ct          int
+	a           uint32
+}
+
+func newMQDecoder(data []byte, start, end int) *mqDecoder {
+	if end > len(data) {
+		end = len(data)
+	}
+	d := &mqDecoder{data: data, bp: start, end: end}
+	d.chigh = uint32(d.byteAt(start))
+	d.byteIn()
+	d.chigh = ((d.chigh << 7) & 0xFFFF) | ((d.clow >> 9) & 0x7F)
+	d.clow = (d.clow << 7) & 0xFFFF
+	d.ct -= 7
+	d.a = 0x8000
+	return d
+}
+
+func (d *mqDecoder) byteAt(i int) byte {
+	if i < d.end {
+		return d.data[i]
+	}
+	return 0xFF
+}
+
+// byteIn implements the BYTEIN procedure (Figure E.19).
+func (d *mqDecoder) byteIn() {
+	if d.byteAt(d.bp) == 0xFF {
+		if d.byteAt(d.bp+1) > 0x8F {
+			d.clow += 0xFF00
+			d.ct = 8
+		} else {
+			d.bp++
+			d.clow += uint32(d.byteAt(d.bp)) << 9
+			d.ct = 7
+		}
+	} else {
+		d.bp++
+		d.clow += uint32(d.byteAt(d.bp)) << 8
+		d.ct = 8
+	}
+	if d.clow > 0xFFFF {
+		d.chigh += d.clow >> 16
+		d.clow &= 0xFFFF
+	}
+}
+
+// readBit implements the DECODE procedure (Figure E.15) for context cx.
+func (d *mqDecoder) readBit(cx mqContexts, pos int) int {
+	index := cx[pos] >> 1
+	mps := int(cx[pos] & 1)
+	q := qeTable[index]
+
+	var bit int
+	a := d.a - q.qe
+
+	if d.chigh < q.qe {
+		// LPS_EXCHANGE
+		if a < q.qe {
+			a = q.qe
+			bit = mps
+			index = q.nmps
+		} else {
+			a = q.qe
+			bit = 1 ^ mps
+			if q.switchFlag {
+				mps = bit
+			}
+			index = q.nlps
+		}
+	} else {
+		d.chigh -= q.qe
+		if a&0x8000 != 0 {
+			d.a = a
+			return mps
+		}
+		// MPS_EXCHANGE
+		if a < q.qe {
+			bit = 1 ^ mps
+			if q.switchFlag {
+				mps = bit
+			}
+			index = q.nlps
+		} else {
+			bit = mps
+			index = q.nmps
+		}
+	}
+
+	// RENORMD
+	for {
+		if d.ct == 0 {
+			d.byteIn()
+		}
+		a <<= 1
+		d.chigh = ((d.chigh << 1) & 0xFFFF) | ((d.clow >> 15) & 1)
+		d.clow = (d.clow << 1) & 0xFFFF
+		d.ct--
+		if a&0x8000 != 0 {
+			break
+		}
+	}
+
+	d.a = a
+	cx[pos] = index<<1 | uint8(mps)
+	return bit
+}
+
+// jbig2Context bundles an arithmetic decoder with its named context tables.
+type jbig2Context struct {
+	decoder  *mqDecoder
+	contexts map[string]mqContexts
+}
+
+func newJBIG2Context(data []byte, start, end int) *jbig2Context {
+	return &jbig2Context{
+		decoder:  newMQDecoder(data, start, end),
+		contexts: map[string]mqContexts{},
+	}
+}
+
+func (c *jbig2Context) cx(name string, size int) mqContexts {
+	cx, ok := c.contexts[name]
+	if !ok {
+		cx = make(mqContexts, size)
+		c.contexts[name] = cx
+	}
+	return cx
+}
+
+// decodeInteger implements the integer arithmetic decoding procedure (A.2).
+// ok is false for the out-of-band value OOB.
+func (c *jbig2Context) decodeInteger(proc string) (v int, ok bool) {
+	cx := c.cx(proc, 512)
+	prev := 1
+
+	readBits := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			bit := c.decoder.readBit(cx, prev)
+			if prev < 256 {
+				prev = prev<<1 | bit
+			} else {
+				prev = (prev<<1|bit)&511 | 256
+			}
+			v = v<<1 | bit
+		}
+		return v
+	}
+
+	sign := readBits(1)
+
+	switch {
+	case readBits(1) == 0:
+		v = readBits(2)
+	case readBits(1) == 0:
+		v = readBits(4) + 4
+	case readBits(1) == 0:
+		v = readBits(6) + 20
+	case readBits(1) == 0:
+		v = readBits(8) + 84
+	case readBits(1) == 0:
+		v = readBits(12) + 340
+	default:
+		v = readBits(32) + 4436
+	}
+
+	if sign == 0 {
+		return v, true
+	}
+	if v > 0 {
+		return -v, true
+	}
+	return 0, false
+}
+
+// decodeIAID implements the IAID decoding procedure (A.3).
+func (c *jbig2Context) decodeIAID(codeLen int) int {
+	cx := c.cx("IAID", 1<<uint(codeLen+1))
+	prev := 1
+	for i := 0; i < codeLen; i++ {
+		prev = prev<<1 | c.decoder.readBit(cx, prev)
+	}
+	return prev - 1<<uint(codeLen)
+}
@@ -0,0 +1,797 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// ErrJBIG2Huffman signals a JBIG2 stream using Huffman coded symbols or text regions.
+var ErrJBIG2Huffman = errors.New("pdfcpu: jbig2: huffman coding not supported")
+
+type jbig2Decode struct {
+	baseFilter
+	globals []byte
+}
+
+// JBIG2Globals returns an option providing a JBIG2Decode filter with the global segments of a JBIG2Globals stream.
+func JBIG2Globals(globals []byte) Option {
+	return func(f Filter) Filter {
+		if f1, ok := f.(jbig2Decode); ok {
+			f1.globals = globals
+			return f1
+		}
+		return f
+	}
+}
+
+// Encode implements encoding for a JBIG2Decode filter.
+func (f jbig2Decode) Encode(r io.Reader) (io.Reader, error) {
+	return nil, errors.New("pdfcpu: JBIG2Decode: encoding not supported")
+}
+
+// Decode implements decoding for a JBIG2Decode filter.
+func (f jbig2Decode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes the first page of an embedded JBIG2 stream (7.4.7)
+// into 1 bit per pixel rows where 0 represents black.
+func (f jbig2Decode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	if log.TraceEnabled() {
+		log.Trace.Println("DecodeJBIG2 begin")
+	}
+
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := &jbig2Decoder{segments: map[uint32]*jbig2Segment{}}
+
+	if len(f.globals) > 0 {
+		if err := dec.decodeSegments(f.globals); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: jbig2: globals")
+		}
+	}
+
+	if err := dec.decodeSegments(bb); err != nil {
+		return nil, err
+	}
+
+	if dec.page == nil {
+		return nil, errors.New("pdfcpu: jbig2: missing page information")
+	}
+
+	b := dec.page.bytes()
+
+	if log.TraceEnabled() {
+		log.Trace.Printf("DecodeJBIG2: decoded %d bytes.\n", len(b))
+	}
+
+	return bytes.NewBuffer(b), nil
+}
+
+// JBIG2 segment types (7.3).
+const (
+	jbig2SymbolDictionary             = 0
+	jbig2IntermediateTextRegion       = 4
+	jbig2ImmediateTextRegion          = 6
+	jbig2ImmediateLosslessTextRegion  = 7
+	jbig2PatternDictionary            = 16
+	jbig2IntermediateHalftoneRegion   = 20
+	jbig2ImmediateHalftoneRegion      = 22
+	jbig2ImmediateLosslessHalftone    = 23
+	jbig2IntermediateGenericRegion    = 36
+	jbig2ImmediateGenericRegion       = 38
+	jbig2ImmediateLosslessGeneric     = 39
+	jbig2IntermediateRefinementRegion = 40
+	jbig2ImmediateRefinementRegion    = 42
+	jbig2ImmediateLosslessRefinement  = 43
+	jbig2PageInformation              = 48
+	jbig2EndOfPage                    = 49
+	jbig2EndOfStripe                  = 50
+	jbig2EndOfFile                    = 51
+	jbig2Profiles                     = 52
+	jbig2Tables                       = 53
+	jbig2Extension                    = 62
+)
+
+type jbig2Segment struct {
+	number   uint32
+	typ      int
+	referred []uint32
+	page     uint32
+	data     []byte
+
+	// Results kept for referring segments.
+	symbols  []*jbig2Bitmap
+	patterns []*jbig2Bitmap
+	region   *jbig2Bitmap
+}
+
+type jbig2RegionInfo struct {
+	w, h, x, y int
+	op         int
+}
+
+type jbig2Decoder struct {
+	segments     map[uint32]*jbig2Segment
+	page         *jbig2Bitmap
+	pageNr       uint32
+	defaultPixel byte
+	striped      bool
+}
+
+type jbig2Reader struct {
+	bb  []byte
+	pos int
+}
+
+func (r *jbig2Reader) need(n int) error {
+	if n < 0 || r.pos+n > len(r.bb) {
+		return errors.New("pdfcpu: jbig2: unexpected end of data")
+	}
+	return nil
+}
+
+func (r *jbig2Reader) u8() (byte, error) {
+	if err := r.need(1); err != nil {
+		return 0, err
+	}
+	b := r.bb[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *jbig2Reader) i8() (int, error) {
+	b, err := r.u8()
+	return int(int8(b)), err
+}
+
+func (r *jbig2Reader) u16() (int, error) {
+	if err := r.need(2); err != nil {
+		return 0, err
+	}
+	v := binary.BigEndian.Uint16(r.bb[r.pos:])
+	r.pos += 2
+	return int(v), nil
+}
+
+func (r *jbig2Reader) u32() (uint32, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	v := binary.BigEndian.Uint32(r.bb[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *jbig2Reader) i32() (int, error) {
+	v, err := r.u32()
+	return int(int32(v)), err
+}
+
+func (r *jbig2Reader) atPixels(n int) ([]atPixel, error) {
+	at := make([]atPixel, n)
+	for i := range at {
+		x, err := r.i8()
+		if err != nil {
+			return nil, err
+		}
+		y, err := r.i8()
+		if err != nil {
+			return nil, err
+		}
+		at[i] = atPixel{x, y}
+	}
+	return at, nil
+}
+
+func (r *jbig2Reader) regionInfo() (*jbig2RegionInfo, error) {
+	var v [4]uint32
+	for i := range v {
+		u, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		v[i] = u
+	}
+	flags, err := r.u8()
+	if err != nil {
+		return nil, err
+	}
+	if v[0] > 1<<24 || v[1] > 1<<24 {
+		return nil, errors.Errorf("pdfcpu: jbig2: region too large: %dx%d", v[0], v[1])
+	}
+	return &jbig2RegionInfo{w: int(v[0]), h: int(v[1]), x: int(int32(v[2])), y: int(int32(v[3])), op: int(flags & 0x07)}, nil
+}
+
+// segmentHeader parses a segment header (7.2).
+func (r *jbig2Reader) segmentHeader() (*jbig2Segment, uint32, error) {
+	nr, err := r.u32()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	flags, err := r.u8()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seg := &jbig2Segment{number: nr, typ: int(flags & 0x3F)}
+
+	b, err := r.u8()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := int(b >> 5)
+	if count == 7 {
+		r.pos--
+		u, err := r.u32()
+		if err != nil {
+			return nil, 0, err
+		}
+		count = int(u & 0x1FFFFFFF)
+		if err := r.need((count + 8) >> 3); err != nil {
+			return nil, 0, err
+		}
+		r.pos += (count + 8) >> 3
+	}
+
+	size := 4
+	if nr <= 256 {
+		size = 1
+	} else if nr <= 65536 {
+		size = 2
+	}
+
+	if err := r.need(count * size); err != nil {
+		return nil, 0, err
+	}
+
+	for i := 0; i < count; i++ {
+		var ref uint32
+		switch size {
+		case 1:
+			v, _ := r.u8()
+			ref = uint32(v)
+		case 2:
+			v, _ := r.u16()
+			ref = uint32(v)
+		default:
+			ref, _ = r.u32()
+		}
+		seg.referred = append(seg.referred, ref)
+	}
+
+	if flags&0x40 != 0 {
+		if seg.page, err = r.u32(); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		v, err := r.u8()
+		if err != nil {
+			return nil, 0, err
+		}
+		seg.page = uint32(v)
+	}
+
+	length, err := r.u32()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return seg, length, nil
+}
+
+// unknownLength determines the data length of an immediate generic region segment
+// of unknown length by searching for its end sequence (7.2.7).
+func (r *jbig2Reader) unknownLength() (uint32, error) {
+	const infoLen = 17
+	if err := r.need(infoLen + 1); err != nil {
+		return 0, err
+	}
+
+	h := r.bb[r.pos+4 : r.pos+8]
+	mmr := r.bb[r.pos+infoLen]&0x01 != 0
+
+	pattern := []byte{0xFF, 0xAC, h[0], h[1], h[2], h[3]}
+	if mmr {
+		pattern[0], pattern[1] = 0x00, 0x00
+	}
+
+	i := bytes.Index(r.bb[r.pos+infoLen+1:], pattern)
+	if i < 0 {
+		return 0, errors.New("pdfcpu: jbig2: missing end of generic region")
+	}
+
+	return uint32(infoLen + 1 + i + len(pattern)), nil
+}
+
+// decodeSegments processes a sequence of segments using the embedded organization (Annex D.3).
+func (dec *jbig2Decoder) decodeSegments(bb []byte) error {
+	r := &jbig2Reader{bb: bb}
+
+	for r.pos < len(bb) {
+		seg, length, err := r.segmentHeader()
+		if err != nil {
+			return err
+		}
+
+		if length == 0xFFFFFFFF {
+			if seg.typ != jbig2ImmediateGenericRegion {
+				return errors.Errorf("pdfcpu: jbig2: segment %d: unknown data length", seg.number)
+			}
+			if length, err = r.unknownLength(); err != nil {
+				return err
+			}
+		}
+
+		if err := r.need(int(length)); err != nil {
+			return err
+		}
+		seg.data = bb[r.pos : r.pos+int(length)]
+		r.pos += int(length)
+
+		if err := dec.decodeSegment(seg); err != nil {
+			return err
+		}
+
+		dec.segments[seg.number] = seg
+
+		if seg.typ == jbig2EndOfFile {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (dec *jbig2Decoder) referred(seg *jbig2Segment, typ int) []*jbig2Segment {
+	var ss []*jbig2Segment
+	for _, nr := range seg.referred {
+		if s, ok := dec.segments[nr]; ok && s.typ == typ {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+func (dec *jbig2Decoder) decodeSegment(seg *jbig2Segment) error {
+	if dec.page != nil && seg.page != 0 && seg.page != dec.pageNr {
+		// PDF streams contain a single page, ignore anything else.
+		return nil
+	}
+
+	r := &jbig2Reader{bb: seg.data}
+
+	switch seg.typ {
+
+	case jbig2SymbolDictionary:
+		return dec.decodeSymbolDictSegment(seg, r)
+
+	case jbig2IntermediateTextRegion, jbig2ImmediateTextRegion, jbig2ImmediateLosslessTextRegion:
+		return dec.decodeTextRegionSegment(seg, r)
+
+	case jbig2PatternDictionary:
+		return dec.decodePatternDictSegment(seg, r)
+
+	case jbig2IntermediateHalftoneRegion, jbig2ImmediateHalftoneRegion, jbig2ImmediateLosslessHalftone:
+		return dec.decodeHalftoneRegionSegment(seg, r)
+
+	case jbig2IntermediateGenericRegion, jbig2ImmediateGenericRegion, jbig2ImmediateLosslessGeneric:
+		return dec.decodeGenericRegionSegment(seg, r)
+
+	case jbig2IntermediateRefinementRegion, jbig2ImmediateRefinementRegion, jbig2ImmediateLosslessRefinement:
+		return dec.decodeRefinementRegionSegment(seg, r)
+
+	case jbig2PageInformation:
+		return dec.decodePageInfo(seg, r)
+
+	case jbig2EndOfStripe:
+		return dec.decodeEndOfStripe(r)
+
+	case jbig2Tables:
+		// Only used by Huffman coding.
+		return nil
+
+	case jbig2EndOfPage, jbig2EndOfFile, jbig2Profiles, jbig2Extension:
+		return nil
+	}
+
+	if log.DebugEnabled() {
+		log.Debug.Printf("DecodeJBIG2: ignoring segment type %d\n", seg.typ)
+	}
+
+	return nil
+}
+
+// decodePageInfo parses a page information segment (7.4.8).
+func (dec *jbig2Decoder) decodePageInfo(seg *jbig2Segment, r *jbig2Reader) error {
+	w, err := r.u32()
+	if err != nil {
+		return err
+	}
+	h, err := r.u32()
+	if err != nil {
+		return err
+	}
+	// Skip resolution.
+	if err := r.need(8); err != nil {
+		return err
+	}
+	r.pos += 8
+
+	flags, err := r.u8()
+	if err != nil {
+		return err
+	}
+
+	if w > 1<<24 || (h != 0xFFFFFFFF && h > 1<<24) {
+		return errors.Errorf("pdfcpu: jbig2: page too large: %dx%d", w, h)
+	}
+
+	dec.pageNr = seg.page
+	dec.defaultPixel = (flags >> 2) & 1
+
+	if h == 0xFFFFFFFF {
+		dec.striped = true
+		h = 0
+	}
+
+	dec.page = newJBIG2Bitmap(int(w), int(h))
+	if dec.defaultPixel != 0 {
+		dec.page.fill(1)
+	}
+
+	return nil
+}
+
+// decodeEndOfStripe handles an end of stripe segment (7.4.10).
+func (dec *jbig2Decoder) decodeEndOfStripe(r *jbig2Reader) error {
+	y, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if dec.page != nil && dec.striped && y < 1<<24 {
+		dec.page.grow(int(y)+1, dec.defaultPixel)
+	}
+	return nil
+}
+
+// placeRegion combines a decoded region with the page or keeps it for refinement.
+func (dec *jbig2Decoder) placeRegion(seg *jbig2Segment, ri *jbig2RegionInfo, bm *jbig2Bitmap) error {
+	switch seg.typ {
+	case jbig2IntermediateTextRegion, jbig2IntermediateHalftoneRegion, jbig2IntermediateGenericRegion, jbig2IntermediateRefinementRegion:
+		seg.region = bm
+		return nil
+	}
+
+	if dec.page == nil {
+		return errors.New("pdfcpu: jbig2: region segment before page information")
+	}
+
+	if dec.striped && ri.y+ri.h > dec.page.h {
+		dec.page.grow(ri.y+ri.h, dec.defaultPixel)
+	}
+
+	dec.page.combine(bm, ri.x, ri.y, ri.op)
+
+	return nil
+}
+
+// decodeSymbolDictSegment parses a symbol dictionary segment (7.4.2).
+func (dec *jbig2Decoder) decodeSymbolDictSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	flags, err := r.u16()
+	if err != nil {
+		return err
+	}
+
+	if flags&0x01 != 0 {
+		return ErrJBIG2Huffman
+	}
+
+	sd := jbig2SymbolDict{
+		refAgg:         flags&0x02 != 0,
+		template:       (flags >> 10) & 0x03,
+		refineTemplate: (flags >> 12) & 0x01,
+	}
+
+	n := 1
+	if sd.template == 0 {
+		n = 4
+	}
+	if sd.at, err = r.atPixels(n); err != nil {
+		return err
+	}
+
+	if sd.refAgg && sd.refineTemplate == 0 {
+		if sd.refineAT, err = r.atPixels(2); err != nil {
+			return err
+		}
+	}
+
+	numExported, err := r.u32()
+	if err != nil {
+		return err
+	}
+	numNew, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if numNew > 1<<20 || numExported > 1<<20 {
+		return errors.New("pdfcpu: jbig2: too many symbols")
+	}
+	sd.numExported, sd.numNew = int(numExported), int(numNew)
+
+	var in []*jbig2Bitmap
+	for _, s := range dec.referred(seg, jbig2SymbolDictionary) {
+		in = append(in, s.symbols...)
+	}
+
+	ctx := newJBIG2Context(r.bb, r.pos, len(r.bb))
+
+	seg.symbols, err = decodeSymbolDict(ctx, sd, in)
+
+	return err
+}
+
+// decodeTextRegionSegment parses a text region segment (7.4.3).
+func (dec *jbig2Decoder) decodeTextRegionSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	ri, err := r.regionInfo()
+	if err != nil {
+		return err
+	}
+
+	flags, err := r.u16()
+	if err != nil {
+		return err
+	}
+
+	if flags&0x01 != 0 {
+		return ErrJBIG2Huffman
+	}
+
+	tr := jbig2TextRegion{
+		w:              ri.w,
+		h:              ri.h,
+		refine:         flags&0x02 != 0,
+		logStripSize:   (flags >> 2) & 0x03,
+		refCorner:      (flags >> 4) & 0x03,
+		transposed:     flags&0x40 != 0,
+		op:             (flags >> 7) & 0x03,
+		defaultPixel:   byte(flags>>9) & 0x01,
+		dsOffset:       int(int16(flags<<1)) >> 11,
+		refineTemplate: (flags >> 15) & 0x01,
+	}
+
+	if tr.refine && tr.refineTemplate == 0 {
+		if tr.refineAT, err = r.atPixels(2); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	tr.numInstances = int(n)
+
+	for _, s := range dec.referred(seg, jbig2SymbolDictionary) {
+		tr.symbols = append(tr.symbols, s.symbols...)
+	}
+	tr.symCodeLen = log2Ceil(len(tr.symbols))
+
+	ctx := newJBIG2Context(r.bb, r.pos, len(r.bb))
+
+	bm, err := decodeTextRegion(ctx, tr)
+	if err != nil {
+		return err
+	}
+
+	return dec.placeRegion(seg, ri, bm)
+}
+
+// decodePatternDictSegment parses a pattern dictionary segment (7.4.4).
+func (dec *jbig2Decoder) decodePatternDictSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	flags, err := r.u8()
+	if err != nil {
+		return err
+	}
+	pw, err := r.u8()
+	if err != nil {
+		return err
+	}
+	ph, err := r.u8()
+	if err != nil {
+		return err
+	}
+	grayMax, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if grayMax > 1<<16 {
+		return errors.New("pdfcpu: jbig2: too many patterns")
+	}
+
+	mmr := flags&0x01 != 0
+	template := int(flags>>1) & 0x03
+
+	var ctx *jbig2Context
+	if !mmr {
+		ctx = newJBIG2Context(r.bb, r.pos, len(r.bb))
+	}
+
+	seg.patterns, err = decodePatternDict(ctx, r.bb[r.pos:], mmr, template, int(pw), int(ph), int(grayMax))
+
+	return err
+}
+
+// decodeHalftoneRegionSegment parses a halftone region segment (7.4.5).
+func (dec *jbig2Decoder) decodeHalftoneRegionSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	ri, err := r.regionInfo()
+	if err != nil {
+		return err
+	}
+
+	flags, err := r.u8()
+	if err != nil {
+		return err
+	}
+
+	if flags&0x01 != 0 {
+		return errors.New("pdfcpu: jbig2: mmr coded halftone regions not supported")
+	}
+
+	hr := jbig2Halftone{
+		w:            ri.w,
+		h:            ri.h,
+		template:     int(flags>>1) & 0x03,
+		skip:         flags&0x08 != 0,
+		op:           int(flags>>4) & 0x07,
+		defaultPixel: (flags >> 7) & 0x01,
+	}
+
+	gw, err := r.u32()
+	if err != nil {
+		return err
+	}
+	gh, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if gw > 1<<16 || gh > 1<<16 {
+		return errors.New("pdfcpu: jbig2: halftone grid too large")
+	}
+	hr.gw, hr.gh = int(gw), int(gh)
+
+	if hr.gx, err = r.i32(); err != nil {
+		return err
+	}
+	if hr.gy, err = r.i32(); err != nil {
+		return err
+	}
+	if hr.rx, err = r.u16(); err != nil {
+		return err
+	}
+	if hr.ry, err = r.u16(); err != nil {
+		return err
+	}
+
+	for _, s := range dec.referred(seg, jbig2PatternDictionary) {
+		hr.patterns = append(hr.patterns, s.patterns...)
+	}
+
+	ctx := newJBIG2Context(r.bb, r.pos, len(r.bb))
+
+	return dec.placeRegion(seg, ri, decodeHalftoneRegion(ctx, hr))
+}
+
+// decodeGenericRegionSegment parses a generic region segment (7.4.6).
+func (dec *jbig2Decoder) decodeGenericRegionSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	ri, err := r.regionInfo()
+	if err != nil {
+		return err
+	}
+
+	flags, err := r.u8()
+	if err != nil {
+		return err
+	}
+
+	mmr := flags&0x01 != 0
+	template := int(flags>>1) & 0x03
+	tpgdon := flags&0x08 != 0
+
+	var bm *jbig2Bitmap
+
+	if mmr {
+		if bm, err = decodeMMRRegion(r.bb[r.pos:], ri.w, ri.h); err != nil {
+			return err
+		}
+	} else {
+		n := 1
+		if template == 0 {
+			n = 4
+		}
+		at, err := r.atPixels(n)
+		if err != nil {
+			return err
+		}
+		ctx := newJBIG2Context(r.bb, r.pos, len(r.bb))
+		bm = decodeGenericRegion(ctx, ri.w, ri.h, template, tpgdon, nil, at)
+	}
+
+	return dec.placeRegion(seg, ri, bm)
+}
+
+// decodeRefinementRegionSegment parses a generic refinement region segment (7.4.7).
+func (dec *jbig2Decoder) decodeRefinementRegionSegment(seg *jbig2Segment, r *jbig2Reader) error {
+	ri, err := r.regionInfo()
+	if err != nil {
+		return err
+	}
+
+	flags, err := r.u8()
+	if err != nil {
+		return err
+	}
+
+	template := int(flags & 0x01)
+	tpgron := flags&0x02 != 0
+
+	var at []atPixel
+	if template == 0 {
+		if at, err = r.atPixels(2); err != nil {
+			return err
+		}
+	}
+
+	// The reference is either an intermediate region or the page area covered by this region.
+	var ref *jbig2Bitmap
+	for _, nr := range seg.referred {
+		if s, ok := dec.segments[nr]; ok && s.region != nil {
+			ref = s.region
+			break
+		}
+	}
+
+	if ref == nil {
+		if dec.page == nil {
+			return errors.New("pdfcpu: jbig2: region segment before page information")
+		}
+		ref = newJBIG2Bitmap(ri.w, ri.h)
+		ref.combine(dec.page, -ri.x, -ri.y, jbig2OpReplace)
+	}
+
+	ctx := newJBIG2Context(r.bb, r.pos, len(r.bb))
+	bm := decodeRefinementRegion(ctx, ri.w, ri.h, template, ref, 0, 0, tpgron, at)
+
+	if len(seg.referred) == 0 && seg.typ != jbig2IntermediateRefinementRegion {
+		// Refining the page itself replaces the area.
+		ri.op = jbig2OpReplace
+	}
+
+	return dec.placeRegion(seg, ri, bm)
+}
@@ -0,0 +1,399 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// mqEncoder implements the MQ arithmetic encoder (T.88 Annex E.2) for generating test streams.
+type mqEncoder struct {
+	a, c uint32
+	ct   int
+	buf  []byte // buf[0] is the byte preceding the code stream.
+}
+
+func newMQEncoder() *mqEncoder {
+	return &mqEncoder{a: 0x8000, ct: 12, buf: []byte{0}}
+}
+
+func (e *mqEncoder) byteOut() {
+	bp := len(e.buf) - 1
+	if e.buf[bp] == 0xFF {
+		e.buf = append(e.buf, byte(e.c>>20))
+		e.c &= 0xFFFFF
+		e.ct = 7
+		return
+	}
+	if e.c < 0x8000000 {
+		e.buf = append(e.buf, byte(e.c>>19))
+		e.c &= 0x7FFFF
+		e.ct = 8
+		return
+	}
+	e.buf[bp]++
+	if e.buf[bp] == 0xFF {
+		e.c &= 0x7FFFFFF
+		e.buf = append(e.buf, byte(e.c>>20))
+		e.c &= 0xFFFFF
+		e.ct = 7
+		return
+	}
+	e.buf = append(e.buf, byte(e.c>>19))
+	e.c &= 0x7FFFF
+	e.ct = 8
+}
+
+func (e *mqEncoder) renorm() {
+	for {
+		e.a <<= 1
+		e.c <<= 1
+		e.ct--
+		if e.ct == 0 {
+			e.byteOut()
+		}
+		if e.a&0x8000 != 0 {
+			break
+		}
+	}
+}
+
+func (e *mqEncoder) encode(cx mqContexts, pos, bit int) {
+	index := cx[pos] >> 1
+	mps := int(cx[pos] & 1)
+	q := qeTable[index]
+
+	e.a -= q.qe
+
+	if bit == mps {
+		if e.a&0x8000 != 0 {
+			e.c += q.qe
+			return
+		}
+		if e.a < q.qe {
+			e.a = q.qe
+		} else {
+			e.c += q.qe
+		}
+		cx[pos] = q.nmps<<1 | uint8(mps)
+		e.renorm()
+		return
+	}
+
+	if e.a < q.qe {
+		e.c += q.qe
+	} else {
+		e.a = q.qe
+	}
+	if q.switchFlag {
+		mps = 1 - mps
+	}
+	cx[pos] = q.nlps<<1 | uint8(mps)
+	e.renorm()
+}
+
+func (e *mqEncoder) flush() []byte {
+	t := e.c + e.a
+	e.c |= 0xFFFF
+	if e.c >= t {
+		e.c -= 0x8000
+	}
+	e.c <<= uint(e.ct)
+	e.byteOut()
+	e.c <<= uint(e.ct)
+	e.byteOut()
+	bb := e.buf[1:]
+	if bb[len(bb)-1] != 0xFF {
+		bb = append(bb, 0xFF)
+	}
+	return append(bb, 0xAC)
+}
+
+type testEncoder struct {
+	*mqEncoder
+	contexts map[string]mqContexts
+}
+
+func (e *testEncoder) cx(name string, size int) mqContexts {
+	cx, ok := e.contexts[name]
+	if !ok {
+		cx = make(mqContexts, size)
+		e.contexts[name] = cx
+	}
+	return cx
+}
+
+// encodeInteger is the inverse of the integer arithmetic decoding procedure (A.2).
+func (e *testEncoder) encodeInteger(proc string, v int, oob bool) {
+	cx := e.cx(proc, 512)
+	prev := 1
+	put := func(bit int) {
+		e.encode(cx, prev, bit)
+		if prev < 256 {
+			prev = prev<<1 | bit
+		} else {
+			prev = (prev<<1|bit)&511 | 256
+		}
+	}
+	putBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			put((v >> uint(i)) & 1)
+		}
+	}
+
+	if oob {
+		putBits(0x8, 4)
+		return
+	}
+
+	sign := 0
+	if v < 0 {
+		sign, v = 1, -v
+	}
+	put(sign)
+
+	switch {
+	case v < 4:
+		putBits(0, 1)
+		putBits(v, 2)
+	case v < 20:
+		putBits(2, 2)
+		putBits(v-4, 4)
+	case v < 84:
+		putBits(6, 3)
+		putBits(v-20, 6)
+	case v < 340:
+		putBits(14, 4)
+		putBits(v-84, 8)
+	case v < 4436:
+		putBits(30, 5)
+		putBits(v-340, 12)
+	default:
+		putBits(31, 5)
+		putBits(v-4436, 32)
+	}
+}
+
+func (e *testEncoder) encodeIAID(v, codeLen int) {
+	cx := e.cx("IAID", 1<<uint(codeLen+1))
+	prev := 1
+	for i := codeLen - 1; i >= 0; i-- {
+		bit := (v >> uint(i)) & 1
+		e.encode(cx, prev, bit)
+		prev = prev<<1 | bit
+	}
+}
+
+// encodeGenericRegion encodes bm using GB template 0 with nominal AT pixels.
+func (e *testEncoder) encodeGenericRegion(bm *jbig2Bitmap) {
+	tmpl := []atPixel{
+		{-2, -2}, {-1, -2}, {0, -2}, {1, -2}, {2, -2},
+		{-3, -1}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1}, {3, -1},
+		{-4, 0}, {-3, 0}, {-2, 0}, {-1, 0},
+	}
+	cx := e.cx("GB", 1<<16)
+	for y := 0; y < bm.h; y++ {
+		for x := 0; x < bm.w; x++ {
+			label := 0
+			for _, p := range tmpl {
+				label = label<<1 | int(bm.pixel(x+p.x, y+p.y))
+			}
+			e.encode(cx, label, int(bm.rows[y][x]))
+		}
+	}
+}
+
+func newTestEncoder() *testEncoder {
+	return &testEncoder{newMQEncoder(), map[string]mqContexts{}}
+}
+
+var nominalAT = []byte{3, 0xFF, 0xFD, 0xFF, 2, 0xFE, 0xFE, 0xFE}
+
+func segment(nr uint32, typ byte, referred []byte, page byte, data []byte) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, nr)
+	b.WriteByte(typ)
+	b.WriteByte(byte(len(referred)) << 5)
+	b.Write(referred)
+	b.WriteByte(page)
+	binary.Write(&b, binary.BigEndian, uint32(len(data)))
+	b.Write(data)
+	return b.Bytes()
+}
+
+func pageInfo(w, h int) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, []uint32{uint32(w), uint32(h), 0, 0})
+	b.WriteByte(0)
+	binary.Write(&b, binary.BigEndian, uint16(0))
+	return b.Bytes()
+}
+
+func regionInfo(w, h, x, y int) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.BigEndian, []uint32{uint32(w), uint32(h), uint32(x), uint32(y)})
+	b.WriteByte(jbig2OpOr)
+	return b.Bytes()
+}
+
+func testBitmap(w, h int, f func(x, y int) bool) *jbig2Bitmap {
+	bm := newJBIG2Bitmap(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if f(x, y) {
+				bm.rows[y][x] = 1
+			}
+		}
+	}
+	return bm
+}
+
+func decodeJBIG2(t *testing.T, globals, data []byte) []byte {
+	t.Helper()
+
+	f, err := NewFilter(JBIG2, nil, JBIG2Globals(globals))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := f.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode: %v\n", err)
+	}
+	bb, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	return bb
+}
+
+func TestJBIG2GenericRegion(t *testing.T) {
+	w, h := 37, 23
+	bm := testBitmap(w, h, func(x, y int) bool {
+		return (x-18)*(x-18)+(y-11)*(y-11) < 80 || x == y || (x*7+y*3)%11 == 0
+	})
+
+	e := newTestEncoder()
+	e.encodeGenericRegion(bm)
+
+	region := append(regionInfo(w, h, 0, 0), 0x00)
+	region = append(region, nominalAT...)
+	region = append(region, e.flush()...)
+
+	var data []byte
+	data = append(data, segment(0, jbig2PageInformation, nil, 1, pageInfo(w, h))...)
+	data = append(data, segment(1, jbig2ImmediateLosslessGeneric, nil, 1, region)...)
+	data = append(data, segment(2, jbig2EndOfPage, nil, 1, nil)...)
+
+	compare(t, decodeJBIG2(t, nil, data), bm.bytes())
+}
+
+func TestJBIG2SymbolDictWithGlobals(t *testing.T) {
+	// Two symbols of height 5 defined in the globals stream.
+	syms := []*jbig2Bitmap{
+		testBitmap(4, 5, func(x, y int) bool { return x == 0 || y == 4 }),
+		testBitmap(6, 5, func(x, y int) bool { return x == y || x == 5-y }),
+	}
+
+	e := newTestEncoder()
+	e.encodeInteger("IADH", 5, false)
+	w := 0
+	for _, s := range syms {
+		e.encodeInteger("IADW", s.w-w, false)
+		w = s.w
+		e.encodeGenericRegion(s)
+	}
+	e.encodeInteger("IADW", 0, true)
+	// Export flags: skip 0 input symbols, export both new symbols.
+	e.encodeInteger("IAEX", 0, false)
+	e.encodeInteger("IAEX", len(syms), false)
+
+	dict := []byte{0x00, 0x00}
+	dict = append(dict, nominalAT...)
+	dict = binary.BigEndian.AppendUint32(dict, uint32(len(syms)))
+	dict = binary.BigEndian.AppendUint32(dict, uint32(len(syms)))
+	dict = append(dict, e.flush()...)
+
+	globals := segment(0, jbig2SymbolDictionary, nil, 0, dict)
+
+	// Place the symbols on a 20x10 page using a text region: id, x, y
+	w, h := 20, 10
+	placements := [][3]int{{0, 1, 2}, {1, 6, 2}, {0, 14, 3}}
+
+	e = newTestEncoder()
+	e.encodeInteger("IADT", 0, false)
+	firstS, curS := 0, 0
+	for i, p := range placements {
+		if i == 0 || p[2] != placements[i-1][2] {
+			if i > 0 {
+				e.encodeInteger("IADS", 0, true)
+			}
+			dt := p[2]
+			if i > 0 {
+				dt -= placements[i-1][2]
+			}
+			e.encodeInteger("IADT", dt, false)
+			e.encodeInteger("IAFS", p[1]-firstS, false)
+			firstS = p[1]
+		} else {
+			e.encodeInteger("IADS", p[1]-curS, false)
+		}
+		e.encodeIAID(p[0], 1)
+		curS = p[1] + syms[p[0]].w - 1
+	}
+	e.encodeInteger("IADS", 0, true)
+
+	text := regionInfo(w, h, 0, 0)
+	text = append(text, 0x00, 0x10) // REFCORNER = TOPLEFT
+	text = binary.BigEndian.AppendUint32(text, uint32(len(placements)))
+	text = append(text, e.flush()...)
+
+	var data []byte
+	data = append(data, segment(1, jbig2PageInformation, nil, 1, pageInfo(w, h))...)
+	data = append(data, segment(2, jbig2ImmediateTextRegion, []byte{0}, 1, text)...)
+	data = append(data, segment(3, jbig2EndOfPage, nil, 1, nil)...)
+
+	want := newJBIG2Bitmap(w, h)
+	for _, p := range placements {
+		want.combine(syms[p[0]], p[1], p[2], jbig2OpOr)
+	}
+
+	compare(t, decodeJBIG2(t, globals, data), want.bytes())
+}
+
+func TestJBIG2ArithmeticDecoder(t *testing.T) {
+	// Test sequence for the arithmetic decoder taken from ITU-T T.88, Annex H.2.
+	encoded := []byte{
+		0x84, 0xC7, 0x3B, 0xFC, 0xE1, 0xA1, 0x43, 0x04, 0x02, 0x20, 0x00, 0x00, 0x41, 0x0D, 0xBB, 0x86,
+		0xF4, 0x31, 0x7F, 0xFF, 0x88, 0xFF, 0x37, 0x47, 0x1A, 0xDB, 0x6A, 0xDF, 0xFF, 0xAC,
+	}
+	want := []byte{
+		0x00, 0x02, 0x00, 0x51, 0x00, 0x00, 0x00, 0xC0, 0x03, 0x52, 0x87, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA,
+		0x82, 0xC0, 0x20, 0x00, 0xFC, 0xD7, 0x9E, 0xF6, 0xBF, 0x7F, 0xED, 0x90, 0x4F, 0x46, 0xA3, 0xBF,
+	}
+
+	d := newMQDecoder(encoded, 0, len(encoded))
+	cx := make(mqContexts, 1)
+	got := make([]byte, len(want))
+	for i := 0; i < 8*len(want); i++ {
+		got[i/8] |= byte(d.readBit(cx, 0) << uint(7-i%8))
+	}
+
+	compare(t, got, want)
+}
@@ -0,0 +1,605 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+	"math/bits"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/ccitt"
+)
+
+// JBIG2 region combination operators (7.4.1.5).
+const (
+	jbig2OpOr = iota
+	jbig2OpAnd
+	jbig2OpXor
+	jbig2OpXNor
+	jbig2OpReplace
+)
+
+// jbig2Bitmap is a bi-level image using one byte per pixel, 1 = black.
+type jbig2Bitmap struct {
+	w, h int
+	rows [][]byte
+}
+
+func newJBIG2Bitmap(w, h int) *jbig2Bitmap {
+	bm := &jbig2Bitmap{w: w, h: h, rows: make([][]byte, h)}
+	pix := make([]byte, w*h)
+	for y := 0; y < h; y++ {
+		bm.rows[y] = pix[y*w : (y+1)*w : (y+1)*w]
+	}
+	return bm
+}
+
+func (bm *jbig2Bitmap) fill(v byte) {
+	for _, row := range bm.rows {
+		for x := range row {
+			row[x] = v
+		}
+	}
+}
+
+// pixel returns the pixel value at x,y treating everything outside bm as 0.
+func (bm *jbig2Bitmap) pixel(x, y int) byte {
+	if x < 0 || y < 0 || x >= bm.w || y >= bm.h {
+		return 0
+	}
+	return bm.rows[y][x]
+}
+
+// combine draws src into bm at x,y using combination operator op.
+func (bm *jbig2Bitmap) combine(src *jbig2Bitmap, x, y, op int) {
+	for sy := 0; sy < src.h; sy++ {
+		dy := y + sy
+		if dy < 0 || dy >= bm.h {
+			continue
+		}
+		row, srow := bm.rows[dy], src.rows[sy]
+		for sx := 0; sx < src.w; sx++ {
+			dx := x + sx
+			if dx < 0 || dx >= bm.w {
+				continue
+			}
+			s := srow[sx]
+			switch op {
+			case jbig2OpOr:
+				row[dx] |= s
+			case jbig2OpAnd:
+				row[dx] &= s
+			case jbig2OpXor:
+				row[dx] ^= s
+			case jbig2OpXNor:
+				row[dx] = 1 ^ (row[dx] ^ s)
+			case jbig2OpReplace:
+				row[dx] = s
+			}
+		}
+	}
+}
+
+// grow extends the height of bm to h rows using pixel value v.
+func (bm *jbig2Bitmap) grow(h int, v byte) {
+	for bm.h < h {
+		row := make([]byte, bm.w)
+		if v != 0 {
+			for x := range row {
+				row[x] = v
+			}
+		}
+		bm.rows = append(bm.rows, row)
+		bm.h++
+	}
+}
+
+// bytes returns the packed rows of bm where 0 represents black.
+func (bm *jbig2Bitmap) bytes() []byte {
+	stride := (bm.w + 7) / 8
+	bb := make([]byte, stride*bm.h)
+	for y, row := range bm.rows {
+		line := bb[y*stride : (y+1)*stride]
+		for x, v := range row {
+			if v != 0 {
+				line[x>>3] |= 0x80 >> uint(x&7)
+			}
+		}
+		for i := range line {
+			line[i] = ^line[i]
+		}
+		if r := bm.w & 7; r != 0 {
+			// Keep padding bits zero.
+			line[stride-1] &= 0xFF << uint(8-r)
+		}
+	}
+	return bb
+}
+
+type atPixel struct {
+	x, y int
+}
+
+// Generic region templates without their adaptive pixels (6.2.5.3).
+var genericTemplates = [4][]atPixel{
+	{{-1, -2}, {0, -2}, {1, -2}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1}, {-4, 0}, {-3, 0}, {-2, 0}, {-1, 0}},
+	{{-1, -2}, {0, -2}, {1, -2}, {2, -2}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {2, -1}, {-3, 0}, {-2, 0}, {-1, 0}},
+	{{-1, -2}, {0, -2}, {1, -2}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {-2, 0}, {-1, 0}},
+	{{-3, -1}, {-2, -1}, {-1, -1}, {0, -1}, {1, -1}, {-4, 0}, {-3, 0}, {-2, 0}, {-1, 0}},
+}
+
+// SLTP pseudo pixel contexts for typical prediction (6.2.5.7).
+var genericSLTPContexts = [4]int{0x9B25, 0x0795, 0x00E5, 0x0195}
+
+// Generic refinement region templates without their adaptive pixels (6.3.5.3).
+var refinementTemplates = [2]struct {
+	coding, reference []atPixel
+}{
+	{
+		coding:    []atPixel{{0, -1}, {1, -1}, {-1, 0}},
+		reference: []atPixel{{0, -1}, {1, -1}, {-1, 0}, {0, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}},
+	},
+	{
+		coding:    []atPixel{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}},
+		reference: []atPixel{{0, -1}, {-1, 0}, {0, 0}, {1, 0}, {0, 1}, {1, 1}},
+	},
+}
+
+// SLTP pseudo pixel contexts for refinement typical prediction (6.3.5.6).
+var refinementSLTPContexts = [2]int{0x0020, 0x0008}
+
+// log2Ceil returns the number of bits needed to code n distinct values.
+func log2Ceil(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// decodeGenericRegion implements the generic region decoding procedure (6.2) using arithmetic coding.
+func decodeGenericRegion(ctx *jbig2Context, w, h, template int, tpgdon bool, skip *jbig2Bitmap, at []atPixel) *jbig2Bitmap {
+	tmpl := append(append([]atPixel{}, genericTemplates[template]...), at...)
+	sort.SliceStable(tmpl, func(i, j int) bool {
+		if tmpl[i].y != tmpl[j].y {
+			return tmpl[i].y < tmpl[j].y
+		}
+		return tmpl[i].x < tmpl[j].x
+	})
+
+	cx := ctx.cx("GB", 1<<16)
+	bm := newJBIG2Bitmap(w, h)
+	ltp := 0
+
+	for y := 0; y < h; y++ {
+		if tpgdon {
+			ltp ^= ctx.decoder.readBit(cx, genericSLTPContexts[template])
+			if ltp == 1 {
+				if y > 0 {
+					copy(bm.rows[y], bm.rows[y-1])
+				}
+				continue
+			}
+		}
+		row := bm.rows[y]
+		for x := 0; x < w; x++ {
+			if skip != nil && skip.rows[y][x] == 1 {
+				continue
+			}
+			label := 0
+			for _, p := range tmpl {
+				label = label<<1 | int(bm.pixel(x+p.x, y+p.y))
+			}
+			row[x] = byte(ctx.decoder.readBit(cx, label))
+		}
+	}
+
+	return bm
+}
+
+// decodeMMRRegion implements the generic region decoding procedure (6.2) using MMR coding.
+func decodeMMRRegion(data []byte, w, h int) (*jbig2Bitmap, error) {
+	bm := newJBIG2Bitmap(w, h)
+	if w == 0 || h == 0 {
+		return bm, nil
+	}
+
+	rd := ccitt.NewReader(bytes.NewReader(data), ccitt.MSB, ccitt.Group4, w, h, &ccitt.Options{Invert: true})
+	line := make([]byte, (w+7)/8)
+
+	for y := 0; y < h; y++ {
+		if _, err := io.ReadFull(rd, line); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: jbig2: mmr")
+		}
+		row := bm.rows[y]
+		for x := range row {
+			row[x] = (line[x>>3] >> uint(7-x&7)) & 1
+		}
+	}
+
+	return bm, nil
+}
+
+// decodeRefinementRegion implements the generic refinement region decoding procedure (6.3).
+func decodeRefinementRegion(ctx *jbig2Context, w, h, template int, ref *jbig2Bitmap, dx, dy int, tpgron bool, at []atPixel) *jbig2Bitmap {
+	coding := refinementTemplates[template].coding
+	reference := refinementTemplates[template].reference
+	if template == 0 {
+		coding = append(append([]atPixel{}, coding...), at[0])
+		reference = append(append([]atPixel{}, reference...), at[1])
+	}
+
+	cx := ctx.cx("GR", 1<<14)
+	bm := newJBIG2Bitmap(w, h)
+	ltp := 0
+
+	// typical returns the common value of the 3x3 reference neighbourhood of x,y or -1.
+	typical := func(x, y int) int {
+		v := ref.pixel(x-dx, y-dy)
+		for j := -1; j <= 1; j++ {
+			for i := -1; i <= 1; i++ {
+				if ref.pixel(x-dx+i, y-dy+j) != v {
+					return -1
+				}
+			}
+		}
+		return int(v)
+	}
+
+	for y := 0; y < h; y++ {
+		if tpgron {
+			ltp ^= ctx.decoder.readBit(cx, refinementSLTPContexts[template])
+		}
+		row := bm.rows[y]
+		for x := 0; x < w; x++ {
+			if ltp == 1 {
+				if v := typical(x, y); v >= 0 {
+					row[x] = byte(v)
+					continue
+				}
+			}
+			label := 0
+			for _, p := range coding {
+				label = label<<1 | int(bm.pixel(x+p.x, y+p.y))
+			}
+			for _, p := range reference {
+				label = label<<1 | int(ref.pixel(x+p.x-dx, y+p.y-dy))
+			}
+			row[x] = byte(ctx.decoder.readBit(cx, label))
+		}
+	}
+
+	return bm
+}
+
+type jbig2TextRegion struct {
+	w, h           int
+	defaultPixel   byte
+	refine         bool
+	numInstances   int
+	logStripSize   int
+	symbols        []*jbig2Bitmap
+	symCodeLen     int
+	transposed     bool
+	dsOffset       int
+	refCorner      int
+	op             int
+	refineTemplate int
+	refineAT       []atPixel
+}
+
+// Reference corners (7.4.3.1.1).
+const (
+	jbig2BottomLeft = iota
+	jbig2TopLeft
+	jbig2BottomRight
+	jbig2TopRight
+)
+
+// decodeTextRegion implements the text region decoding procedure (6.4) using arithmetic coding.
+func decodeTextRegion(ctx *jbig2Context, tr jbig2TextRegion) (*jbig2Bitmap, error) {
+	bm := newJBIG2Bitmap(tr.w, tr.h)
+	if tr.defaultPixel != 0 {
+		bm.fill(1)
+	}
+
+	stripSize := 1 << uint(tr.logStripSize)
+
+	dt, _ := ctx.decodeInteger("IADT")
+	stripT := -dt
+	firstS := 0
+
+	for i := 0; i < tr.numInstances; {
+		dt, _ = ctx.decodeInteger("IADT")
+		stripT += dt
+
+		dfs, _ := ctx.decodeInteger("IAFS")
+		firstS += dfs
+		curS := firstS
+
+		for {
+			curT := 0
+			if stripSize > 1 {
+				curT, _ = ctx.decodeInteger("IAIT")
+			}
+			t := stripSize*stripT + curT
+
+			id := ctx.decodeIAID(tr.symCodeLen)
+			if id < 0 || id >= len(tr.symbols) {
+				return nil, errors.Errorf("pdfcpu: jbig2: invalid symbol id %d", id)
+			}
+			sym := tr.symbols[id]
+
+			if tr.refine {
+				if ri, _ := ctx.decodeInteger("IARI"); ri != 0 {
+					rdw, _ := ctx.decodeInteger("IARDW")
+					rdh, _ := ctx.decodeInteger("IARDH")
+					rdx, _ := ctx.decodeInteger("IARDX")
+					rdy, _ := ctx.decodeInteger("IARDY")
+					w, h := sym.w+rdw, sym.h+rdh
+					if w < 0 || h < 0 {
+						return nil, errors.New("pdfcpu: jbig2: invalid refinement size")
+					}
+					sym = decodeRefinementRegion(ctx, w, h, tr.refineTemplate, sym, rdw>>1+rdx, rdh>>1+rdy, false, tr.refineAT)
+				}
+			}
+
+			if !tr.transposed && tr.refCorner > jbig2TopLeft {
+				curS += sym.w - 1
+			} else if tr.transposed && tr.refCorner&1 == 0 {
+				curS += sym.h - 1
+			}
+
+			var x, y int
+			if !tr.transposed {
+				x, y = curS, t
+			} else {
+				x, y = t, curS
+			}
+			if tr.refCorner&2 != 0 {
+				x -= sym.w - 1
+			}
+			if tr.refCorner&1 == 0 {
+				y -= sym.h - 1
+			}
+			bm.combine(sym, x, y, tr.op)
+
+			if !tr.transposed && tr.refCorner <= jbig2TopLeft {
+				curS += sym.w - 1
+			} else if tr.transposed && tr.refCorner&1 == 1 {
+				curS += sym.h - 1
+			}
+
+			i++
+
+			ds, ok := ctx.decodeInteger("IADS")
+			if !ok {
+				break
+			}
+			curS += ds + tr.dsOffset
+		}
+	}
+
+	return bm, nil
+}
+
+type jbig2SymbolDict struct {
+	refAgg         bool
+	template       int
+	at             []atPixel
+	refineTemplate int
+	refineAT       []atPixel
+	numExported    int
+	numNew         int
+}
+
+// decodeSymbolDict implements the symbol dictionary decoding procedure (6.5) using arithmetic coding.
+func decodeSymbolDict(ctx *jbig2Context, sd jbig2SymbolDict, in []*jbig2Bitmap) ([]*jbig2Bitmap, error) {
+	var newSyms []*jbig2Bitmap
+	symCodeLen := log2Ceil(len(in) + sd.numNew)
+	h := 0
+
+	for len(newSyms) < sd.numNew {
+		dh, _ := ctx.decodeInteger("IADH")
+		h += dh
+		if h < 0 {
+			return nil, errors.New("pdfcpu: jbig2: invalid symbol height class")
+		}
+		w := 0
+
+		for {
+			dw, ok := ctx.decodeInteger("IADW")
+			if !ok {
+				break
+			}
+			if len(newSyms) >= sd.numNew {
+				return nil, errors.New("pdfcpu: jbig2: too many symbols in height class")
+			}
+			w += dw
+			if w < 0 {
+				return nil, errors.New("pdfcpu: jbig2: invalid symbol width")
+			}
+
+			if !sd.refAgg {
+				newSyms = append(newSyms, decodeGenericRegion(ctx, w, h, sd.template, false, nil, sd.at))
+				continue
+			}
+
+			syms := append(append([]*jbig2Bitmap{}, in...), newSyms...)
+
+			n, _ := ctx.decodeInteger("IAAI")
+			if n > 1 {
+				bm, err := decodeTextRegion(ctx, jbig2TextRegion{
+					w:              w,
+					h:              h,
+					refine:         true,
+					numInstances:   n,
+					symbols:        syms,
+					symCodeLen:     symCodeLen,
+					refCorner:      jbig2TopLeft,
+					refineTemplate: sd.refineTemplate,
+					refineAT:       sd.refineAT,
+				})
+				if err != nil {
+					return nil, err
+				}
+				newSyms = append(newSyms, bm)
+				continue
+			}
+
+			id := ctx.decodeIAID(symCodeLen)
+			rdx, _ := ctx.decodeInteger("IARDX")
+			rdy, _ := ctx.decodeInteger("IARDY")
+			if id < 0 || id >= len(syms) {
+				return nil, errors.Errorf("pdfcpu: jbig2: invalid symbol id %d", id)
+			}
+			newSyms = append(newSyms, decodeRefinementRegion(ctx, w, h, sd.refineTemplate, syms[id], rdx, rdy, false, sd.refineAT))
+		}
+	}
+
+	// Exported symbols (6.5.10)
+	all := append(append([]*jbig2Bitmap{}, in...), newSyms...)
+	var exported []*jbig2Bitmap
+	export := false
+
+	for i := 0; i < len(all); {
+		n, ok := ctx.decodeInteger("IAEX")
+		if !ok || n < 0 || i+n > len(all) {
+			return nil, errors.New("pdfcpu: jbig2: corrupt export flags")
+		}
+		if export {
+			exported = append(exported, all[i:i+n]...)
+		}
+		i += n
+		export = !export
+	}
+
+	return exported, nil
+}
+
+// decodePatternDict implements the pattern dictionary decoding procedure (6.7).
+func decodePatternDict(ctx *jbig2Context, data []byte, mmr bool, template, pw, ph, grayMax int) ([]*jbig2Bitmap, error) {
+	w := (grayMax + 1) * pw
+
+	var (
+		bm  *jbig2Bitmap
+		err error
+	)
+
+	if mmr {
+		if bm, err = decodeMMRRegion(data, w, ph); err != nil {
+			return nil, err
+		}
+	} else {
+		at := []atPixel{{-pw, 0}}
+		if template == 0 {
+			at = append(at, atPixel{-3, -1}, atPixel{2, -2}, atPixel{-2, -2})
+		}
+		bm = decodeGenericRegion(ctx, w, ph, template, false, nil, at)
+	}
+
+	pp := make([]*jbig2Bitmap, grayMax+1)
+	for i := range pp {
+		p := newJBIG2Bitmap(pw, ph)
+		for y := 0; y < ph; y++ {
+			copy(p.rows[y], bm.rows[y][i*pw:(i+1)*pw])
+		}
+		pp[i] = p
+	}
+
+	return pp, nil
+}
+
+type jbig2Halftone struct {
+	w, h         int
+	template     int
+	skip         bool
+	op           int
+	defaultPixel byte
+	gw, gh       int
+	gx, gy       int
+	rx, ry       int
+	patterns     []*jbig2Bitmap
+}
+
+// decodeHalftoneRegion implements the halftone region decoding procedure (6.6) using arithmetic coding.
+func decodeHalftoneRegion(ctx *jbig2Context, hr jbig2Halftone) *jbig2Bitmap {
+	bm := newJBIG2Bitmap(hr.w, hr.h)
+	if hr.defaultPixel != 0 {
+		bm.fill(1)
+	}
+
+	if len(hr.patterns) == 0 {
+		return bm
+	}
+
+	pw, ph := hr.patterns[0].w, hr.patterns[0].h
+
+	gridX := func(m, n int) int { return (hr.gx + m*hr.ry + n*hr.rx) >> 8 }
+	gridY := func(m, n int) int { return (hr.gy + m*hr.rx - n*hr.ry) >> 8 }
+
+	var skip *jbig2Bitmap
+	if hr.skip {
+		skip = newJBIG2Bitmap(hr.gw, hr.gh)
+		for m := 0; m < hr.gh; m++ {
+			for n := 0; n < hr.gw; n++ {
+				x, y := gridX(m, n), gridY(m, n)
+				if x+pw <= 0 || x >= hr.w || y+ph <= 0 || y >= hr.h {
+					skip.rows[m][n] = 1
+				}
+			}
+		}
+	}
+
+	at := []atPixel{{3, -1}}
+	if hr.template > 1 {
+		at[0].x = 2
+	}
+	if hr.template == 0 {
+		at = append(at, atPixel{-3, -1}, atPixel{2, -2}, atPixel{-2, -2})
+	}
+
+	// Gray-scale image decoding (Annex C.5)
+	bpp := log2Ceil(len(hr.patterns))
+	planes := make([]*jbig2Bitmap, bpp)
+	for j := bpp - 1; j >= 0; j-- {
+		planes[j] = decodeGenericRegion(ctx, hr.gw, hr.gh, hr.template, false, skip, at)
+		if j < bpp-1 {
+			for y, row := range planes[j].rows {
+				for x := range row {
+					row[x] ^= planes[j+1].rows[y][x]
+				}
+			}
+		}
+	}
+
+	for m := 0; m < hr.gh; m++ {
+		for n := 0; n < hr.gw; n++ {
+			v := 0
+			for j := bpp - 1; j >= 0; j-- {
+				v = v<<1 | int(planes[j].rows[m][n])
+			}
+			if v >= len(hr.patterns) {
+				v = len(hr.patterns) - 1
+			}
+			bm.combine(hr.patterns[v], gridX(m, n), gridY(m, n), hr.op)
+		}
+	}
+
+	return bm
+}
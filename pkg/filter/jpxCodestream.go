@@ -0,0 +1,1203 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// JPEG 2000 codestream markers (ITU-T T.800 Annex A).
+const (
+	jpxSOC = 0xFF4F
+	jpxSIZ = 0xFF51
+	jpxCOD = 0xFF52
+	jpxCOC = 0xFF53
+	jpxTLM = 0xFF55
+	jpxPLM = 0xFF57
+	jpxPLT = 0xFF58
+	jpxQCD = 0xFF5C
+	jpxQCC = 0xFF5D
+	jpxRGN = 0xFF5E
+	jpxPOC = 0xFF5F
+	jpxPPM = 0xFF60
+	jpxPPT = 0xFF61
+	jpxCRG = 0xFF63
+	jpxCOM = 0xFF64
+	jpxSOT = 0xFF90
+	jpxSOP = 0xFF91
+	jpxEPH = 0xFF92
+	jpxSOD = 0xFF93
+	jpxEOC = 0xFFD9
+)
+
+// Progression orders (Table A.16).
+const (
+	jpxLRCP = iota
+	jpxRLCP
+	jpxRPCL
+	jpxPCRL
+	jpxCPRL
+)
+
+// Subband types.
+const (
+	jpxLL = iota
+	jpxHL
+	jpxLH
+	jpxHH
+)
+
+// Code-block style flags (Table A.19).
+const (
+	jpxBypass         = 0x01
+	jpxReset          = 0x02
+	jpxTermAll        = 0x04
+	jpxVerticalCausal = 0x08
+	jpxSegSymbols     = 0x20
+)
+
+type jpxComponentInfo struct {
+	precision int
+	signed    bool
+	dx, dy    int
+}
+
+type jpxSize struct {
+	x1, y1, x0, y0   int
+	tw, th, tx0, ty0 int
+	comps            []jpxComponentInfo
+}
+
+// jpxCompCoding holds the component specific coding style parameters of COD and COC.
+type jpxCompCoding struct {
+	precincts  bool
+	levels     int
+	xcb, ycb   int
+	cbStyle    int
+	reversible bool
+	ppx, ppy   []int
+}
+
+type jpxCoding struct {
+	sop, eph    bool
+	progression int
+	layers      int
+	mct         bool
+	comp        jpxCompCoding
+}
+
+type jpxStep struct {
+	exp, mant int
+}
+
+type jpxQuant struct {
+	style int
+	guard int
+	steps []jpxStep
+}
+
+type jpxSegment struct {
+	data      []byte
+	passes    int
+	maxPasses int
+	raw       bool
+}
+
+type jpxCodeBlock struct {
+	x0, y0, x1, y1 int
+	included       bool
+	lblock         int
+	zeroPlanes     int
+	passes         int
+	segs           []*jpxSegment
+}
+
+type jpxPrecinctBand struct {
+	cw, ch int
+	blocks []*jpxCodeBlock
+	incl   *tagTree
+	zbp    *tagTree
+}
+
+type jpxBand struct {
+	typ            int
+	x0, y0, x1, y1 int
+	mb             int
+	delta          float64
+	precincts      []*jpxPrecinctBand
+	coeffs         []float32
+}
+
+type jpxResolution struct {
+	r              int
+	x0, y0, x1, y1 int
+	ppx, ppy       int
+	pw, ph         int
+	bands          []*jpxBand
+}
+
+type jpxTileComp struct {
+	x0, y0, x1, y1 int
+	cc             *jpxCompCoding
+	q              *jpxQuant
+	res            []*jpxResolution
+	data           []float32
+}
+
+type jpxTile struct {
+	index          int
+	x0, y0, x1, y1 int
+	coding         *jpxCoding
+	compCoding     map[int]*jpxCompCoding
+	quant          *jpxQuant
+	compQuant      map[int]*jpxQuant
+	data           []byte
+	parts          int
+	comps          []*jpxTileComp
+}
+
+type jpxCodestream struct {
+	siz        jpxSize
+	coding     *jpxCoding
+	compCoding map[int]*jpxCompCoding
+	quant      *jpxQuant
+	compQuant  map[int]*jpxQuant
+	tiles      map[int]*jpxTile
+	tileOrder  []int
+}
+
+type jpxReader struct {
+	bb  []byte
+	pos int
+}
+
+func (r *jpxReader) need(n int) error {
+	if n < 0 || r.pos+n > len(r.bb) {
+		return errors.New("pdfcpu: jpx: unexpected end of data")
+	}
+	return nil
+}
+
+func (r *jpxReader) u8() (int, error) {
+	if err := r.need(1); err != nil {
+		return 0, err
+	}
+	r.pos++
+	return int(r.bb[r.pos-1]), nil
+}
+
+func (r *jpxReader) u16() (int, error) {
+	if err := r.need(2); err != nil {
+		return 0, err
+	}
+	r.pos += 2
+	return int(binary.BigEndian.Uint16(r.bb[r.pos-2:])), nil
+}
+
+func (r *jpxReader) u32() (int, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	r.pos += 4
+	return int(binary.BigEndian.Uint32(r.bb[r.pos-4:])), nil
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func floorDiv(a, b int) int {
+	return a / b
+}
+
+// parseCodestream parses a JPEG 2000 codestream (Annex A).
+func parseCodestream(bb []byte) (*jpxCodestream, error) {
+	r := &jpxReader{bb: bb}
+
+	m, err := r.u16()
+	if err != nil {
+		return nil, err
+	}
+	if m != jpxSOC {
+		return nil, errors.New("pdfcpu: jpx: missing SOC marker")
+	}
+
+	cs := &jpxCodestream{
+		compCoding: map[int]*jpxCompCoding{},
+		compQuant:  map[int]*jpxQuant{},
+		tiles:      map[int]*jpxTile{},
+	}
+
+	for r.pos < len(bb) {
+		m, err := r.u16()
+		if err != nil {
+			return nil, err
+		}
+
+		if m == jpxEOC {
+			break
+		}
+
+		start := r.pos
+		l, err := r.u16()
+		if err != nil {
+			return nil, err
+		}
+		if err := r.need(l - 2); err != nil {
+			return nil, err
+		}
+		seg := &jpxReader{bb: bb[start+2 : start+l]}
+		r.pos = start + l
+
+		switch m {
+
+		case jpxSIZ:
+			if err := cs.parseSIZ(seg); err != nil {
+				return nil, err
+			}
+
+		case jpxCOD:
+			if cs.coding, err = parseCOD(seg); err != nil {
+				return nil, err
+			}
+
+		case jpxCOC:
+			i, cc, err := parseCOC(seg, len(cs.siz.comps))
+			if err != nil {
+				return nil, err
+			}
+			cs.compCoding[i] = cc
+
+		case jpxQCD:
+			if cs.quant, err = parseQuant(seg); err != nil {
+				return nil, err
+			}
+
+		case jpxQCC:
+			i, err := compIndex(seg, len(cs.siz.comps))
+			if err != nil {
+				return nil, err
+			}
+			q, err := parseQuant(seg)
+			if err != nil {
+				return nil, err
+			}
+			cs.compQuant[i] = q
+
+		case jpxSOT:
+			if len(cs.siz.comps) == 0 || cs.coding == nil || cs.quant == nil {
+				return nil, errors.New("pdfcpu: jpx: missing main header markers")
+			}
+			if err := cs.parseTilePart(r, seg, start-2); err != nil {
+				return nil, err
+			}
+
+		case jpxPOC:
+			return nil, errors.New("pdfcpu: jpx: progression order changes not supported")
+
+		case jpxPPM:
+			return nil, errors.New("pdfcpu: jpx: packed packet headers not supported")
+
+		case jpxRGN:
+			return nil, errors.New("pdfcpu: jpx: regions of interest not supported")
+
+		case jpxTLM, jpxPLM, jpxCRG, jpxCOM:
+			// Informational only.
+
+		default:
+			return nil, errors.Errorf("pdfcpu: jpx: unexpected marker 0x%04X", m)
+		}
+	}
+
+	if len(cs.siz.comps) == 0 || cs.coding == nil || cs.quant == nil {
+		return nil, errors.New("pdfcpu: jpx: missing main header markers")
+	}
+
+	return cs, nil
+}
+
+func (cs *jpxCodestream) parseSIZ(r *jpxReader) error {
+	if _, err := r.u16(); err != nil { // Rsiz
+		return err
+	}
+	var v [8]int
+	for i := range v {
+		u, err := r.u32()
+		if err != nil {
+			return err
+		}
+		v[i] = u
+	}
+	s := jpxSize{x1: v[0], y1: v[1], x0: v[2], y0: v[3], tw: v[4], th: v[5], tx0: v[6], ty0: v[7]}
+	if s.x1 <= s.x0 || s.y1 <= s.y0 || s.tw == 0 || s.th == 0 {
+		return errors.New("pdfcpu: jpx: invalid image size")
+	}
+	if (s.x1-s.x0) > 1<<16 || (s.y1-s.y0) > 1<<16 {
+		return errors.Errorf("pdfcpu: jpx: image too large: %dx%d", s.x1-s.x0, s.y1-s.y0)
+	}
+
+	n, err := r.u16()
+	if err != nil {
+		return err
+	}
+	if n == 0 || n > 16384 {
+		return errors.New("pdfcpu: jpx: invalid component count")
+	}
+
+	for i := 0; i < n; i++ {
+		ssiz, err := r.u8()
+		if err != nil {
+			return err
+		}
+		dx, err := r.u8()
+		if err != nil {
+			return err
+		}
+		dy, err := r.u8()
+		if err != nil {
+			return err
+		}
+		if dx == 0 || dy == 0 {
+			return errors.New("pdfcpu: jpx: invalid component subsampling")
+		}
+		s.comps = append(s.comps, jpxComponentInfo{precision: ssiz&0x7F + 1, signed: ssiz&0x80 != 0, dx: dx, dy: dy})
+	}
+
+	cs.siz = s
+	return nil
+}
+
+func parseSPcod(r *jpxReader, precincts bool) (*jpxCompCoding, error) {
+	cc := &jpxCompCoding{precincts: precincts}
+
+	var err error
+	if cc.levels, err = r.u8(); err != nil {
+		return nil, err
+	}
+	if cc.levels > 32 {
+		return nil, errors.New("pdfcpu: jpx: invalid decomposition levels")
+	}
+	if cc.xcb, err = r.u8(); err != nil {
+		return nil, err
+	}
+	if cc.ycb, err = r.u8(); err != nil {
+		return nil, err
+	}
+	cc.xcb = cc.xcb&0x0F + 2
+	cc.ycb = cc.ycb&0x0F + 2
+	if cc.xcb+cc.ycb > 12 {
+		return nil, errors.New("pdfcpu: jpx: invalid code-block size")
+	}
+	if cc.cbStyle, err = r.u8(); err != nil {
+		return nil, err
+	}
+	t, err := r.u8()
+	if err != nil {
+		return nil, err
+	}
+	cc.reversible = t == 1
+
+	for i := 0; i <= cc.levels; i++ {
+		pp := 0xFF
+		if precincts {
+			if pp, err = r.u8(); err != nil {
+				return nil, err
+			}
+		}
+		cc.ppx = append(cc.ppx, pp&0x0F)
+		cc.ppy = append(cc.ppy, pp>>4)
+	}
+
+	return cc, nil
+}
+
+func parseCOD(r *jpxReader) (*jpxCoding, error) {
+	scod, err := r.u8()
+	if err != nil {
+		return nil, err
+	}
+	c := &jpxCoding{sop: scod&0x02 != 0, eph: scod&0x04 != 0}
+	if c.progression, err = r.u8(); err != nil {
+		return nil, err
+	}
+	if c.progression > jpxCPRL {
+		return nil, errors.New("pdfcpu: jpx: invalid progression order")
+	}
+	if c.layers, err = r.u16(); err != nil {
+		return nil, err
+	}
+	if c.layers == 0 {
+		return nil, errors.New("pdfcpu: jpx: invalid number of layers")
+	}
+	mct, err := r.u8()
+	if err != nil {
+		return nil, err
+	}
+	c.mct = mct == 1
+	cc, err := parseSPcod(r, scod&0x01 != 0)
+	if err != nil {
+		return nil, err
+	}
+	c.comp = *cc
+	return c, nil
+}
+
+func compIndex(r *jpxReader, n int) (int, error) {
+	if n < 257 {
+		return r.u8()
+	}
+	return r.u16()
+}
+
+func parseCOC(r *jpxReader, n int) (int, *jpxCompCoding, error) {
+	i, err := compIndex(r, n)
+	if err != nil {
+		return 0, nil, err
+	}
+	scoc, err := r.u8()
+	if err != nil {
+		return 0, nil, err
+	}
+	cc, err := parseSPcod(r, scoc&0x01 != 0)
+	return i, cc, err
+}
+
+func parseQuant(r *jpxReader) (*jpxQuant, error) {
+	s, err := r.u8()
+	if err != nil {
+		return nil, err
+	}
+	q := &jpxQuant{style: s & 0x1F, guard: s >> 5}
+	for r.pos < len(r.bb) {
+		if q.style == 0 {
+			v, err := r.u8()
+			if err != nil {
+				return nil, err
+			}
+			q.steps = append(q.steps, jpxStep{exp: v >> 3})
+			continue
+		}
+		v, err := r.u16()
+		if err != nil {
+			return nil, err
+		}
+		q.steps = append(q.steps, jpxStep{exp: v >> 11, mant: v & 0x7FF})
+	}
+	if len(q.steps) == 0 {
+		return nil, errors.New("pdfcpu: jpx: missing quantization step sizes")
+	}
+	return q, nil
+}
+
+// parseTilePart parses a tile-part header starting at SOT and collects the tile-part data.
+func (cs *jpxCodestream) parseTilePart(r *jpxReader, sot *jpxReader, sotPos int) error {
+	isot, err := sot.u16()
+	if err != nil {
+		return err
+	}
+	psot, err := sot.u32()
+	if err != nil {
+		return err
+	}
+
+	numX := ceilDiv(cs.siz.x1-cs.siz.tx0, cs.siz.tw)
+	numY := ceilDiv(cs.siz.y1-cs.siz.ty0, cs.siz.th)
+	if isot >= numX*numY {
+		return errors.Errorf("pdfcpu: jpx: invalid tile index %d", isot)
+	}
+
+	tile, ok := cs.tiles[isot]
+	if !ok {
+		tile = &jpxTile{index: isot, compCoding: map[int]*jpxCompCoding{}, compQuant: map[int]*jpxQuant{}}
+		cs.tiles[isot] = tile
+		cs.tileOrder = append(cs.tileOrder, isot)
+	}
+	tile.parts++
+
+	end := len(r.bb)
+	if psot != 0 {
+		end = sotPos + psot
+		if end > len(r.bb) {
+			end = len(r.bb)
+		}
+	}
+
+	// Tile-part header
+	for {
+		m, err := r.u16()
+		if err != nil {
+			return err
+		}
+		if m == jpxSOD {
+			break
+		}
+		start := r.pos
+		l, err := r.u16()
+		if err != nil {
+			return err
+		}
+		if err := r.need(l - 2); err != nil {
+			return err
+		}
+		seg := &jpxReader{bb: r.bb[start+2 : start+l]}
+		r.pos = start + l
+
+		if tile.parts > 1 {
+			// Coding parameters may only be specified in the first tile-part.
+			continue
+		}
+
+		switch m {
+		case jpxCOD:
+			if tile.coding, err = parseCOD(seg); err != nil {
+				return err
+			}
+		case jpxCOC:
+			i, cc, err := parseCOC(seg, len(cs.siz.comps))
+			if err != nil {
+				return err
+			}
+			tile.compCoding[i] = cc
+		case jpxQCD:
+			if tile.quant, err = parseQuant(seg); err != nil {
+				return err
+			}
+		case jpxQCC:
+			i, err := compIndex(seg, len(cs.siz.comps))
+			if err != nil {
+				return err
+			}
+			q, err := parseQuant(seg)
+			if err != nil {
+				return err
+			}
+			tile.compQuant[i] = q
+		case jpxPOC:
+			return errors.New("pdfcpu: jpx: progression order changes not supported")
+		case jpxPPT:
+			return errors.New("pdfcpu: jpx: packed packet headers not supported")
+		case jpxRGN:
+			return errors.New("pdfcpu: jpx: regions of interest not supported")
+		}
+	}
+
+	if end < r.pos {
+		return errors.New("pdfcpu: jpx: corrupt tile-part length")
+	}
+
+	if psot == 0 {
+		// Last tile-part, data extends up to EOC.
+		if end-r.pos >= 2 && binary.BigEndian.Uint16(r.bb[end-2:]) == jpxEOC {
+			end -= 2
+		}
+	}
+
+	tile.data = append(tile.data, r.bb[r.pos:end]...)
+	r.pos = end
+
+	return nil
+}
+
+// tagTree implements the tag tree decoding procedure (B.10.2).
+type tagTree struct {
+	levels []tagTreeLevel
+}
+
+type tagTreeLevel struct {
+	w, h  int
+	value []int
+	low   []int
+}
+
+func newTagTree(w, h int) *tagTree {
+	t := &tagTree{}
+	for {
+		l := tagTreeLevel{w: w, h: h, value: make([]int, w*h), low: make([]int, w*h)}
+		for i := range l.value {
+			l.value[i] = math.MaxInt32
+		}
+		t.levels = append(t.levels, l)
+		if w <= 1 && h <= 1 {
+			break
+		}
+		w, h = (w+1)/2, (h+1)/2
+	}
+	return t
+}
+
+// decode returns true if the value of leaf x,y is below threshold.
+func (t *tagTree) decode(br *jpxBitReader, x, y, threshold int) (bool, error) {
+	low := 0
+	for lv := len(t.levels) - 1; lv >= 0; lv-- {
+		l := &t.levels[lv]
+		i := (y>>uint(lv))*l.w + x>>uint(lv)
+		if low > l.low[i] {
+			l.low[i] = low
+		} else {
+			low = l.low[i]
+		}
+		for low < threshold && low < l.value[i] {
+			b, err := br.bit()
+			if err != nil {
+				return false, err
+			}
+			if b == 1 {
+				l.value[i] = low
+			} else {
+				low++
+			}
+		}
+		l.low[i] = low
+	}
+	return t.levels[0].value[y*t.levels[0].w+x] < threshold, nil
+}
+
+// value decodes the complete value of leaf x,y.
+func (t *tagTree) value(br *jpxBitReader, x, y int) (int, error) {
+	for threshold := 1; ; threshold++ {
+		ok, err := t.decode(br, x, y, threshold)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return threshold - 1, nil
+		}
+		if threshold > 64 {
+			return 0, errors.New("pdfcpu: jpx: corrupt tag tree")
+		}
+	}
+}
+
+// jpxBitReader reads packet headers honouring bit stuffing (B.10.1).
+type jpxBitReader struct {
+	bb      []byte
+	pos     int
+	buf     uint32
+	n       int
+	stuffed bool
+}
+
+func (br *jpxBitReader) bit() (int, error) {
+	if br.n == 0 {
+		if br.pos >= len(br.bb) {
+			return 0, errors.New("pdfcpu: jpx: unexpected end of packet data")
+		}
+		b := br.bb[br.pos]
+		br.pos++
+		br.buf, br.n = uint32(b), 8
+		if br.stuffed {
+			br.n = 7
+		}
+		br.stuffed = b == 0xFF
+	}
+	br.n--
+	return int(br.buf>>uint(br.n)) & 1, nil
+}
+
+func (br *jpxBitReader) bits(n int) (int, error) {
+	v := 0
+	for i := 0; i < n; i++ {
+		b, err := br.bit()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<1 | b
+	}
+	return v, nil
+}
+
+func (br *jpxBitReader) align() {
+	br.n = 0
+	if br.stuffed {
+		br.pos++
+		br.stuffed = false
+	}
+}
+
+func (br *jpxBitReader) marker(m int) bool {
+	return br.pos+1 < len(br.bb) && int(binary.BigEndian.Uint16(br.bb[br.pos:])) == m
+}
+
+func (cs *jpxCodestream) tileCoding(t *jpxTile) *jpxCoding {
+	if t.coding != nil {
+		return t.coding
+	}
+	return cs.coding
+}
+
+func (cs *jpxCodestream) compCodingFor(t *jpxTile, c int) *jpxCompCoding {
+	if cc, ok := t.compCoding[c]; ok {
+		return cc
+	}
+	if t.coding != nil {
+		return &t.coding.comp
+	}
+	if cc, ok := cs.compCoding[c]; ok {
+		return cc
+	}
+	return &cs.coding.comp
+}
+
+func (cs *jpxCodestream) quantFor(t *jpxTile, c int) *jpxQuant {
+	if q, ok := t.compQuant[c]; ok {
+		return q
+	}
+	if t.quant != nil {
+		return t.quant
+	}
+	if q, ok := cs.compQuant[c]; ok {
+		return q
+	}
+	return cs.quant
+}
+
+// initTile computes the tile, tile-component, resolution, subband, precinct and code-block geometry (Annex B).
+func (cs *jpxCodestream) initTile(t *jpxTile) error {
+	s := cs.siz
+	numX := ceilDiv(s.x1-s.tx0, s.tw)
+	p, q := t.index%numX, t.index/numX
+
+	t.x0 = max(s.tx0+p*s.tw, s.x0)
+	t.y0 = max(s.ty0+q*s.th, s.y0)
+	t.x1 = min(s.tx0+(p+1)*s.tw, s.x1)
+	t.y1 = min(s.ty0+(q+1)*s.th, s.y1)
+
+	for c, ci := range s.comps {
+		tc := &jpxTileComp{
+			x0: ceilDiv(t.x0, ci.dx),
+			y0: ceilDiv(t.y0, ci.dy),
+			x1: ceilDiv(t.x1, ci.dx),
+			y1: ceilDiv(t.y1, ci.dy),
+			cc: cs.compCodingFor(t, c),
+			q:  cs.quantFor(t, c),
+		}
+		if err := tc.initResolutions(ci.precision); err != nil {
+			return err
+		}
+		t.comps = append(t.comps, tc)
+	}
+
+	return nil
+}
+
+func (tc *jpxTileComp) initResolutions(precision int) error {
+	cc := tc.cc
+	nl := cc.levels
+
+	for r := 0; r <= nl; r++ {
+		scale := 1 << uint(nl-r)
+		res := &jpxResolution{
+			r:   r,
+			x0:  ceilDiv(tc.x0, scale),
+			y0:  ceilDiv(tc.y0, scale),
+			x1:  ceilDiv(tc.x1, scale),
+			y1:  ceilDiv(tc.y1, scale),
+			ppx: cc.ppx[r],
+			ppy: cc.ppy[r],
+		}
+
+		if res.x1 > res.x0 {
+			res.pw = ceilDiv(res.x1, 1<<uint(res.ppx)) - floorDiv(res.x0, 1<<uint(res.ppx))
+		}
+		if res.y1 > res.y0 {
+			res.ph = ceilDiv(res.y1, 1<<uint(res.ppy)) - floorDiv(res.y0, 1<<uint(res.ppy))
+		}
+
+		types := []int{jpxLL}
+		if r > 0 {
+			types = []int{jpxHL, jpxLH, jpxHH}
+		}
+
+		for _, typ := range types {
+			b, err := tc.initBand(res, typ, precision)
+			if err != nil {
+				return err
+			}
+			res.bands = append(res.bands, b)
+		}
+
+		tc.res = append(tc.res, res)
+	}
+
+	return nil
+}
+
+func (tc *jpxTileComp) initBand(res *jpxResolution, typ, precision int) (*jpxBand, error) {
+	cc := tc.cc
+	nl := cc.levels
+
+	b := &jpxBand{typ: typ}
+
+	if res.r == 0 {
+		scale := 1 << uint(nl)
+		b.x0, b.y0 = ceilDiv(tc.x0, scale), ceilDiv(tc.y0, scale)
+		b.x1, b.y1 = ceilDiv(tc.x1, scale), ceilDiv(tc.y1, scale)
+	} else {
+		nb := nl - res.r + 1
+		scale := 1 << uint(nb)
+		xo, yo := 0, 0
+		if typ == jpxHL || typ == jpxHH {
+			xo = 1
+		}
+		if typ == jpxLH || typ == jpxHH {
+			yo = 1
+		}
+		half := scale >> 1
+		b.x0 = ceilDiv(tc.x0-half*xo, scale)
+		b.y0 = ceilDiv(tc.y0-half*yo, scale)
+		b.x1 = ceilDiv(tc.x1-half*xo, scale)
+		b.y1 = ceilDiv(tc.y1-half*yo, scale)
+	}
+
+	b.coeffs = make([]float32, max(b.x1-b.x0, 0)*max(b.y1-b.y0, 0))
+
+	if err := tc.quantizeBand(b, res.r, precision); err != nil {
+		return nil, err
+	}
+
+	// Code-block dimensions (B.7)
+	xcb, ycb := cc.xcb, cc.ycb
+	ppx, ppy := res.ppx, res.ppy
+	if res.r > 0 {
+		ppx, ppy = ppx-1, ppy-1
+	}
+	xcb, ycb = min(xcb, ppx), min(ycb, ppy)
+
+	px0, py0 := floorDiv(res.x0, 1<<uint(res.ppx)), floorDiv(res.y0, 1<<uint(res.ppy))
+
+	for k := 0; k < res.pw*res.ph; k++ {
+		// Precinct area in subband coordinates
+		x0 := (px0 + k%res.pw) << uint(ppx)
+		y0 := (py0 + k/res.pw) << uint(ppy)
+		x1, y1 := x0+1<<uint(ppx), y0+1<<uint(ppy)
+		x0, y0 = max(x0, b.x0), max(y0, b.y0)
+		x1, y1 = min(x1, b.x1), min(y1, b.y1)
+
+		pb := &jpxPrecinctBand{}
+		if x1 > x0 && y1 > y0 {
+			cx0, cy0 := x0>>uint(xcb), y0>>uint(ycb)
+			cx1, cy1 := ceilDiv(x1, 1<<uint(xcb)), ceilDiv(y1, 1<<uint(ycb))
+			pb.cw, pb.ch = cx1-cx0, cy1-cy0
+			for cy := cy0; cy < cy1; cy++ {
+				for cx := cx0; cx < cx1; cx++ {
+					pb.blocks = append(pb.blocks, &jpxCodeBlock{
+						x0: max(cx<<uint(xcb), x0),
+						y0: max(cy<<uint(ycb), y0),
+						x1: min((cx+1)<<uint(xcb), x1),
+						y1: min((cy+1)<<uint(ycb), y1),
+					})
+				}
+			}
+			pb.incl = newTagTree(pb.cw, pb.ch)
+			pb.zbp = newTagTree(pb.cw, pb.ch)
+		}
+		b.precincts = append(b.precincts, pb)
+	}
+
+	return b, nil
+}
+
+// quantizeBand determines the number of magnitude bitplanes and the quantization step of b (Annex E).
+func (tc *jpxTileComp) quantizeBand(b *jpxBand, r, precision int) error {
+	q := tc.q
+	nl := tc.cc.levels
+
+	i := 0
+	if r > 0 {
+		i = 1 + 3*(r-1) + b.typ - 1
+	}
+
+	var st jpxStep
+	switch q.style {
+	case 1:
+		// Scalar derived
+		nb := nl
+		if r > 0 {
+			nb = nl - r + 1
+		}
+		st = jpxStep{exp: q.steps[0].exp - nl + nb, mant: q.steps[0].mant}
+	default:
+		if i >= len(q.steps) {
+			return errors.New("pdfcpu: jpx: missing quantization step size")
+		}
+		st = q.steps[i]
+	}
+
+	b.mb = q.guard + st.exp - 1
+	if b.mb > 31 {
+		return errors.New("pdfcpu: jpx: too many bitplanes")
+	}
+
+	b.delta = 1
+	if !tc.cc.reversible {
+		gain := 0
+		switch b.typ {
+		case jpxHL, jpxLH:
+			gain = 1
+		case jpxHH:
+			gain = 2
+		}
+		b.delta = math.Ldexp(1+float64(st.mant)/2048, precision+gain-st.exp)
+	}
+
+	return nil
+}
+
+type jpxPacket struct {
+	layer, r, c, k int
+	x, y           int
+}
+
+// packets returns the packets of t in progression order (B.12).
+func (cs *jpxCodestream) packets(t *jpxTile) []jpxPacket {
+	coding := cs.tileCoding(t)
+
+	var pp []jpxPacket
+
+	for c, tc := range t.comps {
+		ci := cs.siz.comps[c]
+		nl := tc.cc.levels
+		for r, res := range tc.res {
+			px0, py0 := floorDiv(res.x0, 1<<uint(res.ppx)), floorDiv(res.y0, 1<<uint(res.ppy))
+			for k := 0; k < res.pw*res.ph; k++ {
+				// Precinct origin projected onto the reference grid.
+				x := ((px0 + k%res.pw) << uint(res.ppx+nl-r)) * ci.dx
+				y := ((py0 + k/res.pw) << uint(res.ppy+nl-r)) * ci.dy
+				x, y = max(x, t.x0), max(y, t.y0)
+				for l := 0; l < coding.layers; l++ {
+					pp = append(pp, jpxPacket{layer: l, r: r, c: c, k: k, x: x, y: y})
+				}
+			}
+		}
+	}
+
+	key := func(p jpxPacket) []int {
+		switch coding.progression {
+		case jpxRLCP:
+			return []int{p.r, p.layer, p.c, p.k}
+		case jpxRPCL:
+			return []int{p.r, p.y, p.x, p.c, p.k, p.layer}
+		case jpxPCRL:
+			return []int{p.y, p.x, p.c, p.r, p.k, p.layer}
+		case jpxCPRL:
+			return []int{p.c, p.y, p.x, p.r, p.k, p.layer}
+		}
+		return []int{p.layer, p.r, p.c, p.k}
+	}
+
+	sort.SliceStable(pp, func(i, j int) bool {
+		a, b := key(pp[i]), key(pp[j])
+		for n := range a {
+			if a[n] != b[n] {
+				return a[n] < b[n]
+			}
+		}
+		return false
+	})
+
+	return pp
+}
+
+// segmentMaxPasses returns the number of coding passes of a codeword segment starting at pass (Table D.8).
+func segmentMaxPasses(pass, style int) (int, bool) {
+	if style&jpxTermAll != 0 {
+		return 1, style&jpxBypass != 0 && pass >= 10 && (pass-10)%3 != 2
+	}
+	if style&jpxBypass != 0 {
+		if pass < 10 {
+			return 10 - pass, false
+		}
+		if (pass-10)%3 == 2 {
+			return 1, false
+		}
+		return 2 - (pass-10)%3, true
+	}
+	return math.MaxInt32, false
+}
+
+func numPasses(br *jpxBitReader) (int, error) {
+	b, err := br.bit()
+	if err != nil || b == 0 {
+		return 1, err
+	}
+	if b, err = br.bit(); err != nil || b == 0 {
+		return 2, err
+	}
+	v, err := br.bits(2)
+	if err != nil || v < 3 {
+		return 3 + v, err
+	}
+	if v, err = br.bits(5); err != nil || v < 31 {
+		return 6 + v, err
+	}
+	v, err = br.bits(7)
+	return 37 + v, err
+}
+
+type jpxContribution struct {
+	cb      *jpxCodeBlock
+	lengths []int
+}
+
+// decodePackets decodes all packets of t (B.9, B.10).
+func (cs *jpxCodestream) decodePackets(t *jpxTile) error {
+	coding := cs.tileCoding(t)
+	br := &jpxBitReader{bb: t.data}
+
+	for _, p := range cs.packets(t) {
+		if br.pos >= len(br.bb) {
+			// Truncated codestream, decode what we have.
+			break
+		}
+
+		tc := t.comps[p.c]
+		res := tc.res[p.r]
+
+		if coding.sop && br.marker(jpxSOP) {
+			br.pos += 6
+		}
+
+		nonEmpty, err := br.bit()
+		if err != nil {
+			return err
+		}
+
+		var cc []jpxContribution
+
+		if nonEmpty == 1 {
+			for _, b := range res.bands {
+				pb := b.precincts[p.k]
+				for i, cb := range pb.blocks {
+					contrib, err := decodeContribution(br, pb, cb, i, p.layer, b.mb, tc.cc.cbStyle)
+					if err != nil {
+						return err
+					}
+					if contrib != nil {
+						cc = append(cc, *contrib)
+					}
+				}
+			}
+		}
+
+		br.align()
+
+		if coding.eph && br.marker(jpxEPH) {
+			br.pos += 2
+		}
+
+		// Packet body
+		for _, c := range cc {
+			for i, l := range c.lengths {
+				if br.pos+l > len(br.bb) {
+					l = len(br.bb) - br.pos
+				}
+				seg := c.cb.segs[len(c.cb.segs)-len(c.lengths)+i]
+				seg.data = append(seg.data, br.bb[br.pos:br.pos+l]...)
+				br.pos += l
+			}
+		}
+	}
+
+	return nil
+}
+
+func decodeContribution(br *jpxBitReader, pb *jpxPrecinctBand, cb *jpxCodeBlock, i, layer, mb, style int) (*jpxContribution, error) {
+	x, y := i%pb.cw, i/pb.cw
+
+	var (
+		included bool
+		err      error
+	)
+
+	if !cb.included {
+		if included, err = pb.incl.decode(br, x, y, layer+1); err != nil {
+			return nil, err
+		}
+	} else {
+		b, err := br.bit()
+		if err != nil {
+			return nil, err
+		}
+		included = b == 1
+	}
+
+	if !included {
+		return nil, nil
+	}
+
+	if !cb.included {
+		zbp, err := pb.zbp.value(br, x, y)
+		if err != nil {
+			return nil, err
+		}
+		if zbp > mb {
+			return nil, errors.New("pdfcpu: jpx: corrupt zero bitplanes")
+		}
+		cb.included, cb.zeroPlanes, cb.lblock = true, zbp, 3
+	}
+
+	n, err := numPasses(br)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		b, err := br.bit()
+		if err != nil {
+			return nil, err
+		}
+		if b == 0 {
+			break
+		}
+		cb.lblock++
+	}
+
+	contrib := &jpxContribution{cb: cb}
+
+	pass := cb.passes
+	cb.passes += n
+
+	for n > 0 {
+		var seg *jpxSegment
+		if len(cb.segs) > 0 {
+			if last := cb.segs[len(cb.segs)-1]; last.passes < last.maxPasses {
+				seg = last
+			}
+		}
+		if seg == nil {
+			max, raw := segmentMaxPasses(pass, style)
+			seg = &jpxSegment{maxPasses: max, raw: raw}
+			cb.segs = append(cb.segs, seg)
+		}
+		m := min(n, seg.maxPasses-seg.passes)
+		l, err := br.bits(cb.lblock + int(math.Log2(float64(m))))
+		if err != nil {
+			return nil, err
+		}
+		seg.passes += m
+		pass += m
+		n -= m
+		contrib.lengths = append(contrib.lengths, l)
+	}
+
+	// Segments receiving data within this packet are the last len(lengths) segments.
+	return contrib, nil
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type jpxDecode struct {
+	baseFilter
+}
+
+// JPXImage represents a decoded JPEG 2000 image.
+type JPXImage struct {
+	Width, Height int
+	NumComps      int    // Number of colour components.
+	ColorSpace    string // Colour space signalled by the JP2 header: DeviceGray, DeviceRGB, DeviceCMYK or empty.
+	ICCProfile    []byte // ICC profile signalled by the JP2 header.
+	Samples       []byte // Interleaved 8 bit colour samples.
+	Alpha         []byte // 8 bit opacity channel, if present.
+}
+
+// Encode implements encoding for a JPXDecode filter.
+func (f jpxDecode) Encode(r io.Reader) (io.Reader, error) {
+	return nil, errors.New("pdfcpu: JPXDecode: encoding not supported")
+}
+
+// Decode implements decoding for a JPXDecode filter.
+func (f jpxDecode) Decode(r io.Reader) (io.Reader, error) {
+	return f.DecodeLength(r, -1)
+}
+
+// DecodeLength decodes a JPEG 2000 codestream or JP2 file into interleaved 8 bit colour samples.
+// Opacity channels are not part of the result, see DecodeJPX.
+func (f jpxDecode) DecodeLength(r io.Reader, maxLen int64) (io.Reader, error) {
+	if log.TraceEnabled() {
+		log.Trace.Println("DecodeJPX begin")
+	}
+
+	img, err := DecodeJPX(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if log.TraceEnabled() {
+		log.Trace.Printf("DecodeJPX: decoded %d bytes.\n", len(img.Samples))
+	}
+
+	return bytes.NewBuffer(img.Samples), nil
+}
+
+// DecodeJPX decodes a JPEG 2000 codestream or JP2 file including colour space information and opacity.
+func DecodeJPX(r io.Reader) (*JPXImage, error) {
+	bb, err := getReaderBytes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := &jp2Header{}
+
+	if len(bb) < 2 || binary.BigEndian.Uint16(bb) != jpxSOC {
+		if bb, err = hdr.parse(bb); err != nil {
+			return nil, err
+		}
+	}
+
+	cs, err := parseCodestream(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	chans, err := cs.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if chans, err = hdr.applyPalette(chans); err != nil {
+		return nil, err
+	}
+
+	img := &JPXImage{
+		Width:      cs.siz.x1 - cs.siz.x0,
+		Height:     cs.siz.y1 - cs.siz.y0,
+		ICCProfile: hdr.icc,
+	}
+
+	n := 0
+	switch hdr.enumCS {
+	case jp2CSsRGB, jp2CSeRGB, jp2CSROMM, jp2CSsYCC, jp2CSesYCC:
+		img.ColorSpace, n = "DeviceRGB", 3
+	case jp2CSGray:
+		img.ColorSpace, n = "DeviceGray", 1
+	case jp2CSCMYK:
+		img.ColorSpace, n = "DeviceCMYK", 4
+	}
+
+	colour, alpha := hdr.channels(chans, n)
+	if len(colour) == 0 {
+		return nil, errors.New("pdfcpu: jpx: missing colour channels")
+	}
+
+	if (hdr.enumCS == jp2CSsYCC || hdr.enumCS == jp2CSesYCC) && len(colour) == 3 {
+		yccToRGB(colour)
+	}
+
+	img.NumComps = len(colour)
+	img.Samples = make([]byte, img.Width*img.Height*img.NumComps)
+	for c, ch := range colour {
+		for i, v := range ch.data {
+			img.Samples[i*img.NumComps+c] = ch.to8(v)
+		}
+	}
+
+	if alpha != nil {
+		img.Alpha = make([]byte, img.Width*img.Height)
+		for i, v := range alpha.data {
+			img.Alpha[i] = alpha.to8(v)
+		}
+	}
+
+	return img, nil
+}
+
+// jpxChannel is a decoded component of the image size.
+type jpxChannel struct {
+	precision int
+	data      []int32
+}
+
+func (ch jpxChannel) to8(v int32) byte {
+	switch {
+	case ch.precision > 8:
+		return byte(v >> uint(ch.precision-8))
+	case ch.precision < 8:
+		return byte(int(v) * 255 / (1<<uint(ch.precision) - 1))
+	}
+	return byte(v)
+}
+
+func yccToRGB(chans []jpxChannel) {
+	maxVal := float64(int32(1)<<uint(chans[0].precision) - 1)
+	half := float64(int32(1) << uint(chans[0].precision-1))
+	clamp := func(v float64) int32 { return int32(math.Round(math.Max(0, math.Min(maxVal, v)))) }
+	for i := range chans[0].data {
+		y, cb, cr := float64(chans[0].data[i]), float64(chans[1].data[i])-half, float64(chans[2].data[i])-half
+		chans[0].data[i] = clamp(y + 1.402*cr)
+		chans[1].data[i] = clamp(y - 0.344136*cb - 0.714136*cr)
+		chans[2].data[i] = clamp(y + 1.772*cb)
+	}
+}
+
+// decode decodes all tiles into channels covering the image area.
+func (cs *jpxCodestream) decode() ([]jpxChannel, error) {
+	s := cs.siz
+	w, h := s.x1-s.x0, s.y1-s.y0
+
+	chans := make([]jpxChannel, len(s.comps))
+	for c, ci := range s.comps {
+		chans[c] = jpxChannel{precision: ci.precision, data: make([]int32, w*h)}
+	}
+
+	for _, i := range cs.tileOrder {
+		t := cs.tiles[i]
+
+		if err := cs.initTile(t); err != nil {
+			return nil, err
+		}
+
+		if err := cs.decodePackets(t); err != nil {
+			return nil, err
+		}
+
+		for _, tc := range t.comps {
+			for _, res := range tc.res {
+				for _, b := range res.bands {
+					for _, pb := range b.precincts {
+						for _, cb := range pb.blocks {
+							decodeCodeBlock(b, cb, tc.cc.cbStyle, tc.cc.reversible)
+						}
+					}
+				}
+			}
+			tc.reconstruct()
+		}
+
+		if cs.tileCoding(t).mct && len(t.comps) >= 3 {
+			inverseComponentTransform(t.comps)
+		}
+
+		for c, tc := range t.comps {
+			ci := s.comps[c]
+			maxVal := int32(1)<<uint(ci.precision) - 1
+			shift := float64(int32(1) << uint(ci.precision-1))
+			tw := tc.x1 - tc.x0
+			if tw <= 0 || tc.y1 <= tc.y0 {
+				continue
+			}
+			for y := t.y0; y < t.y1; y++ {
+				cy := min(max(y/ci.dy, tc.y0), tc.y1-1) - tc.y0
+				for x := t.x0; x < t.x1; x++ {
+					cx := min(max(x/ci.dx, tc.x0), tc.x1-1) - tc.x0
+					v := int32(math.Round(float64(tc.data[cy*tw+cx]) + shift))
+					if v < 0 {
+						v = 0
+					} else if v > maxVal {
+						v = maxVal
+					}
+					chans[c].data[(y-s.y0)*w+x-s.x0] = v
+				}
+			}
+		}
+
+		// Release tile memory.
+		t.comps, t.data = nil, nil
+	}
+
+	return chans, nil
+}
+
+// JP2 box types (ISO/IEC 15444-1 Annex I).
+const (
+	jp2BoxHeader     = 0x6A703268 // jp2h
+	jp2BoxCodestream = 0x6A703263 // jp2c
+	jp2BoxColour     = 0x636F6C72 // colr
+	jp2BoxPalette    = 0x70636C72 // pclr
+	jp2BoxCompMap    = 0x636D6170 // cmap
+	jp2BoxChanDef    = 0x63646566 // cdef
+)
+
+// Enumerated colour spaces (Table I.10 and ISO/IEC 15444-2).
+const (
+	jp2CSCMYK  = 12
+	jp2CSsRGB  = 16
+	jp2CSGray  = 17
+	jp2CSsYCC  = 18
+	jp2CSeRGB  = 20
+	jp2CSROMM  = 21
+	jp2CSesYCC = 24
+)
+
+type jp2Palette struct {
+	depths  []int
+	entries [][]int32
+}
+
+type jp2CompMapping struct {
+	cmp, mtyp, pcol int
+}
+
+type jp2ChanDef struct {
+	cn, typ, asoc int
+}
+
+type jp2Header struct {
+	enumCS  int
+	icc     []byte
+	colr    bool
+	palette *jp2Palette
+	cmap    []jp2CompMapping
+	cdef    []jp2ChanDef
+}
+
+func jp2Boxes(bb []byte, f func(typ uint32, content []byte) error) error {
+	for pos := 0; pos+8 <= len(bb); {
+		l := int(binary.BigEndian.Uint32(bb[pos:]))
+		typ := binary.BigEndian.Uint32(bb[pos+4:])
+		hl := 8
+		switch l {
+		case 0:
+			l = len(bb) - pos
+		case 1:
+			if pos+16 > len(bb) {
+				return errors.New("pdfcpu: jpx: corrupt box header")
+			}
+			xl := binary.BigEndian.Uint64(bb[pos+8:])
+			if xl > uint64(len(bb)-pos) {
+				return errors.New("pdfcpu: jpx: corrupt box length")
+			}
+			l, hl = int(xl), 16
+		}
+		if l < hl || pos+l > len(bb) {
+			return errors.New("pdfcpu: jpx: corrupt box length")
+		}
+		if err := f(typ, bb[pos+hl:pos+l]); err != nil {
+			return err
+		}
+		pos += l
+	}
+	return nil
+}
+
+// parse parses the boxes of a JP2 file and returns the contiguous codestream.
+func (hdr *jp2Header) parse(bb []byte) ([]byte, error) {
+	var cs []byte
+
+	err := jp2Boxes(bb, func(typ uint32, content []byte) error {
+		switch typ {
+		case jp2BoxCodestream:
+			if cs == nil {
+				cs = content
+			}
+		case jp2BoxHeader:
+			return jp2Boxes(content, hdr.parseBox)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cs == nil {
+		return nil, errors.New("pdfcpu: jpx: missing codestream")
+	}
+
+	return cs, nil
+}
+
+func (hdr *jp2Header) parseBox(typ uint32, c []byte) error {
+	switch typ {
+
+	case jp2BoxColour:
+		if hdr.colr || len(c) < 3 {
+			// Only the first colour specification is used.
+			return nil
+		}
+		hdr.colr = true
+		switch c[0] {
+		case 1:
+			if len(c) < 7 {
+				return errors.New("pdfcpu: jpx: corrupt colr box")
+			}
+			hdr.enumCS = int(binary.BigEndian.Uint32(c[3:]))
+		case 2, 3:
+			hdr.icc = c[3:]
+		}
+
+	case jp2BoxPalette:
+		if len(c) < 3 {
+			return errors.New("pdfcpu: jpx: corrupt pclr box")
+		}
+		ne, npc := int(binary.BigEndian.Uint16(c)), int(c[2])
+		p := &jp2Palette{}
+		pos := 3
+		if len(c) < pos+npc {
+			return errors.New("pdfcpu: jpx: corrupt pclr box")
+		}
+		size := 0
+		for i := 0; i < npc; i++ {
+			d := int(c[pos+i]&0x7F) + 1
+			p.depths = append(p.depths, d)
+			size += (d + 7) / 8
+		}
+		pos += npc
+		if len(c) < pos+ne*size {
+			return errors.New("pdfcpu: jpx: corrupt pclr box")
+		}
+		for i := 0; i < ne; i++ {
+			e := make([]int32, npc)
+			for j, d := range p.depths {
+				var v int32
+				for k := 0; k < (d+7)/8; k++ {
+					v = v<<8 | int32(c[pos])
+					pos++
+				}
+				e[j] = v
+			}
+			p.entries = append(p.entries, e)
+		}
+		hdr.palette = p
+
+	case jp2BoxCompMap:
+		for i := 0; i+4 <= len(c); i += 4 {
+			hdr.cmap = append(hdr.cmap, jp2CompMapping{int(binary.BigEndian.Uint16(c[i:])), int(c[i+2]), int(c[i+3])})
+		}
+
+	case jp2BoxChanDef:
+		if len(c) < 2 {
+			return errors.New("pdfcpu: jpx: corrupt cdef box")
+		}
+		n := int(binary.BigEndian.Uint16(c))
+		if len(c) < 2+6*n {
+			return errors.New("pdfcpu: jpx: corrupt cdef box")
+		}
+		for i := 0; i < n; i++ {
+			b := c[2+6*i:]
+			hdr.cdef = append(hdr.cdef, jp2ChanDef{int(binary.BigEndian.Uint16(b)), int(binary.BigEndian.Uint16(b[2:])), int(binary.BigEndian.Uint16(b[4:]))})
+		}
+	}
+
+	return nil
+}
+
+// applyPalette maps components to channels according to the pclr and cmap boxes.
+func (hdr *jp2Header) applyPalette(comps []jpxChannel) ([]jpxChannel, error) {
+	if hdr.palette == nil || len(hdr.cmap) == 0 {
+		return comps, nil
+	}
+
+	p := hdr.palette
+	if len(p.entries) == 0 {
+		return nil, errors.New("pdfcpu: jpx: empty palette")
+	}
+
+	var chans []jpxChannel
+	for _, m := range hdr.cmap {
+		if m.cmp >= len(comps) {
+			return nil, errors.New("pdfcpu: jpx: invalid component mapping")
+		}
+		src := comps[m.cmp]
+		if m.mtyp == 0 {
+			chans = append(chans, src)
+			continue
+		}
+		if m.pcol >= len(p.depths) {
+			return nil, errors.New("pdfcpu: jpx: invalid palette column")
+		}
+		ch := jpxChannel{precision: p.depths[m.pcol], data: make([]int32, len(src.data))}
+		for i, v := range src.data {
+			ch.data[i] = p.entries[min(int(v), len(p.entries)-1)][m.pcol]
+		}
+		chans = append(chans, ch)
+	}
+
+	return chans, nil
+}
+
+// channels splits chans into colour channels and an optional opacity channel.
+// n is the number of colours of the signalled colour space or 0.
+func (hdr *jp2Header) channels(chans []jpxChannel, n int) ([]jpxChannel, *jpxChannel) {
+	if len(hdr.cdef) == 0 {
+		if n > 0 && len(chans) == n+1 {
+			return chans[:n], &chans[n]
+		}
+		return chans, nil
+	}
+
+	var (
+		colour []jp2ChanDef
+		alpha  *jpxChannel
+	)
+
+	for _, d := range hdr.cdef {
+		if d.cn >= len(chans) {
+			continue
+		}
+		switch d.typ {
+		case 0:
+			colour = append(colour, d)
+		case 1, 2:
+			if alpha == nil {
+				alpha = &chans[d.cn]
+			}
+		}
+	}
+
+	sort.SliceStable(colour, func(i, j int) bool { return colour[i].asoc < colour[j].asoc })
+
+	cc := make([]jpxChannel, len(colour))
+	for i, d := range colour {
+		cc[i] = chans[d.cn]
+	}
+
+	return cc, alpha
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJPXDecode(t *testing.T) {
+	bb, err := os.ReadFile(filepath.Join("..", "testdata", "resources", "mountain.jpx"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	img, err := DecodeJPX(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatalf("decode: %v\n", err)
+	}
+
+	if img.Width != 1667 || img.Height != 2646 || img.NumComps != 3 {
+		t.Fatalf("want 1667x2646x3, got %dx%dx%d\n", img.Width, img.Height, img.NumComps)
+	}
+	if img.ICCProfile == nil {
+		t.Fatal("missing ICC profile\n")
+	}
+	if len(img.Alpha) != img.Width*img.Height {
+		t.Fatal("missing opacity channel\n")
+	}
+
+	// The top of the image is blue sky, the bottom green meadow.
+	top := img.Samples[(10*img.Width+img.Width/2)*3:]
+	if top[2] <= top[0] {
+		t.Errorf("sky: unexpected colour %v\n", top[:3])
+	}
+	bottom := img.Samples[((img.Height-200)*img.Width+50)*3:]
+	if bottom[1] <= bottom[2] {
+		t.Errorf("meadow: unexpected colour %v\n", bottom[:3])
+	}
+
+	// The filter returns the colour samples only.
+	r, err := jpxDecode{}.Decode(bytes.NewReader(bb))
+	if err != nil {
+		t.Fatalf("decode: %v\n", err)
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	compare(t, b, img.Samples)
+}
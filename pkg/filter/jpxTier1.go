@@ -0,0 +1,383 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+// Code-block decoding as defined in ITU-T T.800 Annex D.
+
+// Context labels (Table D.7).
+const (
+	jpxUniformCX   = 17
+	jpxRunLengthCX = 18
+	jpxNumContexts = 19
+	jpxSigPropPass = 0
+	jpxMagRefPass  = 1
+	jpxCleanupPass = 2
+)
+
+// jpxRawReader reads the raw coding passes of the arithmetic coding bypass mode (D.6).
+type jpxRawReader struct {
+	bb      []byte
+	pos     int
+	buf     byte
+	n       int
+	stuffed bool
+}
+
+func (r *jpxRawReader) bit() int {
+	if r.n == 0 {
+		b := byte(0xFF)
+		if r.pos < len(r.bb) {
+			b = r.bb[r.pos]
+		}
+		r.pos++
+		r.buf, r.n = b, 8
+		if r.stuffed {
+			r.n = 7
+		}
+		r.stuffed = b == 0xFF
+	}
+	r.n--
+	return int(r.buf>>uint(r.n)) & 1
+}
+
+type jpxT1 struct {
+	w, h, stride int
+	typ          int
+	causal       bool
+	sig, neg     []uint8
+	visited      []uint8
+	refined      []uint8
+	mag          []uint32
+	cx           mqContexts
+	mq           *mqDecoder
+	raw          *jpxRawReader
+}
+
+func newJPXT1(w, h, typ, style int) *jpxT1 {
+	n := (w + 2) * (h + 2)
+	t := &jpxT1{
+		w: w, h: h, stride: w + 2,
+		typ:     typ,
+		causal:  style&jpxVerticalCausal != 0,
+		sig:     make([]uint8, n),
+		neg:     make([]uint8, n),
+		visited: make([]uint8, n),
+		refined: make([]uint8, n),
+		mag:     make([]uint32, n),
+		cx:      make(mqContexts, jpxNumContexts),
+	}
+	t.resetContexts()
+	return t
+}
+
+func (t *jpxT1) resetContexts() {
+	for i := range t.cx {
+		t.cx[i] = 0
+	}
+	t.cx[0] = 4 << 1
+	t.cx[jpxUniformCX] = 46 << 1
+	t.cx[jpxRunLengthCX] = 3 << 1
+}
+
+func (t *jpxT1) idx(x, y int) int {
+	return (y+1)*t.stride + x + 1
+}
+
+func (t *jpxT1) decodeBit(cx int) int {
+	if t.raw != nil {
+		return t.raw.bit()
+	}
+	return t.mq.readBit(t.cx, cx)
+}
+
+// neighbours returns the number of significant horizontal, vertical and diagonal neighbours.
+func (t *jpxT1) neighbours(i, y int) (h, v, d int) {
+	s := t.stride
+	h = int(t.sig[i-1] + t.sig[i+1])
+	v = int(t.sig[i-s])
+	d = int(t.sig[i-s-1] + t.sig[i-s+1])
+	if !t.causal || y%4 != 3 {
+		v += int(t.sig[i+s])
+		d += int(t.sig[i+s-1] + t.sig[i+s+1])
+	}
+	return h, v, d
+}
+
+// sigContext returns the significance context label (Table D.1).
+func (t *jpxT1) sigContext(h, v, d int) int {
+	if t.typ == jpxHH {
+		hv := h + v
+		switch {
+		case d >= 3:
+			return 8
+		case d == 2:
+			if hv >= 1 {
+				return 7
+			}
+			return 6
+		case d == 1:
+			return 3 + min(hv, 2)
+		}
+		return min(hv, 2)
+	}
+
+	if t.typ == jpxHL {
+		h, v = v, h
+	}
+
+	switch h {
+	case 2:
+		return 8
+	case 1:
+		if v >= 1 {
+			return 7
+		}
+		if d >= 1 {
+			return 6
+		}
+		return 5
+	}
+
+	switch {
+	case v == 2:
+		return 4
+	case v == 1:
+		return 3
+	case d >= 2:
+		return 2
+	}
+	return d
+}
+
+func (t *jpxT1) signContribution(i int) int {
+	if t.sig[i] == 0 {
+		return 0
+	}
+	if t.neg[i] == 1 {
+		return -1
+	}
+	return 1
+}
+
+// decodeSign decodes the sign bit of the coefficient at i (Table D.3).
+func (t *jpxT1) decodeSign(i, y int) uint8 {
+	if t.raw != nil {
+		return uint8(t.raw.bit())
+	}
+
+	s := t.stride
+	hc := max(-1, min(1, t.signContribution(i-1)+t.signContribution(i+1)))
+	vc := t.signContribution(i - s)
+	if !t.causal || y%4 != 3 {
+		vc += t.signContribution(i + s)
+	}
+	vc = max(-1, min(1, vc))
+
+	xor := 0
+	if hc < 0 || (hc == 0 && vc < 0) {
+		xor, hc, vc = 1, -hc, -vc
+	}
+
+	cx := 9 + vc
+	if hc == 1 {
+		cx = 12 + vc
+	}
+
+	return uint8(t.mq.readBit(t.cx, cx) ^ xor)
+}
+
+func (t *jpxT1) setSignificant(i, y, plane int) {
+	t.neg[i] = t.decodeSign(i, y)
+	t.sig[i] = 1
+	t.mag[i] |= 1 << uint(plane)
+}
+
+// significancePropagation implements the significance propagation pass (D.3.1).
+func (t *jpxT1) significancePropagation(plane int) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := t.idx(x, y)
+				if t.sig[i] != 0 {
+					continue
+				}
+				h, v, d := t.neighbours(i, y)
+				if h+v+d == 0 {
+					continue
+				}
+				t.visited[i] = 1
+				if t.decodeBit(t.sigContext(h, v, d)) == 1 {
+					t.setSignificant(i, y, plane)
+				}
+			}
+		}
+	}
+}
+
+// magnitudeRefinement implements the magnitude refinement pass (D.3.3).
+func (t *jpxT1) magnitudeRefinement(plane int) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			for y := y0; y < min(y0+4, t.h); y++ {
+				i := t.idx(x, y)
+				if t.sig[i] == 0 || t.visited[i] != 0 {
+					continue
+				}
+				cx := 16
+				if t.refined[i] == 0 {
+					cx = 14
+					if h, v, d := t.neighbours(i, y); h+v+d > 0 {
+						cx = 15
+					}
+				}
+				t.mag[i] |= uint32(t.decodeBit(cx)) << uint(plane)
+				t.refined[i] = 1
+			}
+		}
+	}
+}
+
+// cleanup implements the cleanup pass including run-length coding (D.3.4).
+func (t *jpxT1) cleanup(plane int, segSymbols bool) {
+	for y0 := 0; y0 < t.h; y0 += 4 {
+		for x := 0; x < t.w; x++ {
+			y := y0
+
+			if y0+4 <= t.h && t.runLengthCandidate(x, y0) {
+				if t.mq.readBit(t.cx, jpxRunLengthCX) == 0 {
+					continue
+				}
+				r := t.mq.readBit(t.cx, jpxUniformCX)<<1 | t.mq.readBit(t.cx, jpxUniformCX)
+				y = y0 + r
+				t.setSignificant(t.idx(x, y), y, plane)
+				y++
+			}
+
+			for ; y < min(y0+4, t.h); y++ {
+				i := t.idx(x, y)
+				if t.sig[i] != 0 || t.visited[i] != 0 {
+					continue
+				}
+				h, v, d := t.neighbours(i, y)
+				if t.decodeBit(t.sigContext(h, v, d)) == 1 {
+					t.setSignificant(i, y, plane)
+				}
+			}
+		}
+	}
+
+	if segSymbols {
+		for i := 0; i < 4; i++ {
+			t.mq.readBit(t.cx, jpxUniformCX)
+		}
+	}
+
+	for i := range t.visited {
+		t.visited[i] = 0
+	}
+}
+
+func (t *jpxT1) runLengthCandidate(x, y0 int) bool {
+	for y := y0; y < y0+4; y++ {
+		i := t.idx(x, y)
+		if t.sig[i] != 0 || t.visited[i] != 0 {
+			return false
+		}
+		if h, v, d := t.neighbours(i, y); h+v+d > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeCodeBlock decodes the coding passes of cb and stores the dequantized coefficients in b.
+func decodeCodeBlock(b *jpxBand, cb *jpxCodeBlock, style int, reversible bool) {
+	w, h := cb.x1-cb.x0, cb.y1-cb.y0
+	if w <= 0 || h <= 0 || cb.passes == 0 {
+		return
+	}
+
+	t := newJPXT1(w, h, b.typ, style)
+
+	plane := b.mb - 1 - cb.zeroPlanes
+	if plane < 0 {
+		return
+	}
+
+	pass, lowest := 0, plane
+
+segments:
+	for _, seg := range cb.segs {
+		if seg.raw {
+			t.raw, t.mq = &jpxRawReader{bb: seg.data}, nil
+		} else {
+			t.raw, t.mq = nil, newMQDecoder(seg.data, 0, len(seg.data))
+		}
+
+		for n := 0; n < seg.passes; n++ {
+			if plane < 0 {
+				break segments
+			}
+
+			typ := jpxCleanupPass
+			if pass > 0 {
+				typ = (pass - 1) % 3
+			}
+
+			switch typ {
+			case jpxSigPropPass:
+				t.significancePropagation(plane)
+			case jpxMagRefPass:
+				t.magnitudeRefinement(plane)
+			case jpxCleanupPass:
+				t.cleanup(plane, style&jpxSegSymbols != 0)
+			}
+
+			lowest = plane
+			if typ == jpxCleanupPass {
+				plane--
+			}
+
+			if style&jpxReset != 0 {
+				t.resetContexts()
+			}
+
+			pass++
+		}
+	}
+
+	// Reconstruction (E.1.1.2)
+	var r float64
+	if !reversible || lowest > 0 {
+		r = float64(uint32(1)<<uint(lowest)) / 2
+	}
+
+	bw := b.x1 - b.x0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := t.idx(x, y)
+			if t.mag[i] == 0 {
+				continue
+			}
+			v := (float64(t.mag[i]) + r) * b.delta
+			if t.neg[i] == 1 {
+				v = -v
+			}
+			b.coeffs[(cb.y0-b.y0+y)*bw+cb.x0-b.x0+x] = float32(v)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filter
+
+import "math"
+
+// Lifting parameters of the irreversible 9-7 filter (Table F.4).
+const (
+	dwtAlpha = -1.586134342059924
+	dwtBeta  = -0.052980118572961
+	dwtGamma = 0.882911075530934
+	dwtDelta = 0.443506852043971
+	dwtK     = 1.230174104914001
+)
+
+// mirror returns the index of the periodic symmetric extension of a signal of length n (F.3.7).
+func mirror(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	p := 2 * (n - 1)
+	if i < 0 {
+		i = -i
+	}
+	i %= p
+	if i >= n {
+		i = p - i
+	}
+	return i
+}
+
+// inverse1D implements the 1D_SR procedure for the signal y spanning i0..i0+len(y) (F.3.6).
+func inverse1D(y []float32, i0 int, reversible bool, ext []float64) {
+	n := len(y)
+	if n == 1 {
+		if i0%2 != 0 {
+			if reversible {
+				y[0] = float32(math.Trunc(float64(y[0]) / 2))
+			} else {
+				y[0] /= 2
+			}
+		}
+		return
+	}
+
+	p := 4
+	if reversible {
+		p = 2
+	}
+
+	ext = ext[:n+2*p]
+	for j := range ext {
+		ext[j] = float64(y[mirror(j-p, n)])
+	}
+
+	// even reports whether ext[j] is a lowpass sample.
+	even := func(j int) bool { return (i0-p+j)%2 == 0 }
+
+	lift := func(from, to int, low bool, f func(j int)) {
+		for j := from; j < to; j++ {
+			if even(j) == low {
+				f(j)
+			}
+		}
+	}
+
+	m := len(ext)
+
+	if reversible {
+		lift(1, m-1, true, func(j int) { ext[j] -= math.Floor((ext[j-1] + ext[j+1] + 2) / 4) })
+		lift(2, m-2, false, func(j int) { ext[j] += math.Floor((ext[j-1] + ext[j+1]) / 2) })
+	} else {
+		for j := range ext {
+			if even(j) {
+				ext[j] *= dwtK
+			} else {
+				ext[j] /= dwtK
+			}
+		}
+		lift(1, m-1, true, func(j int) { ext[j] -= dwtDelta * (ext[j-1] + ext[j+1]) })
+		lift(2, m-2, false, func(j int) { ext[j] -= dwtGamma * (ext[j-1] + ext[j+1]) })
+		lift(3, m-3, true, func(j int) { ext[j] -= dwtBeta * (ext[j-1] + ext[j+1]) })
+		lift(4, m-4, false, func(j int) { ext[j] -= dwtAlpha * (ext[j-1] + ext[j+1]) })
+	}
+
+	for j := range y {
+		y[j] = float32(ext[p+j])
+	}
+}
+
+// reconstruct applies the inverse discrete wavelet transform to all resolutions of tc (F.3.1).
+func (tc *jpxTileComp) reconstruct() {
+	ll := tc.res[0].bands[0]
+	a := ll.coeffs
+
+	rev := tc.cc.reversible
+
+	for _, res := range tc.res[1:] {
+		w, h := res.x1-res.x0, res.y1-res.y0
+		out := make([]float32, w*h)
+
+		// 2D_INTERLEAVE
+		bands := append([]*jpxBand{{typ: jpxLL, x0: ll.x0, y0: ll.y0, x1: ll.x1, y1: ll.y1, coeffs: a}}, res.bands...)
+		for _, b := range bands {
+			xo, yo := 0, 0
+			if b.typ == jpxHL || b.typ == jpxHH {
+				xo = 1
+			}
+			if b.typ == jpxLH || b.typ == jpxHH {
+				yo = 1
+			}
+			bw := b.x1 - b.x0
+			for by := b.y0; by < b.y1; by++ {
+				v := 2*by + yo - res.y0
+				for bx := b.x0; bx < b.x1; bx++ {
+					u := 2*bx + xo - res.x0
+					out[v*w+u] = b.coeffs[(by-b.y0)*bw+bx-b.x0]
+				}
+			}
+		}
+
+		ext := make([]float64, max(w, h)+8)
+
+		// HOR_SR
+		if w > 0 {
+			for v := 0; v < h; v++ {
+				inverse1D(out[v*w:(v+1)*w], res.x0, rev, ext)
+			}
+		}
+
+		// VER_SR
+		col := make([]float32, h)
+		for u := 0; u < w && h > 0; u++ {
+			for v := range col {
+				col[v] = out[v*w+u]
+			}
+			inverse1D(col, res.y0, rev, ext)
+			for v := range col {
+				out[v*w+u] = col[v]
+			}
+		}
+
+		a = out
+		ll = &jpxBand{x0: res.x0, y0: res.y0, x1: res.x1, y1: res.y1}
+	}
+
+	tc.data = a
+}
+
+// inverseComponentTransform applies the inverse multiple component transformation (Annex G).
+func inverseComponentTransform(comps []*jpxTileComp) {
+	y0, y1, y2 := comps[0].data, comps[1].data, comps[2].data
+	n := min(len(y0), len(y1), len(y2))
+
+	if comps[0].cc.reversible {
+		// RCT
+		for i := 0; i < n; i++ {
+			g := float64(y0[i]) - math.Floor(float64(y1[i]+y2[i])/4)
+			y0[i], y1[i], y2[i] = float32(float64(y2[i])+g), float32(g), float32(float64(y1[i])+g)
+		}
+		return
+	}
+
+	// ICT
+	for i := 0; i < n; i++ {
+		y, cb, cr := y0[i], y1[i], y2[i]
+		y0[i] = y + 1.402*cr
+		y1[i] = y - 0.34413*cb - 0.71414*cr
+		y2[i] = y + 1.772*cb
+	}
+}
@@ -0,0 +1,972 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+var errCorruptCFF = errors.New("pdfcpu: corrupt CFF font program")
+
+const CFFEscape = 1200 // two-byte operators are mapped to CFFEscape + second byte.
+
+// CFFDictEntry is an operator of a CFF DICT along with its raw operands.
+type CFFDictEntry struct {
+	Op   int
+	Args []byte
+}
+
+// cffFontDict represents a Font DICT of a CID-keyed font or the Private DICT of a name-keyed font.
+type cffFontDict struct {
+	dict  []CFFDictEntry // Font DICT, CID-keyed fonts only
+	priv  []CFFDictEntry
+	subrs [][]byte
+}
+
+// cffFont represents a bare CFF font program (Adobe Technical Note #5176) split up for rewriting.
+type cffFont struct {
+	names       [][]byte
+	top         []CFFDictEntry
+	strings     [][]byte
+	gsubrs      [][]byte
+	charset     []byte // nil for predefined charsets
+	fdSelect    []byte // CID-keyed fonts only
+	charStrings [][]byte
+	fds         []cffFontDict
+}
+
+// CFFIndex returns the items of the CFF INDEX at off and the offset following it.
+func CFFIndex(bb []byte, off int) ([][]byte, int, error) {
+	if off < 0 || off+2 > len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+	n := int(binary.BigEndian.Uint16(bb[off:]))
+	if n == 0 {
+		return nil, off + 2, nil
+	}
+	if off+3 > len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+	offSize := int(bb[off+2])
+	if offSize < 1 || offSize > 4 || off+3+(n+1)*offSize > len(bb) {
+		return nil, 0, errCorruptCFF
+	}
+
+	offs := make([]int, n+1)
+	for i := range offs {
+		p := off + 3 + i*offSize
+		for j := 0; j < offSize; j++ {
+			offs[i] = offs[i]<<8 | int(bb[p+j])
+		}
+	}
+
+	base := off + 3 + (n+1)*offSize - 1
+	items := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		from, thru := base+offs[i], base+offs[i+1]
+		if from > thru || thru > len(bb) {
+			return nil, 0, errCorruptCFF
+		}
+		items[i] = bb[from:thru]
+	}
+
+	return items, base + offs[n], nil
+}
+
+func cffIndexBytes(items [][]byte) []byte {
+	if len(items) == 0 {
+		return []byte{0, 0}
+	}
+
+	size := 1
+	for _, item := range items {
+		size += len(item)
+	}
+	offSize := 1
+	for ; offSize < 4 && size >= 1<<(8*offSize); offSize++ {
+	}
+
+	var buf bytes.Buffer
+	buf.Write(uint16ToBigEndianBytes(uint16(len(items))))
+	buf.WriteByte(byte(offSize))
+
+	writeOff := func(off int) {
+		for j := offSize - 1; j >= 0; j-- {
+			buf.WriteByte(byte(off >> (8 * j)))
+		}
+	}
+
+	off := 1
+	writeOff(off)
+	for _, item := range items {
+		off += len(item)
+		writeOff(off)
+	}
+	for _, item := range items {
+		buf.Write(item)
+	}
+
+	return buf.Bytes()
+}
+
+// ParseCFFDict splits a CFF DICT into its entries.
+func ParseCFFDict(bb []byte) ([]CFFDictEntry, error) {
+	var entries []CFFDictEntry
+	start := 0
+	for i := 0; i < len(bb); {
+		b0 := bb[i]
+		switch {
+		case b0 == 28:
+			i += 3
+		case b0 == 29:
+			i += 5
+		case b0 == 30:
+			for i++; i < len(bb); i++ {
+				if bb[i]>>4 == 0x0F || bb[i]&0x0F == 0x0F {
+					i++
+					break
+				}
+			}
+		case b0 >= 32 && b0 <= 246:
+			i++
+		case b0 >= 247 && b0 <= 254:
+			i += 2
+		case b0 <= 21:
+			op, j := int(b0), i+1
+			if b0 == 12 {
+				if j >= len(bb) {
+					return nil, errCorruptCFF
+				}
+				op, j = CFFEscape+int(bb[j]), j+1
+			}
+			entries = append(entries, CFFDictEntry{Op: op, Args: bb[start:i]})
+			i, start = j, j
+		default:
+			return nil, errCorruptCFF
+		}
+		if i > len(bb) {
+			return nil, errCorruptCFF
+		}
+	}
+	return entries, nil
+}
+
+func cffReal(bb []byte) (float64, int) {
+	var s []byte
+	for i, b := range bb {
+		for _, nib := range []byte{b >> 4, b & 0x0F} {
+			switch {
+			case nib <= 9:
+				s = append(s, '0'+nib)
+			case nib == 0x0A:
+				s = append(s, '.')
+			case nib == 0x0B:
+				s = append(s, 'E')
+			case nib == 0x0C:
+				s = append(s, 'E', '-')
+			case nib == 0x0E:
+				s = append(s, '-')
+			case nib == 0x0F:
+				f, _ := strconv.ParseFloat(string(s), 64)
+				return f, i + 1
+			}
+		}
+	}
+	return 0, len(bb)
+}
+
+// Operands returns the decoded operands of e.
+func (e CFFDictEntry) Operands() []float64 {
+	bb := e.Args
+	var a []float64
+	for i := 0; i < len(bb); {
+		b0 := bb[i]
+		switch {
+		case b0 == 28 && i+3 <= len(bb):
+			a = append(a, float64(int16(binary.BigEndian.Uint16(bb[i+1:]))))
+			i += 3
+		case b0 == 29 && i+5 <= len(bb):
+			a = append(a, float64(int32(binary.BigEndian.Uint32(bb[i+1:]))))
+			i += 5
+		case b0 == 30:
+			f, n := cffReal(bb[i+1:])
+			a = append(a, f)
+			i += 1 + n
+		case b0 >= 32 && b0 <= 246:
+			a = append(a, float64(int(b0)-139))
+			i++
+		case b0 >= 247 && b0 <= 250 && i+2 <= len(bb):
+			a = append(a, float64((int(b0)-247)*256+int(bb[i+1])+108))
+			i += 2
+		case b0 >= 251 && b0 <= 254 && i+2 <= len(bb):
+			a = append(a, float64(-(int(b0)-251)*256-int(bb[i+1])-108))
+			i += 2
+		default:
+			return a
+		}
+	}
+	return a
+}
+
+// cffIntOperands encodes integer operands using five bytes each so that DICT sizes do not depend on their values.
+func cffIntOperands(ii ...int) []byte {
+	bb := make([]byte, 0, 5*len(ii))
+	for _, i := range ii {
+		bb = append(bb, 29)
+		bb = append(bb, uint32ToBigEndianBytes(uint32(int32(i)))...)
+	}
+	return bb
+}
+
+// cffRealOperand encodes a real operand.
+func cffRealOperand(f float64) []byte {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	var nibbles []byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			nibbles = append(nibbles, c-'0')
+		case c == '.':
+			nibbles = append(nibbles, 0x0A)
+		case c == '-':
+			nibbles = append(nibbles, 0x0E)
+		case c == 'e':
+			if i+1 < len(s) && s[i+1] == '-' {
+				nibbles = append(nibbles, 0x0C)
+				i++
+			} else {
+				nibbles = append(nibbles, 0x0B)
+				if i+1 < len(s) && s[i+1] == '+' {
+					i++
+				}
+			}
+		}
+	}
+	nibbles = append(nibbles, 0x0F)
+	if len(nibbles)%2 == 1 {
+		nibbles = append(nibbles, 0x0F)
+	}
+
+	bb := []byte{30}
+	for i := 0; i < len(nibbles); i += 2 {
+		bb = append(bb, nibbles[i]<<4|nibbles[i+1])
+	}
+	return bb
+}
+
+func cffDictBytes(entries []CFFDictEntry) []byte {
+	var buf bytes.Buffer
+	for _, e := range entries {
+		buf.Write(e.Args)
+		if e.Op >= CFFEscape {
+			buf.WriteByte(12)
+			buf.WriteByte(byte(e.Op - CFFEscape))
+			continue
+		}
+		buf.WriteByte(byte(e.Op))
+	}
+	return buf.Bytes()
+}
+
+func cffEntry(entries []CFFDictEntry, op int) ([]int, bool) {
+	for _, e := range entries {
+		if e.Op == op {
+			var a []int
+			for _, f := range e.Operands() {
+				a = append(a, int(f))
+			}
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+func cffOffset(entries []CFFDictEntry, op int) int {
+	if a, ok := cffEntry(entries, op); ok && len(a) > 0 {
+		return a[len(a)-1]
+	}
+	return 0
+}
+
+func setCFFEntry(entries []CFFDictEntry, op int, args []byte) []CFFDictEntry {
+	for i, e := range entries {
+		if e.Op == op {
+			entries[i].Args = args
+			return entries
+		}
+	}
+	return append(entries, CFFDictEntry{Op: op, Args: args})
+}
+
+func parseCFFPrivate(bb []byte, entries []CFFDictEntry) (cffFontDict, error) {
+	var fd cffFontDict
+	a, ok := cffEntry(entries, 18)
+	if !ok {
+		return fd, nil
+	}
+	if len(a) != 2 {
+		return fd, errCorruptCFF
+	}
+	size, off := a[0], a[1]
+	if size < 0 || off < 0 || off+size > len(bb) {
+		return fd, errCorruptCFF
+	}
+
+	priv, err := ParseCFFDict(bb[off : off+size])
+	if err != nil {
+		return fd, err
+	}
+	fd.priv = priv
+
+	if o := cffOffset(priv, 19); o > 0 {
+		if fd.subrs, _, err = CFFIndex(bb, off+o); err != nil {
+			return fd, err
+		}
+	}
+
+	return fd, nil
+}
+
+func cffCharsetLength(bb []byte, off, n int) (int, error) {
+	if off >= len(bb) {
+		return 0, errCorruptCFF
+	}
+	p := off + 1
+	switch bb[off] {
+	case 0:
+		p += 2 * (n - 1)
+	case 1, 2:
+		size := 3
+		if bb[off] == 2 {
+			size = 4
+		}
+		for gid := 1; gid < n; p += size {
+			if p+size > len(bb) {
+				return 0, errCorruptCFF
+			}
+			left := int(bb[p+2])
+			if size == 4 {
+				left = int(binary.BigEndian.Uint16(bb[p+2:]))
+			}
+			gid += left + 1
+		}
+	default:
+		return 0, errCorruptCFF
+	}
+	if p > len(bb) {
+		return 0, errCorruptCFF
+	}
+	return p - off, nil
+}
+
+func cffFDSelectLength(bb []byte, off, n int) (int, error) {
+	if off+3 > len(bb) {
+		return 0, errCorruptCFF
+	}
+	l := 0
+	switch bb[off] {
+	case 0:
+		l = 1 + n
+	case 3:
+		l = 5 + 3*int(binary.BigEndian.Uint16(bb[off+1:]))
+	default:
+		return 0, errCorruptCFF
+	}
+	if off+l > len(bb) {
+		return 0, errCorruptCFF
+	}
+	return l, nil
+}
+
+// parseCFF parses a bare CFF font program.
+func parseCFF(bb []byte) (*cffFont, error) {
+	if len(bb) < 4 || bb[0] != 1 {
+		return nil, errCorruptCFF
+	}
+
+	f := &cffFont{}
+
+	names, off, err := CFFIndex(bb, int(bb[2]))
+	if err != nil {
+		return nil, err
+	}
+	f.names = names
+
+	topDicts, off, err := CFFIndex(bb, off)
+	if err != nil || len(topDicts) != 1 {
+		return nil, errCorruptCFF
+	}
+
+	if f.strings, off, err = CFFIndex(bb, off); err != nil {
+		return nil, err
+	}
+
+	if f.gsubrs, _, err = CFFIndex(bb, off); err != nil {
+		return nil, err
+	}
+
+	if f.top, err = ParseCFFDict(topDicts[0]); err != nil {
+		return nil, err
+	}
+
+	if f.charStrings, _, err = CFFIndex(bb, cffOffset(f.top, 17)); err != nil {
+		return nil, err
+	}
+	n := len(f.charStrings)
+	if n == 0 {
+		return nil, errCorruptCFF
+	}
+
+	if off := cffOffset(f.top, 15); off > 2 {
+		l, err := cffCharsetLength(bb, off, n)
+		if err != nil {
+			return nil, err
+		}
+		f.charset = bb[off : off+l]
+	}
+
+	if !f.cidKeyed() {
+		fd, err := parseCFFPrivate(bb, f.top)
+		if err != nil {
+			return nil, err
+		}
+		f.fds = []cffFontDict{fd}
+		return f, nil
+	}
+
+	fontDicts, _, err := CFFIndex(bb, cffOffset(f.top, CFFEscape+36))
+	if err != nil || len(fontDicts) == 0 {
+		return nil, errCorruptCFF
+	}
+	for _, d := range fontDicts {
+		entries, err := ParseCFFDict(d)
+		if err != nil {
+			return nil, err
+		}
+		fd, err := parseCFFPrivate(bb, entries)
+		if err != nil {
+			return nil, err
+		}
+		fd.dict = entries
+		f.fds = append(f.fds, fd)
+	}
+
+	if off := cffOffset(f.top, CFFEscape+37); off > 0 {
+		l, err := cffFDSelectLength(bb, off, n)
+		if err != nil {
+			return nil, err
+		}
+		f.fdSelect = bb[off : off+l]
+	}
+
+	return f, nil
+}
+
+func (f *cffFont) cidKeyed() bool {
+	_, ok := cffEntry(f.top, CFFEscape+30)
+	return ok
+}
+
+// fdIndex returns the index of the Font DICT for glyph gid.
+func (f *cffFont) fdIndex(gid int) int {
+	bb := f.fdSelect
+	if len(bb) == 0 {
+		return 0
+	}
+	fd := 0
+	switch bb[0] {
+	case 0:
+		if gid+1 < len(bb) {
+			fd = int(bb[gid+1])
+		}
+	case 3:
+		nRanges := int(binary.BigEndian.Uint16(bb[1:]))
+		for i := 0; i < nRanges; i++ {
+			p := 3 + 3*i
+			if gid >= int(binary.BigEndian.Uint16(bb[p:])) {
+				fd = int(bb[p+2])
+			}
+		}
+	}
+	if fd >= len(f.fds) {
+		return 0
+	}
+	return fd
+}
+
+// sids returns the SID by glyph index of a name-keyed font.
+func (f *cffFont) sids() []int {
+	n := len(f.charStrings)
+	sids := make([]int, n)
+	bb := f.charset
+	if bb == nil {
+		// ISOAdobe charset
+		for gid := range sids {
+			sids[gid] = gid
+		}
+		return sids
+	}
+	p := 1
+	for gid := 1; gid < n; {
+		switch bb[0] {
+		case 0:
+			sids[gid] = int(binary.BigEndian.Uint16(bb[p:]))
+			gid, p = gid+1, p+2
+		case 1, 2:
+			first, left := int(binary.BigEndian.Uint16(bb[p:])), int(bb[p+2])
+			if bb[0] == 2 {
+				left = int(binary.BigEndian.Uint16(bb[p+2:]))
+				p++
+			}
+			p += 3
+			for i := 0; i <= left && gid < n; i++ {
+				sids[gid] = first + i
+				gid++
+			}
+		}
+	}
+	return sids
+}
+
+func (f *cffFont) addString(s string) int {
+	f.strings = append(f.strings, []byte(s))
+	return len(CFFStandardStrings) + len(f.strings) - 1
+}
+
+func (fd cffFontDict) privateBytes() []byte {
+	entries := make([]CFFDictEntry, 0, len(fd.priv))
+	for _, e := range fd.priv {
+		if e.Op != 19 {
+			entries = append(entries, e)
+		}
+	}
+	if len(fd.subrs) == 0 {
+		return cffDictBytes(entries)
+	}
+	// Subrs follow the Private DICT.
+	l := len(cffDictBytes(entries)) + 6
+	return cffDictBytes(append(entries, CFFDictEntry{Op: 19, Args: cffIntOperands(l)}))
+}
+
+// bytes serializes f.
+func (f *cffFont) bytes() []byte {
+	cid := f.cidKeyed()
+
+	privs := make([][]byte, len(f.fds))
+	for i, fd := range f.fds {
+		privs[i] = append(fd.privateBytes(), cffIndexBytes(fd.subrs)...)
+	}
+
+	fontDicts := func(offs []int) []byte {
+		dd := make([][]byte, len(f.fds))
+		for i, fd := range f.fds {
+			priv := cffIntOperands(len(fd.privateBytes()), offs[i])
+			dd[i] = cffDictBytes(setCFFEntry(append([]CFFDictEntry(nil), fd.dict...), 18, priv))
+		}
+		return cffIndexBytes(dd)
+	}
+
+	top := func(charset, fdSelect, charStrings, fdArray int, privOffs []int) []byte {
+		entries := make([]CFFDictEntry, 0, len(f.top))
+		for _, e := range f.top {
+			switch e.Op {
+			case 15:
+				if f.charset != nil {
+					e.Args = cffIntOperands(charset)
+				}
+			case 16:
+				// Glyphs are selected by CID or glyph name.
+				continue
+			case 17:
+				e.Args = cffIntOperands(charStrings)
+			case 18:
+				if cid {
+					continue
+				}
+				e.Args = cffIntOperands(len(f.fds[0].privateBytes()), privOffs[0])
+			case CFFEscape + 36:
+				e.Args = cffIntOperands(fdArray)
+			case CFFEscape + 37:
+				e.Args = cffIntOperands(fdSelect)
+			}
+			entries = append(entries, e)
+		}
+		return cffIndexBytes([][]byte{cffDictBytes(entries)})
+	}
+
+	head := append([]byte{1, 0, 4, 4}, cffIndexBytes(f.names)...)
+	strs, gsubrs, charStrings := cffIndexBytes(f.strings), cffIndexBytes(f.gsubrs), cffIndexBytes(f.charStrings)
+
+	privOffs := make([]int, len(f.fds))
+	off := len(head) + len(top(0, 0, 0, 0, privOffs)) + len(strs) + len(gsubrs)
+	charsetOff := off
+	off += len(f.charset)
+	fdSelectOff := off
+	off += len(f.fdSelect)
+	charStringsOff := off
+	off += len(charStrings)
+	fdArrayOff := off
+	if cid {
+		off += len(fontDicts(privOffs))
+	}
+	for i := range privs {
+		privOffs[i] = off
+		off += len(privs[i])
+	}
+
+	var buf bytes.Buffer
+	buf.Write(head)
+	buf.Write(top(charsetOff, fdSelectOff, charStringsOff, fdArrayOff, privOffs))
+	buf.Write(strs)
+	buf.Write(gsubrs)
+	buf.Write(f.charset)
+	buf.Write(f.fdSelect)
+	buf.Write(charStrings)
+	if cid {
+		buf.Write(fontDicts(privOffs))
+	}
+	for _, priv := range privs {
+		buf.Write(priv)
+	}
+
+	return buf.Bytes()
+}
+
+// normalizeCFF prepares a bare CFF font program for embedding as CIDFontType0C.
+// CID-keyed fonts get renumbered so that CIDs match glyph indices (Adobe-Identity-0).
+func normalizeCFF(bb []byte) ([]byte, error) {
+	f, err := parseCFF(bb)
+	if err != nil {
+		return nil, err
+	}
+	if !f.cidKeyed() {
+		return bb, nil
+	}
+
+	n := len(f.charStrings)
+	f.charset = []byte{0}
+	if n > 1 {
+		f.charset = []byte{2, 0, 1, byte((n - 2) >> 8), byte(n - 2)}
+	}
+
+	f.top = setCFFEntry(f.top, 15, nil)
+
+	registry, ordering := f.addString("Adobe"), f.addString("Identity")
+	f.top = setCFFEntry(f.top, CFFEscape+30, cffIntOperands(registry, ordering, 0))
+	f.top = setCFFEntry(f.top, CFFEscape+34, cffIntOperands(n))
+
+	return f.bytes(), nil
+}
+
+// CFFSubrBias returns the bias added to subroutine numbers of a subroutine INDEX with n items.
+func CFFSubrBias(n int) int {
+	switch {
+	case n < 1240:
+		return 107
+	case n < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// cffSubsetter collects the glyphs and subroutines used by a set of glyphs of a CFF font program.
+type cffSubsetter struct {
+	f      *cffFont
+	sids   []int
+	gids   map[uint16]bool
+	queue  []uint16
+	gsubrs map[int]bool
+	lsubrs []map[int]bool
+	stack  []int
+	nStems int
+	depth  int
+}
+
+func (s *cffSubsetter) use(gid uint16) {
+	if int(gid) < len(s.f.charStrings) && !s.gids[gid] {
+		s.gids[gid] = true
+		s.queue = append(s.queue, gid)
+	}
+}
+
+// seac adds the glyphs of an accented character composed of two glyphs of the StandardEncoding.
+func (s *cffSubsetter) seac(bchar, achar int) {
+	if s.sids == nil {
+		s.sids = s.f.sids()
+	}
+	for _, c := range []int{bchar, achar} {
+		sid := cffStandardEncodingSID(c)
+		if sid == 0 {
+			continue
+		}
+		for gid, v := range s.sids {
+			if v == sid {
+				s.use(uint16(gid))
+				break
+			}
+		}
+	}
+}
+
+func (s *cffSubsetter) callSubr(subrs [][]byte, used map[int]bool, fd int) (bool, error) {
+	if len(s.stack) == 0 {
+		return false, errCorruptCFF
+	}
+	i := s.stack[len(s.stack)-1] + CFFSubrBias(len(subrs))
+	s.stack = s.stack[:len(s.stack)-1]
+	if i < 0 || i >= len(subrs) {
+		return false, errCorruptCFF
+	}
+	used[i] = true
+	return s.exec(subrs[i], fd)
+}
+
+// exec walks a Type 2 charstring, see Adobe Technical Note #5177.
+func (s *cffSubsetter) exec(cs []byte, fd int) (bool, error) {
+	s.depth++
+	defer func() { s.depth-- }()
+	if s.depth > 10 {
+		return false, errCorruptCFF
+	}
+
+	for i := 0; i < len(cs); {
+		b0 := cs[i]
+		switch {
+		case b0 == 28:
+			if i+3 > len(cs) {
+				return false, errCorruptCFF
+			}
+			s.stack = append(s.stack, int(int16(binary.BigEndian.Uint16(cs[i+1:]))))
+			i += 3
+		case b0 >= 32 && b0 <= 246:
+			s.stack = append(s.stack, int(b0)-139)
+			i++
+		case b0 >= 247 && b0 <= 254:
+			if i+2 > len(cs) {
+				return false, errCorruptCFF
+			}
+			v := (int(b0)-247)*256 + int(cs[i+1]) + 108
+			if b0 >= 251 {
+				v = -(int(b0)-251)*256 - int(cs[i+1]) - 108
+			}
+			s.stack = append(s.stack, v)
+			i += 2
+		case b0 == 255:
+			if i+5 > len(cs) {
+				return false, errCorruptCFF
+			}
+			s.stack = append(s.stack, int(int32(binary.BigEndian.Uint32(cs[i+1:]))>>16))
+			i += 5
+		default:
+			i++
+			switch b0 {
+			case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+				s.nStems += len(s.stack) / 2
+			case 19, 20: // hintmask, cntrmask
+				s.nStems += len(s.stack) / 2
+				i += (s.nStems + 7) / 8
+			case 10: // callsubr
+				done, err := s.callSubr(s.f.fds[fd].subrs, s.lsubrs[fd], fd)
+				if err != nil || done {
+					return done, err
+				}
+				continue
+			case 29: // callgsubr
+				done, err := s.callSubr(s.f.gsubrs, s.gsubrs, fd)
+				if err != nil || done {
+					return done, err
+				}
+				continue
+			case 11: // return
+				return false, nil
+			case 14: // endchar
+				if n := len(s.stack); n >= 4 {
+					s.seac(s.stack[n-2], s.stack[n-1])
+				}
+				return true, nil
+			case 12:
+				i++
+			}
+			s.stack = s.stack[:0]
+		}
+	}
+
+	return false, nil
+}
+
+// subsetCFF returns a CFF font program covering usedGIDs.
+// Like Subset for TrueType fonts glyph indices are retained and unused glyphs and subroutines get emptied.
+func subsetCFF(bb []byte, usedGIDs map[uint16]bool) ([]byte, error) {
+	f, err := parseCFF(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &cffSubsetter{f: f, gids: map[uint16]bool{}, gsubrs: map[int]bool{}}
+	for range f.fds {
+		s.lsubrs = append(s.lsubrs, map[int]bool{})
+	}
+
+	gids := []int{0}
+	for gid := range usedGIDs {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+	for _, gid := range gids {
+		s.use(uint16(gid))
+	}
+
+	for len(s.queue) > 0 {
+		gid := s.queue[0]
+		s.queue = s.queue[1:]
+		s.stack, s.nStems = s.stack[:0], 0
+		if _, err := s.exec(f.charStrings[gid], f.fdIndex(int(gid))); err != nil {
+			return nil, err
+		}
+	}
+
+	endchar, ret := []byte{14}, []byte{11}
+
+	charStrings := make([][]byte, len(f.charStrings))
+	for gid, cs := range f.charStrings {
+		charStrings[gid] = endchar
+		if s.gids[uint16(gid)] {
+			charStrings[gid] = cs
+		}
+	}
+	f.charStrings = charStrings
+
+	subset := func(subrs [][]byte, used map[int]bool) [][]byte {
+		ss := make([][]byte, len(subrs))
+		for i, subr := range subrs {
+			ss[i] = ret
+			if used[i] {
+				ss[i] = subr
+			}
+		}
+		return ss
+	}
+
+	f.gsubrs = subset(f.gsubrs, s.gsubrs)
+	for i := range f.fds {
+		f.fds[i].subrs = subset(f.fds[i].subrs, s.lsubrs[i])
+	}
+
+	return f.bytes(), nil
+}
+
+// writeType2Number writes v as Type 2 charstring operand.
+func writeType2Number(buf *bytes.Buffer, v float64) {
+	i := int(v)
+	if float64(i) != v || i < -32768 || i > 32767 {
+		buf.WriteByte(255)
+		buf.Write(uint32ToBigEndianBytes(uint32(int32(math.Round(v * 65536)))))
+		return
+	}
+	switch {
+	case i >= -107 && i <= 107:
+		buf.WriteByte(byte(i + 139))
+	case i >= 108 && i <= 1131:
+		i -= 108
+		buf.WriteByte(byte(i>>8 + 247))
+		buf.WriteByte(byte(i))
+	case i >= -1131 && i <= -108:
+		i = -i - 108
+		buf.WriteByte(byte(i>>8 + 251))
+		buf.WriteByte(byte(i))
+	default:
+		buf.WriteByte(28)
+		buf.Write(uint16ToBigEndianBytes(uint16(int16(i))))
+	}
+}
+
+// cffStandardEncodingSID returns the SID of the glyph for code c of the StandardEncoding.
+func cffStandardEncodingSID(c int) int {
+	if c >= 32 && c <= 126 {
+		return c - 31
+	}
+	if c >= 161 && c <= 251 {
+		return cffStandardEncodingSIDs[c-161]
+	}
+	return 0
+}
+
+// cffStandardEncodingSIDs are the SIDs for the StandardEncoding codes 161 - 251.
+var cffStandardEncodingSIDs = []int{
+	96, 97, 98, 99, 100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110, 0, // 161 - 176
+	111, 112, 113, 114, 0, 115, 116, 117, 118, 119, 120, 121, 122, 0, 123, 0, // 177 - 192
+	124, 125, 126, 127, 128, 129, 130, 131, 0, 132, 133, 0, 134, 135, 136, 137, // 193 - 208
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, // 209 - 224
+	138, 0, 139, 0, 0, 0, 0, 140, 141, 142, 143, 0, 0, 0, 0, 0, // 225 - 240
+	144, 0, 0, 0, 145, 0, 0, 146, 147, 148, 149, // 241 - 251
+}
+
+// CFFStandardStrings are the predefined strings of the CFF format (SID 0 - 390).
+var CFFStandardStrings = []string{
+	".notdef", "space", "exclam", "quotedbl", "numbersign", "dollar", "percent", "ampersand", "quoteright",
+	"parenleft", "parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash", "zero", "one", "two",
+	"three", "four", "five", "six", "seven", "eight", "nine", "colon", "semicolon", "less", "equal", "greater",
+	"question", "at",
+	"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W",
+	"X", "Y", "Z", "bracketleft", "backslash", "bracketright", "asciicircum", "underscore", "quoteleft",
+	"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w",
+	"x", "y", "z", "braceleft", "bar", "braceright", "asciitilde", "exclamdown", "cent", "sterling", "fraction",
+	"yen", "florin", "section", "currency", "quotesingle", "quotedblleft", "guillemotleft", "guilsinglleft",
+	"guilsinglright", "fi", "fl", "endash", "dagger", "daggerdbl", "periodcentered", "paragraph", "bullet",
+	"quotesinglbase", "quotedblbase", "quotedblright", "guillemotright", "ellipsis", "perthousand",
+	"questiondown", "grave", "acute", "circumflex", "tilde", "macron", "breve", "dotaccent", "dieresis", "ring",
+	"cedilla", "hungarumlaut", "ogonek", "caron", "emdash", "AE", "ordfeminine", "Lslash", "Oslash", "OE",
+	"ordmasculine", "ae", "dotlessi", "lslash", "oslash", "oe", "germandbls", "onesuperior", "logicalnot", "mu",
+	"trademark", "Eth", "onehalf", "plusminus", "Thorn", "onequarter", "divide", "brokenbar", "degree", "thorn",
+	"threequarters", "twosuperior", "registered", "minus", "eth", "multiply", "threesuperior", "copyright",
+	"Aacute", "Acircumflex", "Adieresis", "Agrave", "Aring", "Atilde", "Ccedilla", "Eacute", "Ecircumflex",
+	"Edieresis", "Egrave", "Iacute", "Icircumflex", "Idieresis", "Igrave", "Ntilde", "Oacute", "Ocircumflex",
+	"Odieresis", "Ograve", "Otilde", "Scaron", "Uacute", "Ucircumflex", "Udieresis", "Ugrave", "Yacute",
+	"Ydieresis", "Zcaron", "aacute", "acircumflex", "adieresis", "agrave", "aring", "atilde", "ccedilla",
+	"eacute", "ecircumflex", "edieresis", "egrave", "iacute", "icircumflex", "idieresis", "igrave", "ntilde",
+	"oacute", "ocircumflex", "odieresis", "ograve", "otilde", "scaron", "uacute", "ucircumflex", "udieresis",
+	"ugrave", "yacute", "ydieresis", "zcaron", "exclamsmall", "Hungarumlautsmall", "dollaroldstyle",
+	"dollarsuperior", "ampersandsmall", "Acutesmall", "parenleftsuperior", "parenrightsuperior",
+	"twodotenleader", "onedotenleader", "zerooldstyle", "oneoldstyle", "twooldstyle", "threeoldstyle",
+	"fouroldstyle", "fiveoldstyle", "sixoldstyle", "sevenoldstyle", "eightoldstyle", "nineoldstyle",
+	"commasuperior", "threequartersemdash", "periodsuperior", "questionsmall", "asuperior", "bsuperior",
+	"centsuperior", "dsuperior", "esuperior", "isuperior", "lsuperior", "msuperior", "nsuperior", "osuperior",
+	"rsuperior", "ssuperior", "tsuperior", "ff", "ffi", "ffl", "parenleftinferior", "parenrightinferior",
+	"Circumflexsmall", "hyphensuperior", "Gravesmall", "Asmall", "Bsmall", "Csmall", "Dsmall", "Esmall",
+	"Fsmall", "Gsmall", "Hsmall", "Ismall", "Jsmall", "Ksmall", "Lsmall", "Msmall", "Nsmall", "Osmall", "Psmall",
+	"Qsmall", "Rsmall", "Ssmall", "Tsmall", "Usmall", "Vsmall", "Wsmall", "Xsmall", "Ysmall", "Zsmall",
+	"colonmonetary", "onefitted", "rupiah", "Tildesmall", "exclamdownsmall", "centoldstyle", "Lslashsmall",
+	"Scaronsmall", "Zcaronsmall", "Dieresissmall", "Brevesmall", "Caronsmall", "Dotaccentsmall",
+	"Macronsmall", "figuredash", "hypheninferior", "Ogoneksmall", "Ringsmall", "Cedillasmall",
+	"questiondownsmall", "oneeighth", "threeeighths", "fiveeighths", "seveneighths", "onethird", "twothirds",
+	"zerosuperior", "foursuperior", "fivesuperior", "sixsuperior", "sevensuperior", "eightsuperior",
+	"ninesuperior", "zeroinferior", "oneinferior", "twoinferior", "threeinferior", "fourinferior",
+	"fiveinferior", "sixinferior", "seveninferior", "eightinferior", "nineinferior", "centinferior",
+	"dollarinferior", "periodinferior", "commainferior", "Agravesmall", "Aacutesmall", "Acircumflexsmall",
+	"Atildesmall", "Adieresissmall", "Aringsmall", "AEsmall", "Ccedillasmall", "Egravesmall", "Eacutesmall",
+	"Ecircumflexsmall", "Edieresissmall", "Igravesmall", "Iacutesmall", "Icircumflexsmall", "Idieresissmall",
+	"Ethsmall", "Ntildesmall", "Ogravesmall", "Oacutesmall", "Ocircumflexsmall", "Otildesmall",
+	"Odieresissmall", "OEsmall", "Oslashsmall", "Ugravesmall", "Uacutesmall", "Ucircumflexsmall",
+	"Udieresissmall", "Yacutesmall", "Thornsmall", "Ydieresissmall", "001.000", "001.001", "001.002", "001.003",
+	"Black", "Bold", "Book", "Light", "Medium", "Regular", "Roman", "Semibold",
+}
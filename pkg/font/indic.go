@@ -0,0 +1,365 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+// Indic character categories
+const (
+	catX    = iota
+	catC    // consonant
+	catV    // independent vowel
+	catN    // nukta
+	catH    // halant
+	catM    // dependent vowel aka matra
+	catSM   // syllable modifier
+	catZWJ  // zero width joiner
+	catZWNJ // zero width non-joiner
+)
+
+// Indic glyph positions within a syllable
+const (
+	posNone = iota
+	posReph
+	posPreM
+	posPreC
+	posBase
+	posPostC
+)
+
+type indicConfig struct {
+	preMatras     []rune // block offsets of pre-base matras
+	reph          bool   // script forms reph from initial Ra + halant
+	rephAfterPost bool   // reph goes after post-base matras
+}
+
+// indicConfigs maps the start of Unicode blocks to their script configuration.
+var indicConfigs = map[rune]indicConfig{
+	0x0900: {preMatras: []rune{0x3F, 0x4E}, reph: true},       // Devanagari
+	0x0980: {preMatras: []rune{0x3F, 0x47, 0x48}, reph: true}, // Bengali
+	0x0A00: {preMatras: []rune{0x3F}},                         // Gurmukhi
+	0x0A80: {preMatras: []rune{0x3F}, reph: true},             // Gujarati
+	0x0B00: {preMatras: []rune{0x47}, reph: true},             // Oriya
+	0x0B80: {preMatras: []rune{0x46, 0x47, 0x48}},             // Tamil
+	0x0C00: {reph: true, rephAfterPost: true},                 // Telugu
+	0x0C80: {reph: true, rephAfterPost: true},                 // Kannada
+	0x0D00: {preMatras: []rune{0x46, 0x47, 0x48}, reph: true}, // Malayalam
+}
+
+// indicSplitMatras maps two part matras to their canonical decomposition.
+var indicSplitMatras = map[rune][2]rune{
+	0x09CB: {0x09C7, 0x09BE}, 0x09CC: {0x09C7, 0x09D7},
+	0x0B48: {0x0B47, 0x0B56}, 0x0B4B: {0x0B47, 0x0B3E}, 0x0B4C: {0x0B47, 0x0B57},
+	0x0BCA: {0x0BC6, 0x0BBE}, 0x0BCB: {0x0BC7, 0x0BBE}, 0x0BCC: {0x0BC6, 0x0BD7},
+	0x0D4A: {0x0D46, 0x0D3E}, 0x0D4B: {0x0D47, 0x0D3E}, 0x0D4C: {0x0D46, 0x0D57},
+}
+
+// decomposeSplitMatras decomposes two part matras supported by fd.
+func decomposeSplitMatras(fd *TTFLight, rr []rune) []rune {
+	out := make([]rune, 0, len(rr))
+	for _, r := range rr {
+		if dd, ok := indicSplitMatras[r]; ok {
+			_, ok1 := fd.Chars[uint32(dd[0])]
+			_, ok2 := fd.Chars[uint32(dd[1])]
+			if ok1 && ok2 {
+				out = append(out, dd[0], dd[1])
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func indicCategory(r rune) int {
+	switch r {
+	case 0x200C:
+		return catZWNJ
+	case 0x200D:
+		return catZWJ
+	case 0x25CC: // dotted circle
+		return catC
+	case 0x09F0, 0x09F1: // Assamese ra and wa
+		return catC
+	case 0x0A70, 0x0A71: // Gurmukhi tippi and addak
+		return catSM
+	case 0x0A72, 0x0A73:
+		return catV
+	case 0x0A75:
+		return catM
+	}
+	if r < 0x0900 || r > 0x0D7F {
+		return catX
+	}
+	o := r & 0x7F
+	switch {
+	case o >= 0x01 && o <= 0x03, o >= 0x51 && o <= 0x54:
+		return catSM
+	case o >= 0x04 && o <= 0x14, o == 0x60, o == 0x61:
+		return catV
+	case o >= 0x15 && o <= 0x39, o >= 0x58 && o <= 0x5F:
+		return catC
+	case o == 0x3C:
+		return catN
+	case o >= 0x3E && o <= 0x4C, o == 0x4E, o == 0x4F, o >= 0x55 && o <= 0x57, o == 0x62, o == 0x63:
+		return catM
+	case o == 0x4D:
+		return catH
+	case r >= 0x0972 && r <= 0x0977:
+		return catV
+	case r >= 0x0978 && r <= 0x097F:
+		return catC
+	}
+	return catX
+}
+
+func isRa(r rune) bool {
+	return r&0x7F == 0x30 && r >= 0x0900 && r <= 0x0D7F || r == 0x09F0
+}
+
+func isPostMatra(r rune) bool {
+	switch r & 0x7F {
+	case 0x3E, 0x40, 0x49, 0x4A, 0x4B, 0x4C, 0x57:
+		return true
+	}
+	return false
+}
+
+// indicBlock returns the start of the Unicode block of the first Indic character in buf.
+func indicBlock(buf []glyphInfo) rune {
+	for _, g := range buf {
+		if g.r >= 0x0900 && g.r <= 0x0D7F {
+			return g.r &^ 0x7F
+		}
+	}
+	return 0
+}
+
+// indicSyllableEnd returns the end of the syllable starting at i.
+func indicSyllableEnd(buf []glyphInfo, i int) int {
+	n := len(buf)
+	c := buf[i].cat
+	if c != catC && c != catV {
+		return i + 1
+	}
+	j := i + 1
+	for {
+		for j < n && buf[j].cat == catN {
+			j++
+		}
+		if j < n && buf[j].cat == catH {
+			k := j + 1
+			if k < n && (buf[k].cat == catZWJ || buf[k].cat == catZWNJ) {
+				k++
+			}
+			if c == catC && k < n && buf[k].cat == catC {
+				j = k + 1
+				continue
+			}
+			j = k
+		}
+		break
+	}
+	for j < n && (buf[j].cat == catM || buf[j].cat == catN || buf[j].cat == catH || buf[j].cat == catSM) {
+		j++
+	}
+	return j
+}
+
+// indicInitialReordering segments buf into syllables, moves pre-base matras
+// in front of their syllable and sets up the masks for the basic shaping features.
+func (l *otLayout) indicInitialReordering(buf []glyphInfo, si scriptInfo) []glyphInfo {
+	cfg := indicConfigs[indicBlock(buf)]
+	for i := range buf {
+		buf[i].cat = indicCategory(buf[i].r)
+	}
+	out := make([]glyphInfo, 0, len(buf))
+	for i, syl := 0, 1; i < len(buf); syl++ {
+		j := indicSyllableEnd(buf, i)
+		s := append([]glyphInfo{}, buf[i:j]...)
+		for k := range s {
+			s[k].syllable = syl
+		}
+		if s[0].cat == catC || s[0].cat == catV {
+			s = l.reorderSyllable(s, si, cfg)
+		}
+		out = append(out, s...)
+		i = j
+	}
+	return out
+}
+
+func (l *otLayout) reorderSyllable(s []glyphInfo, si scriptInfo, cfg indicConfig) []glyphInfo {
+	start := 0
+	if cfg.reph && len(s) >= 3 && isRa(s[0].r) && s[1].cat == catH && s[2].cat != catZWJ &&
+		l.wouldSubstitute(si.tags, "rphf", s[0].gid, s[1].gid) {
+		for k := 2; k < len(s); k++ {
+			if s[k].cat == catC {
+				start = 2
+				break
+			}
+		}
+	}
+	for k := 0; k < start; k++ {
+		s[k].pos = posReph
+		s[k].mask |= maskRphf
+	}
+
+	// The base consonant is the last consonant not taking a below-base or post-base form.
+	base := start
+	for k := len(s) - 1; k >= start; k-- {
+		if s[k].cat == catC {
+			base = k
+			break
+		}
+	}
+	for base > start {
+		h := base - 1
+		if s[h].cat != catH {
+			break
+		}
+		if !l.wouldSubstitute(si.tags, "blwf", s[h].gid, s[base].gid) &&
+			!l.wouldSubstitute(si.tags, "pstf", s[h].gid, s[base].gid) {
+			break
+		}
+		p := h - 1
+		for p >= start && s[p].cat == catN {
+			p--
+		}
+		if p < start || s[p].cat != catC {
+			break
+		}
+		base = p
+	}
+
+	for k := start; k < len(s); k++ {
+		switch {
+		case k < base:
+			s[k].pos = posPreC
+			s[k].mask |= maskHalf
+		case k == base:
+			s[k].pos = posBase
+		default:
+			s[k].pos = posPostC
+			s[k].mask |= maskBlwf | maskPstf | maskPref
+		}
+		if s[k].cat == catM {
+			for _, o := range cfg.preMatras {
+				if s[k].r&0x7F == o {
+					s[k].pos = posPreM
+				}
+			}
+		}
+	}
+
+	// Move pre-base matras behind a reph.
+	ss := append([]glyphInfo{}, s[:start]...)
+	for _, g := range s[start:] {
+		if g.pos == posPreM {
+			ss = append(ss, g)
+		}
+	}
+	for _, g := range s[start:] {
+		if g.pos != posPreM {
+			ss = append(ss, g)
+		}
+	}
+	return ss
+}
+
+// moveGlyph moves s[from] to s[to] shifting the glyphs in between.
+func moveGlyph(s []glyphInfo, from, to int) {
+	g := s[from]
+	if from < to {
+		copy(s[from:to], s[from+1:to+1])
+	} else {
+		copy(s[to+1:from+1], s[to:from])
+	}
+	s[to] = g
+}
+
+// indicFinalReordering positions pre-base matras and reph glyphs after the basic shaping features have been applied.
+func indicFinalReordering(buf []glyphInfo) []glyphInfo {
+	cfg := indicConfigs[indicBlock(buf)]
+	for a := 0; a < len(buf); {
+		b := a + 1
+		for b < len(buf) && buf[b].syllable == buf[a].syllable {
+			b++
+		}
+		reorderSyllableFinal(buf[a:b], cfg)
+		a = b
+	}
+	return buf
+}
+
+func reorderSyllableFinal(s []glyphInfo, cfg indicConfig) {
+	base := func() int {
+		for k := range s {
+			if s[k].pos == posBase {
+				return k
+			}
+		}
+		return -1
+	}
+
+	b := base()
+	if b < 0 {
+		return
+	}
+
+	// Pre-base matras go behind the last remaining halant in front of the base.
+	for m := 0; m < b; m++ {
+		if s[m].pos != posPreM {
+			continue
+		}
+		to := -1
+		for k := b - 1; k > m; k-- {
+			if s[k].cat == catH {
+				to = k
+				break
+			}
+		}
+		if to < 0 {
+			break
+		}
+		if to+1 < b && (s[to+1].cat == catZWJ || s[to+1].cat == catZWNJ) {
+			to++
+		}
+		moveGlyph(s, m, to)
+		b = base()
+		break
+	}
+
+	// Reph goes behind the base and below-base forms,
+	// in front of post-base matras or syllable modifiers.
+	n := 0
+	for n < len(s) && s[n].pos == posReph {
+		n++
+	}
+	if n == 0 || !s[0].substDone {
+		return
+	}
+	to := b
+	for to+1 < len(s) {
+		g := s[to+1]
+		if g.cat == catSM || !cfg.rephAfterPost && g.cat == catM && isPostMatra(g.r) {
+			break
+		}
+		to++
+	}
+	for k := 0; k < n; k++ {
+		moveGlyph(s, 0, to)
+	}
+}
@@ -62,6 +62,8 @@ type ttf struct {
 	Chars              map[uint32]uint16 // cmap: Unicode character to glyph index
 	ToUnicode          map[uint16]uint32 // map glyph index to unicode character
 	Planes             map[int]bool      // used Unicode planes
+	Format             FontFormat        // font program format
+	GDEF, GSUB, GPOS   []byte            // OpenType layout tables
 	FontFile           []byte
 }
 
@@ -541,11 +543,7 @@ func headerAndTables(fn string, r io.ReaderAt, baseOff int64) ([]byte, map[strin
 
 	st := string(header[:4])
 
-	if st == sfntVersionCFF {
-		return nil, nil, fmt.Errorf("pdfcpu: %s is based on OpenType CFF and unsupported at the moment :(", fn)
-	}
-
-	if st != sfntVersionTrueType && st != sfntVersionTrueTypeApple {
+	if st != sfntVersionTrueType && st != sfntVersionTrueTypeApple && st != sfntVersionCFF {
 		return nil, nil, fmt.Errorf("pdfcpu: unrecognized font format: %s", fn)
 	}
 
@@ -657,12 +655,33 @@ func installTrueTypeRep(fontDir, fontName string, header []byte, tables map[stri
 		}
 	}
 
+	// Keep OpenType layout tables for text shaping.
+	for tag, bb := range map[string]*[]byte{"GDEF": &fd.GDEF, "GSUB": &fd.GSUB, "GPOS": &fd.GPOS} {
+		if t, ok := tables[tag]; ok && t.size > 0 {
+			*bb = t.data[:t.size]
+		}
+	}
+
 	bb, err := createTTF(header, tables)
 	if err != nil {
 		return err
 	}
 	fd.FontFile = bb
 
+	if t, ok := tables["CFF "]; ok {
+		// Embed the bare CFF font program.
+		fd.Format = FormatCFF
+		if fd.FontFile, err = normalizeCFF(t.data[:t.size]); err != nil {
+			return err
+		}
+	} else if _, ok := tables["CFF2"]; ok {
+		fd.Format = FormatOpenType
+	}
+
+	return installRep(fontDir, fontName, fd)
+}
+
+func installRep(fontDir, fontName string, fd ttf) error {
 	if log.CLIEnabled() {
 		log.CLI.Println(fd.PostscriptName)
 	}
@@ -735,7 +754,7 @@ func InstallTrueTypeCollection(fontDir, fn string) error {
 	return nil
 }
 
-// InstallTrueTypeFont saves an internal representation of TrueType font fontName to the pdfcpu config dir.
+// InstallTrueTypeFont saves an internal representation of TrueType or OpenType font fontName to the pdfcpu config dir.
 func InstallTrueTypeFont(fontDir, fontName string) error {
 	f, err := os.Open(fontName)
 	if err != nil {
@@ -1020,11 +1039,19 @@ func createTTF(header []byte, tables map[string]*table) ([]byte, error) {
 
 // Subset creates a new font file based on usedGIDs.
 func Subset(fontName string, usedGIDs map[uint16]bool) ([]byte, error) {
-	bb, err := Read(fontName)
+	format, bb, err := readFontFile(fontName)
 	if err != nil {
 		return nil, err
 	}
 
+	switch format {
+	case FormatCFF:
+		return subsetCFF(bb, usedGIDs)
+	case FormatOpenType:
+		// CFF2 based fonts are embedded in full.
+		return bb, nil
+	}
+
 	header := bb[:12]
 	tableCount := int(binary.BigEndian.Uint16(header[4:]))
 	tables, err := ttfTables(tableCount, bb)
@@ -0,0 +1,1106 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"unicode"
+)
+
+// OpenType layout tables GDEF, GSUB and GPOS, see https://learn.microsoft.com/en-us/typography/opentype/spec/ttochap1
+
+// Lookup flags
+const (
+	lookupRightToLeft         = 0x0001
+	lookupIgnoreBaseGlyphs    = 0x0002
+	lookupIgnoreLigatures     = 0x0004
+	lookupIgnoreMarks         = 0x0008
+	lookupUseMarkFilteringSet = 0x0010
+)
+
+// GDEF glyph classes
+const (
+	glyphClassBase      = 1
+	glyphClassLigature  = 2
+	glyphClassMark      = 3
+	glyphClassComponent = 4
+)
+
+// Attachment types of positioned glyphs.
+const (
+	attachNone = iota
+	attachMark
+	attachCursive
+)
+
+// maxNesting limits the recursion depth of contextual lookups.
+const maxNesting = 6
+
+// otData provides bounds checked big endian access to OpenType table data.
+type otData []byte
+
+func (d otData) u16(off int) int {
+	if off < 0 || off+2 > len(d) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(d[off:]))
+}
+
+func (d otData) i16(off int) int {
+	return int(int16(d.u16(off)))
+}
+
+func (d otData) u32(off int) int {
+	if off < 0 || off+4 > len(d) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint32(d[off:]))
+}
+
+func (d otData) tag(off int) string {
+	if off < 0 || off+4 > len(d) {
+		return ""
+	}
+	return string(d[off : off+4])
+}
+
+// sub returns the data starting at off, nil for a null or invalid offset.
+func (d otData) sub(off int) otData {
+	if off <= 0 || off >= len(d) {
+		return nil
+	}
+	return d[off:]
+}
+
+// coverage returns the coverage index of gid.
+func (d otData) coverage(gid uint16) (int, bool) {
+	g := int(gid)
+	switch d.u16(0) {
+	case 1:
+		n := d.u16(2)
+		i := sort.Search(n, func(i int) bool { return d.u16(4+2*i) >= g })
+		if i < n && d.u16(4+2*i) == g {
+			return i, true
+		}
+	case 2:
+		n := d.u16(2)
+		i := sort.Search(n, func(i int) bool { return d.u16(4+6*i+2) >= g })
+		if i < n && d.u16(4+6*i) <= g {
+			return d.u16(4+6*i+4) + g - d.u16(4+6*i), true
+		}
+	}
+	return 0, false
+}
+
+// class returns the class of gid as defined by a class definition table.
+func (d otData) class(gid uint16) int {
+	g := int(gid)
+	switch d.u16(0) {
+	case 1:
+		start, n := d.u16(2), d.u16(4)
+		if g >= start && g < start+n {
+			return d.u16(6 + 2*(g-start))
+		}
+	case 2:
+		n := d.u16(2)
+		i := sort.Search(n, func(i int) bool { return d.u16(4+6*i+2) >= g })
+		if i < n && d.u16(4+6*i) <= g {
+			return d.u16(4 + 6*i + 4)
+		}
+	}
+	return 0
+}
+
+// anchor returns the coordinates of an anchor table.
+func (d otData) anchor() (int, int, bool) {
+	if d == nil {
+		return 0, 0, false
+	}
+	return d.i16(2), d.i16(4), true
+}
+
+type otLookup struct {
+	typ     int
+	flag    int
+	markSet int
+	subs    []otData
+}
+
+// otTable represents a GSUB or GPOS table.
+type otTable struct {
+	data    otData
+	gpos    bool
+	lookups []*otLookup
+}
+
+func parseOTTable(bb []byte, gpos bool) *otTable {
+	if len(bb) < 10 {
+		return nil
+	}
+	t := &otTable{data: otData(bb), gpos: gpos}
+	ll := t.data.sub(t.data.u16(8))
+	n := ll.u16(0)
+	t.lookups = make([]*otLookup, n)
+	for i := 0; i < n; i++ {
+		d := ll.sub(ll.u16(2 + 2*i))
+		l := &otLookup{typ: d.u16(0), flag: d.u16(2), markSet: -1}
+		c := d.u16(4)
+		if l.flag&lookupUseMarkFilteringSet != 0 {
+			l.markSet = d.u16(6 + 2*c)
+		}
+		ext := 7
+		if gpos {
+			ext = 9
+		}
+		for j := 0; j < c; j++ {
+			sub := d.sub(d.u16(6 + 2*j))
+			if l.typ == ext {
+				// Extension lookups refer to subtables of the actual lookup type using 32 bit offsets.
+				l.typ = sub.u16(2)
+				sub = sub.sub(sub.u32(4))
+			}
+			if sub != nil {
+				l.subs = append(l.subs, sub)
+			}
+		}
+		t.lookups[i] = l
+	}
+	return t
+}
+
+// langSys returns the default language system of the first available script out of scripts.
+func (t *otTable) langSys(scripts []string) otData {
+	sl := t.data.sub(t.data.u16(4))
+	n := sl.u16(0)
+	for _, script := range append(append([]string{}, scripts...), "DFLT", "latn") {
+		for i := 0; i < n; i++ {
+			if sl.tag(2+6*i) == script {
+				s := sl.sub(sl.u16(2 + 6*i + 4))
+				return s.sub(s.u16(0))
+			}
+		}
+	}
+	return nil
+}
+
+// hasScript returns true if t supports script.
+func (t *otTable) hasScript(script string) bool {
+	sl := t.data.sub(t.data.u16(4))
+	for i := 0; i < sl.u16(0); i++ {
+		if sl.tag(2+6*i) == script {
+			return true
+		}
+	}
+	return false
+}
+
+// featureLookups returns the lookup indices for feature tag of language system ls.
+func (t *otTable) featureLookups(ls otData, tag string) []int {
+	if ls == nil {
+		return nil
+	}
+	fl := t.data.sub(t.data.u16(6))
+	var ll []int
+	add := func(fi int) {
+		if fi >= fl.u16(0) || fl.tag(2+6*fi) != tag {
+			return
+		}
+		f := fl.sub(fl.u16(2 + 6*fi + 4))
+		for i := 0; i < f.u16(2); i++ {
+			ll = append(ll, f.u16(4+2*i))
+		}
+	}
+	if req := ls.u16(2); req != 0xFFFF {
+		add(req)
+	}
+	for i := 0; i < ls.u16(4); i++ {
+		add(ls.u16(6 + 2*i))
+	}
+	return ll
+}
+
+// glyphInfo represents a glyph during shaping.
+type glyphInfo struct {
+	gid     uint16
+	r       rune // the first rune represented by this glyph
+	mask    uint32
+	class   int // GDEF glyph class
+	ligID   int // ligature id for mark attachment
+	ligComp int // ligature component of marks following a ligature
+
+	xAdv, xOff, yOff int // in glyph space units
+
+	attach    int  // attachment type
+	attachTo  int  // index of the glyph this glyph is attached to
+	substDone bool // glyph has been substituted
+	hidden    bool // default ignorable glyph
+
+	cat, pos int // Indic character category and position
+	syllable int // Indic syllable id
+}
+
+// otLayout applies OpenType layout features of a font.
+type otLayout struct {
+	fd         *TTFLight
+	gdef       otData
+	glyphClass otData
+	markClass  otData
+	markSets   otData
+	gsub, gpos *otTable
+	nextLigID  int
+	runes      map[uint16][]rune // lazily derived glyph to Unicode mapping
+	mu         sync.Mutex
+}
+
+func newOTLayout(fd *TTFLight) *otLayout {
+	l := &otLayout{fd: fd, gsub: parseOTTable(fd.GSUB, false), gpos: parseOTTable(fd.GPOS, true)}
+	if len(fd.GDEF) >= 12 {
+		l.gdef = otData(fd.GDEF)
+		l.glyphClass = l.gdef.sub(l.gdef.u16(4))
+		l.markClass = l.gdef.sub(l.gdef.u16(10))
+		if l.gdef.u16(0) == 1 && l.gdef.u16(2) >= 2 {
+			l.markSets = l.gdef.sub(l.gdef.u16(12))
+		}
+	}
+	return l
+}
+
+// scale transforms font units into glyph space units.
+func (l *otLayout) scale(v int) int {
+	if l.fd.UnitsPerEm == 0 {
+		return v
+	}
+	return v * 1000 / l.fd.UnitsPerEm
+}
+
+func (l *otLayout) classify(g *glyphInfo) {
+	if l.glyphClass != nil {
+		g.class = l.glyphClass.class(g.gid)
+		return
+	}
+	// Synthesize glyph classes w/o GDEF.
+	g.class = glyphClassBase
+	if unicode.In(g.r, unicode.Mn, unicode.Me) {
+		g.class = glyphClassMark
+	}
+}
+
+func (l *otLayout) inMarkSet(set int, gid uint16) bool {
+	if l.markSets == nil || set >= l.markSets.u16(2) {
+		return false
+	}
+	_, ok := l.markSets.sub(l.markSets.u32(4 + 4*set)).coverage(gid)
+	return ok
+}
+
+// ignored returns true if lookup lk skips g.
+func (l *otLayout) ignored(lk *otLookup, g *glyphInfo) bool {
+	switch g.class {
+	case glyphClassBase:
+		return lk.flag&lookupIgnoreBaseGlyphs != 0
+	case glyphClassLigature:
+		return lk.flag&lookupIgnoreLigatures != 0
+	case glyphClassMark:
+		if lk.flag&lookupIgnoreMarks != 0 {
+			return true
+		}
+		if lk.flag&lookupUseMarkFilteringSet != 0 {
+			return !l.inMarkSet(lk.markSet, g.gid)
+		}
+		if t := lk.flag >> 8; t != 0 && l.markClass != nil {
+			return l.markClass.class(g.gid) != t
+		}
+	}
+	return false
+}
+
+// shapeCtx holds the glyph buffer during the application of lookups.
+type shapeCtx struct {
+	l     *otLayout
+	t     *otTable
+	buf   []glyphInfo
+	rtl   bool
+	depth int
+}
+
+func (c *shapeCtx) next(lk *otLookup, i int) int {
+	for i++; i < len(c.buf); i++ {
+		if !c.l.ignored(lk, &c.buf[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *shapeCtx) prev(lk *otLookup, i int) int {
+	for i--; i >= 0; i-- {
+		if !c.l.ignored(lk, &c.buf[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// apply applies the lookups ll to all glyphs matching mask.
+func (c *shapeCtx) apply(ll []int, masks map[int]uint32) {
+	for _, li := range ll {
+		if li >= len(c.t.lookups) {
+			continue
+		}
+		lk := c.t.lookups[li]
+		mask := masks[li]
+		if !c.t.gpos && lk.typ == 8 {
+			// Reverse chaining contextual single substitution
+			for i := len(c.buf) - 1; i >= 0; i-- {
+				if c.buf[i].mask&mask != 0 && !c.l.ignored(lk, &c.buf[i]) {
+					c.applyLookup(lk, i)
+				}
+			}
+			continue
+		}
+		for i := 0; i < len(c.buf); {
+			if c.buf[i].mask&mask == 0 || c.l.ignored(lk, &c.buf[i]) {
+				i++
+				continue
+			}
+			if n := c.applyLookup(lk, i); n > 0 {
+				i += n
+				continue
+			}
+			i++
+		}
+	}
+}
+
+// applyLookup applies lk at position i and returns the number of glyphs consumed or 0 if not applicable.
+func (c *shapeCtx) applyLookup(lk *otLookup, i int) int {
+	for _, sub := range lk.subs {
+		var n int
+		if c.t.gpos {
+			n = c.applyPos(lk, sub, i)
+		} else {
+			n = c.applySubst(lk, sub, i)
+		}
+		if n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func (c *shapeCtx) applyLookupIndex(li, i int) {
+	if li >= len(c.t.lookups) || c.depth >= maxNesting || i >= len(c.buf) {
+		return
+	}
+	c.depth++
+	c.applyLookup(c.t.lookups[li], i)
+	c.depth--
+}
+
+func (c *shapeCtx) replace(i int, gids ...uint16) {
+	g := c.buf[i]
+	g.substDone = true
+	gg := make([]glyphInfo, len(gids))
+	for j, gid := range gids {
+		gg[j] = g
+		gg[j].gid = gid
+		c.l.classify(&gg[j])
+	}
+	c.buf = append(c.buf[:i], append(gg, c.buf[i+1:]...)...)
+}
+
+func (c *shapeCtx) applySubst(lk *otLookup, d otData, i int) int {
+	gid := c.buf[i].gid
+	switch lk.typ {
+	case 1: // Single
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok {
+			return 0
+		}
+		if d.u16(0) == 1 {
+			c.replace(i, uint16(int(gid)+d.i16(4)))
+		} else {
+			c.replace(i, uint16(d.u16(6+2*idx)))
+		}
+		return 1
+
+	case 2: // Multiple
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok || idx >= d.u16(4) {
+			return 0
+		}
+		seq := d.sub(d.u16(6 + 2*idx))
+		gids := make([]uint16, seq.u16(0))
+		for j := range gids {
+			gids[j] = uint16(seq.u16(2 + 2*j))
+		}
+		c.replace(i, gids...)
+		return max(len(gids), 1)
+
+	case 3: // Alternate
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok || idx >= d.u16(4) {
+			return 0
+		}
+		alt := d.sub(d.u16(6 + 2*idx))
+		if alt.u16(0) == 0 {
+			return 0
+		}
+		c.replace(i, uint16(alt.u16(2)))
+		return 1
+
+	case 4: // Ligature
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok || idx >= d.u16(4) {
+			return 0
+		}
+		set := d.sub(d.u16(6 + 2*idx))
+		for k := 0; k < set.u16(0); k++ {
+			lig := set.sub(set.u16(2 + 2*k))
+			if c.ligate(lk, lig, i) {
+				return 1
+			}
+		}
+		return 0
+
+	case 5, 6:
+		return c.applyContext(lk, d, i, lk.typ == 6)
+
+	case 8: // Reverse chaining contextual single
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok {
+			return 0
+		}
+		bc := d.u16(4)
+		j := i
+		for k := 0; k < bc; k++ {
+			if j = c.prev(lk, j); j < 0 {
+				return 0
+			}
+			if _, ok := d.sub(d.u16(6 + 2*k)).coverage(c.buf[j].gid); !ok {
+				return 0
+			}
+		}
+		off := 6 + 2*bc
+		lc := d.u16(off)
+		j = i
+		for k := 0; k < lc; k++ {
+			if j = c.next(lk, j); j < 0 {
+				return 0
+			}
+			if _, ok := d.sub(d.u16(off + 2 + 2*k)).coverage(c.buf[j].gid); !ok {
+				return 0
+			}
+		}
+		off += 2 + 2*lc
+		if idx >= d.u16(off) {
+			return 0
+		}
+		c.buf[i].gid = uint16(d.u16(off + 2 + 2*idx))
+		c.buf[i].substDone = true
+		c.l.classify(&c.buf[i])
+		return 1
+	}
+	return 0
+}
+
+// ligate replaces the glyph at i and its components by ligature lig.
+func (c *shapeCtx) ligate(lk *otLookup, lig otData, i int) bool {
+	n := lig.u16(2)
+	pos := []int{i}
+	j := i
+	for k := 1; k < n; k++ {
+		if j = c.next(lk, j); j < 0 || int(c.buf[j].gid) != lig.u16(4+2*(k-1)) {
+			return false
+		}
+		pos = append(pos, j)
+	}
+
+	c.l.nextLigID++
+	id := c.l.nextLigID
+
+	// Marks skipped in between get attached to their ligature component.
+	comp := 1
+	for k := i + 1; k <= pos[len(pos)-1]; k++ {
+		if comp < len(pos) && k == pos[comp] {
+			comp++
+			continue
+		}
+		c.buf[k].ligID, c.buf[k].ligComp = id, comp
+	}
+	// Trailing marks belong to the last component.
+	for k := pos[len(pos)-1] + 1; k < len(c.buf) && c.buf[k].class == glyphClassMark; k++ {
+		c.buf[k].ligID, c.buf[k].ligComp = id, n
+	}
+
+	for k := len(pos) - 1; k > 0; k-- {
+		c.buf = append(c.buf[:pos[k]], c.buf[pos[k]+1:]...)
+	}
+	c.buf[i].gid = uint16(lig.u16(0))
+	c.buf[i].substDone = true
+	c.buf[i].ligID = id
+	c.l.classify(&c.buf[i])
+	if c.l.glyphClass == nil && n > 1 {
+		c.buf[i].class = glyphClassLigature
+	}
+	return true
+}
+
+// matcher reports whether glyph gid matches value v of a context rule.
+type matcher func(gid uint16, v int) bool
+
+func glyphMatcher(gid uint16, v int) bool {
+	return int(gid) == v
+}
+
+func classMatcher(cd otData) matcher {
+	return func(gid uint16, v int) bool { return cd.class(gid) == v }
+}
+
+func coverageMatcher(d otData) matcher {
+	return func(gid uint16, v int) bool {
+		_, ok := d.sub(v).coverage(gid)
+		return ok
+	}
+}
+
+// matchInput matches the input sequence following i and returns the positions of all matched glyphs.
+func (c *shapeCtx) matchInput(lk *otLookup, i int, vals []int, m matcher) []int {
+	pos := []int{i}
+	j := i
+	for _, v := range vals {
+		if j = c.next(lk, j); j < 0 || !m(c.buf[j].gid, v) {
+			return nil
+		}
+		pos = append(pos, j)
+	}
+	return pos
+}
+
+func (c *shapeCtx) matchBacktrack(lk *otLookup, i int, vals []int, m matcher) bool {
+	j := i
+	for _, v := range vals {
+		if j = c.prev(lk, j); j < 0 || !m(c.buf[j].gid, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *shapeCtx) matchLookahead(lk *otLookup, i int, vals []int, m matcher) bool {
+	j := i
+	for _, v := range vals {
+		if j = c.next(lk, j); j < 0 || !m(c.buf[j].gid, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func u16s(d otData, off, n int) []int {
+	vv := make([]int, n)
+	for i := range vv {
+		vv[i] = d.u16(off + 2*i)
+	}
+	return vv
+}
+
+// applyRecords applies the nested lookups of a matched context rule.
+func (c *shapeCtx) applyRecords(d otData, off, n int, pos []int) int {
+	end := pos[len(pos)-1]
+	for k := 0; k < n; k++ {
+		seq, li := d.u16(off+4*k), d.u16(off+4*k+2)
+		if seq >= len(pos) {
+			continue
+		}
+		l1 := len(c.buf)
+		p := pos[seq]
+		c.applyLookupIndex(li, p)
+		if delta := len(c.buf) - l1; delta != 0 {
+			// Adjust the positions of following glyphs.
+			for m := range pos {
+				if pos[m] > p {
+					pos[m] += delta
+				}
+			}
+			end += delta
+		}
+	}
+	return max(end-pos[0]+1, 1)
+}
+
+// applyContext applies contextual (GSUB 5, GPOS 7) and chained contextual (GSUB 6, GPOS 8) lookups.
+func (c *shapeCtx) applyContext(lk *otLookup, d otData, i int, chained bool) int {
+	gid := c.buf[i].gid
+	switch d.u16(0) {
+	case 1:
+		idx, ok := d.sub(d.u16(2)).coverage(gid)
+		if !ok || idx >= d.u16(4) {
+			return 0
+		}
+		set := d.sub(d.u16(6 + 2*idx))
+		for k := 0; k < set.u16(0); k++ {
+			r := set.sub(set.u16(2 + 2*k))
+			if n := c.applyRule(lk, r, i, chained, glyphMatcher, glyphMatcher, glyphMatcher); n > 0 {
+				return n
+			}
+		}
+
+	case 2:
+		if _, ok := d.sub(d.u16(2)).coverage(gid); !ok {
+			return 0
+		}
+		var bm, im, lm matcher
+		off := 4
+		if chained {
+			bm = classMatcher(d.sub(d.u16(4)))
+			im = classMatcher(d.sub(d.u16(6)))
+			lm = classMatcher(d.sub(d.u16(8)))
+			off = 10
+		} else {
+			im = classMatcher(d.sub(d.u16(4)))
+			off = 6
+		}
+		cls := 0
+		if chained {
+			cls = d.sub(d.u16(6)).class(gid)
+		} else {
+			cls = d.sub(d.u16(4)).class(gid)
+		}
+		if cls >= d.u16(off) {
+			return 0
+		}
+		set := d.sub(d.u16(off + 2 + 2*cls))
+		for k := 0; k < set.u16(0); k++ {
+			r := set.sub(set.u16(2 + 2*k))
+			if n := c.applyRule(lk, r, i, chained, bm, im, lm); n > 0 {
+				return n
+			}
+		}
+
+	case 3:
+		m := coverageMatcher(d)
+		if !chained {
+			ic, rc := d.u16(2), d.u16(4)
+			if ic == 0 || !m(gid, d.u16(6)) {
+				return 0
+			}
+			pos := c.matchInput(lk, i, u16s(d, 8, ic-1), m)
+			if pos == nil {
+				return 0
+			}
+			return c.applyRecords(d, 6+2*ic, rc, pos)
+		}
+		bc := d.u16(2)
+		off := 4 + 2*bc
+		ic := d.u16(off)
+		if ic == 0 || !m(gid, d.u16(off+2)) {
+			return 0
+		}
+		in := u16s(d, off+4, ic-1)
+		off += 2 + 2*ic
+		lc := d.u16(off)
+		la := u16s(d, off+2, lc)
+		off += 2 + 2*lc
+		rc := d.u16(off)
+		pos := c.matchInput(lk, i, in, m)
+		if pos == nil || !c.matchBacktrack(lk, i, u16s(d, 4, bc), m) || !c.matchLookahead(lk, pos[len(pos)-1], la, m) {
+			return 0
+		}
+		return c.applyRecords(d, off+2, rc, pos)
+	}
+	return 0
+}
+
+// applyRule applies a (chained) sequence rule of format 1 or 2.
+func (c *shapeCtx) applyRule(lk *otLookup, r otData, i int, chained bool, bm, im, lm matcher) int {
+	if !chained {
+		ic, rc := r.u16(0), r.u16(2)
+		if ic == 0 {
+			return 0
+		}
+		pos := c.matchInput(lk, i, u16s(r, 4, ic-1), im)
+		if pos == nil {
+			return 0
+		}
+		return c.applyRecords(r, 4+2*(ic-1), rc, pos)
+	}
+	bc := r.u16(0)
+	off := 2 + 2*bc
+	ic := r.u16(off)
+	if ic == 0 {
+		return 0
+	}
+	in := u16s(r, off+2, ic-1)
+	off += 2 + 2*(ic-1)
+	lc := r.u16(off)
+	la := u16s(r, off+2, lc)
+	off += 2 + 2*lc
+	rc := r.u16(off)
+	pos := c.matchInput(lk, i, in, im)
+	if pos == nil || !c.matchBacktrack(lk, i, u16s(r, 2, bc), bm) || !c.matchLookahead(lk, pos[len(pos)-1], la, lm) {
+		return 0
+	}
+	return c.applyRecords(r, off+2, rc, pos)
+}
+
+// valueRecordSize returns the size of a value record of format f.
+func valueRecordSize(f int) int {
+	n := 0
+	for ; f != 0; f >>= 1 {
+		n += f & 1
+	}
+	return 2 * n
+}
+
+// adjust applies a value record of format f at off to g.
+func (c *shapeCtx) adjust(g *glyphInfo, d otData, off, f int) {
+	if f&0x01 != 0 {
+		g.xOff += c.l.scale(d.i16(off))
+		off += 2
+	}
+	if f&0x02 != 0 {
+		g.yOff += c.l.scale(d.i16(off))
+		off += 2
+	}
+	if f&0x04 != 0 {
+		g.xAdv += c.l.scale(d.i16(off))
+	}
+	// Vertical advance and device tables are ignored.
+}
+
+func (c *shapeCtx) applyPos(lk *otLookup, d otData, i int) int {
+	g := &c.buf[i]
+	switch lk.typ {
+	case 1: // Single adjustment
+		idx, ok := d.sub(d.u16(2)).coverage(g.gid)
+		if !ok {
+			return 0
+		}
+		f := d.u16(4)
+		if d.u16(0) == 1 {
+			c.adjust(g, d, 6, f)
+		} else {
+			c.adjust(g, d, 8+idx*valueRecordSize(f), f)
+		}
+		return 1
+
+	case 2: // Pair adjustment
+		idx, ok := d.sub(d.u16(2)).coverage(g.gid)
+		if !ok {
+			return 0
+		}
+		j := c.next(lk, i)
+		if j < 0 {
+			return 0
+		}
+		g2 := &c.buf[j]
+		f1, f2 := d.u16(4), d.u16(6)
+		s1, s2 := valueRecordSize(f1), valueRecordSize(f2)
+		switch d.u16(0) {
+		case 1:
+			if idx >= d.u16(8) {
+				return 0
+			}
+			ps := d.sub(d.u16(10 + 2*idx))
+			rs := 2 + s1 + s2
+			n := ps.u16(0)
+			k := sort.Search(n, func(k int) bool { return ps.u16(2+k*rs) >= int(g2.gid) })
+			if k >= n || ps.u16(2+k*rs) != int(g2.gid) {
+				return 0
+			}
+			off := 2 + k*rs + 2
+			c.adjust(g, ps, off, f1)
+			c.adjust(g2, ps, off+s1, f2)
+		case 2:
+			c1 := d.sub(d.u16(8)).class(g.gid)
+			c2 := d.sub(d.u16(10)).class(g2.gid)
+			n1, n2 := d.u16(12), d.u16(14)
+			if c1 >= n1 || c2 >= n2 {
+				return 0
+			}
+			off := 16 + (c1*n2+c2)*(s1+s2)
+			c.adjust(g, d, off, f1)
+			c.adjust(g2, d, off+s1, f2)
+		default:
+			return 0
+		}
+		if f2 != 0 {
+			return j - i + 1
+		}
+		return j - i
+
+	case 3: // Cursive attachment
+		cov := d.sub(d.u16(2))
+		idx, ok := cov.coverage(g.gid)
+		if !ok || idx >= d.u16(4) {
+			return 0
+		}
+		entry := d.sub(d.u16(6 + 4*idx))
+		ex, ey, ok := entry.anchor()
+		if !ok {
+			return 0
+		}
+		j := c.prev(lk, i)
+		if j < 0 {
+			return 0
+		}
+		pidx, ok := cov.coverage(c.buf[j].gid)
+		if !ok || pidx >= d.u16(4) {
+			return 0
+		}
+		xx, xy, ok := d.sub(d.u16(6 + 4*pidx + 2)).anchor()
+		if !ok {
+			return 0
+		}
+		ex, ey, xx, xy = c.l.scale(ex), c.l.scale(ey), c.l.scale(xx), c.l.scale(xy)
+		prev := &c.buf[j]
+		if !c.rtl {
+			prev.xAdv = xx + prev.xOff
+			dd := ex + g.xOff
+			g.xAdv -= dd
+			g.xOff -= dd
+		} else {
+			dd := xx + prev.xOff
+			prev.xAdv -= dd
+			prev.xOff -= dd
+			g.xAdv = ex + g.xOff
+		}
+		// Attach the child to its parent in cross direction.
+		if lk.flag&lookupRightToLeft == 0 {
+			g.attach, g.attachTo, g.yOff = attachCursive, j, xy-ey
+		} else {
+			prev.attach, prev.attachTo, prev.yOff = attachCursive, i, ey-xy
+		}
+		return 1
+
+	case 4, 5, 6: // Mark attachment
+		return c.attachMark(lk, d, i)
+
+	case 7, 8:
+		return c.applyContext(lk, d, i, lk.typ == 8)
+	}
+	return 0
+}
+
+func (c *shapeCtx) attachMark(lk *otLookup, d otData, i int) int {
+	g := &c.buf[i]
+	idx, ok := d.sub(d.u16(2)).coverage(g.gid)
+	if !ok {
+		return 0
+	}
+
+	// Find the glyph to attach to.
+	j := i - 1
+	switch lk.typ {
+	case 4, 5:
+		for ; j >= 0 && c.buf[j].class == glyphClassMark; j-- {
+		}
+	case 6:
+		if j = c.prev(lk, i); j >= 0 && c.buf[j].class != glyphClassMark {
+			return 0
+		}
+	}
+	if j < 0 {
+		return 0
+	}
+
+	bidx, ok := d.sub(d.u16(4)).coverage(c.buf[j].gid)
+	if !ok {
+		return 0
+	}
+
+	classCount := d.u16(6)
+	ma := d.sub(d.u16(8))
+	if idx >= ma.u16(0) {
+		return 0
+	}
+	cls := ma.u16(2 + 4*idx)
+	mx, my, ok := ma.sub(ma.u16(2 + 4*idx + 2)).anchor()
+	if !ok || cls >= classCount {
+		return 0
+	}
+
+	arr := d.sub(d.u16(10))
+	if bidx >= arr.u16(0) {
+		return 0
+	}
+
+	var bx, by int
+	switch lk.typ {
+	case 4, 6:
+		off := 2 + 2*(bidx*classCount+cls)
+		bx, by, ok = arr.sub(arr.u16(off)).anchor()
+	case 5:
+		la := arr.sub(arr.u16(2 + 2*bidx))
+		n := la.u16(0)
+		comp := n
+		if g.ligID != 0 && g.ligID == c.buf[j].ligID && g.ligComp > 0 {
+			comp = min(g.ligComp, n)
+		}
+		if comp == 0 {
+			return 0
+		}
+		off := 2 + 2*((comp-1)*classCount+cls)
+		bx, by, ok = la.sub(la.u16(off)).anchor()
+	}
+	if !ok {
+		return 0
+	}
+
+	g.attach, g.attachTo = attachMark, j
+	g.xOff = c.l.scale(bx - mx)
+	g.yOff = c.l.scale(by - my)
+	return 1
+}
+
+// stage is a set of features whose lookups get applied together.
+type stage []string
+
+// applyStages applies the feature stages of t to buf.
+func (l *otLayout) applyStages(t *otTable, ls otData, buf []glyphInfo, stages []stage, rtl bool) []glyphInfo {
+	if t == nil || ls == nil {
+		return buf
+	}
+	c := &shapeCtx{l: l, t: t, buf: buf, rtl: rtl}
+	for _, st := range stages {
+		masks := map[int]uint32{}
+		for _, tag := range st {
+			for _, li := range t.featureLookups(ls, tag) {
+				masks[li] |= featureMask(tag)
+			}
+		}
+		ll := make([]int, 0, len(masks))
+		for li := range masks {
+			ll = append(ll, li)
+		}
+		sort.Ints(ll)
+		c.apply(ll, masks)
+	}
+	return c.buf
+}
+
+// wouldSubstitute returns true if feature tag substitutes the glyph sequence gids.
+func (l *otLayout) wouldSubstitute(scripts []string, tag string, gids ...uint16) bool {
+	if l.gsub == nil {
+		return false
+	}
+	buf := make([]glyphInfo, len(gids))
+	for i, gid := range gids {
+		buf[i] = glyphInfo{gid: gid, mask: featureMask(tag)}
+		l.classify(&buf[i])
+	}
+	buf = l.applyStages(l.gsub, l.gsub.langSys(scripts), buf, []stage{{tag}}, false)
+	for _, g := range buf {
+		if g.substDone {
+			return true
+		}
+	}
+	return false
+}
+
+// glyphRunes derives Unicode values for glyphs created by GSUB substitutions.
+func (l *otLayout) glyphRunes() map[uint16][]rune {
+	m := map[uint16][]rune{}
+	for gid, u := range l.fd.ToUnicode {
+		m[gid] = []rune{rune(u)}
+	}
+	if l.gsub == nil {
+		return m
+	}
+
+	set := func(gid int, rr []rune) bool {
+		if _, ok := m[uint16(gid)]; ok || len(rr) == 0 {
+			return false
+		}
+		m[uint16(gid)] = rr
+		return true
+	}
+
+	// covered returns all glyphs of a coverage table.
+	covered := func(d otData) []int {
+		var gg []int
+		switch d.u16(0) {
+		case 1:
+			gg = u16s(d, 4, d.u16(2))
+		case 2:
+			for i := 0; i < d.u16(2); i++ {
+				for g := d.u16(4 + 6*i); g <= d.u16(4+6*i+2) && len(gg) < 0x10000; g++ {
+					gg = append(gg, g)
+				}
+			}
+		}
+		return gg
+	}
+
+	for pass, changed := 0, true; changed && pass < 4; pass++ {
+		changed = false
+		for _, lk := range l.gsub.lookups {
+			for _, d := range lk.subs {
+				gg := covered(d.sub(d.u16(2)))
+				for idx, g := range gg {
+					rr, ok := m[uint16(g)]
+					if !ok {
+						continue
+					}
+					switch lk.typ {
+					case 1:
+						if d.u16(0) == 1 {
+							changed = set((g+d.i16(4))&0xFFFF, rr) || changed
+						} else if idx < d.u16(4) {
+							changed = set(d.u16(6+2*idx), rr) || changed
+						}
+					case 3:
+						if idx < d.u16(4) {
+							alt := d.sub(d.u16(6 + 2*idx))
+							for k := 0; k < alt.u16(0); k++ {
+								changed = set(alt.u16(2+2*k), rr) || changed
+							}
+						}
+					case 4:
+						if idx >= d.u16(4) {
+							continue
+						}
+						ls := d.sub(d.u16(6 + 2*idx))
+						for k := 0; k < ls.u16(0); k++ {
+							lig := ls.sub(ls.u16(2 + 2*k))
+							rs := append([]rune{}, rr...)
+							for _, comp := range u16s(lig, 4, lig.u16(2)-1) {
+								cr, ok := m[uint16(comp)]
+								if !ok {
+									rs = nil
+									break
+								}
+								rs = append(rs, cr...)
+							}
+							changed = set(lig.u16(0), rs) || changed
+						}
+					case 8:
+						off := 6 + 2*d.u16(4)
+						off += 2 + 2*d.u16(off)
+						if idx < d.u16(off) {
+							changed = set(d.u16(off+2+2*idx), rr) || changed
+						}
+					}
+				}
+			}
+		}
+	}
+	return m
+}
@@ -34,6 +34,16 @@ import (
 	"github.com/pkg/errors"
 )
 
+// FontFormat is the format of an installed font program.
+type FontFormat int
+
+// Font program formats of user fonts.
+const (
+	FormatTrueType FontFormat = iota // glyf based TrueType, embedded using FontFile2
+	FormatCFF                        // bare CFF from OpenType or Type 1 fonts, embedded using FontFile3
+	FormatOpenType                   // OpenType with CFF2 outlines, embedded using FontFile3
+)
+
 // TTFLight represents a TrueType font w/o font file.
 type TTFLight struct {
 	PostscriptName     string            // name: NameID 6
@@ -55,6 +65,8 @@ type TTFLight struct {
 	Chars              map[uint32]uint16 // cmap: Unicode character to glyph index
 	ToUnicode          map[uint16]uint32 // map glyph index to unicode character
 	Planes             map[int]bool      // used Unicode planes
+	Format             FontFormat        // font program format
+	GDEF, GSUB, GPOS   []byte            // OpenType layout tables
 }
 
 func (fd TTFLight) String() string {
@@ -212,16 +224,24 @@ func load(fileName string, fd *TTFLight) error {
 
 // Read reads in the font file bytes from gob
 func Read(fileName string) ([]byte, error) {
+	_, bb, err := readFontFile(fileName)
+	return bb, err
+}
+
+func readFontFile(fileName string) (FontFormat, []byte, error) {
 	fn := filepath.Join(UserFontDir, fileName+".gob")
 	f, err := os.Open(fn)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 	defer f.Close()
 	dec := gob.NewDecoder(f)
-	ff := &struct{ FontFile []byte }{}
+	ff := &struct {
+		Format   FontFormat
+		FontFile []byte
+	}{}
 	err = dec.Decode(ff)
-	return ff.FontFile, err
+	return ff.Format, ff.FontFile, err
 }
 
 func isSupportedFontFile(filename string) bool {
@@ -250,6 +270,9 @@ func LoadUserFonts() error {
 		UserFontMetricsLock.Lock()
 		UserFontMetrics[fn] = ttf
 		UserFontMetricsLock.Unlock()
+		layoutsLock.Lock()
+		delete(layouts, fn)
+		layoutsLock.Unlock()
 	}
 	return nil
 }
@@ -289,6 +312,18 @@ func CharWidth(fontName string, r rune) int {
 	return int(ttf.GlyphWidths[pos])
 }
 
+// HasRune returns true if user font fontName provides a glyph for r.
+func HasRune(fontName string, r rune) bool {
+	UserFontMetricsLock.RLock()
+	defer UserFontMetricsLock.RUnlock()
+	ttf, ok := UserFontMetrics[fontName]
+	if !ok {
+		return false
+	}
+	_, ok = ttf.Chars[uint32(r)]
+	return ok
+}
+
 // UserSpaceUnits transforms glyphSpaceUnits into userspace units.
 func UserSpaceUnits(glyphSpaceUnits float64, fontScalingFactor int) float64 {
 	return glyphSpaceUnits / 1000 * float64(fontScalingFactor)
@@ -330,6 +365,12 @@ func glyphSpaceWidth(text, fontName string) int {
 		}
 		return w
 	}
+	if gg := Shape(fontName, text, false); gg != nil {
+		for _, g := range gg {
+			w += g.XAdvance
+		}
+		return w
+	}
 	for _, r := range text {
 		w += CharWidth(fontName, r)
 	}
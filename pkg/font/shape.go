@@ -0,0 +1,436 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"sync"
+	"unicode"
+)
+
+// Glyph represents a shaped glyph in glyph space units.
+type Glyph struct {
+	GID      uint16
+	Width    int // default glyph width as written to the font dict
+	XAdvance int
+	XOffset  int
+	YOffset  int
+}
+
+// Feature masks restricting features to certain glyphs.
+const (
+	maskGlobal uint32 = 1 << iota
+	maskIsol
+	maskFina
+	maskMedi
+	maskInit
+	maskRphf
+	maskHalf
+	maskBlwf
+	maskPstf
+	maskPref
+)
+
+func featureMask(tag string) uint32 {
+	switch tag {
+	case "isol":
+		return maskIsol
+	case "fina":
+		return maskFina
+	case "medi":
+		return maskMedi
+	case "init":
+		return maskInit
+	case "rphf":
+		return maskRphf
+	case "half":
+		return maskHalf
+	case "blwf":
+		return maskBlwf
+	case "pstf":
+		return maskPstf
+	case "pref":
+		return maskPref
+	}
+	return maskGlobal
+}
+
+// Shapers
+const (
+	shaperDefault = iota
+	shaperArabic
+	shaperIndic
+)
+
+type scriptInfo struct {
+	tags   []string // OpenType script tags in order of preference
+	rt     *unicode.RangeTable
+	shaper int
+}
+
+var shapingScripts = []scriptInfo{
+	{[]string{"arab"}, unicode.Arabic, shaperArabic},
+	{[]string{"syrc"}, unicode.Syriac, shaperArabic},
+	{[]string{"nko "}, unicode.Nko, shaperArabic},
+	{[]string{"hebr"}, unicode.Hebrew, shaperDefault},
+	{[]string{"dev2", "deva"}, unicode.Devanagari, shaperIndic},
+	{[]string{"bng2", "beng"}, unicode.Bengali, shaperIndic},
+	{[]string{"gur2", "guru"}, unicode.Gurmukhi, shaperIndic},
+	{[]string{"gjr2", "gujr"}, unicode.Gujarati, shaperIndic},
+	{[]string{"ory2", "orya"}, unicode.Oriya, shaperIndic},
+	{[]string{"tml2", "taml"}, unicode.Tamil, shaperIndic},
+	{[]string{"tel2", "telu"}, unicode.Telugu, shaperIndic},
+	{[]string{"knd2", "knda"}, unicode.Kannada, shaperIndic},
+	{[]string{"mlm2", "mlym"}, unicode.Malayalam, shaperIndic},
+	{[]string{"latn"}, unicode.Latin, shaperDefault},
+	{[]string{"grek"}, unicode.Greek, shaperDefault},
+	{[]string{"cyrl"}, unicode.Cyrillic, shaperDefault},
+	{[]string{"armn"}, unicode.Armenian, shaperDefault},
+	{[]string{"geor"}, unicode.Georgian, shaperDefault},
+	{[]string{"thai"}, unicode.Thai, shaperDefault},
+	{[]string{"lao "}, unicode.Lao, shaperDefault},
+	{[]string{"hang"}, unicode.Hangul, shaperDefault},
+	{[]string{"hani"}, unicode.Han, shaperDefault},
+	{[]string{"kana"}, unicode.Hiragana, shaperDefault},
+	{[]string{"kana"}, unicode.Katakana, shaperDefault},
+}
+
+// scriptOf returns the index into shapingScripts for r or -1 for common and inherited characters.
+func scriptOf(r rune) int {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return -1
+	}
+	for i, si := range shapingScripts {
+		if unicode.Is(si.rt, r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// scriptRun is a sequence of runes of the same script.
+type scriptRun struct {
+	from, to int
+	script   int
+}
+
+// scriptRuns splits rr into script runs. Common and inherited characters join the surrounding run.
+func scriptRuns(rr []rune) []scriptRun {
+	var runs []scriptRun
+	cur := -1
+	for i, r := range rr {
+		sc := scriptOf(r)
+		if len(runs) == 0 {
+			runs = append(runs, scriptRun{from: i, to: i + 1, script: sc})
+			cur = sc
+			continue
+		}
+		if sc < 0 || sc == cur || cur < 0 {
+			runs[len(runs)-1].to = i + 1
+			if cur < 0 && sc >= 0 {
+				runs[len(runs)-1].script, cur = sc, sc
+			}
+			continue
+		}
+		runs = append(runs, scriptRun{from: i, to: i + 1, script: sc})
+		cur = sc
+	}
+	return runs
+}
+
+var (
+	layoutsLock sync.Mutex
+	layouts     = map[string]*otLayout{}
+)
+
+// userFontLayout returns the OpenType layout for fontName or nil if there are no layout tables.
+func userFontLayout(fontName string) *otLayout {
+	layoutsLock.Lock()
+	defer layoutsLock.Unlock()
+	if l, ok := layouts[fontName]; ok {
+		return l
+	}
+	UserFontMetricsLock.RLock()
+	fd, ok := UserFontMetrics[fontName]
+	UserFontMetricsLock.RUnlock()
+	var l *otLayout
+	if ok && (len(fd.GSUB) > 0 || len(fd.GPOS) > 0) {
+		l = newOTLayout(&fd)
+	}
+	layouts[fontName] = l
+	return l
+}
+
+// HasLayout returns true if fontName is a user font providing OpenType layout tables.
+func HasLayout(fontName string) bool {
+	return userFontLayout(fontName) != nil
+}
+
+// GlyphRunes returns the Unicode values represented by glyph gid of fontName
+// including glyphs resulting from substitutions like ligatures.
+func GlyphRunes(fontName string, gid uint16) []rune {
+	l := userFontLayout(fontName)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.runes == nil {
+		l.runes = l.glyphRunes()
+	}
+	return l.runes[gid]
+}
+
+// Shape applies the OpenType layout features of user font fontName to text
+// and returns the resulting glyphs in visual order.
+// Shape returns nil for fonts w/o OpenType layout tables.
+func Shape(fontName, text string, rtl bool) []Glyph {
+	l := userFontLayout(fontName)
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rr := decomposeSplitMatras(l.fd, []rune(text))
+
+	var buf []glyphInfo
+	for _, run := range scriptRuns(rr) {
+		gg := make([]glyphInfo, 0, run.to-run.from)
+		for i := run.from; i < run.to; i++ {
+			g := glyphInfo{r: rr[i], mask: maskGlobal}
+			gid, ok := l.fd.Chars[uint32(rr[i])]
+			g.gid = gid
+			g.hidden = !ok || defaultIgnorable(rr[i])
+			l.classify(&g)
+			gg = append(gg, g)
+		}
+		si := scriptInfo{tags: []string{"DFLT"}}
+		if run.script >= 0 {
+			si = shapingScripts[run.script]
+		}
+		gg = l.shapeRun(gg, si, rtl)
+		for i := range gg {
+			if gg[i].attach != attachNone {
+				gg[i].attachTo += len(buf)
+			}
+		}
+		buf = append(buf, gg...)
+	}
+
+	return l.resolve(buf, rtl)
+}
+
+func (l *otLayout) shapeRun(buf []glyphInfo, si scriptInfo, rtl bool) []glyphInfo {
+	var gsubStages, gposStages []stage
+	switch si.shaper {
+	case shaperArabic:
+		setArabicForms(buf)
+		gsubStages = []stage{{"ccmp", "locl"}, {"isol", "fina", "medi", "init"}, {"rlig"}, {"calt"}, {"liga", "clig", "mset", "rclt"}}
+		gposStages = []stage{{"curs", "kern", "mark", "mkmk"}}
+	case shaperIndic:
+		buf = l.indicInitialReordering(buf, si)
+		buf = l.applyStages(l.gsub, l.gsub.langSys(si.tags), buf, []stage{
+			{"locl", "ccmp"}, {"nukt"}, {"akhn"}, {"rphf"}, {"rkrf"}, {"pref"}, {"blwf"},
+			{"abvf"}, {"half"}, {"pstf"}, {"vatu"}, {"cjct"}}, rtl)
+		buf = indicFinalReordering(buf)
+		gsubStages = []stage{{"pres", "abvs", "blws", "psts", "haln", "calt", "clig", "rlig", "liga"}}
+		gposStages = []stage{{"abvm", "blwm", "dist", "kern", "mark", "mkmk", "curs"}}
+	default:
+		gsubStages = []stage{{"ccmp", "locl"}, {"rlig", "rclt", "calt", "liga", "clig"}}
+		gposStages = []stage{{"abvm", "blwm", "dist", "kern", "mark", "mkmk", "curs"}}
+	}
+
+	if l.gsub != nil {
+		buf = l.applyStages(l.gsub, l.gsub.langSys(si.tags), buf, gsubStages, rtl)
+	}
+
+	for i := range buf {
+		g := &buf[i]
+		g.xAdv = l.glyphWidth(g.gid)
+		// Marks don't advance unless Indic fonts say otherwise.
+		if g.class == glyphClassMark && si.shaper != shaperIndic {
+			g.xAdv = 0
+		}
+		if g.hidden {
+			g.xAdv = 0
+		}
+	}
+
+	if l.gpos != nil {
+		buf = l.applyStages(l.gpos, l.gpos.langSys(si.tags), buf, gposStages, rtl)
+	}
+	return buf
+}
+
+func (l *otLayout) glyphWidth(gid uint16) int {
+	if int(gid) < len(l.fd.GlyphWidths) {
+		return l.fd.GlyphWidths[gid]
+	}
+	return 0
+}
+
+// resolve computes the final glyph offsets in visual order.
+func (l *otLayout) resolve(buf []glyphInfo, rtl bool) []Glyph {
+	n := len(buf)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+		if rtl {
+			order[i] = n - 1 - i
+		}
+	}
+
+	penX := make([]int, n)
+	pen := 0
+	for _, i := range order {
+		penX[i] = pen
+		pen += buf[i].xAdv
+	}
+
+	xOff := make([]int, n)
+	yOff := make([]int, n)
+	state := make([]int, n) // 0: unresolved, 1: resolving, 2: resolved
+	var res func(i int)
+	res = func(i int) {
+		if state[i] != 0 {
+			return
+		}
+		state[i] = 1
+		g := &buf[i]
+		xOff[i], yOff[i] = g.xOff, g.yOff
+		if p := g.attachTo; g.attach != attachNone && p < n && state[p] != 1 {
+			res(p)
+			switch g.attach {
+			case attachMark:
+				xOff[i] += penX[p] + xOff[p] - penX[i]
+				yOff[i] += yOff[p]
+			case attachCursive:
+				yOff[i] += yOff[p]
+			}
+		}
+		state[i] = 2
+	}
+
+	gg := make([]Glyph, 0, n)
+	for _, i := range order {
+		res(i)
+		g := buf[i]
+		if g.hidden || int(g.gid) >= len(l.fd.GlyphWidths) {
+			continue
+		}
+		gg = append(gg, Glyph{GID: g.gid, Width: l.glyphWidth(g.gid), XAdvance: g.xAdv, XOffset: xOff[i], YOffset: yOff[i]})
+	}
+	return gg
+}
+
+// defaultIgnorable returns true for invisible format characters.
+func defaultIgnorable(r rune) bool {
+	switch {
+	case r == 0x00AD, r >= 0x200B && r <= 0x200F, r >= 0x202A && r <= 0x202E, r >= 0x2060 && r <= 0x2064, r == 0xFEFF:
+		return true
+	}
+	return false
+}
+
+// Arabic joining types, see https://www.unicode.org/Public/UCD/latest/ucd/ArabicShaping.txt
+const (
+	joinNone = iota
+	joinRight
+	joinDual
+	joinCausing
+	joinTransparent
+)
+
+// arabicRightJoining lists the right joining letters of the Arabic blocks.
+var arabicRightJoining = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0622, Hi: 0x0625, Stride: 1},
+		{Lo: 0x0627, Hi: 0x0629, Stride: 2},
+		{Lo: 0x062F, Hi: 0x0632, Stride: 1},
+		{Lo: 0x0648, Hi: 0x0648, Stride: 1},
+		{Lo: 0x0671, Hi: 0x0673, Stride: 1},
+		{Lo: 0x0675, Hi: 0x0677, Stride: 1},
+		{Lo: 0x0688, Hi: 0x0699, Stride: 1},
+		{Lo: 0x06C0, Hi: 0x06C0, Stride: 1},
+		{Lo: 0x06C3, Hi: 0x06CB, Stride: 1},
+		{Lo: 0x06CD, Hi: 0x06CF, Stride: 2},
+		{Lo: 0x06D2, Hi: 0x06D3, Stride: 1},
+		{Lo: 0x06D5, Hi: 0x06D5, Stride: 1},
+		{Lo: 0x06EE, Hi: 0x06EF, Stride: 1},
+		{Lo: 0x0710, Hi: 0x0710, Stride: 1},
+		{Lo: 0x0715, Hi: 0x0719, Stride: 1},
+		{Lo: 0x071E, Hi: 0x071E, Stride: 1},
+		{Lo: 0x0728, Hi: 0x072A, Stride: 2},
+		{Lo: 0x072C, Hi: 0x072C, Stride: 1},
+		{Lo: 0x072F, Hi: 0x072F, Stride: 1},
+		{Lo: 0x074D, Hi: 0x074D, Stride: 1},
+		{Lo: 0x0759, Hi: 0x075B, Stride: 1},
+		{Lo: 0x076B, Hi: 0x076C, Stride: 1},
+		{Lo: 0x0771, Hi: 0x0771, Stride: 1},
+		{Lo: 0x0773, Hi: 0x0774, Stride: 1},
+		{Lo: 0x0778, Hi: 0x0779, Stride: 1},
+		{Lo: 0x08AA, Hi: 0x08AC, Stride: 1},
+		{Lo: 0x08AE, Hi: 0x08AE, Stride: 1},
+		{Lo: 0x08B1, Hi: 0x08B2, Stride: 1},
+		{Lo: 0x08B9, Hi: 0x08B9, Stride: 1},
+	},
+}
+
+func joiningType(r rune) int {
+	switch {
+	case r == 0x0640 || r == 0x07FA || r == 0x200D:
+		return joinCausing
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) && r != 0x200C:
+		return joinTransparent
+	case r == 0x0621 || r == 0x0674:
+		return joinNone
+	case unicode.Is(arabicRightJoining, r):
+		return joinRight
+	case unicode.In(r, unicode.Arabic, unicode.Syriac, unicode.Nko) && unicode.IsLetter(r):
+		return joinDual
+	}
+	return joinNone
+}
+
+// setArabicForms assigns isol, fina, medi and init masks according to the joining behaviour of buf.
+func setArabicForms(buf []glyphInfo) {
+	prev := -1
+	for i := range buf {
+		jt := joiningType(buf[i].r)
+		if jt == joinTransparent {
+			continue
+		}
+		if jt != joinNone && prev >= 0 {
+			pjt := joiningType(buf[prev].r)
+			if (pjt == joinDual || pjt == joinCausing) && jt != joinNone {
+				switch buf[prev].mask &^ maskGlobal {
+				case maskIsol:
+					buf[prev].mask = maskGlobal | maskInit
+				case maskFina:
+					buf[prev].mask = maskGlobal | maskMedi
+				}
+				buf[i].mask = maskGlobal | maskFina
+				prev = i
+				continue
+			}
+		}
+		if jt != joinNone {
+			buf[i].mask = maskGlobal | maskIsol
+		}
+		prev = i
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Helpers for assembling OpenType layout tables.
+
+func u16bytes(vv ...int) []byte {
+	bb := make([]byte, 0, 2*len(vv))
+	for _, v := range vv {
+		bb = append(bb, byte(v>>8), byte(v))
+	}
+	return bb
+}
+
+func coverageTable(gids ...int) []byte {
+	return append(u16bytes(1, len(gids)), u16bytes(gids...)...)
+}
+
+// singleSubst returns a SingleSubstFormat2 subtable for sorted gids.
+func singleSubst(gids, substs []int) []byte {
+	bb := u16bytes(2, 6+2*len(substs), len(substs))
+	bb = append(bb, u16bytes(substs...)...)
+	return append(bb, coverageTable(gids...)...)
+}
+
+// ligatureSubst returns a LigatureSubstFormat1 subtable for a single ligature.
+func ligatureSubst(lig int, comps ...int) []byte {
+	bb := u16bytes(1, 14+2*len(comps), 1, 8)
+	bb = append(bb, u16bytes(1, 4, lig, len(comps))...)
+	bb = append(bb, u16bytes(comps[1:]...)...)
+	return append(bb, coverageTable(comps[0])...)
+}
+
+// pairPos returns a PairPosFormat1 subtable adjusting the advance of first.
+func pairPos(first, second, xAdv int) []byte {
+	bb := u16bytes(1, 12, 4, 0, 1, 18)
+	bb = append(bb, coverageTable(first)...)
+	return append(bb, u16bytes(1, second, xAdv)...)
+}
+
+// markBasePos returns a MarkBasePosFormat1 subtable.
+func markBasePos(mark, mx, my, base, bx, by int) []byte {
+	bb := u16bytes(1, 12, 18, 1, 24, 36)
+	bb = append(bb, coverageTable(mark)...)
+	bb = append(bb, coverageTable(base)...)
+	bb = append(bb, u16bytes(1, 0, 6, 1, mx, my)...)
+	return append(bb, u16bytes(1, 4, 1, bx, by)...)
+}
+
+type testLookup struct {
+	typ int
+	sub []byte
+}
+
+type testFeature struct {
+	tag     string
+	lookups []int
+}
+
+// layoutTable assembles a GSUB or GPOS table for script.
+func layoutTable(script string, ff []testFeature, ll []testLookup) []byte {
+	// LangSys using all features
+	langSys := u16bytes(0, 0xFFFF, len(ff))
+	for i := range ff {
+		langSys = append(langSys, u16bytes(i)...)
+	}
+	scriptList := append(u16bytes(1), script...)
+	scriptList = append(scriptList, u16bytes(8, 4, 0)...)
+	scriptList = append(scriptList, langSys...)
+
+	featureList := u16bytes(len(ff))
+	var features []byte
+	for _, f := range ff {
+		featureList = append(featureList, f.tag...)
+		featureList = append(featureList, u16bytes(2+6*len(ff)+len(features))...)
+		features = append(features, u16bytes(0, len(f.lookups))...)
+		features = append(features, u16bytes(f.lookups...)...)
+	}
+	featureList = append(featureList, features...)
+
+	lookupList := u16bytes(len(ll))
+	var lookups []byte
+	for _, l := range ll {
+		lookupList = append(lookupList, u16bytes(2+2*len(ll)+len(lookups))...)
+		lookups = append(lookups, u16bytes(l.typ, 0, 1, 8)...)
+		lookups = append(lookups, l.sub...)
+	}
+	lookupList = append(lookupList, lookups...)
+
+	bb := u16bytes(1, 0, 10, 10+len(scriptList), 10+len(scriptList)+len(featureList))
+	bb = append(bb, scriptList...)
+	bb = append(bb, featureList...)
+	return append(bb, lookupList...)
+}
+
+func installTestFont(t *testing.T, fontName string, chars map[rune]uint16, gsub, gpos []byte) {
+	t.Helper()
+	fd := TTFLight{UnitsPerEm: 1000, Chars: map[uint32]uint16{}, ToUnicode: map[uint16]uint32{}, GSUB: gsub, GPOS: gpos}
+	for r, gid := range chars {
+		fd.Chars[uint32(r)] = gid
+		fd.ToUnicode[gid] = uint32(r)
+	}
+	fd.GlyphWidths = make([]int, 40)
+	for i := range fd.GlyphWidths {
+		fd.GlyphWidths[i] = 500
+	}
+	UserFontMetricsLock.Lock()
+	UserFontMetrics[fontName] = fd
+	UserFontMetricsLock.Unlock()
+	t.Cleanup(func() {
+		UserFontMetricsLock.Lock()
+		delete(UserFontMetrics, fontName)
+		UserFontMetricsLock.Unlock()
+		layoutsLock.Lock()
+		delete(layouts, fontName)
+		layoutsLock.Unlock()
+	})
+}
+
+func gids(gg []Glyph) []uint16 {
+	ii := make([]uint16, len(gg))
+	for i, g := range gg {
+		ii[i] = g.GID
+	}
+	return ii
+}
+
+func TestShapeArabic(t *testing.T) {
+	// beh: 1 isol, 2 init, 3 medi, 4 fina
+	// alef: 5 isol, 6 fina
+	gsub := layoutTable("arab",
+		[]testFeature{{"init", []int{0}}, {"medi", []int{1}}, {"fina", []int{2}}},
+		[]testLookup{
+			{1, singleSubst([]int{1}, []int{2})},
+			{1, singleSubst([]int{1}, []int{3})},
+			{1, singleSubst([]int{1, 5}, []int{4, 6})},
+		})
+	installTestFont(t, "ShapeTestArabic", map[rune]uint16{0x0628: 1, 0x0627: 5, ' ': 7}, gsub, nil)
+
+	for _, tt := range []struct {
+		s    string
+		want []uint16
+	}{
+		{"ببا", []uint16{6, 3, 2}},
+		{"ب", []uint16{1}},
+		{"اب بب", []uint16{4, 2, 7, 1, 5}},
+	} {
+		if got := gids(Shape("ShapeTestArabic", tt.s, true)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestShapeDevanagari(t *testing.T) {
+	// 10 ka, 11 ra, 12 halant, 13 i matra, 14 reph, 15 half ka, 16 ssa
+	gsub := layoutTable("dev2",
+		[]testFeature{{"rphf", []int{0}}, {"half", []int{1}}},
+		[]testLookup{
+			{4, ligatureSubst(14, 11, 12)},
+			{4, ligatureSubst(15, 10, 12)},
+		})
+	installTestFont(t, "ShapeTestDevanagari",
+		map[rune]uint16{0x0915: 10, 0x0930: 11, 0x094D: 12, 0x093F: 13, 0x0937: 16}, gsub, nil)
+
+	for _, tt := range []struct {
+		s    string
+		want []uint16
+	}{
+		{"कि", []uint16{13, 10}},       // कि
+		{"र्कि", []uint16{13, 10, 14}}, // र्कि
+		{"क्षि", []uint16{13, 15, 16}}, // क्षि
+	} {
+		if got := gids(Shape("ShapeTestDevanagari", tt.s, false)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestShapePositioning(t *testing.T) {
+	// 20 A, 21 V, 22 x, 23 combining acute, 24 f, 25 i, 26 fi
+	gsub := layoutTable("latn",
+		[]testFeature{{"liga", []int{0}}},
+		[]testLookup{{4, ligatureSubst(26, 24, 25)}})
+	gpos := layoutTable("latn",
+		[]testFeature{{"kern", []int{0}}, {"mark", []int{1}}},
+		[]testLookup{
+			{2, pairPos(20, 21, -80)},
+			{4, markBasePos(23, 100, 500, 22, 300, 700)},
+		})
+	installTestFont(t, "ShapeTestLatin",
+		map[rune]uint16{'A': 20, 'V': 21, 'x': 22, 0x0301: 23, 'f': 24, 'i': 25}, gsub, gpos)
+
+	got := Shape("ShapeTestLatin", "AVx́fi", false)
+	want := []Glyph{
+		{GID: 20, Width: 500, XAdvance: 420},
+		{GID: 21, Width: 500, XAdvance: 500},
+		{GID: 22, Width: 500, XAdvance: 500},
+		{GID: 23, Width: 500, XOffset: -300, YOffset: 200},
+		{GID: 26, Width: 500, XAdvance: 500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if w := glyphSpaceWidth("AVx́fi", "ShapeTestLatin"); w != 1920 {
+		t.Errorf("width: got %d, want 1920", w)
+	}
+
+	if rr := GlyphRunes("ShapeTestLatin", 26); string(rr) != "fi" {
+		t.Errorf("glyph runes: got %q, want \"fi\"", string(rr))
+	}
+}
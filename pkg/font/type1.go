@@ -0,0 +1,754 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var errCorruptType1 = errors.New("pdfcpu: corrupt Type 1 font program")
+
+// type1Font represents a Type 1 font program (Adobe Type 1 Font Format).
+type type1Font struct {
+	fontMatrix  [6]float64
+	subrs       [][]byte
+	charStrings map[string][]byte
+}
+
+// afm represents the metrics of a Type 1 font as given by its Adobe Font Metrics file.
+type afm struct {
+	fontName    string
+	weight      string
+	italicAngle float64
+	fixedPitch  bool
+	fontBBox    [4]float64
+	capHeight   float64
+	ascender    float64
+	descender   float64
+	names       []string // glyph names in the order of CharMetrics
+}
+
+func parseAFM(bb []byte) (*afm, error) {
+	m := &afm{}
+	s := bufio.NewScanner(bytes.NewReader(bb))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		k, v, _ := strings.Cut(line, " ")
+		v = strings.TrimSpace(v)
+		switch k {
+		case "FontName":
+			m.fontName = v
+		case "Weight":
+			m.weight = v
+		case "ItalicAngle":
+			m.italicAngle, _ = strconv.ParseFloat(v, 64)
+		case "IsFixedPitch":
+			m.fixedPitch = v == "true"
+		case "FontBBox":
+			for i, f := range strings.Fields(v) {
+				if i < 4 {
+					m.fontBBox[i], _ = strconv.ParseFloat(f, 64)
+				}
+			}
+		case "CapHeight":
+			m.capHeight, _ = strconv.ParseFloat(v, 64)
+		case "Ascender":
+			m.ascender, _ = strconv.ParseFloat(v, 64)
+		case "Descender":
+			m.descender, _ = strconv.ParseFloat(v, 64)
+		case "C", "CH":
+			// C 65 ; WX 667 ; N A ; B 14 0 654 718 ;
+			for _, kv := range strings.Split(line, ";") {
+				if f := strings.Fields(kv); len(f) == 2 && f[0] == "N" {
+					m.names = append(m.names, f[1])
+				}
+			}
+		}
+	}
+	if m.fontName == "" {
+		return nil, errors.New("pdfcpu: corrupt AFM file: missing FontName")
+	}
+	if m.capHeight == 0 {
+		m.capHeight = m.ascender
+	}
+	return m, nil
+}
+
+// pfbData returns the concatenated segment data of a PFB file.
+func pfbData(bb []byte) []byte {
+	if len(bb) == 0 || bb[0] != 0x80 {
+		// PFA
+		return bb
+	}
+	var buf []byte
+	for len(bb) >= 6 && bb[0] == 0x80 && bb[1] != 3 {
+		l := int(binary.LittleEndian.Uint32(bb[2:]))
+		if 6+l > len(bb) {
+			l = len(bb) - 6
+		}
+		buf = append(buf, bb[6:6+l]...)
+		bb = bb[6+l:]
+	}
+	return buf
+}
+
+func isType1Space(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+// Type1Token returns the next token of bb starting at i and the position following it.
+func Type1Token(bb []byte, i int) (string, int) {
+	for i < len(bb) && isType1Space(bb[i]) {
+		i++
+	}
+	if i >= len(bb) {
+		return "", i
+	}
+	j := i
+	if strings.IndexByte("[]{}", bb[i]) >= 0 {
+		return string(bb[i : i+1]), i + 1
+	}
+	if bb[j] == '/' {
+		j++
+	}
+	for j < len(bb) && !isType1Space(bb[j]) && strings.IndexByte("()<>[]{}/%", bb[j]) < 0 {
+		j++
+	}
+	if j == i {
+		j++
+	}
+	return string(bb[i:j]), j
+}
+
+// Type1Decrypt decrypts eexec encrypted data or charstrings, see 7.2 of the Type 1 Font Format.
+func Type1Decrypt(bb []byte, r uint16, skip int) []byte {
+	out := make([]byte, len(bb))
+	for i, c := range bb {
+		out[i] = c ^ byte(r>>8)
+		r = (uint16(c)+r)*52845 + 22719
+	}
+	if skip < 0 || skip > len(out) {
+		return nil
+	}
+	return out[skip:]
+}
+
+// Type1Binary reads the binary data of "n RD <n bytes>" starting at i and decrypts it unless lenIV is negative.
+func Type1Binary(bb []byte, i, lenIV int) ([]byte, int, bool) {
+	tok, i := Type1Token(bb, i)
+	n, err := strconv.Atoi(tok)
+	if err != nil || n < 0 {
+		return nil, i, false
+	}
+	_, i = Type1Token(bb, i) // RD or -|
+	i++
+	if i+n > len(bb) {
+		return nil, len(bb), false
+	}
+	cs := bb[i : i+n]
+	if lenIV >= 0 {
+		cs = Type1Decrypt(cs, 4330, lenIV)
+	}
+	return cs, i + n, true
+}
+
+func parseType1(bb []byte) (*type1Font, error) {
+	bb = pfbData(bb)
+
+	i := bytes.Index(bb, []byte("eexec"))
+	if i < 0 {
+		return nil, errCorruptType1
+	}
+
+	t1 := &type1Font{fontMatrix: [6]float64{0.001, 0, 0, 0.001, 0, 0}, charStrings: map[string][]byte{}}
+
+	if j := bytes.Index(bb[:i], []byte("/FontMatrix")); j >= 0 {
+		tok, j := Type1Token(bb, j+11)
+		for k := 0; k < 6 && (tok == "[" || tok == "{"); k++ {
+			tok, j = Type1Token(bb, j)
+			f, err := strconv.ParseFloat(tok, 64)
+			if err != nil {
+				return nil, errCorruptType1
+			}
+			t1.fontMatrix[k] = f
+		}
+	}
+
+	enc := bytes.TrimLeft(bb[i+5:], " \t\r\n")
+	if len(enc) >= 4 {
+		if _, err := hex.DecodeString(string(enc[:4])); err == nil {
+			// PFA
+			s := strings.Join(strings.Fields(string(enc)), "")
+			if k := strings.IndexFunc(s, func(r rune) bool { return !strings.ContainsRune("0123456789abcdefABCDEF", r) }); k >= 0 {
+				s = s[:k]
+			}
+			enc, _ = hex.DecodeString(s[:len(s)/2*2])
+		}
+	}
+	priv := Type1Decrypt(enc, 55665, 4)
+
+	lenIV := 4
+	if j := bytes.Index(priv, []byte("/lenIV")); j >= 0 {
+		tok, _ := Type1Token(priv, j+6)
+		if n, err := strconv.Atoi(tok); err == nil {
+			lenIV = n
+		}
+	}
+
+	if j := bytes.Index(priv, []byte("/Subrs")); j >= 0 {
+		tok, j := Type1Token(priv, j+6)
+		n, err := strconv.Atoi(tok)
+		if err != nil || n < 0 || n > 65535 {
+			return nil, errCorruptType1
+		}
+		t1.subrs = make([][]byte, n)
+		for {
+			tok, j = Type1Token(priv, j)
+			if tok != "dup" {
+				if tok == "" || strings.HasPrefix(tok, "/") {
+					break
+				}
+				continue
+			}
+			tok, j = Type1Token(priv, j)
+			k, err := strconv.Atoi(tok)
+			if err != nil {
+				break
+			}
+			cs, j1, ok := Type1Binary(priv, j, lenIV)
+			if !ok {
+				break
+			}
+			if j = j1; k >= 0 && k < n {
+				t1.subrs[k] = cs
+			}
+		}
+	}
+
+	j := bytes.Index(priv, []byte("/CharStrings"))
+	if j < 0 {
+		return nil, errCorruptType1
+	}
+	for j += 12; ; {
+		var tok string
+		if tok, j = Type1Token(priv, j); tok == "" || tok == "end" {
+			break
+		}
+		if !strings.HasPrefix(tok, "/") || len(tok) == 1 {
+			continue
+		}
+		cs, j1, ok := Type1Binary(priv, j, lenIV)
+		if !ok {
+			continue
+		}
+		j = j1
+		t1.charStrings[tok[1:]] = cs
+	}
+
+	if len(t1.charStrings) == 0 {
+		return nil, errCorruptType1
+	}
+
+	return t1, nil
+}
+
+// type1Converter converts Type 1 charstrings into Type 2 charstrings (Adobe Technical Note #5177).
+// Hints are dropped, accented characters get decomposed.
+type type1Converter struct {
+	t1       *type1Font
+	stack    []float64
+	ps       []float64 // results of othersubrs
+	x, y     float64   // current point
+	px, py   float64   // last point written
+	dx, dy   float64   // offset of accent glyphs
+	width    float64
+	hasWidth bool
+	written  bool
+	open     bool
+	flex     []float64
+	inFlex   bool
+	done     bool
+	depth    int
+	buf      bytes.Buffer
+}
+
+func (c *type1Converter) op(op byte, args ...float64) {
+	if !c.written {
+		c.written = true
+		if c.width != 0 {
+			writeType2Number(&c.buf, c.width)
+		}
+	}
+	for _, arg := range args {
+		writeType2Number(&c.buf, arg)
+	}
+	c.buf.WriteByte(op)
+}
+
+func (c *type1Converter) moveTo(x, y float64) {
+	c.x, c.y = x, y
+	if c.inFlex {
+		c.flex = append(c.flex, x, y)
+		return
+	}
+	x, y = x+c.dx, y+c.dy
+	c.op(21, x-c.px, y-c.py)
+	c.px, c.py = x, y
+	c.open = true
+}
+
+func (c *type1Converter) lineTo(x, y float64) {
+	if !c.open {
+		c.moveTo(c.x, c.y)
+	}
+	c.x, c.y = x, y
+	x, y = x+c.dx, y+c.dy
+	c.op(5, x-c.px, y-c.py)
+	c.px, c.py = x, y
+}
+
+func (c *type1Converter) curveTo(x1, y1, x2, y2, x3, y3 float64) {
+	if !c.open {
+		c.moveTo(c.x, c.y)
+	}
+	c.x, c.y = x3, y3
+	x1, y1, x2, y2, x3, y3 = x1+c.dx, y1+c.dy, x2+c.dx, y2+c.dy, x3+c.dx, y3+c.dy
+	c.op(8, x1-c.px, y1-c.py, x2-x1, y2-y1, x3-x2, y3-y2)
+	c.px, c.py = x3, y3
+}
+
+func (c *type1Converter) rcurveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	x1, y1 := c.x+dx1, c.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	c.curveTo(x1, y1, x2, y2, x2+dx3, y2+dy3)
+}
+
+func (c *type1Converter) pop() float64 {
+	n := len(c.stack)
+	if n == 0 {
+		return 0
+	}
+	v := c.stack[n-1]
+	c.stack = c.stack[:n-1]
+	return v
+}
+
+func (c *type1Converter) callOtherSubr() error {
+	nr, n := int(c.pop()), int(c.pop())
+	if n < 0 || n > len(c.stack) {
+		return errCorruptType1
+	}
+	args := append([]float64(nil), c.stack[len(c.stack)-n:]...)
+	c.stack = c.stack[:len(c.stack)-n]
+
+	switch nr {
+	case 0:
+		// End of flex: the reference point is followed by the points of two curves.
+		c.inFlex = false
+		if len(c.flex) != 14 || n != 3 {
+			return errCorruptType1
+		}
+		p := c.flex[2:]
+		c.curveTo(p[0], p[1], p[2], p[3], p[4], p[5])
+		c.curveTo(p[6], p[7], p[8], p[9], p[10], p[11])
+		c.ps = append(c.ps, args[2], args[1])
+		return nil
+	case 1:
+		c.inFlex, c.flex = true, nil
+		return nil
+	case 2:
+		return nil
+	case 14, 15, 16, 17, 18:
+		return errors.New("pdfcpu: multiple master fonts are unsupported")
+	}
+
+	// Hint replacement and counter control: return the arguments.
+	for i := len(args) - 1; i >= 0; i-- {
+		c.ps = append(c.ps, args[i])
+	}
+	return nil
+}
+
+// seac draws an accented character composed of two glyphs of the StandardEncoding.
+func (c *type1Converter) seac(asb, adx, ady float64, bchar, achar int) error {
+	base, accent := cffStandardEncodingSID(bchar), cffStandardEncodingSID(achar)
+	if base == 0 || accent == 0 {
+		return errCorruptType1
+	}
+	bcs, ok1 := c.t1.charStrings[CFFStandardStrings[base]]
+	acs, ok2 := c.t1.charStrings[CFFStandardStrings[accent]]
+	if !ok1 || !ok2 {
+		return errCorruptType1
+	}
+
+	c.stack, c.done = nil, false
+	if err := c.exec(bcs); err != nil {
+		return err
+	}
+
+	c.stack, c.done, c.open = nil, false, false
+	c.dx, c.dy = adx-asb, ady
+	if err := c.exec(acs); err != nil {
+		return err
+	}
+	c.dx, c.dy = 0, 0
+
+	return nil
+}
+
+func (c *type1Converter) sbw(sbx, sby, wx float64) {
+	c.x, c.y = sbx, sby
+	if !c.hasWidth {
+		c.width, c.hasWidth = wx, true
+	}
+}
+
+func (c *type1Converter) operator(op int) error {
+	a := c.stack
+	need := func(n int) bool { return len(a) >= n }
+
+	switch op {
+	case 10: // callsubr
+		i := int(c.pop())
+		if i < 0 || i >= len(c.t1.subrs) {
+			return errCorruptType1
+		}
+		return c.exec(c.t1.subrs[i])
+	case CFFEscape + 16: // callothersubr
+		return c.callOtherSubr()
+	case CFFEscape + 17: // pop
+		if n := len(c.ps); n > 0 {
+			c.stack = append(c.stack, c.ps[n-1])
+			c.ps = c.ps[:n-1]
+		}
+		return nil
+	case CFFEscape + 12: // div
+		if need(2) {
+			n := len(a)
+			c.stack = append(a[:n-2], a[n-2]/a[n-1])
+		}
+		return nil
+	case 13: // hsbw
+		if need(2) {
+			c.sbw(a[0], 0, a[1])
+		}
+	case CFFEscape + 7: // sbw
+		if need(4) {
+			c.sbw(a[0], a[1], a[2])
+		}
+	case 21: // rmoveto
+		if need(2) {
+			c.moveTo(c.x+a[0], c.y+a[1])
+		}
+	case 22: // hmoveto
+		if need(1) {
+			c.moveTo(c.x+a[0], c.y)
+		}
+	case 4: // vmoveto
+		if need(1) {
+			c.moveTo(c.x, c.y+a[0])
+		}
+	case 5: // rlineto
+		if need(2) {
+			c.lineTo(c.x+a[0], c.y+a[1])
+		}
+	case 6: // hlineto
+		if need(1) {
+			c.lineTo(c.x+a[0], c.y)
+		}
+	case 7: // vlineto
+		if need(1) {
+			c.lineTo(c.x, c.y+a[0])
+		}
+	case 8: // rrcurveto
+		if need(6) {
+			c.rcurveTo(a[0], a[1], a[2], a[3], a[4], a[5])
+		}
+	case 30: // vhcurveto
+		if need(4) {
+			c.rcurveTo(0, a[0], a[1], a[2], a[3], 0)
+		}
+	case 31: // hvcurveto
+		if need(4) {
+			c.rcurveTo(a[0], 0, a[1], a[2], 0, a[3])
+		}
+	case CFFEscape + 33: // setcurrentpoint
+		if need(2) {
+			c.x, c.y = a[0], a[1]
+		}
+	case CFFEscape + 6: // seac
+		if !need(5) {
+			return errCorruptType1
+		}
+		if err := c.seac(a[0], a[1], a[2], int(a[3]), int(a[4])); err != nil {
+			return err
+		}
+		c.done = true
+	case 14: // endchar
+		c.done = true
+	}
+
+	// closepath, hints and dotsection are not needed.
+	c.stack = c.stack[:0]
+	return nil
+}
+
+func (c *type1Converter) exec(cs []byte) error {
+	c.depth++
+	defer func() { c.depth-- }()
+	if c.depth > 10 {
+		return errCorruptType1
+	}
+
+	for i := 0; i < len(cs) && !c.done; {
+		v := int(cs[i])
+		switch {
+		case v >= 32 && v <= 246:
+			c.stack = append(c.stack, float64(v-139))
+			i++
+		case v >= 247 && v <= 254:
+			if i+2 > len(cs) {
+				return errCorruptType1
+			}
+			w := float64((v-247)*256 + int(cs[i+1]) + 108)
+			if v >= 251 {
+				w = float64(-(v-251)*256 - int(cs[i+1]) - 108)
+			}
+			c.stack = append(c.stack, w)
+			i += 2
+		case v == 255:
+			if i+5 > len(cs) {
+				return errCorruptType1
+			}
+			c.stack = append(c.stack, float64(int32(binary.BigEndian.Uint32(cs[i+1:]))))
+			i += 5
+		case v == 11: // return
+			return nil
+		default:
+			i++
+			if v == 12 {
+				if i >= len(cs) {
+					return errCorruptType1
+				}
+				v = CFFEscape + int(cs[i])
+				i++
+			}
+			if err := c.operator(v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// type2CharString returns the Type 2 charstring and the advance width of glyph name.
+func (t1 *type1Font) type2CharString(name string) ([]byte, float64, error) {
+	c := &type1Converter{t1: t1}
+	if err := c.exec(t1.charStrings[name]); err != nil {
+		return nil, 0, errors.Wrapf(err, "glyph %s", name)
+	}
+	c.op(14)
+	return c.buf.Bytes(), c.width, nil
+}
+
+// cff converts t1 into a bare name-keyed CFF font program with glyphs in the order of names.
+func (t1 *type1Font) cff(fontName string, names []string) ([]byte, []float64, error) {
+	sids := map[string]int{}
+	for sid, s := range CFFStandardStrings {
+		sids[s] = sid
+	}
+
+	f := &cffFont{names: [][]byte{[]byte(fontName)}}
+
+	charset := []byte{0}
+	widths := make([]float64, len(names))
+	for gid, name := range names {
+		cs, w, err := t1.type2CharString(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.charStrings = append(f.charStrings, cs)
+		widths[gid] = w
+		if gid == 0 {
+			continue
+		}
+		sid, ok := sids[name]
+		if !ok {
+			sid = f.addString(name)
+		}
+		charset = append(charset, uint16ToBigEndianBytes(uint16(sid))...)
+	}
+	f.charset = charset
+
+	if t1.fontMatrix != [6]float64{0.001, 0, 0, 0.001, 0, 0} {
+		var args []byte
+		for _, v := range t1.fontMatrix {
+			args = append(args, cffRealOperand(v)...)
+		}
+		f.top = append(f.top, CFFDictEntry{Op: CFFEscape + 7, Args: args})
+	}
+	f.top = append(f.top, CFFDictEntry{Op: 15}, CFFDictEntry{Op: 17}, CFFDictEntry{Op: 18})
+
+	// Widths are given relative to nominalWidthX = 0.
+	f.fds = []cffFontDict{{priv: []CFFDictEntry{{Op: 20, Args: cffIntOperands(0)}}}}
+
+	return f.bytes(), widths, nil
+}
+
+// glyphNameRune returns the Unicode character for glyph names of WinAnsiEncoding and uniXXXX or uXXXX[XX] names.
+func glyphNameRune(name string) (rune, bool) {
+	for c, s := range metrics.WinAnsiGlyphMap {
+		if s == name {
+			return charmap.Windows1252.DecodeByte(byte(c)), true
+		}
+	}
+	for _, p := range []string{"uni", "u"} {
+		s, ok := strings.CutPrefix(name, p)
+		if !ok || len(s) < 4 || len(s) > 6 || (p == "uni" && len(s) != 4) {
+			continue
+		}
+		if r, err := strconv.ParseUint(s, 16, 32); err == nil && r <= 0x10FFFF {
+			return rune(r), true
+		}
+	}
+	return 0, false
+}
+
+func type1Rep(bb, bbAFM []byte) (*ttf, error) {
+	t1, err := parseType1(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseAFM(bbAFM)
+	if err != nil {
+		return nil, err
+	}
+
+	// .notdef is followed by the glyphs of the AFM file and remaining glyphs of the font program.
+	names := []string{".notdef"}
+	if _, ok := t1.charStrings[".notdef"]; !ok {
+		t1.charStrings[".notdef"] = []byte{139, 139, 13, 14} // 0 0 hsbw endchar
+	}
+	seen := map[string]bool{".notdef": true}
+	for _, name := range m.names {
+		if _, ok := t1.charStrings[name]; ok && !seen[name] {
+			names, seen[name] = append(names, name), true
+		}
+	}
+	var rest []string
+	for name := range t1.charStrings {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	names = append(names, rest...)
+
+	cff, widths, err := t1.cff(m.fontName, names)
+	if err != nil {
+		return nil, err
+	}
+
+	fd := &ttf{
+		PostscriptName:  m.fontName,
+		UnitsPerEm:      1000,
+		Ascent:          int(m.ascender),
+		Descent:         int(m.descender),
+		CapHeight:       int(m.capHeight),
+		LLx:             m.fontBBox[0],
+		LLy:             m.fontBBox[1],
+		URx:             m.fontBBox[2],
+		URy:             m.fontBBox[3],
+		ItalicAngle:     m.italicAngle,
+		FixedPitch:      m.fixedPitch,
+		Bold:            strings.Contains(m.weight, "Bold") || m.weight == "Black" || m.weight == "Heavy",
+		HorMetricsCount: len(names),
+		GlyphCount:      len(names),
+		GlyphWidths:     make([]int, len(names)),
+		Chars:           map[uint32]uint16{},
+		ToUnicode:       map[uint16]uint32{},
+		Planes:          map[int]bool{0: true},
+		Format:          FormatCFF,
+		FontFile:        cff,
+	}
+
+	for gid, w := range widths {
+		fd.GlyphWidths[gid] = int(math.Round(w * t1.fontMatrix[0] * 1000))
+	}
+
+	fd.FirstChar, fd.LastChar = 0xFFFF, 0
+	for gid, name := range names {
+		r, ok := glyphNameRune(name)
+		if !ok {
+			continue
+		}
+		c := uint32(r)
+		if _, ok := fd.Chars[c]; !ok {
+			fd.Chars[c] = uint16(gid)
+		}
+		fd.ToUnicode[uint16(gid)] = c
+		if c <= 0xFFFF {
+			fd.FirstChar, fd.LastChar = min(fd.FirstChar, uint16(c)), max(fd.LastChar, uint16(c))
+		}
+		// Basic Latin, Latin-1 Supplement, Latin Extended-A, Latin Extended-B
+		for bit, hi := range []uint32{0x7F, 0xFF, 0x17F, 0x24F} {
+			if c <= hi {
+				fd.UnicodeRange[0] |= 1 << bit
+				break
+			}
+		}
+	}
+
+	return fd, nil
+}
+
+// InstallType1Font saves an internal representation of Type 1 font fontName (.pfb or .pfa) to the pdfcpu config dir.
+// The font metrics are taken from the corresponding .afm file located in the same directory.
+func InstallType1Font(fontDir, fontName string) error {
+	bb, err := os.ReadFile(fontName)
+	if err != nil {
+		return err
+	}
+
+	afmName := strings.TrimSuffix(fontName, filepath.Ext(fontName)) + ".afm"
+	bbAFM, err := os.ReadFile(afmName)
+	if err != nil {
+		return errors.Errorf("pdfcpu: missing AFM file for %s", fontName)
+	}
+
+	fd, err := type1Rep(bb, bbAFM)
+	if err != nil {
+		return errors.Wrap(err, fontName)
+	}
+
+	return installRep(fontDir, fontName, *fd)
+}
@@ -757,5 +757,5 @@ func FromJSON(ctx *model.Context, rd io.Reader) error {
 		}
 	}
 
-	return nil
+	return pdf.WriteStructTree()
 }
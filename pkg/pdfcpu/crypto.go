@@ -124,6 +124,25 @@ var (
 		model.SETVIEWERPREFERENCES:    {0, 1},
 		model.RESETVIEWERPREFERENCES:  {0, 1},
 		model.ZOOM:                    {0, 1},
+		model.SIGN:                    {0, 1},
+		model.LISTSIGNATURES:          {0, 0},
+		model.VALIDATESIGNATURES:      {0, 0},
+		model.EXTRACTTEXT:             {1, 0},
+		model.REDACT:                  {0, 1},
+		model.RENDER:                  {1, 0},
+		model.PDFACONVERT:             {0, 1},
+		model.LISTSTRUCTURE:           {0, 0},
+		model.EXPORTSTRUCTURE:         {0, 1},
+		model.LISTLAYERS:              {0, 0},
+		model.ADDLAYERS:               {0, 1},
+		model.REMOVELAYERS:            {0, 1},
+		model.SHOWLAYERS:              {0, 1},
+		model.HIDELAYERS:              {0, 1},
+		model.FLATTENLAYERS:           {0, 1},
+		model.REPAIR:                  {0, 1},
+		model.LISTREVISIONS:           {0, 0},
+		model.EXTRACTREVISION:         {1, 0},
+		model.DIFFREVISIONS:           {0, 0},
 	}
 
 	ErrUnknownEncryption = errors.New("pdfcpu: unknown encryption")
@@ -1129,8 +1148,11 @@ func validateOAndU(ctx *model.Context, d types.Dict) (o, u []byte, err error) {
 func supportedEncryption(ctx *model.Context, d types.Dict) (*model.Enc, error) {
 	// Filter
 	filter := d.NameEntry("Filter")
+	if filter != nil && *filter == filterPubSec {
+		return supportedPubSecEncryption(ctx, d)
+	}
 	if filter == nil || *filter != "Standard" {
-		return nil, errors.New("pdfcpu: unsupported encryption: filter must be \"Standard\"")
+		return nil, errors.New("pdfcpu: unsupported encryption: filter must be \"Standard\" or \"Adobe.PubSec\"")
 	}
 
 	// SubFilter
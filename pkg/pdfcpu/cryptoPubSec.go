@@ -0,0 +1,285 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+// Functions dealing with the public-key security handler, see 7.6.5.
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/sign"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	filterPubSec     = "Adobe.PubSec"
+	subFilterPKCS7s4 = "adbe.pkcs7.s4"
+	subFilterPKCS7s5 = "adbe.pkcs7.s5"
+	pubSecCF         = "DefaultCryptFilter"
+	pubSecSeedLength = 20
+)
+
+// ErrNoRecipientKey indicates a missing private key for a file encrypted using the public-key security handler.
+var ErrNoRecipientKey = errors.New("pdfcpu: this file is encrypted for specific recipients (Hint: provide the private key of a recipient)")
+
+// newPubSecEncryptDict creates a new encrypt dict for the public-key security handler using crypt filters.
+func newPubSecEncryptDict(needAES bool, keyLength int, recipients types.Array) types.Dict {
+	d := types.NewDict()
+
+	d.Insert("Filter", types.Name(filterPubSec))
+	d.Insert("SubFilter", types.Name(subFilterPKCS7s5))
+
+	v := 4
+	if keyLength == 256 {
+		v = 5
+	}
+	d.Insert("V", types.Integer(v))
+	d.Insert("Length", types.Integer(keyLength))
+
+	d.Insert("StmF", types.Name(pubSecCF))
+	d.Insert("StrF", types.Name(pubSecCF))
+
+	d1 := types.NewDict()
+	d1.Insert("AuthEvent", types.Name("DocOpen"))
+
+	if needAES {
+		n := "AESV2"
+		if keyLength == 256 {
+			n = "AESV3"
+		}
+		d1.Insert("CFM", types.Name(n))
+	} else {
+		d1.Insert("CFM", types.Name("V2"))
+	}
+
+	d1.Insert("Length", types.Integer(keyLength/8))
+	d1.Insert("Recipients", recipients)
+
+	d2 := types.NewDict()
+	d2.Insert(pubSecCF, d1)
+
+	d.Insert("CF", d2)
+
+	return d
+}
+
+// pubSecKey computes the file encryption key from seed and the recipients of the encrypt dict.
+func pubSecKey(seed []byte, recipients [][]byte, e *model.Enc) []byte {
+	var h hash.Hash = sha1.New()
+	if e.V == 5 {
+		// AESV3
+		h = sha256.New()
+	}
+
+	h.Write(seed)
+	for _, bb := range recipients {
+		h.Write(bb)
+	}
+	if !e.Emd {
+		h.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	}
+
+	return h.Sum(nil)[:e.L/8]
+}
+
+func pubSecRecipientData(seed []byte, p int) []byte {
+	bb := make([]byte, pubSecSeedLength+4)
+	copy(bb, seed)
+	binary.BigEndian.PutUint32(bb[pubSecSeedLength:], uint32(int32(p)))
+	return bb
+}
+
+// setupPubSecEncryption creates the encrypt dict and the file encryption key for ctx.Recipients.
+func setupPubSecEncryption(ctx *model.Context) (types.Dict, error) {
+	if ctx.EncryptKeyLength != 128 && ctx.EncryptKeyLength != 256 || !ctx.EncryptUsingAES && ctx.EncryptKeyLength == 256 {
+		return nil, errors.New("pdfcpu: public-key encryption supports AES-128, AES-256 or RC4-128 only")
+	}
+
+	seed := make([]byte, pubSecSeedLength)
+	if _, err := io.ReadFull(rand.Reader, seed); err != nil {
+		return nil, err
+	}
+
+	p := int(int16(ctx.Permissions))
+
+	// One envelope per recipient.
+	var (
+		recipients [][]byte
+		a          types.Array
+	)
+	for _, cert := range ctx.Recipients {
+		bb, err := sign.Envelope(pubSecRecipientData(seed, p), cert)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, bb)
+		a = append(a, types.NewHexLiteral(bb))
+	}
+
+	d := newPubSecEncryptDict(ctx.EncryptUsingAES, ctx.EncryptKeyLength, a)
+
+	e, err := supportedEncryption(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	e.P = p
+
+	ctx.E = e
+	ctx.EncKey = pubSecKey(seed, recipients, e)
+
+	return d, nil
+}
+
+func pubSecRecipients(d types.Dict) ([][]byte, error) {
+	a := d.ArrayEntry("Recipients")
+	if len(a) == 0 {
+		return nil, errors.New("pdfcpu: unsupported encryption: missing or empty \"Recipients\"")
+	}
+
+	var recipients [][]byte
+	for _, o := range a {
+		bb, err := types.StringBytes(o)
+		if err != nil {
+			return nil, errors.New("pdfcpu: unsupported encryption: corrupt \"Recipients\"")
+		}
+		recipients = append(recipients, bb)
+	}
+
+	return recipients, nil
+}
+
+// supportedPubSecEncryption returns a pointer to a struct encapsulating encryption via the public-key security handler.
+func supportedPubSecEncryption(ctx *model.Context, d types.Dict) (*model.Enc, error) {
+	subFilter := d.NameEntry("SubFilter")
+	if subFilter == nil || *subFilter != subFilterPKCS7s4 && *subFilter != subFilterPKCS7s5 {
+		return nil, errors.New("pdfcpu: unsupported encryption: \"SubFilter\" must be adbe.pkcs7.s4 or adbe.pkcs7.s5")
+	}
+
+	// EncryptMetadata
+	encMeta := true
+	if emd := d.BooleanEntry("EncryptMetadata"); emd != nil {
+		encMeta = *emd
+	}
+
+	if *subFilter == subFilterPKCS7s4 {
+		// RC4 using Length, recipients in encrypt dict.
+		l, err := length(d)
+		if err != nil {
+			return nil, err
+		}
+		v := d.IntEntry("V")
+		if v == nil || *v != 1 && *v != 2 {
+			return nil, errors.New("pdfcpu: unsupported encryption: adbe.pkcs7.s4 requires \"V\" 1 or 2")
+		}
+		recipients, err := pubSecRecipients(d)
+		if err != nil {
+			return nil, err
+		}
+		return &model.Enc{L: l, R: 3, V: *v, Emd: encMeta, Recipients: recipients}, nil
+	}
+
+	// Crypt filters, recipients in crypt filter dict.
+	cfDict := d.DictEntry("CF")
+	if cfDict == nil {
+		return nil, errors.New("pdfcpu: unsupported encryption: required entry \"CF\" missing")
+	}
+
+	stmf := d.NameEntry("StmF")
+	if stmf == nil || *stmf == "Identity" {
+		return nil, errors.New("pdfcpu: unsupported encryption: missing crypt filter")
+	}
+
+	cf := cfDict.DictEntry(*stmf)
+	if cf == nil {
+		return nil, errors.Errorf("pdfcpu: unsupported encryption: entry \"%s\" missing in \"CF\"", *stmf)
+	}
+
+	l := 128
+	if i := d.IntEntry("Length"); i != nil {
+		l = *i
+	} else if i := cf.IntEntry("Length"); i != nil {
+		// Length in bytes.
+		l = *i * 8
+	}
+	if l != 128 && l != 256 {
+		return nil, errors.Errorf("pdfcpu: unsupported encryption: \"Length\" %d not supported", l)
+	}
+
+	v, err := checkV(ctx, d, l)
+	if err != nil {
+		return nil, err
+	}
+
+	if emd := cf.BooleanEntry("EncryptMetadata"); emd != nil {
+		encMeta = *emd
+	}
+
+	recipients, err := pubSecRecipients(cf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Enc{L: l, R: *v, V: *v, Emd: encMeta, Recipients: recipients}, nil
+}
+
+// setupPubSecEncryptionKey computes the file encryption key using the private key of a recipient.
+func setupPubSecEncryptionKey(ctx *model.Context) error {
+	if ctx.RecipientKey == nil {
+		return ErrNoRecipientKey
+	}
+
+	for _, bb := range ctx.E.Recipients {
+		data, err := sign.OpenEnvelope(bb, ctx.RecipientKey, ctx.RecipientCert)
+		if err == sign.ErrNotRecipient {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if len(data) < pubSecSeedLength {
+			return errors.New("pdfcpu: unsupported encryption: corrupt recipient seed")
+		}
+
+		// Permissions are optional for adbe.pkcs7.s4.
+		ctx.E.P = -1
+		if len(data) >= pubSecSeedLength+4 {
+			ctx.E.P = int(int32(binary.BigEndian.Uint32(data[pubSecSeedLength:])))
+		}
+
+		ctx.EncKey = pubSecKey(data[:pubSecSeedLength], ctx.E.Recipients, ctx.E)
+
+		if !hasNeededPermissions(ctx.Cmd, ctx.E) {
+			return errors.New("pdfcpu: operation restricted via pdfcpu's permission bits setting")
+		}
+
+		if log.InfoEnabled() {
+			log.Info.Println("opened public-key encryption envelope")
+		}
+
+		return nil
+	}
+
+	return sign.ErrNotRecipient
+}
@@ -268,7 +268,7 @@ func imageStub(
 	if err != nil {
 		return nil, err
 	}
-	if lastFilter == filter.CCITTFax {
+	if lastFilter == filter.CCITTFax || lastFilter == filter.JBIG2 {
 		comp = 1
 	}
 
@@ -356,12 +356,25 @@ func prepareExtractImage(sd *types.StreamDict) (string, string, types.Dict, bool
 }
 func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter string, objNr int) error {
 	// CCITTDecoded images / (bit) masks don't have a ColorSpace attribute, but we render image files.
-	if lastFilter == filter.CCITTFax {
+	if lastFilter == filter.CCITTFax || lastFilter == filter.JBIG2 {
 		if _, err := ctx.DereferenceDictEntry(sd.Dict, "ColorSpace"); err != nil {
 			sd.InsertName("ColorSpace", model.DeviceGrayCS)
 		}
 	}
 
+	var opts []filter.Option
+
+	if lastFilter == filter.JBIG2 {
+		// JBIG2 images are monochrome.
+		if sd.IntEntry("BitsPerComponent") == nil {
+			sd.InsertInt("BitsPerComponent", 1)
+		}
+		var err error
+		if opts, err = ctx.FilterOptions(sd); err != nil {
+			return err
+		}
+	}
+
 	if lastFilter == filter.DCT {
 		comp, err := ColorSpaceComponents(ctx.XRefTable, sd)
 		if err != nil {
@@ -372,11 +385,14 @@ func decodeImage(ctx *model.Context, sd *types.StreamDict, filters, lastFilter s
 
 	switch lastFilter {
 
-	case filter.DCT, filter.JPX, filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
-		if err := sd.Decode(); err != nil {
+	case filter.DCT, filter.Flate, filter.LZW, filter.CCITTFax, filter.JBIG2, filter.RunLength:
+		if err := sd.Decode(opts...); err != nil {
 			return err
 		}
 
+	case filter.JPX:
+		// RenderImage decodes JPEG 2000 data along with its colour space and opacity.
+
 	default:
 		msg := fmt.Sprintf("pdfcpu: ExtractImage(obj#%d): skipping img, filter %s unsupported", objNr, filters)
 		if log.DebugEnabled() {
@@ -399,7 +415,11 @@ func img(
 	objNr int) (*model.Image, error) {
 
 	if sd.FilterPipeline == nil {
-		sd.Content = sd.Raw
+		raw, err := sd.RawContent()
+		if err != nil {
+			return nil, err
+		}
+		sd.Content = raw
 	} else {
 		if err := decodeImage(ctx, sd, filters, lastFilter, objNr); err != nil {
 			return nil, err
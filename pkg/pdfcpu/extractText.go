@@ -0,0 +1,693 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// TextRun represents a sequence of characters on a common baseline shown using the same font.
+type TextRun struct {
+	Text     string          `json:"text"`
+	Font     string          `json:"font,omitempty"`
+	FontSize float64         `json:"fontSize"` // in user space units
+	Line     int             `json:"line"`     // line number in reading order
+	BBox     types.Rectangle `json:"bbox"`     // in user space
+	x, y     float64         // baseline origin in text direction coordinates
+	angle    int             // text direction in degrees
+	end      float64         // baseline end in text direction coordinates
+}
+
+// PageText represents the text of a page in reading order.
+type PageText struct {
+	Page int       `json:"page"`
+	Runs []TextRun `json:"runs"`
+}
+
+// String returns the plain text of a page in reading order.
+func (pt PageText) String() string {
+	var sb strings.Builder
+	for i, r := range pt.Runs {
+		if i > 0 {
+			prev := pt.Runs[i-1]
+			if r.Line != prev.Line {
+				sb.WriteString("\n")
+			} else if r.x-prev.end > 0.15*math.Min(r.FontSize, prev.FontSize) {
+				// Runs of a line may be split due to font changes only.
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString(r.Text)
+	}
+	if len(pt.Runs) > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// textChar represents a single decoded glyph positioned in user space.
+type textChar struct {
+	text   string
+	font   string
+	size   float64
+	origin types.Point // baseline start
+	end    types.Point // baseline end
+	dir    types.Point // unit vector in text direction
+	bbox   types.Rectangle
+}
+
+type textState struct {
+	charSpace  float64
+	wordSpace  float64
+	hScale     float64
+	leading    float64
+	font       *font.Decoder
+	fontSize   float64
+	rise       float64
+	ctm        matrix.Matrix
+	resources  types.Dict
+	fontsCache map[string]*font.Decoder // per resource dict
+}
+
+type textExtractor struct {
+	ctx     *model.Context
+	gs      textState
+	stack   []textState
+	tm, tlm matrix.Matrix
+	chars   []textChar
+	fonts   map[int]*font.Decoder // fonts by object number
+	forms   types.IntSet          // form XObjects in process
+}
+
+func pdfMatrix(a, b, c, d, e, f float64) matrix.Matrix {
+	return matrix.Matrix{{a, b, 0}, {c, d, 0}, {e, f, 1}}
+}
+
+func numbers(operands []types.Object, n int) ([]float64, bool) {
+	if len(operands) < n {
+		return nil, false
+	}
+	ff := make([]float64, n)
+	for i, o := range operands[len(operands)-n:] {
+		switch o := o.(type) {
+		case types.Integer:
+			ff[i] = float64(o.Value())
+		case types.Float:
+			ff[i] = o.Value()
+		default:
+			return nil, false
+		}
+	}
+	return ff, true
+}
+
+func (te *textExtractor) fontDecoder(name string) (*font.Decoder, error) {
+	if dec, ok := te.gs.fontsCache[name]; ok {
+		return dec, nil
+	}
+
+	d, err := te.ctx.DereferenceDict(te.gs.resources["Font"])
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	o, found := d.Find(name)
+	if !found {
+		return nil, nil
+	}
+
+	objNr := -1
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr = indRef.ObjectNumber.Value()
+		if dec, ok := te.fonts[objNr]; ok {
+			te.gs.fontsCache[name] = dec
+			return dec, nil
+		}
+	}
+
+	fd, err := te.ctx.DereferenceDict(o)
+	if err != nil || fd == nil {
+		return nil, err
+	}
+
+	dec, err := font.NewDecoder(te.ctx.XRefTable, fd)
+	if err != nil {
+		return nil, err
+	}
+
+	if objNr >= 0 {
+		te.fonts[objNr] = dec
+	}
+	te.gs.fontsCache[name] = dec
+
+	return dec, nil
+}
+
+func (te *textExtractor) setFont(operands []types.Object) error {
+	ff, ok := numbers(operands, 1)
+	if !ok || len(operands) < 2 {
+		return nil
+	}
+	n, ok := operands[len(operands)-2].(types.Name)
+	if !ok {
+		return nil
+	}
+	dec, err := te.fontDecoder(n.Value())
+	if err != nil {
+		return err
+	}
+	te.gs.font = dec
+	te.gs.fontSize = ff[0]
+	return nil
+}
+
+func (te *textExtractor) moveTextPosition(tx, ty float64) {
+	te.tlm = pdfMatrix(1, 0, 0, 1, tx, ty).Multiply(te.tlm)
+	te.tm = te.tlm
+}
+
+func unitVector(p types.Point) (types.Point, float64) {
+	l := math.Hypot(p.X, p.Y)
+	if l == 0 {
+		return types.Point{X: 1}, 0
+	}
+	return types.Point{X: p.X / l, Y: p.Y / l}, l
+}
+
+func boundingBox(pp ...types.Point) types.Rectangle {
+	r := types.Rectangle{LL: pp[0], UR: pp[0]}
+	for _, p := range pp[1:] {
+		r.LL.X = math.Min(r.LL.X, p.X)
+		r.LL.Y = math.Min(r.LL.Y, p.Y)
+		r.UR.X = math.Max(r.UR.X, p.X)
+		r.UR.Y = math.Max(r.UR.Y, p.Y)
+	}
+	return r
+}
+
+// showGlyphs positions the glyphs of a string according to 9.4.4.
+func (te *textExtractor) showGlyphs(bb []byte) {
+	dec := te.gs.font
+	if dec == nil {
+		return
+	}
+
+	tfs, th := te.gs.fontSize, te.gs.hScale
+
+	for _, g := range dec.Decode(bb) {
+		trm := pdfMatrix(tfs*th, 0, 0, tfs, 0, te.gs.rise).Multiply(te.tm).Multiply(te.gs.ctm)
+
+		wordSep := g.Len == 1 && g.Code == 32
+
+		var tx, ty float64
+		var ll, ur types.Point
+
+		if dec.Vertical {
+			ty = -tfs + te.gs.charSpace
+			if wordSep {
+				ty += te.gs.wordSpace
+			}
+			ll, ur = types.Point{X: -0.5, Y: -1}, types.Point{X: 0.5, Y: 0}
+		} else {
+			tx = g.Width*tfs + te.gs.charSpace
+			if wordSep {
+				tx += te.gs.wordSpace
+			}
+			tx *= th
+			ll, ur = types.Point{X: 0, Y: dec.Descent}, types.Point{X: g.Width, Y: dec.Ascent}
+		}
+
+		origin := trm.Transform(types.Point{})
+		dir, _ := unitVector(types.Point{X: trm[0][0], Y: trm[0][1]})
+		if dec.Vertical {
+			dir, _ = unitVector(types.Point{X: -trm[1][0], Y: -trm[1][1]})
+		}
+		_, size := unitVector(types.Point{X: trm[1][0], Y: trm[1][1]})
+
+		te.tm = pdfMatrix(1, 0, 0, 1, tx, ty).Multiply(te.tm)
+
+		// The glyph origin of the next glyph.
+		end := pdfMatrix(tfs*th, 0, 0, tfs, 0, te.gs.rise).Multiply(te.tm).Multiply(te.gs.ctm).Transform(types.Point{})
+
+		te.chars = append(te.chars, textChar{
+			text:   g.Text,
+			font:   dec.Name,
+			size:   size,
+			origin: origin,
+			end:    end,
+			dir:    dir,
+			bbox:   boundingBox(trm.Transform(ll), trm.Transform(ur), trm.Transform(types.Point{X: ll.X, Y: ur.Y}), trm.Transform(types.Point{X: ur.X, Y: ll.Y})),
+		})
+	}
+}
+
+func (te *textExtractor) showText(o types.Object) {
+	bb, err := types.StringBytes(o)
+	if err != nil {
+		return
+	}
+	te.showGlyphs(bb)
+}
+
+func (te *textExtractor) showTextArray(operands []types.Object) {
+	if len(operands) == 0 {
+		return
+	}
+	a, ok := operands[len(operands)-1].(types.Array)
+	if !ok {
+		return
+	}
+	for _, o := range a {
+		switch o := o.(type) {
+		case types.Integer:
+			te.adjust(float64(o.Value()))
+		case types.Float:
+			te.adjust(o.Value())
+		default:
+			te.showText(o)
+		}
+	}
+}
+
+// adjust applies a TJ position adjustment expressed in thousandths of a unit of text space.
+func (te *textExtractor) adjust(f float64) {
+	d := -f / 1000 * te.gs.fontSize
+	if te.gs.font != nil && te.gs.font.Vertical {
+		te.tm = pdfMatrix(1, 0, 0, 1, 0, d).Multiply(te.tm)
+		return
+	}
+	te.tm = pdfMatrix(1, 0, 0, 1, d*te.gs.hScale, 0).Multiply(te.tm)
+}
+
+func (te *textExtractor) nextLine() {
+	te.moveTextPosition(0, -te.gs.leading)
+}
+
+func (te *textExtractor) doXObject(operands []types.Object) error {
+	if len(operands) == 0 {
+		return nil
+	}
+	n, ok := operands[len(operands)-1].(types.Name)
+	if !ok {
+		return nil
+	}
+
+	d, err := te.ctx.DereferenceDict(te.gs.resources["XObject"])
+	if err != nil || d == nil {
+		return err
+	}
+
+	o, found := d.Find(n.Value())
+	if !found {
+		return nil
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+
+	objNr := indRef.ObjectNumber.Value()
+	if te.forms[objNr] {
+		// Recursive form.
+		return nil
+	}
+
+	sd, _, err := te.ctx.DereferenceStreamDict(indRef)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	if st := sd.Subtype(); st == nil || *st != "Form" {
+		return nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	te.forms[objNr] = true
+	defer delete(te.forms, objNr)
+
+	te.stack = append(te.stack, te.gs)
+	defer func() {
+		te.gs = te.stack[len(te.stack)-1]
+		te.stack = te.stack[:len(te.stack)-1]
+	}()
+
+	if a, err := te.ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(a) == 6 {
+		if ff, ok := numbers(a, 6); ok {
+			te.gs.ctm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5]).Multiply(te.gs.ctm)
+		}
+	}
+
+	if res, err := te.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && res != nil {
+		te.gs.resources = res
+		te.gs.fontsCache = map[string]*font.Decoder{}
+	}
+
+	tm, tlm := te.tm, te.tlm
+	defer func() { te.tm, te.tlm = tm, tlm }()
+
+	return te.processContent(sd.Content)
+}
+
+func (te *textExtractor) saveGraphicsState() {
+	te.stack = append(te.stack, te.gs)
+}
+
+func (te *textExtractor) restoreGraphicsState() {
+	if len(te.stack) == 0 {
+		return
+	}
+	te.gs = te.stack[len(te.stack)-1]
+	te.stack = te.stack[:len(te.stack)-1]
+}
+
+func (te *textExtractor) processTextStateOperator(op model.Operator, operands []types.Object) (bool, error) {
+	switch op {
+
+	case model.OpSetCharSpacing:
+		if ff, ok := numbers(operands, 1); ok {
+			te.gs.charSpace = ff[0]
+		}
+
+	case model.OpSetWordSpacing:
+		if ff, ok := numbers(operands, 1); ok {
+			te.gs.wordSpace = ff[0]
+		}
+
+	case model.OpSetHorizScaling:
+		if ff, ok := numbers(operands, 1); ok {
+			te.gs.hScale = ff[0] / 100
+		}
+
+	case model.OpSetLeading:
+		if ff, ok := numbers(operands, 1); ok {
+			te.gs.leading = ff[0]
+		}
+
+	case model.OpSetTextRise:
+		if ff, ok := numbers(operands, 1); ok {
+			te.gs.rise = ff[0]
+		}
+
+	case model.OpSetFont:
+		return true, te.setFont(operands)
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (te *textExtractor) processTextOperator(op model.Operator, operands []types.Object) bool {
+	switch op {
+
+	case model.OpBeginText:
+		te.tm, te.tlm = matrix.IdentMatrix, matrix.IdentMatrix
+
+	case model.OpMoveText:
+		if ff, ok := numbers(operands, 2); ok {
+			te.moveTextPosition(ff[0], ff[1])
+		}
+
+	case model.OpMoveTextSetLeading:
+		if ff, ok := numbers(operands, 2); ok {
+			te.gs.leading = -ff[1]
+			te.moveTextPosition(ff[0], ff[1])
+		}
+
+	case model.OpSetTextMatrix:
+		if ff, ok := numbers(operands, 6); ok {
+			te.tlm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+			te.tm = te.tlm
+		}
+
+	case model.OpNextLine:
+		te.nextLine()
+
+	case model.OpShowText:
+		if len(operands) > 0 {
+			te.showText(operands[len(operands)-1])
+		}
+
+	case model.OpShowTextArray:
+		te.showTextArray(operands)
+
+	case model.OpMoveShowText:
+		te.nextLine()
+		if len(operands) > 0 {
+			te.showText(operands[len(operands)-1])
+		}
+
+	case model.OpMoveSetShowText:
+		if len(operands) == 3 {
+			if ff, ok := numbers(operands[:2], 2); ok {
+				te.gs.wordSpace, te.gs.charSpace = ff[0], ff[1]
+			}
+		}
+		te.nextLine()
+		if len(operands) > 0 {
+			te.showText(operands[len(operands)-1])
+		}
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (te *textExtractor) processOperator(op model.Operator, operands []types.Object) error {
+	if ok, err := te.processTextStateOperator(op, operands); ok {
+		return err
+	}
+
+	if te.processTextOperator(op, operands) {
+		return nil
+	}
+
+	switch op {
+
+	case model.OpSave:
+		te.saveGraphicsState()
+
+	case model.OpRestore:
+		te.restoreGraphicsState()
+
+	case model.OpConcatMatrix:
+		if ff, ok := numbers(operands, 6); ok {
+			te.gs.ctm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5]).Multiply(te.gs.ctm)
+		}
+
+	case model.OpPaintXObject:
+		return te.doXObject(operands)
+	}
+
+	return nil
+}
+
+func (te *textExtractor) processContent(bb []byte) error {
+	p := model.NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := te.processOperator(op.Operator, op.Operands); err != nil {
+			return err
+		}
+	}
+}
+
+// textCoords returns the coordinates of p in a coordinate system rotated into the text direction dir.
+func textCoords(p, dir types.Point) (float64, float64) {
+	return dir.X*p.X + dir.Y*p.Y, -dir.Y*p.X + dir.X*p.Y
+}
+
+func textAngle(dir types.Point) int {
+	a := int(math.Round(math.Atan2(dir.Y, dir.X) * matrix.RadToDeg))
+	if a < 0 {
+		a += 360
+	}
+	return a % 360
+}
+
+func newTextRun(c textChar) *TextRun {
+	x, y := textCoords(c.origin, c.dir)
+	end, _ := textCoords(c.end, c.dir)
+	return &TextRun{Text: c.text, Font: c.font, FontSize: c.size, BBox: c.bbox, x: x, y: y, angle: textAngle(c.dir), end: end}
+}
+
+// extend adds c to r if c continues r, possibly separated by a word break.
+func (r *TextRun) extend(c textChar) bool {
+	if c.font != r.Font || math.Abs(c.size-r.FontSize) > 0.1*r.FontSize || textAngle(c.dir) != r.angle {
+		return false
+	}
+
+	dir := c.dir
+	x, y := textCoords(c.origin, dir)
+	if math.Abs(y-r.y) > 0.3*r.FontSize {
+		return false
+	}
+
+	gap := x - r.end
+	if gap < -0.5*r.FontSize || gap > r.FontSize {
+		return false
+	}
+
+	// Assume a word break for gaps exceeding 15% of the font size.
+	if gap > 0.15*r.FontSize && !strings.HasSuffix(r.Text, " ") && !strings.HasPrefix(c.text, " ") {
+		r.Text += " "
+	}
+
+	r.Text += c.text
+	r.end, _ = textCoords(c.end, dir)
+	r.BBox = *model.CalcBoundingBoxForRects(&r.BBox, &c.bbox)
+
+	return true
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// textRuns merges chars into runs.
+func textRuns(chars []textChar) []TextRun {
+	var (
+		rr []TextRun
+		r  *TextRun
+	)
+
+	flush := func() {
+		if r == nil {
+			return
+		}
+		if s := strings.TrimSpace(r.Text); s != "" {
+			r.Text = s
+			r.FontSize = round2(r.FontSize)
+			r.BBox = *types.NewRectangle(round2(r.BBox.LL.X), round2(r.BBox.LL.Y), round2(r.BBox.UR.X), round2(r.BBox.UR.Y))
+			rr = append(rr, *r)
+		}
+		r = nil
+	}
+
+	for _, c := range chars {
+		if r != nil && r.extend(c) {
+			continue
+		}
+		flush()
+		r = newTextRun(c)
+	}
+	flush()
+
+	return rr
+}
+
+// sortTextRuns puts rr into reading order: top to bottom, left to right for each text direction.
+func sortTextRuns(rr []TextRun) {
+	sort.SliceStable(rr, func(i, j int) bool {
+		if rr[i].angle != rr[j].angle {
+			return rr[i].angle < rr[j].angle
+		}
+		return rr[i].y > rr[j].y
+	})
+
+	// Group runs into lines.
+	line := 0
+	for i := range rr {
+		if i > 0 {
+			first := rr[i-1]
+			for k := i - 1; k >= 0 && rr[k].Line == line; k-- {
+				first = rr[k]
+			}
+			tol := 0.5 * math.Min(first.FontSize, rr[i].FontSize)
+			if rr[i].angle != first.angle || first.y-rr[i].y > tol {
+				line++
+			}
+		}
+		rr[i].Line = line
+	}
+
+	sort.SliceStable(rr, func(i, j int) bool {
+		if rr[i].Line != rr[j].Line {
+			return rr[i].Line < rr[j].Line
+		}
+		return rr[i].x < rr[j].x
+	})
+}
+
+// ExtractPageText extracts the text of pageNr as text runs in reading order.
+func ExtractPageText(ctx *model.Context, pageNr int) (*PageText, error) {
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.Errorf("pdfcpu: unknown page number: %d", pageNr)
+	}
+
+	pt := &PageText{Page: pageNr, Runs: []TextRun{}}
+
+	bb, err := ctx.PageContent(d)
+	if err == model.ErrNoContent {
+		return pt, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	te := &textExtractor{
+		ctx:   ctx,
+		fonts: map[int]*font.Decoder{},
+		forms: types.IntSet{},
+		gs: textState{
+			hScale:     1,
+			ctm:        matrix.IdentMatrix,
+			resources:  inhPAttrs.Resources,
+			fontsCache: map[string]*font.Decoder{},
+		},
+	}
+
+	if err := te.processContent(bb); err != nil {
+		return nil, err
+	}
+
+	if log.DebugEnabled() {
+		log.Debug.Printf("ExtractPageText: page %d: %d chars\n", pageNr, len(te.chars))
+	}
+
+	pt.Runs = textRuns(te.chars)
+	sortTextRuns(pt.Runs)
+
+	return pt, nil
+}
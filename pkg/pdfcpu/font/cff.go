@@ -0,0 +1,832 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pkg/errors"
+)
+
+var errCorruptCFF = errors.New("pdfcpu: corrupt CFF font program")
+
+// CFF represents a Compact Font Format font program (Adobe Technical Note #5176) providing glyph outlines.
+type CFF struct {
+	Name        string
+	FontMatrix  [6]float64
+	IsCID       bool
+	charStrings [][]byte
+	gsubrs      [][]byte
+	privs       []cffPrivate // one per Font DICT, a single one for name-keyed fonts
+	fdSelect    []byte       // Font DICT index by glyph index, CID-keyed fonts only
+	charset     []uint16     // SID or CID by glyph index
+	encoding    [256]uint16  // glyph index by code, name-keyed fonts only
+	strings     []string
+	gids        map[uint16]uint16 // glyph index by SID or CID
+}
+
+type cffPrivate struct {
+	subrs [][]byte
+}
+
+// cffDict maps operators to operands of a Top, Font or Private DICT.
+type cffDict map[int][]float64
+
+func (d cffDict) int(op, def int) int {
+	if v, ok := d[op]; ok && len(v) > 0 {
+		return int(v[0])
+	}
+	return def
+}
+
+func parseCFFDict(bb []byte) (cffDict, error) {
+	entries, err := font.ParseCFFDict(bb)
+	if err != nil {
+		return nil, err
+	}
+	d := cffDict{}
+	for _, e := range entries {
+		d[e.Op] = e.Operands()
+	}
+	return d, nil
+}
+
+// ParseCFF parses a bare CFF font program as embedded using FontFile3 of subtype Type1C or CIDFontType0C.
+func ParseCFF(bb []byte) (*CFF, error) {
+	if len(bb) < 4 {
+		return nil, errCorruptCFF
+	}
+
+	names, off, err := font.CFFIndex(bb, int(bb[2]))
+	if err != nil {
+		return nil, err
+	}
+
+	topDicts, off, err := font.CFFIndex(bb, off)
+	if err != nil || len(topDicts) == 0 {
+		return nil, errCorruptCFF
+	}
+
+	strs, off, err := font.CFFIndex(bb, off)
+	if err != nil {
+		return nil, err
+	}
+
+	gsubrs, _, err := font.CFFIndex(bb, off)
+	if err != nil {
+		return nil, err
+	}
+
+	cff := &CFF{gsubrs: gsubrs, FontMatrix: [6]float64{0.001, 0, 0, 0.001, 0, 0}}
+	if len(names) > 0 {
+		cff.Name = string(names[0])
+	}
+	for _, s := range strs {
+		cff.strings = append(cff.strings, string(s))
+	}
+
+	top, err := parseCFFDict(topDicts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if m := top[font.CFFEscape+7]; len(m) == 6 {
+		copy(cff.FontMatrix[:], m)
+	}
+
+	if cff.charStrings, _, err = font.CFFIndex(bb, top.int(17, 0)); err != nil {
+		return nil, err
+	}
+	if len(cff.charStrings) == 0 {
+		return nil, errCorruptCFF
+	}
+
+	_, cff.IsCID = top[font.CFFEscape+30]
+
+	if cff.IsCID {
+		if err := cff.parseFDs(bb, top); err != nil {
+			return nil, err
+		}
+	} else {
+		priv, err := parseCFFPrivate(bb, top[18])
+		if err != nil {
+			return nil, err
+		}
+		cff.privs = []cffPrivate{priv}
+	}
+
+	cff.parseCharset(bb, top.int(15, 0))
+
+	cff.gids = map[uint16]uint16{}
+	for gid, sid := range cff.charset {
+		if _, ok := cff.gids[sid]; !ok {
+			cff.gids[sid] = uint16(gid)
+		}
+	}
+
+	if !cff.IsCID {
+		cff.parseEncoding(bb, top.int(16, 0))
+	}
+
+	return cff, nil
+}
+
+func parseCFFPrivate(bb []byte, sizeOff []float64) (cffPrivate, error) {
+	var priv cffPrivate
+	if len(sizeOff) != 2 {
+		return priv, nil
+	}
+	size, off := int(sizeOff[0]), int(sizeOff[1])
+	if off < 0 || size < 0 || off+size > len(bb) {
+		return priv, errCorruptCFF
+	}
+
+	d, err := parseCFFDict(bb[off : off+size])
+	if err != nil {
+		return priv, err
+	}
+	if o := d.int(19, 0); o > 0 {
+		subrs, _, err := font.CFFIndex(bb, off+o)
+		if err != nil {
+			return priv, err
+		}
+		priv.subrs = subrs
+	}
+
+	return priv, nil
+}
+
+func (cff *CFF) parseFDs(bb []byte, top cffDict) error {
+	fds, _, err := font.CFFIndex(bb, top.int(font.CFFEscape+36, 0))
+	if err != nil {
+		return err
+	}
+	for _, fd := range fds {
+		d, err := parseCFFDict(fd)
+		if err != nil {
+			return err
+		}
+		priv, err := parseCFFPrivate(bb, d[18])
+		if err != nil {
+			return err
+		}
+		cff.privs = append(cff.privs, priv)
+	}
+	if len(cff.privs) == 0 {
+		return errCorruptCFF
+	}
+
+	n := len(cff.charStrings)
+	cff.fdSelect = make([]byte, n)
+
+	off := top.int(font.CFFEscape+37, 0)
+	if off <= 0 || off >= len(bb) {
+		return nil
+	}
+
+	switch bb[off] {
+	case 0:
+		for gid := 0; gid < n && off+1+gid < len(bb); gid++ {
+			cff.fdSelect[gid] = bb[off+1+gid]
+		}
+	case 3:
+		nRanges := ttU16(bb, off+1)
+		for i := 0; i < nRanges; i++ {
+			rec := off + 3 + 3*i
+			first, fd, next := ttU16(bb, rec), bb[min(rec+2, len(bb)-1)], ttU16(bb, rec+3)
+			for gid := first; gid < next && gid < n; gid++ {
+				cff.fdSelect[gid] = fd
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cff *CFF) parseCharset(bb []byte, off int) {
+	n := len(cff.charStrings)
+	cff.charset = make([]uint16, n)
+
+	if off <= 2 || off >= len(bb) {
+		// Predefined charsets: use the ISOAdobe charset which maps glyph indices to SIDs.
+		for gid := range cff.charset {
+			cff.charset[gid] = uint16(gid)
+		}
+		return
+	}
+
+	format := bb[off]
+	p := off + 1
+	gid := 1
+	for gid < n && p < len(bb) {
+		switch format {
+		case 0:
+			cff.charset[gid] = uint16(ttU16(bb, p))
+			gid++
+			p += 2
+		case 1, 2:
+			first := ttU16(bb, p)
+			var left int
+			if format == 1 {
+				if p+2 >= len(bb) {
+					return
+				}
+				left = int(bb[p+2])
+				p += 3
+			} else {
+				left = ttU16(bb, p+2)
+				p += 4
+			}
+			for i := 0; i <= left && gid < n; i++ {
+				cff.charset[gid] = uint16(first + i)
+				gid++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (cff *CFF) parseEncoding(bb []byte, off int) {
+	if off <= 1 || off >= len(bb) {
+		// Standard or Expert encoding.
+		if off == 0 {
+			for c, name := range baseEncoding("StandardEncoding") {
+				if gid, ok := cff.GlyphIndexForName(name); ok && name != "" {
+					cff.encoding[c] = gid
+				}
+			}
+		}
+		return
+	}
+
+	format := bb[off]
+	p := off + 1
+	switch format & 0x7F {
+	case 0:
+		if p >= len(bb) {
+			return
+		}
+		n := int(bb[p])
+		for i := 1; i <= n && p+i < len(bb); i++ {
+			cff.encoding[bb[p+i]] = uint16(i)
+		}
+		p += 1 + n
+	case 1:
+		if p >= len(bb) {
+			return
+		}
+		nRanges := int(bb[p])
+		gid := 1
+		for i := 0; i < nRanges && p+2+2*i < len(bb); i++ {
+			first, left := int(bb[p+1+2*i]), int(bb[p+2+2*i])
+			for c := first; c <= first+left && c < 256; c++ {
+				cff.encoding[c] = uint16(gid)
+				gid++
+			}
+		}
+		p += 1 + 2*nRanges
+	}
+
+	if format&0x80 != 0 && p < len(bb) {
+		// Supplements
+		n := int(bb[p])
+		for i := 0; i < n; i++ {
+			rec := p + 1 + 3*i
+			if rec+3 > len(bb) {
+				break
+			}
+			if gid, ok := cff.gids[uint16(ttU16(bb, rec+1))]; ok {
+				cff.encoding[bb[rec]] = gid
+			}
+		}
+	}
+}
+
+func (cff *CFF) sid(name string) (uint16, bool) {
+	for i, s := range font.CFFStandardStrings {
+		if s == name {
+			return uint16(i), true
+		}
+	}
+	for i, s := range cff.strings {
+		if s == name {
+			return uint16(len(font.CFFStandardStrings) + i), true
+		}
+	}
+	return 0, false
+}
+
+// NumGlyphs returns the number of glyphs.
+func (cff *CFF) NumGlyphs() int {
+	return len(cff.charStrings)
+}
+
+// GlyphIndexForName returns the glyph index for a glyph name of a name-keyed font.
+func (cff *CFF) GlyphIndexForName(name string) (uint16, bool) {
+	if cff.IsCID {
+		return 0, false
+	}
+	sid, ok := cff.sid(name)
+	if !ok {
+		return 0, false
+	}
+	gid, ok := cff.gids[sid]
+	return gid, ok
+}
+
+// GlyphIndexForCID returns the glyph index for a CID of a CID-keyed font.
+func (cff *CFF) GlyphIndexForCID(cid uint32) (uint16, bool) {
+	if !cff.IsCID {
+		if int(cid) < len(cff.charStrings) {
+			return uint16(cid), true
+		}
+		return 0, false
+	}
+	gid, ok := cff.gids[uint16(cid)]
+	return gid, ok
+}
+
+// GlyphIndexForCode returns the glyph index for a character code using the font's built-in encoding.
+func (cff *CFF) GlyphIndexForCode(c byte) (uint16, bool) {
+	gid := cff.encoding[c]
+	return gid, gid > 0
+}
+
+// Outline returns the outline of glyph gid in glyph space.
+func (cff *CFF) Outline(gid uint16) ([]Segment, error) {
+	if int(gid) >= len(cff.charStrings) {
+		return nil, nil
+	}
+	ip := &type2Interpreter{cff: cff}
+	if err := ip.run(gid, 0, 0); err != nil {
+		return nil, err
+	}
+	return ip.b.segs, nil
+}
+
+func (cff *CFF) private(gid uint16) cffPrivate {
+	if cff.fdSelect != nil && int(gid) < len(cff.fdSelect) && int(cff.fdSelect[gid]) < len(cff.privs) {
+		return cff.privs[cff.fdSelect[gid]]
+	}
+	return cff.privs[0]
+}
+
+// type2Interpreter executes Type 2 charstrings, see Adobe Technical Note #5177.
+type type2Interpreter struct {
+	cff       *CFF
+	b         outlineBuilder
+	stack     []float64
+	nStems    int
+	haveWidth bool
+	transient [32]float64
+	depth     int
+	done      bool
+}
+
+func (ip *type2Interpreter) run(gid uint16, dx, dy float64) error {
+	ip.b.dx, ip.b.dy = dx, dy
+	ip.b.x, ip.b.y = 0, 0
+	ip.stack, ip.nStems, ip.haveWidth, ip.done = ip.stack[:0], 0, false, false
+	priv := ip.cff.private(gid)
+	return ip.exec(ip.cff.charStrings[gid], priv)
+}
+
+// clearWidth drops the optional advance width preceding the first stack clearing operator.
+func (ip *type2Interpreter) clearWidth(n int, even bool) {
+	if ip.haveWidth {
+		return
+	}
+	ip.haveWidth = true
+	if (even && len(ip.stack)%2 == 1) || (!even && len(ip.stack) > n) {
+		ip.stack = ip.stack[1:]
+	}
+}
+
+func (ip *type2Interpreter) rlineto(args []float64) {
+	for i := 0; i+1 < len(args); i += 2 {
+		ip.b.lineTo(ip.b.x+args[i], ip.b.y+args[i+1])
+	}
+}
+
+func (ip *type2Interpreter) rcurveto(a []float64) {
+	x1, y1 := ip.b.x+a[0], ip.b.y+a[1]
+	x2, y2 := x1+a[2], y1+a[3]
+	ip.b.cubeTo(x1, y1, x2, y2, x2+a[4], y2+a[5])
+}
+
+// alternatingLines handles hlineto and vlineto.
+func (ip *type2Interpreter) alternatingLines(args []float64, horizontal bool) {
+	for _, v := range args {
+		if horizontal {
+			ip.b.lineTo(ip.b.x+v, ip.b.y)
+		} else {
+			ip.b.lineTo(ip.b.x, ip.b.y+v)
+		}
+		horizontal = !horizontal
+	}
+}
+
+// alternatingCurves handles hvcurveto and vhcurveto.
+func (ip *type2Interpreter) alternatingCurves(args []float64, horizontal bool) {
+	for len(args) >= 4 {
+		last := len(args) < 8 && len(args) == 5
+		var a [6]float64
+		if horizontal {
+			a = [6]float64{args[0], 0, args[1], args[2], 0, args[3]}
+			if last {
+				a[4] = args[4]
+			}
+		} else {
+			a = [6]float64{0, args[0], args[1], args[2], args[3], 0}
+			if last {
+				a[5] = args[4]
+			}
+		}
+		ip.rcurveto(a[:])
+		if last {
+			return
+		}
+		args = args[4:]
+		horizontal = !horizontal
+	}
+}
+
+func (ip *type2Interpreter) flex(a [12]float64) {
+	ip.rcurveto(a[:6])
+	ip.rcurveto(a[6:])
+}
+
+func (ip *type2Interpreter) escapeOp(op byte) {
+	s := ip.stack
+	switch op {
+
+	case 35: // flex
+		if len(s) >= 12 {
+			var a [12]float64
+			copy(a[:], s[:12])
+			ip.flex(a)
+		}
+
+	case 34: // hflex
+		if len(s) >= 7 {
+			ip.flex([12]float64{s[0], 0, s[1], s[2], s[3], 0, s[4], 0, s[5], -s[2], s[6], 0})
+		}
+
+	case 36: // hflex1
+		if len(s) >= 9 {
+			dy := s[1] + s[3] + s[7]
+			ip.flex([12]float64{s[0], s[1], s[2], s[3], s[4], 0, s[5], 0, s[6], s[7], s[8], -dy})
+		}
+
+	case 37: // flex1
+		if len(s) >= 11 {
+			dx := s[0] + s[2] + s[4] + s[6] + s[8]
+			dy := s[1] + s[3] + s[5] + s[7] + s[9]
+			a := [12]float64{s[0], s[1], s[2], s[3], s[4], s[5], s[6], s[7], s[8], s[9]}
+			if math.Abs(dx) > math.Abs(dy) {
+				a[10], a[11] = s[10], -dy
+			} else {
+				a[10], a[11] = -dx, s[10]
+			}
+			ip.flex(a)
+		}
+
+	default:
+		ip.arithmetic(op)
+		return
+	}
+
+	ip.stack = ip.stack[:0]
+}
+
+func (ip *type2Interpreter) pop() float64 {
+	if len(ip.stack) == 0 {
+		return 0
+	}
+	v := ip.stack[len(ip.stack)-1]
+	ip.stack = ip.stack[:len(ip.stack)-1]
+	return v
+}
+
+func (ip *type2Interpreter) push(v float64) {
+	ip.stack = append(ip.stack, v)
+}
+
+func (ip *type2Interpreter) arithmetic(op byte) {
+	b2i := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	switch op {
+	case 3: // and
+		b, a := ip.pop(), ip.pop()
+		ip.push(b2i(a != 0 && b != 0))
+	case 4: // or
+		b, a := ip.pop(), ip.pop()
+		ip.push(b2i(a != 0 || b != 0))
+	case 5: // not
+		ip.push(b2i(ip.pop() == 0))
+	case 9: // abs
+		ip.push(math.Abs(ip.pop()))
+	case 10: // add
+		b, a := ip.pop(), ip.pop()
+		ip.push(a + b)
+	case 11: // sub
+		b, a := ip.pop(), ip.pop()
+		ip.push(a - b)
+	case 12: // div
+		b, a := ip.pop(), ip.pop()
+		if b == 0 {
+			ip.push(0)
+		} else {
+			ip.push(a / b)
+		}
+	case 14: // neg
+		ip.push(-ip.pop())
+	case 15: // eq
+		b, a := ip.pop(), ip.pop()
+		ip.push(b2i(a == b))
+	case 18: // drop
+		ip.pop()
+	case 20: // put
+		i, v := ip.pop(), ip.pop()
+		if i >= 0 && int(i) < len(ip.transient) {
+			ip.transient[int(i)] = v
+		}
+	case 21: // get
+		i := ip.pop()
+		if i >= 0 && int(i) < len(ip.transient) {
+			ip.push(ip.transient[int(i)])
+		} else {
+			ip.push(0)
+		}
+	case 22: // ifelse
+		v2, v1, s2, s1 := ip.pop(), ip.pop(), ip.pop(), ip.pop()
+		if v1 <= v2 {
+			ip.push(s1)
+		} else {
+			ip.push(s2)
+		}
+	case 23: // random
+		ip.push(0.5)
+	case 24: // mul
+		b, a := ip.pop(), ip.pop()
+		ip.push(a * b)
+	case 26: // sqrt
+		ip.push(math.Sqrt(math.Abs(ip.pop())))
+	case 27: // dup
+		v := ip.pop()
+		ip.push(v)
+		ip.push(v)
+	case 28: // exch
+		b, a := ip.pop(), ip.pop()
+		ip.push(b)
+		ip.push(a)
+	case 29: // index
+		i := int(ip.pop())
+		if i < 0 {
+			i = 0
+		}
+		if i < len(ip.stack) {
+			ip.push(ip.stack[len(ip.stack)-1-i])
+		} else {
+			ip.push(0)
+		}
+	case 30: // roll
+		j, n := int(ip.pop()), int(ip.pop())
+		if n > 0 && n <= len(ip.stack) {
+			s := ip.stack[len(ip.stack)-n:]
+			j = ((j % n) + n) % n
+			r := append(append([]float64{}, s[n-j:]...), s[:n-j]...)
+			copy(s, r)
+		}
+	default:
+		ip.stack = ip.stack[:0]
+	}
+}
+
+// seac renders an accented character composed of two glyphs of the StandardEncoding.
+func (ip *type2Interpreter) seac(adx, ady float64, bchar, achar int) error {
+	enc := baseEncoding("StandardEncoding")
+	if bchar < 0 || bchar > 255 || achar < 0 || achar > 255 {
+		return nil
+	}
+	bgid, ok1 := ip.cff.GlyphIndexForName(enc[bchar])
+	agid, ok2 := ip.cff.GlyphIndexForName(enc[achar])
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	b := ip.b
+	ip1 := &type2Interpreter{cff: ip.cff, b: b, depth: ip.depth + 1}
+	if err := ip1.run(bgid, b.dx, b.dy); err != nil {
+		return err
+	}
+	if err := ip1.run(agid, b.dx+adx, b.dy+ady); err != nil {
+		return err
+	}
+	ip.b.segs = ip1.b.segs
+	return nil
+}
+
+func (ip *type2Interpreter) exec(cs []byte, priv cffPrivate) error {
+	ip.depth++
+	defer func() { ip.depth-- }()
+	if ip.depth > 10 {
+		return errCorruptCFF
+	}
+
+	for i := 0; i < len(cs) && !ip.done; {
+		b0 := cs[i]
+
+		switch {
+		case b0 == 28:
+			ip.push(float64(ttI16(cs, i+1)))
+			i += 3
+			continue
+		case b0 >= 32 && b0 <= 246:
+			ip.push(float64(int(b0) - 139))
+			i++
+			continue
+		case b0 >= 247 && b0 <= 250:
+			if i+1 >= len(cs) {
+				return errCorruptCFF
+			}
+			ip.push(float64((int(b0)-247)*256 + int(cs[i+1]) + 108))
+			i += 2
+			continue
+		case b0 >= 251 && b0 <= 254:
+			if i+1 >= len(cs) {
+				return errCorruptCFF
+			}
+			ip.push(float64(-(int(b0)-251)*256 - int(cs[i+1]) - 108))
+			i += 2
+			continue
+		case b0 == 255:
+			ip.push(float64(int32(ttU32(cs, i+1))) / 65536)
+			i += 5
+			continue
+		}
+
+		i++
+		s := ip.stack
+
+		switch b0 {
+
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			ip.clearWidth(0, true)
+			ip.nStems += len(ip.stack) / 2
+			ip.stack = ip.stack[:0]
+
+		case 19, 20: // hintmask, cntrmask
+			ip.clearWidth(0, true)
+			ip.nStems += len(ip.stack) / 2
+			ip.stack = ip.stack[:0]
+			i += (ip.nStems + 7) / 8
+
+		case 21: // rmoveto
+			ip.clearWidth(2, false)
+			s = ip.stack
+			if len(s) >= 2 {
+				ip.b.closePath()
+				ip.b.moveTo(ip.b.x+s[0], ip.b.y+s[1])
+			}
+			ip.stack = ip.stack[:0]
+
+		case 22: // hmoveto
+			ip.clearWidth(1, false)
+			s = ip.stack
+			if len(s) >= 1 {
+				ip.b.closePath()
+				ip.b.moveTo(ip.b.x+s[0], ip.b.y)
+			}
+			ip.stack = ip.stack[:0]
+
+		case 4: // vmoveto
+			ip.clearWidth(1, false)
+			s = ip.stack
+			if len(s) >= 1 {
+				ip.b.closePath()
+				ip.b.moveTo(ip.b.x, ip.b.y+s[0])
+			}
+			ip.stack = ip.stack[:0]
+
+		case 5: // rlineto
+			ip.rlineto(s)
+			ip.stack = ip.stack[:0]
+
+		case 6, 7: // hlineto, vlineto
+			ip.alternatingLines(s, b0 == 6)
+			ip.stack = ip.stack[:0]
+
+		case 8: // rrcurveto
+			for j := 0; j+6 <= len(s); j += 6 {
+				ip.rcurveto(s[j : j+6])
+			}
+			ip.stack = ip.stack[:0]
+
+		case 24: // rcurveline
+			j := 0
+			for ; j+6 <= len(s)-2; j += 6 {
+				ip.rcurveto(s[j : j+6])
+			}
+			ip.rlineto(s[j:])
+			ip.stack = ip.stack[:0]
+
+		case 25: // rlinecurve
+			j := 0
+			for ; j+2 <= len(s)-6; j += 2 {
+				ip.rlineto(s[j : j+2])
+			}
+			if j+6 <= len(s) {
+				ip.rcurveto(s[j : j+6])
+			}
+			ip.stack = ip.stack[:0]
+
+		case 26: // vvcurveto
+			var dx1 float64
+			if len(s)%4 == 1 {
+				dx1, s = s[0], s[1:]
+			}
+			for j := 0; j+4 <= len(s); j += 4 {
+				ip.rcurveto([]float64{dx1, s[j], s[j+1], s[j+2], 0, s[j+3]})
+				dx1 = 0
+			}
+			ip.stack = ip.stack[:0]
+
+		case 27: // hhcurveto
+			var dy1 float64
+			if len(s)%4 == 1 {
+				dy1, s = s[0], s[1:]
+			}
+			for j := 0; j+4 <= len(s); j += 4 {
+				ip.rcurveto([]float64{s[j], dy1, s[j+1], s[j+2], s[j+3], 0})
+				dy1 = 0
+			}
+			ip.stack = ip.stack[:0]
+
+		case 30, 31: // vhcurveto, hvcurveto
+			ip.alternatingCurves(s, b0 == 31)
+			ip.stack = ip.stack[:0]
+
+		case 10, 29: // callsubr, callgsubr
+			subrs := priv.subrs
+			if b0 == 29 {
+				subrs = ip.cff.gsubrs
+			}
+			j := int(ip.pop()) + font.CFFSubrBias(len(subrs))
+			if j < 0 || j >= len(subrs) {
+				return errCorruptCFF
+			}
+			if err := ip.exec(subrs[j], priv); err != nil {
+				return err
+			}
+
+		case 11: // return
+			return nil
+
+		case 14: // endchar
+			ip.clearWidth(0, true)
+			s = ip.stack
+			ip.b.closePath()
+			if len(s) == 4 {
+				if err := ip.seac(s[0], s[1], int(s[2]), int(s[3])); err != nil {
+					return err
+				}
+			}
+			ip.stack = ip.stack[:0]
+			ip.done = true
+
+		case 12:
+			if i >= len(cs) {
+				return errCorruptCFF
+			}
+			ip.escapeOp(cs[i])
+			i++
+
+		default:
+			ip.stack = ip.stack[:0]
+		}
+	}
+
+	return nil
+}
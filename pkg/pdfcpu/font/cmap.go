@@ -0,0 +1,480 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// codespaceRange represents a range of valid input codes of a CMap, see 9.7.6.2.
+type codespaceRange struct {
+	n        int // code length in bytes
+	low, hig uint32
+}
+
+type cidRange struct {
+	low, hig uint32
+	cid      uint32
+}
+
+// CMap represents the parts of a CMap needed for decoding text, see 9.7.5 and 9.10.3.
+// This covers CMaps mapping codes to CIDs (cidchar, cidrange) as well as ToUnicode CMaps (bfchar, bfrange).
+type CMap struct {
+	Name       string
+	Vertical   bool
+	codespaces []codespaceRange
+	cids       []cidRange
+	unicode    map[uint32]string
+}
+
+// IdentityCMap returns the predefined CMap Identity-H or Identity-V mapping 2 byte codes to CIDs of the same value.
+func IdentityCMap(vertical bool) *CMap {
+	cm := &CMap{Name: "Identity-H", Vertical: vertical}
+	if vertical {
+		cm.Name = "Identity-V"
+	}
+	cm.codespaces = []codespaceRange{{n: 2, low: 0, hig: 0xFFFF}}
+	return cm
+}
+
+// cmapTokenizer splits a CMap into PostScript tokens.
+type cmapTokenizer struct {
+	s string
+}
+
+func (t *cmapTokenizer) skipWhitespaceAndComments() {
+	for len(t.s) > 0 {
+		c := t.s[0]
+		if c == '%' {
+			i := strings.IndexAny(t.s, "\n\r")
+			if i < 0 {
+				t.s = ""
+				return
+			}
+			t.s = t.s[i:]
+			continue
+		}
+		if c != ' ' && c != '\n' && c != '\r' && c != '\t' && c != '\f' && c != 0x00 {
+			return
+		}
+		t.s = t.s[1:]
+	}
+}
+
+func cmapDelimiter(c byte) bool {
+	return strings.IndexByte(" \n\r\t\f\x00()<>[]{}/%", c) >= 0
+}
+
+// next returns the next token.
+// Hex strings are returned including their brackets, string literals are returned in parentheses.
+func (t *cmapTokenizer) next() (string, bool) {
+	t.skipWhitespaceAndComments()
+	if len(t.s) == 0 {
+		return "", false
+	}
+
+	s := t.s
+	switch s[0] {
+
+	case '<':
+		if strings.HasPrefix(s, "<<") {
+			t.s = s[2:]
+			return "<<", true
+		}
+		i := strings.IndexByte(s, '>')
+		if i < 0 {
+			t.s = ""
+			return s, true
+		}
+		t.s = s[i+1:]
+		return s[:i+1], true
+
+	case '>':
+		if strings.HasPrefix(s, ">>") {
+			t.s = s[2:]
+			return ">>", true
+		}
+		t.s = s[1:]
+		return ">", true
+
+	case '[', ']', '{', '}':
+		t.s = s[1:]
+		return s[:1], true
+
+	case '(':
+		depth := 0
+		for i := 0; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					t.s = s[i+1:]
+					return s[:i+1], true
+				}
+			}
+		}
+		t.s = ""
+		return s, true
+
+	case '/':
+		i := 1
+		for i < len(s) && !cmapDelimiter(s[i]) {
+			i++
+		}
+		t.s = s[i:]
+		return s[:i], true
+	}
+
+	i := 0
+	for i < len(s) && !cmapDelimiter(s[i]) {
+		i++
+	}
+	t.s = s[i:]
+	return s[:i], true
+}
+
+// hexDigits returns the even length digit sequence of a hex string token.
+func hexDigits(tok string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\n' || r == '\r' || r == '\t' {
+			return -1
+		}
+		return r
+	}, tok[1:len(tok)-1])
+	if len(s)%2 == 1 {
+		s += "0"
+	}
+	return s
+}
+
+// hexCode decodes a CMap hex string token into its byte length and code value.
+func hexCode(tok string) (int, uint32, error) {
+	if len(tok) < 2 || tok[0] != '<' || tok[len(tok)-1] != '>' {
+		return 0, 0, errCorruptCMap
+	}
+	bb, err := hex.DecodeString(hexDigits(tok))
+	if err != nil || len(bb) > 4 {
+		return 0, 0, errCorruptCMap
+	}
+	var c uint32
+	for _, b := range bb {
+		c = c<<8 | uint32(b)
+	}
+	return len(bb), c, nil
+}
+
+// utf16BEString decodes a bfchar/bfrange destination.
+func utf16BEString(tok string) (string, error) {
+	if len(tok) < 2 || tok[0] != '<' || tok[len(tok)-1] != '>' {
+		return "", errCorruptCMap
+	}
+	bb, err := hex.DecodeString(hexDigits(tok))
+	if err != nil {
+		return "", errCorruptCMap
+	}
+	return decodeUTF16BE(bb), nil
+}
+
+func decodeUTF16BE(bb []byte) string {
+	if len(bb) == 1 {
+		return string(rune(bb[0]))
+	}
+	u := make([]uint16, 0, len(bb)/2)
+	for i := 0; i+1 < len(bb); i += 2 {
+		u = append(u, uint16(bb[i])<<8|uint16(bb[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
+
+// incrementLast increments the last UTF-16 code unit of s by d, see 9.10.3.
+func incrementLast(s string, d uint32) string {
+	u := utf16.Encode([]rune(s))
+	if len(u) == 0 {
+		return s
+	}
+	u[len(u)-1] += uint16(d)
+	return string(utf16.Decode(u))
+}
+
+func (cm *CMap) parseCodespaceRanges(t *cmapTokenizer) error {
+	for {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "endcodespacerange" {
+			return nil
+		}
+		n, low, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		_, hig, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		cm.codespaces = append(cm.codespaces, codespaceRange{n: n, low: low, hig: hig})
+	}
+}
+
+func (cm *CMap) parseCIDChars(t *cmapTokenizer) error {
+	for {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "endcidchar" {
+			return nil
+		}
+		_, c, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		cid, err := strconv.Atoi(tok)
+		if err != nil {
+			return errCorruptCMap
+		}
+		cm.cids = append(cm.cids, cidRange{low: c, hig: c, cid: uint32(cid)})
+	}
+}
+
+func (cm *CMap) parseCIDRanges(t *cmapTokenizer) error {
+	for {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "endcidrange" {
+			return nil
+		}
+		_, low, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		_, hig, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		cid, err := strconv.Atoi(tok)
+		if err != nil {
+			return errCorruptCMap
+		}
+		cm.cids = append(cm.cids, cidRange{low: low, hig: hig, cid: uint32(cid)})
+	}
+}
+
+func (cm *CMap) parseBFChars(t *cmapTokenizer) error {
+	for {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "endbfchar" {
+			return nil
+		}
+		_, c, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		if strings.HasPrefix(tok, "/") {
+			// Destination may be a glyph name.
+			if r, ok := GlyphNameToRune(tok[1:]); ok {
+				cm.unicode[c] = string(r)
+			}
+			continue
+		}
+		s, err := utf16BEString(tok)
+		if err != nil {
+			return err
+		}
+		cm.unicode[c] = s
+	}
+}
+
+func (cm *CMap) parseBFRangeArray(t *cmapTokenizer, low, hig uint32) error {
+	for c := low; ; c++ {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "]" {
+			return nil
+		}
+		s, err := utf16BEString(tok)
+		if err != nil {
+			return err
+		}
+		if c <= hig {
+			cm.unicode[c] = s
+		}
+	}
+}
+
+func (cm *CMap) parseBFRanges(t *cmapTokenizer) error {
+	for {
+		tok, ok := t.next()
+		if !ok {
+			return errCorruptCMap
+		}
+		if tok == "endbfrange" {
+			return nil
+		}
+		_, low, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		tok, _ = t.next()
+		_, hig, err := hexCode(tok)
+		if err != nil {
+			return err
+		}
+		if hig < low || hig-low > 0xFFFF {
+			return errCorruptCMap
+		}
+		tok, _ = t.next()
+		if tok == "[" {
+			if err := cm.parseBFRangeArray(t, low, hig); err != nil {
+				return err
+			}
+			continue
+		}
+		s, err := utf16BEString(tok)
+		if err != nil {
+			return err
+		}
+		for c := low; c <= hig; c++ {
+			cm.unicode[c] = incrementLast(s, c-low)
+		}
+	}
+}
+
+// ParseCMap parses the CMap or ToUnicode CMap in bb.
+// Inherited CMaps referenced via usecmap are not resolved.
+func ParseCMap(bb []byte) (*CMap, error) {
+	cm := &CMap{unicode: map[uint32]string{}}
+	t := &cmapTokenizer{s: string(bb)}
+
+	var prev string
+
+	for {
+		tok, ok := t.next()
+		if !ok {
+			break
+		}
+
+		var err error
+
+		switch tok {
+		case "begincodespacerange":
+			err = cm.parseCodespaceRanges(t)
+		case "begincidchar":
+			err = cm.parseCIDChars(t)
+		case "begincidrange":
+			err = cm.parseCIDRanges(t)
+		case "beginbfchar":
+			err = cm.parseBFChars(t)
+		case "beginbfrange":
+			err = cm.parseBFRanges(t)
+		default:
+			if prev == "/CMapName" && strings.HasPrefix(tok, "/") {
+				cm.Name = tok[1:]
+			}
+			if prev == "/WMode" && tok == "1" {
+				cm.Vertical = true
+			}
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: ParseCMap")
+		}
+
+		prev = tok
+	}
+
+	if len(cm.codespaces) == 0 && len(cm.unicode) == 0 && len(cm.cids) == 0 {
+		return nil, errCorruptCMap
+	}
+
+	return cm, nil
+}
+
+// NextCode returns the next character code of bb and its length in bytes, see 9.7.6.2.
+func (cm *CMap) NextCode(bb []byte) (uint32, int) {
+	if len(cm.codespaces) == 0 {
+		// Assume single byte codes.
+		return uint32(bb[0]), 1
+	}
+
+	var c uint32
+	for n := 1; n <= 4 && n <= len(bb); n++ {
+		c = c<<8 | uint32(bb[n-1])
+		for _, r := range cm.codespaces {
+			if r.n == n && c >= r.low && c <= r.hig {
+				return c, n
+			}
+		}
+	}
+
+	// No matching codespace: consume the length of the shortest codespace range.
+	n := 4
+	for _, r := range cm.codespaces {
+		if r.n < n {
+			n = r.n
+		}
+	}
+	if n > len(bb) {
+		n = len(bb)
+	}
+	c = 0
+	for _, b := range bb[:n] {
+		c = c<<8 | uint32(b)
+	}
+	return c, n
+}
+
+// CID returns the CID for code c.
+func (cm *CMap) CID(c uint32) uint32 {
+	if strings.HasPrefix(cm.Name, "Identity") || len(cm.cids) == 0 {
+		return c
+	}
+	for _, r := range cm.cids {
+		if c >= r.low && c <= r.hig {
+			return r.cid + c - r.low
+		}
+	}
+	return 0
+}
+
+// Unicode returns the Unicode text for code c as defined by a ToUnicode CMap.
+func (cm *CMap) Unicode(c uint32) (string, bool) {
+	s, ok := cm.unicode[c]
+	return s, ok
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import "testing"
+
+const testToUnicodeCMap = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CMapName /Adobe-Identity-UCS def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+2 beginbfchar
+<0003> <0020>
+<00B2> <FB01>
+endbfchar
+2 beginbfrange
+<0024> <0026> <0041>
+<0030> <0031> [<00DF> <D835DC00>]
+endbfrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`
+
+func TestParseToUnicodeCMap(t *testing.T) {
+	cm, err := ParseCMap([]byte(testToUnicodeCMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cm.Name != "Adobe-Identity-UCS" {
+		t.Errorf("name: got %q", cm.Name)
+	}
+
+	for c, want := range map[uint32]string{
+		0x0003: " ",
+		0x00B2: "ﬁ",
+		0x0024: "A",
+		0x0026: "C",
+		0x0030: "ß",
+		0x0031: "\U0001D400",
+	} {
+		if got, ok := cm.Unicode(c); !ok || got != want {
+			t.Errorf("code %04X: got %q, want %q", c, got, want)
+		}
+	}
+
+	if _, ok := cm.Unicode(0x0027); ok {
+		t.Errorf("code 0027: unexpected mapping")
+	}
+
+	c, n := cm.NextCode([]byte{0x00, 0x24, 0x00})
+	if c != 0x24 || n != 2 {
+		t.Errorf("NextCode: got %04X/%d", c, n)
+	}
+}
+
+func TestGlyphNameToRune(t *testing.T) {
+	for name, want := range map[string]rune{
+		"A":        'A',
+		"eacute":   'é',
+		"uni20AC":  '€',
+		"u1F600":   0x1F600,
+		"a.sc":     'a',
+		"quotedbl": '"',
+		"Omega":    'Ω',
+	} {
+		if got, ok := GlyphNameToRune(name); !ok || got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,522 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Aliases for standard font names commonly used for the 14 core fonts.
+var coreFontAliases = map[string]string{
+	"Arial":                    "Helvetica",
+	"Arial,Bold":               "Helvetica-Bold",
+	"Arial,Italic":             "Helvetica-Oblique",
+	"Arial,BoldItalic":         "Helvetica-BoldOblique",
+	"ArialMT":                  "Helvetica",
+	"Arial-BoldMT":             "Helvetica-Bold",
+	"Arial-ItalicMT":           "Helvetica-Oblique",
+	"Arial-BoldItalicMT":       "Helvetica-BoldOblique",
+	"TimesNewRoman":            "Times-Roman",
+	"TimesNewRoman,Bold":       "Times-Bold",
+	"TimesNewRoman,Italic":     "Times-Italic",
+	"TimesNewRoman,BoldItalic": "Times-BoldItalic",
+	"TimesNewRomanPSMT":        "Times-Roman",
+	"CourierNew":               "Courier",
+	"CourierNew,Bold":          "Courier-Bold",
+	"CourierNew,Italic":        "Courier-Oblique",
+	"CourierNew,BoldItalic":    "Courier-BoldOblique",
+	"CourierNewPSMT":           "Courier",
+}
+
+// Glyph represents a decoded character code of a string shown by a text showing operator.
+type Glyph struct {
+	Code  uint32  // character code
+	Len   int     // length of the character code in bytes
+	Text  string  // Unicode text, may be empty
+	Width float64 // horizontal displacement in text space units for a font size of 1
+	Name  string  // glyph name according to the font encoding, simple fonts only
+	CID   uint32  // composite fonts only
+}
+
+// Decoder decodes strings shown using a specific font into Unicode text and glyph metrics.
+type Decoder struct {
+	Name      string  // BaseFont w/o subset prefix
+	Subtype   string  // Type0, Type1, MMType1, Type3, TrueType
+	Vertical  bool    // vertical writing mode
+	Ascent    float64 // in text space units for a font size of 1
+	Descent   float64 // in text space units for a font size of 1, usually negative
+	composite bool
+	scale     float64            // glyph space to text space
+	encoding  *CMap              // composite fonts only
+	toUnicode *CMap              // optional
+	ucs2      bool               // Unicode based predefined CMap
+	names     [256]string        // glyph names for simple fonts
+	firstChar int                // simple fonts only
+	widths    []float64          // simple fonts only, in glyph space
+	missingW  float64            // in glyph space
+	cidWidths map[uint32]float64 // composite fonts only, in glyph space
+	coreFont  string             // one of the 14 core fonts
+}
+
+func baseFontName(s string) string {
+	// Remove subset prefix.
+	if i := strings.IndexByte(s, '+'); i == 6 {
+		s = s[7:]
+	}
+	return s
+}
+
+func (dec *Decoder) cmapStream(xRefTable *model.XRefTable, o types.Object) (*CMap, error) {
+	sd, _, err := xRefTable.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, err
+	}
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	return ParseCMap(sd.Content)
+}
+
+func (dec *Decoder) parseToUnicode(xRefTable *model.XRefTable, d types.Dict) {
+	o, found := d.Find("ToUnicode")
+	if !found {
+		return
+	}
+	o, err := xRefTable.Dereference(o)
+	if err != nil || o == nil {
+		return
+	}
+	if _, ok := o.(types.StreamDict); !ok {
+		// Identity-H etc. carry no Unicode information.
+		return
+	}
+	cm, err := dec.cmapStream(xRefTable, o)
+	if err != nil {
+		// Ignore corrupt ToUnicode CMaps and fall back to the font encoding.
+		if log.DebugEnabled() {
+			log.Debug.Printf("font %s: %v\n", dec.Name, err)
+		}
+		return
+	}
+	dec.toUnicode = cm
+}
+
+func (dec *Decoder) parseFontDescriptor(xRefTable *model.XRefTable, d types.Dict) error {
+	o, found := d.Find("FontDescriptor")
+	if !found {
+		return nil
+	}
+
+	fd, err := xRefTable.DereferenceDict(o)
+	if err != nil || fd == nil {
+		return err
+	}
+
+	if o, found := fd.Find("Ascent"); found {
+		if f, err := xRefTable.DereferenceNumber(o); err == nil && f != 0 {
+			dec.Ascent = f * dec.scale
+		}
+	}
+
+	if o, found := fd.Find("Descent"); found {
+		if f, err := xRefTable.DereferenceNumber(o); err == nil && f != 0 {
+			dec.Descent = f * dec.scale
+		}
+	}
+
+	if o, found := fd.Find("MissingWidth"); found {
+		if f, err := xRefTable.DereferenceNumber(o); err == nil {
+			dec.missingW = f
+		}
+	}
+
+	return nil
+}
+
+func (dec *Decoder) parseSimpleWidths(xRefTable *model.XRefTable, d types.Dict) error {
+	if o, found := d.Find("FirstChar"); found {
+		i, err := xRefTable.DereferenceInteger(o)
+		if err != nil {
+			return err
+		}
+		if i != nil {
+			dec.firstChar = i.Value()
+		}
+	}
+
+	o, found := d.Find("Widths")
+	if !found {
+		return nil
+	}
+
+	a, err := xRefTable.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+
+	dec.widths = make([]float64, len(a))
+	for i, o := range a {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return err
+		}
+		dec.widths[i] = f
+	}
+
+	return nil
+}
+
+func (dec *Decoder) parseSimpleEncoding(xRefTable *model.XRefTable, d types.Dict) error {
+	base := "StandardEncoding"
+	switch dec.coreFont {
+	case "Symbol", "ZapfDingbats":
+		base = dec.coreFont
+	}
+
+	o, found := d.Find("Encoding")
+	if !found {
+		dec.names = baseEncoding(base)
+		return nil
+	}
+
+	o, err := xRefTable.Dereference(o)
+	if err != nil {
+		return err
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		dec.names = baseEncoding(o.Value())
+
+	case types.Dict:
+		if n := o.NameEntry("BaseEncoding"); n != nil {
+			base = *n
+		}
+		dec.names = baseEncoding(base)
+		a, err := xRefTable.DereferenceArray(o["Differences"])
+		if err != nil {
+			return err
+		}
+		c := 0
+		for _, o := range a {
+			o, err := xRefTable.Dereference(o)
+			if err != nil {
+				return err
+			}
+			switch o := o.(type) {
+			case types.Integer:
+				c = o.Value()
+			case types.Float:
+				c = int(o.Value())
+			case types.Name:
+				if c >= 0 && c < 256 {
+					dec.names[c] = o.Value()
+				}
+				c++
+			}
+		}
+
+	default:
+		dec.names = baseEncoding(base)
+	}
+
+	return nil
+}
+
+func (dec *Decoder) parseType3(xRefTable *model.XRefTable, d types.Dict) error {
+	a, err := xRefTable.DereferenceArray(d["FontMatrix"])
+	if err != nil {
+		return err
+	}
+	if len(a) == 6 {
+		f, err := xRefTable.DereferenceNumber(a[0])
+		if err != nil {
+			return err
+		}
+		dec.scale = f
+	}
+
+	// Derive ascent and descent from the glyph bounding box.
+	dec.Ascent, dec.Descent = 0.8, -0.2
+	if a, err := xRefTable.DereferenceArray(d["FontBBox"]); err == nil && len(a) == 4 {
+		lly, err1 := xRefTable.DereferenceNumber(a[1])
+		ury, err2 := xRefTable.DereferenceNumber(a[3])
+		if err1 == nil && err2 == nil && ury > lly {
+			dec.Ascent, dec.Descent = ury*dec.scale, lly*dec.scale
+		}
+	}
+
+	return nil
+}
+
+func newSimpleDecoder(xRefTable *model.XRefTable, d types.Dict, dec *Decoder) (*Decoder, error) {
+	if dec.Subtype == "Type3" {
+		if err := dec.parseType3(xRefTable, d); err != nil {
+			return nil, err
+		}
+	} else if _, ok := metrics.CoreFontMetrics[dec.Name]; ok {
+		dec.coreFont = dec.Name
+	} else if n, ok := coreFontAliases[dec.Name]; ok {
+		dec.coreFont = n
+	}
+
+	if dec.coreFont != "" {
+		bb := metrics.CoreFontMetrics[dec.coreFont].FBox
+		dec.Ascent, dec.Descent = bb.UR.Y*dec.scale, bb.LL.Y*dec.scale
+	}
+
+	if err := dec.parseSimpleWidths(xRefTable, d); err != nil {
+		return nil, err
+	}
+
+	if err := dec.parseSimpleEncoding(xRefTable, d); err != nil {
+		return nil, err
+	}
+
+	if err := dec.parseFontDescriptor(xRefTable, d); err != nil {
+		return nil, err
+	}
+
+	dec.parseToUnicode(xRefTable, d)
+
+	return dec, nil
+}
+
+func (dec *Decoder) parseCIDWidths(xRefTable *model.XRefTable, d types.Dict) error {
+	dw := 1000.
+	if o, found := d.Find("DW"); found {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return err
+		}
+		dw = f
+	}
+	dec.missingW = dw
+
+	a, err := xRefTable.DereferenceArray(d["W"])
+	if err != nil || a == nil {
+		return err
+	}
+
+	// c [w1 w2 ... wn] or cFirst cLast w
+	for i := 0; i < len(a); {
+		c, err := xRefTable.DereferenceNumber(a[i])
+		if err != nil {
+			return err
+		}
+		if i+1 >= len(a) {
+			break
+		}
+		o, err := xRefTable.Dereference(a[i+1])
+		if err != nil {
+			return err
+		}
+		if ws, ok := o.(types.Array); ok {
+			for j, o := range ws {
+				w, err := xRefTable.DereferenceNumber(o)
+				if err != nil {
+					return err
+				}
+				dec.cidWidths[uint32(c)+uint32(j)] = w
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(a) {
+			break
+		}
+		cLast, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return err
+		}
+		w, err := xRefTable.DereferenceNumber(a[i+2])
+		if err != nil {
+			return err
+		}
+		if cLast-c > 0xFFFF {
+			return ErrCorruptFontDict
+		}
+		for cid := uint32(c); cid <= uint32(cLast); cid++ {
+			dec.cidWidths[cid] = w
+		}
+		i += 3
+	}
+
+	return nil
+}
+
+func (dec *Decoder) parseCIDEncoding(xRefTable *model.XRefTable, d types.Dict) error {
+	o, err := xRefTable.Dereference(d["Encoding"])
+	if err != nil {
+		return err
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		n := o.Value()
+		dec.Vertical = strings.HasSuffix(n, "-V")
+		dec.encoding = IdentityCMap(dec.Vertical)
+		if n != "Identity-H" && n != "Identity-V" {
+			// We don't ship predefined CMaps.
+			// Unicode based ones may be decoded as UTF-16 though.
+			dec.encoding.Name = n
+			dec.ucs2 = strings.HasPrefix(n, "Uni") && (strings.Contains(n, "UCS2") || strings.Contains(n, "UTF16"))
+		}
+
+	case types.StreamDict:
+		cm, err := dec.cmapStream(xRefTable, o)
+		if err != nil {
+			return err
+		}
+		dec.encoding = cm
+		dec.Vertical = cm.Vertical
+
+	default:
+		dec.encoding = IdentityCMap(false)
+	}
+
+	return nil
+}
+
+func newCompositeDecoder(xRefTable *model.XRefTable, d types.Dict, dec *Decoder) (*Decoder, error) {
+	dec.composite = true
+	dec.cidWidths = map[uint32]float64{}
+
+	if err := dec.parseCIDEncoding(xRefTable, d); err != nil {
+		return nil, err
+	}
+
+	a, err := xRefTable.DereferenceArray(d["DescendantFonts"])
+	if err != nil {
+		return nil, err
+	}
+	if len(a) != 1 {
+		return nil, errors.Errorf("pdfcpu: font %s: corrupt \"DescendantFonts\"", dec.Name)
+	}
+
+	df, err := xRefTable.DereferenceDict(a[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dec.parseCIDWidths(xRefTable, df); err != nil {
+		return nil, err
+	}
+
+	if err := dec.parseFontDescriptor(xRefTable, df); err != nil {
+		return nil, err
+	}
+
+	dec.parseToUnicode(xRefTable, d)
+
+	return dec, nil
+}
+
+// NewDecoder returns a Decoder for the font dict d.
+func NewDecoder(xRefTable *model.XRefTable, d types.Dict) (*Decoder, error) {
+	dec := &Decoder{scale: 0.001, Ascent: 0.8, Descent: -0.2}
+
+	if n := d.NameEntry("BaseFont"); n != nil {
+		dec.Name = baseFontName(*n)
+	}
+
+	if n := d.Subtype(); n != nil {
+		dec.Subtype = *n
+	}
+
+	if dec.Subtype == "Type0" {
+		return newCompositeDecoder(xRefTable, d, dec)
+	}
+
+	return newSimpleDecoder(xRefTable, d, dec)
+}
+
+func (dec *Decoder) simpleWidth(c uint32) float64 {
+	i := int(c) - dec.firstChar
+	if i >= 0 && i < len(dec.widths) {
+		return dec.widths[i]
+	}
+	if dec.coreFont != "" && len(dec.widths) == 0 {
+		if w, ok := metrics.CoreFontMetrics[dec.coreFont].W[dec.names[c&0xFF]]; ok {
+			return float64(w)
+		}
+	}
+	return dec.missingW
+}
+
+func (dec *Decoder) simpleText(c uint32) string {
+	if dec.toUnicode != nil {
+		if s, ok := dec.toUnicode.Unicode(c); ok {
+			return s
+		}
+	}
+	if n := dec.names[c&0xFF]; n != "" {
+		if s, ok := GlyphNameToString(n); ok {
+			return s
+		}
+	}
+	if c >= 0x20 && dec.Subtype == "TrueType" {
+		// Symbolic TrueType fonts w/o encoding.
+		return string(rune(c))
+	}
+	return ""
+}
+
+func (dec *Decoder) compositeText(c uint32, n int) string {
+	if dec.toUnicode != nil {
+		if s, ok := dec.toUnicode.Unicode(c); ok {
+			return s
+		}
+	}
+	if dec.ucs2 && n == 2 {
+		return decodeUTF16BE([]byte{byte(c >> 8), byte(c)})
+	}
+	return ""
+}
+
+// Decode splits a string into glyphs according to the font encoding.
+func (dec *Decoder) Decode(bb []byte) []Glyph {
+	var gg []Glyph
+
+	for len(bb) > 0 {
+		if !dec.composite {
+			c := uint32(bb[0])
+			gg = append(gg, Glyph{Code: c, Len: 1, Text: dec.simpleText(c), Width: dec.simpleWidth(c) * dec.scale, Name: dec.names[c]})
+			bb = bb[1:]
+			continue
+		}
+
+		c, n := dec.encoding.NextCode(bb)
+		cid := dec.encoding.CID(c)
+		w, ok := dec.cidWidths[cid]
+		if !ok {
+			w = dec.missingW
+		}
+		gg = append(gg, Glyph{Code: c, Len: n, Text: dec.compositeText(c, n), Width: w * dec.scale, CID: cid})
+		bb = bb[n:]
+	}
+
+	return gg
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// UserFontName returns the name of the installed user font matching baseFont.
+func UserFontName(baseFont string) (string, bool) {
+	s := baseFontName(baseFont)
+	for _, fontName := range []string{s, strings.ReplaceAll(s, ",", "-")} {
+		if font.IsUserFont(fontName) {
+			return fontName, true
+		}
+	}
+	return "", false
+}
+
+func embeddedFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, baseFont string, fontFile types.IndirectRef) (*types.IndirectRef, error) {
+	d := types.Dict(
+		map[string]types.Object{
+			"Type":        types.Name("FontDescriptor"),
+			"FontName":    types.Name(baseFont),
+			"Flags":       types.Integer(ttfFontDescriptorFlags(ttf)),
+			"FontBBox":    types.NewNumberArray(ttf.LLx, ttf.LLy, ttf.URx, ttf.URy),
+			"ItalicAngle": types.Float(ttf.ItalicAngle),
+			"Ascent":      types.Integer(ttf.Ascent),
+			"Descent":     types.Integer(ttf.Descent),
+			"CapHeight":   types.Integer(ttf.CapHeight),
+			"StemV":       types.Integer(70), // Irrelevant for embedded files.
+		},
+	)
+	d[fontFileKey(ttf)] = fontFile
+	return xRefTable.IndRefForNewObject(d)
+}
+
+func subsetFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName string, usedGIDs map[uint16]bool, cid bool) (*types.IndirectRef, error) {
+	bb, err := font.Subset(fontName, usedGIDs)
+	if err != nil {
+		return nil, err
+	}
+	return fontFileStreamIndRef(xRefTable, ttf, bb, cid)
+}
+
+func simpleFontEncoding(xRefTable *model.XRefTable, d types.Dict, fontName string, codes map[uint32]bool) (string, error) {
+	enc := "StandardEncoding"
+	if o, found := d.Find("Encoding"); found {
+		o, err := xRefTable.Dereference(o)
+		if err != nil {
+			return "", err
+		}
+		n, ok := o.(types.Name)
+		if !ok {
+			return "", errors.Errorf("pdfcpu: font %s: unsupported encoding with differences", fontName)
+		}
+		enc = n.Value()
+	}
+
+	if enc == "WinAnsiEncoding" || enc == "MacRomanEncoding" {
+		return enc, nil
+	}
+
+	// Non symbolic TrueType fonts need to use either WinAnsiEncoding or MacRomanEncoding.
+	names, winAnsi := baseEncoding(enc), baseEncoding("WinAnsiEncoding")
+	for c := range codes {
+		if c > 255 || names[c] != winAnsi[c] {
+			return "", errors.Errorf("pdfcpu: font %s: unable to map %s to WinAnsiEncoding", fontName, enc)
+		}
+	}
+
+	return "WinAnsiEncoding", nil
+}
+
+func embedSimpleFont(xRefTable *model.XRefTable, d types.Dict, ttf font.TTFLight, fontName string, codes map[uint32]bool) error {
+	subtype := "TrueType"
+	if ttf.Format != font.FormatTrueType {
+		if err := simpleCFF(fontName, ttf); err != nil {
+			return err
+		}
+		subtype = "Type1"
+	}
+
+	enc, err := simpleFontEncoding(xRefTable, d, fontName, codes)
+	if err != nil {
+		return err
+	}
+	names := baseEncoding(enc)
+
+	gid := func(c int) (uint16, bool) {
+		r, ok := GlyphNameToRune(names[c])
+		if !ok {
+			return 0, false
+		}
+		g, ok := ttf.Chars[uint32(r)]
+		return g, ok
+	}
+
+	usedGIDs := map[uint16]bool{}
+	first, last := 255, 0
+	for c := range codes {
+		first, last = min(first, int(c)), max(last, int(c))
+		if g, ok := gid(int(c)); ok {
+			usedGIDs[g] = true
+		}
+	}
+	if first > last {
+		first, last = 32, 32
+	}
+
+	w := types.Array{}
+	for c := first; c <= last; c++ {
+		g, _ := gid(c)
+		w = append(w, types.Integer(ttf.GlyphWidths[g]))
+	}
+
+	fontFile, err := subsetFontFile(xRefTable, ttf, fontName, usedGIDs, false)
+	if err != nil {
+		return err
+	}
+
+	baseFont := subFontPrefix() + "+" + fontName
+
+	fd, err := embeddedFontDescriptor(xRefTable, ttf, baseFont, *fontFile)
+	if err != nil {
+		return err
+	}
+
+	d["Subtype"] = types.Name(subtype)
+	d["BaseFont"] = types.Name(baseFont)
+	d["Encoding"] = types.Name(enc)
+	d["FirstChar"] = types.Integer(first)
+	d["LastChar"] = types.Integer(last)
+	d["Widths"] = w
+	d["FontDescriptor"] = *fd
+
+	return nil
+}
+
+func embedCIDFont(xRefTable *model.XRefTable, d types.Dict, ttf font.TTFLight, fontName string, codes map[uint32]bool) error {
+	if enc := d.NameEntry("Encoding"); enc == nil || (*enc != "Identity-H" && *enc != "Identity-V") {
+		return errors.Errorf("pdfcpu: font %s: unsupported CMap", fontName)
+	}
+
+	a, err := xRefTable.DereferenceArray(d["DescendantFonts"])
+	if err != nil || len(a) != 1 {
+		return errors.Errorf("pdfcpu: font %s: corrupt DescendantFonts", fontName)
+	}
+
+	df, err := xRefTable.DereferenceDict(a[0])
+	if err != nil || df == nil {
+		return errors.Errorf("pdfcpu: font %s: corrupt DescendantFonts", fontName)
+	}
+
+	if st := df.Subtype(); st == nil || *st != cidFontSubtype(ttf) {
+		if ttf.Format == font.FormatTrueType {
+			return errors.Errorf("pdfcpu: font %s: TrueType font program not applicable for CIDFontType0", fontName)
+		}
+		return errors.Errorf("pdfcpu: font %s: CFF font program not applicable for CIDFontType2", fontName)
+	}
+
+	if o, found := df.Find("CIDToGIDMap"); found {
+		if n, ok := o.(types.Name); !ok || n.Value() != "Identity" {
+			return errors.Errorf("pdfcpu: font %s: unsupported CIDToGIDMap", fontName)
+		}
+	}
+
+	// Identity-H and Identity-V map codes to CIDs, CIDToGIDMap maps CIDs to GIDs.
+	// For CIDFontType0 CIDs are glyph indices of the installed CFF font program.
+	usedGIDs := map[uint16]bool{}
+	for c := range codes {
+		if c < uint32(ttf.GlyphCount) {
+			usedGIDs[uint16(c)] = true
+		}
+	}
+
+	fontFile, err := subsetFontFile(xRefTable, ttf, fontName, usedGIDs, true)
+	if err != nil {
+		return err
+	}
+
+	baseFont := subFontPrefix() + "+" + fontName
+
+	fd, err := embeddedFontDescriptor(xRefTable, ttf, baseFont, *fontFile)
+	if err != nil {
+		return err
+	}
+
+	d["BaseFont"] = types.Name(baseFont)
+	df["BaseFont"] = types.Name(baseFont)
+	df["FontDescriptor"] = *fd
+	if ttf.Format == font.FormatTrueType {
+		df["CIDToGIDMap"] = types.Name("Identity")
+	}
+
+	return nil
+}
+
+// EmbedUserFont embeds a subset of the installed user font fontName into the font dict d.
+// The subset covers all glyphs for the character codes in codes.
+func EmbedUserFont(xRefTable *model.XRefTable, d types.Dict, fontName string, codes map[uint32]bool) error {
+	font.UserFontMetricsLock.RLock()
+	ttf, ok := font.UserFontMetrics[fontName]
+	font.UserFontMetricsLock.RUnlock()
+	if !ok {
+		return errors.Errorf("pdfcpu: font %s not available", fontName)
+	}
+
+	st := d.Subtype()
+	if st == nil {
+		return errors.Errorf("pdfcpu: font %s: missing Subtype", fontName)
+	}
+
+	switch *st {
+	case "Type1", "MMType1", "TrueType":
+		return embedSimpleFont(xRefTable, d, ttf, fontName, codes)
+	case "Type0":
+		return embedCIDFont(xRefTable, d, ttf, fontName, codes)
+	}
+
+	return errors.Errorf("pdfcpu: font %s: unsupported font type %s", fontName, *st)
+}
@@ -82,6 +82,9 @@ func fontDescriptorIndRefs(fd types.Dict, lang string, font *model.FontResource)
 	}
 
 	font.FontFile = fd.IndirectRefEntry("FontFile2")
+	if font.FontFile == nil {
+		font.FontFile = fd.IndirectRefEntry("FontFile3")
+	}
 	if font.FontFile == nil {
 		return ErrCorruptFontDict
 	}
@@ -148,12 +151,49 @@ func flateEncodedStreamIndRef(xRefTable *model.XRefTable, data []byte) (*types.I
 	return xRefTable.IndRefForNewObject(*sd)
 }
 
-func ttfFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName string) (*types.IndirectRef, error) {
+// fontFileKey returns the font descriptor entry for the embedded font program of ttf.
+func fontFileKey(ttf font.TTFLight) string {
+	if ttf.Format == font.FormatTrueType {
+		return "FontFile2"
+	}
+	return "FontFile3"
+}
+
+// cidFontSubtype returns the Subtype of the CIDFont dict for ttf.
+func cidFontSubtype(ttf font.TTFLight) string {
+	if ttf.Format == font.FormatTrueType {
+		return "CIDFontType2"
+	}
+	return "CIDFontType0"
+}
+
+func fontFileStreamIndRef(xRefTable *model.XRefTable, ttf font.TTFLight, bb []byte, cid bool) (*types.IndirectRef, error) {
+	if ttf.Format == font.FormatTrueType {
+		return flateEncodedStreamIndRef(xRefTable, bb)
+	}
+
+	// FontFile3 streams carry the font program format as Subtype.
+	subtype := "Type1C"
+	if ttf.Format == font.FormatOpenType {
+		subtype = "OpenType"
+	} else if cid {
+		subtype = "CIDFontType0C"
+	}
+
+	sd, _ := xRefTable.NewStreamDictForBuf(bb)
+	sd.InsertName("Subtype", subtype)
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+	return xRefTable.IndRefForNewObject(*sd)
+}
+
+func ttfFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName string, cid bool) (*types.IndirectRef, error) {
 	bb, err := font.Read(fontName)
 	if err != nil {
 		return nil, err
 	}
-	return flateEncodedStreamIndRef(xRefTable, bb)
+	return fontFileStreamIndRef(xRefTable, ttf, bb, cid)
 }
 
 func ttfSubFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName string, indRef *types.IndirectRef) (*types.IndirectRef, error) {
@@ -162,12 +202,14 @@ func ttfSubFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName stri
 		return nil, err
 	}
 	if indRef == nil {
-		return flateEncodedStreamIndRef(xRefTable, bb)
+		return fontFileStreamIndRef(xRefTable, ttf, bb, true)
 	}
 	entry, _ := xRefTable.FindTableEntryForIndRef(indRef)
 	sd, _ := entry.Object.(types.StreamDict)
 	sd.Content = bb
-	sd.InsertInt("Length1", len(bb))
+	if ttf.Format == font.FormatTrueType {
+		sd.InsertInt("Length1", len(bb))
+	}
 	if err := sd.Encode(); err != nil {
 		return nil, err
 	}
@@ -292,12 +334,12 @@ func ttfFontDescriptorFlags(ttf font.TTFLight) uint32 {
 	return flags
 }
 
-// CIDFontFile returns a TrueType font file or subfont file for fontName.
+// CIDFontFile returns a font file or subfont file for fontName.
 func CIDFontFile(xRefTable *model.XRefTable, ttf font.TTFLight, fontName string, subFont bool) (*types.IndirectRef, error) {
 	if subFont {
 		return ttfSubFontFile(xRefTable, ttf, fontName, nil)
 	}
-	return ttfFontFile(xRefTable, ttf, fontName)
+	return ttfFontFile(xRefTable, ttf, fontName, true)
 }
 
 // CIDFontDescriptor returns a font descriptor describing the CIDFont’s default metrics other than its glyph widths.
@@ -326,7 +368,7 @@ func CIDFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 		if err != nil {
 			return nil, err
 		}
-		d["FontFile2"] = *fontFile
+		d[fontFileKey(ttf)] = *fontFile
 	}
 
 	if embed {
@@ -353,7 +395,7 @@ func CIDFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 
 // FontDescriptor returns a TrueType font descriptor describing font’s default metrics other than its glyph widths.
 func NewFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, fontLang string) (*types.IndirectRef, error) {
-	fontFile, err := ttfFontFile(xRefTable, ttf, fontName)
+	fontFile, err := ttfFontFile(xRefTable, ttf, fontName, false)
 	if err != nil {
 		return nil, err
 	}
@@ -366,7 +408,6 @@ func NewFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 			"Flags":       types.Integer(ttfFontDescriptorFlags(ttf)),
 			"FontBBox":    types.NewNumberArray(ttf.LLx, ttf.LLy, ttf.URx, ttf.URy),
 			"FontFamily":  types.StringLiteral(fontName),
-			"FontFile2":   *fontFile,
 			"FontName":    types.Name(fontName),
 			"ItalicAngle": types.Float(ttf.ItalicAngle),
 			"StemV":       types.Integer(70), // Irrelevant for embedded files.
@@ -374,6 +415,8 @@ func NewFontDescriptor(xRefTable *model.XRefTable, ttf font.TTFLight, fontName,
 		},
 	)
 
+	d[fontFileKey(ttf)] = *fontFile
+
 	if fontLang != "" {
 		d["Lang"] = types.Name(fontLang)
 	}
@@ -552,7 +595,7 @@ func Widths(xRefTable *model.XRefTable, ttf font.TTFLight, first, last int) (*ty
 	return xRefTable.IndRefForNewObject(a)
 }
 
-func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, subFont bool) {
+func bf(b *bytes.Buffer, ttf font.TTFLight, fontName string, usedGIDs map[uint16]bool, subFont bool) {
 	var gids []int
 	if subFont {
 		gids = make([]int, 0, len(usedGIDs))
@@ -575,8 +618,15 @@ func bf(b *bytes.Buffer, ttf font.TTFLight, usedGIDs map[uint16]bool, subFont bo
 	for i := 0; i < l; i++ {
 		gid := gids[i]
 		fmt.Fprintf(b, "<%04X> <", gid)
-		u := ttf.ToUnicode[uint16(gid)]
-		s := utf16.Encode([]rune{rune(u)})
+		u, ok := ttf.ToUnicode[uint16(gid)]
+		rr := []rune{rune(u)}
+		if !ok {
+			// Glyphs resulting from shaping like ligatures.
+			if rr1 := font.GlyphRunes(fontName, uint16(gid)); len(rr1) > 0 {
+				rr = rr1
+			}
+		}
+		s := utf16.Encode(rr)
 		for _, v := range s {
 			fmt.Fprintf(b, "%04X", v)
 		}
@@ -637,7 +687,7 @@ end`
 	if usedGIDs == nil {
 		usedGIDs = map[uint16]bool{}
 	}
-	bf(&b, ttf, usedGIDs, subFont)
+	bf(&b, ttf, fontName, usedGIDs, subFont)
 	b.WriteString(epi)
 
 	bb := b.Bytes()
@@ -807,7 +857,7 @@ func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFo
 	d := types.Dict(
 		map[string]types.Object{
 			"Type":     types.Name("Font"),
-			"Subtype":  types.Name("CIDFontType2"),
+			"Subtype":  types.Name(cidFontSubtype(ttf)),
 			"BaseFont": types.Name(baseFontName),
 			"CIDSystemInfo": types.Dict(
 				map[string]types.Object{
@@ -847,7 +897,7 @@ func CIDFontDict(xRefTable *model.XRefTable, ttf font.TTFLight, fontName, baseFo
 	// maps CIDs to the glyph indices for the appropriate glyph descriptions in that font program.
 	// if stream: the glyph index for a particular CID value c shall be a 2-byte value stored in bytes 2 × c and 2 × c + 1,
 	// where the first byte shall be the high-order byte.))
-	if ordering == "Identity" {
+	if ordering == "Identity" && ttf.Format == font.FormatTrueType {
 		d["CIDToGIDMap"] = types.Name("Identity")
 	}
 
@@ -940,6 +990,15 @@ func trueTypeFontDict(xRefTable *model.XRefTable, fontName, fontLang string) (*t
 		return nil, errors.Errorf("pdfcpu: font %s not available", fontName)
 	}
 
+	subtype := "TrueType"
+	if ttf.Format != font.FormatTrueType {
+		// Simple fonts may only use name-keyed CFF font programs.
+		if err := simpleCFF(fontName, ttf); err != nil {
+			return nil, err
+		}
+		subtype = "Type1"
+	}
+
 	first, last := 0, 255
 	wIndRef, err := Widths(xRefTable, ttf, first, last)
 	if err != nil {
@@ -953,7 +1012,7 @@ func trueTypeFontDict(xRefTable *model.XRefTable, fontName, fontLang string) (*t
 
 	d := types.NewDict()
 	d.InsertName("Type", "Font")
-	d.InsertName("Subtype", "TrueType")
+	d.InsertName("Subtype", subtype)
 	d.InsertName("BaseFont", fontName)
 	d.InsertName("Name", fontName)
 	d.InsertName("Encoding", "WinAnsiEncoding")
@@ -965,6 +1024,24 @@ func trueTypeFontDict(xRefTable *model.XRefTable, fontName, fontLang string) (*t
 	return xRefTable.IndRefForNewObject(d)
 }
 
+func simpleCFF(fontName string, ttf font.TTFLight) error {
+	if ttf.Format == font.FormatOpenType {
+		return errors.Errorf("pdfcpu: font %s: CFF2 based fonts are unsupported for simple fonts", fontName)
+	}
+	bb, err := font.Read(fontName)
+	if err != nil {
+		return err
+	}
+	cff, err := ParseCFF(bb)
+	if err != nil {
+		return err
+	}
+	if cff.IsCID {
+		return errors.Errorf("pdfcpu: font %s: CID-keyed fonts are unsupported for simple fonts", fontName)
+	}
+	return nil
+}
+
 // CJK returns true if script and lang imply a CJK font.
 func CJK(script, lang string) bool {
 	if script != "" {
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/internal/corefont/metrics"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// glyphNames maps glyph names not covered by WinAnsiEncoding to Unicode, see the Adobe Glyph List.
+var glyphNames = map[string]rune{
+	"Abreve": 0x0102, "abreve": 0x0103, "Amacron": 0x0100, "amacron": 0x0101, "Aogonek": 0x0104, "aogonek": 0x0105,
+	"Cacute": 0x0106, "cacute": 0x0107, "Ccaron": 0x010C, "ccaron": 0x010D, "Dcaron": 0x010E, "dcaron": 0x010F,
+	"Dcroat": 0x0110, "dcroat": 0x0111, "Ecaron": 0x011A, "ecaron": 0x011B, "Edotaccent": 0x0116, "edotaccent": 0x0117,
+	"Emacron": 0x0112, "emacron": 0x0113, "Eogonek": 0x0118, "eogonek": 0x0119, "Gbreve": 0x011E, "gbreve": 0x011F,
+	"Gcommaaccent": 0x0122, "gcommaaccent": 0x0123, "Idotaccent": 0x0130, "dotlessi": 0x0131, "Imacron": 0x012A,
+	"imacron": 0x012B, "Iogonek": 0x012E, "iogonek": 0x012F, "Kcommaaccent": 0x0136, "kcommaaccent": 0x0137,
+	"Lacute": 0x0139, "lacute": 0x013A, "Lcommaaccent": 0x013B, "lcommaaccent": 0x013C, "Lcaron": 0x013D, "lcaron": 0x013E,
+	"Lslash": 0x0141, "lslash": 0x0142, "Nacute": 0x0143, "nacute": 0x0144, "Ncommaaccent": 0x0145, "ncommaaccent": 0x0146,
+	"Ncaron": 0x0147, "ncaron": 0x0148, "Omacron": 0x014C, "omacron": 0x014D, "Ohungarumlaut": 0x0150, "ohungarumlaut": 0x0151,
+	"Racute": 0x0154, "racute": 0x0155, "Rcommaaccent": 0x0156, "rcommaaccent": 0x0157, "Rcaron": 0x0158, "rcaron": 0x0159,
+	"Sacute": 0x015A, "sacute": 0x015B, "Scedilla": 0x015E, "scedilla": 0x015F, "Scommaaccent": 0x0218, "scommaaccent": 0x0219,
+	"Tcommaaccent": 0x0162, "tcommaaccent": 0x0163, "Tcaron": 0x0164, "tcaron": 0x0165, "Umacron": 0x016A, "umacron": 0x016B,
+	"Uring": 0x016E, "uring": 0x016F, "Uhungarumlaut": 0x0170, "uhungarumlaut": 0x0171, "Uogonek": 0x0172, "uogonek": 0x0173,
+	"Zacute": 0x0179, "zacute": 0x017A, "Zdotaccent": 0x017B, "zdotaccent": 0x017C,
+
+	"breve": 0x02D8, "caron": 0x02C7, "dotaccent": 0x02D9, "hungarumlaut": 0x02DD, "ogonek": 0x02DB, "ring": 0x02DA,
+	"commaaccent": 0x0326, "ff": 0xFB00, "fi": 0xFB01, "fl": 0xFB02, "ffi": 0xFB03, "ffl": 0xFB04, "fraction": 0x2044,
+	"minus": 0x2212, "Delta": 0x2206, "greaterequal": 0x2265, "lessequal": 0x2264, "notequal": 0x2260, "lozenge": 0x25CA,
+	"partialdiff": 0x2202, "radical": 0x221A, "summation": 0x2211, "nbspace": 0x00A0, "sfthyphen": 0x00AD,
+
+	"Alpha": 0x0391, "Beta": 0x0392, "Gamma": 0x0393, "Epsilon": 0x0395, "Zeta": 0x0396, "Eta": 0x0397, "Theta": 0x0398,
+	"Iota": 0x0399, "Kappa": 0x039A, "Lambda": 0x039B, "Mu": 0x039C, "Nu": 0x039D, "Xi": 0x039E, "Omicron": 0x039F,
+	"Pi": 0x03A0, "Rho": 0x03A1, "Sigma": 0x03A3, "Tau": 0x03A4, "Upsilon": 0x03A5, "Phi": 0x03A6, "Chi": 0x03A7,
+	"Psi": 0x03A8, "Omega": 0x03A9, "Upsilon1": 0x03D2,
+	"alpha": 0x03B1, "beta": 0x03B2, "gamma": 0x03B3, "delta": 0x03B4, "epsilon": 0x03B5, "zeta": 0x03B6, "eta": 0x03B7,
+	"theta": 0x03B8, "iota": 0x03B9, "kappa": 0x03BA, "lambda": 0x03BB, "nu": 0x03BD, "xi": 0x03BE, "omicron": 0x03BF,
+	"pi": 0x03C0, "rho": 0x03C1, "sigma1": 0x03C2, "sigma": 0x03C3, "tau": 0x03C4, "upsilon": 0x03C5, "phi": 0x03C6,
+	"chi": 0x03C7, "psi": 0x03C8, "omega": 0x03C9, "theta1": 0x03D1, "phi1": 0x03D5, "omega1": 0x03D6,
+
+	"aleph": 0x2135, "angle": 0x2220, "angleleft": 0x2329, "angleright": 0x232A, "approxequal": 0x2248,
+	"arrowboth": 0x2194, "arrowdblboth": 0x21D4, "arrowdbldown": 0x21D3, "arrowdblleft": 0x21D0, "arrowdblright": 0x21D2,
+	"arrowdblup": 0x21D1, "arrowdown": 0x2193, "arrowleft": 0x2190, "arrowright": 0x2192, "arrowup": 0x2191,
+	"asteriskmath": 0x2217, "carriagereturn": 0x21B5, "circlemultiply": 0x2297, "circleplus": 0x2295, "club": 0x2663,
+	"congruent": 0x2245, "diamond": 0x2666, "dotmath": 0x22C5, "element": 0x2208, "emptyset": 0x2205,
+	"equivalence": 0x2261, "existential": 0x2203, "gradient": 0x2207, "heart": 0x2665, "infinity": 0x221E,
+	"integral": 0x222B, "intersection": 0x2229, "logicaland": 0x2227, "logicalor": 0x2228, "minute": 0x2032,
+	"notelement": 0x2209, "notsubset": 0x2284, "perpendicular": 0x22A5, "product": 0x220F, "propersubset": 0x2282,
+	"propersuperset": 0x2283, "proportional": 0x221D, "reflexsubset": 0x2286, "reflexsuperset": 0x2287, "second": 0x2033,
+	"similar": 0x223C, "spade": 0x2660, "suchthat": 0x220B, "therefore": 0x2234, "union": 0x222A, "universal": 0x2200,
+	"weierstrass": 0x2118, "Ifraktur": 0x2111, "Rfraktur": 0x211C, "copyrightsans": 0x00A9, "copyrightserif": 0x00A9,
+	"registersans": 0x00AE, "registerserif": 0x00AE, "trademarksans": 0x2122, "trademarkserif": 0x2122,
+}
+
+// runeNames is the inverse of glyphNames.
+var runeNames map[rune]string
+
+// standardEncoding maps the codes of StandardEncoding outside of ASCII to glyph names, see Annex D.2.
+var standardEncoding = map[byte]string{
+	39: "quoteright", 96: "quoteleft",
+	161: "exclamdown", 162: "cent", 163: "sterling", 164: "fraction", 165: "yen", 166: "florin", 167: "section",
+	168: "currency", 169: "quotesingle", 170: "quotedblleft", 171: "guillemotleft", 172: "guilsinglleft",
+	173: "guilsinglright", 174: "fi", 175: "fl", 177: "endash", 178: "dagger", 179: "daggerdbl", 180: "periodcentered",
+	182: "paragraph", 183: "bullet", 184: "quotesinglbase", 185: "quotedblbase", 186: "quotedblright",
+	187: "guillemotright", 188: "ellipsis", 189: "perthousand", 191: "questiondown", 193: "grave", 194: "acute",
+	195: "circumflex", 196: "tilde", 197: "macron", 198: "breve", 199: "dotaccent", 200: "dieresis", 202: "ring",
+	203: "cedilla", 205: "hungarumlaut", 206: "ogonek", 207: "caron", 208: "emdash", 225: "AE", 227: "ordfeminine",
+	232: "Lslash", 233: "Oslash", 234: "OE", 235: "ordmasculine", 241: "ae", 245: "dotlessi", 248: "lslash",
+	249: "oslash", 250: "oe", 251: "germandbls",
+}
+
+func init() {
+	// Complete glyphNames using WinAnsiEncoding.
+	codes := make([]int, 0, len(metrics.WinAnsiGlyphMap))
+	for c := range metrics.WinAnsiGlyphMap {
+		codes = append(codes, c)
+	}
+	sort.Ints(codes)
+	for _, c := range codes {
+		n := metrics.WinAnsiGlyphMap[c]
+		if _, ok := glyphNames[n]; ok {
+			continue
+		}
+		if r := charmap.Windows1252.DecodeByte(byte(c)); r != 0xFFFD {
+			glyphNames[n] = r
+		}
+	}
+
+	runeNames = map[rune]string{}
+	for n, r := range glyphNames {
+		if n1, ok := runeNames[r]; !ok || n < n1 {
+			runeNames[r] = n
+		}
+	}
+}
+
+func glyphNameComponentToRune(s string) (rune, bool) {
+	if r, ok := glyphNames[s]; ok {
+		return r, true
+	}
+
+	// uniXXXX
+	if strings.HasPrefix(s, "uni") && len(s) == 7 {
+		if i, err := strconv.ParseUint(s[3:], 16, 32); err == nil {
+			return rune(i), true
+		}
+	}
+
+	// uXXXX to uXXXXXX
+	if strings.HasPrefix(s, "u") && len(s) >= 5 && len(s) <= 7 {
+		if i, err := strconv.ParseUint(s[1:], 16, 32); err == nil {
+			return rune(i), true
+		}
+	}
+
+	return 0, false
+}
+
+// GlyphNameToRune returns the Unicode character for a glyph name.
+func GlyphNameToRune(name string) (rune, bool) {
+	// Drop any suffix like in "a.sc".
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+	return glyphNameComponentToRune(name)
+}
+
+// GlyphNameToString returns the Unicode text for a glyph name including ligatures like "f_f_i".
+func GlyphNameToString(name string) (string, bool) {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i]
+	}
+
+	if !strings.Contains(name, "_") {
+		r, ok := glyphNameComponentToRune(name)
+		if !ok {
+			return "", false
+		}
+		return string(r), true
+	}
+
+	var sb strings.Builder
+	for _, s := range strings.Split(name, "_") {
+		r, ok := glyphNameComponentToRune(s)
+		if !ok {
+			return "", false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), true
+}
+
+// baseEncoding returns the glyph names for a predefined simple font encoding, see Annex D.
+func baseEncoding(name string) [256]string {
+	var enc [256]string
+
+	switch name {
+
+	case "WinAnsiEncoding":
+		for c, n := range metrics.WinAnsiGlyphMap {
+			if c >= 0 && c < 256 {
+				enc[c] = n
+			}
+		}
+
+	case "MacRomanEncoding":
+		for c := 32; c < 256; c++ {
+			r := charmap.Macintosh.DecodeByte(byte(c))
+			enc[c] = runeGlyphName(r)
+		}
+
+	case "Symbol":
+		for c, n := range metrics.SymbolGlyphMap {
+			if c >= 0 && c < 256 {
+				enc[c] = n
+			}
+		}
+
+	case "ZapfDingbats":
+		for c, n := range metrics.ZapfDingbatsGlyphMap {
+			if c >= 0 && c < 256 {
+				enc[c] = n
+			}
+		}
+
+	default:
+		// StandardEncoding
+		for c := 32; c < 127; c++ {
+			enc[c] = metrics.WinAnsiGlyphMap[c]
+		}
+		for c, n := range standardEncoding {
+			enc[c] = n
+		}
+	}
+
+	return enc
+}
+
+// runeGlyphName returns a glyph name for r.
+func runeGlyphName(r rune) string {
+	if n, ok := runeNames[r]; ok {
+		return n
+	}
+	return fmt.Sprintf("uni%04X", r)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// SegmentOp is the drawing operation of an outline segment.
+type SegmentOp int
+
+// Outline segment operations.
+const (
+	SegmentMoveTo SegmentOp = iota // starts a new contour at Args[0]
+	SegmentLineTo                  // straight line to Args[0]
+	SegmentQuadTo                  // quadratic Bézier curve via Args[0] to Args[1]
+	SegmentCubeTo                  // cubic Bézier curve via Args[0], Args[1] to Args[2]
+)
+
+// Segment is a segment of a glyph outline in glyph space.
+// Contours are implicitly closed.
+type Segment struct {
+	Op   SegmentOp
+	Args [3]types.Point
+}
+
+// outlineBuilder collects segments and keeps track of the current point.
+type outlineBuilder struct {
+	segs   []Segment
+	x, y   float64
+	dx, dy float64 // offset applied to all points, used for accented characters
+	open   bool
+}
+
+func (b *outlineBuilder) pt(x, y float64) types.Point {
+	return types.Point{X: x + b.dx, Y: y + b.dy}
+}
+
+func (b *outlineBuilder) moveTo(x, y float64) {
+	b.x, b.y = x, y
+	b.segs = append(b.segs, Segment{Op: SegmentMoveTo, Args: [3]types.Point{b.pt(x, y)}})
+	b.open = true
+}
+
+func (b *outlineBuilder) ensureOpen() {
+	if !b.open {
+		b.moveTo(b.x, b.y)
+	}
+}
+
+func (b *outlineBuilder) lineTo(x, y float64) {
+	b.ensureOpen()
+	b.x, b.y = x, y
+	b.segs = append(b.segs, Segment{Op: SegmentLineTo, Args: [3]types.Point{b.pt(x, y)}})
+}
+
+func (b *outlineBuilder) quadTo(x1, y1, x, y float64) {
+	b.ensureOpen()
+	b.x, b.y = x, y
+	b.segs = append(b.segs, Segment{Op: SegmentQuadTo, Args: [3]types.Point{b.pt(x1, y1), b.pt(x, y)}})
+}
+
+func (b *outlineBuilder) cubeTo(x1, y1, x2, y2, x, y float64) {
+	b.ensureOpen()
+	b.x, b.y = x, y
+	b.segs = append(b.segs, Segment{Op: SegmentCubeTo, Args: [3]types.Point{b.pt(x1, y1), b.pt(x2, y2), b.pt(x, y)}})
+}
+
+func (b *outlineBuilder) closePath() {
+	b.open = false
+}
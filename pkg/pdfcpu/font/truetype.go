@@ -0,0 +1,584 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+var errCorruptTrueType = errors.New("pdfcpu: corrupt TrueType font program")
+
+// TrueType represents a TrueType font program providing glyph outlines.
+type TrueType struct {
+	UnitsPerEm int
+	CFF        *CFF // outlines of OpenType fonts with CFF data
+	numGlyphs  int
+	locs       []uint32
+	glyf       []byte
+	cmaps      map[uint32][]byte // cmap subtables by platformID<<16 | encodingID
+	names      map[string]uint16 // glyph indices by glyph name from the post table
+}
+
+func ttU16(bb []byte, off int) int {
+	if off < 0 || off+2 > len(bb) {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(bb[off:]))
+}
+
+func ttI16(bb []byte, off int) int {
+	return int(int16(ttU16(bb, off)))
+}
+
+func ttU32(bb []byte, off int) uint32 {
+	if off < 0 || off+4 > len(bb) {
+		return 0
+	}
+	return binary.BigEndian.Uint32(bb[off:])
+}
+
+func ttTables(bb []byte) (map[string][]byte, error) {
+	off := 0
+	if len(bb) >= 12 && string(bb[:4]) == "ttcf" {
+		// Use the first font of a collection.
+		off = int(ttU32(bb, 12))
+	}
+	if off+12 > len(bb) {
+		return nil, errCorruptTrueType
+	}
+
+	n := ttU16(bb, off+4)
+	m := map[string][]byte{}
+	for i := 0; i < n; i++ {
+		rec := off + 12 + 16*i
+		if rec+16 > len(bb) {
+			return nil, errCorruptTrueType
+		}
+		tag := string(bb[rec : rec+4])
+		o, l := int(ttU32(bb, rec+8)), int(ttU32(bb, rec+12))
+		if o < 0 || o > len(bb) {
+			continue
+		}
+		if o+l > len(bb) || o+l < o {
+			// Tolerate truncated tables.
+			l = len(bb) - o
+		}
+		m[tag] = bb[o : o+l]
+	}
+
+	return m, nil
+}
+
+// ParseTrueType parses a TrueType or OpenType font program.
+func ParseTrueType(bb []byte) (*TrueType, error) {
+	tables, err := ttTables(bb)
+	if err != nil {
+		return nil, err
+	}
+
+	tt := &TrueType{UnitsPerEm: 1000, cmaps: map[uint32][]byte{}}
+
+	head := tables["head"]
+	if u := ttU16(head, 18); u >= 16 && u <= 16384 {
+		tt.UnitsPerEm = u
+	}
+
+	tt.numGlyphs = ttU16(tables["maxp"], 4)
+
+	if cff, ok := tables["CFF "]; ok {
+		if tt.CFF, err = ParseCFF(cff); err != nil {
+			return nil, err
+		}
+	} else if err := tt.parseLoca(tables["loca"], ttI16(head, 50)); err != nil {
+		return nil, err
+	}
+	tt.glyf = tables["glyf"]
+
+	tt.parseCmap(tables["cmap"])
+	tt.parsePost(tables["post"])
+
+	return tt, nil
+}
+
+func (tt *TrueType) parseLoca(loca []byte, format int) error {
+	n := tt.numGlyphs
+	if format == 0 {
+		if len(loca)/2-1 < n {
+			n = len(loca)/2 - 1
+		}
+	} else if len(loca)/4-1 < n {
+		n = len(loca)/4 - 1
+	}
+	if n < 0 {
+		return errCorruptTrueType
+	}
+
+	tt.numGlyphs = n
+	tt.locs = make([]uint32, n+1)
+	for i := range tt.locs {
+		if format == 0 {
+			tt.locs[i] = 2 * uint32(ttU16(loca, 2*i))
+		} else {
+			tt.locs[i] = ttU32(loca, 4*i)
+		}
+	}
+
+	return nil
+}
+
+func (tt *TrueType) parseCmap(cmap []byte) {
+	n := ttU16(cmap, 2)
+	for i := 0; i < n; i++ {
+		rec := 4 + 8*i
+		pid, eid, off := ttU16(cmap, rec), ttU16(cmap, rec+2), int(ttU32(cmap, rec+4))
+		if off <= 0 || off >= len(cmap) {
+			continue
+		}
+		tt.cmaps[uint32(pid)<<16|uint32(eid)] = cmap[off:]
+	}
+}
+
+func (tt *TrueType) parsePost(post []byte) {
+	if ttU32(post, 0) != 0x00020000 {
+		return
+	}
+
+	n := ttU16(post, 32)
+	off := 34 + 2*n
+
+	// Collect the Pascal strings following the glyph name indices.
+	var ss []string
+	for i := off; i < len(post); {
+		l := int(post[i])
+		if i+1+l > len(post) {
+			break
+		}
+		ss = append(ss, string(post[i+1:i+1+l]))
+		i += 1 + l
+	}
+
+	tt.names = map[string]uint16{}
+	for gid := 0; gid < n; gid++ {
+		j := ttU16(post, 34+2*gid)
+		var name string
+		if j < len(macGlyphNames) {
+			name = macGlyphNames[j]
+		} else if j-len(macGlyphNames) < len(ss) {
+			name = ss[j-len(macGlyphNames)]
+		}
+		if _, ok := tt.names[name]; !ok && name != "" {
+			tt.names[name] = uint16(gid)
+		}
+	}
+}
+
+// NumGlyphs returns the number of glyphs.
+func (tt *TrueType) NumGlyphs() int {
+	if tt.CFF != nil {
+		return tt.CFF.NumGlyphs()
+	}
+	return tt.numGlyphs
+}
+
+// HasCmap reports whether the font has a cmap subtable for platformID and encodingID.
+func (tt *TrueType) HasCmap(platformID, encodingID int) bool {
+	_, ok := tt.cmaps[uint32(platformID)<<16|uint32(encodingID)]
+	return ok
+}
+
+// HasCmaps reports whether the font has any cmap subtables.
+func (tt *TrueType) HasCmaps() bool {
+	return len(tt.cmaps) > 0
+}
+
+// GlyphIndex maps c to a glyph index using the cmap subtable for platformID and encodingID.
+func (tt *TrueType) GlyphIndex(platformID, encodingID int, c uint32) (uint16, bool) {
+	st, ok := tt.cmaps[uint32(platformID)<<16|uint32(encodingID)]
+	if !ok {
+		return 0, false
+	}
+	gid := cmapLookup(st, c)
+	return gid, gid > 0
+}
+
+// GlyphIndexForName maps a glyph name to a glyph index using the post table.
+func (tt *TrueType) GlyphIndexForName(name string) (uint16, bool) {
+	if tt.CFF != nil {
+		return tt.CFF.GlyphIndexForName(name)
+	}
+	gid, ok := tt.names[name]
+	return gid, ok
+}
+
+func cmapLookup(st []byte, c uint32) uint16 {
+	switch ttU16(st, 0) {
+
+	case 0:
+		if c < 256 && 6+int(c) < len(st) {
+			return uint16(st[6+c])
+		}
+
+	case 4:
+		segX2 := ttU16(st, 6)
+		for i := 0; i < segX2; i += 2 {
+			end := uint32(ttU16(st, 14+i))
+			if c > end {
+				continue
+			}
+			start := uint32(ttU16(st, 16+segX2+i))
+			if c < start {
+				return 0
+			}
+			delta := ttU16(st, 16+2*segX2+i)
+			roOff := 16 + 3*segX2 + i
+			ro := ttU16(st, roOff)
+			if ro == 0 {
+				return uint16(int(c) + delta)
+			}
+			gid := ttU16(st, roOff+ro+2*int(c-start))
+			if gid == 0 {
+				return 0
+			}
+			return uint16(gid + delta)
+		}
+
+	case 6:
+		first, n := uint32(ttU16(st, 6)), uint32(ttU16(st, 8))
+		if c >= first && c < first+n {
+			return uint16(ttU16(st, 10+2*int(c-first)))
+		}
+
+	case 12:
+		n := int(ttU32(st, 12))
+		for i := 0; i < n; i++ {
+			rec := 16 + 12*i
+			if rec+12 > len(st) {
+				break
+			}
+			start, end := ttU32(st, rec), ttU32(st, rec+4)
+			if c >= start && c <= end {
+				return uint16(ttU32(st, rec+8) + c - start)
+			}
+		}
+	}
+
+	return 0
+}
+
+// Outline returns the outline of glyph gid in glyph space.
+func (tt *TrueType) Outline(gid uint16) ([]Segment, error) {
+	if tt.CFF != nil {
+		return tt.CFF.Outline(gid)
+	}
+	b := &outlineBuilder{}
+	if err := tt.glyphOutline(b, gid, ttTransform{a: 1, d: 1}, 0); err != nil {
+		return nil, err
+	}
+	return b.segs, nil
+}
+
+// ttTransform is the affine transformation of a composite glyph component.
+type ttTransform struct {
+	a, b, c, d, e, f float64
+}
+
+func (t ttTransform) apply(x, y float64) (float64, float64) {
+	return t.a*x + t.c*y + t.e, t.b*x + t.d*y + t.f
+}
+
+func (t ttTransform) concat(t1 ttTransform) ttTransform {
+	// Apply t first, then t1.
+	return ttTransform{
+		a: t.a*t1.a + t.b*t1.c,
+		b: t.a*t1.b + t.b*t1.d,
+		c: t.c*t1.a + t.d*t1.c,
+		d: t.c*t1.b + t.d*t1.d,
+		e: t.e*t1.a + t.f*t1.c + t1.e,
+		f: t.e*t1.b + t.f*t1.d + t1.f,
+	}
+}
+
+func (tt *TrueType) glyphData(gid uint16) []byte {
+	if int(gid) >= tt.numGlyphs {
+		return nil
+	}
+	i, j := tt.locs[gid], tt.locs[gid+1]
+	if j <= i || int(j) > len(tt.glyf) {
+		return nil
+	}
+	return tt.glyf[i:j]
+}
+
+func (tt *TrueType) glyphOutline(b *outlineBuilder, gid uint16, t ttTransform, depth int) error {
+	if depth > 8 {
+		return errCorruptTrueType
+	}
+
+	g := tt.glyphData(gid)
+	if len(g) < 10 {
+		// Empty glyph like space.
+		return nil
+	}
+
+	n := ttI16(g, 0)
+	if n < 0 {
+		return tt.compositeOutline(b, g, t, depth)
+	}
+
+	return simpleOutline(b, g, n, t)
+}
+
+type ttPoint struct {
+	x, y    float64
+	onCurve bool
+}
+
+func simpleOutline(b *outlineBuilder, g []byte, n int, t ttTransform) error {
+	endPts := make([]int, n)
+	for i := range endPts {
+		endPts[i] = ttU16(g, 10+2*i)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	nPts := endPts[n-1] + 1
+	off := 10 + 2*n
+	off += 2 + ttU16(g, off) // skip instructions
+
+	flags := make([]byte, 0, nPts)
+	for len(flags) < nPts {
+		if off >= len(g) {
+			return errCorruptTrueType
+		}
+		f := g[off]
+		off++
+		flags = append(flags, f)
+		if f&0x08 != 0 {
+			if off >= len(g) {
+				return errCorruptTrueType
+			}
+			for r := int(g[off]); r > 0 && len(flags) < nPts; r-- {
+				flags = append(flags, f)
+			}
+			off++
+		}
+	}
+
+	pts := make([]ttPoint, nPts)
+
+	// Decode x coordinates, then y coordinates.
+	for pass, v := 0, 0; pass < 2; pass, v = pass+1, 0 {
+		short, same := byte(0x02), byte(0x10)
+		if pass == 1 {
+			short, same = 0x04, 0x20
+		}
+		for i, f := range flags {
+			switch {
+			case f&short != 0:
+				if off >= len(g) {
+					return errCorruptTrueType
+				}
+				if f&same != 0 {
+					v += int(g[off])
+				} else {
+					v -= int(g[off])
+				}
+				off++
+			case f&same == 0:
+				v += ttI16(g, off)
+				off += 2
+			}
+			if pass == 0 {
+				pts[i].x = float64(v)
+			} else {
+				pts[i].y = float64(v)
+			}
+			pts[i].onCurve = f&0x01 != 0
+		}
+	}
+
+	for i := range pts {
+		pts[i].x, pts[i].y = t.apply(pts[i].x, pts[i].y)
+	}
+
+	start := 0
+	for _, end := range endPts {
+		if end < start || end >= nPts {
+			return errCorruptTrueType
+		}
+		contourOutline(b, pts[start:end+1])
+		start = end + 1
+	}
+
+	return nil
+}
+
+// contourOutline converts a contour of on- and off-curve points into segments.
+func contourOutline(b *outlineBuilder, pts []ttPoint) {
+	n := len(pts)
+	if n == 0 {
+		return
+	}
+
+	mid := func(p, q ttPoint) ttPoint {
+		return ttPoint{x: (p.x + q.x) / 2, y: (p.y + q.y) / 2, onCurve: true}
+	}
+
+	// Find a starting point on the curve.
+	first := -1
+	for i, p := range pts {
+		if p.onCurve {
+			first = i
+			break
+		}
+	}
+
+	var p0 ttPoint
+	if first < 0 {
+		// All points are off curve.
+		p0 = mid(pts[0], pts[n-1])
+		first = 0
+	} else {
+		p0 = pts[first]
+		first++
+	}
+
+	b.moveTo(p0.x, p0.y)
+
+	var ctrl *ttPoint
+	for k := 0; k < n; k++ {
+		p := pts[(first+k)%n]
+		if k == n-1 && p == p0 && p.onCurve {
+			break
+		}
+		if p.onCurve {
+			if ctrl != nil {
+				b.quadTo(ctrl.x, ctrl.y, p.x, p.y)
+				ctrl = nil
+			} else {
+				b.lineTo(p.x, p.y)
+			}
+			continue
+		}
+		if ctrl != nil {
+			m := mid(*ctrl, p)
+			b.quadTo(ctrl.x, ctrl.y, m.x, m.y)
+		}
+		c := p
+		ctrl = &c
+	}
+
+	if ctrl != nil {
+		b.quadTo(ctrl.x, ctrl.y, p0.x, p0.y)
+	} else {
+		b.lineTo(p0.x, p0.y)
+	}
+	b.closePath()
+}
+
+func (tt *TrueType) compositeOutline(b *outlineBuilder, g []byte, t ttTransform, depth int) error {
+	const (
+		argsAreWords   = 0x0001
+		argsAreXY      = 0x0002
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		have2x2        = 0x0080
+	)
+
+	off := 10
+	for {
+		if off+4 > len(g) {
+			return errCorruptTrueType
+		}
+		flags, gid := ttU16(g, off), uint16(ttU16(g, off+2))
+		off += 4
+
+		var dx, dy float64
+		if flags&argsAreWords != 0 {
+			dx, dy = float64(ttI16(g, off)), float64(ttI16(g, off+2))
+			off += 4
+		} else {
+			if off+2 > len(g) {
+				return errCorruptTrueType
+			}
+			dx, dy = float64(int8(g[off])), float64(int8(g[off+1]))
+			off += 2
+		}
+		if flags&argsAreXY == 0 {
+			// Point matching is not supported.
+			dx, dy = 0, 0
+		}
+
+		f2dot14 := func(o int) float64 { return float64(ttI16(g, o)) / 16384 }
+
+		ct := ttTransform{a: 1, d: 1, e: dx, f: dy}
+		switch {
+		case flags&haveScale != 0:
+			ct.a = f2dot14(off)
+			ct.d = ct.a
+			off += 2
+		case flags&haveXYScale != 0:
+			ct.a, ct.d = f2dot14(off), f2dot14(off+2)
+			off += 4
+		case flags&have2x2 != 0:
+			ct.a, ct.b, ct.c, ct.d = f2dot14(off), f2dot14(off+2), f2dot14(off+4), f2dot14(off+6)
+			off += 8
+		}
+
+		if err := tt.glyphOutline(b, gid, ct.concat(t), depth+1); err != nil {
+			return err
+		}
+
+		if flags&moreComponents == 0 {
+			return nil
+		}
+	}
+}
+
+// macGlyphNames are the standard Macintosh glyph names used by the post table.
+var macGlyphNames = []string{
+	".notdef", ".null", "nonmarkingreturn", "space", "exclam", "quotedbl", "numbersign", "dollar", "percent",
+	"ampersand", "quotesingle", "parenleft", "parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash",
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "colon", "semicolon", "less",
+	"equal", "greater", "question", "at",
+	"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W",
+	"X", "Y", "Z", "bracketleft", "backslash", "bracketright", "asciicircum", "underscore", "grave",
+	"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w",
+	"x", "y", "z", "braceleft", "bar", "braceright", "asciitilde", "Adieresis", "Aring", "Ccedilla", "Eacute",
+	"Ntilde", "Odieresis", "Udieresis", "aacute", "agrave", "acircumflex", "adieresis", "atilde", "aring",
+	"ccedilla", "eacute", "egrave", "ecircumflex", "edieresis", "iacute", "igrave", "icircumflex", "idieresis",
+	"ntilde", "oacute", "ograve", "ocircumflex", "odieresis", "otilde", "uacute", "ugrave", "ucircumflex",
+	"udieresis", "dagger", "degree", "cent", "sterling", "section", "bullet", "paragraph", "germandbls",
+	"registered", "copyright", "trademark", "acute", "dieresis", "notequal", "AE", "Oslash", "infinity",
+	"plusminus", "lessequal", "greaterequal", "yen", "mu", "partialdiff", "summation", "product", "pi",
+	"integral", "ordfeminine", "ordmasculine", "Omega", "ae", "oslash", "questiondown", "exclamdown",
+	"logicalnot", "radical", "florin", "approxequal", "Delta", "guillemotleft", "guillemotright", "ellipsis",
+	"nonbreakingspace", "Agrave", "Atilde", "Otilde", "OE", "oe", "endash", "emdash", "quotedblleft",
+	"quotedblright", "quoteleft", "quoteright", "divide", "lozenge", "ydieresis", "Ydieresis", "fraction",
+	"currency", "guilsinglleft", "guilsinglright", "fi", "fl", "daggerdbl", "periodcentered", "quotesinglbase",
+	"quotedblbase", "perthousand", "Acircumflex", "Ecircumflex", "Aacute", "Edieresis", "Egrave", "Iacute",
+	"Icircumflex", "Idieresis", "Igrave", "Oacute", "Ocircumflex", "apple", "Ograve", "Uacute", "Ucircumflex",
+	"Ugrave", "dotlessi", "circumflex", "tilde", "macron", "breve", "dotaccent", "ring", "cedilla",
+	"hungarumlaut", "ogonek", "caron", "Lslash", "lslash", "Scaron", "scaron", "Zcaron", "zcaron", "brokenbar",
+	"Eth", "eth", "Yacute", "yacute", "Thorn", "thorn", "minus", "multiply", "onesuperior", "twosuperior",
+	"threesuperior", "onehalf", "onequarter", "threequarters", "franc", "Gbreve", "gbreve", "Idotaccent",
+	"Scedilla", "scedilla", "Cacute", "cacute", "Ccaron", "ccaron", "dcroat",
+}
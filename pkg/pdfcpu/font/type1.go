@@ -0,0 +1,499 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package font
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pkg/errors"
+)
+
+var errCorruptType1 = errors.New("pdfcpu: corrupt Type1 font program")
+
+// Type1 represents a Type 1 font program (Adobe Type 1 Font Format) providing glyph outlines.
+type Type1 struct {
+	FontMatrix  [6]float64
+	Encoding    *[256]string // built-in encoding, nil for StandardEncoding
+	charStrings map[string][]byte
+	subrs       [][]byte
+}
+
+func isType1Whitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func eexecData(bb []byte) []byte {
+	for len(bb) > 0 && isType1Whitespace(bb[0]) {
+		bb = bb[1:]
+	}
+	if len(bb) < 4 || !isHexDigit(bb[0]) || !isHexDigit(bb[1]) || !isHexDigit(bb[2]) || !isHexDigit(bb[3]) {
+		return bb
+	}
+
+	// PFA style hex encoding
+	var buf []byte
+	for _, c := range bb {
+		if isHexDigit(c) {
+			buf = append(buf, c)
+		} else if !isType1Whitespace(c) {
+			break
+		}
+	}
+	if len(buf)%2 == 1 {
+		buf = buf[:len(buf)-1]
+	}
+	out := make([]byte, len(buf)/2)
+	hex.Decode(out, buf)
+	return out
+}
+
+// ParseType1 parses a Type 1 font program as embedded using FontFile.
+func ParseType1(bb []byte) (*Type1, error) {
+	i := bytes.Index(bb, []byte("eexec"))
+	if i < 0 {
+		return nil, errCorruptType1
+	}
+
+	t1 := &Type1{FontMatrix: [6]float64{0.001, 0, 0, 0.001, 0, 0}, charStrings: map[string][]byte{}}
+
+	clear := bb[:i]
+	t1.parseFontMatrix(clear)
+	t1.parseEncoding(clear)
+
+	priv := font.Type1Decrypt(eexecData(bb[i+5:]), 55665, 4)
+
+	lenIV := 4
+	if j := bytes.Index(priv, []byte("/lenIV")); j >= 0 {
+		tok, _ := font.Type1Token(priv, j+6)
+		if n, err := strconv.Atoi(tok); err == nil {
+			lenIV = n
+		}
+	}
+
+	t1.parseSubrs(priv, lenIV)
+	t1.parseCharStrings(priv, lenIV)
+
+	if len(t1.charStrings) == 0 {
+		return nil, errCorruptType1
+	}
+
+	return t1, nil
+}
+
+func (t1 *Type1) parseFontMatrix(bb []byte) {
+	i := bytes.Index(bb, []byte("/FontMatrix"))
+	if i < 0 {
+		return
+	}
+	tok, i := font.Type1Token(bb, i+11)
+	if tok != "[" && tok != "{" {
+		return
+	}
+	var m [6]float64
+	for k := 0; k < 6; k++ {
+		tok, i = font.Type1Token(bb, i)
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return
+		}
+		m[k] = f
+	}
+	t1.FontMatrix = m
+}
+
+func (t1 *Type1) parseEncoding(bb []byte) {
+	i := bytes.Index(bb, []byte("/Encoding"))
+	if i < 0 {
+		return
+	}
+	tok, i := font.Type1Token(bb, i+9)
+	if tok == "StandardEncoding" {
+		return
+	}
+
+	var enc [256]string
+	for {
+		tok, i = font.Type1Token(bb, i)
+		if tok == "" || tok == "def" || tok == "readonly" {
+			break
+		}
+		if tok != "dup" {
+			continue
+		}
+		tok, i = font.Type1Token(bb, i)
+		c, err := strconv.Atoi(tok)
+		if err != nil {
+			continue
+		}
+		tok, i = font.Type1Token(bb, i)
+		if c >= 0 && c < 256 && strings.HasPrefix(tok, "/") {
+			enc[c] = tok[1:]
+		}
+	}
+	t1.Encoding = &enc
+}
+
+func (t1 *Type1) parseSubrs(bb []byte, lenIV int) {
+	i := bytes.Index(bb, []byte("/Subrs"))
+	if i < 0 {
+		return
+	}
+	tok, i := font.Type1Token(bb, i+6)
+	n, err := strconv.Atoi(tok)
+	if err != nil || n < 0 || n > 65535 {
+		return
+	}
+	t1.subrs = make([][]byte, n)
+
+	for {
+		tok, i = font.Type1Token(bb, i)
+		if tok == "" || strings.HasPrefix(tok, "/") {
+			return
+		}
+		if tok != "dup" {
+			continue
+		}
+		tok, i = font.Type1Token(bb, i)
+		j, err := strconv.Atoi(tok)
+		if err != nil {
+			return
+		}
+		cs, i1, ok := font.Type1Binary(bb, i, lenIV)
+		if !ok {
+			return
+		}
+		i = i1
+		if j >= 0 && j < n {
+			t1.subrs[j] = cs
+		}
+	}
+}
+
+func (t1 *Type1) parseCharStrings(bb []byte, lenIV int) {
+	i := bytes.Index(bb, []byte("/CharStrings"))
+	if i < 0 {
+		return
+	}
+	i += 12
+
+	for {
+		var tok string
+		tok, i = font.Type1Token(bb, i)
+		if tok == "" || tok == "end" {
+			return
+		}
+		if !strings.HasPrefix(tok, "/") || len(tok) == 1 {
+			continue
+		}
+		cs, i1, ok := font.Type1Binary(bb, i, lenIV)
+		if !ok {
+			continue
+		}
+		i = i1
+		t1.charStrings[tok[1:]] = cs
+	}
+}
+
+// HasGlyph reports whether the font has a glyph named name.
+func (t1 *Type1) HasGlyph(name string) bool {
+	_, ok := t1.charStrings[name]
+	return ok
+}
+
+// Outline returns the outline of the glyph named name in glyph space.
+func (t1 *Type1) Outline(name string) ([]Segment, error) {
+	cs, ok := t1.charStrings[name]
+	if !ok {
+		return nil, nil
+	}
+	ip := &type1Interpreter{t1: t1}
+	if err := ip.exec(cs); err != nil {
+		return nil, err
+	}
+	return ip.b.segs, nil
+}
+
+// type1Interpreter executes Type 1 charstrings, see chapter 6 of the Type 1 Font Format.
+type type1Interpreter struct {
+	t1       *Type1
+	b        outlineBuilder
+	stack    []float64
+	psStack  []float64
+	flex     bool
+	flexPts  []float64
+	depth    int
+	done     bool
+	sbx, sby float64
+}
+
+func (ip *type1Interpreter) pop() float64 {
+	if len(ip.stack) == 0 {
+		return 0
+	}
+	v := ip.stack[len(ip.stack)-1]
+	ip.stack = ip.stack[:len(ip.stack)-1]
+	return v
+}
+
+func (ip *type1Interpreter) rmoveto(dx, dy float64) {
+	if ip.flex {
+		ip.b.x, ip.b.y = ip.b.x+dx, ip.b.y+dy
+		ip.flexPts = append(ip.flexPts, ip.b.x, ip.b.y)
+		return
+	}
+	ip.b.closePath()
+	ip.b.moveTo(ip.b.x+dx, ip.b.y+dy)
+}
+
+func (ip *type1Interpreter) rcurveto(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	x1, y1 := ip.b.x+dx1, ip.b.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	ip.b.cubeTo(x1, y1, x2, y2, x2+dx3, y2+dy3)
+}
+
+func (ip *type1Interpreter) callOtherSubr() {
+	nr, n := int(ip.pop()), int(ip.pop())
+	if n < 0 || n > len(ip.stack) {
+		n = len(ip.stack)
+	}
+	args := append([]float64{}, ip.stack[len(ip.stack)-n:]...)
+	ip.stack = ip.stack[:len(ip.stack)-n]
+
+	switch nr {
+
+	case 0: // end flex
+		ip.flex = false
+		if p := ip.flexPts; len(p) >= 14 {
+			// The first point is the reference point.
+			ip.b.cubeTo(p[2], p[3], p[4], p[5], p[6], p[7])
+			ip.b.cubeTo(p[8], p[9], p[10], p[11], p[12], p[13])
+		}
+		ip.psStack = ip.psStack[:0]
+		if len(args) == 3 {
+			ip.psStack = append(ip.psStack, args[2], args[1])
+		}
+		return
+
+	case 1: // start flex
+		ip.flex = true
+		ip.flexPts = ip.flexPts[:0]
+		return
+	}
+
+	ip.psStack = ip.psStack[:0]
+	for i := len(args) - 1; i >= 0; i-- {
+		ip.psStack = append(ip.psStack, args[i])
+	}
+}
+
+func (ip *type1Interpreter) seac(asb, adx, ady float64, bchar, achar int) error {
+	enc := baseEncoding("StandardEncoding")
+	if bchar < 0 || bchar > 255 || achar < 0 || achar > 255 {
+		return nil
+	}
+	bcs, ok1 := ip.t1.charStrings[enc[bchar]]
+	acs, ok2 := ip.t1.charStrings[enc[achar]]
+	if !ok1 || !ok2 {
+		return nil
+	}
+
+	ip1 := &type1Interpreter{t1: ip.t1, depth: ip.depth}
+	if err := ip1.exec(bcs); err != nil {
+		return err
+	}
+	segs := ip1.b.segs
+
+	ip1 = &type1Interpreter{t1: ip.t1, depth: ip.depth}
+	ip1.b.dx, ip1.b.dy = adx-asb, ady
+	if err := ip1.exec(acs); err != nil {
+		return err
+	}
+
+	ip.b.segs = append(segs, ip1.b.segs...)
+	return nil
+}
+
+func (ip *type1Interpreter) escapeOp(op byte) error {
+	s := ip.stack
+	switch op {
+
+	case 6: // seac
+		if len(s) >= 5 {
+			if err := ip.seac(s[0], s[1], s[2], int(s[3]), int(s[4])); err != nil {
+				return err
+			}
+		}
+		ip.done = true
+
+	case 7: // sbw
+		if len(s) >= 4 {
+			ip.sbx, ip.sby = s[0], s[1]
+			ip.b.x, ip.b.y = s[0], s[1]
+		}
+
+	case 12: // div
+		b, a := ip.pop(), ip.pop()
+		if b != 0 {
+			ip.stack = append(ip.stack, a/b)
+		} else {
+			ip.stack = append(ip.stack, 0)
+		}
+		return nil
+
+	case 16: // callothersubr
+		ip.callOtherSubr()
+		return nil
+
+	case 17: // pop
+		v := 0.0
+		if n := len(ip.psStack); n > 0 {
+			v = ip.psStack[n-1]
+			ip.psStack = ip.psStack[:n-1]
+		}
+		ip.stack = append(ip.stack, v)
+		return nil
+
+	case 33: // setcurrentpoint
+		if len(s) >= 2 {
+			ip.b.x, ip.b.y = s[0], s[1]
+		}
+	}
+
+	ip.stack = ip.stack[:0]
+	return nil
+}
+
+func (ip *type1Interpreter) exec(cs []byte) error {
+	ip.depth++
+	defer func() { ip.depth-- }()
+	if ip.depth > 10 {
+		return errCorruptType1
+	}
+
+	for i := 0; i < len(cs) && !ip.done; {
+		v := int(cs[i])
+
+		switch {
+		case v >= 32 && v <= 246:
+			ip.stack = append(ip.stack, float64(v-139))
+			i++
+			continue
+		case v >= 247 && v <= 254:
+			if i+1 >= len(cs) {
+				return errCorruptType1
+			}
+			w := int(cs[i+1])
+			if v <= 250 {
+				ip.stack = append(ip.stack, float64((v-247)*256+w+108))
+			} else {
+				ip.stack = append(ip.stack, float64(-(v-251)*256-w-108))
+			}
+			i += 2
+			continue
+		case v == 255:
+			ip.stack = append(ip.stack, float64(int32(ttU32(cs, i+1))))
+			i += 5
+			continue
+		}
+
+		i++
+		s := ip.stack
+		arg := func(j int) float64 {
+			if j < len(s) {
+				return s[j]
+			}
+			return 0
+		}
+
+		switch v {
+
+		case 4: // vmoveto
+			ip.rmoveto(0, arg(0))
+
+		case 5: // rlineto
+			ip.b.lineTo(ip.b.x+arg(0), ip.b.y+arg(1))
+
+		case 6: // hlineto
+			ip.b.lineTo(ip.b.x+arg(0), ip.b.y)
+
+		case 7: // vlineto
+			ip.b.lineTo(ip.b.x, ip.b.y+arg(0))
+
+		case 8: // rrcurveto
+			ip.rcurveto(arg(0), arg(1), arg(2), arg(3), arg(4), arg(5))
+
+		case 9: // closepath
+			ip.b.closePath()
+
+		case 10: // callsubr
+			j := int(ip.pop())
+			if j < 0 || j >= len(ip.t1.subrs) {
+				return errCorruptType1
+			}
+			if err := ip.exec(ip.t1.subrs[j]); err != nil {
+				return err
+			}
+			continue
+
+		case 11: // return
+			return nil
+
+		case 13: // hsbw
+			ip.sbx = arg(0)
+			ip.b.x, ip.b.y = arg(0), 0
+
+		case 14: // endchar
+			ip.b.closePath()
+			ip.done = true
+
+		case 21: // rmoveto
+			ip.rmoveto(arg(0), arg(1))
+
+		case 22: // hmoveto
+			ip.rmoveto(arg(0), 0)
+
+		case 30: // vhcurveto
+			ip.rcurveto(0, arg(0), arg(1), arg(2), arg(3), 0)
+
+		case 31: // hvcurveto
+			ip.rcurveto(arg(0), 0, arg(1), arg(2), 0, arg(3))
+
+		case 12:
+			if i >= len(cs) {
+				return errCorruptType1
+			}
+			op := cs[i]
+			i++
+			if err := ip.escapeOp(op); err != nil {
+				return err
+			}
+			continue
+		}
+
+		ip.stack = ip.stack[:0]
+	}
+
+	return nil
+}
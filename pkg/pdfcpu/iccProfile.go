@@ -20,6 +20,8 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -28,7 +30,7 @@ import (
 //
 // We fall back to the alternate color space and if there is none to whatever color space makes sense.
 
-//ICC profiles use big endian always.
+// ICC profiles use big endian always.
 type iccProfile struct {
 	b          []byte
 	rX, rY, rZ float32 // redMatrixColumn; the first column in the matrix, which is used in matrix/TRC transforms.
@@ -309,3 +311,146 @@ func (p iccProfile) String() string {
 
 	return s
 }
+
+// ICCProfileInfo describes the header of an ICC profile.
+type ICCProfileInfo struct {
+	Version    string // eg. 2.1.0.0
+	Major      int    // major version
+	Class      string // device class, eg. mntr, prtr, scnr
+	ColorSpace string // data color space, eg. RGB, CMYK, GRAY
+	N          int    // number of color components
+}
+
+// ParseICCProfile checks the header and tag table of an ICC profile.
+func ParseICCProfile(b []byte) (*ICCProfileInfo, error) {
+	if len(b) < 132 {
+		return nil, errors.New("pdfcpu: ICC profile: header too short")
+	}
+
+	p := iccProfile{b: b}
+
+	if p.fileSig() != "acsp" {
+		return nil, errors.New("pdfcpu: ICC profile: missing profile file signature")
+	}
+
+	if size := p.size(); size < 132 || int64(size) > int64(len(b)) {
+		return nil, errors.Errorf("pdfcpu: ICC profile: invalid size %d", size)
+	}
+
+	if n := p.tagCount(); n < 0 || 132+12*n > len(b) {
+		return nil, errors.New("pdfcpu: ICC profile: corrupt tag table")
+	}
+
+	info := &ICCProfileInfo{
+		Version:    p.version(),
+		Major:      int(b[8]),
+		Class:      p.class(),
+		ColorSpace: strings.TrimSpace(p.dataColorSpace()),
+	}
+
+	switch info.ColorSpace {
+	case "GRAY":
+		info.N = 1
+	case "RGB", "Lab", "XYZ":
+		info.N = 3
+	case "CMYK":
+		info.N = 4
+	}
+
+	return info, nil
+}
+
+func iccTag(typ string, data ...[]byte) []byte {
+	bb := append([]byte(typ), 0, 0, 0, 0)
+	for _, d := range data {
+		bb = append(bb, d...)
+	}
+	for len(bb)%4 > 0 {
+		bb = append(bb, 0)
+	}
+	return bb
+}
+
+func s15Fixed16(ff ...float64) []byte {
+	bb := make([]byte, 4*len(ff))
+	for i, f := range ff {
+		binary.BigEndian.PutUint32(bb[4*i:], uint32(int32(math.Round(f*65536))))
+	}
+	return bb
+}
+
+func iccTextDescription(s string) []byte {
+	bb := make([]byte, 4, 4+len(s)+1+8+3+67)
+	binary.BigEndian.PutUint32(bb, uint32(len(s)+1))
+	bb = append(bb, s...)
+	bb = append(bb, 0)
+	// Empty Unicode and ScriptCode descriptions.
+	return append(bb, make([]byte, 8+3+67)...)
+}
+
+func iccSRGBCurve(n int) []byte {
+	bb := make([]byte, 4+2*n)
+	binary.BigEndian.PutUint32(bb, uint32(n))
+	for i := 0; i < n; i++ {
+		v := float64(i) / float64(n-1)
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+		binary.BigEndian.PutUint16(bb[4+2*i:], uint16(math.Round(v*65535)))
+	}
+	return bb
+}
+
+// SRGBProfile returns a version 2 ICC display profile for the sRGB color space (IEC 61966-2-1).
+func SRGBProfile() []byte {
+	trc := iccTag("curv", iccSRGBCurve(1024))
+
+	tags := []struct {
+		sig  string
+		data []byte
+	}{
+		{"desc", iccTag("desc", iccTextDescription("sRGB IEC61966-2.1"))},
+		{"cprt", iccTag("text", []byte("No copyright, use freely"), []byte{0})},
+		{"wtpt", iccTag("XYZ ", s15Fixed16(0.9642, 1.0, 0.8249))},
+		{"rXYZ", iccTag("XYZ ", s15Fixed16(0.4360747, 0.2225045, 0.0139322))},
+		{"gXYZ", iccTag("XYZ ", s15Fixed16(0.3850649, 0.7168786, 0.0971045))},
+		{"bXYZ", iccTag("XYZ ", s15Fixed16(0.1430804, 0.0606169, 0.7141733))},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	off := 128 + 4 + 12*len(tags)
+	table := make([]byte, 4, 4+12*len(tags))
+	binary.BigEndian.PutUint32(table, uint32(len(tags)))
+
+	var data []byte
+	for i, t := range tags {
+		tagOff := off + len(data)
+		if t.sig[1:] == "TRC" && i > 0 && tags[i-1].sig[1:] == "TRC" {
+			// Share the tone reproduction curve.
+			tagOff -= len(trc)
+		} else {
+			data = append(data, t.data...)
+		}
+		entry := make([]byte, 12)
+		copy(entry, t.sig)
+		binary.BigEndian.PutUint32(entry[4:], uint32(tagOff))
+		binary.BigEndian.PutUint32(entry[8:], uint32(len(t.data)))
+		table = append(table, entry...)
+	}
+
+	h := make([]byte, 128)
+	binary.BigEndian.PutUint32(h, uint32(off+len(data)))
+	binary.BigEndian.PutUint32(h[8:], 0x02100000)
+	copy(h[12:], "mntrRGB XYZ ")
+	for i, v := range []uint16{1998, 2, 9, 6, 49, 0} {
+		binary.BigEndian.PutUint16(h[24+2*i:], v)
+	}
+	copy(h[36:], "acsp")
+	copy(h[68:], s15Fixed16(0.9642, 1.0, 0.8249))
+
+	return append(append(h, table...), data...)
+}
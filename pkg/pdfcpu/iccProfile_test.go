@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSRGBProfile(t *testing.T) {
+	bb := SRGBProfile()
+
+	info, err := ParseICCProfile(bb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Major != 2 || info.Class != "mntr" || info.ColorSpace != "RGB" || info.N != 3 {
+		t.Fatalf("unexpected profile header: %+v\n", *info)
+	}
+	if len(bb)%4 != 0 {
+		t.Fatalf("profile size %d not 4 byte aligned\n", len(bb))
+	}
+
+	p := iccProfile{b: bb}
+	if err := p.init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The matrix columns add up to the D50 white point.
+	x, y, z := p.rX+p.gX+p.bX, p.rY+p.gY+p.bY, p.rZ+p.gZ+p.bZ
+	if math.Abs(float64(x)-0.9642) > 1e-3 || math.Abs(float64(y)-1) > 1e-3 || math.Abs(float64(z)-0.8249) > 1e-3 {
+		t.Fatalf("unexpected white point: %f %f %f\n", x, y, z)
+	}
+
+	for _, sig := range []string{"desc", "cprt", "wtpt", "rTRC", "gTRC", "bTRC"} {
+		off, size, err := p.tag(sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if off%4 != 0 || off+size > len(bb) {
+			t.Fatalf("tag %s: invalid offset %d or size %d\n", sig, off, size)
+		}
+	}
+}
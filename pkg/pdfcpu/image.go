@@ -310,10 +310,31 @@ func validateImageDimensions(ctx *model.Context, objNr, w, h int) error {
 	return nil
 }
 
+// createImageStreamDict returns a stream dict for the image data represented by rd
+// recompressed as CCITTFax or DCT if configured and beneficial.
+func createImageStreamDict(ctx *model.Context, rd io.Reader) (*types.StreamDict, int, int, error) {
+	sd, w, h, err := model.CreateImageStreamDict(ctx.XRefTable, rd, false, false)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if ctx.Conf.OptimizeImages {
+		sd1, err := RecompressImage(ctx.XRefTable, sd)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if sd1 != nil {
+			sd = sd1
+		}
+	}
+
+	return sd, w, h, nil
+}
+
 // UpdateImagesByObjNr replaces an XObject.
 func UpdateImagesByObjNr(ctx *model.Context, rd io.Reader, objNr int) error {
 
-	sd, w, h, err := model.CreateImageStreamDict(ctx.XRefTable, rd, false, false)
+	sd, w, h, err := createImageStreamDict(ctx, rd)
 	if err != nil {
 		return err
 	}
@@ -355,7 +376,12 @@ func isInheritedXObjectResource(inhRes types.Dict, id string) bool {
 // UpdateImagesByPageNrAndId replaces the XObject referenced by pageNr and id.
 func UpdateImagesByPageNrAndId(ctx *model.Context, rd io.Reader, pageNr int, id string) error {
 
-	imgIndRef, w, h, err := model.CreateImageResource(ctx.XRefTable, rd, false, false)
+	sd, w, h, err := createImageStreamDict(ctx, rd)
+	if err != nil {
+		return err
+	}
+
+	imgIndRef, err := ctx.IndRefForNewObject(*sd)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,303 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// dctColorSpace returns true if images using colorspace cs may be lossy compressed.
+func dctColorSpace(xRefTable *model.XRefTable, cs types.Object) bool {
+	o, err := xRefTable.Dereference(cs)
+	if err != nil {
+		return false
+	}
+
+	switch cs := o.(type) {
+
+	case types.Name:
+		return cs == model.DeviceGrayCS || cs == model.DeviceRGBCS
+
+	case types.Array:
+		if len(cs) == 0 {
+			return false
+		}
+		n, _ := cs[0].(types.Name)
+		return n == model.CalGrayCS || n == model.CalRGBCS || n == model.ICCBasedCS
+	}
+
+	return false
+}
+
+// indexedColorSpace returns true for colorspace cs being an Indexed colorspace.
+func indexedColorSpace(xRefTable *model.XRefTable, cs types.Object) bool {
+	o, err := xRefTable.Dereference(cs)
+	if err != nil {
+		return false
+	}
+	a, ok := o.(types.Array)
+	if !ok || len(a) == 0 {
+		return false
+	}
+	n, _ := a[0].(types.Name)
+	return n == model.IndexedCS
+}
+
+// dctSamples returns the interleaved 8 bit samples of a gray or RGB JPEG.
+func dctSamples(bb []byte) ([]byte, int) {
+	img, err := jpeg.Decode(bytes.NewReader(bb))
+	if err != nil {
+		return nil, 0
+	}
+
+	r := img.Bounds()
+
+	switch img := img.(type) {
+
+	case *image.Gray:
+		buf := make([]byte, 0, r.Dx()*r.Dy())
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			i := img.PixOffset(r.Min.X, y)
+			buf = append(buf, img.Pix[i:i+r.Dx()]...)
+		}
+		return buf, 1
+
+	case *image.YCbCr:
+		buf := make([]byte, 0, r.Dx()*r.Dy()*3)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				c := img.YCbCrAt(x, y)
+				r, g, b := color.YCbCrToRGB(c.Y, c.Cb, c.Cr)
+				buf = append(buf, r, g, b)
+			}
+		}
+		return buf, 3
+	}
+
+	return nil, 0
+}
+
+// imageSamples returns the decoded samples of sd.
+func imageSamples(sd *types.StreamDict, comps int) ([]byte, error) {
+	if sd.HasSoleFilterNamed(filter.DCT) {
+		raw, err := sd.RawContent()
+		if err != nil {
+			return nil, err
+		}
+		bb, n := dctSamples(raw)
+		if n != comps {
+			return nil, nil
+		}
+		return bb, nil
+	}
+
+	for _, f := range sd.FilterPipeline {
+		if f.Name == filter.DCT || f.Name == filter.JPX || f.Name == filter.JBIG2 {
+			return nil, nil
+		}
+	}
+
+	sd1 := *sd
+	if err := sd1.Decode(); err != nil {
+		return nil, err
+	}
+
+	return sd1.Content, nil
+}
+
+// bilevel packs 8 bit gray samples into rows of 1 bit samples if all samples are either black or white.
+func bilevel(samples []byte, w, h int) []byte {
+	rowBytes := (w + 7) / 8
+	buf := make([]byte, rowBytes*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			switch samples[y*w+x] {
+			case 0x00:
+			case 0xFF:
+				buf[y*rowBytes+x/8] |= 0x80 >> uint(x%8)
+			default:
+				return nil
+			}
+		}
+	}
+	return buf
+}
+
+func recompressedImage(sd *types.StreamDict, samples []byte, filterName string, encodeParms, decodeParms types.Dict, bpc int) (*types.StreamDict, error) {
+	d := sd.Dict.Clone().(types.Dict)
+	d.Delete("DecodeParms")
+	d.Update("Filter", types.Name(filterName))
+	if decodeParms != nil {
+		d.Insert("DecodeParms", decodeParms)
+	}
+	if d.IntEntry("BitsPerComponent") != nil {
+		d.Update("BitsPerComponent", types.Integer(bpc))
+	}
+
+	sd1 := types.NewStreamDict(d, 0, nil, nil, []types.PDFFilter{{Name: filterName, DecodeParms: encodeParms}})
+	sd1.Content = samples
+
+	if err := sd1.Encode(); err != nil {
+		return nil, err
+	}
+
+	sd1.FilterPipeline = []types.PDFFilter{{Name: filterName, DecodeParms: decodeParms}}
+
+	if filterName == filter.DCT {
+		// The content of a DCT encoded image is the encoded stream.
+		sd1.Content = nil
+		sd1.CSComponents = encodeParms["Colors"].(types.Integer).Value()
+	}
+
+	return &sd1, nil
+}
+
+func ccittImage(sd *types.StreamDict, samples []byte, w, h int) (*types.StreamDict, error) {
+	parms := types.Dict(map[string]types.Object{
+		"K":       types.Integer(-1),
+		"Columns": types.Integer(w),
+		"Rows":    types.Integer(h),
+	})
+	return recompressedImage(sd, samples, filter.CCITTFax, parms, parms, 1)
+}
+
+func dctImage(sd *types.StreamDict, samples []byte, w, h, comps, quality int) (*types.StreamDict, error) {
+	parms := types.Dict(map[string]types.Object{
+		"Columns": types.Integer(w),
+		"Rows":    types.Integer(h),
+		"Colors":  types.Integer(comps),
+		"Quality": types.Integer(quality),
+	})
+	return recompressedImage(sd, samples, filter.DCT, parms, nil, 8)
+}
+
+// RecompressImage returns sd encoded as CCITTFax (Group 4) for bilevel images or as DCT for 8 bit gray or RGB images
+// using the configured image quality. The result is nil if sd is not eligible or re-encoding does not save space.
+func RecompressImage(xRefTable *model.XRefTable, sd *types.StreamDict) (*types.StreamDict, error) {
+	if s := sd.Subtype(); s == nil || *s != "Image" {
+		return nil, nil
+	}
+
+	w, h := sd.IntEntry("Width"), sd.IntEntry("Height")
+	if w == nil || h == nil || *w <= 0 || *h <= 0 {
+		return nil, nil
+	}
+
+	if sd.HasSoleFilterNamed(filter.CCITTFax) {
+		if k := sd.FilterPipeline[0].DecodeParms.IntEntry("K"); k != nil && *k < 0 {
+			// Already Group 4.
+			return nil, nil
+		}
+	}
+
+	if sd.BooleanEntry("SMaskInData") != nil {
+		return nil, nil
+	}
+
+	bpc := 1
+	if i := sd.IntEntry("BitsPerComponent"); i != nil {
+		bpc = *i
+	}
+
+	cs, found := sd.Find("ColorSpace")
+
+	imageMask := sd.BooleanEntry("ImageMask")
+	if imageMask != nil && *imageMask {
+		cs, found, bpc = nil, false, 1
+	}
+
+	comps := 1
+	if found {
+		if indexedColorSpace(xRefTable, cs) {
+			if bpc != 1 {
+				return nil, nil
+			}
+		} else {
+			c, err := ColorSpaceComponents(xRefTable, sd)
+			if err != nil {
+				return nil, err
+			}
+			comps = c
+		}
+	}
+
+	if (comps != 1 && comps != 3) || (bpc != 1 && bpc != 8) || (bpc == 1 && comps != 1) {
+		return nil, nil
+	}
+
+	samples, err := imageSamples(sd, comps)
+	if err != nil || samples == nil {
+		return nil, err
+	}
+
+	var sd1 *types.StreamDict
+
+	switch {
+
+	case bpc == 1:
+		if len(samples) < (*w+7)/8**h {
+			return nil, nil
+		}
+		sd1, err = ccittImage(sd, samples, *w, *h)
+
+	default:
+		if len(samples) < *w**h*comps {
+			return nil, nil
+		}
+		if comps == 1 && !indexedColorSpace(xRefTable, cs) {
+			if bb := bilevel(samples, *w, *h); bb != nil {
+				sd1, err = ccittImage(sd, bb, *w, *h)
+				break
+			}
+		}
+		if !found || !dctColorSpace(xRefTable, cs) {
+			return nil, nil
+		}
+		quality := 0
+		if xRefTable.Conf != nil {
+			quality = xRefTable.Conf.ImageQuality
+		}
+		sd1, err = dctImage(sd, samples, *w, *h, comps, quality)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := len(sd.Raw)
+	if sd.Detached() {
+		l = int(*sd.StreamLength)
+	}
+
+	if len(sd1.Raw) >= l {
+		return nil, nil
+	}
+
+	if log.OptimizeEnabled() {
+		log.Optimize.Printf("RecompressImage: %s %d -> %d bytes\n", sd1.FilterPipeline[0].Name, l, len(sd1.Raw))
+	}
+
+	return sd1, nil
+}
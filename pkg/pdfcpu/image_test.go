@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/png"
 	"io"
 	"os"
 	"path/filepath"
@@ -474,3 +475,51 @@ func TestReadWriteJPEG(t *testing.T) {
 	fmt.Printf("fileName: %s\n", fn)
 	// No comparison since JPG is lossy.
 }
+
+func TestReadJPXWritePNG(t *testing.T) {
+	bb, err := os.ReadFile(filepath.Join(inDir, "mountain.jpx"))
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	// An image dict w/o ColorSpace: the JP2 header applies.
+	d := types.Dict{
+		"Type":    types.Name("XObject"),
+		"Subtype": types.Name("Image"),
+		"Width":   types.Integer(1667),
+		"Height":  types.Integer(2646),
+		"Filter":  types.Name(filter.JPX),
+	}
+	sd := types.NewStreamDict(d, 0, nil, nil, []types.PDFFilter{{Name: filter.JPX}})
+	sd.Raw = bb
+
+	r, typ, err := RenderImage(xRefTable, &sd, false, "", 0)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if typ != "png" {
+		t.Fatalf("want png, got %s\n", typ)
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	// The ICC profile of the JP2 header gets embedded.
+	if i := bytes.Index(b, []byte("iCCP")); i < 0 || i > bytes.Index(b, []byte("IDAT")) {
+		t.Fatal("missing iCCP chunk\n")
+	}
+
+	if _, err := png.Decode(bytes.NewReader(b)); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+
+	// Generic decoding yields the colour samples.
+	if err := sd.Decode(); err != nil {
+		t.Fatalf("err: %v\n", err)
+	}
+	if len(sd.Content) != 1667*2646*3 {
+		t.Fatalf("want %d decoded bytes, got %d\n", 1667*2646*3, len(sd.Content))
+	}
+}
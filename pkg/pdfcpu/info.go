@@ -116,6 +116,11 @@ func ensureInfoDict(ctx *model.Context) error {
 	// ModDate		        modified by pdfcpu
 	// Trapped              -
 
+	if ctx.Cmd == model.PDFACONVERT && ctx.Info != nil {
+		// Already synchronized with the XMP metadata.
+		return nil
+	}
+
 	now := types.DateString(time.Now())
 
 	v := "pdfcpu " + model.VersionStr
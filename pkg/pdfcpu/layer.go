@@ -0,0 +1,1184 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Layer represents an optional content group (OCG), see 8.11.2.
+type Layer struct {
+	Name    string
+	ObjNr   int
+	Visible bool  // default visibility according to the default configuration.
+	Locked  bool  // visibility locked in the user interface.
+	Pages   []int // pages referencing this layer.
+}
+
+// optional content actions applied to content belonging to a layer.
+type ocAction int
+
+const (
+	ocKeep   ocAction = iota // leave as is
+	ocUnwrap                 // keep content, drop optional content membership
+	ocDrop                   // remove content
+)
+
+func ocProperties(ctx *model.Context) (types.Dict, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("OCProperties")
+	if !found {
+		return nil, nil
+	}
+
+	return ctx.DereferenceDict(o)
+}
+
+func indRefObjNr(o types.Object) int {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		return indRef.ObjectNumber.Value()
+	}
+	return 0
+}
+
+// ocgObjNrs returns the object numbers of the OCGs referenced by an optional content group or membership dict.
+func ocgObjNrs(ctx *model.Context, o types.Object) []int {
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return nil
+	}
+
+	if t := d.Type(); t != nil && *t == "OCMD" {
+		o1, err := ctx.Dereference(d["OCGs"])
+		if err != nil || o1 == nil {
+			return nil
+		}
+		if a, ok := o1.(types.Array); ok {
+			var ii []int
+			for _, o2 := range a {
+				if i := indRefObjNr(o2); i > 0 {
+					ii = append(ii, i)
+				}
+			}
+			return ii
+		}
+		if i := indRefObjNr(d["OCGs"]); i > 0 {
+			return []int{i}
+		}
+		return nil
+	}
+
+	if i := indRefObjNr(o); i > 0 {
+		return []int{i}
+	}
+
+	return nil
+}
+
+// ocStates returns the visibility of all OCGs according to the optional content configuration d.
+func ocStates(ctx *model.Context, ocgs types.Array, d types.Dict) map[int]bool {
+	base := true
+	if n := d.NameEntry("BaseState"); n != nil && *n == "OFF" {
+		base = false
+	}
+
+	m := map[int]bool{}
+	for _, o := range ocgs {
+		if i := indRefObjNr(o); i > 0 {
+			m[i] = base
+		}
+	}
+
+	for k, on := range map[string]bool{"ON": true, "OFF": false} {
+		a, err := ctx.DereferenceArray(d[k])
+		if err != nil {
+			continue
+		}
+		for _, o := range a {
+			if i := indRefObjNr(o); i > 0 {
+				m[i] = on
+			}
+		}
+	}
+
+	return m
+}
+
+func visibilityExpression(ctx *model.Context, a types.Array, states map[int]bool, depth int) bool {
+	if len(a) == 0 || depth > 100 {
+		return true
+	}
+
+	op, ok := a[0].(types.Name)
+	if !ok {
+		return true
+	}
+
+	var vv []bool
+	for _, o := range a[1:] {
+		if i := indRefObjNr(o); i > 0 {
+			if a1, err := ctx.DereferenceArray(o); err == nil && a1 != nil {
+				vv = append(vv, visibilityExpression(ctx, a1, states, depth+1))
+				continue
+			}
+			v, found := states[i]
+			vv = append(vv, v || !found)
+			continue
+		}
+		if a1, ok := o.(types.Array); ok {
+			vv = append(vv, visibilityExpression(ctx, a1, states, depth+1))
+		}
+	}
+
+	switch op {
+	case "Not":
+		return len(vv) == 0 || !vv[0]
+	case "And":
+		for _, v := range vv {
+			if !v {
+				return false
+			}
+		}
+		return true
+	case "Or":
+		for _, v := range vv {
+			if v {
+				return true
+			}
+		}
+		return len(vv) == 0
+	}
+
+	return true
+}
+
+// ocVisible returns the visibility of content associated with the optional content group or membership dict o, see 8.11.2.2.
+func ocVisible(ctx *model.Context, o types.Object, states map[int]bool) bool {
+	d, err := ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return true
+	}
+
+	if t := d.Type(); t == nil || *t != "OCMD" {
+		v, found := states[indRefObjNr(o)]
+		return v || !found
+	}
+
+	if a, err := ctx.DereferenceArray(d["VE"]); err == nil && len(a) > 0 {
+		return visibilityExpression(ctx, a, states, 0)
+	}
+
+	ii := ocgObjNrs(ctx, d)
+	if len(ii) == 0 {
+		return true
+	}
+
+	p := "AnyOn"
+	if n := d.NameEntry("P"); n != nil {
+		p = *n
+	}
+
+	on := 0
+	for _, i := range ii {
+		if v, found := states[i]; v || !found {
+			on++
+		}
+	}
+
+	switch p {
+	case "AllOn":
+		return on == len(ii)
+	case "AnyOff":
+		return on < len(ii)
+	case "AllOff":
+		return on == 0
+	}
+
+	return on > 0
+}
+
+// layerProcessor rewrites content associated with optional content.
+type layerProcessor struct {
+	ctx       *model.Context
+	action    func(o types.Object) ocAction // o is an optional content group or membership dict.
+	forms     types.IntSet
+	resources []types.Dict // resources of rewritten content
+}
+
+type markedContent struct {
+	remove bool // remove BMC/BDC and corresponding EMC
+	drop   bool // content gets dropped
+}
+
+func (lp *layerProcessor) membership(operands []types.Object, res types.Dict) types.Object {
+	if len(operands) != 2 {
+		return nil
+	}
+
+	if n, ok := operands[0].(types.Name); !ok || n != "OC" {
+		return nil
+	}
+
+	switch o := operands[1].(type) {
+
+	case types.Dict:
+		return o
+
+	case types.Name:
+		d, err := lp.ctx.DereferenceDict(res["Properties"])
+		if err != nil || d == nil {
+			return nil
+		}
+		o1, _ := d.Find(o.Value())
+		return o1
+	}
+
+	return nil
+}
+
+// processXObject handles the Do operator and returns false if the XObject needs to be dropped.
+func (lp *layerProcessor) processXObject(operands []types.Object, res types.Dict) (bool, error) {
+	if len(operands) == 0 {
+		return true, nil
+	}
+	n, ok := operands[len(operands)-1].(types.Name)
+	if !ok {
+		return true, nil
+	}
+
+	d, err := lp.ctx.DereferenceDict(res["XObject"])
+	if err != nil || d == nil {
+		return true, err
+	}
+
+	o, found := d.Find(n.Value())
+	if !found {
+		return true, nil
+	}
+
+	sd, _, err := lp.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return true, err
+	}
+
+	if o1, found := sd.Find("OC"); found {
+		switch lp.action(o1) {
+		case ocDrop:
+			return false, nil
+		case ocUnwrap:
+			sd.Delete("OC")
+		}
+	}
+
+	if st := sd.Subtype(); st != nil && *st == "Form" {
+		if err := lp.processForm(o); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// processForm rewrites the content of the form XObject o in place.
+func (lp *layerProcessor) processForm(o types.Object) error {
+	i := indRefObjNr(o)
+	if i == 0 || lp.forms[i] {
+		return nil
+	}
+	lp.forms[i] = true
+
+	entry, ok := lp.ctx.FindTableEntryLight(i)
+	if !ok {
+		return nil
+	}
+	sd, ok := entry.Object.(types.StreamDict)
+	if !ok {
+		return nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	res, err := lp.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil {
+		return err
+	}
+
+	bb, changed, err := lp.processContent(sd.Content, res)
+	if err != nil || !changed {
+		return err
+	}
+
+	sd.Content = bb
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	entry.Object = sd
+
+	if res != nil {
+		lp.resources = append(lp.resources, res)
+	}
+
+	return nil
+}
+
+// processContent rewrites content bb using resources res.
+func (lp *layerProcessor) processContent(bb []byte, res types.Dict) ([]byte, bool, error) {
+	var (
+		ops     []model.ContentOperation
+		stack   []markedContent
+		drop    int
+		hidden  hiddenContent
+		changed bool
+	)
+
+	p := model.NewContentParser(bb)
+
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch op.Operator {
+
+		case model.OpBeginMarkedContent, model.OpBeginMarkedContentProps:
+			mc := markedContent{remove: drop > 0}
+			if drop == 0 && op.Operator == model.OpBeginMarkedContentProps {
+				if o := lp.membership(op.Operands, res); o != nil {
+					switch lp.action(o) {
+					case ocUnwrap:
+						mc.remove = true
+					case ocDrop:
+						mc.remove, mc.drop = true, true
+						drop++
+					}
+				}
+			}
+			stack = append(stack, mc)
+			if mc.remove {
+				changed = true
+				continue
+			}
+
+		case model.OpEndMarkedContent:
+			if len(stack) > 0 {
+				mc := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if mc.drop {
+					drop--
+				}
+				if mc.remove {
+					continue
+				}
+			}
+
+		case model.OpPaintXObject:
+			if drop > 0 {
+				continue
+			}
+			keep, err := lp.processXObject(op.Operands, res)
+			if err != nil {
+				return nil, false, err
+			}
+			if !keep {
+				changed = true
+				continue
+			}
+		}
+
+		if drop > 0 {
+			changed = true
+			ops = append(ops, hidden.operations(op)...)
+			continue
+		}
+
+		ops = append(ops, *op)
+	}
+
+	if !changed {
+		return bb, false, nil
+	}
+
+	return model.ContentStreamBytes(ops), true, nil
+}
+
+// hiddenContent filters the operations of hidden optional content.
+// Painting gets suppressed whereas the graphics state still applies, see 8.11.3.2.
+type hiddenContent struct {
+	path []model.ContentOperation // Current path under construction.
+	clip bool                     // The current path sets the clipping path.
+}
+
+// operations returns what remains of op.
+func (hc *hiddenContent) operations(op *model.ContentOperation) []model.ContentOperation {
+	switch op.Operator {
+
+	case model.OpMoveTo, model.OpLineTo, model.OpCurveTo, model.OpCurveToV, model.OpCurveToY, model.OpClosePath, model.OpRectangle:
+		hc.path = append(hc.path, *op)
+		return nil
+
+	case model.OpClip, model.OpClipEvenOdd:
+		hc.path = append(hc.path, *op)
+		hc.clip = true
+		return nil
+
+	case model.OpMoveShowText:
+		return []model.ContentOperation{{Operator: model.OpNextLine}}
+
+	case model.OpMoveSetShowText:
+		if len(op.Operands) != 3 {
+			return nil
+		}
+		return []model.ContentOperation{
+			{Operator: model.OpSetWordSpacing, Operands: op.Operands[:1]},
+			{Operator: model.OpSetCharSpacing, Operands: op.Operands[1:2]},
+			{Operator: model.OpNextLine},
+		}
+
+	case model.OpShowText, model.OpShowTextArray, model.OpShade, model.OpBeginInlineImage:
+		return nil
+	}
+
+	if op.Operator != model.OpEndPath && !op.Operator.Paints() {
+		return []model.ContentOperation{*op}
+	}
+
+	// Path painting: keep a path only for its clipping.
+	path, clip := hc.path, hc.clip
+	hc.path, hc.clip = nil, false
+	if !clip {
+		return nil
+	}
+
+	return append(path, model.ContentOperation{Operator: model.OpEndPath})
+}
+
+// cleanupProperties removes property list entries which are no longer referenced by content.
+func (lp *layerProcessor) cleanupProperties(res types.Dict) error {
+	d, err := lp.ctx.DereferenceDict(res["Properties"])
+	if err != nil || d == nil {
+		return err
+	}
+
+	for k, o := range d {
+		d1, err := lp.ctx.DereferenceDict(o)
+		if err != nil || d1 == nil {
+			continue
+		}
+		if t := d1.Type(); t == nil || (*t != "OCG" && *t != "OCMD") {
+			continue
+		}
+		if lp.action(o) != ocKeep {
+			d.Delete(k)
+		}
+	}
+
+	return nil
+}
+
+func (lp *layerProcessor) processAnnotations(d types.Dict, pageObjNr, pageNr int) error {
+	a, err := lp.ctx.DereferenceArray(d["Annots"])
+	if err != nil || len(a) == 0 {
+		return err
+	}
+
+	objNrs := types.IntSet{}
+
+	for _, o := range a {
+		d1, err := lp.ctx.DereferenceDict(o)
+		if err != nil || d1 == nil {
+			continue
+		}
+		o1, found := d1.Find("OC")
+		if !found {
+			continue
+		}
+		switch lp.action(o1) {
+		case ocDrop:
+			if i := indRefObjNr(o); i > 0 {
+				objNrs[i] = true
+			}
+		case ocUnwrap:
+			d1.Delete("OC")
+		}
+	}
+
+	if len(objNrs) == 0 {
+		return nil
+	}
+
+	_, err = RemoveAnnotationsFromPageDict(lp.ctx, nil, nil, objNrs, d, pageObjNr, pageNr, false)
+	return err
+}
+
+func (lp *layerProcessor) processPage(pageNr int) error {
+	d, pageIndRef, inhPAttrs, err := lp.ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return errors.Errorf("pdfcpu: unknown page number: %d", pageNr)
+	}
+
+	if err := lp.processAnnotations(d, pageIndRef.ObjectNumber.Value(), pageNr); err != nil {
+		return err
+	}
+
+	bb, err := lp.ctx.PageContent(d)
+	if err == model.ErrNoContent {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	bb, changed, err := lp.processContent(bb, inhPAttrs.Resources)
+	if err != nil || !changed {
+		return err
+	}
+
+	sd, _ := lp.ctx.NewStreamDictForBuf(bb)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	ir, err := lp.ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d["Contents"] = *ir
+
+	if inhPAttrs.Resources != nil {
+		lp.resources = append(lp.resources, inhPAttrs.Resources)
+	}
+
+	return nil
+}
+
+func (lp *layerProcessor) process() error {
+	lp.forms = types.IntSet{}
+	for pageNr := 1; pageNr <= lp.ctx.PageCount; pageNr++ {
+		if err := lp.processPage(pageNr); err != nil {
+			return err
+		}
+	}
+
+	// Property lists may be shared across pages and forms.
+	for _, res := range lp.resources {
+		if err := lp.cleanupProperties(res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pageLayers returns the object numbers of the OCGs referenced by a page, its XObjects and annotations.
+func pageLayers(ctx *model.Context, pageNr int) (types.IntSet, error) {
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	m := types.IntSet{}
+	add := func(o types.Object) {
+		for _, i := range ocgObjNrs(ctx, o) {
+			m[i] = true
+		}
+	}
+
+	if res := inhPAttrs.Resources; res != nil {
+		if d1, err := ctx.DereferenceDict(res["Properties"]); err == nil {
+			for _, o := range d1 {
+				add(o)
+			}
+		}
+		if d1, err := ctx.DereferenceDict(res["XObject"]); err == nil {
+			for _, o := range d1 {
+				if sd, _, err := ctx.DereferenceStreamDict(o); err == nil && sd != nil {
+					if o1, found := sd.Find("OC"); found {
+						add(o1)
+					}
+				}
+			}
+		}
+	}
+
+	if a, err := ctx.DereferenceArray(d["Annots"]); err == nil {
+		for _, o := range a {
+			if d1, err := ctx.DereferenceDict(o); err == nil && d1 != nil {
+				if o1, found := d1.Find("OC"); found {
+					add(o1)
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// Layers returns all optional content groups of ctx.
+func Layers(ctx *model.Context) ([]Layer, error) {
+	d, err := ocProperties(ctx)
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	ocgs, err := ctx.DereferenceArray(d["OCGs"])
+	if err != nil || len(ocgs) == 0 {
+		return nil, err
+	}
+
+	d1, err := ctx.DereferenceDict(d["D"])
+	if err != nil {
+		return nil, err
+	}
+
+	states := ocStates(ctx, ocgs, d1)
+
+	locked := types.IntSet{}
+	if a, err := ctx.DereferenceArray(d1["Locked"]); err == nil {
+		for _, o := range a {
+			locked[indRefObjNr(o)] = true
+		}
+	}
+
+	pages := map[int][]int{}
+	for pageNr := 1; pageNr <= ctx.PageCount; pageNr++ {
+		m, err := pageLayers(ctx, pageNr)
+		if err != nil {
+			return nil, err
+		}
+		for i := range m {
+			pages[i] = append(pages[i], pageNr)
+		}
+	}
+
+	var ll []Layer
+
+	for _, o := range ocgs {
+		i := indRefObjNr(o)
+		if i == 0 {
+			continue
+		}
+		ocg, err := ctx.DereferenceDict(o)
+		if err != nil || ocg == nil {
+			continue
+		}
+		name, err := ctx.DereferenceText(ocg["Name"])
+		if err != nil {
+			name = ""
+		}
+		ll = append(ll, Layer{
+			Name:    name,
+			ObjNr:   i,
+			Visible: states[i],
+			Locked:  locked[i],
+			Pages:   pages[i],
+		})
+	}
+
+	return ll, nil
+}
+
+func pageRanges(ii []int) string {
+	var ss []string
+	for i := 0; i < len(ii); {
+		j := i
+		for j+1 < len(ii) && ii[j+1] == ii[j]+1 {
+			j++
+		}
+		if j == i {
+			ss = append(ss, fmt.Sprintf("%d", ii[i]))
+		} else {
+			ss = append(ss, fmt.Sprintf("%d-%d", ii[i], ii[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ss, ",")
+}
+
+// ListLayers returns a list representation of all optional content groups of ctx.
+func ListLayers(ctx *model.Context) ([]string, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ll) == 0 {
+		return []string{"no layers available"}, nil
+	}
+
+	maxLen := len("Name")
+	for _, l := range ll {
+		maxLen = max(maxLen, len(l.Name))
+	}
+
+	ss := []string{fmt.Sprintf("%-*s   obj#  visible  locked  pages", maxLen, "Name")}
+	ss = append(ss, strings.Repeat("=", maxLen+32))
+
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+
+	for _, l := range ll {
+		ss = append(ss, fmt.Sprintf("%-*s %6d  %-7s  %-6s  %s", maxLen, l.Name, l.ObjNr, yesNo(l.Visible), yesNo(l.Locked), pageRanges(l.Pages)))
+	}
+
+	return ss, nil
+}
+
+// findLayer returns the first optional content group named name or nil.
+func findLayer(ctx *model.Context, name string) (*types.IndirectRef, error) {
+	d, err := ocProperties(ctx)
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	ocgs, err := ctx.DereferenceArray(d["OCGs"])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range ocgs {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+		ocg, err := ctx.DereferenceDict(indRef)
+		if err != nil || ocg == nil {
+			continue
+		}
+		if s, err := ctx.DereferenceText(ocg["Name"]); err == nil && s == name {
+			return &indRef, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// addUsageApplications makes viewers apply the usage dict of ocg when viewing, printing and exporting, see 8.11.4.4.
+func addUsageApplications(ctx *model.Context, ocg types.IndirectRef) error {
+	d, err := ocProperties(ctx)
+	if err != nil || d == nil {
+		return err
+	}
+
+	d1, err := ctx.DereferenceDict(d["D"])
+	if err != nil || d1 == nil {
+		return err
+	}
+
+	a, err := ctx.DereferenceArray(d1["AS"])
+	if err != nil {
+		return err
+	}
+
+	for _, event := range []string{"View", "Print", "Export"} {
+		var app types.Dict
+		for _, o := range a {
+			d2, err := ctx.DereferenceDict(o)
+			if err != nil || d2 == nil {
+				continue
+			}
+			if n := d2.NameEntry("Event"); n != nil && *n == event {
+				app = d2
+				break
+			}
+		}
+
+		if app == nil {
+			app = types.Dict(map[string]types.Object{
+				"Event":    types.Name(event),
+				"Category": types.NewNameArray(event),
+				"OCGs":     types.Array{},
+			})
+			a = append(a, app)
+		}
+
+		ocgs, err := ctx.DereferenceArray(app["OCGs"])
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, o := range ocgs {
+			if indRefObjNr(o) == ocg.ObjectNumber.Value() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			app["OCGs"] = append(ocgs, ocg)
+		}
+	}
+
+	d1["AS"] = a
+
+	return nil
+}
+
+// layerObjNrs returns the object numbers of the OCGs matching names.
+func layerObjNrs(ctx *model.Context, names []string) (types.IntSet, error) {
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m := types.IntSet{}
+
+	for _, name := range names {
+		found := false
+		for _, l := range ll {
+			if l.Name == name {
+				m[l.ObjNr] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("pdfcpu: unknown layer: %s", name)
+		}
+	}
+
+	return m, nil
+}
+
+// AddLayer adds a new empty optional content group to ctx and returns its indirect reference.
+func AddLayer(ctx *model.Context, name string, visible bool) (*types.IndirectRef, error) {
+	if name == "" {
+		return nil, errors.New("pdfcpu: missing layer name")
+	}
+
+	ll, err := Layers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range ll {
+		if l.Name == name {
+			return nil, errors.Errorf("pdfcpu: layer already exists: %s", name)
+		}
+	}
+
+	d, err := ocProperties(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if d == nil {
+		rootDict, err := ctx.Catalog()
+		if err != nil {
+			return nil, err
+		}
+		d = types.Dict(map[string]types.Object{
+			"OCGs": types.Array{},
+			"D":    types.Dict(map[string]types.Object{"Order": types.Array{}}),
+		})
+		rootDict["OCProperties"] = d
+	}
+
+	s, err := types.EscapedUTF16String(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ir, err := ctx.IndRefForNewObject(types.Dict(map[string]types.Object{
+		"Type": types.Name("OCG"),
+		"Name": types.StringLiteral(*s),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	ocgs, err := ctx.DereferenceArray(d["OCGs"])
+	if err != nil {
+		return nil, err
+	}
+	d["OCGs"] = append(ocgs, *ir)
+
+	d1, err := ctx.DereferenceDict(d["D"])
+	if err != nil {
+		return nil, err
+	}
+	if d1 == nil {
+		d1 = types.Dict{}
+		d["D"] = d1
+	}
+
+	order, err := ctx.DereferenceArray(d1["Order"])
+	if err != nil {
+		return nil, err
+	}
+	d1["Order"] = append(order, *ir)
+
+	if err := setLayerState(ctx, d1, types.IntSet{ir.ObjectNumber.Value(): true}, visible); err != nil {
+		return nil, err
+	}
+
+	return ir, nil
+}
+
+// removeIndRefs returns a copy of a without references to objNrs, descending into nested arrays.
+func removeIndRefs(a types.Array, objNrs types.IntSet) types.Array {
+	a1 := types.Array{}
+	for _, o := range a {
+		if objNrs[indRefObjNr(o)] {
+			continue
+		}
+		if a2, ok := o.(types.Array); ok {
+			o = removeIndRefs(a2, objNrs)
+		}
+		a1 = append(a1, o)
+	}
+	return a1
+}
+
+func setLayerState(ctx *model.Context, d types.Dict, objNrs types.IntSet, visible bool) error {
+	for _, k := range []string{"ON", "OFF"} {
+		a, err := ctx.DereferenceArray(d[k])
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			d[k] = removeIndRefs(a, objNrs)
+		}
+	}
+
+	k := "OFF"
+	if visible {
+		k = "ON"
+	}
+
+	a, _ := d[k].(types.Array)
+
+	var ii []int
+	for i := range objNrs {
+		ii = append(ii, i)
+	}
+	sort.Ints(ii)
+	for _, i := range ii {
+		a = append(a, *types.NewIndirectRef(i, 0))
+	}
+
+	d[k] = a
+
+	return nil
+}
+
+// SetLayerVisibility sets the default visibility of layers matching names.
+func SetLayerVisibility(ctx *model.Context, names []string, visible bool) error {
+	objNrs, err := layerObjNrs(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	d, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	d1, err := ctx.DereferenceDict(d["D"])
+	if err != nil {
+		return err
+	}
+	if d1 == nil {
+		d1 = types.Dict{}
+		d["D"] = d1
+	}
+
+	return setLayerState(ctx, d1, objNrs, visible)
+}
+
+// removeLayersFromConfig removes all references to objNrs from the optional content configuration d.
+func removeLayersFromConfig(ctx *model.Context, d types.Dict, objNrs types.IntSet) error {
+	for _, k := range []string{"ON", "OFF", "Locked", "Order", "RBGroups"} {
+		a, err := ctx.DereferenceArray(d[k])
+		if err != nil {
+			return err
+		}
+		if a != nil {
+			d[k] = removeIndRefs(a, objNrs)
+		}
+	}
+
+	a, err := ctx.DereferenceArray(d["AS"])
+	if err != nil || a == nil {
+		return err
+	}
+
+	for _, o := range a {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil || d1 == nil {
+			continue
+		}
+		a1, err := ctx.DereferenceArray(d1["OCGs"])
+		if err == nil && a1 != nil {
+			d1["OCGs"] = removeIndRefs(a1, objNrs)
+		}
+	}
+
+	return nil
+}
+
+// RemoveLayers deletes the layers matching names along with their content.
+func RemoveLayers(ctx *model.Context, names []string) error {
+	objNrs, err := layerObjNrs(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	action := func(o types.Object) ocAction {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			return ocKeep
+		}
+
+		if t := d.Type(); t == nil || *t != "OCMD" {
+			if objNrs[indRefObjNr(o)] {
+				return ocDrop
+			}
+			return ocKeep
+		}
+
+		ii := ocgObjNrs(ctx, d)
+		if len(ii) == 0 {
+			return ocKeep
+		}
+
+		var a types.Array
+		for _, i := range ii {
+			if !objNrs[i] {
+				a = append(a, *types.NewIndirectRef(i, 0))
+			}
+		}
+		if len(a) == 0 {
+			return ocDrop
+		}
+		if len(a) < len(ii) {
+			d["OCGs"] = a
+		}
+
+		return ocKeep
+	}
+
+	lp := layerProcessor{ctx: ctx, action: action}
+	if err := lp.process(); err != nil {
+		return err
+	}
+
+	d, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+
+	ocgs, err := ctx.DereferenceArray(d["OCGs"])
+	if err != nil {
+		return err
+	}
+	ocgs = removeIndRefs(ocgs, objNrs)
+
+	if len(ocgs) == 0 {
+		rootDict, err := ctx.Catalog()
+		if err != nil {
+			return err
+		}
+		rootDict.Delete("OCProperties")
+		return nil
+	}
+
+	d["OCGs"] = ocgs
+
+	configs := []types.Object{d["D"]}
+	if a, err := ctx.DereferenceArray(d["Configs"]); err == nil {
+		configs = append(configs, a...)
+	}
+
+	for _, o := range configs {
+		d1, err := ctx.DereferenceDict(o)
+		if err != nil || d1 == nil {
+			continue
+		}
+		if err := removeLayersFromConfig(ctx, d1, objNrs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FlattenLayers merges the content of visible layers into the page content,
+// drops the content of hidden layers and removes all optional content.
+func FlattenLayers(ctx *model.Context) error {
+	d, err := ocProperties(ctx)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return nil
+	}
+
+	ocgs, err := ctx.DereferenceArray(d["OCGs"])
+	if err != nil {
+		return err
+	}
+
+	d1, err := ctx.DereferenceDict(d["D"])
+	if err != nil {
+		return err
+	}
+
+	states := ocStates(ctx, ocgs, d1)
+
+	action := func(o types.Object) ocAction {
+		if ocVisible(ctx, o, states) {
+			return ocUnwrap
+		}
+		return ocDrop
+	}
+
+	lp := layerProcessor{ctx: ctx, action: action}
+	if err := lp.process(); err != nil {
+		return err
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+	rootDict.Delete("OCProperties")
+
+	return nil
+}
@@ -0,0 +1,906 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Linearized files as specified in ISO 32000-1 Annex F are laid out like so:
+//
+//	header
+//	linearization parameter dict
+//	first-page cross reference section and trailer
+//	catalog and other objects needed for opening the document
+//	primary hint stream
+//	first page section
+//	remaining pages
+//	shared objects
+//	other objects
+//	main cross reference section and trailer
+//
+// Objects of the main part get numbered first, followed by the objects of the first-page part.
+// Objects are written without using object streams.
+
+const maxLayoutPasses = 10
+
+var inheritablePageAttrs = []string{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+// linObject is an object of a linearized file.
+type linObject struct {
+	objNr  int          // Object number in the linearized file.
+	obj    types.Object // Renumbered object.
+	prefix string       // Object header and object, stream dict for streams.
+	size   int64        // Size of the written object.
+	offset int64        // Offset of the written object.
+}
+
+type linearizer struct {
+	ctx        *model.Context
+	pages      []types.IndirectRef // Page objects in page order.
+	stop       types.IntSet        // Page objects, page tree nodes and the catalog.
+	inherited  map[int]types.Dict  // Inherited page attributes by page object number.
+	part4      []int               // Catalog and objects needed for opening the document.
+	part6      []int               // First page section.
+	pageObjs   [][]int             // Page object followed by its private objects for pages 2..n.
+	shared     []int               // Shared objects of pages 2..n.
+	other      []int               // Remaining objects.
+	sharedRefs [][]int             // Shared object identifiers of pages 2..n.
+	renum      map[int]int         // Object numbers of the linearized file by source object number.
+	objs       map[int]*linObject  // Objects to be written by source object number.
+	lin, hint  *linObject          // Linearization parameter dict and primary hint stream.
+	firstXRef  string              // First-page cross reference section and trailer.
+	mainXRef   string              // Main cross reference section and trailer.
+	eol        string
+}
+
+func sortedKeys(d types.Dict) []string {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pageTree collects page objects, page tree nodes and inherited page attributes.
+func (l *linearizer) pageTree(o types.Object, inherited types.Dict) error {
+	ir, ok := o.(types.IndirectRef)
+	if !ok {
+		return errors.New("pdfcpu: linearize: corrupt page tree")
+	}
+
+	objNr := ir.ObjectNumber.Value()
+	if l.stop[objNr] {
+		return errors.Errorf("pdfcpu: linearize: page tree object #%d used more than once", objNr)
+	}
+	l.stop[objNr] = true
+
+	d, err := l.ctx.DereferenceDict(ir)
+	if err != nil || d == nil {
+		return errors.Errorf("pdfcpu: linearize: corrupt page tree node #%d", objNr)
+	}
+
+	attrs := types.Dict{}
+	for _, k := range inheritablePageAttrs {
+		if o, ok := d.Find(k); ok {
+			attrs[k] = o
+		} else if o, ok := inherited[k]; ok {
+			attrs[k] = o
+		}
+	}
+
+	if _, ok := d.Find("Kids"); !ok {
+		l.pages = append(l.pages, ir)
+		inh := types.Dict{}
+		for k, o := range attrs {
+			if _, ok := d.Find(k); !ok {
+				inh[k] = o
+			}
+		}
+		l.inherited[objNr] = inh
+		return nil
+	}
+
+	kids, err := l.ctx.DereferenceArray(d["Kids"])
+	if err != nil {
+		return err
+	}
+
+	for _, o := range kids {
+		if err := l.pageTree(o, attrs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collect appends the object numbers of all unseen objects reachable from o in depth-first order.
+func (l *linearizer) collect(o types.Object, seen types.IntSet, objNrs *[]int) error {
+	switch o := o.(type) {
+
+	case types.IndirectRef:
+		objNr := o.ObjectNumber.Value()
+		if seen[objNr] {
+			return nil
+		}
+		o1, err := l.ctx.Dereference(o)
+		if err != nil {
+			return err
+		}
+		if o1 == nil {
+			return nil
+		}
+		seen[objNr] = true
+		*objNrs = append(*objNrs, objNr)
+		return l.collect(o1, seen, objNrs)
+
+	case types.Dict:
+		for _, k := range sortedKeys(o) {
+			if err := l.collect(o[k], seen, objNrs); err != nil {
+				return err
+			}
+		}
+
+	case types.StreamDict:
+		// Stream lengths get written as direct objects.
+		for _, k := range sortedKeys(o.Dict) {
+			if k == "Length" {
+				continue
+			}
+			if err := l.collect(o.Dict[k], seen, objNrs); err != nil {
+				return err
+			}
+		}
+
+	case types.Array:
+		for _, o1 := range o {
+			if err := l.collect(o1, seen, objNrs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// pageObjects returns the page object of page i followed by all objects reachable from this page
+// excluding page tree objects and the catalog.
+func (l *linearizer) pageObjects(i int) ([]int, error) {
+	ir := l.pages[i]
+	objNr := ir.ObjectNumber.Value()
+
+	d, err := l.ctx.DereferenceDict(ir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := types.IntSet{}
+	for k := range l.stop {
+		seen[k] = true
+	}
+
+	objNrs := []int{objNr}
+
+	for _, k := range sortedKeys(d) {
+		if k == "Parent" {
+			continue
+		}
+		if err := l.collect(d[k], seen, &objNrs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := l.collect(l.inherited[objNr], seen, &objNrs); err != nil {
+		return nil, err
+	}
+
+	return objNrs, nil
+}
+
+// partition distributes all objects reachable from the catalog over the parts of the linearized file.
+func (l *linearizer) partition(rootDict types.Dict) error {
+	rootNr := l.ctx.Root.ObjectNumber.Value()
+	assigned := types.IntSet{rootNr: true}
+
+	seen := types.IntSet{}
+	for k := range l.stop {
+		seen[k] = true
+	}
+
+	l.part4 = []int{rootNr}
+	for _, k := range []string{"ViewerPreferences", "OpenAction"} {
+		if err := l.collect(rootDict[k], seen, &l.part4); err != nil {
+			return err
+		}
+	}
+	if l.ctx.Encrypt != nil && l.ctx.EncKey != nil {
+		if err := l.collect(*l.ctx.Encrypt, seen, &l.part4); err != nil {
+			return err
+		}
+	}
+	for _, objNr := range l.part4 {
+		assigned[objNr] = true
+	}
+
+	objNrs, err := l.pageObjects(0)
+	if err != nil {
+		return err
+	}
+	for _, objNr := range objNrs {
+		if !assigned[objNr] {
+			l.part6 = append(l.part6, objNr)
+			assigned[objNr] = true
+		}
+	}
+
+	if pm := rootDict.NameEntry("PageMode"); pm != nil && *pm == "UseOutlines" {
+		for k := range assigned {
+			seen[k] = true
+		}
+		if err := l.collect(rootDict["Outlines"], seen, &l.part6); err != nil {
+			return err
+		}
+		for _, objNr := range l.part6 {
+			assigned[objNr] = true
+		}
+	}
+
+	// Objects referenced by more than one of the remaining pages are shared.
+	refs := make([][]int, len(l.pages)-1)
+	count := map[int]int{}
+	for i := 1; i < len(l.pages); i++ {
+		objNrs, err := l.pageObjects(i)
+		if err != nil {
+			return err
+		}
+		refs[i-1] = objNrs
+		for _, objNr := range objNrs[1:] {
+			if !assigned[objNr] {
+				count[objNr]++
+			}
+		}
+	}
+
+	isShared := types.IntSet{}
+	for _, objNrs := range refs {
+		objs := []int{objNrs[0]}
+		for _, objNr := range objNrs[1:] {
+			switch {
+			case assigned[objNr]:
+			case count[objNr] == 1:
+				objs = append(objs, objNr)
+			case !isShared[objNr]:
+				l.shared = append(l.shared, objNr)
+				isShared[objNr] = true
+			}
+		}
+		l.pageObjs = append(l.pageObjs, objs)
+	}
+
+	for _, objNrs := range l.pageObjs {
+		for _, objNr := range objNrs {
+			assigned[objNr] = true
+		}
+	}
+	for _, objNr := range l.shared {
+		assigned[objNr] = true
+	}
+
+	// Shared object identifiers index the first page section followed by the shared objects section.
+	ids := map[int]int{}
+	for i, objNr := range l.part6 {
+		ids[objNr] = i
+	}
+	for i, objNr := range l.shared {
+		ids[objNr] = len(l.part6) + i
+	}
+	for _, objNrs := range refs {
+		var rr []int
+		for _, objNr := range objNrs[1:] {
+			if id, ok := ids[objNr]; ok {
+				rr = append(rr, id)
+			}
+		}
+		l.sharedRefs = append(l.sharedRefs, rr)
+	}
+
+	// Page tree nodes, document outline, name trees, forms, structure tree, document info etc.
+	for _, k := range sortedKeys(rootDict) {
+		if err := l.collect(rootDict[k], assigned, &l.other); err != nil {
+			return err
+		}
+	}
+	if l.ctx.Info != nil {
+		if err := l.collect(*l.ctx.Info, assigned, &l.other); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renumber assigns the object numbers of the linearized file.
+func (l *linearizer) renumber() int {
+	objNr := 1
+	next := func(objNrs []int) {
+		for _, i := range objNrs {
+			l.renum[i] = objNr
+			objNr++
+		}
+	}
+
+	for _, objNrs := range l.pageObjs {
+		next(objNrs)
+	}
+	next(l.shared)
+	next(l.other)
+
+	// The linearization dict is the first object of the first-page part.
+	firstObjNr := objNr
+	objNr++
+	next(l.part4)
+	objNr++ // primary hint stream
+	next(l.part6)
+
+	return firstObjNr
+}
+
+func (l *linearizer) renumbered(o types.Object) types.Object {
+	switch o := o.(type) {
+
+	case types.IndirectRef:
+		// References to objects not written resolve to null.
+		objNr, ok := l.renum[o.ObjectNumber.Value()]
+		if !ok {
+			return nil
+		}
+		return *types.NewIndirectRef(objNr, 0)
+
+	case types.Dict:
+		d := types.NewDict()
+		for k, v := range o {
+			d[k] = l.renumbered(v)
+		}
+		return d
+
+	case types.StreamDict:
+		sd := o
+		if sd.StreamLength == nil {
+			l := int64(len(sd.Raw))
+			sd.StreamLength = &l
+		}
+		sd.Dict = l.renumbered(o.Dict).(types.Dict)
+		sd.Dict["Length"] = types.Integer(*sd.StreamLength)
+		sd.StreamLengthObjNr = nil
+		return sd
+
+	case types.Array:
+		a := make(types.Array, len(o))
+		for i, v := range o {
+			a[i] = l.renumbered(v)
+		}
+		return a
+	}
+
+	return o
+}
+
+func (l *linearizer) encrypt(lo *linObject) error {
+	ctx := l.ctx
+
+	if sd, ok := lo.obj.(types.StreamDict); ok {
+		// Unless the "Identity" crypt filter is used we have to encrypt.
+		if !(len(sd.FilterPipeline) == 1 && sd.FilterPipeline[0].Name == "Crypt") {
+			raw, err := sd.RawContent()
+			if err != nil {
+				return err
+			}
+			if sd.Raw, err = encryptStream(raw, lo.objNr, 0, ctx.EncKey, ctx.AES4Streams, ctx.E.R); err != nil {
+				return err
+			}
+			l := int64(len(sd.Raw))
+			sd.StreamLength = &l
+			sd.Dict["Length"] = types.Integer(l)
+		}
+		lo.obj = sd
+	}
+
+	o, err := encryptDeepObject(lo.obj, lo.objNr, 0, ctx.EncKey, ctx.AES4Strings, ctx.E.R)
+	if err != nil {
+		return err
+	}
+	if o != nil {
+		lo.obj = o
+	}
+
+	return nil
+}
+
+// serialize prepares lo for writing.
+func (l *linearizer) serialize(lo *linObject) {
+	var s string
+	if lo.obj == nil {
+		s = "null"
+	} else {
+		s = lo.obj.PDFString()
+	}
+	lo.prefix = fmt.Sprintf("%d 0 obj%s%s", lo.objNr, l.eol, s)
+
+	lo.size = int64(len(lo.prefix) + len(l.eol+"endobj"+l.eol))
+	if sd, ok := lo.obj.(types.StreamDict); ok {
+		lo.size += int64(len(l.eol+"stream"+l.eol+l.eol+"endstream")) + *sd.StreamLength
+	}
+}
+
+func (l *linearizer) newObject(objNr int, o types.Object) (*linObject, error) {
+	lo := &linObject{objNr: objNr, obj: o}
+
+	isEncryptDict := l.ctx.Encrypt != nil && l.renum[l.ctx.Encrypt.ObjectNumber.Value()] == objNr
+	if l.ctx.EncKey != nil && !isEncryptDict {
+		if err := l.encrypt(lo); err != nil {
+			return nil, err
+		}
+	}
+
+	l.serialize(lo)
+
+	return lo, nil
+}
+
+// prepareObjects renumbers all objects to be written.
+func (l *linearizer) prepareObjects() error {
+	for objNr, newObjNr := range l.renum {
+		// All objects have been dereferenced during partitioning.
+		o1 := l.renumbered(l.ctx.Table[objNr].Object)
+
+		// Pages carry their inherited attributes.
+		if d, ok := o1.(types.Dict); ok {
+			for k, v := range l.inherited[objNr] {
+				d[k] = l.renumbered(v)
+			}
+		}
+
+		lo, err := l.newObject(newObjNr, o1)
+		if err != nil {
+			return err
+		}
+		l.objs[objNr] = lo
+	}
+
+	return nil
+}
+
+func (l *linearizer) objects(objNrs []int) []*linObject {
+	oo := make([]*linObject, len(objNrs))
+	for i, objNr := range objNrs {
+		oo[i] = l.objs[objNr]
+	}
+	return oo
+}
+
+// sections returns all objects in file order.
+func (l *linearizer) sections() (first []*linObject, main []*linObject) {
+	first = append(first, l.lin)
+	first = append(first, l.objects(l.part4)...)
+	first = append(first, l.hint)
+	first = append(first, l.objects(l.part6)...)
+
+	for _, objNrs := range l.pageObjs {
+		main = append(main, l.objects(objNrs)...)
+	}
+	main = append(main, l.objects(l.shared)...)
+	main = append(main, l.objects(l.other)...)
+
+	return first, main
+}
+
+func (l *linearizer) xRefEntries(oo []*linObject) string {
+	var sb strings.Builder
+	for _, lo := range oo {
+		sb.WriteString(fmt.Sprintf("%010d %05d n%2s", lo.offset, 0, l.eol))
+	}
+	return sb.String()
+}
+
+func (l *linearizer) trailer(d types.Dict, startXRef int64) string {
+	eol := l.eol
+	return "trailer" + eol + d.PDFString() + eol + "startxref" + eol + fmt.Sprintf("%d", startXRef) + eol + "%%EOF" + eol
+}
+
+// bitWriter packs hint table items.
+type bitWriter struct {
+	buf  []byte
+	cur  byte
+	nBit int
+}
+
+func (w *bitWriter) write(v int64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.cur = w.cur<<1 | byte(v>>uint(i)&1)
+		w.nBit++
+		if w.nBit == 8 {
+			w.buf = append(w.buf, w.cur)
+			w.cur, w.nBit = 0, 0
+		}
+	}
+}
+
+// align pads w to the next byte boundary.
+func (w *bitWriter) align() {
+	if w.nBit > 0 {
+		w.write(0, 8-w.nBit)
+	}
+}
+
+func bitsNeeded(i int64) int {
+	return bits.Len64(uint64(i))
+}
+
+func minMax(ii []int64) (int64, int64) {
+	min, max := ii[0], ii[0]
+	for _, i := range ii[1:] {
+		if i < min {
+			min = i
+		}
+		if i > max {
+			max = i
+		}
+	}
+	return min, max
+}
+
+func objectsSize(oo []*linObject) int64 {
+	var size int64
+	for _, lo := range oo {
+		size += lo.size
+	}
+	return size
+}
+
+// writePageOffsetHintTable writes the page offset hint table (see F.4.1).
+// Content stream offsets and lengths refer to whole pages.
+func (l *linearizer) writePageOffsetHintTable(w *bitWriter) {
+	part6 := l.objects(l.part6)
+
+	nObjs := []int64{int64(len(part6))}
+	lengths := []int64{objectsSize(part6)}
+	nShared := []int64{0}
+	var maxID int64
+
+	for i, objNrs := range l.pageObjs {
+		nObjs = append(nObjs, int64(len(objNrs)))
+		lengths = append(lengths, objectsSize(l.objects(objNrs)))
+		nShared = append(nShared, int64(len(l.sharedRefs[i])))
+		for _, id := range l.sharedRefs[i] {
+			maxID = max(maxID, int64(id))
+		}
+	}
+
+	minObjs, maxObjs := minMax(nObjs)
+	minLen, maxLen := minMax(lengths)
+	_, maxShared := minMax(nShared)
+
+	objBits, lenBits := bitsNeeded(maxObjs-minObjs), bitsNeeded(maxLen-minLen)
+	sharedBits, idBits := bitsNeeded(maxShared), bitsNeeded(maxID)
+
+	w.write(minObjs, 32)
+	w.write(part6[0].offset, 32)
+	w.write(int64(objBits), 16)
+	w.write(minLen, 32)
+	w.write(int64(lenBits), 16)
+	w.write(0, 32)
+	w.write(0, 16)
+	w.write(minLen, 32)
+	w.write(int64(lenBits), 16)
+	w.write(int64(sharedBits), 16)
+	w.write(int64(idBits), 16)
+	w.write(0, 16)
+	w.write(1, 16)
+
+	for _, n := range nObjs {
+		w.write(n-minObjs, objBits)
+	}
+	w.align()
+
+	for _, n := range lengths {
+		w.write(n-minLen, lenBits)
+	}
+	w.align()
+
+	for _, n := range nShared {
+		w.write(n, sharedBits)
+	}
+	w.align()
+
+	for _, ids := range l.sharedRefs {
+		for _, id := range ids {
+			w.write(int64(id), idBits)
+		}
+	}
+	w.align()
+
+	// Content stream lengths
+	for _, n := range lengths {
+		w.write(n-minLen, lenBits)
+	}
+	w.align()
+}
+
+// writeSharedObjectHintTable writes the shared object hint table (see F.4.2).
+// Each shared object group consists of a single object.
+func (l *linearizer) writeSharedObjectHintTable(w *bitWriter) {
+	shared := l.objects(l.shared)
+	groups := append(l.objects(l.part6), shared...)
+
+	var firstObjNr, firstOffset int64
+	if len(shared) > 0 {
+		firstObjNr, firstOffset = int64(shared[0].objNr), shared[0].offset
+	}
+
+	lengths := make([]int64, len(groups))
+	for i, lo := range groups {
+		lengths[i] = lo.size
+	}
+	minLen, maxLen := minMax(lengths)
+	lenBits := bitsNeeded(maxLen - minLen)
+
+	w.write(firstObjNr, 32)
+	w.write(firstOffset, 32)
+	w.write(int64(len(l.part6)), 32)
+	w.write(int64(len(groups)), 32)
+	w.write(0, 16)
+	w.write(minLen, 32)
+	w.write(int64(lenBits), 16)
+
+	for _, n := range lengths {
+		w.write(n-minLen, lenBits)
+	}
+	w.align()
+
+	// No MD5 signatures.
+	for range groups {
+		w.write(0, 1)
+	}
+	w.align()
+}
+
+func (l *linearizer) hintStream() (*linObject, error) {
+	w := &bitWriter{}
+	l.writePageOffsetHintTable(w)
+	s := len(w.buf)
+	l.writeSharedObjectHintTable(w)
+
+	// Unfiltered hint streams keep their size while offsets change during layout.
+	sd := types.NewStreamDict(types.Dict{"S": types.Integer(s)}, 0, nil, nil, nil)
+	sd.Content = w.buf
+
+	if err := sd.Encode(); err != nil {
+		return nil, err
+	}
+
+	return l.newObject(l.hint.objNr, sd)
+}
+
+// layout assigns offsets to all objects and generates the linearization dict, hint stream and cross reference sections
+// until all of them are consistent.
+func (l *linearizer) layout(offset int64, firstObjNr int) error {
+	ctx := l.ctx
+	eol := l.eol
+
+	l.lin = &linObject{objNr: firstObjNr}
+	l.hint = &linObject{objNr: firstObjNr + len(l.part4) + 1}
+	l.serialize(l.lin)
+	l.serialize(l.hint)
+
+	first, main := l.sections()
+	size := firstObjNr + len(first)
+
+	for i := 0; i < maxLayoutPasses; i++ {
+
+		off := offset
+		l.lin.offset = off
+		off += l.lin.size
+
+		firstXRefOffset := off
+		off += int64(len(l.firstXRef))
+
+		for _, lo := range first[1:] {
+			lo.offset = off
+			off += lo.size
+		}
+		endOfFirstPage := off
+
+		for _, lo := range main {
+			lo.offset = off
+			off += lo.size
+		}
+
+		mainXRefOffset := off
+		h := fmt.Sprintf("xref%s0 %d%s", eol, firstObjNr, eol)
+		l.mainXRef = h + fmt.Sprintf("%010d %05d f%2s", 0, 65535, eol) + l.xRefEntries(main) +
+			l.trailer(types.Dict{"Size": types.Integer(firstObjNr)}, firstXRefOffset)
+		fileSize := off + int64(len(l.mainXRef))
+
+		hint, err := l.hintStream()
+		if err != nil {
+			return err
+		}
+		hint.offset = l.hint.offset
+
+		lin := &linObject{objNr: l.lin.objNr, offset: l.lin.offset}
+		lin.obj = types.Dict{
+			"Linearized": types.Integer(1),
+			"L":          types.Integer(fileSize),
+			"H":          types.NewIntegerArray(int(l.hint.offset), int(l.hint.size)),
+			"O":          types.Integer(l.renum[l.pages[0].ObjectNumber.Value()]),
+			"E":          types.Integer(endOfFirstPage),
+			"N":          types.Integer(len(l.pages)),
+			"T":          types.Integer(mainXRefOffset + int64(len(h)-1)),
+		}
+		l.serialize(lin)
+
+		d := types.Dict{
+			"Size": types.Integer(size),
+			"Root": *types.NewIndirectRef(l.renum[ctx.Root.ObjectNumber.Value()], 0),
+			"Prev": types.Integer(mainXRefOffset),
+		}
+		if ctx.Info != nil {
+			if objNr, ok := l.renum[ctx.Info.ObjectNumber.Value()]; ok {
+				d["Info"] = *types.NewIndirectRef(objNr, 0)
+			}
+		}
+		if ctx.Encrypt != nil && ctx.EncKey != nil {
+			d["Encrypt"] = *types.NewIndirectRef(l.renum[ctx.Encrypt.ObjectNumber.Value()], 0)
+		}
+		if ctx.ID != nil {
+			d["ID"] = ctx.ID
+		}
+		firstXRef := fmt.Sprintf("xref%s%d %d%s", eol, firstObjNr, len(first), eol) + l.xRefEntries(first) + l.trailer(d, 0)
+
+		if lin.prefix == l.lin.prefix && firstXRef == l.firstXRef && sameStream(hint, l.hint) {
+			if log.WriteEnabled() {
+				log.Write.Printf("linearize: layout done after %d passes\n", i+1)
+			}
+			return nil
+		}
+
+		*l.lin, *l.hint, l.firstXRef = *lin, *hint, firstXRef
+	}
+
+	return errors.New("pdfcpu: linearize: unable to lay out file")
+}
+
+func sameStream(lo1, lo2 *linObject) bool {
+	sd1, ok1 := lo1.obj.(types.StreamDict)
+	sd2, ok2 := lo2.obj.(types.StreamDict)
+	return ok1 && ok2 && lo1.prefix == lo2.prefix && bytes.Equal(sd1.Content, sd2.Content)
+}
+
+func (l *linearizer) writeObject(lo *linObject) error {
+	w := l.ctx.Write
+
+	if _, err := w.WriteString(lo.prefix); err != nil {
+		return err
+	}
+
+	written := int64(len(lo.prefix))
+
+	if sd, ok := lo.obj.(types.StreamDict); ok {
+		n, err := writeStream(w, sd)
+		if err != nil {
+			return err
+		}
+		written += n
+		w.BinaryTotalSize += *sd.StreamLength
+	}
+
+	n, err := writeObjectTrailer(w)
+	if err != nil {
+		return err
+	}
+	written += int64(n)
+
+	if w.Offset != lo.offset || written != lo.size {
+		return errors.Errorf("pdfcpu: linearize: obj#%d: unexpected offset or size", lo.objNr)
+	}
+
+	w.Offset += written
+
+	return nil
+}
+
+// writeLinearized writes all objects reachable from the catalog and the document information dict
+// followed by the cross reference sections as a linearized file.
+func writeLinearized(ctx *model.Context) error {
+	if log.WriteEnabled() {
+		log.Write.Println("writeLinearized begin")
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return err
+	}
+
+	l := &linearizer{
+		ctx:       ctx,
+		stop:      types.IntSet{ctx.Root.ObjectNumber.Value(): true},
+		inherited: map[int]types.Dict{},
+		renum:     map[int]int{},
+		objs:      map[int]*linObject{},
+		eol:       ctx.Write.Eol,
+	}
+
+	if err := l.pageTree(rootDict["Pages"], nil); err != nil {
+		return err
+	}
+	if len(l.pages) == 0 {
+		return errors.New("pdfcpu: linearize: missing pages")
+	}
+
+	if err := l.partition(rootDict); err != nil {
+		return err
+	}
+
+	firstObjNr := l.renumber()
+
+	if err := l.prepareObjects(); err != nil {
+		return err
+	}
+
+	if err := l.layout(ctx.Write.Offset, firstObjNr); err != nil {
+		return err
+	}
+
+	first, main := l.sections()
+
+	if err := l.writeObject(l.lin); err != nil {
+		return err
+	}
+
+	if _, err := ctx.Write.WriteString(l.firstXRef); err != nil {
+		return err
+	}
+	ctx.Write.Offset += int64(len(l.firstXRef))
+
+	for _, lo := range append(first[1:], main...) {
+		if err := l.writeObject(lo); err != nil {
+			return err
+		}
+	}
+
+	if _, err := ctx.Write.WriteString(l.mainXRef); err != nil {
+		return err
+	}
+	ctx.Write.Offset += int64(len(l.mainXRef))
+
+	if log.WriteEnabled() {
+		log.Write.Println("writeLinearized end")
+	}
+
+	return nil
+}
@@ -45,7 +45,11 @@ func decodeFileSpecStreamDict(sd *types.StreamDict) error {
 	fpl := sd.FilterPipeline
 
 	if fpl == nil {
-		sd.Content = sd.Raw
+		raw, err := sd.RawContent()
+		if err != nil {
+			return err
+		}
+		sd.Content = raw
 		return nil
 	}
 
@@ -17,6 +17,8 @@ limitations under the License.
 package model
 
 import (
+	"crypto"
+	"crypto/x509"
 	_ "embed"
 	"fmt"
 	"os"
@@ -153,6 +155,25 @@ const (
 	SETVIEWERPREFERENCES
 	RESETVIEWERPREFERENCES
 	ZOOM
+	SIGN
+	LISTSIGNATURES
+	VALIDATESIGNATURES
+	EXTRACTTEXT
+	REDACT
+	RENDER
+	PDFACONVERT
+	LISTSTRUCTURE
+	EXPORTSTRUCTURE
+	LISTLAYERS
+	ADDLAYERS
+	REMOVELAYERS
+	SHOWLAYERS
+	HIDELAYERS
+	FLATTENLAYERS
+	REPAIR
+	LISTREVISIONS
+	EXTRACTREVISION
+	DIFFREVISIONS
 )
 
 // Configuration of a Context.
@@ -173,6 +194,9 @@ type Configuration struct {
 	// Enables decoding of all streams (fontfiles, images..) for logging purposes.
 	DecodeAllStreams bool
 
+	// Parse objects and load streams on first access only for read only commands like info, bookmarks list or page extraction.
+	LazyRead bool
+
 	// Validate against ISO-32000: strict or relaxed.
 	ValidationMode int
 
@@ -182,6 +206,12 @@ type Configuration struct {
 	// Check for broken links in LinkedAnnotations/URIActions.
 	ValidateLinks bool
 
+	// Check for PDF/A conformance on top of validating against ISO-32000.
+	ValidatePDFA bool
+
+	// Check for PDF/UA conformance on top of validating against ISO-32000.
+	ValidatePDFUA bool
+
 	// End of line char sequence for writing.
 	Eol string
 
@@ -194,6 +224,12 @@ type Configuration struct {
 	// Switches between xRefSection (<=V1.4) and objectStream/xRefStream (>=V1.5) writing.
 	WriteXRefStream bool
 
+	// Copy unmodified streams from the source file at write time instead of holding them in memory.
+	StreamWrite bool
+
+	// Write linearized files optimized for Fast Web View using cross reference tables.
+	Linearize bool
+
 	// Turns on stats collection.
 	// TODO Decision - unused.
 	CollectStats bool
@@ -220,6 +256,13 @@ type Configuration struct {
 	// Supplied user access permissions, see Table 22.
 	Permissions PermissionFlags // int16
 
+	// Recipient certificates for encryption using the public-key security handler.
+	Recipients []*x509.Certificate
+
+	// Private key and optional certificate of a recipient for decryption using the public-key security handler.
+	RecipientKey  crypto.Decrypter
+	RecipientCert *x509.Certificate
+
 	// Command being executed.
 	Cmd CommandMode
 
@@ -245,6 +288,12 @@ type Configuration struct {
 	// Optimize duplicate content streams across pages. (assuming Optimize == true || OptimizeBeforeWriting == true)
 	OptimizeDuplicateContentStreams bool
 
+	// Recompress images using CCITTFax or DCT encoding whenever this saves space. (assuming Optimize == true || OptimizeBeforeWriting == true)
+	OptimizeImages bool
+
+	// JPEG quality (1..100) for DCT encoded images.
+	ImageQuality int
+
 	// Merge creates bookmarks.
 	CreateBookmarks bool
 
@@ -256,6 +305,9 @@ type Configuration struct {
 
 	// HTTP timeout in seconds.
 	Timeout int
+
+	// User fonts used for runes missing in the font in effect in order of preference.
+	FallbackFonts []string
 }
 
 // ConfigPath defines the location of pdfcpu's configuration directory.
@@ -357,11 +409,14 @@ func newDefaultConfiguration() *Configuration {
 		CheckFileNameExt:                true,
 		Reader15:                        true,
 		DecodeAllStreams:                false,
+		LazyRead:                        false,
 		ValidationMode:                  ValidationRelaxed,
 		ValidateLinks:                   false,
 		Eol:                             types.EolLF,
 		WriteObjectStream:               true,
 		WriteXRefStream:                 true,
+		StreamWrite:                     false,
+		Linearize:                       false,
 		EncryptUsingAES:                 true,
 		EncryptKeyLength:                256,
 		Permissions:                     PermissionsPrint,
@@ -371,6 +426,8 @@ func newDefaultConfiguration() *Configuration {
 		OptimizeBeforeWriting:           true,
 		OptimizeResourceDicts:           true,
 		OptimizeDuplicateContentStreams: false,
+		OptimizeImages:                  false,
+		ImageQuality:                    75,
 		CreateBookmarks:                 true,
 		NeedAppearances:                 false,
 		Offline:                         false,
@@ -418,6 +475,15 @@ func NewAESConfiguration(userPW, ownerPW string, keyLength int) *Configuration {
 	return c
 }
 
+// NewCertificateConfiguration returns a default configuration for AES encryption using the public-key security handler.
+func NewCertificateConfiguration(recipients []*x509.Certificate, keyLength int) *Configuration {
+	c := NewDefaultConfiguration()
+	c.Recipients = recipients
+	c.EncryptUsingAES = true
+	c.EncryptKeyLength = keyLength
+	return c
+}
+
 // NewRC4Configuration returns a default configuration for RC4 encryption.
 func NewRC4Configuration(userPW, ownerPW string, keyLength int) *Configuration {
 	c := NewDefaultConfiguration()
@@ -440,12 +506,15 @@ func (c Configuration) String() string {
 		"CheckFileNameExt:    %t\n"+
 		"Reader15:            %t\n"+
 		"DecodeAllStreams:    %t\n"+
+		"LazyRead:            %t\n"+
 		"ValidationMode:      %s\n"+
 		"PostProcessValidate: %t\n"+
 		"ValidateLinks:       %t\n"+
 		"Eol:                 %s\n"+
 		"WriteObjectStream:   %t\n"+
 		"WriteXrefStream:     %t\n"+
+		"StreamWrite:         %t\n"+
+		"Linearize:           %t\n"+
 		"EncryptUsingAES:     %t\n"+
 		"EncryptKeyLength:    %d\n"+
 		"Permissions:         %d\n"+
@@ -456,22 +525,28 @@ func (c Configuration) String() string {
 		"OptimizeBeforeWriting %t\n"+
 		"OptimizeResourceDicts %t\n"+
 		"OptimizeDuplicateContentStreams %t\n"+
+		"OptimizeImages %t\n"+
+		"ImageQuality %d\n"+
 		"CreateBookmarks %t\n"+
 		"NeedAppearances %t\n"+
 		"Offline %t\n"+
-		"Timeout %d\n",
+		"Timeout %d\n"+
+		"FallbackFonts %v\n",
 		path,
 		c.CreationDate,
 		c.Version,
 		c.CheckFileNameExt,
 		c.Reader15,
 		c.DecodeAllStreams,
+		c.LazyRead,
 		c.ValidationModeString(),
 		c.PostProcessValidate,
 		c.ValidateLinks,
 		c.EolString(),
 		c.WriteObjectStream,
 		c.WriteXRefStream,
+		c.StreamWrite,
+		c.Linearize,
 		c.EncryptUsingAES,
 		c.EncryptKeyLength,
 		c.Permissions,
@@ -482,10 +557,13 @@ func (c Configuration) String() string {
 		c.OptimizeBeforeWriting,
 		c.OptimizeResourceDicts,
 		c.OptimizeDuplicateContentStreams,
+		c.OptimizeImages,
+		c.ImageQuality,
 		c.CreateBookmarks,
 		c.NeedAppearances,
 		c.Offline,
 		c.Timeout,
+		c.FallbackFonts,
 	)
 }
 
@@ -0,0 +1,440 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// InlineImage represents an inline image, see 8.9.7.
+type InlineImage struct {
+	Dict types.Dict // Image attributes using the keys as found in the content stream.
+	Data []byte     // Image data as found in the content stream.
+}
+
+// Operator represents a content stream operator, see Annex A.
+type Operator string
+
+// Content stream operators, see Table A.1.
+const (
+	OpCloseFillStroke         Operator = "b"
+	OpFillStroke              Operator = "B"
+	OpCloseFillStrokeEvenOdd  Operator = "b*"
+	OpFillStrokeEvenOdd       Operator = "B*"
+	OpBeginMarkedContentProps Operator = "BDC"
+	OpBeginInlineImage        Operator = "BI"
+	OpBeginMarkedContent      Operator = "BMC"
+	OpBeginText               Operator = "BT"
+	OpBeginCompatibility      Operator = "BX"
+	OpCurveTo                 Operator = "c"
+	OpConcatMatrix            Operator = "cm"
+	OpSetStrokeColorSpace     Operator = "CS"
+	OpSetFillColorSpace       Operator = "cs"
+	OpSetDash                 Operator = "d"
+	OpSetCharWidth            Operator = "d0"
+	OpSetCacheDevice          Operator = "d1"
+	OpPaintXObject            Operator = "Do"
+	OpMarkPointProps          Operator = "DP"
+	OpEndInlineImage          Operator = "EI"
+	OpEndMarkedContent        Operator = "EMC"
+	OpEndText                 Operator = "ET"
+	OpEndCompatibility        Operator = "EX"
+	OpFill                    Operator = "f"
+	OpFillObsolete            Operator = "F"
+	OpFillEvenOdd             Operator = "f*"
+	OpSetStrokeGray           Operator = "G"
+	OpSetFillGray             Operator = "g"
+	OpSetExtGState            Operator = "gs"
+	OpClosePath               Operator = "h"
+	OpSetFlatness             Operator = "i"
+	OpBeginImageData          Operator = "ID"
+	OpSetLineJoin             Operator = "j"
+	OpSetLineCap              Operator = "J"
+	OpSetStrokeCMYK           Operator = "K"
+	OpSetFillCMYK             Operator = "k"
+	OpLineTo                  Operator = "l"
+	OpMoveTo                  Operator = "m"
+	OpSetMiterLimit           Operator = "M"
+	OpMarkPoint               Operator = "MP"
+	OpEndPath                 Operator = "n"
+	OpSave                    Operator = "q"
+	OpRestore                 Operator = "Q"
+	OpRectangle               Operator = "re"
+	OpSetStrokeRGB            Operator = "RG"
+	OpSetFillRGB              Operator = "rg"
+	OpSetRenderingIntent      Operator = "ri"
+	OpCloseStroke             Operator = "s"
+	OpStroke                  Operator = "S"
+	OpSetStrokeColor          Operator = "SC"
+	OpSetFillColor            Operator = "sc"
+	OpSetStrokeColorN         Operator = "SCN"
+	OpSetFillColorN           Operator = "scn"
+	OpShade                   Operator = "sh"
+	OpNextLine                Operator = "T*"
+	OpSetCharSpacing          Operator = "Tc"
+	OpMoveText                Operator = "Td"
+	OpMoveTextSetLeading      Operator = "TD"
+	OpSetFont                 Operator = "Tf"
+	OpShowText                Operator = "Tj"
+	OpShowTextArray           Operator = "TJ"
+	OpSetLeading              Operator = "TL"
+	OpSetTextMatrix           Operator = "Tm"
+	OpSetTextRender           Operator = "Tr"
+	OpSetTextRise             Operator = "Ts"
+	OpSetWordSpacing          Operator = "Tw"
+	OpSetHorizScaling         Operator = "Tz"
+	OpCurveToV                Operator = "v"
+	OpSetLineWidth            Operator = "w"
+	OpClip                    Operator = "W"
+	OpClipEvenOdd             Operator = "W*"
+	OpCurveToY                Operator = "y"
+	OpMoveShowText            Operator = "'"
+	OpMoveSetShowText         Operator = "\""
+)
+
+// paintingOperators are content stream operators producing visible marks on their own.
+var paintingOperators = map[Operator]bool{
+	OpShowText: true, OpShowTextArray: true, OpMoveShowText: true, OpMoveSetShowText: true,
+	OpFill: true, OpFillObsolete: true, OpFillEvenOdd: true,
+	OpFillStroke: true, OpFillStrokeEvenOdd: true, OpCloseFillStroke: true, OpCloseFillStrokeEvenOdd: true,
+	OpStroke: true, OpCloseStroke: true,
+	OpShade: true, OpBeginInlineImage: true,
+}
+
+// Paints reports whether op produces visible marks, not counting XObjects painted by Do.
+func (op Operator) Paints() bool {
+	return paintingOperators[op]
+}
+
+// ContentOperation represents a content stream operator and its operands, see 7.8.2.
+type ContentOperation struct {
+	Operator Operator
+	Operands []types.Object
+	Image    *InlineImage // for BI only
+}
+
+// ContentParser parses a content stream into a sequence of operations.
+type ContentParser struct {
+	s string
+}
+
+// NewContentParser returns a parser for the content stream bb.
+func NewContentParser(bb []byte) *ContentParser {
+	return &ContentParser{s: string(bb)}
+}
+
+func contentWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0x00
+}
+
+func contentDelimiter(c byte) bool {
+	return contentWhitespace(c) || strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (p *ContentParser) skipWhitespaceAndComments() {
+	for len(p.s) > 0 {
+		if p.s[0] == '%' {
+			i := strings.IndexAny(p.s, "\n\r")
+			if i < 0 {
+				p.s = ""
+				return
+			}
+			p.s = p.s[i:]
+			continue
+		}
+		if !contentWhitespace(p.s[0]) {
+			return
+		}
+		p.s = p.s[1:]
+	}
+}
+
+func (p *ContentParser) keyword() string {
+	i := 0
+	for i < len(p.s) && !contentDelimiter(p.s[i]) {
+		i++
+	}
+	kw := p.s[:i]
+	p.s = p.s[i:]
+	return kw
+}
+
+func contentNumber(s string) (types.Object, error) {
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.Atoi(s); err == nil {
+			return types.Integer(i), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, errors.Errorf("pdfcpu: corrupt number in content stream: %s", s)
+	}
+	return types.Float(f), nil
+}
+
+// operand parses the next operand.
+func (p *ContentParser) operand() (types.Object, error) {
+	c := p.s[0]
+
+	if strings.IndexByte("+-.0123456789", c) >= 0 {
+		return contentNumber(p.keyword())
+	}
+
+	o, err := ParseObject(&p.s)
+	if err != nil {
+		return nil, errors.Wrapf(errPageContentCorrupt, "%v", err)
+	}
+
+	if _, ok := o.(types.IndirectRef); ok {
+		return nil, errPageContentCorrupt
+	}
+
+	return o, nil
+}
+
+func inlineImageLength(d types.Dict) int {
+	for _, k := range []string{"L", "Length"} {
+		if i, ok := d[k].(types.Integer); ok && i >= 0 {
+			return i.Value()
+		}
+	}
+	return -1
+}
+
+// inlineImage parses the image attributes and data following BI up to and including EI.
+func (p *ContentParser) inlineImage() (*InlineImage, error) {
+	d := types.Dict{}
+
+	for {
+		p.skipWhitespaceAndComments()
+		if len(p.s) == 0 {
+			return nil, errBIExpressionCorrupt
+		}
+		if p.s[0] != '/' {
+			if p.keyword() != "ID" {
+				return nil, errBIExpressionCorrupt
+			}
+			break
+		}
+		k, err := ParseObject(&p.s)
+		if err != nil {
+			return nil, errBIExpressionCorrupt
+		}
+		p.skipWhitespaceAndComments()
+		if len(p.s) == 0 {
+			return nil, errBIExpressionCorrupt
+		}
+		v, err := p.operand()
+		if err != nil {
+			return nil, errBIExpressionCorrupt
+		}
+		d[k.(types.Name).Value()] = v
+	}
+
+	// ID is followed by a single white-space character.
+	if len(p.s) > 0 && contentWhitespace(p.s[0]) {
+		p.s = p.s[1:]
+	}
+
+	if l := inlineImageLength(d); l >= 0 && l <= len(p.s) {
+		s := strings.TrimLeftFunc(p.s[l:], func(r rune) bool { return r < 0x80 && contentWhitespace(byte(r)) })
+		if strings.HasPrefix(s, "EI") && (len(s) == 2 || contentDelimiter(s[2])) {
+			img := &InlineImage{Dict: d, Data: []byte(p.s[:l])}
+			p.s = s[2:]
+			return img, nil
+		}
+	}
+
+	// EI is preceded by a white-space character and followed by a delimiter.
+	for i := 0; ; {
+		j := strings.Index(p.s[i:], "EI")
+		if j < 0 {
+			return nil, errBIExpressionCorrupt
+		}
+		i += j
+		if (i == 0 || contentWhitespace(p.s[i-1])) && (i+2 == len(p.s) || contentDelimiter(p.s[i+2])) {
+			end := i
+			if end > 0 {
+				end--
+			}
+			img := &InlineImage{Dict: d, Data: []byte(p.s[:end])}
+			p.s = p.s[i+2:]
+			return img, nil
+		}
+		i += 2
+	}
+}
+
+// Next returns the next operation of the content stream or io.EOF.
+func (p *ContentParser) Next() (*ContentOperation, error) {
+	var operands []types.Object
+
+	for {
+		p.skipWhitespaceAndComments()
+
+		if len(p.s) == 0 {
+			if len(operands) > 0 && log.DebugEnabled() {
+				log.Debug.Printf("ContentParser: ignoring %d dangling operands\n", len(operands))
+			}
+			return nil, io.EOF
+		}
+
+		if strings.IndexByte("/([<+-.0123456789", p.s[0]) >= 0 {
+			o, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			operands = append(operands, o)
+			continue
+		}
+
+		kw := p.keyword()
+
+		switch kw {
+		case "":
+			return nil, errors.Wrapf(errPageContentCorrupt, "unexpected %q", p.s[0])
+		case "true", "false":
+			operands = append(operands, types.Boolean(kw == "true"))
+			continue
+		case "null":
+			operands = append(operands, nil)
+			continue
+		case string(OpBeginInlineImage):
+			img, err := p.inlineImage()
+			if err != nil {
+				return nil, err
+			}
+			return &ContentOperation{Operator: OpBeginInlineImage, Image: img}, nil
+		}
+
+		return &ContentOperation{Operator: Operator(kw), Operands: operands}, nil
+	}
+}
+
+// ParseContentStream parses the content stream bb into a sequence of operations.
+func ParseContentStream(bb []byte) ([]ContentOperation, error) {
+	var ops []ContentOperation
+	p := NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			return ops, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, *op)
+	}
+}
+
+func writeContentOperand(buf *bytes.Buffer, o types.Object) {
+	switch o := o.(type) {
+	case nil:
+		buf.WriteString("null")
+	case types.Float:
+		s := strconv.FormatFloat(o.Value(), 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			// Preserve the operand type.
+			s += ".0"
+		}
+		buf.WriteString(s)
+	case types.Array:
+		buf.WriteByte('[')
+		for i, o1 := range o {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeContentOperand(buf, o1)
+		}
+		buf.WriteByte(']')
+	case types.Dict:
+		writeContentDict(buf, o)
+	default:
+		buf.WriteString(o.PDFString())
+	}
+}
+
+func writeContentDictEntries(buf *bytes.Buffer, d types.Dict) {
+	keys := make([]string, 0, len(d))
+	for k := range d {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(types.Name(k).PDFString())
+		buf.WriteByte(' ')
+		writeContentOperand(buf, d[k])
+	}
+}
+
+func writeContentDict(buf *bytes.Buffer, d types.Dict) {
+	buf.WriteString("<<")
+	writeContentDictEntries(buf, d)
+	buf.WriteString(">>")
+}
+
+func (op ContentOperation) write(buf *bytes.Buffer) {
+	if op.Operator == OpBeginInlineImage && op.Image != nil {
+		buf.WriteString("BI ")
+		writeContentDictEntries(buf, op.Image.Dict)
+		buf.WriteString(" ID ")
+		buf.Write(op.Image.Data)
+		buf.WriteString("\nEI")
+		return
+	}
+
+	for _, o := range op.Operands {
+		writeContentOperand(buf, o)
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(string(op.Operator))
+}
+
+// String returns the content stream representation of op.
+func (op ContentOperation) String() string {
+	var buf bytes.Buffer
+	op.write(&buf)
+	return buf.String()
+}
+
+// ContentStreamBytes serializes ops into a content stream.
+func ContentStreamBytes(ops []ContentOperation) []byte {
+	var buf bytes.Buffer
+	for _, op := range ops {
+		op.write(&buf)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// WriteContentStream serializes ops into a content stream and writes it to w.
+func WriteContentStream(w io.Writer, ops []ContentOperation) error {
+	_, err := w.Write(ContentStreamBytes(ops))
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestParseContentStream(t *testing.T) {
+	s := `q 1 0 0 1 72.5 -.5 cm % comment
+	/Span<</ActualText<FEFF0041>/MCID 3>>BDC
+	BT/F1 12 Tf[(Hello\)) -250 <0057>]TJ ET EMC
+	BI /W 2 /H 1 /CS /G /BPC 8 /F [/AHx] ID 00ff> EI
+	0 0 1 rg true null Q`
+
+	want := []ContentOperation{
+		{Operator: OpSave},
+		{Operator: OpConcatMatrix, Operands: []types.Object{types.Integer(1), types.Integer(0), types.Integer(0), types.Integer(1), types.Float(72.5), types.Float(-.5)}},
+		{Operator: OpBeginMarkedContentProps, Operands: []types.Object{types.Name("Span"), types.Dict{"ActualText": types.HexLiteral("FEFF0041"), "MCID": types.Integer(3)}}},
+		{Operator: OpBeginText},
+		{Operator: OpSetFont, Operands: []types.Object{types.Name("F1"), types.Integer(12)}},
+		{Operator: OpShowTextArray, Operands: []types.Object{types.Array{types.StringLiteral(`Hello\)`), types.Integer(-250), types.HexLiteral("0057")}}},
+		{Operator: OpEndText},
+		{Operator: OpEndMarkedContent},
+		{Operator: OpBeginInlineImage, Image: &InlineImage{
+			Dict: types.Dict{"W": types.Integer(2), "H": types.Integer(1), "CS": types.Name("G"), "BPC": types.Integer(8), "F": types.Array{types.Name("AHx")}},
+			Data: []byte("00ff>"),
+		}},
+		{Operator: OpSetFillRGB, Operands: []types.Object{types.Integer(0), types.Integer(0), types.Integer(1)}},
+		{Operator: OpRestore, Operands: []types.Object{types.Boolean(true), nil}},
+	}
+
+	got, err := ParseContentStream([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want:\n%v\ngot:\n%v\n", want, got)
+	}
+
+	// Serialize and parse again.
+	got, err = ParseContentStream(ContentStreamBytes(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("roundtrip want:\n%v\ngot:\n%v\n", want, got)
+	}
+
+	if s := got[1].String(); s != "1 0 0 1 72.5 -0.5 cm" {
+		t.Fatalf("want: 1 0 0 1 72.5 -0.5 cm, got: %s\n", s)
+	}
+}
+
+func TestParseContentStreamCorrupt(t *testing.T) {
+	for _, s := range []string{
+		"1 0 0 1 --5 0 cm",
+		"BT (unterminated Tj ET",
+		"BI /W 1 /H 1 ID xx",
+		"0 0 m ) l",
+	} {
+		if _, err := ParseContentStream([]byte(s)); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
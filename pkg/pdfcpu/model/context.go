@@ -194,6 +194,7 @@ type ReadContext struct {
 	ObjectStreams       types.IntSet  // All object numbers of any object streams found which need to be decoded.
 	UsingXRefStreams    bool          // File is using xref streams.
 	XRefStreams         types.IntSet  // All object numbers of any xref streams found.
+	XRefRebuilt         bool          // XRefTable has been reconstructed by scanning the file.
 }
 
 func newReadContext(rs io.ReadSeeker) (*ReadContext, error) {
@@ -76,6 +76,10 @@ func (xRefTable *XRefTable) indRefToObject(ir *types.IndirectRef, decodeLazy boo
 
 	xRefTable.CurObj = int(ir.ObjectNumber)
 
+	if err := xRefTable.loadLazily(xRefTable.CurObj, entry); err != nil {
+		return nil, err
+	}
+
 	if l, ok := entry.Object.(types.LazyObjectStreamObject); ok && decodeLazy {
 		ob, err := l.DecodedObject(context.TODO())
 		if err != nil {
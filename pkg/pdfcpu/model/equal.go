@@ -120,11 +120,21 @@ func EqualStreamDicts(sd1, sd2 *types.StreamDict, xRefTable *XRefTable) (bool, e
 		return false, nil
 	}
 
-	if sd1.Raw == nil || sd2 == nil {
+	if (sd1.Raw == nil && !sd1.Detached()) || sd2 == nil {
 		return false, errors.New("pdfcpu: EqualStreamDicts: stream dict not loaded")
 	}
 
-	return bytes.Equal(sd1.Raw, sd2.Raw), nil
+	raw1, err := sd1.RawContent()
+	if err != nil {
+		return false, err
+	}
+
+	raw2, err := sd2.RawContent()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(raw1, raw2), nil
 }
 
 func equalFontNames(v1, v2 types.Object, xRefTable *XRefTable) (bool, error) {
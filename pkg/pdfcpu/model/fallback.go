@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pkg/errors"
+)
+
+// fontRun is a part of a text line rendered using a single font.
+type fontRun struct {
+	text     string
+	fontName string
+}
+
+// ValidateFallbackFonts ensures all fallback fonts are installed user fonts.
+func ValidateFallbackFonts(fontNames []string) error {
+	for _, fontName := range fontNames {
+		if !font.IsUserFont(fontName) {
+			return errors.Errorf("pdfcpu: fallback font %s is not installed, please refer to \"pdfcpu fonts list\"", fontName)
+		}
+	}
+	return nil
+}
+
+func coversRune(fontName string, r rune) bool {
+	if font.IsCoreFont(fontName) {
+		_, ok := unicodeToCP1252[r]
+		return r <= 0xFF || ok
+	}
+	return font.HasRune(fontName, r)
+}
+
+// sticky returns true for runes continuing the current font run if possible.
+func sticky(r rune) bool {
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+}
+
+// fontIndex returns the index of the first font in fontNames providing r.
+func fontIndex(r rune, fontNames []string) int {
+	for i, fontName := range fontNames {
+		if coversRune(fontName, r) {
+			return i
+		}
+	}
+	return 0
+}
+
+// fontRuns splits s into runs of the first font in fontNames providing a glyph for each rune.
+// Text for core fonts gets decoded into WinAnsi.
+func fontRuns(s string, fontNames []string) []fontRun {
+	var (
+		runs []fontRun
+		sb   strings.Builder
+	)
+	cur := -1
+	flush := func() {
+		if sb.Len() == 0 {
+			return
+		}
+		t := sb.String()
+		if font.IsCoreFont(fontNames[cur]) {
+			t = DecodeUTF8ToByte(t)
+		}
+		runs = append(runs, fontRun{text: t, fontName: fontNames[cur]})
+		sb.Reset()
+	}
+	for _, r := range s {
+		i := cur
+		if cur < 0 || !sticky(r) || !coversRune(fontNames[cur], r) {
+			i = fontIndex(r, fontNames)
+		}
+		if i != cur {
+			flush()
+			cur = i
+		}
+		sb.WriteRune(r)
+	}
+	flush()
+	return runs
+}
+
+// UsedFallbackFonts returns the fallback fonts needed for rendering s using fontName.
+func UsedFallbackFonts(s, fontName string, fallbackFonts []string) []string {
+	if len(fallbackFonts) == 0 {
+		return nil
+	}
+	fontNames := append([]string{fontName}, fallbackFonts...)
+	used := map[string]bool{}
+	for _, fr := range fontRuns(s, fontNames) {
+		used[fr.fontName] = true
+	}
+	var ss []string
+	for _, fn := range fallbackFonts {
+		if used[fn] && fn != fontName {
+			ss = append(ss, fn)
+		}
+	}
+	return ss
+}
+
+// fontNames returns td's font followed by its registered fallback fonts.
+func (td TextDescriptor) fontNames() []string {
+	ss := []string{td.FontName}
+	for _, fn := range td.FallbackFonts {
+		if _, ok := td.FallbackFontKeys[fn]; ok {
+			ss = append(ss, fn)
+		}
+	}
+	return ss
+}
+
+// textWidth returns the width of s in user space units rendered using fontNames.
+func textWidth(s string, fontNames []string, fontSize int) float64 {
+	if len(fontNames) == 1 {
+		return font.TextWidth(s, fontNames[0], fontSize)
+	}
+	var w float64
+	for _, fr := range fontRuns(s, fontNames) {
+		w += font.TextWidth(fr.text, fr.fontName, fontSize)
+	}
+	return w
+}
+
+// fontSizeForWidth returns the font size needed for rendering s using fontNames with width w.
+func fontSizeForWidth(s string, fontNames []string, w float64) int {
+	if len(fontNames) == 1 {
+		return font.Size(s, fontNames[0], w)
+	}
+	return int(math.Round(w / textWidth(s, fontNames, 1000) * 1000))
+}
+
+// showText returns the text showing operators for s switching to fallback fonts for runes missing in td's font.
+func showText(xRefTable *XRefTable, s string, fontSize int, td TextDescriptor) string {
+	fontNames := td.fontNames()
+	if len(fontNames) == 1 {
+		return PrepText(xRefTable, s, td.FontName, fontSize, td.Embed, td.RTL, false)
+	}
+
+	runs := fontRuns(s, fontNames)
+	if td.RTL {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+
+	var ss []string
+	fontName := td.FontName
+	for _, fr := range runs {
+		if fr.fontName != fontName {
+			ss = append(ss, fmt.Sprintf("/%s %d Tf", td.fontKey(fr.fontName), fontSize))
+			fontName = fr.fontName
+		}
+		ss = append(ss, PrepText(xRefTable, fr.text, fr.fontName, fontSize, td.Embed, td.RTL, false))
+	}
+	if fontName != td.FontName {
+		ss = append(ss, fmt.Sprintf("/%s %d Tf", td.FontKey, fontSize))
+	}
+	if len(ss) == 0 {
+		return "() Tj"
+	}
+	return strings.Join(ss, " ")
+}
+
+func (td TextDescriptor) fontKey(fontName string) string {
+	if fontName == td.FontName {
+		return td.FontKey
+	}
+	return td.FallbackFontKeys[fontName]
+}
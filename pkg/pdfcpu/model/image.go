@@ -86,6 +86,33 @@ func ImageFileNames(dir string, maxFileSize types.ByteSize) ([]string, error) {
 	return fn, nil
 }
 
+// FilterOptions returns the filter options needed for decoding sd,
+// ie. the decoded JBIG2Globals stream of a JBIG2Decode filter.
+func (xRefTable *XRefTable) FilterOptions(sd *types.StreamDict) ([]filter.Option, error) {
+	var opts []filter.Option
+	for _, f := range sd.FilterPipeline {
+		if f.Name != filter.JBIG2 || f.DecodeParms == nil {
+			continue
+		}
+		o, found := f.DecodeParms.Find("JBIG2Globals")
+		if !found {
+			continue
+		}
+		gsd, _, err := xRefTable.DereferenceStreamDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if gsd == nil {
+			continue
+		}
+		if err := gsd.Decode(); err != nil {
+			return nil, err
+		}
+		opts = append(opts, filter.JBIG2Globals(gsd.Content))
+	}
+	return opts, nil
+}
+
 func createSMaskObject(xRefTable *XRefTable, buf []byte, w, h, bpc int) (*types.IndirectRef, error) {
 	sd := &types.StreamDict{
 		Dict: types.Dict(
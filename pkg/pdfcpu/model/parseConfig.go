@@ -34,11 +34,14 @@ type configuration struct {
 	CheckFileNameExt                bool   `yaml:"checkFileNameExt"`
 	Reader15                        bool   `yaml:"reader15"`
 	DecodeAllStreams                bool   `yaml:"decodeAllStreams"`
+	LazyRead                        bool   `yaml:"lazyRead"`
 	ValidationMode                  string `yaml:"validationMode"`
 	PostProcessValidate             bool   `yaml:"postProcessValidate"`
 	Eol                             string `yaml:"eol"`
 	WriteObjectStream               bool   `yaml:"writeObjectStream"`
 	WriteXRefStream                 bool   `yaml:"writeXRefStream"`
+	StreamWrite                     bool   `yaml:"streamWrite"`
+	Linearize                       bool   `yaml:"linearize"`
 	EncryptUsingAES                 bool   `yaml:"encryptUsingAES"`
 	EncryptKeyLength                int    `yaml:"encryptKeyLength"`
 	Permissions                     int    `yaml:"permissions"`
@@ -48,12 +51,15 @@ type configuration struct {
 	DateFormat                      string `yaml:"dateFormat"`
 	Optimize                        bool   `yaml:"optimize"`
 	OptimizeBeforeWriting           bool
-	OptimizeResourceDicts           bool `yaml:"optimizeResourceDicts"`
-	OptimizeDuplicateContentStreams bool `yaml:"optimizeDuplicateContentStreams"`
-	CreateBookmarks                 bool `yaml:"createBookmarks"`
-	NeedAppearances                 bool `yaml:"needAppearances"`
-	Offline                         bool `yaml:"offline"`
-	Timeout                         int  `yaml:"timeout"`
+	OptimizeResourceDicts           bool     `yaml:"optimizeResourceDicts"`
+	OptimizeDuplicateContentStreams bool     `yaml:"optimizeDuplicateContentStreams"`
+	OptimizeImages                  bool     `yaml:"optimizeImages"`
+	ImageQuality                    int      `yaml:"imageQuality"`
+	CreateBookmarks                 bool     `yaml:"createBookmarks"`
+	NeedAppearances                 bool     `yaml:"needAppearances"`
+	Offline                         bool     `yaml:"offline"`
+	Timeout                         int      `yaml:"timeout"`
+	FallbackFonts                   []string `yaml:"fallbackFonts"`
 }
 
 func loadedConfig(c configuration, configPath string) *Configuration {
@@ -65,8 +71,11 @@ func loadedConfig(c configuration, configPath string) *Configuration {
 	conf.CheckFileNameExt = c.CheckFileNameExt
 	conf.Reader15 = c.Reader15
 	conf.DecodeAllStreams = c.DecodeAllStreams
+	conf.LazyRead = c.LazyRead
 	conf.WriteObjectStream = c.WriteObjectStream
 	conf.WriteXRefStream = c.WriteXRefStream
+	conf.StreamWrite = c.StreamWrite
+	conf.Linearize = c.Linearize
 	conf.EncryptUsingAES = c.EncryptUsingAES
 	conf.EncryptKeyLength = c.EncryptKeyLength
 	conf.Permissions = PermissionFlags(c.Permissions)
@@ -109,10 +118,13 @@ func loadedConfig(c configuration, configPath string) *Configuration {
 
 	conf.OptimizeResourceDicts = c.OptimizeResourceDicts
 	conf.OptimizeDuplicateContentStreams = c.OptimizeDuplicateContentStreams
+	conf.OptimizeImages = c.OptimizeImages
+	conf.ImageQuality = c.ImageQuality
 	conf.CreateBookmarks = c.CreateBookmarks
 	conf.NeedAppearances = c.NeedAppearances
 	conf.Offline = c.Offline
 	conf.Timeout = c.Timeout
+	conf.FallbackFonts = c.FallbackFonts
 
 	return &conf
 }
@@ -122,6 +134,7 @@ func parseConfigFile(r io.Reader, configPath string) error {
 
 	// Enforce default for old config files.
 	c.CheckFileNameExt = true
+	c.ImageQuality = 75
 
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, r); err != nil {
@@ -148,6 +161,10 @@ func parseConfigFile(r io.Reader, configPath string) error {
 		return errors.Errorf("invalid unit: %s", c.Unit)
 	}
 
+	if c.ImageQuality < 1 || c.ImageQuality > 100 {
+		return errors.Errorf("imageQuality possible values: 1..100, got: %d", c.ImageQuality)
+	}
+
 	if !types.IntMemberOf(c.EncryptKeyLength, []int{40, 128, 256}) {
 		return errors.Errorf("encryptKeyLength possible values: 40, 128, 256, got: %s", c.Unit)
 	}
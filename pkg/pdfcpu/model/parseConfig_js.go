@@ -150,6 +150,25 @@ func handleTimeout(v string, c *Configuration) error {
 	return nil
 }
 
+func handleFallbackFonts(v string, c *Configuration) error {
+	v = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			c.FallbackFonts = append(c.FallbackFonts, s)
+		}
+	}
+	return nil
+}
+
+func handleImageQuality(v string, c *Configuration) error {
+	i, err := strconv.Atoi(v)
+	if err != nil || i < 1 || i > 100 {
+		return errors.Errorf("imageQuality possible values: 1..100, got: %s", v)
+	}
+	c.ImageQuality = i
+	return nil
+}
+
 func handleConfPermissions(v string, c *Configuration) error {
 	i, err := strconv.Atoi(v)
 	if err != nil {
@@ -226,6 +245,16 @@ func parseKeysPart1(k, v string, c *Configuration) (bool, error) {
 
 	case "writeXRefStream":
 		return true, handleConfWriteXRefStream(k, v, c)
+
+	case "streamWrite":
+		var err error
+		c.StreamWrite, err = boolean(k, v)
+		return true, err
+
+	case "linearize":
+		var err error
+		c.Linearize, err = boolean(k, v)
+		return true, err
 	}
 
 	return false, nil
@@ -252,6 +281,9 @@ func parseKeysPart2(k, v string, c *Configuration) (err error) {
 	case "dateFormat":
 		err = handleDateFormat(v, c)
 
+	case "lazyRead":
+		c.LazyRead, err = boolean(k, v)
+
 	case "optimize":
 		c.Optimize, err = boolean(k, v)
 
@@ -261,6 +293,12 @@ func parseKeysPart2(k, v string, c *Configuration) (err error) {
 	case "optimizeDuplicateContentStreams":
 		c.OptimizeDuplicateContentStreams, err = boolean(k, v)
 
+	case "optimizeImages":
+		c.OptimizeImages, err = boolean(k, v)
+
+	case "imageQuality":
+		err = handleImageQuality(v, c)
+
 	case "createBookmarks":
 		c.CreateBookmarks, err = boolean(k, v)
 
@@ -272,6 +310,9 @@ func parseKeysPart2(k, v string, c *Configuration) (err error) {
 
 	case "timeout":
 		handleTimeout(v, c)
+
+	case "fallbackFonts":
+		err = handleFallbackFonts(v, c)
 	}
 
 	return err
@@ -296,6 +337,8 @@ func parseConfigFile(r io.Reader, configPath string) error {
 	// TODO add to config.yml
 	conf.OptimizeBeforeWriting = true
 
+	conf.ImageQuality = 75
+
 	s := bufio.NewScanner(r)
 	for s.Scan() {
 		t := s.Text()
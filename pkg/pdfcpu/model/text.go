@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -33,37 +34,39 @@ import (
 
 // TextDescriptor contains all attributes needed for rendering a text column in PDF user space.
 type TextDescriptor struct {
-	Text           string              // A multi line string using \n for line breaks.
-	FontName       string              // Name of the core or user font to be used.
-	RTL            bool                // Right to left user font.
-	Embed          bool                // Embed font.
-	FontKey        string              // Resource id registered for FontName.
-	FontSize       int                 // Fontsize in points.
-	X, Y           float64             // Position of first char's baseline.
-	Dx, Dy         float64             // Horizontal and vertical offsets for X,Y.
-	MTop, MBot     float64             // Top and bottom margins applied to text bounding box.
-	MLeft, MRight  float64             // Left and right margins applied to text bounding box.
-	MinHeight      float64             // The minimum height of this text's bounding box.
-	Rotation       float64             // 0..360 degree rotation angle.
-	ScaleAbs       bool                // Scaling type, true=absolute, false=relative to container dimensions.
-	Scale          float64             // font scaling factor > 0 (and <= 1 for relative scaling).
-	HAlign         types.HAlignment    // Horizontal text alignment.
-	VAlign         types.VAlignment    // Vertical text alignment.
-	RMode          draw.RenderMode     // Text render mode
-	StrokeCol      color.SimpleColor   // Stroke color to be used for rendering text corresponding to RMode.
-	FillCol        color.SimpleColor   // Fill color to be used for rendering text corresponding to RMode.
-	ShowTextBB     bool                // Render bounding box including BackgroundCol, border and margins.
-	ShowBackground bool                // Render background of bounding box using BackgroundCol.
-	BackgroundCol  color.SimpleColor   // Bounding box fill color.
-	ShowBorder     bool                // Render border using BorderCol, BorderWidth and BorderStyle.
-	BorderWidth    float64             // Border width, visibility depends on ShowBorder.
-	BorderStyle    types.LineJoinStyle // Border style, also visible if ShowBorder is false as long as ShowBackground is true.
-	BorderCol      color.SimpleColor   // Border color.
-	ParIndent      bool                // Indent first line of paragraphs or space between paragraphs.
-	ShowLineBB     bool                // Render line bounding boxes in black (for HAlign != AlignJustify only)
-	ShowMargins    bool                // Render margins in light gray.
-	ShowPosition   bool                // Highlight position.
-	HairCross      bool                // Draw haircross at X,Y
+	Text             string              // A multi line string using \n for line breaks.
+	FontName         string              // Name of the core or user font to be used.
+	RTL              bool                // Right to left user font.
+	Embed            bool                // Embed font.
+	FontKey          string              // Resource id registered for FontName.
+	FallbackFonts    []string            // User fonts for runes missing in FontName in order of preference.
+	FallbackFontKeys map[string]string   // Resource ids registered for FallbackFonts.
+	FontSize         int                 // Fontsize in points.
+	X, Y             float64             // Position of first char's baseline.
+	Dx, Dy           float64             // Horizontal and vertical offsets for X,Y.
+	MTop, MBot       float64             // Top and bottom margins applied to text bounding box.
+	MLeft, MRight    float64             // Left and right margins applied to text bounding box.
+	MinHeight        float64             // The minimum height of this text's bounding box.
+	Rotation         float64             // 0..360 degree rotation angle.
+	ScaleAbs         bool                // Scaling type, true=absolute, false=relative to container dimensions.
+	Scale            float64             // font scaling factor > 0 (and <= 1 for relative scaling).
+	HAlign           types.HAlignment    // Horizontal text alignment.
+	VAlign           types.VAlignment    // Vertical text alignment.
+	RMode            draw.RenderMode     // Text render mode
+	StrokeCol        color.SimpleColor   // Stroke color to be used for rendering text corresponding to RMode.
+	FillCol          color.SimpleColor   // Fill color to be used for rendering text corresponding to RMode.
+	ShowTextBB       bool                // Render bounding box including BackgroundCol, border and margins.
+	ShowBackground   bool                // Render background of bounding box using BackgroundCol.
+	BackgroundCol    color.SimpleColor   // Bounding box fill color.
+	ShowBorder       bool                // Render border using BorderCol, BorderWidth and BorderStyle.
+	BorderWidth      float64             // Border width, visibility depends on ShowBorder.
+	BorderStyle      types.LineJoinStyle // Border style, also visible if ShowBorder is false as long as ShowBackground is true.
+	BorderCol        color.SimpleColor   // Border color.
+	ParIndent        bool                // Indent first line of paragraphs or space between paragraphs.
+	ShowLineBB       bool                // Render line bounding boxes in black (for HAlign != AlignJustify only)
+	ShowMargins      bool                // Render margins in light gray.
+	ShowPosition     bool                // Highlight position.
+	HairCross        bool                // Draw haircross at X,Y
 }
 
 func deltaAlignMiddle(fontName string, fontSize, lines int, mTop, mBot float64) float64 {
@@ -154,7 +157,7 @@ func CalcBoundingBoxForRects(r1, r2 *types.Rectangle) *types.Rectangle {
 	return calcBoundingBoxForRectAndPoint(bbox, r2.UR)
 }
 
-func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, fontSize int) (*types.Rectangle, string) {
+func calcBoundingBoxForLines(lines []string, x, y float64, fontNames []string, fontSize int) (*types.Rectangle, string) {
 	var (
 		box      *types.Rectangle
 		maxLine  string
@@ -162,7 +165,7 @@ func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, font
 	)
 	// TODO Return error if lines == nil or empty.
 	for _, s := range lines {
-		bbox := CalcBoundingBox(s, x, y, fontName, fontSize)
+		bbox := calcLineBoundingBox(s, x, y, fontNames, fontSize)
 		if bbox.Width() > maxWidth {
 			maxWidth = bbox.Width()
 			maxLine = s
@@ -173,8 +176,22 @@ func calcBoundingBoxForLines(lines []string, x, y float64, fontName string, font
 	return box, maxLine
 }
 
+func usedGIDs(xRefTable *XRefTable, fontName string) map[uint16]bool {
+	m, ok := xRefTable.UsedGIDs[fontName]
+	if !ok {
+		m = map[uint16]bool{}
+		xRefTable.UsedGIDs[fontName] = m
+	}
+	return m
+}
+
 func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bool) string {
 	if font.IsUserFont(fontName) && !fillFont {
+		if embed {
+			if gg := font.Shape(fontName, s, rtl); gg != nil {
+				return glyphString(xRefTable, fontName, gg)
+			}
+		}
 		if rtl {
 			s = types.Reverse(s)
 		}
@@ -186,11 +203,7 @@ func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bo
 				bb = append(bb, b...)
 			}
 		} else {
-			usedGIDs, ok := xRefTable.UsedGIDs[fontName]
-			if !ok {
-				xRefTable.UsedGIDs[fontName] = map[uint16]bool{}
-				usedGIDs = xRefTable.UsedGIDs[fontName]
-			}
+			usedGIDs := usedGIDs(xRefTable, fontName)
 
 			font.UserFontMetricsLock.RLock()
 			ttf := font.UserFontMetrics[fontName]
@@ -212,9 +225,84 @@ func PrepBytes(xRefTable *XRefTable, s, fontName string, embed, rtl, fillFont bo
 	return *s1
 }
 
-func writeStringToBuf(xRefTable *XRefTable, w io.Writer, s string, x, y float64, td TextDescriptor) {
-	s = PrepBytes(xRefTable, s, td.FontName, td.Embed, td.RTL, false)
-	fmt.Fprintf(w, "BT 0 Tw %.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %d Tr (%s) Tj ET ",
+// glyphString returns the escaped string of glyph ids for gg.
+func glyphString(xRefTable *XRefTable, fontName string, gg []font.Glyph) string {
+	usedGIDs := usedGIDs(xRefTable, fontName)
+	bb := make([]byte, 0, 2*len(gg))
+	for _, g := range gg {
+		bb = binary.BigEndian.AppendUint16(bb, g.GID)
+		usedGIDs[g.GID] = true
+	}
+	s, _ := types.Escape(string(bb))
+	return *s
+}
+
+// PrepText returns the text showing operators for s.
+// Text using user fonts with OpenType layout tables gets shaped and positioned glyph by glyph.
+func PrepText(xRefTable *XRefTable, s, fontName string, fontSize int, embed, rtl, fillFont bool) string {
+	if embed && !fillFont && font.IsUserFont(fontName) {
+		if gg := font.Shape(fontName, s, rtl); gg != nil {
+			return showGlyphs(xRefTable, fontName, fontSize, gg)
+		}
+	}
+	return fmt.Sprintf("(%s) Tj", PrepBytes(xRefTable, s, fontName, embed, rtl, fillFont))
+}
+
+// showGlyphs returns Tj or TJ operations for gg applying glyph offsets and advances.
+func showGlyphs(xRefTable *XRefTable, fontName string, fontSize int, gg []font.Glyph) string {
+	var (
+		sb   strings.Builder
+		arr  []string // TJ array elements
+		i0   int      // first glyph of the pending string
+		rise int      // current text rise in glyph space units
+	)
+
+	flushString := func(i int) {
+		if i > i0 {
+			arr = append(arr, "("+glyphString(xRefTable, fontName, gg[i0:i])+")")
+		}
+		i0 = i
+	}
+
+	flush := func(i int) {
+		flushString(i)
+		if len(arr) == 1 && arr[0][0] == '(' {
+			fmt.Fprintf(&sb, "%s Tj ", arr[0])
+		} else if len(arr) > 0 {
+			fmt.Fprintf(&sb, "[%s] TJ ", strings.Join(arr, " "))
+		}
+		arr = nil
+	}
+
+	for i, g := range gg {
+		if g.YOffset != rise {
+			flush(i)
+			rise = g.YOffset
+			fmt.Fprintf(&sb, "%.2f Ts ", font.UserSpaceUnits(float64(rise), fontSize))
+		}
+		if g.XOffset != 0 {
+			flushString(i)
+			arr = append(arr, strconv.Itoa(-g.XOffset))
+		}
+		if d := g.XAdvance - g.XOffset - g.Width; d != 0 {
+			flushString(i + 1)
+			arr = append(arr, strconv.Itoa(-d))
+		}
+	}
+	flush(len(gg))
+	if rise != 0 {
+		sb.WriteString("0 Ts ")
+	}
+
+	if sb.Len() == 0 {
+		return "() Tj"
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func writeStringToBuf(xRefTable *XRefTable, w io.Writer, s string, x, y float64, fontSize int, td TextDescriptor) {
+	s = showText(xRefTable, s, fontSize, td)
+	fmt.Fprintf(w, "BT 0 Tw %.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %d Tr %s ET ",
 		td.StrokeCol.R, td.StrokeCol.G, td.StrokeCol.B, td.FillCol.R, td.FillCol.G, td.FillCol.B, x, y, td.RMode, s)
 }
 
@@ -223,9 +311,13 @@ func setFont(w io.Writer, fontID string, fontSize float32) {
 }
 
 func CalcBoundingBox(s string, x, y float64, fontName string, fontSize int) *types.Rectangle {
-	w := font.TextWidth(s, fontName, fontSize)
-	h := font.LineHeight(fontName, fontSize)
-	y -= math.Ceil(font.Descent(fontName, fontSize))
+	return calcLineBoundingBox(s, x, y, []string{fontName}, fontSize)
+}
+
+func calcLineBoundingBox(s string, x, y float64, fontNames []string, fontSize int) *types.Rectangle {
+	w := textWidth(s, fontNames, fontSize)
+	h := font.LineHeight(fontNames[0], fontSize)
+	y -= math.Ceil(font.Descent(fontNames[0], fontSize))
 	return types.NewRectangle(x, y, x+w, y+h)
 }
 
@@ -250,31 +342,47 @@ func horAdjustBoundingBoxForLines(r, box *types.Rectangle, dx, dy float64, x, y
 	}
 }
 
-func prepJustifiedLine(xRefTable *XRefTable, lines *[]string, strbuf []string, strWidth, w float64, fontSize int, fontName string, embed, rtl bool) {
+func prepJustifiedLine(xRefTable *XRefTable, lines *[]string, strbuf []string, strWidth, w float64, fontSize int, td TextDescriptor) {
+	fontName, embed, rtl := td.FontName, td.Embed, td.RTL
 	blank := PrepBytes(xRefTable, " ", fontName, embed, true, false)
 	var sb strings.Builder
-	sb.WriteString("[")
 	wc := len(strbuf)
 	dx := font.GlyphSpaceUnits(float64((w-strWidth))/float64(wc-1), fontSize)
+	shaped := embed && font.IsUserFont(fontName) && font.HasLayout(fontName) || len(td.fontNames()) > 1
+	if !shaped {
+		sb.WriteString("[")
+	}
 	for i := 0; i < wc; i++ {
 		j := i
 		if rtl {
 			j = wc - 1 - i
 		}
+		if shaped {
+			// Shaped words and words using fallback fonts come with their own positioning.
+			sb.WriteString(showText(xRefTable, strbuf[j], fontSize, td))
+			if i < wc-1 {
+				sb.WriteString(fmt.Sprintf(" [ %d (%s) ] TJ ", -int(dx), blank))
+			}
+			continue
+		}
 		s := PrepBytes(xRefTable, strbuf[j], fontName, embed, rtl, false)
 		sb.WriteString(fmt.Sprintf(" (%s)", s))
 		if i < wc-1 {
 			sb.WriteString(fmt.Sprintf(" %d (%s)", -int(dx), blank))
 		}
 	}
-	sb.WriteString(" ] TJ")
+	if !shaped {
+		sb.WriteString(" ] TJ")
+	}
 	*lines = append(*lines, sb.String())
 }
 
 func newPrepJustifiedString(
 	xRefTable *XRefTable,
-	fontName string,
-	fontSize int) func(lines *[]string, s string, w float64, fontName string, fontSize *int, lastline, parIndent, cjk, rtl bool) int {
+	td TextDescriptor,
+	fontSize int) func(lines *[]string, s string, w float64, fontSize *int, lastline bool) int {
+
+	fontNames := td.fontNames()
 
 	// Not yet rendered content.
 	strbuf := []string{}
@@ -288,18 +396,16 @@ func newPrepJustifiedString(
 	// Indentation string for first line of paragraphs.
 	identPrefix := "    "
 
-	blankWidth := font.TextWidth(" ", fontName, fontSize)
+	blankWidth := font.TextWidth(" ", td.FontName, fontSize)
 
-	return func(lines *[]string, s string, w float64, fontName string, fontSize *int, lastline, parIndent, embed, rtl bool) int {
+	return func(lines *[]string, s string, w float64, fontSize *int, lastline bool) int {
 
 		if len(s) == 0 {
 			if len(strbuf) > 0 {
-				s1 := PrepBytes(xRefTable, strings.Join(strbuf, " "), fontName, embed, rtl, false)
-				if rtl {
+				s = showText(xRefTable, strings.Join(strbuf, " "), *fontSize, td)
+				if td.RTL {
 					dx := font.GlyphSpaceUnits(w-strWidth, *fontSize)
-					s = fmt.Sprintf("[ %d (%s) ] TJ ", -int(dx), s1)
-				} else {
-					s = fmt.Sprintf("(%s) Tj", s1)
+					s = fmt.Sprintf("[ %d ] TJ %s", -int(dx), s)
 				}
 				*lines = append(*lines, s)
 				strbuf = []string{}
@@ -309,7 +415,7 @@ func newPrepJustifiedString(
 				return 0
 			}
 			indent = true
-			if parIndent {
+			if td.ParIndent {
 				return 0
 			}
 			return 1
@@ -317,12 +423,12 @@ func newPrepJustifiedString(
 
 		linefeeds := 0
 		ss := strings.Split(s, " ")
-		if parIndent && len(strbuf) == 0 && indent {
+		if td.ParIndent && len(strbuf) == 0 && indent {
 			ss[0] = identPrefix + ss[0]
 		}
 
 		for _, s1 := range ss {
-			s1Width := font.TextWidth(s1, fontName, *fontSize)
+			s1Width := textWidth(s1, fontNames, *fontSize)
 			bw := 0.
 			if len(strbuf) > 0 {
 				bw = blankWidth
@@ -333,15 +439,15 @@ func newPrepJustifiedString(
 				continue
 			}
 			// Ensure s1 fits into w.
-			fs := font.Size(s1, fontName, w)
+			fs := fontSizeForWidth(s1, fontNames, w)
 			if fs < *fontSize {
 				*fontSize = fs
 			}
 			if len(strbuf) == 0 {
-				prepJustifiedLine(xRefTable, lines, []string{s1}, s1Width, w, *fontSize, fontName, embed, rtl)
+				prepJustifiedLine(xRefTable, lines, []string{s1}, s1Width, w, *fontSize, td)
 			} else {
 				// Note: Previous lines have whitespace based on bigger font size.
-				prepJustifiedLine(xRefTable, lines, strbuf, strWidth, w, *fontSize, fontName, embed, rtl)
+				prepJustifiedLine(xRefTable, lines, strbuf, strWidth, w, *fontSize, td)
 				strbuf = []string{s1}
 				strWidth = s1Width
 			}
@@ -369,21 +475,21 @@ func preRenderJustifiedText(
 		if width > 0 {
 			ww = width * td.Scale
 		} else {
-			box, _ := calcBoundingBoxForLines(*lines, x, y, td.FontName, *fontSize)
+			box, _ := calcBoundingBoxForLines(*lines, x, y, td.fontNames(), *fontSize)
 			ww = box.Width() * td.Scale
 		}
 	}
 	ww -= mLeft + mRight + 2*borderWidth
-	prepJustifiedString := newPrepJustifiedString(xRefTable, td.FontName, *fontSize)
+	prepJustifiedString := newPrepJustifiedString(xRefTable, td, *fontSize)
 	l := []string{}
 	for i, s := range *lines {
-		linefeeds := prepJustifiedString(&l, s, ww, td.FontName, fontSize, false, td.ParIndent, td.Embed, td.RTL)
+		linefeeds := prepJustifiedString(&l, s, ww, fontSize, false)
 		for j := 0; j < linefeeds; j++ {
 			l = append(l, "")
 		}
 		isLastLine := i == len(*lines)-1
 		if isLastLine {
-			prepJustifiedString(&l, "", ww, td.FontName, fontSize, true, td.ParIndent, td.Embed, td.RTL)
+			prepJustifiedString(&l, "", ww, fontSize, true)
 		}
 	}
 	*lines = l
@@ -392,13 +498,13 @@ func preRenderJustifiedText(
 
 func scaleFontSize(r *types.Rectangle, lines []string, scaleAbs bool,
 	scale, width, x, y, mLeft, mRight, borderWidth float64,
-	fontName string, fontSize *int) {
+	fontNames []string, fontSize *int) {
 	if scaleAbs {
 		*fontSize = int(float64(*fontSize) * scale)
 	} else {
 		www := width
 		if width == 0 {
-			box, _ := calcBoundingBoxForLines(lines, x, y, fontName, *fontSize)
+			box, _ := calcBoundingBoxForLines(lines, x, y, fontNames, *fontSize)
 			www = box.Width() + mLeft + mRight + 2*borderWidth
 		}
 		*fontSize = int(r.Width() * scale * float64(*fontSize) / www)
@@ -407,7 +513,7 @@ func scaleFontSize(r *types.Rectangle, lines []string, scaleAbs bool,
 
 func horizontalWrapUp(box *types.Rectangle, maxLine string, hAlign types.HAlignment,
 	x *float64, width, ww, mLeft, mRight, borderWidth float64,
-	fontName string, fontSize *int) {
+	fontNames []string, fontSize *int) {
 	switch hAlign {
 	case types.AlignLeft:
 		box.Translate(mLeft+borderWidth, 0)
@@ -428,7 +534,7 @@ func horizontalWrapUp(box *types.Rectangle, maxLine string, hAlign types.HAlignm
 	} else if width > 0 {
 		netWidth := width - 2*borderWidth - mLeft - mRight
 		if box.Width() > netWidth {
-			*fontSize = font.Size(maxLine, fontName, netWidth)
+			*fontSize = fontSizeForWidth(maxLine, fontNames, netWidth)
 		}
 		switch hAlign {
 		case types.AlignLeft:
@@ -461,7 +567,7 @@ func createBoundingBoxForColumn(xRefTable *XRefTable, r *types.Rectangle, x, y *
 	}
 
 	if td.HAlign != types.AlignJustify {
-		scaleFontSize(r, *lines, td.ScaleAbs, td.Scale, width, *x, *y, mLeft, mRight, borderWidth, td.FontName, fontSize)
+		scaleFontSize(r, *lines, td.ScaleAbs, td.Scale, width, *x, *y, mLeft, mRight, borderWidth, td.fontNames(), fontSize)
 	}
 
 	// Apply vertical alignment.
@@ -476,9 +582,9 @@ func createBoundingBoxForColumn(xRefTable *XRefTable, r *types.Rectangle, x, y *
 	}
 	*y += math.Ceil(dy1)
 
-	box, maxLine := calcBoundingBoxForLines(*lines, *x, *y, td.FontName, *fontSize)
+	box, maxLine := calcBoundingBoxForLines(*lines, *x, *y, td.fontNames(), *fontSize)
 	// maxLine for hAlign != AlignJustify only!
-	horizontalWrapUp(box, maxLine, td.HAlign, x, width, ww, mLeft, mRight, borderWidth, td.FontName, fontSize)
+	horizontalWrapUp(box, maxLine, td.HAlign, x, width, ww, mLeft, mRight, borderWidth, td.fontNames(), fontSize)
 
 	box.LL.Y -= mBot + borderWidth
 	box.UR.Y += mTop + borderWidth
@@ -551,7 +657,7 @@ func renderText(xRefTable *XRefTable, w io.Writer, lines []string, td TextDescri
 	lh := font.LineHeight(td.FontName, fontSize)
 	for _, s := range lines {
 		if td.HAlign != types.AlignJustify {
-			lineBB := CalcBoundingBox(s, x, y, td.FontName, fontSize)
+			lineBB := calcLineBoundingBox(s, x, y, td.fontNames(), fontSize)
 			// Apply horizontal alignment.
 			var dx float64
 			switch td.HAlign {
@@ -566,7 +672,7 @@ func renderText(xRefTable *XRefTable, w io.Writer, lines []string, td TextDescri
 				draw.SetStrokeColor(w, color.Black)
 				draw.DrawRectSimple(w, lineBB)
 			}
-			writeStringToBuf(xRefTable, w, s, x-dx, y, td)
+			writeStringToBuf(xRefTable, w, s, x-dx, y, fontSize, td)
 			y -= lh
 			continue
 		}
@@ -673,7 +779,8 @@ func WriteColumn(xRefTable *XRefTable, w io.Writer, mediaBox, region *types.Rect
 	// Cache haircross coordinates.
 	x0, y0 := x, y
 
-	if font.IsCoreFont(td.FontName) && utf8.ValidString(s) {
+	// Text using fallback fonts gets decoded per font run.
+	if font.IsCoreFont(td.FontName) && utf8.ValidString(s) && len(td.fontNames()) == 1 {
 		s = DecodeUTF8ToByte(s)
 	}
 
@@ -56,43 +56,49 @@ type PdfResources struct {
 
 // Watermark represents the basic structure and command details for the commands "Stamp" and "Watermark".
 type Watermark struct {
-	OnTop                     bool                // if true STAMP else WATERMARK.
-	Mode                      int                 // WMText, WMImage or WMPDF
-	FileName                  string              // image or PDF file name
-	Image                     io.Reader           // image reader
-	PDF                       io.ReadSeeker       // PDF read seeker
-	TextString                string              // raw display text.
-	TextLines                 []string            // display multiple lines of text.
-	URL                       string              // overlay link annotation for stamps.
-	InpUnit                   types.DisplayUnit   // input display unit.
-	Pos                       types.Anchor        // position anchor, one of tl,tc,tr,l,c,r,bl,bc,br.
-	Dx, Dy                    float64             // anchor offset.
-	HAlign                    *types.HAlignment   // horizontal alignment for text watermarks.
-	FontName                  string              // supported are Adobe base fonts only. (as of now: Helvetica, Times-Roman, Courier)
-	FontSize                  int                 // font scaling factor.
-	ScaledFontSize            int                 // font scaling factor for a specific page
-	ScriptName                string              // ISO 15924: Hans, Hant, Hira, Kana, Jpan, Hang, Kore: if set, font will not be embedded.
-	RTL                       bool                // if true, render text from right to left
-	Color                     color.SimpleColor   // text fill color(=non stroking color) for backwards compatibility.
-	FillColor                 color.SimpleColor   // text fill color(=non stroking color).
-	StrokeColor               color.SimpleColor   // text stroking color
-	BgColor                   *color.SimpleColor  // text bounding box background color
-	MLeft, MRight             float64             // left and right bounding box margin
-	MTop, MBot                float64             // top and bottom bounding box margin
-	BorderWidth               float64             // Border width, visible if BgColor is set.
-	BorderStyle               types.LineJoinStyle // Border style (bounding box corner style), visible if BgColor is set.
-	BorderColor               *color.SimpleColor  // border color
-	Rotation                  float64             // rotation to apply in degrees. -180 <= x <= 180
-	Diagonal                  int                 // paint along the diagonal.
-	UserRotOrDiagonal         bool                // true if one of rotation or diagonal provided overriding the default.
-	Opacity                   float64             // opacity of the watermark. 0 <= x <= 1
-	RenderMode                draw.RenderMode     // fill=0, stroke=1 fill&stroke=2
-	Scale                     float64             // relative scale factor: 0 <= x <= 1, absolute scale factor: 0 <= x
-	ScaleEff                  float64             // effective scale factor
-	ScaleAbs                  bool                // true for absolute scaling.
-	Update                    bool                // true for updating instead of adding a page watermark.
-	Ocg, ExtGState, Font, Img *types.IndirectRef  // resources
-	Width, Height             int                 // image or page dimensions
+	OnTop                     bool                          // if true STAMP else WATERMARK.
+	Mode                      int                           // WMText, WMImage or WMPDF
+	FileName                  string                        // image or PDF file name
+	Image                     io.Reader                     // image reader
+	PDF                       io.ReadSeeker                 // PDF read seeker
+	TextString                string                        // raw display text.
+	TextLines                 []string                      // display multiple lines of text.
+	URL                       string                        // overlay link annotation for stamps.
+	InpUnit                   types.DisplayUnit             // input display unit.
+	Pos                       types.Anchor                  // position anchor, one of tl,tc,tr,l,c,r,bl,bc,br.
+	Dx, Dy                    float64                       // anchor offset.
+	HAlign                    *types.HAlignment             // horizontal alignment for text watermarks.
+	FontName                  string                        // supported are Adobe base fonts only. (as of now: Helvetica, Times-Roman, Courier)
+	FontSize                  int                           // font scaling factor.
+	ScaledFontSize            int                           // font scaling factor for a specific page
+	ScriptName                string                        // ISO 15924: Hans, Hant, Hira, Kana, Jpan, Hang, Kore: if set, font will not be embedded.
+	FallbackFonts             []string                      // user fonts for runes missing in FontName, defaults to the configured fallback fonts.
+	FallbackFontRes           map[string]*types.IndirectRef // fallback font resources
+	RTL                       bool                          // if true, render text from right to left
+	Color                     color.SimpleColor             // text fill color(=non stroking color) for backwards compatibility.
+	FillColor                 color.SimpleColor             // text fill color(=non stroking color).
+	StrokeColor               color.SimpleColor             // text stroking color
+	BgColor                   *color.SimpleColor            // text bounding box background color
+	MLeft, MRight             float64                       // left and right bounding box margin
+	MTop, MBot                float64                       // top and bottom bounding box margin
+	BorderWidth               float64                       // Border width, visible if BgColor is set.
+	BorderStyle               types.LineJoinStyle           // Border style (bounding box corner style), visible if BgColor is set.
+	BorderColor               *color.SimpleColor            // border color
+	Rotation                  float64                       // rotation to apply in degrees. -180 <= x <= 180
+	Diagonal                  int                           // paint along the diagonal.
+	UserRotOrDiagonal         bool                          // true if one of rotation or diagonal provided overriding the default.
+	Opacity                   float64                       // opacity of the watermark. 0 <= x <= 1
+	RenderMode                draw.RenderMode               // fill=0, stroke=1 fill&stroke=2
+	Scale                     float64                       // relative scale factor: 0 <= x <= 1, absolute scale factor: 0 <= x
+	ScaleEff                  float64                       // effective scale factor
+	ScaleAbs                  bool                          // true for absolute scaling.
+	Update                    bool                          // true for updating instead of adding a page watermark.
+	Layer                     string                        // name of the optional content group, defaults to "Watermark" for stamps and "Background" for watermarks.
+	ViewOff                   bool                          // true for hiding the layer in viewers.
+	PrintOff                  bool                          // true for excluding the layer from printing.
+	UserLayerUsage            bool                          // true if one of view or print provided overriding the usage of an existing layer.
+	Ocg, ExtGState, Font, Img *types.IndirectRef            // resources
+	Width, Height             int                           // image or page dimensions
 
 	// PDF stamp
 	bbPDF                   *types.Rectangle     // bounding box
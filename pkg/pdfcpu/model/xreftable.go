@@ -85,6 +85,7 @@ type Enc struct {
 	L, P, R, V int
 	Emd        bool // encrypt meta data
 	ID         []byte
+	Recipients [][]byte // public-key security handler: CMS enveloped seed and permissions per recipient
 }
 
 // AnnotMap represents annotations by object number of the corresponding annotation dict.
@@ -101,6 +102,7 @@ type PgAnnots map[AnnotationType]Annot
 // XRefTable represents a PDF cross reference table plus stats for a PDF file.
 type XRefTable struct {
 	Table               map[int]*XRefTableEntry
+	LoadObject          LoadObjectFunc     // Parses objects on first access for lazily read files.
 	Size                *int               // from trailer dict.
 	MaxObjNr            int                // after reading in all objects from xRef table.
 	PageCount           int                // Number of pages.
@@ -180,6 +182,9 @@ type XRefTable struct {
 	FillFonts map[string]types.IndirectRef
 }
 
+// LoadObjectFunc parses an object including any stream content and saves it to its xRefTable entry.
+type LoadObjectFunc func(objNr int) error
+
 // NewXRefTable creates a new XRefTable.
 func newXRefTable(conf *Configuration) (xRefTable *XRefTable) {
 	return &XRefTable{
@@ -300,9 +305,25 @@ func (xRefTable *XRefTable) FindObject(objNr int) (types.Object, error) {
 	if !ok {
 		return nil, errors.Errorf("FindObject: obj#%d not registered in xRefTable", objNr)
 	}
+	if err := xRefTable.loadLazily(objNr, entry); err != nil {
+		return nil, err
+	}
 	return entry.Object, nil
 }
 
+// Lazy returns true if objects get parsed on first access.
+func (xRefTable *XRefTable) Lazy() bool {
+	return xRefTable.LoadObject != nil
+}
+
+// loadLazily parses the object for entry on first access.
+func (xRefTable *XRefTable) loadLazily(objNr int, entry *XRefTableEntry) error {
+	if xRefTable.LoadObject == nil || entry.Free || entry.Object != nil {
+		return nil
+	}
+	return xRefTable.LoadObject(objNr)
+}
+
 // Free returns the cross ref table entry for given number of a free object.
 func (xRefTable *XRefTable) Free(objNr int) (*XRefTableEntry, error) {
 	entry, found := xRefTable.Find(objNr)
@@ -979,7 +1000,13 @@ func (xRefTable *XRefTable) DereferenceStreamDict(o types.Object) (*types.Stream
 	// An indirect reference to an undefined object shall not be considered an error by a conforming reader;
 	// it shall be treated as a reference to the null object.
 	entry, found := xRefTable.FindTableEntry(indRef.ObjectNumber.Value(), indRef.GenerationNumber.Value())
-	if !found || entry.Object == nil || entry.Free {
+	if !found || entry.Free {
+		return nil, false, nil
+	}
+	if err := xRefTable.loadLazily(indRef.ObjectNumber.Value(), entry); err != nil {
+		return nil, false, err
+	}
+	if entry.Object == nil {
 		return nil, false, nil
 	}
 	ev := entry.Valid
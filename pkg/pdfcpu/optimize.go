@@ -50,9 +50,17 @@ func optimizeContentStreamUsage(ctx *model.Context, sd *types.StreamDict, objNr
 		return nil, nil
 	}
 
+	raw, err := sd.RawContent()
+	if err != nil {
+		return nil, err
+	}
+
 	for _, objNr := range cachedObjNrs {
-		sd1 := f[objNr]
-		if bytes.Equal(sd.Raw, sd1.Raw) {
+		raw1, err := f[objNr].RawContent()
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(raw, raw1) {
 			ir := types.NewIndirectRef(objNr, 0)
 			ctx.IncrementRefCount(ir)
 			return ir, nil
@@ -1003,6 +1011,31 @@ func optimizeFontAndImages(ctx *model.Context) error {
 	return nil
 }
 
+// recompressImages re-encodes images using CCITTFax or DCT encoding whenever this saves space.
+func recompressImages(ctx *model.Context) error {
+	for objNr, imageObj := range ctx.Optimize.ImageObjects {
+		sd, err := RecompressImage(ctx.XRefTable, imageObj.ImageDict)
+		if err != nil {
+			// Leave images we are unable to process untouched.
+			if log.OptimizeEnabled() {
+				log.Optimize.Printf("recompressImages: obj#%d: %v\n", objNr, err)
+			}
+			continue
+		}
+		if sd == nil {
+			continue
+		}
+		entry, ok := ctx.FindTableEntryLight(objNr)
+		if !ok {
+			continue
+		}
+		entry.Object = *sd
+		imageObj.ImageDict = sd
+	}
+
+	return nil
+}
+
 // Return stream length for font file object.
 func streamLengthFontFile(xRefTable *model.XRefTable, indirectRef *types.IndirectRef) (*int64, error) {
 	if log.OptimizeEnabled() {
@@ -1456,6 +1489,10 @@ func OptimizeXRefTable(ctx *model.Context) error {
 		return nil
 	}
 
+	if err := LoadLazyObjects(ctx); err != nil {
+		return err
+	}
+
 	// Sometimes free objects are used although they are part of the free object list.
 	// Replace references to free xref table entries with a reference to a NULL object.
 	if err := fixReferencesToFreeObjects(ctx); err != nil {
@@ -1479,6 +1516,12 @@ func OptimizeXRefTable(ctx *model.Context) error {
 		return err
 	}
 
+	if ctx.Cmd == model.OPTIMIZE && ctx.Conf.OptimizeImages {
+		if err := recompressImages(ctx); err != nil {
+			return err
+		}
+	}
+
 	if err := ensureDirectWidthForXObjs(ctx); err != nil {
 		return err
 	}
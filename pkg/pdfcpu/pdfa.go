@@ -0,0 +1,795 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Actions not allowed in PDF/A files.
+var (
+	pdfaForbiddenActions = []string{
+		"Launch", "Sound", "Movie", "ResetForm", "ImportData", "JavaScript", "Hide",
+		"SetOCGState", "Rendition", "Trans", "GoTo3DView", "RichMediaExecute"}
+
+	pdfaNamedActions = []string{"NextPage", "PrevPage", "FirstPage", "LastPage"}
+)
+
+type pdfaConverter struct {
+	ctx      *model.Context
+	part     int
+	notes    []string                // problems that could not be repaired
+	fonts    map[int]map[uint32]bool // font obj# => used character codes
+	decoders map[int]*font.Decoder
+	forms    types.IntSet
+}
+
+func (c *pdfaConverter) note(format string, args ...interface{}) {
+	s := fmt.Sprintf(format, args...)
+	if !types.MemberOf(s, c.notes) {
+		c.notes = append(c.notes, s)
+	}
+}
+
+func (c *pdfaConverter) removeEncryption() error {
+	c.ctx.Encrypt, c.ctx.E, c.ctx.EncKey = nil, nil, nil
+	if len(c.ctx.ID) != 2 {
+		c.ctx.ID = nil
+	}
+	return ensureFileID(c.ctx)
+}
+
+func forbiddenAction(d types.Dict) bool {
+	s := d.NameEntry("S")
+	if s == nil {
+		return false
+	}
+	if types.MemberOf(*s, pdfaForbiddenActions) {
+		return true
+	}
+	if *s == "Named" {
+		n := d.NameEntry("N")
+		return n == nil || !types.MemberOf(*n, pdfaNamedActions)
+	}
+	return false
+}
+
+// removeActions removes additional actions, JavaScript and all other actions not allowed in d and its direct objects.
+func (c *pdfaConverter) removeActions(o types.Object, depth int) {
+	if depth > 32 {
+		return
+	}
+
+	var d types.Dict
+
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.StreamDict:
+		d = o.Dict
+	case types.Array:
+		for _, o1 := range o {
+			c.removeActions(o1, depth+1)
+		}
+		return
+	default:
+		return
+	}
+
+	d.Delete("AA")
+
+	for _, k := range []string{"A", "OpenAction", "Next"} {
+		o, found := d.Find(k)
+		if !found {
+			continue
+		}
+		o1, err := c.ctx.Dereference(o)
+		if err != nil {
+			continue
+		}
+		switch o1 := o1.(type) {
+		case types.Dict:
+			if forbiddenAction(o1) {
+				d.Delete(k)
+			}
+		case types.Array:
+			if k != "Next" {
+				continue
+			}
+			a := types.Array{}
+			for _, o2 := range o1 {
+				if d1, err := c.ctx.DereferenceDict(o2); err == nil && d1 != nil && !forbiddenAction(d1) {
+					a = append(a, o2)
+				}
+			}
+			d[k] = a
+		}
+	}
+
+	for _, o1 := range d {
+		c.removeActions(o1, depth+1)
+	}
+}
+
+func (c *pdfaConverter) removeJavaScript(rootDict types.Dict) error {
+	names, err := c.ctx.DereferenceDict(rootDict["Names"])
+	if err != nil {
+		return err
+	}
+	if names != nil {
+		names.Delete("JavaScript")
+	}
+
+	for _, objNr := range c.objNrs() {
+		c.removeActions(c.ctx.Table[objNr].Object, 0)
+	}
+
+	return nil
+}
+
+func (c *pdfaConverter) objNrs() []int {
+	var objNrs []int
+	for objNr, e := range c.ctx.Table {
+		if e == nil || e.Free || e.Object == nil {
+			continue
+		}
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+	return objNrs
+}
+
+// recompress replaces LZWDecode by FlateDecode.
+func (c *pdfaConverter) recompress(sd *types.StreamDict, objNr int) error {
+	lzw := false
+	for _, f := range sd.FilterPipeline {
+		if f.Name == filter.LZW {
+			lzw = true
+		}
+	}
+	if !lzw {
+		return nil
+	}
+
+	for _, f := range sd.FilterPipeline {
+		if !types.MemberOf(f.Name, filter.List()) {
+			c.note("obj#%d: unable to replace LZWDecode in %s encoded stream", objNr, f.Name)
+			return nil
+		}
+	}
+
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	sd.FilterPipeline = []types.PDFFilter{{Name: filter.Flate}}
+	sd.Update("Filter", types.Name(filter.Flate))
+	sd.Delete("DecodeParms")
+
+	return sd.Encode()
+}
+
+// fixStreams removes image alternates and interpolation as well as LZW compression.
+func (c *pdfaConverter) fixStreams() error {
+	for _, objNr := range c.objNrs() {
+		entry := c.ctx.Table[objNr]
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok {
+			continue
+		}
+
+		if st := sd.Subtype(); st != nil && (*st == "Image" || *st == "Form") {
+			sd.Delete("OPI")
+			if *st == "Image" {
+				sd.Delete("Alternates")
+				sd.Delete("Interpolate")
+			}
+		}
+
+		if err := c.recompress(&sd, objNr); err != nil {
+			return err
+		}
+
+		entry.Object = sd
+	}
+
+	return nil
+}
+
+func (c *pdfaConverter) ensureOutputIntent(rootDict types.Dict) error {
+	a, err := c.ctx.DereferenceArray(rootDict["OutputIntents"])
+	if err != nil {
+		return err
+	}
+
+	for _, o := range a {
+		d, err := c.ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+		if s := d.NameEntry("S"); s != nil && *s == "GTS_PDFA1" && d.IndirectRefEntry("DestOutputProfile") != nil {
+			return nil
+		}
+	}
+
+	bb := SRGBProfile()
+
+	info, err := ParseICCProfile(bb)
+	if err != nil {
+		return err
+	}
+
+	sd, err := c.ctx.NewStreamDictForBuf(bb)
+	if err != nil {
+		return err
+	}
+	sd.InsertInt("N", info.N)
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	indRef, err := c.ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return err
+	}
+
+	d := types.Dict(
+		map[string]types.Object{
+			"Type":                      types.Name("OutputIntent"),
+			"S":                         types.Name("GTS_PDFA1"),
+			"OutputConditionIdentifier": types.StringLiteral("sRGB IEC61966-2.1"),
+			"RegistryName":              types.StringLiteral("http://www.color.org"),
+			"Info":                      types.StringLiteral("sRGB IEC61966-2.1"),
+			"DestOutputProfile":         *indRef,
+		},
+	)
+
+	rootDict["OutputIntents"] = append(a, d)
+
+	return nil
+}
+
+func (c *pdfaConverter) fixAnnotations() error {
+	for i := 1; i <= c.ctx.PageCount; i++ {
+		d, _, _, err := c.ctx.PageDict(i, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		a, err := c.ctx.DereferenceArray(d["Annots"])
+		if err != nil {
+			continue
+		}
+		for _, o := range a {
+			ad, err := c.ctx.DereferenceDict(o)
+			if err != nil || ad == nil {
+				continue
+			}
+			if st := ad.Subtype(); st == nil || *st == "Popup" {
+				continue
+			}
+			f := 0
+			if i := ad.IntEntry("F"); i != nil {
+				f = *i
+			}
+			flags := model.AnnotationFlags(f)
+			if flags&(model.AnnInvisible|model.AnnHidden|model.AnnNoView|model.AnnToggleNoView) != 0 {
+				// Making hidden annotations visible would change the appearance of the page.
+				continue
+			}
+			ad["F"] = types.Integer(flags | model.AnnPrint)
+		}
+	}
+
+	return nil
+}
+
+func uuid() string {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "00000000-0000-0000-0000-000000000000"
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func xmlEscaped(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+func xmpDate(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05-07:00")
+}
+
+// infoDict returns the document information dictionary, synchronized with the XMP metadata about to be written.
+func (c *pdfaConverter) infoDict() (types.Dict, error) {
+	if c.ctx.Info == nil {
+		indRef, err := c.ctx.IndRefForNewObject(types.NewDict())
+		if err != nil {
+			return nil, err
+		}
+		c.ctx.Info = indRef
+	}
+
+	d, err := c.ctx.DereferenceDict(*c.ctx.Info)
+	if err != nil || d == nil {
+		return nil, errors.New("pdfcpu: corrupt info dict")
+	}
+
+	now := time.Now()
+
+	created := now
+	if s, err := c.ctx.DereferenceText(d["CreationDate"]); err == nil && s != "" {
+		if t, ok := types.DateTime(s, true); ok {
+			created = t
+		}
+	}
+
+	d.Update("CreationDate", types.StringLiteral(types.DateString(created)))
+	d.Update("ModDate", types.StringLiteral(types.DateString(now)))
+	d.Update("Producer", types.StringLiteral("pdfcpu "+model.VersionStr))
+
+	return d, nil
+}
+
+// xmp returns XMP metadata equivalent to the document information dictionary d.
+func (c *pdfaConverter) xmp(d types.Dict) []byte {
+	text := func(k string) string {
+		s, err := c.ctx.DereferenceText(d[k])
+		if err != nil {
+			return ""
+		}
+		return xmlEscaped(s)
+	}
+
+	date := func(k string) string {
+		s, _ := c.ctx.DereferenceText(d[k])
+		t, _ := types.DateTime(s, true)
+		return xmpDate(t)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	sb.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	sb.WriteString(" <rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+
+	fmt.Fprintf(&sb, "  <rdf:Description rdf:about=\"\" xmlns:pdfaid=\"http://www.aiim.org/pdfa/ns/id/\">\n")
+	fmt.Fprintf(&sb, "   <pdfaid:part>%d</pdfaid:part>\n", c.part)
+	fmt.Fprintf(&sb, "   <pdfaid:conformance>B</pdfaid:conformance>\n")
+	sb.WriteString("  </rdf:Description>\n")
+
+	sb.WriteString("  <rdf:Description rdf:about=\"\" xmlns:dc=\"http://purl.org/dc/elements/1.1/\">\n")
+	sb.WriteString("   <dc:format>application/pdf</dc:format>\n")
+	if s := text("Title"); s != "" {
+		fmt.Fprintf(&sb, "   <dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", s)
+	}
+	if s := text("Author"); s != "" {
+		fmt.Fprintf(&sb, "   <dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", s)
+	}
+	if s := text("Subject"); s != "" {
+		fmt.Fprintf(&sb, "   <dc:description><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:description>\n", s)
+	}
+	sb.WriteString("  </rdf:Description>\n")
+
+	sb.WriteString("  <rdf:Description rdf:about=\"\" xmlns:xmp=\"http://ns.adobe.com/xap/1.0/\">\n")
+	if s := text("Creator"); s != "" {
+		fmt.Fprintf(&sb, "   <xmp:CreatorTool>%s</xmp:CreatorTool>\n", s)
+	}
+	fmt.Fprintf(&sb, "   <xmp:CreateDate>%s</xmp:CreateDate>\n", date("CreationDate"))
+	fmt.Fprintf(&sb, "   <xmp:ModifyDate>%s</xmp:ModifyDate>\n", date("ModDate"))
+	fmt.Fprintf(&sb, "   <xmp:MetadataDate>%s</xmp:MetadataDate>\n", date("ModDate"))
+	sb.WriteString("  </rdf:Description>\n")
+
+	sb.WriteString("  <rdf:Description rdf:about=\"\" xmlns:pdf=\"http://ns.adobe.com/pdf/1.3/\">\n")
+	fmt.Fprintf(&sb, "   <pdf:Producer>%s</pdf:Producer>\n", text("Producer"))
+	if s := text("Keywords"); s != "" {
+		fmt.Fprintf(&sb, "   <pdf:Keywords>%s</pdf:Keywords>\n", s)
+	}
+	if n := d.NameEntry("Trapped"); n != nil {
+		fmt.Fprintf(&sb, "   <pdf:Trapped>%s</pdf:Trapped>\n", *n)
+	}
+	sb.WriteString("  </rdf:Description>\n")
+
+	sb.WriteString("  <rdf:Description rdf:about=\"\" xmlns:xmpMM=\"http://ns.adobe.com/xap/1.0/mm/\">\n")
+	fmt.Fprintf(&sb, "   <xmpMM:DocumentID>uuid:%s</xmpMM:DocumentID>\n", uuid())
+	fmt.Fprintf(&sb, "   <xmpMM:InstanceID>uuid:%s</xmpMM:InstanceID>\n", uuid())
+	sb.WriteString("  </rdf:Description>\n")
+
+	sb.WriteString(" </rdf:RDF>\n")
+	sb.WriteString("</x:xmpmeta>\n")
+	sb.WriteString("<?xpacket end=\"w\"?>")
+
+	return []byte(sb.String())
+}
+
+// synthesizeMetadata replaces the catalog metadata by XMP metadata generated from the document information dictionary.
+func (c *pdfaConverter) synthesizeMetadata(rootDict types.Dict) error {
+	d, err := c.infoDict()
+	if err != nil {
+		return err
+	}
+
+	sd := types.NewStreamDict(types.NewDict(), 0, nil, nil, nil)
+	sd.InsertName("Type", "Metadata")
+	sd.InsertName("Subtype", "XML")
+	sd.Content = c.xmp(d)
+
+	// PDF/A-1 does not allow filters for metadata streams.
+	if err := sd.Encode(); err != nil {
+		return err
+	}
+
+	indRef, err := c.ctx.IndRefForNewObject(sd)
+	if err != nil {
+		return err
+	}
+
+	rootDict["Metadata"] = *indRef
+
+	return nil
+}
+
+func (c *pdfaConverter) fontObjNr(res types.Dict, name string) int {
+	d, err := c.ctx.DereferenceDict(res["Font"])
+	if err != nil || d == nil {
+		return -1
+	}
+	if indRef := d.IndirectRefEntry(name); indRef != nil {
+		return indRef.ObjectNumber.Value()
+	}
+	return -1
+}
+
+// registerFonts records all fonts of res.
+func (c *pdfaConverter) registerFonts(res types.Dict) {
+	d, err := c.ctx.DereferenceDict(res["Font"])
+	if err != nil || d == nil {
+		return
+	}
+	for _, o := range d {
+		if indRef, ok := o.(types.IndirectRef); ok {
+			if _, ok := c.fonts[indRef.ObjectNumber.Value()]; !ok {
+				c.fonts[indRef.ObjectNumber.Value()] = map[uint32]bool{}
+			}
+		}
+	}
+}
+
+func (c *pdfaConverter) showText(objNr int, o types.Object) error {
+	if objNr < 0 {
+		return nil
+	}
+
+	bb, err := types.StringBytes(o)
+	if err != nil {
+		return nil
+	}
+
+	dec, ok := c.decoders[objNr]
+	if !ok {
+		d, err := c.ctx.DereferenceDict(*types.NewIndirectRef(objNr, 0))
+		if err != nil || d == nil {
+			return err
+		}
+		if dec, err = font.NewDecoder(c.ctx.XRefTable, d); err != nil {
+			return err
+		}
+		c.decoders[objNr] = dec
+	}
+
+	for _, g := range dec.Decode(bb) {
+		c.fonts[objNr][g.Code] = true
+	}
+
+	return nil
+}
+
+func (c *pdfaConverter) scanForm(o types.Object, res types.Dict) error {
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+
+	objNr := indRef.ObjectNumber.Value()
+	if c.forms[objNr] {
+		return nil
+	}
+	c.forms[objNr] = true
+
+	sd, _, err := c.ctx.DereferenceStreamDict(indRef)
+	if err != nil || sd == nil {
+		return err
+	}
+	if st := sd.Subtype(); st == nil || *st != "Form" {
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+
+	if d, err := c.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && d != nil {
+		res = d
+	}
+
+	return c.scanContent(sd.Content, res)
+}
+
+// scanContent collects the character codes shown for each font.
+func (c *pdfaConverter) scanContent(bb []byte, res types.Dict) error {
+	if res == nil {
+		return nil
+	}
+
+	c.registerFonts(res)
+
+	var (
+		objNr = -1
+		stack []int
+	)
+
+	p := model.NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		operands := op.Operands
+		if len(operands) == 0 {
+			if op.Operator == model.OpSave {
+				stack = append(stack, objNr)
+			}
+			if op.Operator == model.OpRestore && len(stack) > 0 {
+				objNr, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+			continue
+		}
+		last := operands[len(operands)-1]
+
+		switch op.Operator {
+
+		case model.OpSetFont:
+			if len(operands) == 2 {
+				if n, ok := operands[0].(types.Name); ok {
+					objNr = c.fontObjNr(res, n.Value())
+				}
+			}
+
+		case model.OpShowText, model.OpMoveShowText, model.OpMoveSetShowText:
+			if err := c.showText(objNr, last); err != nil {
+				return err
+			}
+
+		case model.OpShowTextArray:
+			if a, ok := last.(types.Array); ok {
+				for _, o := range a {
+					if err := c.showText(objNr, o); err != nil {
+						return err
+					}
+				}
+			}
+
+		case model.OpPaintXObject:
+			n, ok := last.(types.Name)
+			if !ok {
+				continue
+			}
+			d, err := c.ctx.DereferenceDict(res["XObject"])
+			if err != nil || d == nil {
+				continue
+			}
+			if o, found := d.Find(n.Value()); found {
+				if err := c.scanForm(o, res); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (c *pdfaConverter) scanAnnotations(d types.Dict) error {
+	a, err := c.ctx.DereferenceArray(d["Annots"])
+	if err != nil {
+		return nil
+	}
+
+	for _, o := range a {
+		ad, err := c.ctx.DereferenceDict(o)
+		if err != nil || ad == nil {
+			continue
+		}
+		ap, err := c.ctx.DereferenceDict(ad["AP"])
+		if err != nil || ap == nil {
+			continue
+		}
+		o, found := ap.Find("N")
+		if !found {
+			continue
+		}
+		if states, err := c.ctx.DereferenceDict(o); err == nil && states != nil {
+			for _, o := range states {
+				if err := c.scanForm(o, nil); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := c.scanForm(o, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *pdfaConverter) scanPages() error {
+	for i := 1; i <= c.ctx.PageCount; i++ {
+		d, _, inhPAttrs, err := c.ctx.PageDict(i, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		bb, err := c.ctx.PageContent(d)
+		if err != nil && err != model.ErrNoContent {
+			return err
+		}
+		if err := c.scanContent(bb, inhPAttrs.Resources); err != nil {
+			return err
+		}
+
+		if err := c.scanAnnotations(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// embedFonts embeds subsets of installed user fonts for all fonts in use which are not embedded.
+func (c *pdfaConverter) embedFonts() error {
+	if err := c.scanPages(); err != nil {
+		return err
+	}
+
+	objNrs := make([]int, 0, len(c.fonts))
+	for objNr := range c.fonts {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		d, err := c.ctx.DereferenceDict(*types.NewIndirectRef(objNr, 0))
+		if err != nil || d == nil {
+			continue
+		}
+		if st := d.Subtype(); st == nil || *st == "Type3" {
+			continue
+		}
+		if ok, err := font.Embedded(c.ctx.XRefTable, d, objNr); err != nil || ok {
+			continue
+		}
+
+		baseFont := ""
+		if n := d.NameEntry("BaseFont"); n != nil {
+			baseFont = *n
+		}
+
+		fontName, ok := font.UserFontName(baseFont)
+		if !ok {
+			c.note("font %s: no matching user font installed", baseFont)
+			continue
+		}
+
+		if err := font.EmbedUserFont(c.ctx.XRefTable, d, fontName, c.fonts[objNr]); err != nil {
+			c.note("%s", strings.TrimPrefix(err.Error(), "pdfcpu: "))
+			continue
+		}
+
+		if log.CLIEnabled() {
+			log.CLI.Printf("embedding font %s\n", fontName)
+		}
+	}
+
+	return nil
+}
+
+// ConvertToPDFA repairs ctx for conformance with PDF/A-1b, PDF/A-2b or PDF/A-3b:
+// encryption, JavaScript and all other forbidden actions get removed,
+// image interpolation and LZW compression get replaced,
+// an sRGB OutputIntent gets added unless present,
+// fonts which are not embedded get embedded using matching installed user fonts
+// and XMP metadata gets generated from the document information dictionary.
+// The returned notes describe problems which could not be repaired.
+func ConvertToPDFA(ctx *model.Context, part int) ([]string, error) {
+	if part < 1 || part > 3 {
+		return nil, errors.Errorf("pdfcpu: invalid PDF/A part: %d", part)
+	}
+
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &pdfaConverter{
+		ctx:      ctx,
+		part:     part,
+		fonts:    map[int]map[uint32]bool{},
+		decoders: map[int]*font.Decoder{},
+		forms:    types.IntSet{},
+	}
+
+	if part == 1 {
+		// PDF/A-1 is based on PDF 1.4.
+		ctx.WriteObjectStream = false
+		ctx.WriteXRefStream = false
+	}
+
+	if err := c.removeEncryption(); err != nil {
+		return nil, err
+	}
+
+	if err := c.removeJavaScript(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.fixStreams(); err != nil {
+		return nil, err
+	}
+
+	if err := c.fixAnnotations(); err != nil {
+		return nil, err
+	}
+
+	if err := c.embedFonts(); err != nil {
+		return nil, err
+	}
+
+	if err := c.ensureOutputIntent(rootDict); err != nil {
+		return nil, err
+	}
+
+	if err := c.synthesizeMetadata(rootDict); err != nil {
+		return nil, err
+	}
+
+	return c.notes, nil
+}
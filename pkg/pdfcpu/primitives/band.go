@@ -151,6 +151,10 @@ func (hb *HorizontalBand) renderAnchoredTextBox(
 		td.ShowBackground, td.ShowTextBB, td.BackgroundCol = true, true, *bgCol
 	}
 
+	if err := pdf.setFallbackFonts(&td, p, fonts, pageNr); err != nil {
+		return err
+	}
+
 	model.WriteMultiLineAnchored(hb.pdf.XRefTable, p.Buf, r, nil, td, a)
 
 	return nil
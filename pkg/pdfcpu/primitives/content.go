@@ -1038,7 +1038,7 @@ func (c *Content) renderBars(p *model.Page) error {
 		if b.Hide {
 			continue
 		}
-		if err := b.render(p); err != nil {
+		if err := c.page.pdf.renderArtifact(p, func() error { return b.render(p) }); err != nil {
 			return err
 		}
 	}
@@ -1059,7 +1059,7 @@ func (c *Content) renderSimpleBoxes(p *model.Page) error {
 			}
 			sb.mergeIn(sb0)
 		}
-		if err := sb.render(p); err != nil {
+		if err := c.page.pdf.renderArtifact(p, func() error { return sb.render(p) }); err != nil {
 			return err
 		}
 	}
@@ -1080,7 +1080,8 @@ func (c *Content) renderTextBoxes(p *model.Page, pageNr int, fonts model.FontMap
 			}
 			tb.mergeIn(tb0)
 		}
-		if err := tb.render(p, pageNr, fonts); err != nil {
+		render := func() error { return tb.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tag(p, pageNr, tb.role(), "", render); err != nil {
 			return err
 		}
 	}
@@ -1101,7 +1102,8 @@ func (c *Content) renderImageBoxes(p *model.Page, pageNr int, images model.Image
 			}
 			ib.mergeIn(ib0)
 		}
-		if err := ib.render(p, pageNr, images); err != nil {
+		render := func() error { return ib.render(p, pageNr, images) }
+		if err := c.page.pdf.tag(p, pageNr, "Figure", ib.Alt, render); err != nil {
 			return err
 		}
 	}
@@ -1134,7 +1136,8 @@ func (c *Content) renderTextFields(p *model.Page, pageNr int, fonts model.FontMa
 		if tf.Hide {
 			continue
 		}
-		if err := tf.render(p, pageNr, fonts); err != nil {
+		render := func() error { return tf.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1146,7 +1149,8 @@ func (c *Content) renderDateFields(p *model.Page, pageNr int, fonts model.FontMa
 		if df.Hide {
 			continue
 		}
-		if err := df.render(p, pageNr, fonts); err != nil {
+		render := func() error { return df.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1158,7 +1162,8 @@ func (c *Content) renderCheckBoxes(p *model.Page, pageNr int, fonts model.FontMa
 		if cb.Hide {
 			continue
 		}
-		if err := cb.render(p, pageNr, fonts); err != nil {
+		render := func() error { return cb.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1170,7 +1175,8 @@ func (c *Content) renderRadioButtonGroups(p *model.Page, pageNr int, fonts model
 		if rbg.Hide {
 			continue
 		}
-		if err := rbg.render(p, pageNr, fonts); err != nil {
+		render := func() error { return rbg.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1182,7 +1188,8 @@ func (c *Content) renderComboBoxes(p *model.Page, pageNr int, fonts model.FontMa
 		if cb.Hide {
 			continue
 		}
-		if err := cb.render(p, pageNr, fonts); err != nil {
+		render := func() error { return cb.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1194,7 +1201,8 @@ func (c *Content) renderListBoxes(p *model.Page, pageNr int, fonts model.FontMap
 		if lb.Hide {
 			continue
 		}
-		if err := lb.render(p, pageNr, fonts); err != nil {
+		render := func() error { return lb.render(p, pageNr, fonts) }
+		if err := c.page.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -1295,15 +1303,21 @@ func (c *Content) render(p *model.Page, pageNr int, fonts model.FontMap, images
 		return c.Regions.render(p, pageNr, fonts, images)
 	}
 
-	// Render background
-	if c.bgCol != nil {
-		draw.FillRectNoBorder(p.Buf, c.BorderRect(), *c.bgCol)
-	}
+	err := c.page.pdf.renderArtifact(p, func() error {
+		// Render background
+		if c.bgCol != nil {
+			draw.FillRectNoBorder(p.Buf, c.BorderRect(), *c.bgCol)
+		}
 
-	// Render border
-	b := c.border()
-	if b != nil && b.col != nil && b.Width >= 0 {
-		draw.DrawRect(p.Buf, c.BorderRect(), float64(b.Width), b.col, &b.style)
+		// Render border
+		b := c.border()
+		if b != nil && b.col != nil && b.Width >= 0 {
+			draw.DrawRect(p.Buf, c.BorderRect(), float64(b.Width), b.col, &b.style)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if err := c.renderPrimitives(p, pageNr, fonts, images); err != nil {
@@ -1314,7 +1328,8 @@ func (c *Content) render(p *model.Page, pageNr int, fonts model.FontMap, images
 		return err
 	}
 
-	c.renderBoxesAndGuides(p)
-
-	return nil
+	return c.page.pdf.renderArtifact(p, func() error {
+		c.renderBoxesAndGuides(p)
+		return nil
+	})
 }
@@ -456,7 +456,7 @@ func (df *DateField) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 	}
 
 	lineBB := model.CalcBoundingBox(v, 0, 0, f.Name, f.Size)
-	s := model.PrepBytes(xRefTable, v, f.Name, true, false, f.FillFont)
+	s := model.PrepText(xRefTable, v, f.Name, f.Size, true, false, f.FillFont)
 	x := 2 * boWidth
 	if x == 0 {
 		x = 2
@@ -471,7 +471,7 @@ func (df *DateField) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 	y := (df.BoundingBox.Height()-font.LineHeight(f.Name, f.Size))/2 + font.Descent(f.Name, f.Size)
 
 	fmt.Fprintf(buf, "BT /%s %d Tf ", df.fontID, f.Size)
-	fmt.Fprintf(buf, "%.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td (%s) Tj ET ",
+	fmt.Fprintf(buf, "%.2f %.2f %.2f RG %.2f %.2f %.2f rg %.2f %.2f Td %s ET ",
 		f.col.R, f.col.G, f.col.B,
 		f.col.R, f.col.G, f.col.B, x, y, s)
 
@@ -290,36 +290,39 @@ func (fg *FieldGroup) renderBBox(bbox *types.Rectangle, p *model.Page) error {
 	return sb.render(p)
 }
 
-func (fg *FieldGroup) renderTextFields(p *model.Page, fonts model.FontMap) error {
+func (fg *FieldGroup) renderTextFields(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, tf := range fg.TextFields {
 		if tf.Hide {
 			continue
 		}
-		if err := tf.doRender(p, fonts); err != nil {
+		render := func() error { return tf.doRender(p, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (fg *FieldGroup) renderDateFields(p *model.Page, fonts model.FontMap) error {
+func (fg *FieldGroup) renderDateFields(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, df := range fg.DateFields {
 		if df.Hide {
 			continue
 		}
-		if err := df.doRender(p, fonts); err != nil {
+		render := func() error { return df.doRender(p, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (fg *FieldGroup) renderCheckBoxes(p *model.Page, fonts model.FontMap) error {
+func (fg *FieldGroup) renderCheckBoxes(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, cb := range fg.CheckBoxes {
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		render := func() error { return cb.doRender(p, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -331,31 +334,34 @@ func (fg *FieldGroup) renderRadioButtonGroups(p *model.Page, pageNr int, fonts m
 		if rbg.Hide {
 			continue
 		}
-		if err := rbg.doRender(p, pageNr, fonts); err != nil {
+		render := func() error { return rbg.doRender(p, pageNr, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (fg *FieldGroup) renderComboBoxes(p *model.Page, fonts model.FontMap) error {
+func (fg *FieldGroup) renderComboBoxes(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, cb := range fg.ComboBoxes {
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		render := func() error { return cb.doRender(p, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (fg *FieldGroup) renderListBoxes(p *model.Page, fonts model.FontMap) error {
+func (fg *FieldGroup) renderListBoxes(p *model.Page, pageNr int, fonts model.FontMap) error {
 	for _, cb := range fg.ListBoxes {
 		if cb.Hide {
 			continue
 		}
-		if err := cb.doRender(p, fonts); err != nil {
+		render := func() error { return cb.doRender(p, fonts) }
+		if err := fg.pdf.tagFormField(p, pageNr, render); err != nil {
 			return err
 		}
 	}
@@ -363,22 +369,22 @@ func (fg *FieldGroup) renderListBoxes(p *model.Page, fonts model.FontMap) error
 }
 
 func (fg *FieldGroup) renderFields(p *model.Page, pageNr int, fonts model.FontMap) error {
-	if err := fg.renderTextFields(p, fonts); err != nil {
+	if err := fg.renderTextFields(p, pageNr, fonts); err != nil {
 		return err
 	}
-	if err := fg.renderDateFields(p, fonts); err != nil {
+	if err := fg.renderDateFields(p, pageNr, fonts); err != nil {
 		return err
 	}
-	if err := fg.renderCheckBoxes(p, fonts); err != nil {
+	if err := fg.renderCheckBoxes(p, pageNr, fonts); err != nil {
 		return err
 	}
 	if err := fg.renderRadioButtonGroups(p, pageNr, fonts); err != nil {
 		return err
 	}
-	if err := fg.renderComboBoxes(p, fonts); err != nil {
+	if err := fg.renderComboBoxes(p, pageNr, fonts); err != nil {
 		return err
 	}
-	return fg.renderListBoxes(p, fonts)
+	return fg.renderListBoxes(p, pageNr, fonts)
 }
 
 func (fg *FieldGroup) render(p *model.Page, pageNr int, fonts model.FontMap) error {
@@ -388,7 +394,7 @@ func (fg *FieldGroup) render(p *model.Page, pageNr int, fonts model.FontMap) err
 	}
 
 	// Render simpleBox containing all fields of this group.
-	if err := fg.renderBBox(bbox, p); err != nil {
+	if err := fg.pdf.renderArtifact(p, func() error { return fg.renderBBox(bbox, p) }); err != nil {
 		return err
 	}
 
@@ -484,7 +484,7 @@ func (lb *ListBox) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 			s = model.DecodeUTF8ToByte(s)
 		}
 		lineBB := model.CalcBoundingBox(s, 0, 0, f.Name, f.Size)
-		s = model.PrepBytes(xRefTable, s, f.Name, true, lb.RTL, f.FillFont)
+		s = model.PrepText(xRefTable, s, f.Name, f.Size, true, lb.RTL, f.FillFont)
 		x := 2 * boWidth
 		if x == 0 {
 			x = 2
@@ -502,7 +502,7 @@ func (lb *ListBox) renderN(xRefTable *model.XRefTable) ([]byte, error) {
 				f.col.R, f.col.G, f.col.B,
 				f.col.R, f.col.G, f.col.B)
 		}
-		fmt.Fprintf(buf, "%.2f %.2f Td (%s) Tj ET ", x, h0-float64(i+1)*lh, s)
+		fmt.Fprintf(buf, "%.2f %.2f Td %s ET ", x, h0-float64(i+1)*lh, s)
 	}
 
 	fmt.Fprint(buf, "Q EMC ")
@@ -81,6 +81,7 @@ type PDF struct {
 	BackgroundColor string               `json:"bgCol"`
 	bgCol           *color.SimpleColor   // default background color
 	Fonts           map[string]*FormFont // global fonts
+	FallbackFonts   []string             `json:"fallbackFonts"` // user fonts for runes missing in the font in effect
 	FormFonts       map[string]*FormFont
 	FieldIDs        types.StringSet
 	Fields          types.Array
@@ -102,10 +103,13 @@ type PDF struct {
 	FieldGroupPool  map[string]*FieldGroup `json:"fieldgroups"`
 	Colors          map[string]string
 	colors          map[string]color.SimpleColor
-	DirNames        map[string]string          `json:"dirs"`
-	FileNames       map[string]string          `json:"files"`
-	TimestampFormat string                     `json:"timestamp"`
-	DateFormat      string                     `json:"dateFormat"`
+	DirNames        map[string]string `json:"dirs"`
+	FileNames       map[string]string `json:"files"`
+	TimestampFormat string            `json:"timestamp"`
+	DateFormat      string            `json:"dateFormat"`
+	Tagged          bool              // generate a structure tree for accessibility
+	Lang            string            // natural language of tagged content eg. en-US
+	structTree      *structTree
 	Conf            *model.Configuration       `json:"-"`
 	XRefTable       *model.XRefTable           `json:"-"`
 	Optimize        *model.OptimizationContext `json:"-"`
@@ -250,7 +254,13 @@ func (pdf *PDF) validateFonts() error {
 			return err
 		}
 	}
-	return nil
+
+	// Fallback fonts
+	if pdf.FallbackFonts == nil {
+		pdf.FallbackFonts = pdf.Conf.FallbackFonts
+		return nil
+	}
+	return model.ValidateFallbackFonts(pdf.FallbackFonts)
 }
 
 func (pdf *PDF) validateHeader() error {
@@ -454,6 +464,13 @@ func (pdf *PDF) Validate() error {
 		return errors.New("pdfcpu: Please supply \"pages\"")
 	}
 
+	if pdf.Tagged {
+		if pdf.Update() {
+			return errors.New("pdfcpu: \"tagged\" is not supported for existing PDF files")
+		}
+		pdf.structTree = newStructTree()
+	}
+
 	// What follows is a quirky way of turning a map of pages into a sorted slice of pages
 	// including entries for pages that are missing in the map.
 
@@ -620,6 +637,27 @@ func (pdf *PDF) idForFontName(fontName, fontLang string, pageFonts, globalFonts
 	return id, nil
 }
 
+// setFallbackFonts registers the fallback fonts needed for rendering td.Text.
+func (pdf *PDF) setFallbackFonts(td *model.TextDescriptor, p *model.Page, fonts model.FontMap, pageNr int) error {
+	fallbackFonts := model.UsedFallbackFonts(td.Text, td.FontName, pdf.FallbackFonts)
+	if len(fallbackFonts) == 0 {
+		td.FallbackFonts, td.FallbackFontKeys = nil, nil
+		return nil
+	}
+
+	td.FallbackFonts = fallbackFonts
+	td.FallbackFontKeys = map[string]string{}
+	for _, fontName := range fallbackFonts {
+		id, err := pdf.idForFontName(fontName, "", p.Fm, fonts, pageNr)
+		if err != nil {
+			return err
+		}
+		td.FallbackFontKeys[fontName] = id
+	}
+
+	return nil
+}
+
 func fontIndRef(xRefTable *model.XRefTable, fontName, fontLang string) (*types.IndirectRef, error) {
 	fName := fontName
 	if strings.HasPrefix(fontName, "cjk:") {
@@ -1028,13 +1066,17 @@ func (pdf *PDF) highlightPos(w io.Writer, x, y float64, cBox *types.Rectangle) {
 	draw.DrawHairCross(w, x, y, cBox)
 }
 
-func (pdf *PDF) renderPageBackground(page *PDFPage, w io.Writer) {
+func (pdf *PDF) renderPageBackground(page *PDFPage, p *model.Page) error {
 	if page.bgCol == nil {
 		page.bgCol = pdf.bgCol
 	}
-	if page.bgCol != nil {
-		draw.FillRectNoBorder(w, page.cropBox, *page.bgCol)
+	if page.bgCol == nil {
+		return nil
 	}
+	return pdf.renderArtifact(p, func() error {
+		draw.FillRectNoBorder(p.Buf, page.cropBox, *page.bgCol)
+		return nil
+	})
 }
 
 func (pdf *PDF) newModelPageforPDFPage(page *PDFPage) model.Page {
@@ -1074,19 +1116,27 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 
 			// Create blank page with optional background color.
 			if pdf.bgCol != nil {
-				draw.FillRectNoBorder(p.Buf, p.CropBox, *pdf.bgCol)
+				err := pdf.renderArtifact(&p, func() error {
+					draw.FillRectNoBorder(p.Buf, p.CropBox, *pdf.bgCol)
+					return nil
+				})
+				if err != nil {
+					return nil, nil, err
+				}
 			}
 
 			// Render page header.
 			if pdf.Header != nil {
-				if err := pdf.Header.render(&p, pageNr, fontMap, imageMap, true); err != nil {
+				render := func() error { return pdf.Header.render(&p, pageNr, fontMap, imageMap, true) }
+				if err := pdf.renderArtifact(&p, render); err != nil {
 					return nil, nil, err
 				}
 			}
 
 			// Render page footer.
 			if pdf.Footer != nil {
-				if err := pdf.Footer.render(&p, pageNr, fontMap, imageMap, false); err != nil {
+				render := func() error { return pdf.Footer.render(&p, pageNr, fontMap, imageMap, false) }
+				if err := pdf.renderArtifact(&p, render); err != nil {
 					return nil, nil, err
 				}
 			}
@@ -1096,14 +1146,17 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 			continue
 		}
 
-		pdf.renderPageBackground(page, p.Buf)
+		if err := pdf.renderPageBackground(page, &p); err != nil {
+			return nil, nil, err
+		}
 
 		var headerHeight, headerDy float64
 		var footerHeight, footerDy float64
 
 		// Render page header.
 		if pdf.Header != nil {
-			if err := pdf.Header.render(&p, pageNr, fontMap, imageMap, true); err != nil {
+			render := func() error { return pdf.Header.render(&p, pageNr, fontMap, imageMap, true) }
+			if err := pdf.renderArtifact(&p, render); err != nil {
 				return nil, nil, err
 			}
 			headerHeight = pdf.Header.Height
@@ -1112,7 +1165,8 @@ func (pdf *PDF) RenderPages() ([]*model.Page, model.FontMap, error) {
 
 		// Render page footer.
 		if pdf.Footer != nil {
-			if err := pdf.Footer.render(&p, pageNr, fontMap, imageMap, false); err != nil {
+			render := func() error { return pdf.Footer.render(&p, pageNr, fontMap, imageMap, false) }
+			if err := pdf.renderArtifact(&p, render); err != nil {
 				return nil, nil, err
 			}
 			footerHeight = pdf.Footer.Height
@@ -158,5 +158,5 @@ func (r *Regions) render(p *model.Page, pageNr int, fonts model.FontMap, images
 
 	}
 
-	return r.Divider.render(p)
+	return r.page.pdf.renderArtifact(p, func() error { return r.Divider.render(p) })
 }
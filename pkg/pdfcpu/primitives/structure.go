@@ -0,0 +1,441 @@
+/*
+	Copyright 2026 The pdfcpu Authors.
+
+	Licensed under the Apache License, Version 2.0 (the "License");
+	you may not use this file except in compliance with the License.
+	You may obtain a copy of the License at
+
+		http://www.apache.org/licenses/LICENSE-2.0
+
+	Unless required by applicable law or agreed to in writing, software
+	distributed under the License is distributed on an "AS IS" BASIS,
+	WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+	See the License for the specific language governing permissions and
+	limitations under the License.
+*/
+
+package primitives
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Supported structure types for text boxes, see 14.8.4.
+var textRoles = types.StringSet{
+	"P": true, "H": true, "H1": true, "H2": true, "H3": true, "H4": true, "H5": true, "H6": true,
+	"Caption": true, "BlockQuote": true, "Quote": true, "Note": true, "Code": true, "Lbl": true, "Title": true,
+}
+
+func validateTextRole(role string) error {
+	if role != "" && !textRoles[role] {
+		return errors.Errorf("pdfcpu: unsupported text role: %s", role)
+	}
+	return nil
+}
+
+// structElem is a structure element of tagged content, see 14.7.2.
+type structElem struct {
+	typ    string
+	alt    string
+	pageNr int   // page holding the marked content of this element
+	mcids  []int // marked content of this element
+	annots []int // parent tree keys of widget annotations belonging to this element
+	kids   []*structElem
+	indRef *types.IndirectRef
+}
+
+func (e *structElem) add(typ string) *structElem {
+	if e == nil {
+		// Untagged content.
+		return nil
+	}
+	e1 := &structElem{typ: typ}
+	e.kids = append(e.kids, e1)
+	return e1
+}
+
+// structTree collects the logical structure of rendered content.
+type structTree struct {
+	root     *structElem
+	elems    map[int][]*structElem // structure elements by page number indexed by MCID
+	annots   map[int]*structElem   // structure elements by parent tree key of widget annotations
+	nextKey  int                   // next parent tree key
+	artifact int                   // > 0 while rendering artifacts
+}
+
+func newStructTree() *structTree {
+	return &structTree{
+		root:   &structElem{typ: "Document"},
+		elems:  map[int][]*structElem{},
+		annots: map[int]*structElem{},
+	}
+}
+
+// newStructElem returns a new top level structure element of type typ or nil for untagged content.
+func (pdf *PDF) newStructElem(typ string) *structElem {
+	st := pdf.structTree
+	if st == nil || st.artifact > 0 {
+		return nil
+	}
+	return st.root.add(typ)
+}
+
+// markContent wraps everything render writes to p into a marked content sequence belonging to e.
+func (pdf *PDF) markContent(p *model.Page, pageNr int, e *structElem, render func() error) error {
+	st := pdf.structTree
+	if st == nil || st.artifact > 0 || e == nil {
+		return render()
+	}
+
+	i := p.Buf.Len()
+
+	if err := render(); err != nil {
+		return err
+	}
+
+	if p.Buf.Len() == i {
+		return nil
+	}
+
+	bb := bytes.Clone(p.Buf.Bytes()[i:])
+	p.Buf.Truncate(i)
+
+	mcid := len(st.elems[pageNr])
+	st.elems[pageNr] = append(st.elems[pageNr], e)
+	e.pageNr = pageNr
+	e.mcids = append(e.mcids, mcid)
+
+	fmt.Fprintf(p.Buf, "/%s <</MCID %d>> BDC ", e.typ, mcid)
+	p.Buf.Write(bb)
+	fmt.Fprint(p.Buf, "EMC ")
+
+	return nil
+}
+
+// renderArtifact marks everything render writes to p as artifact, see 14.8.2.2.
+func (pdf *PDF) renderArtifact(p *model.Page, render func() error) error {
+	st := pdf.structTree
+	if st == nil || st.artifact > 0 {
+		return render()
+	}
+
+	i := p.Buf.Len()
+	fmt.Fprint(p.Buf, "/Artifact BMC ")
+	j := p.Buf.Len()
+
+	st.artifact++
+	err := render()
+	st.artifact--
+
+	if p.Buf.Len() == j {
+		p.Buf.Truncate(i)
+		return err
+	}
+
+	fmt.Fprint(p.Buf, "EMC ")
+
+	return err
+}
+
+// tag renders content as structure element of type typ.
+func (pdf *PDF) tag(p *model.Page, pageNr int, typ, alt string, render func() error) error {
+	e := pdf.newStructElem(typ)
+	if e != nil {
+		e.alt = alt
+	}
+
+	return pdf.markContent(p, pageNr, e, render)
+}
+
+// tagFormField renders a form field as Form structure element
+// referring to its widget annotations and label, see 14.8.4.5.
+func (pdf *PDF) tagFormField(p *model.Page, pageNr int, render func() error) error {
+	st := pdf.structTree
+	if st == nil || st.artifact > 0 {
+		return render()
+	}
+
+	tabs := types.IntSet{}
+	for k := range p.AnnotTabs {
+		tabs[k] = true
+	}
+	n := len(p.Annots)
+
+	e := st.root.add("Form")
+
+	if err := pdf.markContent(p, pageNr, e, render); err != nil {
+		return err
+	}
+
+	e.pageNr = pageNr
+
+	anns := append([]model.FieldAnnotation{}, p.Annots[n:]...)
+	for k, ann := range p.AnnotTabs {
+		if !tabs[k] {
+			anns = append(anns, ann)
+		}
+	}
+
+	for _, ann := range anns {
+		if ann.Dict != nil {
+			st.addWidget(e, ann.Dict)
+		}
+		for _, o := range ann.Kids {
+			d, err := pdf.XRefTable.DereferenceDict(o)
+			if err != nil {
+				return err
+			}
+			if d != nil {
+				st.addWidget(e, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (st *structTree) addWidget(e *structElem, d types.Dict) {
+	key := st.nextKey
+	st.nextKey++
+	d["StructParent"] = types.Integer(key)
+	st.annots[key] = e
+	e.annots = append(e.annots, key)
+}
+
+func (pdf *PDF) pageIndRef(pageNr int, pages map[int]*types.IndirectRef) (*types.IndirectRef, error) {
+	if ir, ok := pages[pageNr]; ok {
+		return ir, nil
+	}
+	ir, err := pdf.XRefTable.PageDictIndRef(pageNr)
+	if err != nil {
+		return nil, err
+	}
+	pages[pageNr] = ir
+	return ir, nil
+}
+
+// widgetIndRefs returns the widget annotations of all tagged pages by parent tree key.
+func (pdf *PDF) widgetIndRefs(pages map[int]*types.IndirectRef) (map[int]types.IndirectRef, error) {
+	m := map[int]types.IndirectRef{}
+
+	for pageNr := 1; pageNr <= pdf.XRefTable.PageCount; pageNr++ {
+		d, _, _, err := pdf.XRefTable.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+		arr, err := pdf.XRefTable.DereferenceArray(d["Annots"])
+		if err != nil {
+			return nil, err
+		}
+		tagged := false
+		for _, o := range arr {
+			ir, ok := o.(types.IndirectRef)
+			if !ok {
+				continue
+			}
+			d1, err := pdf.XRefTable.DereferenceDict(ir)
+			if err != nil {
+				return nil, err
+			}
+			if key := d1.IntEntry("StructParent"); key != nil && pdf.structTree.annots[*key] != nil {
+				m[*key] = ir
+				tagged = true
+			}
+		}
+		if tagged {
+			// Use structure order for navigating annotations.
+			d["Tabs"] = types.Name("S")
+		}
+	}
+
+	return m, nil
+}
+
+func (pdf *PDF) createStructElemDicts(e *structElem, parent types.IndirectRef, pages map[int]*types.IndirectRef) error {
+	d := types.Dict{
+		"Type": types.Name("StructElem"),
+		"S":    types.Name(e.typ),
+		"P":    parent,
+	}
+	if e.alt != "" {
+		s, err := types.EscapedUTF16String(e.alt)
+		if err != nil {
+			return err
+		}
+		d["Alt"] = types.StringLiteral(*s)
+	}
+	if e.pageNr > 0 {
+		ir, err := pdf.pageIndRef(e.pageNr, pages)
+		if err != nil {
+			return err
+		}
+		d["Pg"] = *ir
+	}
+
+	ir, err := pdf.XRefTable.IndRefForNewObject(d)
+	if err != nil {
+		return err
+	}
+	e.indRef = ir
+
+	for _, e1 := range e.kids {
+		if err := pdf.createStructElemDicts(e1, *ir, pages); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (pdf *PDF) setStructElemKids(e *structElem, widgets map[int]types.IndirectRef) error {
+	d, err := pdf.XRefTable.DereferenceDict(*e.indRef)
+	if err != nil {
+		return err
+	}
+
+	kids := types.Array{}
+
+	for _, mcid := range e.mcids {
+		kids = append(kids, types.Integer(mcid))
+	}
+
+	for _, key := range e.annots {
+		ir, ok := widgets[key]
+		if !ok {
+			continue
+		}
+		objr := types.Dict{"Type": types.Name("OBJR"), "Obj": ir}
+		if pg, found := d.Find("Pg"); found {
+			objr["Pg"] = pg
+		}
+		kids = append(kids, objr)
+	}
+
+	for _, e1 := range e.kids {
+		kids = append(kids, *e1.indRef)
+		if err := pdf.setStructElemKids(e1, widgets); err != nil {
+			return err
+		}
+	}
+
+	switch len(kids) {
+	case 0:
+	case 1:
+		d["K"] = kids[0]
+	default:
+		d["K"] = kids
+	}
+
+	return nil
+}
+
+func (pdf *PDF) parentTree(pages map[int]*types.IndirectRef) (types.Dict, error) {
+	st := pdf.structTree
+
+	type entry struct {
+		key int
+		o   types.Object
+	}
+	var ee []entry
+
+	for key, e := range st.annots {
+		ee = append(ee, entry{key, *e.indRef})
+	}
+
+	pageNrs := make([]int, 0, len(st.elems))
+	for pageNr := range st.elems {
+		pageNrs = append(pageNrs, pageNr)
+	}
+	sort.Ints(pageNrs)
+
+	for _, pageNr := range pageNrs {
+		arr := types.Array{}
+		for _, e := range st.elems[pageNr] {
+			arr = append(arr, *e.indRef)
+		}
+
+		d, _, _, err := pdf.XRefTable.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+
+		key := st.nextKey
+		st.nextKey++
+		d["StructParents"] = types.Integer(key)
+
+		ir, err := pdf.XRefTable.IndRefForNewObject(arr)
+		if err != nil {
+			return nil, err
+		}
+		ee = append(ee, entry{key, *ir})
+	}
+
+	sort.Slice(ee, func(i, j int) bool { return ee[i].key < ee[j].key })
+
+	nums := types.Array{}
+	for _, e := range ee {
+		nums = append(nums, types.Integer(e.key), e.o)
+	}
+
+	return types.Dict{"Nums": nums}, nil
+}
+
+// WriteStructTree adds the logical structure of tagged content to the document, see 14.7.
+func (pdf *PDF) WriteStructTree() error {
+	st := pdf.structTree
+	if st == nil {
+		return nil
+	}
+
+	xRefTable := pdf.XRefTable
+
+	rootDict, err := xRefTable.Catalog()
+	if err != nil {
+		return err
+	}
+
+	d := types.Dict{"Type": types.Name("StructTreeRoot")}
+	ir, err := xRefTable.IndRefForNewObject(d)
+	if err != nil {
+		return err
+	}
+
+	pages := map[int]*types.IndirectRef{}
+
+	if err := pdf.createStructElemDicts(st.root, *ir, pages); err != nil {
+		return err
+	}
+
+	widgets, err := pdf.widgetIndRefs(pages)
+	if err != nil {
+		return err
+	}
+
+	if err := pdf.setStructElemKids(st.root, widgets); err != nil {
+		return err
+	}
+
+	parentTree, err := pdf.parentTree(pages)
+	if err != nil {
+		return err
+	}
+
+	d["K"] = *st.root.indRef
+	d["ParentTree"] = parentTree
+	d["ParentTreeNextKey"] = types.Integer(st.nextKey)
+
+	rootDict["StructTreeRoot"] = *ir
+	rootDict["MarkInfo"] = types.Dict{"Marked": types.Boolean(true)}
+
+	if pdf.Lang != "" {
+		rootDict["Lang"] = types.StringLiteral(pdf.Lang)
+	}
+
+	return nil
+}
@@ -743,7 +743,7 @@ func (t *Table) calcTextDescriptorPadding(td *model.TextDescriptor, p *Padding)
 	return nil
 }
 
-func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, colWidths []float64, td model.TextDescriptor, ll func(row, col int) (float64, float64)) error {
+func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, colWidths []float64, td model.TextDescriptor, ll func(row, col int) (float64, float64), rows []*structElem) error {
 	pdf := t.pdf
 
 	f := t.Font
@@ -773,6 +773,8 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 				break
 			}
 
+			cell := rows[i].add("TD")
+
 			s := t.Values[i][j]
 			if len(strings.TrimSpace(s)) == 0 {
 				continue
@@ -786,6 +788,9 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 			}
 
 			colTd.Text, _ = format.Text(s, pdf.TimestampFormat, pageNr, pdf.pageCount())
+			if err := pdf.setFallbackFonts(&colTd, p, fonts, pageNr); err != nil {
+				return err
+			}
 
 			row := i
 			if t.Header != nil {
@@ -795,7 +800,14 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 			x, y := ll(row, j)
 			r := types.RectForWidthAndHeight(x, y, colWidths[j], float64(t.LineHeight))
 
-			bb := model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, t.colAnchors[j])
+			var bb *types.Rectangle
+			err := pdf.markContent(p, pageNr, cell, func() error {
+				bb = model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, t.colAnchors[j])
+				return nil
+			})
+			if err != nil {
+				return err
+			}
 
 			if bb.Width() > colWidths[j] {
 				return errors.Errorf("pdfcpu: table cell width overflow - reduce padding or text: %s", colTd.Text)
@@ -809,7 +821,7 @@ func (t *Table) renderValues(p *model.Page, pageNr int, fonts model.FontMap, col
 	return nil
 }
 
-func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, colWidths []float64, td model.TextDescriptor, ll func(row, col int) (float64, float64)) error {
+func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, colWidths []float64, td model.TextDescriptor, ll func(row, col int) (float64, float64), row *structElem) error {
 	pdf := t.pdf
 	th := t.Header
 
@@ -853,6 +865,8 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 	// Render header values.
 	for i, s := range th.Values {
 
+		cell := row.add("TH")
+
 		if len(strings.TrimSpace(s)) == 0 {
 			continue
 		}
@@ -860,6 +874,9 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 		colTd := td
 		th.calcColumnPadding(&colTd, i)
 		colTd.Text, _ = format.Text(s, pdf.TimestampFormat, pageNr, pdf.pageCount())
+		if err := pdf.setFallbackFonts(&colTd, p, fonts, pageNr); err != nil {
+			return err
+		}
 
 		x, y := ll(0, i)
 		r := types.RectForWidthAndHeight(x, y, colWidths[i], float64(t.LineHeight))
@@ -869,7 +886,14 @@ func (t *Table) renderHeader(p *model.Page, pageNr int, fonts model.FontMap, col
 			a = th.colAnchors[i]
 		}
 
-		bb := model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, a)
+		var bb *types.Rectangle
+		err := pdf.markContent(p, pageNr, cell, func() error {
+			bb = model.WriteMultiLineAnchored(pdf.XRefTable, p.Buf, r, nil, colTd, a)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 
 		if bb.Width() > colWidths[i] {
 			return errors.Errorf("pdfcpu: table header cell width overflow - reduce padding or text: %s", colTd.Text)
@@ -903,28 +927,35 @@ func (t *Table) render(p *model.Page, pageNr int, fonts model.FontMap) error {
 
 	fmt.Fprintf(p.Buf, "q %.5f %.5f %.5f %.5f %.5f %.5f cm ", m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1])
 
-	if t.bgCol != nil {
-		x, w := r.LL.X+bWidth/2, t.Width-2*bWidth
-		if bWidth == 0 {
-			// Reduce artefacts.
-			x += .5
-			w -= 1
+	colWidths := t.prepareColWidths(bWidth)
+
+	err = t.pdf.renderArtifact(p, func() error {
+		if t.bgCol != nil {
+			x, w := r.LL.X+bWidth/2, t.Width-2*bWidth
+			if bWidth == 0 {
+				// Reduce artefacts.
+				x += .5
+				w -= 1
+			}
+			y := r.LL.Y + bWidth/2
+			r1 := types.RectForWidthAndHeight(x, y, w, r.Height()-.5)
+			draw.FillRect(p.Buf, r1, 0, bCol, *t.bgCol, &bStyle)
 		}
-		y := r.LL.Y + bWidth/2
-		r1 := types.RectForWidthAndHeight(x, y, w, r.Height()-.5)
-		draw.FillRect(p.Buf, r1, 0, bCol, *t.bgCol, &bStyle)
-	}
 
-	if t.Border != nil {
-		draw.DrawRect(p.Buf, r, bWidth, bCol, &bStyle)
-	}
+		if t.Border != nil {
+			draw.DrawRect(p.Buf, r, bWidth, bCol, &bStyle)
+		}
 
-	t.renderBackground(p, bWidth, r)
+		t.renderBackground(p, bWidth, r)
 
-	colWidths := t.prepareColWidths(bWidth)
+		if t.Grid {
+			t.renderGrid(p, colWidths, bWidth, bCol, r)
+		}
 
-	if t.Grid {
-		t.renderGrid(p, colWidths, bWidth, bCol, r)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	td, err := t.prepareTextDescriptor()
@@ -941,14 +972,25 @@ func (t *Table) render(p *model.Page, pageNr int, fonts model.FontMap) error {
 		return r.LL.X + bWidth/2 + x, y
 	}
 
+	// Table structure for tagged content.
+	tbl := t.pdf.newStructElem("Table")
+	var header *structElem
+	if t.Header != nil {
+		header = tbl.add("TR")
+	}
+	rows := make([]*structElem, t.Rows)
+	for i := 0; i < t.Rows && i < len(t.Values); i++ {
+		rows[i] = tbl.add("TR")
+	}
+
 	if len(t.Values) > 0 {
-		if err := t.renderValues(p, pageNr, fonts, colWidths, td, ll); err != nil {
+		if err := t.renderValues(p, pageNr, fonts, colWidths, td, ll, rows); err != nil {
 			return err
 		}
 	}
 
 	if t.Header != nil {
-		if err := t.renderHeader(p, pageNr, fonts, colWidths, td, ll); err != nil {
+		if err := t.renderHeader(p, pageNr, fonts, colWidths, td, ll, header); err != nil {
 			return err
 		}
 	}
@@ -51,6 +51,7 @@ type TextBox struct {
 	horAlign        types.HAlignment
 	RTL             bool
 	Rotation        float64 `json:"rot"`
+	Role            string  // structure type for tagged content: P (default), H1..H6, ...
 	Hide            bool
 }
 
@@ -161,9 +162,20 @@ func (tb *TextBox) validate() error {
 		return err
 	}
 
+	if err := validateTextRole(tb.Role); err != nil {
+		return err
+	}
+
 	return tb.validateHorAlign()
 }
 
+func (tb *TextBox) role() string {
+	if tb.Role == "" {
+		return "P"
+	}
+	return tb.Role
+}
+
 func (tb *TextBox) font(name string) *FormFont {
 	return tb.content.namedFont(name)
 }
@@ -237,6 +249,10 @@ func (tb *TextBox) mergeIn(tb0 *TextBox) {
 		tb.Rotation = tb0.Rotation
 	}
 
+	if tb.Role == "" {
+		tb.Role = tb0.Role
+	}
+
 	if !tb.Hide {
 		tb.Hide = tb0.Hide
 	}
@@ -320,6 +336,10 @@ func (tb *TextBox) prepareTextDescriptor(p *model.Page, pageNr int, fonts model.
 		RTL:      tb.RTL, // for user fonts only!
 	}
 
+	if err := pdf.setFallbackFonts(&td, p, fonts, pageNr); err != nil {
+		return nil, err
+	}
+
 	if col != nil {
 		td.StrokeCol, td.FillCol = *col, *col
 	}
@@ -392,7 +392,6 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 	for i := 0; i < len(lines); i++ {
 		s := lines[i]
 		lineBB := model.CalcBoundingBox(s, 0, 0, f.Name, f.Size)
-		s = model.PrepBytes(xRefTable, s, f.Name, !cjk, f.RTL(), f.FillFont)
 		x := 2 * boWidth
 		if x == 0 {
 			x = 2
@@ -415,6 +414,7 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 			x = 0.5
 			dx := w / float64(tf.MaxLen)
 			y0 := y
+			s = model.PrepBytes(xRefTable, s, f.Name, !cjk, f.RTL(), f.FillFont)
 			for j := 0; j < len(s) && j < tf.MaxLen; j++ {
 				fmt.Fprintf(buf, "%.2f %.2f Td (%c) Tj ", x, y0, s[j])
 				y0 = 0
@@ -422,7 +422,8 @@ func (tf *TextField) renderLines(xRefTable *model.XRefTable, boWidth, lh, w, y f
 			}
 			fmt.Fprint(buf, "ET ")
 		} else {
-			fmt.Fprintf(buf, "%.2f %.2f Td (%s) Tj ET ", x, y, s)
+			s = model.PrepText(xRefTable, s, f.Name, f.Size, !cjk, f.RTL(), f.FillFont)
+			fmt.Fprintf(buf, "%.2f %.2f Td %s ET ", x, y, s)
 		}
 
 		y -= lh
@@ -102,15 +102,46 @@ func ReadWithContext(c context.Context, rs io.ReadSeeker, conf *model.Configurat
 		}
 	}
 
+	// Only pdfcpu repair reconstructs the xRefTable of a damaged file.
+	if err = readContext(c, ctx, conf, ctx.Cmd == model.REPAIR); err != nil {
+		return nil, err
+	}
+
+	if log.ReadEnabled() {
+		log.Read.Println("Read: end")
+	}
+
+	return ctx, nil
+}
+
+func readContext(c context.Context, ctx *model.Context, conf *model.Configuration, rebuildXRef bool) (err error) {
 	// Populate xRefTable.
-	if err = readXRefTable(c, ctx); err != nil {
-		return nil, errors.Wrap(err, "Read: xRefTable failed")
+	if rebuildXRef {
+		err = rebuildXRefTable(c, ctx)
+	} else {
+		err = readXRefTable(c, ctx)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Read: xRefTable failed")
 	}
 
-	// Make all objects explicitly available (load into memory) in corresponding xRefTable entries.
-	// Also decode any involved object streams.
-	if err = dereferenceXRefTable(c, ctx, conf); err != nil {
-		return nil, err
+	if ctx.LazyRead {
+		// Defer parsing objects and loading streams until first access.
+		err = prepareLazyXRefTable(c, ctx)
+	} else {
+		// Make all objects explicitly available (load into memory) in corresponding xRefTable entries.
+		// Also decode any involved object streams.
+		err = dereferenceXRefTable(c, ctx, conf)
+	}
+	if err != nil {
+		return err
+	}
+
+	if rebuildXRef {
+		// A usable xRefTable leads to the document catalog.
+		if d, err := ctx.Catalog(); err != nil || d == nil {
+			return errors.Errorf("pdfcpu: Read: unable to dereference root catalog: %v", err)
+		}
 	}
 
 	// Some PDFWriters write an incorrect Size into trailer.
@@ -119,11 +150,7 @@ func ReadWithContext(c context.Context, rs io.ReadSeeker, conf *model.Configurat
 		model.ShowRepaired("trailer size")
 	}
 
-	if log.ReadEnabled() {
-		log.Read.Println("Read: end")
-	}
-
-	return ctx, nil
+	return nil
 }
 
 // fillBuffer reads from r until buf is full or read returns an error.
@@ -2536,7 +2563,12 @@ func decodeObjectStream(c context.Context, ctx *model.Context, objNr int) error
 	}
 
 	// Load encoded stream content to xRefTable.
-	if err = loadEncodedStreamContent(c, ctx, &sd, false); err != nil {
+	if ctx.Read.XRefRebuilt {
+		err = loadRepairedStreamContent(c, ctx, &sd, objNr)
+	} else {
+		err = loadEncodedStreamContent(c, ctx, &sd, false)
+	}
+	if err != nil {
 		return errors.Wrapf(err, "decodeObjectStream: problem dereferencing object stream %d", objNr)
 	}
 
@@ -2653,9 +2685,73 @@ func handleLinearizationParmDict(ctx *model.Context, obj types.Object, objNr int
 	return nil
 }
 
+// endstreamAt returns true if "endstream" follows offset in the source file, skipping eol.
+func endstreamAt(ctx *model.Context, offset int64) (bool, error) {
+	rd, err := newPositionedReader(ctx.Read.RS, &offset)
+	if err != nil {
+		return false, err
+	}
+
+	buf := make([]byte, 32)
+	n, err := fillBuffer(rd, buf)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return bytes.HasPrefix(bytes.TrimLeft(buf[:n], "\x00\t\n\f\r "), []byte("endstream")), nil
+}
+
+// detachStreamContent records the location of sd's encoded stream content in the source file instead of loading it.
+// Streams whose length can't be verified get loaded.
+func detachStreamContent(c context.Context, ctx *model.Context, sd *types.StreamDict) (bool, error) {
+	if ctx.EncKey != nil || ctx.DecodeAllStreams {
+		return false, nil
+	}
+
+	if sd.StreamLength == nil {
+		if sd.StreamLengthObjNr == nil {
+			return false, nil
+		}
+		l, err := int64Object(c, ctx, *sd.StreamLengthObjNr)
+		if err != nil {
+			if err != ErrReferenceDoesNotExist {
+				return false, err
+			}
+			return false, nil
+		}
+		sd.StreamLength = l
+	}
+
+	ok, err := endstreamAt(ctx, sd.StreamOffset+*sd.StreamLength)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	sd.Source = &types.StreamSource{RS: ctx.Read.RS, Offset: sd.StreamOffset}
+
+	return true, nil
+}
+
 func loadStreamDict(c context.Context, ctx *model.Context, sd *types.StreamDict, objNr, genNr int, fixLength bool) error {
+	if ctx.StreamWrite && !fixLength && !ctx.Read.XRefRebuilt {
+		ok, err := detachStreamContent(c, ctx, sd)
+		if err != nil {
+			return errors.Wrapf(err, "dereferenceObject: problem dereferencing stream %d", objNr)
+		}
+		if ok {
+			ctx.Read.BinaryTotalSize += *sd.StreamLength
+			return nil
+		}
+	}
+
 	// Load encoded stream content for stream dicts into xRefTable entry.
-	if err := loadEncodedStreamContent(c, ctx, sd, fixLength); err != nil {
+	var err error
+	if ctx.Read.XRefRebuilt && !fixLength {
+		err = loadRepairedStreamContent(c, ctx, sd, objNr)
+	} else {
+		err = loadEncodedStreamContent(c, ctx, sd, fixLength)
+	}
+	if err != nil {
 		return errors.Wrapf(err, "dereferenceObject: problem dereferencing stream %d", objNr)
 	}
 
@@ -2944,6 +3040,102 @@ func dereferenceXRefTable(c context.Context, ctx *model.Context, conf *model.Con
 	return nil
 }
 
+// loadObjectLazily parses objNr including any stream content on first access.
+func loadObjectLazily(c context.Context, ctx *model.Context, objNr int) error {
+	entry, ok := ctx.Find(objNr)
+	if !ok || entry.Free || entry.Object != nil {
+		return nil
+	}
+
+	if entry.Compressed {
+		osEntry, ok := ctx.Find(*entry.ObjectStream)
+		if !ok {
+			return errors.Errorf("pdfcpu: loadObjectLazily: missing object stream %d for obj#%d", *entry.ObjectStream, objNr)
+		}
+		if _, ok := osEntry.Object.(types.ObjectStreamDict); !ok {
+			if err := decodeObjectStream(c, ctx, *entry.ObjectStream); err != nil {
+				return err
+			}
+		}
+	}
+
+	return dereferenceObject(c, ctx, objNr)
+}
+
+// Prepare xRefTable for parsing objects and loading streams on first access.
+func prepareLazyXRefTable(c context.Context, ctx *model.Context) error {
+	if log.ReadEnabled() {
+		log.Read.Println("prepareLazyXRefTable: begin")
+	}
+
+	if err := checkForEncryption(c, ctx); err != nil {
+		return err
+	}
+
+	for objNr := range ctx.Table {
+		if objNr > ctx.MaxObjNr {
+			ctx.MaxObjNr = objNr
+		}
+	}
+
+	// Objects get loaded after reading has finished.
+	ctx.LoadObject = func(objNr int) error {
+		return loadObjectLazily(context.Background(), ctx, objNr)
+	}
+
+	// A linearization parm dict is always the first object in the file.
+	first, offset := 0, int64(-1)
+	for objNr, entry := range ctx.Table {
+		if entry.Free || entry.Compressed || entry.Offset == nil || *entry.Offset == 0 {
+			continue
+		}
+		if offset < 0 || *entry.Offset < offset {
+			first, offset = objNr, *entry.Offset
+		}
+	}
+	if offset > 0 {
+		if err := loadObjectLazily(c, ctx, first); err != nil {
+			return err
+		}
+	}
+
+	if err := identifyRootVersion(ctx.XRefTable); err != nil {
+		return err
+	}
+
+	if log.ReadEnabled() {
+		log.Read.Println("prepareLazyXRefTable: end")
+	}
+
+	return nil
+}
+
+// LoadLazyObjects parses all remaining objects of a lazily read ctx.
+func LoadLazyObjects(ctx *model.Context) error {
+	if !ctx.Lazy() {
+		return nil
+	}
+
+	c := context.Background()
+
+	for objNr := range ctx.Read.ObjectStreams {
+		entry, ok := ctx.Find(objNr)
+		if !ok {
+			continue
+		}
+		if _, ok := entry.Object.(types.ObjectStreamDict); ok {
+			continue
+		}
+		if err := decodeObjectStream(c, ctx, objNr); err != nil {
+			return err
+		}
+	}
+
+	ctx.LoadObject = nil
+
+	return dereferenceObjects(c, ctx)
+}
+
 func handleUnencryptedFile(ctx *model.Context) error {
 	if ctx.Cmd == model.DECRYPT || ctx.Cmd == model.SETPERMISSIONS {
 		return errors.New("pdfcpu: this file is not encrypted")
@@ -2955,7 +3147,7 @@ func handleUnencryptedFile(ctx *model.Context) error {
 
 	// Encrypt subcommand found.
 
-	if ctx.OwnerPW == "" {
+	if ctx.OwnerPW == "" && len(ctx.Recipients) == 0 {
 		return errors.New("pdfcpu: please provide owner password and optional user password")
 	}
 
@@ -2994,6 +3186,14 @@ func setupEncryptionKey(ctx *model.Context, d types.Dict) (err error) {
 		return err
 	}
 
+	if ctx.E.Recipients != nil {
+		// Public-key security handler.
+		if needsOwnerAndUserPassword(ctx.Cmd) {
+			return errors.New("pdfcpu: passwords and permissions not applicable for public-key encryption")
+		}
+		return setupPubSecEncryptionKey(ctx)
+	}
+
 	if ctx.E.ID, err = ctx.IDFirstElement(); err != nil {
 		return err
 	}
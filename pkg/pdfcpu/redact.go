@@ -0,0 +1,1159 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+var redactAreaRE = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// ParseRedactAreas parses a list of rectangles in user space like "[x1 y1 x2 y2] [x1 y1 x2 y2]" using display unit u.
+func ParseRedactAreas(s string, u types.DisplayUnit) ([]types.Rectangle, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("pdfcpu: missing redaction area")
+	}
+
+	mm := redactAreaRE.FindAllStringSubmatchIndex(s, -1)
+	if len(mm) == 0 || strings.TrimSpace(redactAreaRE.ReplaceAllString(s, "")) != "" {
+		return nil, errors.Errorf("pdfcpu: invalid redaction areas: %s", s)
+	}
+
+	var rr []types.Rectangle
+
+	for _, m := range mm {
+		ss := strings.Fields(s[m[2]:m[3]])
+		if len(ss) != 4 {
+			return nil, errors.Errorf("pdfcpu: invalid redaction area: %s", s[m[0]:m[1]])
+		}
+		var ff [4]float64
+		for i, s1 := range ss {
+			f, err := strconv.ParseFloat(s1, 64)
+			if err != nil {
+				return nil, errors.Errorf("pdfcpu: invalid redaction area: %s", s[m[0]:m[1]])
+			}
+			ff[i] = types.ToUserSpace(f, u)
+		}
+		r := types.NewRectangle(math.Min(ff[0], ff[2]), math.Min(ff[1], ff[3]), math.Max(ff[0], ff[2]), math.Max(ff[1], ff[3]))
+		if r.Width() == 0 || r.Height() == 0 {
+			return nil, errors.Errorf("pdfcpu: empty redaction area: %s", s[m[0]:m[1]])
+		}
+		rr = append(rr, *r)
+	}
+
+	return rr, nil
+}
+
+// redactArea is a page region to be redacted along with the color used to fill it.
+type redactArea struct {
+	rect types.Rectangle
+	fill []float64 // gray, rgb or cmyk, no fill if empty
+}
+
+// redactor rewrites content streams removing all text and image content intersecting the redaction areas.
+type redactor struct {
+	*textExtractor
+	areas []redactArea
+}
+
+func intersects(r1, r2 types.Rectangle) bool {
+	return r1.LL.X < r2.UR.X && r2.LL.X < r1.UR.X && r1.LL.Y < r2.UR.Y && r2.LL.Y < r1.UR.Y
+}
+
+func (rd *redactor) hit(r types.Rectangle) bool {
+	for _, a := range rd.areas {
+		if intersects(a.rect, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// unitSquareBox returns the bounding box of the unit square mapped by m, see 8.3.4.
+func unitSquareBox(m matrix.Matrix) types.Rectangle {
+	return boundingBox(
+		m.Transform(types.Point{X: 0, Y: 0}),
+		m.Transform(types.Point{X: 1, Y: 0}),
+		m.Transform(types.Point{X: 0, Y: 1}),
+		m.Transform(types.Point{X: 1, Y: 1}))
+}
+
+func invertMatrix(m matrix.Matrix) (matrix.Matrix, bool) {
+	a, b, c, d, e, f := m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1]
+	det := a*d - b*c
+	if det == 0 {
+		return m, false
+	}
+	return pdfMatrix(d/det, -b/det, -c/det, a/det, (c*f-d*e)/det, (b*e-a*f)/det), true
+}
+
+func tjNumber(f float64) types.Object {
+	f = math.Round(f*1000) / 1000
+	if f == math.Trunc(f) {
+		return types.Integer(int(f))
+	}
+	return types.Float(f)
+}
+
+// tjBuilder assembles the operand of a TJ operator.
+type tjBuilder struct {
+	a   types.Array
+	bb  []byte
+	adj float64
+}
+
+func (b *tjBuilder) flush() {
+	if len(b.bb) > 0 {
+		b.a = append(b.a, types.NewHexLiteral(b.bb))
+		b.bb = nil
+	}
+}
+
+func (b *tjBuilder) adjust(f float64) {
+	b.flush()
+	b.adj += f
+}
+
+func (b *tjBuilder) write(bb []byte) {
+	if b.adj != 0 {
+		b.a = append(b.a, tjNumber(b.adj))
+		b.adj = 0
+	}
+	b.bb = append(b.bb, bb...)
+}
+
+func (b *tjBuilder) array() types.Array {
+	b.flush()
+	if b.adj != 0 {
+		b.a = append(b.a, tjNumber(b.adj))
+		b.adj = 0
+	}
+	if b.a == nil {
+		return types.Array{}
+	}
+	return b.a
+}
+
+// advance returns the TJ position adjustment equivalent to showing g.
+func (rd *redactor) advance(g font.Glyph) float64 {
+	tfs := rd.gs.fontSize
+	if tfs == 0 {
+		return 0
+	}
+	var tw float64
+	if g.Len == 1 && g.Code == 32 {
+		tw = rd.gs.wordSpace
+	}
+	if rd.gs.font.Vertical {
+		return (tfs - rd.gs.charSpace - tw) * 1000 / tfs
+	}
+	return -(g.Width*tfs + rd.gs.charSpace + tw) * 1000 / tfs
+}
+
+// showString shows the glyphs of o replacing glyphs intersecting the redaction areas by position adjustments.
+func (rd *redactor) showString(o types.Object, b *tjBuilder) bool {
+	bb, err := types.StringBytes(o)
+	if err != nil {
+		return false
+	}
+
+	dec := rd.gs.font
+	if dec == nil {
+		b.write(bb)
+		return false
+	}
+
+	var removed bool
+
+	off := 0
+	for _, g := range dec.Decode(bb) {
+		if off+g.Len > len(bb) {
+			break
+		}
+		code := bb[off : off+g.Len]
+		off += g.Len
+
+		n := len(rd.chars)
+		rd.showGlyphs(code)
+		hit := false
+		for _, c := range rd.chars[n:] {
+			if rd.hit(c.bbox) {
+				hit = true
+			}
+		}
+		rd.chars = rd.chars[:n]
+
+		if !hit {
+			b.write(code)
+			continue
+		}
+
+		removed = true
+		b.adjust(rd.advance(g))
+	}
+
+	return removed
+}
+
+func (rd *redactor) showTextArray(operands []types.Object, b *tjBuilder) bool {
+	if len(operands) == 0 {
+		return false
+	}
+	a, ok := operands[len(operands)-1].(types.Array)
+	if !ok {
+		return false
+	}
+
+	var removed bool
+
+	for _, o := range a {
+		switch o := o.(type) {
+		case types.Integer:
+			rd.adjust(float64(o.Value()))
+			b.adjust(float64(o.Value()))
+		case types.Float:
+			rd.adjust(o.Value())
+			b.adjust(o.Value())
+		default:
+			if rd.showString(o, b) {
+				removed = true
+			}
+		}
+	}
+
+	return removed
+}
+
+// redactTextOperator returns the replacement for a text showing operator or nil if nothing was removed.
+func (rd *redactor) redactTextOperator(op model.ContentOperation) []model.ContentOperation {
+	b := &tjBuilder{}
+	tj := func() model.ContentOperation {
+		return model.ContentOperation{Operator: model.OpShowTextArray, Operands: []types.Object{b.array()}}
+	}
+
+	switch op.Operator {
+
+	case model.OpShowText:
+		if len(op.Operands) > 0 && rd.showString(op.Operands[len(op.Operands)-1], b) {
+			return []model.ContentOperation{tj()}
+		}
+
+	case model.OpShowTextArray:
+		if rd.showTextArray(op.Operands, b) {
+			return []model.ContentOperation{tj()}
+		}
+
+	case model.OpMoveShowText:
+		rd.nextLine()
+		if len(op.Operands) > 0 && rd.showString(op.Operands[len(op.Operands)-1], b) {
+			return []model.ContentOperation{{Operator: model.OpNextLine}, tj()}
+		}
+
+	case model.OpMoveSetShowText:
+		if len(op.Operands) != 3 {
+			return nil
+		}
+		if ff, ok := numbers(op.Operands[:2], 2); ok {
+			rd.gs.wordSpace, rd.gs.charSpace = ff[0], ff[1]
+		}
+		rd.nextLine()
+		if rd.showString(op.Operands[2], b) {
+			return []model.ContentOperation{
+				{Operator: model.OpSetWordSpacing, Operands: op.Operands[:1]},
+				{Operator: model.OpSetCharSpacing, Operands: op.Operands[1:2]},
+				{Operator: model.OpNextLine},
+				tj(),
+			}
+		}
+	}
+
+	return nil
+}
+
+// clearBits clears the bits from up to excluding to in row.
+func clearBits(row []byte, from, to int) {
+	for i := from; i < to; {
+		if i%8 == 0 && to-i >= 8 {
+			row[i/8] = 0
+			i += 8
+			continue
+		}
+		row[i/8] &^= 0x80 >> uint(i%8)
+		i++
+	}
+}
+
+// imageColorComponents returns the number of color components per sample of the image sd.
+func (rd *redactor) imageColorComponents(sd *types.StreamDict) (int, error) {
+	cs, found := sd.Find("ColorSpace")
+	if !found {
+		// Soft masks are DeviceGray.
+		return 1, nil
+	}
+	if indexedColorSpace(rd.ctx.XRefTable, cs) {
+		return 1, nil
+	}
+	n, err := ColorSpaceComponents(rd.ctx.XRefTable, sd)
+	if err == nil && n == 0 {
+		err = errors.New("pdfcpu: redact: unsupported image color space")
+	}
+	return n, err
+}
+
+// redactSamples returns the decoded samples of the image sd.
+func (rd *redactor) redactSamples(sd *types.StreamDict, comps int) ([]byte, error) {
+	for _, f := range sd.FilterPipeline {
+		if f.Name == filter.JBIG2 {
+			opts, err := rd.ctx.FilterOptions(sd)
+			if err != nil {
+				return nil, err
+			}
+			sd1 := *sd
+			if err := sd1.Decode(opts...); err != nil {
+				return nil, err
+			}
+			return sd1.Content, nil
+		}
+	}
+	return imageSamples(sd, comps)
+}
+
+// blankImage returns a copy of the image sd with all samples intersecting the redaction areas blanked.
+// The image is shown using the inverse inv of the ctm.
+// The copy keeps the color space and the Decode array and is encoded using FlateDecode.
+// Samples get blanked by clearing all their bits which works for any color space including image masks.
+func (rd *redactor) blankImage(sd *types.StreamDict, inv matrix.Matrix, masks bool) (*types.StreamDict, error) {
+	w, h := sd.IntEntry("Width"), sd.IntEntry("Height")
+	if w == nil || h == nil || *w <= 0 || *h <= 0 {
+		return nil, errors.New("pdfcpu: redact: missing image dimensions")
+	}
+
+	d := sd.Dict.Clone().(types.Dict)
+
+	var (
+		samples     []byte
+		comps, bpc  = 1, 1
+		alpha       []byte
+		err         error
+		isImageMask = sd.BooleanEntry("ImageMask")
+	)
+
+	switch {
+
+	case sd.HasSoleFilterNamed(filter.JPX):
+		raw, err := sd.RawContent()
+		if err != nil {
+			return nil, err
+		}
+		img, err := filter.DecodeJPX(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		samples, comps, bpc = img.Samples, img.NumComps, 8
+		if _, found := d.Find("ColorSpace"); !found {
+			cs, ok := map[int]string{1: model.DeviceGrayCS, 3: model.DeviceRGBCS, 4: model.DeviceCMYKCS}[comps]
+			if !ok {
+				return nil, errors.New("pdfcpu: redact: unsupported JPX color space")
+			}
+			d["ColorSpace"] = types.Name(cs)
+		}
+		if i := sd.IntEntry("SMaskInData"); i != nil && *i > 0 {
+			alpha = img.Alpha
+		}
+		// Decode arrays only apply to JPX image masks.
+		d.Delete("Decode")
+		d.Delete("SMaskInData")
+		d["BitsPerComponent"] = types.Integer(bpc)
+
+	case isImageMask != nil && *isImageMask:
+		if samples, err = rd.redactSamples(sd, 1); err != nil {
+			return nil, err
+		}
+
+	default:
+		i := sd.IntEntry("BitsPerComponent")
+		if i == nil {
+			return nil, errors.New("pdfcpu: redact: missing image BitsPerComponent")
+		}
+		bpc = *i
+		if comps, err = rd.imageColorComponents(sd); err != nil {
+			return nil, err
+		}
+		if samples, err = rd.redactSamples(sd, comps); err != nil {
+			return nil, err
+		}
+	}
+
+	if samples == nil {
+		return nil, errors.New("pdfcpu: redact: unsupported image encoding")
+	}
+
+	rowBytes := (*w*comps*bpc + 7) / 8
+	if len(samples) < rowBytes**h {
+		return nil, errors.New("pdfcpu: redact: corrupt image samples")
+	}
+	// Never touch the samples of the original image.
+	samples = append([]byte(nil), samples[:rowBytes**h]...)
+
+	box := unitSquareBox(rd.gs.ctm)
+	width, height := float64(*w), float64(*h)
+
+	for _, a := range rd.areas {
+		if !intersects(a.rect, box) {
+			continue
+		}
+		r := boundingBox(
+			inv.Transform(a.rect.LL),
+			inv.Transform(a.rect.UR),
+			inv.Transform(types.Point{X: a.rect.LL.X, Y: a.rect.UR.Y}),
+			inv.Transform(types.Point{X: a.rect.UR.X, Y: a.rect.LL.Y}))
+		// Image space has its origin in the upper left corner.
+		pr := image.Rect(
+			int(math.Floor(r.LL.X*width)), int(math.Floor((1-r.UR.Y)*height)),
+			int(math.Ceil(r.UR.X*width)), int(math.Ceil((1-r.LL.Y)*height))).Intersect(image.Rect(0, 0, *w, *h))
+		for y := pr.Min.Y; y < pr.Max.Y; y++ {
+			clearBits(samples[y*rowBytes:(y+1)*rowBytes], pr.Min.X*comps*bpc, pr.Max.X*comps*bpc)
+		}
+	}
+
+	if alpha != nil {
+		sm := types.NewStreamDict(types.Dict{
+			"Type":             types.Name("XObject"),
+			"Subtype":          types.Name("Image"),
+			"Width":            types.Integer(*w),
+			"Height":           types.Integer(*h),
+			"ColorSpace":       types.Name(model.DeviceGrayCS),
+			"BitsPerComponent": types.Integer(8),
+		}, 0, nil, nil, nil)
+		sm.Content = alpha
+		d["SMask"] = sm
+	}
+
+	// Soft masks and stencil masks might reveal the redacted content too.
+	for _, k := range []string{"SMask", "Mask"} {
+		if !masks {
+			break
+		}
+		o, err := rd.ctx.Dereference(d[k])
+		if err != nil {
+			return nil, err
+		}
+		sm, ok := o.(types.StreamDict)
+		if !ok {
+			continue
+		}
+		sm1, err := rd.blankImage(&sm, inv, false)
+		if err != nil {
+			return nil, err
+		}
+		ir, err := rd.ctx.IndRefForNewObject(*sm1)
+		if err != nil {
+			return nil, err
+		}
+		d[k] = *ir
+	}
+
+	d.Delete("DecodeParms")
+	d.Update("Filter", types.Name(filter.Flate))
+
+	sd1 := types.NewStreamDict(d, 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+	sd1.Content = samples
+	if err := sd1.Encode(); err != nil {
+		return nil, err
+	}
+
+	return &sd1, nil
+}
+
+// redactImage returns a copy of the image sd with all samples intersecting the redaction areas blanked
+// or nil if the image needs to be removed.
+// The image is shown using ctm.
+func (rd *redactor) redactImage(sd *types.StreamDict, objNr int) (*types.IndirectRef, error) {
+	inv, ok := invertMatrix(rd.gs.ctm)
+	if !ok {
+		// The image does not cover any area.
+		return nil, nil
+	}
+
+	sd1, err := rd.blankImage(sd, inv, true)
+	if err != nil {
+		model.ShowSkipped(fmt.Sprintf("redact: image obj#%d removed: %v", objNr, err))
+		return nil, nil
+	}
+
+	return rd.ctx.IndRefForNewObject(*sd1)
+}
+
+// redactForm returns a copy of the form sd with its content redacted or nil if nothing was removed.
+func (rd *redactor) redactForm(sd *types.StreamDict, objNr int, res types.Dict) (*types.IndirectRef, error) {
+	if rd.forms[objNr] {
+		// Recursive form.
+		return nil, nil
+	}
+
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+
+	rd.forms[objNr] = true
+	defer delete(rd.forms, objNr)
+
+	rd.saveGraphicsState()
+	defer rd.restoreGraphicsState()
+
+	if a, err := rd.ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(a) == 6 {
+		if ff, ok := numbers(a, 6); ok {
+			rd.gs.ctm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5]).Multiply(rd.gs.ctm)
+		}
+	}
+
+	if d, err := rd.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && d != nil {
+		res = d
+		rd.gs.resources = d
+		rd.gs.fontsCache = map[string]*font.Decoder{}
+	}
+
+	tm, tlm := rd.tm, rd.tlm
+	defer func() { rd.tm, rd.tlm = tm, tlm }()
+
+	bb, res1, changed, err := rd.redactContent(sd.Content, res)
+	if err != nil || !changed {
+		return nil, err
+	}
+
+	sd1 := types.StreamDict{
+		Dict:           sd.Dict.Clone().(types.Dict),
+		Content:        bb,
+		FilterPipeline: []types.PDFFilter{{Name: filter.Flate}},
+	}
+	sd1.Delete("DecodeParms")
+	sd1.Update("Filter", types.Name(filter.Flate))
+	sd1.Update("Resources", res1)
+
+	if err := sd1.Encode(); err != nil {
+		return nil, err
+	}
+
+	return rd.ctx.IndRefForNewObject(sd1)
+}
+
+// redactXObject handles the Do operator.
+// It returns false if the XObject needs to be dropped and updates xo for XObjects that got replaced by redacted copies.
+func (rd *redactor) redactXObject(operands []types.Object, res types.Dict, xo *types.Dict) (bool, error) {
+	if len(operands) == 0 {
+		return true, nil
+	}
+	n, ok := operands[len(operands)-1].(types.Name)
+	if !ok {
+		return true, nil
+	}
+
+	d, err := rd.ctx.DereferenceDict(res["XObject"])
+	if err != nil || d == nil {
+		return true, err
+	}
+
+	o, found := d.Find(n.Value())
+	if !found {
+		return true, nil
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return true, nil
+	}
+	objNr := indRef.ObjectNumber.Value()
+
+	sd, _, err := rd.ctx.DereferenceStreamDict(indRef)
+	if err != nil || sd == nil {
+		return true, err
+	}
+
+	st := sd.Subtype()
+	if st == nil {
+		return true, nil
+	}
+
+	var ir *types.IndirectRef
+
+	switch *st {
+
+	case "Image":
+		if !rd.hit(unitSquareBox(rd.gs.ctm)) {
+			return true, nil
+		}
+		if ir, err = rd.redactImage(sd, objNr); err != nil {
+			return false, err
+		}
+		if ir == nil {
+			if log.DebugEnabled() {
+				log.Debug.Printf("redact: removing image obj#%d\n", objNr)
+			}
+			return false, nil
+		}
+
+	case "Form":
+		if ir, err = rd.redactForm(sd, objNr, res); err != nil || ir == nil {
+			return true, err
+		}
+
+	default:
+		return true, nil
+	}
+
+	if *xo == nil {
+		*xo = d.Clone().(types.Dict)
+	}
+	(*xo)[n.Value()] = *ir
+
+	return true, nil
+}
+
+// redactContent rewrites content bb using resources res.
+// It returns the new content along with the resources to be used and whether anything was removed.
+func (rd *redactor) redactContent(bb []byte, res types.Dict) ([]byte, types.Dict, bool, error) {
+	var (
+		ops     []model.ContentOperation
+		xo      types.Dict
+		changed bool
+	)
+
+	p := model.NewContentParser(bb)
+
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		switch op.Operator {
+
+		case model.OpShowText, model.OpShowTextArray, model.OpMoveShowText, model.OpMoveSetShowText:
+			if ops1 := rd.redactTextOperator(*op); ops1 != nil {
+				ops = append(ops, ops1...)
+				changed = true
+				continue
+			}
+
+		case model.OpBeginInlineImage:
+			if rd.hit(unitSquareBox(rd.gs.ctm)) {
+				changed = true
+				continue
+			}
+
+		case model.OpPaintXObject:
+			keep, err := rd.redactXObject(op.Operands, res, &xo)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			if !keep {
+				changed = true
+				continue
+			}
+
+		default:
+			if err := rd.processOperator(op.Operator, op.Operands); err != nil {
+				return nil, nil, false, err
+			}
+		}
+
+		ops = append(ops, *op)
+	}
+
+	if xo != nil {
+		res1 := types.Dict{}
+		for k, v := range res {
+			res1[k] = v
+		}
+		res1["XObject"] = xo
+		res, changed = res1, true
+	}
+
+	return model.ContentStreamBytes(ops), res, changed, nil
+}
+
+func redactFillColor(a types.Array) []float64 {
+	ff, ok := numbers(a, len(a))
+	if !ok || (len(ff) != 1 && len(ff) != 3 && len(ff) != 4) {
+		return nil
+	}
+	return ff
+}
+
+// redactAnnotAreas returns the areas marked by Redact annotations along with the obj#s of these annotations and their popups.
+func redactAnnotAreas(ctx *model.Context, d types.Dict) ([]redactArea, types.IntSet, error) {
+	annots, err := ctx.DereferenceArray(d["Annots"])
+	if err != nil || len(annots) == 0 {
+		return nil, nil, err
+	}
+
+	var aa []redactArea
+	objNrs := types.IntSet{}
+
+	for _, o := range annots {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+		d1, err := ctx.DereferenceDict(indRef)
+		if err != nil || d1 == nil {
+			return nil, nil, err
+		}
+		if st := d1.NameEntry("Subtype"); st == nil || *st != "Redact" {
+			continue
+		}
+
+		objNrs[indRef.ObjectNumber.Value()] = true
+		if ir := d1.IndirectRefEntry("Popup"); ir != nil {
+			objNrs[ir.ObjectNumber.Value()] = true
+		}
+
+		var fill []float64
+		if a, err := ctx.DereferenceArray(d1["IC"]); err == nil {
+			fill = redactFillColor(a)
+		}
+
+		if qp, err := ctx.DereferenceArray(d1["QuadPoints"]); err == nil && len(qp) >= 8 && len(qp)%8 == 0 {
+			if ff, ok := numbers(qp, len(qp)); ok {
+				for i := 0; i < len(ff); i += 8 {
+					r := boundingBox(
+						types.Point{X: ff[i], Y: ff[i+1]}, types.Point{X: ff[i+2], Y: ff[i+3]},
+						types.Point{X: ff[i+4], Y: ff[i+5]}, types.Point{X: ff[i+6], Y: ff[i+7]})
+					aa = append(aa, redactArea{rect: r, fill: fill})
+				}
+				continue
+			}
+		}
+
+		if r, err := ctx.RectForArray(d1.ArrayEntry("Rect")); err == nil && r != nil {
+			aa = append(aa, redactArea{rect: *r, fill: fill})
+		}
+	}
+
+	return aa, objNrs, nil
+}
+
+// normalizedAnnotRect returns the normalized rectangle of the annotation d.
+func normalizedAnnotRect(ctx *model.Context, d types.Dict) (*types.Rectangle, error) {
+	a, err := ctx.DereferenceArray(d["Rect"])
+	if err != nil || len(a) != 4 {
+		return nil, err
+	}
+	r, err := ctx.RectForArray(a)
+	if err != nil {
+		return nil, err
+	}
+	box := boundingBox(r.LL, r.UR)
+	return &box, nil
+}
+
+// annotAppearance returns the normal appearance of the annotation d.
+func annotAppearance(ctx *model.Context, d types.Dict) (*types.IndirectRef, *types.StreamDict, error) {
+	ap, err := ctx.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return nil, nil, err
+	}
+
+	o := ap["N"]
+	if d1, err := ctx.DereferenceDict(o); err == nil && d1 != nil {
+		// Appearance subdictionary.
+		as := d.NameEntry("AS")
+		if as == nil {
+			return nil, nil, nil
+		}
+		o = d1[*as]
+	}
+
+	ir, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil, nil, nil
+	}
+
+	sd, _, err := ctx.DereferenceStreamDict(ir)
+	if err != nil || sd == nil {
+		return nil, nil, err
+	}
+
+	return &ir, sd, nil
+}
+
+// appearanceMatrix returns the matrix mapping the appearance sd into rectangle r, see 12.5.5.
+func appearanceMatrix(ctx *model.Context, sd *types.StreamDict, r types.Rectangle) (matrix.Matrix, bool) {
+	a, err := ctx.DereferenceArray(sd.Dict["BBox"])
+	if err != nil || len(a) != 4 {
+		return matrix.IdentMatrix, false
+	}
+	ff, ok := numbers(a, 4)
+	if !ok {
+		return matrix.IdentMatrix, false
+	}
+
+	m := matrix.IdentMatrix
+	if a, err := ctx.DereferenceArray(sd.Dict["Matrix"]); err == nil && len(a) == 6 {
+		if ff, ok := numbers(a, 6); ok {
+			m = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+		}
+	}
+
+	box := boundingBox(
+		m.Transform(types.Point{X: ff[0], Y: ff[1]}), m.Transform(types.Point{X: ff[2], Y: ff[3]}),
+		m.Transform(types.Point{X: ff[0], Y: ff[3]}), m.Transform(types.Point{X: ff[2], Y: ff[1]}))
+	if box.Width() == 0 || box.Height() == 0 {
+		return matrix.IdentMatrix, false
+	}
+
+	sx, sy := r.Width()/box.Width(), r.Height()/box.Height()
+
+	return pdfMatrix(sx, 0, 0, sy, r.LL.X-box.LL.X*sx, r.LL.Y-box.LL.Y*sy), true
+}
+
+// flattenAnnots paints the appearances of visible annotations overlapping the redaction areas
+// so they get redacted along with the page content.
+// The appearances are added to the XObjects of res.
+// It returns the content painting the appearances along with the obj#s of the overlapping annotations and their popups.
+func (rd *redactor) flattenAnnots(d, res types.Dict, skip types.IntSet) ([]byte, map[int]types.Dict, error) {
+	annots, err := rd.ctx.DereferenceArray(d["Annots"])
+	if err != nil || len(annots) == 0 {
+		return nil, nil, err
+	}
+
+	var (
+		buf bytes.Buffer
+		xo  types.Dict
+	)
+	m := map[int]types.Dict{}
+
+	for _, o := range annots {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok || skip[indRef.ObjectNumber.Value()] {
+			continue
+		}
+		d1, err := rd.ctx.DereferenceDict(indRef)
+		if err != nil || d1 == nil {
+			return nil, nil, err
+		}
+		if st := d1.NameEntry("Subtype"); st != nil && *st == "Popup" {
+			// Popups go along with their parent.
+			continue
+		}
+
+		r, err := normalizedAnnotRect(rd.ctx, d1)
+		if err != nil || r == nil || !rd.hit(*r) {
+			continue
+		}
+
+		m[indRef.ObjectNumber.Value()] = d1
+		if ir := d1.IndirectRefEntry("Popup"); ir != nil {
+			m[ir.ObjectNumber.Value()] = nil
+		}
+
+		if f := d1.IntEntry("F"); f != nil && model.AnnotationFlags(*f)&(model.AnnHidden|model.AnnNoView) > 0 {
+			continue
+		}
+
+		ir, sd, err := annotAppearance(rd.ctx, d1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sd == nil {
+			continue
+		}
+		mx, ok := appearanceMatrix(rd.ctx, sd, *r)
+		if !ok {
+			continue
+		}
+
+		if xo == nil {
+			xo = types.Dict{}
+			if d2, err := rd.ctx.DereferenceDict(res["XObject"]); err == nil {
+				for k, v := range d2 {
+					xo[k] = v
+				}
+			}
+			res["XObject"] = xo
+		}
+		n := fmt.Sprintf("Annot%d", indRef.ObjectNumber.Value())
+		for _, found := xo.Find(n); found; _, found = xo.Find(n) {
+			n += "_"
+		}
+		xo[n] = *ir
+
+		fmt.Fprintf(&buf, "q %.5f %.5f %.5f %.5f %.5f %.5f cm /%s Do Q\n", mx[0][0], mx[0][1], mx[1][0], mx[1][1], mx[2][0], mx[2][1], n)
+	}
+
+	return buf.Bytes(), m, nil
+}
+
+// removeIndRef returns a without ir.
+func removeIndRef(a types.Array, ir types.IndirectRef) types.Array {
+	a1 := types.Array{}
+	for _, o := range a {
+		if ir1, ok := o.(types.IndirectRef); ok && ir1.ObjectNumber == ir.ObjectNumber {
+			continue
+		}
+		a1 = append(a1, o)
+	}
+	return a1
+}
+
+// removeFormField removes the field or widget d from the field hierarchy of the form.
+// Parent fields without any kids left get removed along with their values.
+func removeFormField(ctx *model.Context, ir types.IndirectRef, d types.Dict) error {
+	af, err := ctx.DereferenceDict(ctx.RootDict["AcroForm"])
+	if err != nil || af == nil {
+		return err
+	}
+
+	if co, err := ctx.DereferenceArray(af["CO"]); err == nil && co != nil {
+		af["CO"] = removeIndRef(co, ir)
+	}
+
+	if p := d.IndirectRefEntry("Parent"); p != nil {
+		pd, err := ctx.DereferenceDict(*p)
+		if err != nil || pd == nil {
+			return err
+		}
+		kids, err := ctx.DereferenceArray(pd["Kids"])
+		if err != nil {
+			return err
+		}
+		if kids = removeIndRef(kids, ir); len(kids) > 0 {
+			pd["Kids"] = kids
+			return nil
+		}
+		pd.Delete("Kids")
+		return removeFormField(ctx, *p, pd)
+	}
+
+	fields, err := ctx.DereferenceArray(af["Fields"])
+	if err != nil {
+		return err
+	}
+	if fields = removeIndRef(fields, ir); len(fields) > 0 {
+		af["Fields"] = fields
+		return nil
+	}
+
+	ctx.RootDict.Delete("AcroForm")
+
+	return nil
+}
+
+// removeOverlappingAnnots removes the annotations m from page d.
+// Form fields lose their widgets in the redaction areas.
+func removeOverlappingAnnots(ctx *model.Context, d types.Dict, pageNr int, m map[int]types.Dict) error {
+	annots, err := ctx.DereferenceArray(d["Annots"])
+	if err != nil {
+		return err
+	}
+
+	a := types.Array{}
+	for _, o := range annots {
+		ir, ok := o.(types.IndirectRef)
+		if !ok {
+			a = append(a, o)
+			continue
+		}
+		d1, found := m[ir.ObjectNumber.Value()]
+		if !found {
+			a = append(a, o)
+			continue
+		}
+		// Not every annotation is cached.
+		_ = removeAnnotationFromCache(ctx, pageNr, ir.ObjectNumber.Value())
+		if st := d1.NameEntry("Subtype"); st != nil && *st == "Widget" {
+			if err := removeFormField(ctx, ir, d1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(a) == 0 {
+		d.Delete("Annots")
+		return nil
+	}
+
+	d["Annots"] = a
+
+	return nil
+}
+
+func redactOverlay(aa []redactArea) []byte {
+	var buf bytes.Buffer
+	for _, a := range aa {
+		if len(a.fill) == 0 {
+			continue
+		}
+		op := map[int]string{1: "g", 3: "rg", 4: "k"}[len(a.fill)]
+		buf.WriteString("q ")
+		for _, f := range a.fill {
+			fmt.Fprintf(&buf, "%.3f ", f)
+		}
+		fmt.Fprintf(&buf, "%s %.2f %.2f %.2f %.2f re f Q\n", op, a.rect.LL.X, a.rect.LL.Y, a.rect.Width(), a.rect.Height())
+	}
+	return buf.Bytes()
+}
+
+// RedactPage permanently removes all text and image content of pageNr intersecting areas in user space
+// as well as content marked by Redact annotations.
+// The areas get filled with black, areas marked by Redact annotations get filled using their interior color.
+// Annotations and form field widgets overlapping the areas get their appearances flattened into the page and redacted.
+// Finally the Redact annotations and all overlapping annotations are removed.
+func RedactPage(ctx *model.Context, pageNr int, areas []types.Rectangle) (bool, error) {
+	d, pageIndRef, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return false, err
+	}
+	if d == nil {
+		return false, errors.Errorf("pdfcpu: unknown page number: %d", pageNr)
+	}
+
+	var aa []redactArea
+	for _, r := range areas {
+		aa = append(aa, redactArea{rect: r, fill: []float64{0}})
+	}
+
+	annotAreas, annotObjNrs, err := redactAnnotAreas(ctx, d)
+	if err != nil {
+		return false, err
+	}
+	aa = append(aa, annotAreas...)
+
+	if len(aa) == 0 {
+		return false, nil
+	}
+
+	bb, err := ctx.PageContent(d)
+	if err != nil && err != model.ErrNoContent {
+		return false, err
+	}
+
+	res := types.Dict{}
+	if inhPAttrs.Resources != nil {
+		res = inhPAttrs.Resources.Clone().(types.Dict)
+	}
+
+	rd := &redactor{
+		textExtractor: &textExtractor{
+			ctx:   ctx,
+			fonts: map[int]*font.Decoder{},
+			forms: types.IntSet{},
+			gs: textState{
+				hScale:     1,
+				ctm:        matrix.IdentMatrix,
+				resources:  res,
+				fontsCache: map[string]*font.Decoder{},
+			},
+		},
+		areas: aa,
+	}
+
+	flat, overlapping, err := rd.flattenAnnots(d, res, annotObjNrs)
+	if err != nil {
+		return false, err
+	}
+	if len(flat) > 0 {
+		bb = append(append(bb, '\n'), flat...)
+	}
+
+	bb, res, changed, err := rd.redactContent(bb, res)
+	if err != nil {
+		return false, err
+	}
+
+	if changed || len(flat) > 0 {
+		d["Resources"] = res
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("q\n")
+	buf.Write(bb)
+	buf.WriteString("Q\n")
+	buf.Write(redactOverlay(aa))
+
+	sd, _ := ctx.NewStreamDictForBuf(buf.Bytes())
+	if err := sd.Encode(); err != nil {
+		return false, err
+	}
+
+	ir, err := ctx.IndRefForNewObject(*sd)
+	if err != nil {
+		return false, err
+	}
+
+	d["Contents"] = *ir
+
+	if len(overlapping) > 0 {
+		if err := removeOverlappingAnnots(ctx, d, pageNr, overlapping); err != nil {
+			return false, err
+		}
+	}
+
+	if len(annotObjNrs) > 0 {
+		if _, err := RemoveAnnotationsFromPageDict(ctx, nil, nil, annotObjNrs, d, pageIndRef.ObjectNumber.Value(), pageNr, false); err != nil {
+			return false, err
+		}
+	}
+
+	if log.CLIEnabled() {
+		log.CLI.Printf("page %d: redacted %d areas\n", pageNr, len(aa))
+	}
+
+	return true, nil
+}
+
+// Redact permanently removes all text and image content of selected pages intersecting areas
+// as well as content marked by Redact annotations.
+func Redact(ctx *model.Context, selectedPages types.IntSet, areas []types.Rectangle) error {
+	var pageNrs []int
+	for pageNr, v := range selectedPages {
+		if v {
+			pageNrs = append(pageNrs, pageNr)
+		}
+	}
+	sort.Ints(pageNrs)
+
+	for _, pageNr := range pageNrs {
+		if _, err := RedactPage(ctx, pageNr, areas); err != nil {
+			return err
+		}
+	}
+
+	ctx.EnsureVersionForWriting()
+
+	return nil
+}
@@ -0,0 +1,428 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// colorSpace converts color components into device RGB, see 8.6.
+type colorSpace interface {
+	ncomps() int
+	rgb(comps []float64) color.NRGBA
+	initial() []float64
+	defaultDecode(bpc int) []float64
+}
+
+func unitByte(f float64) uint8 {
+	return uint8(clamp(f, 0, 1)*255 + 0.5)
+}
+
+func unitDecode(n int) []float64 {
+	d := make([]float64, 2*n)
+	for i := 0; i < n; i++ {
+		d[2*i+1] = 1
+	}
+	return d
+}
+
+func comp(comps []float64, i int) float64 {
+	if i < len(comps) {
+		return comps[i]
+	}
+	return 0
+}
+
+type deviceGray struct{}
+
+func (deviceGray) ncomps() int                     { return 1 }
+func (deviceGray) initial() []float64              { return []float64{0} }
+func (deviceGray) defaultDecode(bpc int) []float64 { return unitDecode(1) }
+
+func (deviceGray) rgb(comps []float64) color.NRGBA {
+	g := unitByte(comp(comps, 0))
+	return color.NRGBA{g, g, g, 255}
+}
+
+type deviceRGB struct{}
+
+func (deviceRGB) ncomps() int                     { return 3 }
+func (deviceRGB) initial() []float64              { return []float64{0, 0, 0} }
+func (deviceRGB) defaultDecode(bpc int) []float64 { return unitDecode(3) }
+
+func (deviceRGB) rgb(comps []float64) color.NRGBA {
+	return color.NRGBA{unitByte(comp(comps, 0)), unitByte(comp(comps, 1)), unitByte(comp(comps, 2)), 255}
+}
+
+type deviceCMYK struct{}
+
+func (deviceCMYK) ncomps() int                     { return 4 }
+func (deviceCMYK) initial() []float64              { return []float64{0, 0, 0, 1} }
+func (deviceCMYK) defaultDecode(bpc int) []float64 { return unitDecode(4) }
+
+func (deviceCMYK) rgb(comps []float64) color.NRGBA {
+	k := clamp(comp(comps, 3), 0, 1)
+	return color.NRGBA{
+		unitByte((1 - clamp(comp(comps, 0), 0, 1)) * (1 - k)),
+		unitByte((1 - clamp(comp(comps, 1), 0, 1)) * (1 - k)),
+		unitByte((1 - clamp(comp(comps, 2), 0, 1)) * (1 - k)),
+		255,
+	}
+}
+
+// labSpace is a CIE L*a*b* color space, see 8.6.5.4.
+type labSpace struct {
+	rng [4]float64
+}
+
+func (labSpace) ncomps() int        { return 3 }
+func (labSpace) initial() []float64 { return []float64{0, 0, 0} }
+func (cs labSpace) defaultDecode(bpc int) []float64 {
+	return []float64{0, 100, cs.rng[0], cs.rng[1], cs.rng[2], cs.rng[3]}
+}
+
+func srgbGamma(f float64) uint8 {
+	f = clamp(f, 0, 1)
+	if f <= 0.0031308 {
+		return unitByte(12.92 * f)
+	}
+	return unitByte(1.055*math.Pow(f, 1/2.4) - 0.055)
+}
+
+func (cs labSpace) rgb(comps []float64) color.NRGBA {
+	l := clamp(comp(comps, 0), 0, 100)
+	a := clamp(comp(comps, 1), cs.rng[0], cs.rng[1])
+	b := clamp(comp(comps, 2), cs.rng[2], cs.rng[3])
+
+	g := func(t float64) float64 {
+		if t > 6.0/29 {
+			return t * t * t
+		}
+		return 108.0 / 841 * (t - 4.0/29)
+	}
+
+	fy := (l + 16) / 116
+	x := 0.9505 * g(fy+a/500)
+	y := g(fy)
+	z := 1.089 * g(fy-b/200)
+
+	return color.NRGBA{
+		srgbGamma(3.2406*x - 1.5372*y - 0.4986*z),
+		srgbGamma(-0.9689*x + 1.8758*y + 0.0415*z),
+		srgbGamma(0.0557*x - 0.2040*y + 1.0570*z),
+		255,
+	}
+}
+
+// indexedSpace maps color table indices to colors of a base color space, see 8.6.6.3.
+type indexedSpace struct {
+	base   colorSpace
+	hival  int
+	lookup []byte
+}
+
+func (indexedSpace) ncomps() int        { return 1 }
+func (indexedSpace) initial() []float64 { return []float64{0} }
+func (cs indexedSpace) defaultDecode(bpc int) []float64 {
+	return []float64{0, float64(int(1)<<bpc - 1)}
+}
+
+func (cs indexedSpace) rgb(comps []float64) color.NRGBA {
+	i := int(clamp(math.Round(comp(comps, 0)), 0, float64(cs.hival)))
+	n := cs.base.ncomps()
+	cc := make([]float64, n)
+	dec := cs.base.defaultDecode(8)
+	for j := 0; j < n; j++ {
+		var v byte
+		if k := i*n + j; k < len(cs.lookup) {
+			v = cs.lookup[k]
+		}
+		cc[j] = interpolate(float64(v), 0, 255, dec[2*j], dec[2*j+1])
+	}
+	return cs.base.rgb(cc)
+}
+
+// tintSpace is a Separation or DeviceN color space using a tint transform function, see 8.6.6.4 and 8.6.6.5.
+type tintSpace struct {
+	n         int
+	alt       colorSpace
+	tint      function
+	none      bool // Separation /None does not mark the page
+	cache     map[float64]color.NRGBA
+	cacheable bool
+}
+
+func (cs *tintSpace) ncomps() int { return cs.n }
+
+func (cs *tintSpace) initial() []float64 {
+	ff := make([]float64, cs.n)
+	for i := range ff {
+		ff[i] = 1
+	}
+	return ff
+}
+
+func (cs *tintSpace) defaultDecode(bpc int) []float64 { return unitDecode(cs.n) }
+
+func (cs *tintSpace) rgb(comps []float64) color.NRGBA {
+	if cs.none {
+		return color.NRGBA{}
+	}
+	if cs.cacheable && len(comps) > 0 {
+		if c, ok := cs.cache[comps[0]]; ok {
+			return c
+		}
+	}
+	c := cs.alt.rgb(cs.tint.eval(comps))
+	if cs.cacheable && len(comps) > 0 && len(cs.cache) < 4096 {
+		cs.cache[comps[0]] = c
+	}
+	return c
+}
+
+// patternSpace is the Pattern color space whose colors are provided by pattern paints, see 8.6.6.2.
+type patternSpace struct {
+	base colorSpace // for uncolored tiling patterns
+}
+
+func (cs patternSpace) ncomps() int {
+	if cs.base != nil {
+		return cs.base.ncomps()
+	}
+	return 0
+}
+
+func (patternSpace) initial() []float64              { return nil }
+func (patternSpace) defaultDecode(bpc int) []float64 { return nil }
+
+func (cs patternSpace) rgb(comps []float64) color.NRGBA {
+	if cs.base != nil {
+		return cs.base.rgb(comps)
+	}
+	return color.NRGBA{A: 255}
+}
+
+func deviceSpace(n int) colorSpace {
+	switch n {
+	case 1:
+		return deviceGray{}
+	case 4:
+		return deviceCMYK{}
+	}
+	return deviceRGB{}
+}
+
+func namedColorSpace(n string) colorSpace {
+	switch n {
+	case "DeviceGray", "G", "CalGray":
+		return deviceGray{}
+	case "DeviceRGB", "RGB", "CalRGB":
+		return deviceRGB{}
+	case "DeviceCMYK", "CMYK":
+		return deviceCMYK{}
+	case "Pattern":
+		return patternSpace{}
+	}
+	return nil
+}
+
+// colorSpace resolves o which is either the name of a color space family,
+// the name of a color space resource or a color space array.
+func (r *renderer) colorSpace(o types.Object, res types.Dict) (colorSpace, error) {
+	if r.csDepth > 16 {
+		return nil, errors.New("pdfcpu: color space nesting too deep")
+	}
+	r.csDepth++
+	defer func() { r.csDepth-- }()
+
+	o, err := r.ctx.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		if cs := namedColorSpace(o.Value()); cs != nil {
+			return cs, nil
+		}
+		d, err := r.ctx.DereferenceDict(res["ColorSpace"])
+		if err != nil {
+			return nil, err
+		}
+		o1, found := d.Find(o.Value())
+		if !found {
+			return nil, errors.Errorf("pdfcpu: unknown color space: %s", o.Value())
+		}
+		return r.colorSpace(o1, res)
+
+	case types.Array:
+		return r.colorSpaceArray(o, res)
+	}
+
+	return nil, errors.Errorf("pdfcpu: invalid color space: %v", o)
+}
+
+func (r *renderer) iccBasedSpace(o types.Object, res types.Dict) (colorSpace, error) {
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, errors.New("pdfcpu: corrupt ICCBased color space")
+	}
+	if o, found := sd.Dict.Find("Alternate"); found {
+		if cs, err := r.colorSpace(o, res); err == nil {
+			return cs, nil
+		}
+	}
+	n := 3
+	if i := sd.IntEntry("N"); i != nil {
+		n = *i
+	}
+	return deviceSpace(n), nil
+}
+
+func (r *renderer) labSpace(o types.Object) (colorSpace, error) {
+	cs := labSpace{rng: [4]float64{-100, 100, -100, 100}}
+	d, err := r.ctx.DereferenceDict(o)
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		if ff, err := numberArray(r.ctx.XRefTable, d["Range"]); err == nil && len(ff) == 4 {
+			copy(cs.rng[:], ff)
+		}
+	}
+	return cs, nil
+}
+
+func (r *renderer) indexedSpace(a types.Array, res types.Dict) (colorSpace, error) {
+	if len(a) < 4 {
+		return nil, errors.New("pdfcpu: corrupt Indexed color space")
+	}
+	base, err := r.colorSpace(a[1], res)
+	if err != nil {
+		return nil, err
+	}
+	hival, err := r.ctx.DereferenceNumber(a[2])
+	if err != nil {
+		return nil, err
+	}
+	cs := indexedSpace{base: base, hival: int(clamp(hival, 0, 255))}
+
+	o, err := r.ctx.Dereference(a[3])
+	if err != nil {
+		return nil, err
+	}
+	switch o := o.(type) {
+	case types.StringLiteral, types.HexLiteral:
+		if cs.lookup, err = types.StringBytes(o); err != nil {
+			return nil, err
+		}
+	case types.StreamDict:
+		if err := o.Decode(); err != nil {
+			return nil, err
+		}
+		cs.lookup = o.Content
+	default:
+		return nil, errors.New("pdfcpu: corrupt Indexed color space lookup")
+	}
+
+	return cs, nil
+}
+
+func (r *renderer) tintSpace(a types.Array, res types.Dict) (colorSpace, error) {
+	if len(a) < 4 {
+		return nil, errors.New("pdfcpu: corrupt Separation/DeviceN color space")
+	}
+
+	cs := &tintSpace{n: 1, cache: map[float64]color.NRGBA{}}
+
+	if a[0].(types.Name).Value() == "DeviceN" {
+		names, err := r.ctx.DereferenceArray(a[1])
+		if err != nil {
+			return nil, err
+		}
+		cs.n = len(names)
+	} else if n, ok := a[1].(types.Name); ok && n.Value() == "None" {
+		cs.none = true
+	}
+	cs.cacheable = cs.n == 1
+
+	alt, err := r.colorSpace(a[2], res)
+	if err != nil {
+		return nil, err
+	}
+	cs.alt = alt
+
+	if cs.tint, err = r.function(a[3]); err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+func (r *renderer) colorSpaceArray(a types.Array, res types.Dict) (colorSpace, error) {
+	if len(a) == 0 {
+		return nil, errors.New("pdfcpu: empty color space array")
+	}
+	n, ok := a[0].(types.Name)
+	if !ok {
+		return nil, errors.Errorf("pdfcpu: invalid color space: %v", a)
+	}
+
+	switch n.Value() {
+
+	case "ICCBased":
+		if len(a) < 2 {
+			return nil, errors.New("pdfcpu: corrupt ICCBased color space")
+		}
+		return r.iccBasedSpace(a[1], res)
+
+	case "Lab":
+		if len(a) < 2 {
+			return labSpace{rng: [4]float64{-100, 100, -100, 100}}, nil
+		}
+		return r.labSpace(a[1])
+
+	case "Indexed", "I":
+		return r.indexedSpace(a, res)
+
+	case "Separation", "DeviceN":
+		return r.tintSpace(a, res)
+
+	case "Pattern":
+		cs := patternSpace{}
+		if len(a) > 1 {
+			base, err := r.colorSpace(a[1], res)
+			if err != nil {
+				return nil, err
+			}
+			cs.base = base
+		}
+		return cs, nil
+	}
+
+	if cs := namedColorSpace(n.Value()); cs != nil {
+		// e.g. [/CalRGB <<...>>]
+		return cs, nil
+	}
+
+	return nil, errors.Errorf("pdfcpu: unsupported color space: %s", n.Value())
+}
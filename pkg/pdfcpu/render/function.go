@@ -0,0 +1,820 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// function is a PDF function, see 7.10.
+type function interface {
+	eval(in []float64) []float64
+}
+
+type functionBase struct {
+	domain []float64
+	rng    []float64 // optional for type 2 and 3 functions
+}
+
+func clamp(f, lo, hi float64) float64 {
+	if f < lo {
+		return lo
+	}
+	if f > hi {
+		return hi
+	}
+	return f
+}
+
+func interpolate(x, xmin, xmax, ymin, ymax float64) float64 {
+	if xmax == xmin {
+		return ymin
+	}
+	return ymin + (x-xmin)*(ymax-ymin)/(xmax-xmin)
+}
+
+func (fb functionBase) clipInput(in []float64) []float64 {
+	res := make([]float64, len(in))
+	for i, f := range in {
+		if 2*i+1 < len(fb.domain) {
+			f = clamp(f, fb.domain[2*i], fb.domain[2*i+1])
+		}
+		res[i] = f
+	}
+	return res
+}
+
+func (fb functionBase) clipOutput(out []float64) []float64 {
+	for i := range out {
+		if 2*i+1 < len(fb.rng) {
+			out[i] = clamp(out[i], fb.rng[2*i], fb.rng[2*i+1])
+		}
+	}
+	return out
+}
+
+// sampledFunction is a type 0 function, see 7.10.2.
+type sampledFunction struct {
+	functionBase
+	size    []int
+	bps     int
+	encode  []float64
+	decode  []float64
+	samples []float64 // normalized to [0,1]
+	nOut    int
+}
+
+func (f *sampledFunction) eval(in []float64) []float64 {
+	in = f.clipInput(in)
+	m := len(f.size)
+	if len(in) < m {
+		return make([]float64, f.nOut)
+	}
+
+	// Encoded input positions and interpolation weights.
+	idx := make([]int, m)
+	frac := make([]float64, m)
+	for i := 0; i < m; i++ {
+		e := interpolate(in[i], f.domain[2*i], f.domain[2*i+1], f.encode[2*i], f.encode[2*i+1])
+		e = clamp(e, 0, float64(f.size[i]-1))
+		k := int(math.Floor(e))
+		if k >= f.size[i]-1 {
+			k = max(f.size[i]-2, 0)
+		}
+		idx[i], frac[i] = k, e-float64(k)
+	}
+
+	out := make([]float64, f.nOut)
+
+	// Multilinear interpolation over the 2^m surrounding samples.
+	for corner := 0; corner < 1<<m; corner++ {
+		w := 1.
+		off, stride := 0, 1
+		for i := 0; i < m; i++ {
+			k := idx[i]
+			if corner&(1<<i) != 0 {
+				w *= frac[i]
+				if k+1 < f.size[i] {
+					k++
+				}
+			} else {
+				w *= 1 - frac[i]
+			}
+			off += k * stride
+			stride *= f.size[i]
+		}
+		if w == 0 {
+			continue
+		}
+		for j := 0; j < f.nOut; j++ {
+			if k := off*f.nOut + j; k < len(f.samples) {
+				out[j] += w * f.samples[k]
+			}
+		}
+	}
+
+	for j := range out {
+		out[j] = interpolate(out[j], 0, 1, f.decode[2*j], f.decode[2*j+1])
+	}
+
+	return f.clipOutput(out)
+}
+
+// exponentialFunction is a type 2 function, see 7.10.3.
+type exponentialFunction struct {
+	functionBase
+	c0, c1 []float64
+	n      float64
+}
+
+func (f *exponentialFunction) eval(in []float64) []float64 {
+	in = f.clipInput(in)
+	x := 0.
+	if len(in) > 0 {
+		x = in[0]
+	}
+	xn := math.Pow(x, f.n)
+	out := make([]float64, len(f.c0))
+	for i := range out {
+		out[i] = f.c0[i] + xn*(f.c1[i]-f.c0[i])
+	}
+	return f.clipOutput(out)
+}
+
+// stitchingFunction is a type 3 function, see 7.10.4.
+type stitchingFunction struct {
+	functionBase
+	functions []function
+	bounds    []float64
+	encode    []float64
+}
+
+func (f *stitchingFunction) eval(in []float64) []float64 {
+	in = f.clipInput(in)
+	x := 0.
+	if len(in) > 0 {
+		x = in[0]
+	}
+	k := 0
+	for k < len(f.bounds) && x >= f.bounds[k] {
+		k++
+	}
+	lo, hi := f.domain[0], f.domain[1]
+	if k > 0 {
+		lo = f.bounds[k-1]
+	}
+	if k < len(f.bounds) {
+		hi = f.bounds[k]
+	}
+	x = interpolate(x, lo, hi, f.encode[2*k], f.encode[2*k+1])
+	return f.clipOutput(f.functions[k].eval([]float64{x}))
+}
+
+// functionArray combines n functions with a single output value each.
+type functionArray []function
+
+func (fa functionArray) eval(in []float64) []float64 {
+	out := make([]float64, 0, len(fa))
+	for _, f := range fa {
+		if v := f.eval(in); len(v) > 0 {
+			out = append(out, v[0])
+		} else {
+			out = append(out, 0)
+		}
+	}
+	return out
+}
+
+func numberArray(xRefTable *model.XRefTable, o types.Object) ([]float64, error) {
+	a, err := xRefTable.DereferenceArray(o)
+	if err != nil || a == nil {
+		return nil, err
+	}
+	ff := make([]float64, len(a))
+	for i, o := range a {
+		f, err := xRefTable.DereferenceNumber(o)
+		if err != nil {
+			return nil, err
+		}
+		ff[i] = f
+	}
+	return ff, nil
+}
+
+func parseSampledFunction(xRefTable *model.XRefTable, sd *types.StreamDict, fb functionBase) (function, error) {
+	f := &sampledFunction{functionBase: fb}
+
+	size, err := numberArray(xRefTable, sd.Dict["Size"])
+	if err != nil {
+		return nil, err
+	}
+	if len(size) == 0 || 2*len(size) > len(fb.domain) || len(fb.rng) < 2 {
+		return nil, errors.New("pdfcpu: corrupt sampled function")
+	}
+
+	f.nOut = len(fb.rng) / 2
+	n := f.nOut
+	for _, s := range size {
+		if s < 1 || s > 1<<16 {
+			return nil, errors.New("pdfcpu: corrupt sampled function")
+		}
+		f.size = append(f.size, int(s))
+		n *= int(s)
+		if n > 1<<24 {
+			return nil, errors.New("pdfcpu: sampled function too large")
+		}
+	}
+
+	bps, err := xRefTable.DereferenceNumber(sd.Dict["BitsPerSample"])
+	if err != nil {
+		return nil, err
+	}
+	f.bps = int(bps)
+	switch f.bps {
+	case 1, 2, 4, 8, 12, 16, 24, 32:
+	default:
+		return nil, errors.Errorf("pdfcpu: sampled function: invalid BitsPerSample %d", f.bps)
+	}
+
+	if f.encode, err = numberArray(xRefTable, sd.Dict["Encode"]); err != nil {
+		return nil, err
+	}
+	if len(f.encode) < 2*len(f.size) {
+		f.encode = make([]float64, 2*len(f.size))
+		for i, s := range f.size {
+			f.encode[2*i+1] = float64(s - 1)
+		}
+	}
+
+	if f.decode, err = numberArray(xRefTable, sd.Dict["Decode"]); err != nil {
+		return nil, err
+	}
+	if len(f.decode) < 2*f.nOut {
+		f.decode = fb.rng
+	}
+
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+
+	f.samples = make([]float64, n)
+	maxVal := math.Pow(2, float64(f.bps)) - 1
+	bb := sd.Content
+	var pos uint
+	for i := range f.samples {
+		var v uint64
+		for b := 0; b < f.bps; b++ {
+			byteIdx := pos / 8
+			if int(byteIdx) >= len(bb) {
+				break
+			}
+			bit := (bb[byteIdx] >> (7 - pos%8)) & 1
+			v = v<<1 | uint64(bit)
+			pos++
+		}
+		f.samples[i] = float64(v) / maxVal
+	}
+
+	return f, nil
+}
+
+func parseExponentialFunction(xRefTable *model.XRefTable, d types.Dict, fb functionBase) (function, error) {
+	f := &exponentialFunction{functionBase: fb, c0: []float64{0}, c1: []float64{1}}
+
+	var err error
+	if o, found := d.Find("C0"); found {
+		if f.c0, err = numberArray(xRefTable, o); err != nil {
+			return nil, err
+		}
+	}
+	if o, found := d.Find("C1"); found {
+		if f.c1, err = numberArray(xRefTable, o); err != nil {
+			return nil, err
+		}
+	}
+	if len(f.c0) != len(f.c1) {
+		return nil, errors.New("pdfcpu: corrupt exponential function")
+	}
+	if f.n, err = xRefTable.DereferenceNumber(d["N"]); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (r *renderer) parseStitchingFunction(d types.Dict, fb functionBase) (function, error) {
+	f := &stitchingFunction{functionBase: fb}
+
+	a, err := r.ctx.DereferenceArray(d["Functions"])
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range a {
+		fn, err := r.function(o)
+		if err != nil {
+			return nil, err
+		}
+		f.functions = append(f.functions, fn)
+	}
+
+	if f.bounds, err = numberArray(r.ctx.XRefTable, d["Bounds"]); err != nil {
+		return nil, err
+	}
+	if f.encode, err = numberArray(r.ctx.XRefTable, d["Encode"]); err != nil {
+		return nil, err
+	}
+
+	if len(f.functions) == 0 || len(f.bounds) != len(f.functions)-1 || len(f.encode) < 2*len(f.functions) || len(fb.domain) < 2 {
+		return nil, errors.New("pdfcpu: corrupt stitching function")
+	}
+
+	return f, nil
+}
+
+// function parses the function o which may also be an array of functions.
+func (r *renderer) function(o types.Object) (function, error) {
+	var objNr int
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr = indRef.ObjectNumber.Value()
+		if f, ok := r.functions[objNr]; ok {
+			return f, nil
+		}
+	}
+
+	o, err := r.ctx.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		f  function
+		d  types.Dict
+		sd *types.StreamDict
+	)
+
+	switch o := o.(type) {
+
+	case types.Array:
+		var fa functionArray
+		for _, o := range o {
+			f, err := r.function(o)
+			if err != nil {
+				return nil, err
+			}
+			fa = append(fa, f)
+		}
+		return fa, nil
+
+	case types.Dict:
+		d = o
+
+	case types.StreamDict:
+		sd, d = &o, o.Dict
+
+	default:
+		return nil, errors.Errorf("pdfcpu: invalid function: %v", o)
+	}
+
+	fb := functionBase{}
+	if fb.domain, err = numberArray(r.ctx.XRefTable, d["Domain"]); err != nil {
+		return nil, err
+	}
+	if fb.rng, err = numberArray(r.ctx.XRefTable, d["Range"]); err != nil {
+		return nil, err
+	}
+
+	ft := d.IntEntry("FunctionType")
+	if ft == nil {
+		return nil, errors.New("pdfcpu: function: missing FunctionType")
+	}
+
+	switch *ft {
+	case 0:
+		if sd == nil {
+			return nil, errors.New("pdfcpu: sampled function: missing stream")
+		}
+		f, err = parseSampledFunction(r.ctx.XRefTable, sd, fb)
+	case 2:
+		f, err = parseExponentialFunction(r.ctx.XRefTable, d, fb)
+	case 3:
+		f, err = r.parseStitchingFunction(d, fb)
+	case 4:
+		if sd == nil {
+			return nil, errors.New("pdfcpu: PostScript calculator function: missing stream")
+		}
+		if err = sd.Decode(); err == nil {
+			f, err = parsePostScriptFunction(sd.Content, fb)
+		}
+	default:
+		err = errors.Errorf("pdfcpu: unsupported FunctionType %d", *ft)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if objNr > 0 {
+		r.functions[objNr] = f
+	}
+
+	return f, nil
+}
+
+// PostScript calculator functions, see 7.10.5.
+
+type psKind int
+
+const (
+	psInt psKind = iota
+	psReal
+	psBool
+)
+
+type psValue struct {
+	f    float64
+	kind psKind
+}
+
+type psInstr struct {
+	op       string
+	val      psValue
+	branches [][]psInstr // if: 1 branch, ifelse: 2 branches
+}
+
+type postScriptFunction struct {
+	functionBase
+	prog []psInstr
+}
+
+func psTokens(bb []byte) []string {
+	var tt []string
+	for i := 0; i < len(bb); {
+		c := bb[i]
+		switch {
+		case c == '{' || c == '}':
+			tt = append(tt, string(c))
+			i++
+		case c == '%':
+			for i < len(bb) && bb[i] != '\n' && bb[i] != '\r' {
+				i++
+			}
+		case c <= ' ':
+			i++
+		default:
+			j := i
+			for j < len(bb) && bb[j] > ' ' && bb[j] != '{' && bb[j] != '}' && bb[j] != '%' {
+				j++
+			}
+			tt = append(tt, string(bb[i:j]))
+			i = j
+		}
+	}
+	return tt
+}
+
+func parsePSBlock(tt []string, i int) ([]psInstr, int, error) {
+	var (
+		prog   []psInstr
+		blocks [][]psInstr
+	)
+	for i < len(tt) {
+		t := tt[i]
+		switch t {
+		case "{":
+			b, j, err := parsePSBlock(tt, i+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			blocks = append(blocks, b)
+			i = j
+			continue
+		case "}":
+			return prog, i + 1, nil
+		case "if":
+			if len(blocks) < 1 {
+				return nil, 0, errors.New("pdfcpu: PostScript function: corrupt if")
+			}
+			prog = append(prog, psInstr{op: t, branches: blocks[len(blocks)-1:]})
+			blocks = nil
+		case "ifelse":
+			if len(blocks) < 2 {
+				return nil, 0, errors.New("pdfcpu: PostScript function: corrupt ifelse")
+			}
+			prog = append(prog, psInstr{op: t, branches: blocks[len(blocks)-2:]})
+			blocks = nil
+		case "true", "false":
+			prog = append(prog, psInstr{val: psValue{kind: psBool, f: boolFloat(t == "true")}})
+		default:
+			if i64, err := strconv.ParseInt(t, 10, 64); err == nil {
+				prog = append(prog, psInstr{val: psValue{f: float64(i64), kind: psInt}})
+			} else if f, err := strconv.ParseFloat(t, 64); err == nil {
+				prog = append(prog, psInstr{val: psValue{f: f, kind: psReal}})
+			} else {
+				prog = append(prog, psInstr{op: t})
+			}
+		}
+		i++
+	}
+	return prog, i, nil
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func parsePostScriptFunction(bb []byte, fb functionBase) (function, error) {
+	tt := psTokens(bb)
+	if len(tt) == 0 || tt[0] != "{" {
+		return nil, errors.New("pdfcpu: PostScript function: missing {")
+	}
+	prog, _, err := parsePSBlock(tt, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(fb.domain) < 2 || len(fb.rng) < 2 {
+		return nil, errors.New("pdfcpu: PostScript function: missing Domain or Range")
+	}
+	return &postScriptFunction{functionBase: fb, prog: prog}, nil
+}
+
+type psStack []psValue
+
+func (s *psStack) push(v psValue) {
+	if len(*s) < 1000 {
+		*s = append(*s, v)
+	}
+}
+
+func (s *psStack) pushReal(f float64) {
+	s.push(psValue{f: f, kind: psReal})
+}
+
+func (s *psStack) pushInt(i int64) {
+	s.push(psValue{f: float64(i), kind: psInt})
+}
+
+func (s *psStack) pushBool(b bool) {
+	s.push(psValue{f: boolFloat(b), kind: psBool})
+}
+
+func (s *psStack) pop() psValue {
+	n := len(*s)
+	if n == 0 {
+		return psValue{}
+	}
+	v := (*s)[n-1]
+	*s = (*s)[:n-1]
+	return v
+}
+
+func (s *psStack) pop2() (psValue, psValue) {
+	b := s.pop()
+	a := s.pop()
+	return a, b
+}
+
+func psNumeric(a, b psValue, f float64, s *psStack) {
+	if a.kind == psInt && b.kind == psInt && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		s.pushInt(int64(f))
+		return
+	}
+	s.pushReal(f)
+}
+
+func (s *psStack) arithmetic(op string) bool {
+	switch op {
+	case "add":
+		a, b := s.pop2()
+		psNumeric(a, b, a.f+b.f, s)
+	case "sub":
+		a, b := s.pop2()
+		psNumeric(a, b, a.f-b.f, s)
+	case "mul":
+		a, b := s.pop2()
+		psNumeric(a, b, a.f*b.f, s)
+	case "div":
+		a, b := s.pop2()
+		if b.f == 0 {
+			s.pushReal(0)
+			return true
+		}
+		s.pushReal(a.f / b.f)
+	case "idiv":
+		a, b := s.pop2()
+		if int64(b.f) == 0 {
+			s.pushInt(0)
+			return true
+		}
+		s.pushInt(int64(a.f) / int64(b.f))
+	case "mod":
+		a, b := s.pop2()
+		if int64(b.f) == 0 {
+			s.pushInt(0)
+			return true
+		}
+		s.pushInt(int64(a.f) % int64(b.f))
+	case "neg":
+		a := s.pop()
+		s.push(psValue{f: -a.f, kind: a.kind})
+	case "abs":
+		a := s.pop()
+		s.push(psValue{f: math.Abs(a.f), kind: a.kind})
+	case "ceiling":
+		a := s.pop()
+		s.push(psValue{f: math.Ceil(a.f), kind: a.kind})
+	case "floor":
+		a := s.pop()
+		s.push(psValue{f: math.Floor(a.f), kind: a.kind})
+	case "round":
+		a := s.pop()
+		s.push(psValue{f: math.Floor(a.f + 0.5), kind: a.kind})
+	case "truncate":
+		a := s.pop()
+		s.push(psValue{f: math.Trunc(a.f), kind: a.kind})
+	case "cvi":
+		s.pushInt(int64(s.pop().f))
+	case "cvr":
+		s.pushReal(s.pop().f)
+	case "sqrt":
+		s.pushReal(math.Sqrt(math.Max(0, s.pop().f)))
+	case "sin":
+		s.pushReal(math.Sin(s.pop().f * math.Pi / 180))
+	case "cos":
+		s.pushReal(math.Cos(s.pop().f * math.Pi / 180))
+	case "atan":
+		a, b := s.pop2()
+		deg := math.Atan2(a.f, b.f) * 180 / math.Pi
+		if deg < 0 {
+			deg += 360
+		}
+		s.pushReal(deg)
+	case "exp":
+		a, b := s.pop2()
+		s.pushReal(math.Pow(a.f, b.f))
+	case "ln":
+		s.pushReal(math.Log(s.pop().f))
+	case "log":
+		s.pushReal(math.Log10(s.pop().f))
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *psStack) relational(op string) bool {
+	switch op {
+	case "eq":
+		a, b := s.pop2()
+		s.pushBool(a.f == b.f)
+	case "ne":
+		a, b := s.pop2()
+		s.pushBool(a.f != b.f)
+	case "gt":
+		a, b := s.pop2()
+		s.pushBool(a.f > b.f)
+	case "ge":
+		a, b := s.pop2()
+		s.pushBool(a.f >= b.f)
+	case "lt":
+		a, b := s.pop2()
+		s.pushBool(a.f < b.f)
+	case "le":
+		a, b := s.pop2()
+		s.pushBool(a.f <= b.f)
+	case "and", "or", "xor":
+		a, b := s.pop2()
+		x, y := int64(a.f), int64(b.f)
+		var v int64
+		switch op {
+		case "and":
+			v = x & y
+		case "or":
+			v = x | y
+		default:
+			v = x ^ y
+		}
+		s.push(psValue{f: float64(v), kind: a.kind})
+	case "not":
+		a := s.pop()
+		if a.kind == psBool {
+			s.pushBool(a.f == 0)
+			return true
+		}
+		s.pushInt(^int64(a.f))
+	case "bitshift":
+		a, b := s.pop2()
+		x, n := int64(a.f), int64(b.f)
+		if n >= 0 {
+			s.pushInt(x << uint(n))
+		} else {
+			s.pushInt(x >> uint(-n))
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (s *psStack) stackOp(op string) bool {
+	switch op {
+	case "pop":
+		s.pop()
+	case "dup":
+		if n := len(*s); n > 0 {
+			s.push((*s)[n-1])
+		}
+	case "exch":
+		a, b := s.pop2()
+		s.push(b)
+		s.push(a)
+	case "copy":
+		n := int(s.pop().f)
+		l := len(*s)
+		if n > 0 && n <= l {
+			for _, v := range (*s)[l-n:] {
+				s.push(v)
+			}
+		}
+	case "index":
+		n := int(s.pop().f)
+		l := len(*s)
+		if n >= 0 && n < l {
+			s.push((*s)[l-1-n])
+		}
+	case "roll":
+		j := int(s.pop().f)
+		n := int(s.pop().f)
+		l := len(*s)
+		if n <= 0 || n > l {
+			return true
+		}
+		vv := append([]psValue{}, (*s)[l-n:]...)
+		j = ((j % n) + n) % n
+		for i := range vv {
+			(*s)[l-n+(i+j)%n] = vv[i]
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (f *postScriptFunction) exec(prog []psInstr, s *psStack) {
+	for _, in := range prog {
+		switch in.op {
+		case "":
+			s.push(in.val)
+		case "if":
+			if s.pop().f != 0 {
+				f.exec(in.branches[0], s)
+			}
+		case "ifelse":
+			if s.pop().f != 0 {
+				f.exec(in.branches[0], s)
+			} else {
+				f.exec(in.branches[1], s)
+			}
+		default:
+			if !s.arithmetic(in.op) && !s.relational(in.op) {
+				s.stackOp(in.op)
+			}
+		}
+	}
+}
+
+func (f *postScriptFunction) eval(in []float64) []float64 {
+	in = f.clipInput(in)
+	s := make(psStack, 0, 32)
+	for _, v := range in {
+		s.pushReal(v)
+	}
+	f.exec(f.prog, &s)
+	n := len(f.rng) / 2
+	out := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = s.pop().f
+	}
+	return f.clipOutput(out)
+}
@@ -0,0 +1,670 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	// Register image decoders used by pdfcpu.RenderImage.
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "github.com/hhrutter/tiff"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+)
+
+// maxImagePixels limits the size of images to be decoded.
+const maxImagePixels = 1 << 26
+
+// Abbreviations used in inline image dicts, see 8.9.7.
+var (
+	inlineImageKeys = map[string]string{
+		"BPC": "BitsPerComponent",
+		"CS":  "ColorSpace",
+		"D":   "Decode",
+		"DP":  "DecodeParms",
+		"F":   "Filter",
+		"H":   "Height",
+		"IM":  "ImageMask",
+		"I":   "Interpolate",
+		"W":   "Width",
+		"L":   "Length",
+	}
+	inlineImageColorSpaces = map[string]string{
+		"G":    model.DeviceGrayCS,
+		"RGB":  model.DeviceRGBCS,
+		"CMYK": model.DeviceCMYKCS,
+		"I":    model.IndexedCS,
+	}
+	inlineImageFilters = map[string]string{
+		"AHx": filter.ASCIIHex,
+		"A85": filter.ASCII85,
+		"LZW": filter.LZW,
+		"Fl":  filter.Flate,
+		"RL":  filter.RunLength,
+		"CCF": filter.CCITTFax,
+		"DCT": filter.DCT,
+	}
+)
+
+// decodedImage is an image XObject or inline image ready for painting.
+type decodedImage struct {
+	img         *image.NRGBA // nil for stencil masks
+	stencil     *image.Alpha // image masks only
+	interpolate bool
+}
+
+func lastFilter(sd *types.StreamDict) string {
+	if n := len(sd.FilterPipeline); n > 0 {
+		return sd.FilterPipeline[n-1].Name
+	}
+	return ""
+}
+
+// decodeSamples decodes all filters of sd except DCTDecode and JPXDecode.
+func (r *renderer) decodeSamples(sd *types.StreamDict) error {
+	if len(sd.FilterPipeline) == 0 {
+		raw, err := sd.RawContent()
+		if err != nil {
+			return err
+		}
+		sd.Content = raw
+		return nil
+	}
+
+	if f := sd.FilterPipeline[0].Name; f == filter.DCT || f == filter.JPX {
+		// Nothing to decode, see renderedImage.
+		return nil
+	}
+
+	var opts []filter.Option
+
+	switch lastFilter(sd) {
+	case filter.JBIG2:
+		var err error
+		if opts, err = r.ctx.FilterOptions(sd); err != nil {
+			return err
+		}
+	case filter.DCT:
+		if n, err := pdfcpu.ColorSpaceComponents(r.ctx.XRefTable, sd); err == nil {
+			sd.CSComponents = n
+		}
+	}
+
+	return sd.Decode(opts...)
+}
+
+func (r *renderer) imageDims(d types.Dict) (int, int, error) {
+	w, err := r.ctx.DereferenceNumber(d["Width"])
+	if err != nil {
+		return 0, 0, err
+	}
+	h, err := r.ctx.DereferenceNumber(d["Height"])
+	if err != nil {
+		return 0, 0, err
+	}
+	if w < 1 || h < 1 || w*h > maxImagePixels {
+		return 0, 0, errors.Errorf("pdfcpu: invalid image size %.0fx%.0f", w, h)
+	}
+	return int(w), int(h), nil
+}
+
+// sampleReader reads samples of bpc bits from rows starting at byte boundaries.
+type sampleReader struct {
+	bb  []byte
+	bpc int
+	pos int // in bits
+}
+
+func (sr *sampleReader) next() int {
+	if sr.bpc == 8 {
+		i := sr.pos / 8
+		sr.pos += 8
+		if i < len(sr.bb) {
+			return int(sr.bb[i])
+		}
+		return 0
+	}
+	var v int
+	for b := 0; b < sr.bpc; b++ {
+		i := sr.pos / 8
+		if i >= len(sr.bb) {
+			v <<= 1
+			sr.pos++
+			continue
+		}
+		v = v<<1 | int(sr.bb[i]>>(7-sr.pos%8))&1
+		sr.pos++
+	}
+	return v
+}
+
+func (sr *sampleReader) alignRow() {
+	sr.pos = (sr.pos + 7) / 8 * 8
+}
+
+// stencilMask returns the painted area of a 1 bit image mask, see 8.9.6.2.
+func stencilMask(bb []byte, w, h int, invert bool) *image.Alpha {
+	m := image.NewAlpha(image.Rect(0, 0, w, h))
+	sr := &sampleReader{bb: bb, bpc: 1}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (sr.next() == 0) != invert {
+				m.Pix[y*m.Stride+x] = 255
+			}
+		}
+		sr.alignRow()
+	}
+	return m
+}
+
+// sampledImage converts image samples into an NRGBA image using color space cs.
+func sampledImage(bb []byte, w, h, bpc int, cs colorSpace, decode []float64, colorKey []int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	n := cs.ncomps()
+	maxVal := float64(int(1)<<bpc - 1)
+
+	var lut []color.NRGBA
+	if n == 1 && bpc <= 8 {
+		lut = make([]color.NRGBA, 1<<bpc)
+		for v := range lut {
+			lut[v] = cs.rgb([]float64{interpolate(float64(v), 0, maxVal, decode[0], decode[1])})
+		}
+	}
+
+	sr := &sampleReader{bb: bb, bpc: bpc}
+	raw := make([]int, n)
+	comps := make([]float64, n)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			for i := 0; i < n; i++ {
+				raw[i] = sr.next()
+			}
+
+			var c color.NRGBA
+			if lut != nil {
+				c = lut[raw[0]]
+			} else {
+				for i := 0; i < n; i++ {
+					comps[i] = interpolate(float64(raw[i]), 0, maxVal, decode[2*i], decode[2*i+1])
+				}
+				c = cs.rgb(comps)
+			}
+
+			if colorKey != nil {
+				masked := true
+				for i := 0; i < n && 2*i+1 < len(colorKey); i++ {
+					if raw[i] < colorKey[2*i] || raw[i] > colorKey[2*i+1] {
+						masked = false
+						break
+					}
+				}
+				if masked {
+					c.A = 0
+				}
+			}
+
+			img.SetNRGBA(x, y, c)
+		}
+		sr.alignRow()
+	}
+
+	return img
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+		return nrgba
+	}
+	b := img.Bounds()
+	res := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(res, res.Rect, img, b.Min, draw.Src)
+	return res
+}
+
+// alphaChannel decodes a soft mask or a stencil mask and scales it to w x h pixels.
+func (r *renderer) alphaChannel(o types.Object, w, h int, stencil bool) *image.Alpha {
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil
+	}
+	sd1 := sd.Clone().(types.StreamDict)
+	if err := r.decodeSamples(&sd1); err != nil {
+		return nil
+	}
+	mw, mh, err := r.imageDims(sd1.Dict)
+	if err != nil {
+		return nil
+	}
+
+	var m *image.Alpha
+
+	if stencil {
+		invert := false
+		if ff, err := numberArray(r.ctx.XRefTable, sd1.Dict["Decode"]); err == nil && len(ff) == 2 && ff[0] == 1 {
+			invert = true
+		}
+		m = stencilMask(sd1.Content, mw, mh, invert)
+	} else {
+		var img image.Image
+		if f := lastFilter(&sd1); f == filter.DCT || f == filter.JPX {
+			rd, _, err := pdfcpu.RenderImage(r.ctx.XRefTable, &sd1, false, "", 0)
+			if err != nil || rd == nil {
+				return nil
+			}
+			if img, _, err = image.Decode(rd); err != nil {
+				return nil
+			}
+		} else {
+			bpc := 8
+			if i := sd1.IntEntry("BitsPerComponent"); i != nil {
+				bpc = *i
+			}
+			decode, err := numberArray(r.ctx.XRefTable, sd1.Dict["Decode"])
+			if err != nil || len(decode) < 2 {
+				decode = []float64{0, 1}
+			}
+			img = sampledImage(sd1.Content, mw, mh, bpc, deviceGray{}, decode, nil)
+		}
+		b := img.Bounds()
+		m = image.NewAlpha(image.Rect(0, 0, b.Dx(), b.Dy()))
+		for y := 0; y < b.Dy(); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				g := color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray)
+				m.Pix[y*m.Stride+x] = g.Y
+			}
+		}
+	}
+
+	if m.Rect.Dx() == w && m.Rect.Dy() == h {
+		return m
+	}
+
+	scaled := image.NewAlpha(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(scaled, scaled.Rect, m, m.Rect, draw.Src, nil)
+	return scaled
+}
+
+func applyAlpha(img *image.NRGBA, m *image.Alpha) {
+	if m == nil {
+		return
+	}
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			i := y*img.Stride + 4*x + 3
+			img.Pix[i] = uint8(int(img.Pix[i]) * int(m.Pix[y*m.Stride+x]) / 255)
+		}
+	}
+}
+
+func (r *renderer) colorKey(o types.Object) []int {
+	a, err := r.ctx.DereferenceArray(o)
+	if err != nil {
+		return nil
+	}
+	ff, err := numberArray(r.ctx.XRefTable, a)
+	if err != nil {
+		return nil
+	}
+	key := make([]int, len(ff))
+	for i, f := range ff {
+		key[i] = int(f)
+	}
+	return key
+}
+
+// renderedImage decodes an image using the image renderers of package pdfcpu.
+func (r *renderer) renderedImage(sd *types.StreamDict, objNr int) (*image.NRGBA, error) {
+	rd, _, err := pdfcpu.RenderImage(r.ctx.XRefTable, sd, false, "", objNr)
+	if err != nil || rd == nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(rd)
+	if err != nil {
+		return nil, err
+	}
+	return toNRGBA(img), nil
+}
+
+func (r *renderer) sampledImageForStream(sd *types.StreamDict, w, h int, res types.Dict, colorKey []int) (*image.NRGBA, error) {
+	if f := lastFilter(sd); f == filter.DCT || f == filter.JPX {
+		return nil, errors.Errorf("pdfcpu: unsupported image filter %s", f)
+	}
+
+	cs, err := r.colorSpace(sd.Dict["ColorSpace"], res)
+	if err != nil {
+		return nil, err
+	}
+
+	bpc := 8
+	if i := sd.IntEntry("BitsPerComponent"); i != nil {
+		bpc = *i
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return nil, errors.Errorf("pdfcpu: invalid BitsPerComponent %d", bpc)
+	}
+
+	decode, err := numberArray(r.ctx.XRefTable, sd.Dict["Decode"])
+	if err != nil || len(decode) < 2*cs.ncomps() {
+		decode = cs.defaultDecode(bpc)
+	}
+
+	return sampledImage(sd.Content, w, h, bpc, cs, decode, colorKey), nil
+}
+
+// decodeImage decodes an image XObject or inline image, see 8.9.
+func (r *renderer) decodeImage(sd *types.StreamDict, objNr int, res types.Dict) (*decodedImage, error) {
+	sd1 := sd.Clone().(types.StreamDict)
+	if err := r.decodeSamples(&sd1); err != nil {
+		return nil, err
+	}
+
+	w, h, err := r.imageDims(sd1.Dict)
+	if err != nil {
+		return nil, err
+	}
+
+	di := &decodedImage{}
+	if b := sd1.BooleanEntry("Interpolate"); b != nil {
+		di.interpolate = *b
+	}
+
+	if b := sd1.BooleanEntry("ImageMask"); b != nil && *b {
+		invert := false
+		if ff, err := numberArray(r.ctx.XRefTable, sd1.Dict["Decode"]); err == nil && len(ff) == 2 && ff[0] == 1 {
+			invert = true
+		}
+		di.stencil = stencilMask(sd1.Content, w, h, invert)
+		return di, nil
+	}
+
+	var colorKey []int
+	mask, hasMask := sd1.Find("Mask")
+	if hasMask {
+		colorKey = r.colorKey(mask)
+	}
+
+	if colorKey == nil {
+		if di.img, err = r.renderedImage(&sd1, objNr); err != nil && log.DebugEnabled() {
+			log.Debug.Printf("render: image obj#%d: %v\n", objNr, err)
+		}
+	}
+
+	if di.img == nil {
+		if di.img, err = r.sampledImageForStream(&sd1, w, h, res, colorKey); err != nil {
+			return nil, err
+		}
+	}
+
+	if di.img.Rect.Dx() != w || di.img.Rect.Dy() != h {
+		// Use the decoded size e.g. for JPEG images with inaccurate dimensions.
+		w, h = di.img.Rect.Dx(), di.img.Rect.Dy()
+	}
+
+	if di.img.Opaque() {
+		if o, found := sd1.Find("SMask"); found {
+			applyAlpha(di.img, r.alphaChannel(o, w, h, false))
+		} else if hasMask && colorKey == nil {
+			applyAlpha(di.img, r.alphaChannel(mask, w, h, true))
+		}
+	}
+
+	return di, nil
+}
+
+// inlineImageStreamDict expands the abbreviations of an inline image dict and
+// returns an equivalent image stream, see 8.9.7.
+func (r *renderer) inlineImageStreamDict(im *model.InlineImage) (*types.StreamDict, error) {
+	d := types.Dict{}
+	for k, v := range im.Dict {
+		if k1, ok := inlineImageKeys[k]; ok {
+			k = k1
+		}
+		d[k] = v
+	}
+
+	expand := func(o types.Object, m map[string]string) types.Object {
+		switch o := o.(type) {
+		case types.Name:
+			if n, ok := m[o.Value()]; ok {
+				return types.Name(n)
+			}
+		case types.Array:
+			a := make(types.Array, len(o))
+			for i, o1 := range o {
+				a[i] = o1
+				if n, ok := o1.(types.Name); ok {
+					if n1, ok := m[n.Value()]; ok {
+						a[i] = types.Name(n1)
+					}
+				}
+			}
+			return a
+		}
+		return o
+	}
+
+	if o, found := d["ColorSpace"]; found {
+		o = expand(o, inlineImageColorSpaces)
+		if n, ok := o.(types.Name); ok && namedColorSpace(n.Value()) == nil {
+			// A named color space resource.
+			if csd, err := r.ctx.DereferenceDict(r.gs.resources["ColorSpace"]); err == nil && csd != nil {
+				if o1, found := csd.Find(n.Value()); found {
+					if o1, err = r.ctx.Dereference(o1); err == nil {
+						o = o1
+					}
+				}
+			}
+		}
+		d["ColorSpace"] = o
+	}
+
+	var pipeline []types.PDFFilter
+
+	if o, found := d["Filter"]; found {
+		o = expand(o, inlineImageFilters)
+		d["Filter"] = o
+
+		var filters types.Array
+		switch o := o.(type) {
+		case types.Name:
+			filters = types.Array{o}
+		case types.Array:
+			filters = o
+		}
+
+		var parms types.Array
+		switch o := d["DecodeParms"].(type) {
+		case types.Dict:
+			parms = types.Array{o}
+		case types.Array:
+			parms = o
+		}
+
+		for i, f := range filters {
+			n, ok := f.(types.Name)
+			if !ok {
+				return nil, errors.New("pdfcpu: corrupt inline image filter")
+			}
+			pf := types.PDFFilter{Name: n.Value()}
+			if i < len(parms) {
+				if d, ok := parms[i].(types.Dict); ok {
+					pf.DecodeParms = d
+				}
+			}
+			pipeline = append(pipeline, pf)
+		}
+	}
+
+	sd := types.NewStreamDict(d, 0, nil, nil, pipeline)
+	sd.Raw = im.Data
+
+	return &sd, nil
+}
+
+// imageTransform returns the transformation from image pixels into device space.
+func imageTransform(ctm [3][3]float64, w, h int) f64.Aff3 {
+	a, b, c, d, e, f := ctm[0][0], ctm[0][1], ctm[1][0], ctm[1][1], ctm[2][0], ctm[2][1]
+	fw, fh := float64(w), float64(h)
+	return f64.Aff3{a / fw, -c / fh, c + e, b / fw, -d / fh, d + f}
+}
+
+// imageBounds returns the device space bounds of the unit square painted by images.
+func (r *renderer) imageBounds() image.Rectangle {
+	m := r.gs.ctm
+	pp := []point{transform(m, 0, 0), transform(m, 1, 0), transform(m, 1, 1), transform(m, 0, 1)}
+	_, rect := polygonEdges([][]point{pp})
+	rect = rect.Intersect(r.dst.Rect)
+	if r.gs.clip != nil {
+		rect = rect.Intersect(r.gs.clip.Rect)
+	}
+	return rect
+}
+
+func (r *renderer) interpolator(s2d f64.Aff3, interpolate bool) draw.Transformer {
+	scale := math.Sqrt(math.Abs(s2d[0]*s2d[4] - s2d[1]*s2d[3]))
+	if scale > 4 && !interpolate {
+		return draw.NearestNeighbor
+	}
+	return draw.BiLinear
+}
+
+// compositeImage paints the premultiplied image src through the clip mask onto dst.
+func compositeImage(dst, src *image.RGBA, clip *image.Alpha, alpha float64) {
+	r := src.Rect.Intersect(dst.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			i := src.PixOffset(x, y)
+			s := src.Pix[i : i+4]
+			if s[3] == 0 {
+				continue
+			}
+			a := alpha
+			if clip != nil {
+				if !(image.Point{x, y}).In(clip.Rect) {
+					continue
+				}
+				a *= float64(clip.Pix[clip.PixOffset(x, y)]) / 255
+			}
+			if a <= 0 {
+				continue
+			}
+			j := dst.PixOffset(x, y)
+			p := dst.Pix[j : j+4]
+			sa := float64(s[3]) / 255 * a
+			if sa >= 1 {
+				copy(p, s)
+				continue
+			}
+			b := 1 - sa
+			p[0] = uint8(math.Min(255, float64(s[0])*a+float64(p[0])*b+0.5))
+			p[1] = uint8(math.Min(255, float64(s[1])*a+float64(p[1])*b+0.5))
+			p[2] = uint8(math.Min(255, float64(s[2])*a+float64(p[2])*b+0.5))
+			p[3] = uint8(math.Min(255, 255*sa+float64(p[3])*b+0.5))
+		}
+	}
+}
+
+// paintImage paints di into the unit square of user space, see 8.9.4.
+func (r *renderer) paintImage(di *decodedImage) {
+	if r.hidden() {
+		return
+	}
+
+	rect := r.imageBounds()
+	if rect.Empty() {
+		return
+	}
+
+	var src image.Image = di.img
+	if di.stencil != nil {
+		src = di.stencil
+	}
+	b := src.Bounds()
+
+	s2d := imageTransform(r.gs.ctm, b.Dx(), b.Dy())
+	if s2d[0]*s2d[4]-s2d[1]*s2d[3] == 0 {
+		return
+	}
+	interp := r.interpolator(s2d, di.interpolate)
+
+	if di.stencil != nil {
+		m := image.NewAlpha(rect)
+		interp.Transform(m, s2d, di.stencil, b, draw.Src, nil)
+		r.paintMask(m, r.gs.fillPaint, r.gs.fillAlpha)
+		return
+	}
+
+	if r.forcedColor != nil {
+		// Images are not allowed in uncolored patterns and glyphs of Type 3 fonts using d1.
+		return
+	}
+
+	tmp := image.NewRGBA(rect)
+	interp.Transform(tmp, s2d, di.img, b, draw.Src, nil)
+	compositeImage(r.dst, tmp, r.gs.clip, r.gs.fillAlpha)
+}
+
+func (r *renderer) doImage(sd *types.StreamDict, objNr int) error {
+	di, ok := r.images[objNr]
+	if !ok {
+		var err error
+		if di, err = r.decodeImage(sd, objNr, r.gs.resources); err != nil {
+			if log.DebugEnabled() {
+				log.Debug.Printf("render: image obj#%d: %v\n", objNr, err)
+			}
+			di = nil
+		}
+		r.images[objNr] = di
+	}
+	if di != nil {
+		r.paintImage(di)
+	}
+	return nil
+}
+
+func (r *renderer) doInlineImage(im *model.InlineImage) error {
+	if im == nil || r.hidden() {
+		return nil
+	}
+	sd, err := r.inlineImageStreamDict(im)
+	if err != nil {
+		return err
+	}
+	di, err := r.decodeImage(sd, 0, r.gs.resources)
+	if err != nil {
+		if log.DebugEnabled() {
+			log.Debug.Printf("render: inline image: %v\n", err)
+		}
+		return nil
+	}
+	r.paintImage(di)
+	return nil
+}
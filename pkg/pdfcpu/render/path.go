@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+)
+
+// flatness is the maximum deviation in device pixels of a flattened curve from the exact curve.
+const flatness = 0.2
+
+func pdfMatrix(a, b, c, d, e, f float64) matrix.Matrix {
+	return matrix.Matrix{{a, b, 0}, {c, d, 0}, {e, f, 1}}
+}
+
+func transform(m matrix.Matrix, x, y float64) point {
+	return point{x*m[0][0] + y*m[1][0] + m[2][0], x*m[0][1] + y*m[1][1] + m[2][1]}
+}
+
+func invert(m matrix.Matrix) (matrix.Matrix, bool) {
+	a, b, c, d, e, f := m[0][0], m[0][1], m[1][0], m[1][1], m[2][0], m[2][1]
+	det := a*d - b*c
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return matrix.Matrix{}, false
+	}
+	return pdfMatrix(d/det, -b/det, -c/det, a/det, (c*f-d*e)/det, (b*e-a*f)/det), true
+}
+
+// scaleFactor returns the mean scaling factor of m.
+func scaleFactor(m matrix.Matrix) float64 {
+	return math.Sqrt(math.Abs(m[0][0]*m[1][1] - m[0][1]*m[1][0]))
+}
+
+// subpath is a flattened subpath in device space.
+type subpath struct {
+	pts    []point
+	closed bool
+}
+
+// path is a flattened path in device space, see 8.5.2.
+type path struct {
+	subpaths []subpath
+	cur      point // current point in device space
+	start    point // start of the current subpath
+	open     bool
+}
+
+func (p *path) empty() bool {
+	return len(p.subpaths) == 0
+}
+
+func (p *path) last() *subpath {
+	return &p.subpaths[len(p.subpaths)-1]
+}
+
+func (p *path) moveTo(pt point) {
+	if p.open && len(p.last().pts) == 1 {
+		// Drop a lone moveto.
+		p.subpaths = p.subpaths[:len(p.subpaths)-1]
+	}
+	p.subpaths = append(p.subpaths, subpath{pts: []point{pt}})
+	p.cur, p.start, p.open = pt, pt, true
+}
+
+func (p *path) ensureOpen() {
+	if !p.open {
+		p.moveTo(p.cur)
+	}
+}
+
+func (p *path) lineTo(pt point) {
+	p.ensureOpen()
+	sp := p.last()
+	sp.pts = append(sp.pts, pt)
+	p.cur = pt
+}
+
+func curveSteps(dd float64) int {
+	// dd is an estimate of the curve's deviation from its chord.
+	n := int(math.Ceil(math.Sqrt(dd / flatness)))
+	if n < 1 {
+		n = 1
+	}
+	if n > 100 {
+		n = 100
+	}
+	return n
+}
+
+func (p *path) cubeTo(p1, p2, p3 point) {
+	p.ensureOpen()
+	p0 := p.cur
+	dd := math.Max(
+		math.Hypot(p0.x-2*p1.x+p2.x, p0.y-2*p1.y+p2.y),
+		math.Hypot(p1.x-2*p2.x+p3.x, p1.y-2*p2.y+p3.y)) * 0.75
+	n := curveSteps(dd)
+	sp := p.last()
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		a, b, c, d := u*u*u, 3*u*u*t, 3*u*t*t, t*t*t
+		sp.pts = append(sp.pts, point{
+			a*p0.x + b*p1.x + c*p2.x + d*p3.x,
+			a*p0.y + b*p1.y + c*p2.y + d*p3.y,
+		})
+	}
+	p.cur = p3
+}
+
+func (p *path) quadTo(p1, p2 point) {
+	p.ensureOpen()
+	p0 := p.cur
+	dd := math.Hypot(p0.x-2*p1.x+p2.x, p0.y-2*p1.y+p2.y) * 0.25
+	n := curveSteps(dd)
+	sp := p.last()
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		a, b, c := u*u, 2*u*t, t*t
+		sp.pts = append(sp.pts, point{a*p0.x + b*p1.x + c*p2.x, a*p0.y + b*p1.y + c*p2.y})
+	}
+	p.cur = p2
+}
+
+func (p *path) closePath() {
+	if !p.open {
+		return
+	}
+	p.last().closed = true
+	p.open = false
+	p.cur = p.start
+}
+
+func (p *path) rect(m matrix.Matrix, x, y, w, h float64) {
+	p.moveTo(transform(m, x, y))
+	p.lineTo(transform(m, x+w, y))
+	p.lineTo(transform(m, x+w, y+h))
+	p.lineTo(transform(m, x, y+h))
+	p.closePath()
+}
+
+// polygons returns all subpaths as implicitly closed polygons suitable for filling.
+func (p *path) polygons() [][]point {
+	pp := make([][]point, 0, len(p.subpaths))
+	for _, sp := range p.subpaths {
+		if len(sp.pts) > 1 {
+			pp = append(pp, sp.pts)
+		}
+	}
+	return pp
+}
+
+// outlinePath transforms a glyph outline by m into a device space path.
+func outlinePath(segs []font.Segment, m matrix.Matrix) *path {
+	p := &path{}
+	for _, s := range segs {
+		switch s.Op {
+		case font.SegmentMoveTo:
+			p.closePath()
+			p.moveTo(transform(m, s.Args[0].X, s.Args[0].Y))
+		case font.SegmentLineTo:
+			p.lineTo(transform(m, s.Args[0].X, s.Args[0].Y))
+		case font.SegmentQuadTo:
+			p.quadTo(transform(m, s.Args[0].X, s.Args[0].Y), transform(m, s.Args[1].X, s.Args[1].Y))
+		case font.SegmentCubeTo:
+			p.cubeTo(transform(m, s.Args[0].X, s.Args[0].Y), transform(m, s.Args[1].X, s.Args[1].Y), transform(m, s.Args[2].X, s.Args[2].Y))
+		}
+	}
+	p.closePath()
+	return p
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// subsamples is the number of sample scanlines per pixel row used for anti-aliasing.
+const subsamples = 16
+
+type point struct {
+	x, y float64
+}
+
+type edge struct {
+	x0, y0, y1 float64 // y0 < y1
+	dxdy       float64
+	dir        int
+}
+
+type crossing struct {
+	x   float64
+	dir int
+}
+
+func validPoint(p point) bool {
+	return !math.IsNaN(p.x) && !math.IsNaN(p.y) && !math.IsInf(p.x, 0) && !math.IsInf(p.y, 0)
+}
+
+func polygonEdges(pp [][]point) ([]edge, image.Rectangle) {
+	var (
+		edges                  []edge
+		minX, minY, maxX, maxY = math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	)
+
+	for _, p := range pp {
+		n := len(p)
+		if n < 2 {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			a, b := p[i], p[(i+1)%n]
+			if !validPoint(a) || !validPoint(b) {
+				continue
+			}
+			minX, maxX = math.Min(minX, a.x), math.Max(maxX, a.x)
+			minY, maxY = math.Min(minY, a.y), math.Max(maxY, a.y)
+			if a.y == b.y {
+				continue
+			}
+			dir := 1
+			if a.y > b.y {
+				a, b = b, a
+				dir = -1
+			}
+			edges = append(edges, edge{x0: a.x, y0: a.y, y1: b.y, dxdy: (b.x - a.x) / (b.y - a.y), dir: dir})
+		}
+	}
+
+	if len(edges) == 0 {
+		return nil, image.Rectangle{}
+	}
+
+	// Avoid integer overflow for degenerate coordinates.
+	const lim = 1 << 24
+	minX, minY = math.Max(minX, -lim), math.Max(minY, -lim)
+	maxX, maxY = math.Min(maxX, lim), math.Min(maxY, lim)
+
+	r := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY)))
+
+	return edges, r
+}
+
+// coverageMask rasterizes the closed polygons pp into an anti-aliased coverage mask limited to bounds
+// using either the nonzero winding number rule or the even-odd rule, see 8.5.3.3.
+// It returns nil if nothing is covered.
+func coverageMask(pp [][]point, evenOdd bool, bounds image.Rectangle) *image.Alpha {
+	edges, r := polygonEdges(pp)
+	r = r.Intersect(bounds)
+	if r.Empty() {
+		return nil
+	}
+
+	sort.Slice(edges, func(i, j int) bool { return edges[i].y0 < edges[j].y0 })
+
+	mask := image.NewAlpha(r)
+	w := r.Dx()
+	cov := make([]float32, w+2)
+	diff := make([]float32, w+2)
+	ox := float64(r.Min.X)
+
+	const wt = 1.0 / subsamples
+
+	addSpan := func(x0, x1 float64) {
+		x0 = math.Max(x0-ox, 0)
+		x1 = math.Min(x1-ox, float64(w))
+		if x1 <= x0 {
+			return
+		}
+		i0, i1 := int(x0), int(x1)
+		if i0 == i1 {
+			cov[i0] += float32((x1 - x0) * wt)
+			return
+		}
+		cov[i0] += float32((float64(i0+1) - x0) * wt)
+		diff[i0+1] += wt
+		diff[i1] -= wt
+		cov[i1] += float32((x1 - float64(i1)) * wt)
+	}
+
+	var (
+		active []edge
+		xs     []crossing
+		next   int
+	)
+
+	// Skip edges ending above the mask.
+	for next < len(edges) && edges[next].y0 < float64(r.Min.Y) {
+		if edges[next].y1 > float64(r.Min.Y) {
+			active = append(active, edges[next])
+		}
+		next++
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for next < len(edges) && edges[next].y0 < float64(y+1) {
+			active = append(active, edges[next])
+			next++
+		}
+
+		k := 0
+		for _, e := range active {
+			if e.y1 > float64(y) {
+				active[k] = e
+				k++
+			}
+		}
+		active = active[:k]
+
+		if len(active) == 0 {
+			if next == len(edges) {
+				break
+			}
+			continue
+		}
+
+		for i := range cov {
+			cov[i], diff[i] = 0, 0
+		}
+
+		for s := 0; s < subsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/subsamples
+			xs = xs[:0]
+			for _, e := range active {
+				if sy >= e.y0 && sy < e.y1 {
+					xs = append(xs, crossing{x: e.x0 + (sy-e.y0)*e.dxdy, dir: e.dir})
+				}
+			}
+			if len(xs) < 2 {
+				continue
+			}
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+			wind := 0
+			for i := 0; i < len(xs)-1; i++ {
+				wind += xs[i].dir
+				inside := wind != 0
+				if evenOdd {
+					inside = wind&1 != 0
+				}
+				if inside {
+					addSpan(xs[i].x, xs[i+1].x)
+				}
+			}
+		}
+
+		row := mask.Pix[(y-r.Min.Y)*mask.Stride:]
+		var run float32
+		for i := 0; i < w; i++ {
+			run += diff[i]
+			a := cov[i] + run
+			switch {
+			case a <= 0:
+			case a >= 1:
+				row[i] = 255
+			default:
+				row[i] = uint8(a*255 + 0.5)
+			}
+		}
+	}
+
+	return mask
+}
+
+// intersectMasks returns the intersection of two coverage masks where nil denotes no limitation.
+func intersectMasks(m1, m2 *image.Alpha) *image.Alpha {
+	if m1 == nil {
+		return m2
+	}
+	if m2 == nil {
+		return m1
+	}
+	r := m1.Rect.Intersect(m2.Rect)
+	m := image.NewAlpha(r)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			a1 := m1.Pix[m1.PixOffset(x, y)]
+			a2 := m2.Pix[m2.PixOffset(x, y)]
+			m.Pix[m.PixOffset(x, y)] = uint8((int(a1)*int(a2) + 127) / 255)
+		}
+	}
+	return m
+}
+
+// emptyMask returns a clip mask covering nothing.
+func emptyMask() *image.Alpha {
+	return image.NewAlpha(image.Rectangle{})
+}
+
+// colorSource provides the color of a paint at device pixels.
+type colorSource interface {
+	// colorAt returns the color at pixel (x, y) in non-premultiplied form.
+	colorAt(x, y int) color.NRGBA
+}
+
+// blendPixel composites c with coverage a in [0,1] onto the premultiplied pixel p.
+func blendPixel(p []uint8, c color.NRGBA, a float64) {
+	a *= float64(c.A) / 255
+	if a <= 0 {
+		return
+	}
+	if a >= 1 {
+		p[0], p[1], p[2], p[3] = c.R, c.G, c.B, 255
+		return
+	}
+	b := 1 - a
+	p[0] = uint8(float64(c.R)*a + float64(p[0])*b + 0.5)
+	p[1] = uint8(float64(c.G)*a + float64(p[1])*b + 0.5)
+	p[2] = uint8(float64(c.B)*a + float64(p[2])*b + 0.5)
+	p[3] = uint8(255*a + float64(p[3])*b + 0.5)
+}
+
+// composite paints src through mask, the clip mask and the constant alpha onto dst.
+// A nil mask covers the whole clip region.
+func composite(dst *image.RGBA, mask, clip *image.Alpha, src colorSource, solid *color.NRGBA, alpha float64) {
+	if alpha <= 0 {
+		return
+	}
+
+	r := dst.Rect
+	if mask != nil {
+		r = r.Intersect(mask.Rect)
+	}
+	if clip != nil {
+		r = r.Intersect(clip.Rect)
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			a := alpha
+			if mask != nil {
+				m := mask.Pix[mask.PixOffset(x, y)]
+				if m == 0 {
+					continue
+				}
+				a *= float64(m) / 255
+			}
+			if clip != nil {
+				m := clip.Pix[clip.PixOffset(x, y)]
+				if m == 0 {
+					continue
+				}
+				a *= float64(m) / 255
+			}
+			var c color.NRGBA
+			if solid != nil {
+				c = *solid
+			} else {
+				c = src.colorAt(x, y)
+			}
+			i := dst.PixOffset(x, y)
+			blendPixel(dst.Pix[i:i+4], c, a)
+		}
+	}
+}
@@ -0,0 +1,1120 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package render rasterizes PDF pages.
+package render
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxFormNesting = 32
+	maxStackDepth  = 256
+	maxPixels      = 1 << 28
+)
+
+// paint is a solid color or a pattern.
+type paint struct {
+	color   color.NRGBA
+	pattern *patternPaint
+}
+
+type graphicsState struct {
+	ctm         matrix.Matrix
+	baseCTM     matrix.Matrix // the pattern matrix maps pattern space into this space, see 8.7.2
+	clip        *image.Alpha  // nil for no clipping
+	fillCS      colorSpace
+	strokeCS    colorSpace
+	fillPaint   paint
+	strokePaint paint
+	stroke      strokeStyle
+	fillAlpha   float64
+	strokeAlpha float64
+	text        textState
+	resources   types.Dict
+}
+
+// caches are shared by all renderers working on the same page.
+type caches struct {
+	fonts       map[int]*renderFont
+	functions   map[int]function
+	images      map[int]*decodedImage
+	contents    map[int][]byte
+	substitutes map[string]*pdffont.TrueType
+	forms       types.IntSet // form XObjects and glyph descriptions in process
+	ocOff       types.IntSet // optional content groups turned off
+}
+
+type renderer struct {
+	*caches
+	ctx         *model.Context
+	dst         *image.RGBA
+	gs          graphicsState
+	stack       []graphicsState
+	path        path
+	clipRule    int // pending clip: 0 = none, 1 = nonzero, 2 = even-odd
+	tm, tlm     matrix.Matrix
+	textClip    [][]point
+	forcedColor *color.NRGBA // uncolored tiling patterns and Type 3 glyphs using d1
+	inType3     bool
+	marked      []bool // marked content sections, true if hidden by optional content
+	csDepth     int
+}
+
+func newGraphicsState(ctm matrix.Matrix, res types.Dict) graphicsState {
+	black := paint{color: color.NRGBA{A: 255}}
+	return graphicsState{
+		ctm:         ctm,
+		baseCTM:     ctm,
+		fillCS:      deviceGray{},
+		strokeCS:    deviceGray{},
+		fillPaint:   black,
+		strokePaint: black,
+		stroke:      strokeStyle{width: 1, miterLimit: 10},
+		fillAlpha:   1,
+		strokeAlpha: 1,
+		text:        textState{hScale: 1},
+		resources:   res,
+	}
+}
+
+// subRenderer returns a renderer painting onto dst sharing r's caches.
+func (r *renderer) subRenderer(dst *image.RGBA, ctm matrix.Matrix, res types.Dict) *renderer {
+	return &renderer{
+		caches: r.caches,
+		ctx:    r.ctx,
+		dst:    dst,
+		gs:     newGraphicsState(ctm, res),
+		tm:     matrix.IdentMatrix,
+		tlm:    matrix.IdentMatrix,
+	}
+}
+
+func numbers(operands []types.Object, n int) ([]float64, bool) {
+	if len(operands) < n {
+		return nil, false
+	}
+	ff := make([]float64, n)
+	for i, o := range operands[len(operands)-n:] {
+		switch o := o.(type) {
+		case types.Integer:
+			ff[i] = float64(o.Value())
+		case types.Float:
+			ff[i] = o.Value()
+		default:
+			return nil, false
+		}
+	}
+	return ff, true
+}
+
+// allNumbers returns the leading numeric operands.
+func allNumbers(operands []types.Object) []float64 {
+	var ff []float64
+	for _, o := range operands {
+		switch o := o.(type) {
+		case types.Integer:
+			ff = append(ff, float64(o.Value()))
+		case types.Float:
+			ff = append(ff, o.Value())
+		default:
+			return ff
+		}
+	}
+	return ff
+}
+
+func (r *renderer) hidden() bool {
+	for _, h := range r.marked {
+		if h {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *renderer) saveGraphicsState() {
+	if len(r.stack) < maxStackDepth {
+		r.stack = append(r.stack, r.gs)
+	}
+}
+
+func (r *renderer) restoreGraphicsState() {
+	if len(r.stack) == 0 {
+		return
+	}
+	r.gs = r.stack[len(r.stack)-1]
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+// paintSource returns the color source for p.
+func (r *renderer) paintSource(p paint) (colorSource, *color.NRGBA) {
+	if r.forcedColor != nil {
+		return nil, r.forcedColor
+	}
+	if p.pattern != nil {
+		return r.patternSource(p.pattern), nil
+	}
+	if p.color.A == 0 {
+		return nil, nil
+	}
+	c := p.color
+	return nil, &c
+}
+
+func (r *renderer) paintMask(mask *image.Alpha, p paint, alpha float64) {
+	if mask == nil {
+		return
+	}
+	src, solid := r.paintSource(p)
+	if src == nil && solid == nil {
+		return
+	}
+	composite(r.dst, mask, r.gs.clip, src, solid, alpha)
+}
+
+func (r *renderer) fillPath(p *path, evenOdd bool) {
+	if r.hidden() {
+		return
+	}
+	r.paintMask(coverageMask(p.polygons(), evenOdd, r.dst.Rect), r.gs.fillPaint, r.gs.fillAlpha)
+}
+
+func (r *renderer) strokePath(p *path) {
+	if r.hidden() {
+		return
+	}
+	pp := strokePolygons(p, r.gs.stroke, r.gs.ctm)
+	r.paintMask(coverageMask(pp, false, r.dst.Rect), r.gs.strokePaint, r.gs.strokeAlpha)
+}
+
+// endPath applies a pending clip and starts a new path, see 8.5.4.
+func (r *renderer) endPath() {
+	if r.clipRule != 0 {
+		m := coverageMask(r.path.polygons(), r.clipRule == 2, r.dst.Rect)
+		if m == nil {
+			m = emptyMask()
+		}
+		r.gs.clip = intersectMasks(r.gs.clip, m)
+		r.clipRule = 0
+	}
+	r.path = path{}
+}
+
+func (r *renderer) processPathOperator(op model.Operator, operands []types.Object) bool {
+	ctm := r.gs.ctm
+	p := &r.path
+
+	switch op {
+
+	case model.OpMoveTo:
+		if ff, ok := numbers(operands, 2); ok {
+			p.moveTo(transform(ctm, ff[0], ff[1]))
+		}
+
+	case model.OpLineTo:
+		if ff, ok := numbers(operands, 2); ok {
+			p.lineTo(transform(ctm, ff[0], ff[1]))
+		}
+
+	case model.OpCurveTo:
+		if ff, ok := numbers(operands, 6); ok {
+			p.cubeTo(transform(ctm, ff[0], ff[1]), transform(ctm, ff[2], ff[3]), transform(ctm, ff[4], ff[5]))
+		}
+
+	case model.OpCurveToV:
+		if ff, ok := numbers(operands, 4); ok {
+			p.cubeTo(p.cur, transform(ctm, ff[0], ff[1]), transform(ctm, ff[2], ff[3]))
+		}
+
+	case model.OpCurveToY:
+		if ff, ok := numbers(operands, 4); ok {
+			p3 := transform(ctm, ff[2], ff[3])
+			p.cubeTo(transform(ctm, ff[0], ff[1]), p3, p3)
+		}
+
+	case model.OpClosePath:
+		p.closePath()
+
+	case model.OpRectangle:
+		if ff, ok := numbers(operands, 4); ok {
+			p.rect(ctm, ff[0], ff[1], ff[2], ff[3])
+		}
+
+	case model.OpClip:
+		r.clipRule = 1
+
+	case model.OpClipEvenOdd:
+		r.clipRule = 2
+
+	case model.OpEndPath:
+		r.endPath()
+
+	case model.OpFill, model.OpFillObsolete, model.OpFillEvenOdd:
+		r.fillPath(p, op == model.OpFillEvenOdd)
+		r.endPath()
+
+	case model.OpStroke:
+		r.strokePath(p)
+		r.endPath()
+
+	case model.OpCloseStroke:
+		p.closePath()
+		r.strokePath(p)
+		r.endPath()
+
+	case model.OpFillStroke, model.OpFillStrokeEvenOdd:
+		r.fillPath(p, op == model.OpFillStrokeEvenOdd)
+		r.strokePath(p)
+		r.endPath()
+
+	case model.OpCloseFillStroke, model.OpCloseFillStrokeEvenOdd:
+		p.closePath()
+		r.fillPath(p, op == model.OpCloseFillStrokeEvenOdd)
+		r.strokePath(p)
+		r.endPath()
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (r *renderer) setDash(a types.Array, phase float64) {
+	dd, err := numberArray(r.ctx.XRefTable, a)
+	if err != nil {
+		return
+	}
+	r.gs.stroke.dashes, r.gs.stroke.dashPhase = dd, phase
+}
+
+func (r *renderer) setExtGState(name string) error {
+	d, err := r.ctx.DereferenceDict(r.gs.resources["ExtGState"])
+	if err != nil || d == nil {
+		return err
+	}
+	o, found := d.Find(name)
+	if !found {
+		return nil
+	}
+	gs, err := r.ctx.DereferenceDict(o)
+	if err != nil || gs == nil {
+		return err
+	}
+
+	for k, v := range gs {
+		switch k {
+		case "LW":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.stroke.width = f
+			}
+		case "LC":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.stroke.cap = int(f)
+			}
+		case "LJ":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.stroke.join = int(f)
+			}
+		case "ML":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.stroke.miterLimit = f
+			}
+		case "D":
+			if a, err := r.ctx.DereferenceArray(v); err == nil && len(a) == 2 {
+				if dd, ok := a[0].(types.Array); ok {
+					phase, _ := r.ctx.DereferenceNumber(a[1])
+					r.setDash(dd, phase)
+				}
+			}
+		case "CA":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.strokeAlpha = clamp(f, 0, 1)
+			}
+		case "ca":
+			if f, err := r.ctx.DereferenceNumber(v); err == nil {
+				r.gs.fillAlpha = clamp(f, 0, 1)
+			}
+		case "Font":
+			if a, err := r.ctx.DereferenceArray(v); err == nil && len(a) == 2 {
+				r.setExtGStateFont(a)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *renderer) setExtGStateFont(a types.Array) {
+	indRef, ok := a[0].(types.IndirectRef)
+	if !ok {
+		return
+	}
+	size, err := r.ctx.DereferenceNumber(a[1])
+	if err != nil {
+		return
+	}
+	objNr := indRef.ObjectNumber.Value()
+	rf, ok := r.fonts[objNr]
+	if !ok {
+		d, err := r.ctx.DereferenceDict(indRef)
+		if err != nil || d == nil {
+			return
+		}
+		if rf, err = r.newRenderFont(d); err != nil {
+			return
+		}
+		r.fonts[objNr] = rf
+	}
+	r.gs.text.font, r.gs.text.fontSize = rf, size
+}
+
+func (r *renderer) processGraphicsStateOperator(op model.Operator, operands []types.Object) (bool, error) {
+	switch op {
+
+	case model.OpSave:
+		r.saveGraphicsState()
+
+	case model.OpRestore:
+		r.restoreGraphicsState()
+
+	case model.OpConcatMatrix:
+		if ff, ok := numbers(operands, 6); ok {
+			r.gs.ctm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5]).Multiply(r.gs.ctm)
+		}
+
+	case model.OpSetLineWidth:
+		if ff, ok := numbers(operands, 1); ok {
+			r.gs.stroke.width = ff[0]
+		}
+
+	case model.OpSetLineCap:
+		if ff, ok := numbers(operands, 1); ok {
+			r.gs.stroke.cap = int(ff[0])
+		}
+
+	case model.OpSetLineJoin:
+		if ff, ok := numbers(operands, 1); ok {
+			r.gs.stroke.join = int(ff[0])
+		}
+
+	case model.OpSetMiterLimit:
+		if ff, ok := numbers(operands, 1); ok {
+			r.gs.stroke.miterLimit = ff[0]
+		}
+
+	case model.OpSetDash:
+		if len(operands) == 2 {
+			if a, ok := operands[0].(types.Array); ok {
+				phase, _ := numbers(operands, 1)
+				if phase != nil {
+					r.setDash(a, phase[0])
+				}
+			}
+		}
+
+	case model.OpSetExtGState:
+		if len(operands) > 0 {
+			if n, ok := operands[len(operands)-1].(types.Name); ok {
+				return true, r.setExtGState(n.Value())
+			}
+		}
+
+	case model.OpSetRenderingIntent, model.OpSetFlatness:
+		// Rendering intent and flatness tolerance are ignored.
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *renderer) setColorSpace(operands []types.Object, stroke bool) error {
+	if len(operands) == 0 || r.forcedColor != nil {
+		return nil
+	}
+	cs, err := r.colorSpace(operands[len(operands)-1], r.gs.resources)
+	if err != nil {
+		return err
+	}
+	p := paint{}
+	if _, ok := cs.(patternSpace); !ok {
+		p.color = cs.rgb(cs.initial())
+	}
+	if stroke {
+		r.gs.strokeCS, r.gs.strokePaint = cs, p
+	} else {
+		r.gs.fillCS, r.gs.fillPaint = cs, p
+	}
+	return nil
+}
+
+func (r *renderer) setColor(operands []types.Object, stroke bool) error {
+	if r.forcedColor != nil {
+		return nil
+	}
+
+	cs := r.gs.fillCS
+	if stroke {
+		cs = r.gs.strokeCS
+	}
+
+	var p paint
+
+	if pcs, ok := cs.(patternSpace); ok {
+		if len(operands) == 0 {
+			return nil
+		}
+		n, ok := operands[len(operands)-1].(types.Name)
+		if !ok {
+			return nil
+		}
+		d, err := r.ctx.DereferenceDict(r.gs.resources["Pattern"])
+		if err != nil || d == nil {
+			return err
+		}
+		o, found := d.Find(n.Value())
+		if !found {
+			return nil
+		}
+		p.pattern = &patternPaint{
+			obj:   o,
+			comps: allNumbers(operands),
+			base:  pcs.base,
+			ctm:   r.gs.baseCTM,
+			res:   r.gs.resources,
+		}
+	} else {
+		p.color = cs.rgb(allNumbers(operands))
+	}
+
+	if stroke {
+		r.gs.strokePaint = p
+	} else {
+		r.gs.fillPaint = p
+	}
+
+	return nil
+}
+
+func (r *renderer) setDeviceColor(cs colorSpace, operands []types.Object, stroke bool) {
+	if r.forcedColor != nil {
+		return
+	}
+	ff, ok := numbers(operands, cs.ncomps())
+	if !ok {
+		return
+	}
+	p := paint{color: cs.rgb(ff)}
+	if stroke {
+		r.gs.strokeCS, r.gs.strokePaint = cs, p
+	} else {
+		r.gs.fillCS, r.gs.fillPaint = cs, p
+	}
+}
+
+func (r *renderer) processColorOperator(op model.Operator, operands []types.Object) (bool, error) {
+	switch op {
+
+	case model.OpSetStrokeColorSpace, model.OpSetFillColorSpace:
+		return true, r.setColorSpace(operands, op == model.OpSetStrokeColorSpace)
+
+	case model.OpSetStrokeColor, model.OpSetStrokeColorN, model.OpSetFillColor, model.OpSetFillColorN:
+		return true, r.setColor(operands, op == model.OpSetStrokeColor || op == model.OpSetStrokeColorN)
+
+	case model.OpSetStrokeGray, model.OpSetFillGray:
+		r.setDeviceColor(deviceGray{}, operands, op == model.OpSetStrokeGray)
+
+	case model.OpSetStrokeRGB, model.OpSetFillRGB:
+		r.setDeviceColor(deviceRGB{}, operands, op == model.OpSetStrokeRGB)
+
+	case model.OpSetStrokeCMYK, model.OpSetFillCMYK:
+		r.setDeviceColor(deviceCMYK{}, operands, op == model.OpSetStrokeCMYK)
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *renderer) cachedContent(objNr int, sd *types.StreamDict) ([]byte, error) {
+	if bb, ok := r.contents[objNr]; ok {
+		return bb, nil
+	}
+	bb, err := r.streamContent(sd)
+	if err != nil {
+		return nil, err
+	}
+	r.contents[objNr] = bb
+	return bb, nil
+}
+
+func (r *renderer) streamContent(sd *types.StreamDict) ([]byte, error) {
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	return sd.Content, nil
+}
+
+// doForm paints a form XObject, see 8.10.
+func (r *renderer) doForm(sd *types.StreamDict, objNr int) error {
+	if r.forms[objNr] || len(r.forms) >= maxFormNesting {
+		return nil
+	}
+
+	content, err := r.cachedContent(objNr, sd)
+	if err != nil {
+		return err
+	}
+
+	r.forms[objNr] = true
+	defer delete(r.forms, objNr)
+
+	r.saveGraphicsState()
+	defer r.restoreGraphicsState()
+
+	tm, tlm, p, marked := r.tm, r.tlm, r.path, r.marked
+	defer func() { r.tm, r.tlm, r.path, r.marked = tm, tlm, p, marked }()
+	r.path = path{}
+	r.marked = append([]bool{}, marked...)
+
+	if ff, err := numberArray(r.ctx.XRefTable, sd.Dict["Matrix"]); err == nil && len(ff) == 6 {
+		r.gs.ctm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5]).Multiply(r.gs.ctm)
+	}
+	r.gs.baseCTM = r.gs.ctm
+
+	if ff, err := numberArray(r.ctx.XRefTable, sd.Dict["BBox"]); err == nil && len(ff) == 4 {
+		bp := &path{}
+		bp.rect(r.gs.ctm, ff[0], ff[1], ff[2]-ff[0], ff[3]-ff[1])
+		m := coverageMask(bp.polygons(), false, r.dst.Rect)
+		if m == nil {
+			return nil
+		}
+		r.gs.clip = intersectMasks(r.gs.clip, m)
+	}
+
+	if res, err := r.ctx.DereferenceDict(sd.Dict["Resources"]); err == nil && res != nil {
+		r.gs.resources = res
+	}
+
+	return r.processContent(content)
+}
+
+func (r *renderer) doXObject(operands []types.Object) error {
+	if len(operands) == 0 {
+		return nil
+	}
+	n, ok := operands[len(operands)-1].(types.Name)
+	if !ok {
+		return nil
+	}
+
+	d, err := r.ctx.DereferenceDict(r.gs.resources["XObject"])
+	if err != nil || d == nil {
+		return err
+	}
+
+	o, found := d.Find(n.Value())
+	if !found {
+		return nil
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+	objNr := indRef.ObjectNumber.Value()
+
+	sd, _, err := r.ctx.DereferenceStreamDict(indRef)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	if oc, found := sd.Dict.Find("OC"); found && !r.ocVisible(oc) {
+		return nil
+	}
+
+	st := sd.Subtype()
+	if st == nil {
+		return nil
+	}
+
+	switch *st {
+	case "Image":
+		if r.hidden() {
+			return nil
+		}
+		return r.doImage(sd, objNr)
+	case "Form":
+		return r.doForm(sd, objNr)
+	}
+
+	return nil
+}
+
+func (r *renderer) processMarkedContentOperator(op model.Operator, operands []types.Object) bool {
+	switch op {
+
+	case model.OpBeginMarkedContent:
+		r.marked = append(r.marked, false)
+
+	case model.OpBeginMarkedContentProps:
+		hidden := false
+		if len(operands) == 2 {
+			if tag, ok := operands[0].(types.Name); ok && tag.Value() == "OC" {
+				hidden = !r.ocVisibleProperties(operands[1])
+			}
+		}
+		r.marked = append(r.marked, hidden)
+
+	case model.OpEndMarkedContent:
+		if len(r.marked) > 0 {
+			r.marked = r.marked[:len(r.marked)-1]
+		}
+
+	case model.OpMarkPoint, model.OpMarkPointProps, model.OpBeginCompatibility, model.OpEndCompatibility:
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (r *renderer) processOperator(op *model.ContentOperation) error {
+	operands := op.Operands
+
+	if ok, err := r.processGraphicsStateOperator(op.Operator, operands); ok {
+		return err
+	}
+
+	if r.processPathOperator(op.Operator, operands) {
+		return nil
+	}
+
+	if ok, err := r.processColorOperator(op.Operator, operands); ok {
+		return err
+	}
+
+	if ok, err := r.processTextStateOperator(op.Operator, operands); ok {
+		return err
+	}
+
+	if ok, err := r.processTextOperator(op.Operator, operands); ok {
+		return err
+	}
+
+	if r.processMarkedContentOperator(op.Operator, operands) {
+		return nil
+	}
+
+	switch op.Operator {
+
+	case model.OpPaintXObject:
+		return r.doXObject(operands)
+
+	case model.OpBeginInlineImage:
+		return r.doInlineImage(op.Image)
+
+	case model.OpShade:
+		if len(operands) > 0 && !r.hidden() {
+			if n, ok := operands[len(operands)-1].(types.Name); ok {
+				return r.shade(n.Value())
+			}
+		}
+
+	case model.OpSetCharWidth:
+		// Colored Type 3 glyph.
+
+	case model.OpSetCacheDevice:
+		if r.inType3 && r.forcedColor == nil && r.gs.fillPaint.pattern == nil {
+			// Uncolored Type 3 glyph painted using the current fill color.
+			c := r.gs.fillPaint.color
+			r.forcedColor = &c
+		}
+	}
+
+	return nil
+}
+
+func (r *renderer) processContent(bb []byte) error {
+	p := model.NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.processOperator(op); err != nil {
+			// Render as much as possible.
+			if log.DebugEnabled() {
+				log.Debug.Printf("render: %s: %v\n", op.Operator, err)
+			}
+		}
+	}
+}
+
+// initOptionalContent determines the optional content groups turned off
+// according to the default viewing configuration, see 8.11.4.3.
+func (r *renderer) initOptionalContent() {
+	cat, err := r.ctx.Catalog()
+	if err != nil || cat == nil {
+		return
+	}
+	ocp, err := r.ctx.DereferenceDict(cat["OCProperties"])
+	if err != nil || ocp == nil {
+		return
+	}
+	d, err := r.ctx.DereferenceDict(ocp["D"])
+	if err != nil || d == nil {
+		return
+	}
+
+	objNrs := func(o types.Object) []int {
+		a, err := r.ctx.DereferenceArray(o)
+		if err != nil {
+			return nil
+		}
+		var nn []int
+		for _, o := range a {
+			if indRef, ok := o.(types.IndirectRef); ok {
+				nn = append(nn, indRef.ObjectNumber.Value())
+			}
+		}
+		return nn
+	}
+
+	if bs := d.NameEntry("BaseState"); bs != nil && *bs == "OFF" {
+		for _, nr := range objNrs(ocp["OCGs"]) {
+			r.ocOff[nr] = true
+		}
+		for _, nr := range objNrs(d["ON"]) {
+			delete(r.ocOff, nr)
+		}
+	}
+
+	for _, nr := range objNrs(d["OFF"]) {
+		r.ocOff[nr] = true
+	}
+}
+
+// ocVisible returns true if the optional content group or membership dict o is visible, see 8.11.2.
+func (r *renderer) ocVisible(o types.Object) bool {
+	indRef, ok := o.(types.IndirectRef)
+	if ok && r.ocOff[indRef.ObjectNumber.Value()] {
+		return false
+	}
+
+	d, err := r.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return true
+	}
+
+	if t := d.Type(); t == nil || *t != "OCMD" {
+		return true
+	}
+
+	// Optional content membership dict.
+	var groups []bool
+	o1, err := r.ctx.Dereference(d["OCGs"])
+	if err != nil {
+		return true
+	}
+	switch o1 := o1.(type) {
+	case types.Dict:
+		groups = append(groups, r.ocVisible(d["OCGs"]))
+	case types.Array:
+		for _, o2 := range o1 {
+			if o2 != nil {
+				groups = append(groups, r.ocVisible(o2))
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return true
+	}
+
+	policy := "AnyOn"
+	if p := d.NameEntry("P"); p != nil {
+		policy = *p
+	}
+
+	var on, off int
+	for _, v := range groups {
+		if v {
+			on++
+		} else {
+			off++
+		}
+	}
+
+	switch policy {
+	case "AllOn":
+		return off == 0
+	case "AnyOff":
+		return off > 0
+	case "AllOff":
+		return on == 0
+	}
+
+	return on > 0
+}
+
+func (r *renderer) ocVisibleProperties(o types.Object) bool {
+	if n, ok := o.(types.Name); ok {
+		d, err := r.ctx.DereferenceDict(r.gs.resources["Properties"])
+		if err != nil || d == nil {
+			return true
+		}
+		o1, found := d.Find(n.Value())
+		if !found {
+			return true
+		}
+		o = o1
+	}
+	return r.ocVisible(o)
+}
+
+func annotationRect(ctx *model.Context, d types.Dict) (*types.Rectangle, error) {
+	ff, err := numberArray(ctx.XRefTable, d["Rect"])
+	if err != nil || len(ff) != 4 {
+		return nil, errors.New("pdfcpu: corrupt annotation Rect")
+	}
+	return types.NewRectangle(
+		math.Min(ff[0], ff[2]), math.Min(ff[1], ff[3]),
+		math.Max(ff[0], ff[2]), math.Max(ff[1], ff[3])), nil
+}
+
+// appearanceStream returns the normal appearance stream of an annotation, see 12.5.5.
+func (r *renderer) appearanceStream(d types.Dict) (*types.StreamDict, int, error) {
+	ap, err := r.ctx.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return nil, 0, err
+	}
+	o, found := ap.Find("N")
+	if !found {
+		return nil, 0, nil
+	}
+
+	o1, err := r.ctx.Dereference(o)
+	if err != nil {
+		return nil, 0, err
+	}
+	if states, ok := o1.(types.Dict); ok {
+		as := d.NameEntry("AS")
+		if as == nil {
+			return nil, 0, nil
+		}
+		if o, found = states.Find(*as); !found {
+			return nil, 0, nil
+		}
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil, 0, nil
+	}
+	sd, _, err := r.ctx.DereferenceStreamDict(indRef)
+	if err != nil || sd == nil {
+		return nil, 0, err
+	}
+
+	return sd, indRef.ObjectNumber.Value(), nil
+}
+
+// renderAnnotation paints the normal appearance of an annotation, see 12.5.5.
+func (r *renderer) renderAnnotation(d types.Dict) error {
+	if f := d.IntEntry("F"); f != nil && model.AnnotationFlags(*f)&(model.AnnHidden|model.AnnNoView) != 0 {
+		return nil
+	}
+	if oc, found := d.Find("OC"); found && !r.ocVisible(oc) {
+		return nil
+	}
+
+	sd, objNr, err := r.appearanceStream(d)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	rect, err := annotationRect(r.ctx, d)
+	if err != nil {
+		return err
+	}
+
+	bbox, err := numberArray(r.ctx.XRefTable, sd.Dict["BBox"])
+	if err != nil || len(bbox) != 4 {
+		return nil
+	}
+
+	m := matrix.IdentMatrix
+	if ff, err := numberArray(r.ctx.XRefTable, sd.Dict["Matrix"]); err == nil && len(ff) == 6 {
+		m = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+	}
+
+	// Map the transformed appearance box onto the annotation rectangle.
+	pp := []point{transform(m, bbox[0], bbox[1]), transform(m, bbox[2], bbox[1]), transform(m, bbox[2], bbox[3]), transform(m, bbox[0], bbox[3])}
+	minX, minY, maxX, maxY := pp[0].x, pp[0].y, pp[0].x, pp[0].y
+	for _, p := range pp[1:] {
+		minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+		maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+	}
+	if maxX-minX == 0 || maxY-minY == 0 {
+		return nil
+	}
+	sx, sy := rect.Width()/(maxX-minX), rect.Height()/(maxY-minY)
+	a := pdfMatrix(sx, 0, 0, sy, rect.LL.X-minX*sx, rect.LL.Y-minY*sy)
+
+	r.saveGraphicsState()
+	defer r.restoreGraphicsState()
+	r.gs.ctm = a.Multiply(r.gs.ctm)
+
+	return r.doForm(sd, objNr)
+}
+
+func (r *renderer) renderAnnotations(pageDict types.Dict) error {
+	a, err := r.ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil || a == nil {
+		return err
+	}
+	for _, o := range a {
+		d, err := r.ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+		if err := r.renderAnnotation(d); err != nil {
+			if log.DebugEnabled() {
+				log.Debug.Printf("render: annotation: %v\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+// deviceMatrix returns the transformation from default user space into device space for a page, see 8.3.2.3.
+func deviceMatrix(box *types.Rectangle, rot int, s float64) matrix.Matrix {
+	llx, lly, urx, ury := box.LL.X, box.LL.Y, box.UR.X, box.UR.Y
+	switch rot {
+	case 90:
+		return pdfMatrix(0, s, s, 0, -lly*s, -llx*s)
+	case 180:
+		return pdfMatrix(-s, 0, 0, s, urx*s, -lly*s)
+	case 270:
+		return pdfMatrix(0, -s, -s, 0, ury*s, urx*s)
+	}
+	return pdfMatrix(s, 0, 0, -s, -llx*s, ury*s)
+}
+
+// Page renders page pageNr of ctx at the given resolution in dots per inch.
+func Page(ctx *model.Context, pageNr int, dpi float64) (*image.RGBA, error) {
+	if dpi <= 0 {
+		return nil, errors.Errorf("pdfcpu: invalid resolution: %.2f dpi", dpi)
+	}
+
+	d, _, inhPAttrs, err := ctx.PageDict(pageNr, false)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, errors.Errorf("pdfcpu: unknown page number: %d", pageNr)
+	}
+
+	box := inhPAttrs.CropBox
+	if box == nil {
+		box = inhPAttrs.MediaBox
+	}
+	if box == nil {
+		return nil, errors.Errorf("pdfcpu: page %d: missing MediaBox", pageNr)
+	}
+
+	rot := inhPAttrs.Rotate % 360
+	if rot < 0 {
+		rot += 360
+	}
+	rot = rot / 90 * 90
+
+	s := dpi / 72
+	w, h := int(math.Round(box.Width()*s)), int(math.Round(box.Height()*s))
+	if rot == 90 || rot == 270 {
+		w, h = h, w
+	}
+	if w <= 0 || h <= 0 || float64(w)*float64(h) > maxPixels {
+		return nil, errors.Errorf("pdfcpu: page %d: invalid image size %dx%d", pageNr, w, h)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	r := &renderer{
+		caches: &caches{
+			fonts:       map[int]*renderFont{},
+			functions:   map[int]function{},
+			images:      map[int]*decodedImage{},
+			contents:    map[int][]byte{},
+			substitutes: map[string]*pdffont.TrueType{},
+			forms:       types.IntSet{},
+			ocOff:       types.IntSet{},
+		},
+		ctx: ctx,
+		dst: img,
+		gs:  newGraphicsState(deviceMatrix(box, rot, s), inhPAttrs.Resources),
+		tm:  matrix.IdentMatrix,
+		tlm: matrix.IdentMatrix,
+	}
+
+	r.initOptionalContent()
+
+	bb, err := ctx.PageContent(d)
+	if err != nil && err != model.ErrNoContent {
+		return nil, err
+	}
+
+	if err == nil {
+		if err := r.processContent(bb); err != nil {
+			return nil, err
+		}
+	}
+
+	r.gs = newGraphicsState(deviceMatrix(box, rot, s), inhPAttrs.Resources)
+	r.stack, r.marked = nil, nil
+
+	if err := r.renderAnnotations(d); err != nil {
+		return nil, err
+	}
+
+	if log.DebugEnabled() {
+		log.Debug.Printf("render: page %d: %dx%d pixels\n", pageNr, w, h)
+	}
+
+	return img, nil
+}
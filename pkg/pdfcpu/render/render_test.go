@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestPostScriptFunction(t *testing.T) {
+	fb := functionBase{domain: []float64{0, 1, 0, 1}, rng: []float64{0, 1, 0, 1}}
+
+	for _, tt := range []struct {
+		prog string
+		in   []float64
+		want []float64
+	}{
+		{"{ exch }", []float64{.2, .8}, []float64{.8, .2}},
+		{"{ add 2 div dup }", []float64{.2, .6}, []float64{.4, .4}},
+		{"{ gt { 1 0 } { 0 1 } ifelse }", []float64{.7, .3}, []float64{1, 0}},
+		{"{ 360 mul sin 2 div exch 360 mul sin 2 div add 0 }", []float64{.25, 0}, []float64{.5, 0}},
+		{"{ 2 copy add 3 1 roll pop }", []float64{.5, .25}, []float64{.75, .5}},
+	} {
+		f, err := parsePostScriptFunction([]byte(tt.prog), fb)
+		if err != nil {
+			t.Fatalf("%s: %v\n", tt.prog, err)
+		}
+		got := f.eval(tt.in)
+		if len(got) < len(tt.want) {
+			t.Fatalf("%s: want %v, got %v\n", tt.prog, tt.want, got)
+		}
+		for i := range tt.want {
+			if math.Abs(got[i]-tt.want[i]) > 1e-6 {
+				t.Fatalf("%s: want %v, got %v\n", tt.prog, tt.want, got)
+			}
+		}
+	}
+}
+
+func TestCoverageMask(t *testing.T) {
+	bounds := image.Rect(0, 0, 20, 20)
+
+	square := []point{{5, 5}, {15, 5}, {15, 15}, {5, 15}}
+	inner := []point{{8, 8}, {12, 8}, {12, 12}, {8, 12}}
+
+	m := coverageMask([][]point{square}, false, bounds)
+	if m == nil || m.AlphaAt(10, 10).A != 255 || m.AlphaAt(2, 2).A != 0 {
+		t.Fatal("nonzero fill of square")
+	}
+
+	// Both subpaths share the same orientation:
+	// the nonzero rule fills the inner square, the even-odd rule doesn't.
+	m = coverageMask([][]point{square, inner}, false, bounds)
+	if m.AlphaAt(10, 10).A != 255 {
+		t.Fatal("nonzero fill of nested squares")
+	}
+	m = coverageMask([][]point{square, inner}, true, bounds)
+	if m.AlphaAt(10, 10).A != 0 || m.AlphaAt(6, 6).A != 255 {
+		t.Fatal("even-odd fill of nested squares")
+	}
+
+	// Half covered pixels get antialiased.
+	m = coverageMask([][]point{{{0, 0}, {5.5, 0}, {5.5, 1}, {0, 1}}}, false, bounds)
+	if a := m.AlphaAt(5, 0).A; a < 96 || a > 160 {
+		t.Fatalf("antialiasing: got alpha %d\n", a)
+	}
+
+	if m = coverageMask([][]point{{{30, 30}, {40, 30}, {40, 40}}}, false, bounds); m != nil {
+		t.Fatal("want nil mask for path outside bounds")
+	}
+}
@@ -0,0 +1,505 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// maxTileSize limits the size of a rendered pattern cell in device pixels.
+const maxTileSize = 1024
+
+// shading is a function based, axial or radial shading, see 8.7.4.5.
+type shading struct {
+	typ        int
+	cs         colorSpace
+	fn         function
+	coords     []float64
+	domain     []float64
+	extend     [2]bool
+	matrix     matrix.Matrix // type 1 only
+	background *color.NRGBA  // used by shading patterns only
+	bbox       *types.Rectangle
+	lut        [256]color.NRGBA // types 2 and 3
+}
+
+func (sh *shading) colorFor(comps []float64) color.NRGBA {
+	if sh.fn != nil {
+		comps = sh.fn.eval(comps)
+	}
+	return sh.cs.rgb(comps)
+}
+
+func (r *renderer) shading(o types.Object, res types.Dict) (*shading, error) {
+	o, err := r.ctx.Dereference(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var d types.Dict
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.StreamDict:
+		d = o.Dict
+	default:
+		return nil, errors.New("pdfcpu: invalid shading")
+	}
+
+	st := d.IntEntry("ShadingType")
+	if st == nil {
+		return nil, errors.New("pdfcpu: shading: missing ShadingType")
+	}
+
+	sh := &shading{typ: *st, matrix: matrix.IdentMatrix}
+
+	if sh.typ < 1 || sh.typ > 3 {
+		// Mesh based shadings are not supported.
+		return sh, nil
+	}
+
+	if sh.cs, err = r.colorSpace(d["ColorSpace"], res); err != nil {
+		return nil, err
+	}
+
+	if o, found := d.Find("Function"); found {
+		if sh.fn, err = r.function(o); err != nil {
+			return nil, err
+		}
+	}
+	if sh.fn == nil {
+		return nil, errors.New("pdfcpu: shading: missing Function")
+	}
+
+	if o, found := d.Find("Background"); found {
+		if ff, err := numberArray(r.ctx.XRefTable, o); err == nil {
+			c := sh.cs.rgb(ff)
+			sh.background = &c
+		}
+	}
+
+	if o, found := d.Find("BBox"); found {
+		if ff, err := numberArray(r.ctx.XRefTable, o); err == nil && len(ff) == 4 {
+			rect := types.NewRectangle(ff[0], ff[1], ff[2], ff[3])
+			sh.bbox = rect
+		}
+	}
+
+	if sh.domain, err = numberArray(r.ctx.XRefTable, d["Domain"]); err != nil {
+		return nil, err
+	}
+
+	if sh.typ == 1 {
+		if len(sh.domain) < 4 {
+			sh.domain = []float64{0, 1, 0, 1}
+		}
+		if ff, err := numberArray(r.ctx.XRefTable, d["Matrix"]); err == nil && len(ff) == 6 {
+			sh.matrix = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+		}
+		return sh, nil
+	}
+
+	if len(sh.domain) < 2 {
+		sh.domain = []float64{0, 1}
+	}
+
+	if sh.coords, err = numberArray(r.ctx.XRefTable, d["Coords"]); err != nil {
+		return nil, err
+	}
+	if (sh.typ == 2 && len(sh.coords) < 4) || (sh.typ == 3 && len(sh.coords) < 6) {
+		return nil, errors.New("pdfcpu: shading: corrupt Coords")
+	}
+
+	if a, err := r.ctx.DereferenceArray(d["Extend"]); err == nil && len(a) == 2 {
+		for i, o := range a {
+			if b, ok := o.(types.Boolean); ok {
+				sh.extend[i] = b.Value()
+			}
+		}
+	}
+
+	for i := range sh.lut {
+		t := interpolate(float64(i), 0, 255, sh.domain[0], sh.domain[1])
+		sh.lut[i] = sh.colorFor([]float64{t})
+	}
+
+	return sh, nil
+}
+
+// shadingSource paints a shading defined in a coordinate space mapped to device space by m.
+type shadingSource struct {
+	sh         *shading
+	inv        matrix.Matrix // device space to shading space
+	background bool
+}
+
+func newShadingSource(sh *shading, m matrix.Matrix, background bool) (*shadingSource, bool) {
+	if sh.typ == 1 {
+		m = sh.matrix.Multiply(m)
+	}
+	inv, ok := invert(m)
+	if !ok {
+		return nil, false
+	}
+	return &shadingSource{sh: sh, inv: inv, background: background && sh.background != nil}, true
+}
+
+func (ss *shadingSource) outside() color.NRGBA {
+	if ss.background {
+		return *ss.sh.background
+	}
+	return color.NRGBA{}
+}
+
+func (ss *shadingSource) lookup(t float64) color.NRGBA {
+	i := int(math.Round(clamp(t, 0, 1) * 255))
+	return ss.sh.lut[i]
+}
+
+func (ss *shadingSource) axial(p point) color.NRGBA {
+	c := ss.sh.coords
+	dx, dy := c[2]-c[0], c[3]-c[1]
+	l := dx*dx + dy*dy
+	if l == 0 {
+		return ss.outside()
+	}
+	t := ((p.x-c[0])*dx + (p.y-c[1])*dy) / l
+	if (t < 0 && !ss.sh.extend[0]) || (t > 1 && !ss.sh.extend[1]) {
+		return ss.outside()
+	}
+	return ss.lookup(t)
+}
+
+func (ss *shadingSource) radialParam(p point) (float64, bool) {
+	c := ss.sh.coords
+	x0, y0, r0, x1, y1, r1 := c[0], c[1], c[2], c[3], c[4], c[5]
+	cdx, cdy, dr := x1-x0, y1-y0, r1-r0
+	pdx, pdy := p.x-x0, p.y-y0
+
+	a := cdx*cdx + cdy*cdy - dr*dr
+	b := pdx*cdx + pdy*cdy + r0*dr
+	cc := pdx*pdx + pdy*pdy - r0*r0
+
+	valid := func(s float64) bool {
+		if r0+s*dr < 0 {
+			return false
+		}
+		return (s >= 0 || ss.sh.extend[0]) && (s <= 1 || ss.sh.extend[1])
+	}
+
+	if math.Abs(a) < 1e-9 {
+		if b == 0 {
+			return 0, false
+		}
+		s := cc / (2 * b)
+		return s, valid(s)
+	}
+
+	disc := b*b - a*cc
+	if disc < 0 {
+		return 0, false
+	}
+	sq := math.Sqrt(disc)
+	s1, s2 := (b+sq)/a, (b-sq)/a
+	if s1 < s2 {
+		s1, s2 = s2, s1
+	}
+	if valid(s1) {
+		return s1, true
+	}
+	if valid(s2) {
+		return s2, true
+	}
+	return 0, false
+}
+
+func (ss *shadingSource) radial(p point) color.NRGBA {
+	s, ok := ss.radialParam(p)
+	if !ok {
+		return ss.outside()
+	}
+	return ss.lookup(s)
+}
+
+func (ss *shadingSource) functionBased(p point) color.NRGBA {
+	d := ss.sh.domain
+	if p.x < d[0] || p.x > d[1] || p.y < d[2] || p.y > d[3] {
+		return ss.outside()
+	}
+	return ss.sh.colorFor([]float64{p.x, p.y})
+}
+
+func (ss *shadingSource) colorAt(x, y int) color.NRGBA {
+	p := transform(ss.inv, float64(x)+0.5, float64(y)+0.5)
+	switch ss.sh.typ {
+	case 1:
+		return ss.functionBased(p)
+	case 2:
+		return ss.axial(p)
+	case 3:
+		return ss.radial(p)
+	}
+	return color.NRGBA{}
+}
+
+// shade paints the shading resource named n into the current clip region, see 8.7.4.2.
+func (r *renderer) shade(n string) error {
+	d, err := r.ctx.DereferenceDict(r.gs.resources["Shading"])
+	if err != nil || d == nil {
+		return err
+	}
+	o, found := d.Find(n)
+	if !found {
+		return nil
+	}
+
+	sh, err := r.shading(o, r.gs.resources)
+	if err != nil {
+		return err
+	}
+	if sh.typ < 1 || sh.typ > 3 {
+		if log.DebugEnabled() {
+			log.Debug.Printf("render: unsupported shading type %d\n", sh.typ)
+		}
+		return nil
+	}
+
+	src, ok := newShadingSource(sh, r.gs.ctm, false)
+	if !ok {
+		return nil
+	}
+
+	var mask *image.Alpha
+	if sh.bbox != nil {
+		p := &path{}
+		p.rect(r.gs.ctm, sh.bbox.LL.X, sh.bbox.LL.Y, sh.bbox.Width(), sh.bbox.Height())
+		if mask = coverageMask(p.polygons(), false, r.dst.Rect); mask == nil {
+			return nil
+		}
+	}
+
+	composite(r.dst, mask, r.gs.clip, src, nil, r.gs.fillAlpha)
+
+	return nil
+}
+
+// patternPaint is a pattern used as color, see 8.7.
+type patternPaint struct {
+	obj   types.Object
+	comps []float64     // uncolored tiling patterns only
+	base  colorSpace    // uncolored tiling patterns only
+	ctm   matrix.Matrix // maps the pattern's default coordinate space to device space
+	res   types.Dict    // resources of the content stream using the pattern
+	src   colorSource   // lazily created
+	done  bool
+}
+
+// tileSource paints a tiling pattern by repeating a rendered pattern cell.
+type tileSource struct {
+	tile         *image.RGBA
+	inv          matrix.Matrix // device space to pattern space
+	bx, by       float64       // origin of the pattern cell in pattern space
+	xStep, yStep float64
+	sx, sy       float64 // pattern space to tile pixels
+}
+
+func (ts *tileSource) colorAt(x, y int) color.NRGBA {
+	p := transform(ts.inv, float64(x)+0.5, float64(y)+0.5)
+	u := math.Mod(p.x-ts.bx, ts.xStep)
+	if u < 0 {
+		u += ts.xStep
+	}
+	v := math.Mod(p.y-ts.by, ts.yStep)
+	if v < 0 {
+		v += ts.yStep
+	}
+	b := ts.tile.Rect
+	i := min(int(u*ts.sx), b.Dx()-1)
+	j := min(int(v*ts.sy), b.Dy()-1)
+	k := ts.tile.PixOffset(i, j)
+	px := ts.tile.Pix[k : k+4]
+	if px[3] == 0 {
+		return color.NRGBA{}
+	}
+	if px[3] == 255 {
+		return color.NRGBA{px[0], px[1], px[2], 255}
+	}
+	// Unpremultiply.
+	a := uint32(px[3])
+	return color.NRGBA{uint8(uint32(px[0]) * 255 / a), uint8(uint32(px[1]) * 255 / a), uint8(uint32(px[2]) * 255 / a), px[3]}
+}
+
+func (r *renderer) tilingPattern(pp *patternPaint, sd *types.StreamDict, m matrix.Matrix) (colorSource, error) {
+	d := sd.Dict
+
+	bbox, err := numberArray(r.ctx.XRefTable, d["BBox"])
+	if err != nil || len(bbox) != 4 {
+		return nil, errors.New("pdfcpu: tiling pattern: corrupt BBox")
+	}
+	xStep, err := r.ctx.DereferenceNumber(d["XStep"])
+	if err != nil {
+		return nil, err
+	}
+	yStep, err := r.ctx.DereferenceNumber(d["YStep"])
+	if err != nil {
+		return nil, err
+	}
+	xStep, yStep = math.Abs(xStep), math.Abs(yStep)
+	if xStep == 0 || yStep == 0 {
+		return nil, errors.New("pdfcpu: tiling pattern: corrupt step")
+	}
+
+	inv, ok := invert(m)
+	if !ok {
+		return nil, nil
+	}
+
+	// Size of a pattern cell in device pixels.
+	p0, px, py := transform(m, 0, 0), transform(m, xStep, 0), transform(m, 0, yStep)
+	tw := int(math.Ceil(math.Hypot(px.x-p0.x, px.y-p0.y)))
+	th := int(math.Ceil(math.Hypot(py.x-p0.x, py.y-p0.y)))
+	tw, th = max(1, min(tw, maxTileSize)), max(1, min(th, maxTileSize))
+
+	ts := &tileSource{
+		tile:  image.NewRGBA(image.Rect(0, 0, tw, th)),
+		inv:   inv,
+		bx:    math.Min(bbox[0], bbox[2]),
+		by:    math.Min(bbox[1], bbox[3]),
+		xStep: xStep,
+		yStep: yStep,
+		sx:    float64(tw) / xStep,
+		sy:    float64(th) / yStep,
+	}
+
+	content, err := r.streamContent(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	res := pp.res
+	if d, err := r.ctx.DereferenceDict(d["Resources"]); err == nil && d != nil {
+		res = d
+	}
+
+	var forced *color.NRGBA
+	if pt := d.IntEntry("PaintType"); pt != nil && *pt == 2 {
+		// Uncolored tiling pattern.
+		c := color.NRGBA{A: 255}
+		if pp.base != nil {
+			c = pp.base.rgb(pp.comps)
+		}
+		forced = &c
+	}
+
+	// Render the cell and its neighbours wrapped into the tile.
+	for i := -1; i <= 1; i++ {
+		for j := -1; j <= 1; j++ {
+			ctm := pdfMatrix(ts.sx, 0, 0, ts.sy, -ts.bx*ts.sx+float64(i*tw), -ts.by*ts.sy+float64(j*th))
+			sr := r.subRenderer(ts.tile, ctm, res)
+			sr.forcedColor = forced
+			p := &path{}
+			p.rect(ctm, bbox[0], bbox[1], bbox[2]-bbox[0], bbox[3]-bbox[1])
+			sr.gs.clip = coverageMask(p.polygons(), false, ts.tile.Rect)
+			if sr.gs.clip == nil {
+				continue
+			}
+			if err := sr.processContent(content); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return ts, nil
+}
+
+func (r *renderer) patternSource(pp *patternPaint) colorSource {
+	if pp.done {
+		return pp.src
+	}
+	pp.done = true
+
+	src, err := r.buildPatternSource(pp)
+	if err != nil {
+		if log.DebugEnabled() {
+			log.Debug.Printf("render: pattern: %v\n", err)
+		}
+		return nil
+	}
+	pp.src = src
+
+	return src
+}
+
+func (r *renderer) buildPatternSource(pp *patternPaint) (colorSource, error) {
+	o, err := r.ctx.Dereference(pp.obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		d  types.Dict
+		sd *types.StreamDict
+	)
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.StreamDict:
+		d, sd = o.Dict, &o
+	default:
+		return nil, errors.New("pdfcpu: invalid pattern")
+	}
+
+	m := matrix.IdentMatrix
+	if ff, err := numberArray(r.ctx.XRefTable, d["Matrix"]); err == nil && len(ff) == 6 {
+		m = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+	}
+	m = m.Multiply(pp.ctm)
+
+	pt := d.IntEntry("PatternType")
+	if pt == nil {
+		return nil, errors.New("pdfcpu: pattern: missing PatternType")
+	}
+
+	if *pt == 1 {
+		if sd == nil {
+			return nil, errors.New("pdfcpu: tiling pattern: missing stream")
+		}
+		return r.tilingPattern(pp, sd, m)
+	}
+
+	sh, err := r.shading(d["Shading"], pp.res)
+	if err != nil {
+		return nil, err
+	}
+	if sh.typ < 1 || sh.typ > 3 {
+		return nil, errors.Errorf("pdfcpu: unsupported shading type %d", sh.typ)
+	}
+
+	src, ok := newShadingSource(sh, m, true)
+	if !ok {
+		return nil, nil
+	}
+
+	return src, nil
+}
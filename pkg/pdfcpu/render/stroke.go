@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+)
+
+// Line cap styles, see 8.4.3.3.
+const (
+	capButt = iota
+	capRound
+	capSquare
+)
+
+// Line join styles, see 8.4.3.4.
+const (
+	joinMiter = iota
+	joinRound
+	joinBevel
+)
+
+type strokeStyle struct {
+	width      float64 // in user space
+	cap        int
+	join       int
+	miterLimit float64
+	dashes     []float64
+	dashPhase  float64
+}
+
+// stroker outlines polylines in user space as a set of positively oriented polygons
+// whose nonzero union is the stroke, see 8.5.3.2.
+type stroker struct {
+	style strokeStyle
+	hw    float64 // half line width
+	steps int     // segments used to approximate a full circle
+	polys [][]point
+}
+
+func area(pp []point) float64 {
+	var a float64
+	for i := range pp {
+		p, q := pp[i], pp[(i+1)%len(pp)]
+		a += p.x*q.y - q.x*p.y
+	}
+	return a
+}
+
+func (s *stroker) add(pp ...point) {
+	if area(pp) < 0 {
+		for i, j := 0, len(pp)-1; i < j; i, j = i+1, j-1 {
+			pp[i], pp[j] = pp[j], pp[i]
+		}
+	}
+	s.polys = append(s.polys, pp)
+}
+
+func (s *stroker) circle(c point) {
+	pp := make([]point, s.steps)
+	for i := range pp {
+		a := 2 * math.Pi * float64(i) / float64(s.steps)
+		pp[i] = point{c.x + s.hw*math.Cos(a), c.y + s.hw*math.Sin(a)}
+	}
+	s.add(pp...)
+}
+
+// normal returns the unit direction of p0->p1 and its normal scaled by the half line width.
+func (s *stroker) normal(p0, p1 point) (point, point) {
+	dx, dy := p1.x-p0.x, p1.y-p0.y
+	l := math.Hypot(dx, dy)
+	d := point{dx / l, dy / l}
+	return d, point{-d.y * s.hw, d.x * s.hw}
+}
+
+func (s *stroker) segment(p0, p1 point) {
+	_, n := s.normal(p0, p1)
+	s.add(
+		point{p0.x - n.x, p0.y - n.y},
+		point{p1.x - n.x, p1.y - n.y},
+		point{p1.x + n.x, p1.y + n.y},
+		point{p0.x + n.x, p0.y + n.y})
+}
+
+// joint joins the segments p0->v and v->p1 at v.
+func (s *stroker) joint(p0, v, p1 point) {
+	d0, n0 := s.normal(p0, v)
+	d1, n1 := s.normal(v, p1)
+
+	cross := d0.x*d1.y - d0.y*d1.x
+	if math.Abs(cross) < 1e-9 && d0.x*d1.x+d0.y*d1.y > 0 {
+		// Collinear.
+		return
+	}
+
+	if s.style.join == joinRound {
+		s.circle(v)
+		return
+	}
+
+	// The outer side is opposite to the turning direction.
+	sign := -1.
+	if cross < 0 {
+		sign = 1
+	}
+	a := point{v.x + sign*n0.x, v.y + sign*n0.y}
+	b := point{v.x + sign*n1.x, v.y + sign*n1.y}
+
+	if s.style.join == joinMiter {
+		cosTheta := -(d0.x*d1.x + d0.y*d1.y) // angle between the segments
+		sinHalf := math.Sqrt(math.Max(0, (1-cosTheta)/2))
+		if sinHalf > 0 && 1/sinHalf <= s.style.miterLimit {
+			// The miter tip lies on the bisector of the outer normals.
+			mx, my := a.x+b.x-2*v.x, a.y+b.y-2*v.y
+			l := math.Hypot(mx, my)
+			if l > 0 {
+				ml := s.hw / sinHalf
+				tip := point{v.x + mx/l*ml, v.y + my/l*ml}
+				s.add(v, a, tip, b)
+				return
+			}
+		}
+	}
+
+	s.add(v, a, b)
+}
+
+func (s *stroker) cap(p, dir point, start bool) {
+	switch s.style.cap {
+	case capRound:
+		s.circle(p)
+	case capSquare:
+		if start {
+			dir = point{-dir.x, -dir.y}
+		}
+		n := point{-dir.y * s.hw, dir.x * s.hw}
+		q := point{p.x + dir.x*s.hw, p.y + dir.y*s.hw}
+		s.add(
+			point{p.x - n.x, p.y - n.y},
+			point{q.x - n.x, q.y - n.y},
+			point{q.x + n.x, q.y + n.y},
+			point{p.x + n.x, p.y + n.y})
+	}
+}
+
+func (s *stroker) dot(p point) {
+	switch s.style.cap {
+	case capRound:
+		s.circle(p)
+	case capSquare:
+		s.add(
+			point{p.x - s.hw, p.y - s.hw},
+			point{p.x + s.hw, p.y - s.hw},
+			point{p.x + s.hw, p.y + s.hw},
+			point{p.x - s.hw, p.y + s.hw})
+	}
+}
+
+func dedupe(pp []point) []point {
+	const eps = 1e-9
+	res := make([]point, 0, len(pp))
+	for _, p := range pp {
+		if len(res) > 0 {
+			q := res[len(res)-1]
+			if math.Abs(p.x-q.x) < eps && math.Abs(p.y-q.y) < eps {
+				continue
+			}
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+func (s *stroker) polyline(pp []point, closed bool) {
+	pp = dedupe(pp)
+	if closed && len(pp) > 1 && pp[0] == pp[len(pp)-1] {
+		pp = pp[:len(pp)-1]
+	}
+
+	if len(pp) == 0 {
+		return
+	}
+
+	if len(pp) == 1 {
+		s.dot(pp[0])
+		return
+	}
+
+	n := len(pp)
+	for i := 0; i < n-1; i++ {
+		s.segment(pp[i], pp[i+1])
+		if i > 0 {
+			s.joint(pp[i-1], pp[i], pp[i+1])
+		}
+	}
+
+	if closed && n > 2 {
+		s.segment(pp[n-1], pp[0])
+		s.joint(pp[n-2], pp[n-1], pp[0])
+		s.joint(pp[n-1], pp[0], pp[1])
+		return
+	}
+
+	d0, _ := s.normal(pp[0], pp[1])
+	d1, _ := s.normal(pp[n-2], pp[n-1])
+	s.cap(pp[0], d0, true)
+	s.cap(pp[n-1], d1, false)
+}
+
+// dash splits a polyline into its dashes, see 8.4.3.6.
+func (s *stroker) dash(pp []point, closed bool) [][]point {
+	if len(pp) == 0 {
+		return nil
+	}
+	if closed {
+		pp = append(pp, pp[0])
+	}
+
+	dd := s.style.dashes
+	var total float64
+	for _, d := range dd {
+		total += d
+	}
+
+	// Locate the start of the pattern according to the dash phase.
+	i, on := 0, true
+	rem := dd[0]
+	phase := math.Mod(s.style.dashPhase, total)
+	if phase < 0 {
+		phase += total
+	}
+	for phase > 0 {
+		if phase < rem {
+			rem -= phase
+			break
+		}
+		phase -= rem
+		i, on = (i+1)%len(dd), !on
+		rem = dd[i]
+	}
+
+	var (
+		res [][]point
+		cur []point
+	)
+	if on {
+		cur = []point{pp[0]}
+	}
+
+	for k := 0; k < len(pp)-1; k++ {
+		p0, p1 := pp[k], pp[k+1]
+		l := math.Hypot(p1.x-p0.x, p1.y-p0.y)
+		pos := 0.
+		for l-pos > rem {
+			pos += rem
+			t := pos / l
+			q := point{p0.x + (p1.x-p0.x)*t, p0.y + (p1.y-p0.y)*t}
+			if on {
+				res = append(res, append(cur, q))
+				cur = nil
+			} else {
+				cur = []point{q}
+			}
+			i, on = (i+1)%len(dd), !on
+			rem = dd[i]
+			if len(res) > 100000 {
+				// Guard against pathological dash patterns.
+				return res
+			}
+		}
+		rem -= l - pos
+		if on {
+			cur = append(cur, p1)
+		}
+	}
+
+	if on && len(cur) > 0 {
+		res = append(res, cur)
+	}
+
+	return res
+}
+
+func (s *stroker) dashed() bool {
+	var total float64
+	for _, d := range s.style.dashes {
+		if d < 0 {
+			return false
+		}
+		total += d
+	}
+	return total > 0
+}
+
+// strokePolygons returns the polygons in device space covering the stroke of p.
+func strokePolygons(p *path, style strokeStyle, ctm matrix.Matrix) [][]point {
+	inv, ok := invert(ctm)
+	if !ok {
+		return nil
+	}
+
+	sf := scaleFactor(ctm)
+	w := style.width
+	if w*sf < 1 {
+		// Thinnest line that can be rendered, see 8.4.3.2.
+		w = 1 / sf
+	}
+
+	steps := int(math.Ceil(math.Pi * w * sf / 2))
+	steps = max(8, min(steps, 128))
+
+	s := &stroker{style: style, hw: w / 2, steps: steps}
+
+	for _, sp := range p.subpaths {
+		pp := make([]point, len(sp.pts))
+		for i, q := range sp.pts {
+			pp[i] = transform(inv, q.x, q.y)
+		}
+		if len(style.dashes) > 0 && s.dashed() {
+			for _, d := range s.dash(dedupe(pp), sp.closed) {
+				s.polyline(d, false)
+			}
+			continue
+		}
+		s.polyline(pp, sp.closed)
+	}
+
+	for _, pp := range s.polys {
+		for i, q := range pp {
+			pp[i] = transform(ctm, q.x, q.y)
+		}
+	}
+
+	return s.polys
+}
@@ -0,0 +1,814 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/matrix"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Text rendering modes, see 9.3.6.
+const (
+	trFill = iota
+	trStroke
+	trFillStroke
+	trInvisible
+	trFillClip
+	trStrokeClip
+	trFillStrokeClip
+	trClip
+)
+
+// fontFlagSymbolic is bit 3 of the font descriptor flags, see 9.8.2.
+const fontFlagSymbolic = 1 << 2
+
+type textState struct {
+	charSpace float64
+	wordSpace float64
+	hScale    float64
+	leading   float64
+	font      *renderFont
+	fontSize  float64
+	rise      float64
+	mode      int
+}
+
+// renderFont provides glyph outlines for a font dict, see 9.
+type renderFont struct {
+	dec         *pdffont.Decoder
+	fontMatrix  matrix.Matrix // glyph space to text space
+	tt          *pdffont.TrueType
+	cff         *pdffont.CFF
+	t1          *pdffont.Type1
+	substitute  bool // tt is a substitute for a font program not embedded
+	symbolic    bool
+	hasEncoding bool
+	cidToGID    []byte // CIDFontType2 only, nil for Identity
+	charProcs   types.Dict
+	resources   types.Dict // Type3 only
+	glyphs      map[uint32][]pdffont.Segment
+}
+
+// substituteFontProgram returns an installed user font or a Go font similar to the font named name.
+func substituteFontProgram(name string) (string, []byte) {
+	if font.IsUserFont(name) {
+		if bb, err := font.Read(name); err == nil {
+			return name, bb
+		}
+	}
+
+	s := strings.ToLower(name)
+	bold := strings.Contains(s, "bold") || strings.Contains(s, "black") || strings.Contains(s, "heavy")
+	italic := strings.Contains(s, "italic") || strings.Contains(s, "oblique")
+
+	switch {
+	case strings.Contains(s, "courier") || strings.Contains(s, "mono"):
+		return "go-mono", gomono.TTF
+	case bold && italic:
+		return "go-bolditalic", gobolditalic.TTF
+	case bold:
+		return "go-bold", gobold.TTF
+	case italic:
+		return "go-italic", goitalic.TTF
+	}
+
+	return "go-regular", goregular.TTF
+}
+
+func (r *renderer) substituteFont(name string) (*pdffont.TrueType, error) {
+	key, bb := substituteFontProgram(name)
+	if tt, ok := r.substitutes[key]; ok {
+		return tt, nil
+	}
+	tt, err := pdffont.ParseTrueType(bb)
+	if err != nil {
+		return nil, err
+	}
+	r.substitutes[key] = tt
+	return tt, nil
+}
+
+func (r *renderer) fontDescriptor(d types.Dict) types.Dict {
+	fd, err := r.ctx.DereferenceDict(d["FontDescriptor"])
+	if err != nil {
+		return nil
+	}
+	return fd
+}
+
+func (r *renderer) loadFontProgram(rf *renderFont, fd types.Dict) {
+	if fd == nil {
+		return
+	}
+
+	if f := fd.IntEntry("Flags"); f != nil {
+		rf.symbolic = *f&fontFlagSymbolic != 0
+	}
+
+	for _, k := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		o, found := fd.Find(k)
+		if !found {
+			continue
+		}
+		sd, _, err := r.ctx.DereferenceStreamDict(o)
+		if err != nil || sd == nil {
+			continue
+		}
+		if err := sd.Decode(); err != nil {
+			continue
+		}
+		bb := sd.Content
+
+		switch k {
+		case "FontFile":
+			rf.t1, err = pdffont.ParseType1(bb)
+		case "FontFile2":
+			rf.tt, err = pdffont.ParseTrueType(bb)
+		default:
+			st := sd.Subtype()
+			if st != nil && *st == "OpenType" {
+				rf.tt, err = pdffont.ParseTrueType(bb)
+			} else {
+				rf.cff, err = pdffont.ParseCFF(bb)
+			}
+		}
+
+		if err != nil {
+			if log.DebugEnabled() {
+				log.Debug.Printf("render: font %s: %v\n", rf.dec.Name, err)
+			}
+			rf.t1, rf.tt, rf.cff = nil, nil, nil
+			continue
+		}
+
+		if rf.tt != nil && rf.tt.CFF != nil {
+			rf.cff = rf.tt.CFF
+		}
+		return
+	}
+}
+
+func (r *renderer) initFontMatrix(rf *renderFont) {
+	rf.fontMatrix = pdfMatrix(0.001, 0, 0, 0.001, 0, 0)
+	switch {
+	case rf.cff != nil:
+		m := rf.cff.FontMatrix
+		rf.fontMatrix = pdfMatrix(m[0], m[1], m[2], m[3], m[4], m[5])
+	case rf.t1 != nil:
+		m := rf.t1.FontMatrix
+		rf.fontMatrix = pdfMatrix(m[0], m[1], m[2], m[3], m[4], m[5])
+	case rf.tt != nil && rf.tt.UnitsPerEm > 0:
+		s := 1 / float64(rf.tt.UnitsPerEm)
+		rf.fontMatrix = pdfMatrix(s, 0, 0, s, 0, 0)
+	}
+}
+
+func (r *renderer) newRenderFont(d types.Dict) (*renderFont, error) {
+	dec, err := pdffont.NewDecoder(r.ctx.XRefTable, d)
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &renderFont{dec: dec, glyphs: map[uint32][]pdffont.Segment{}}
+	_, rf.hasEncoding = d.Find("Encoding")
+
+	if dec.Subtype == "Type3" {
+		rf.fontMatrix = pdfMatrix(0.001, 0, 0, 0.001, 0, 0)
+		if ff, err := numberArray(r.ctx.XRefTable, d["FontMatrix"]); err == nil && len(ff) == 6 {
+			rf.fontMatrix = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+		}
+		if rf.charProcs, err = r.ctx.DereferenceDict(d["CharProcs"]); err != nil {
+			return nil, err
+		}
+		if rf.resources, err = r.ctx.DereferenceDict(d["Resources"]); err != nil {
+			return nil, err
+		}
+		return rf, nil
+	}
+
+	fd := r.fontDescriptor(d)
+
+	if dec.Subtype == "Type0" {
+		if a, err := r.ctx.DereferenceArray(d["DescendantFonts"]); err == nil && len(a) == 1 {
+			if df, err := r.ctx.DereferenceDict(a[0]); err == nil && df != nil {
+				fd = r.fontDescriptor(df)
+				if err := r.parseCIDToGIDMap(rf, df); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	r.loadFontProgram(rf, fd)
+
+	if rf.tt == nil && rf.cff == nil && rf.t1 == nil {
+		// Substitute fonts that are not embedded.
+		if rf.tt, err = r.substituteFont(dec.Name); err != nil {
+			return nil, err
+		}
+		rf.substitute = true
+	}
+
+	r.initFontMatrix(rf)
+
+	return rf, nil
+}
+
+func (r *renderer) parseCIDToGIDMap(rf *renderFont, df types.Dict) error {
+	o, found := df.Find("CIDToGIDMap")
+	if !found {
+		return nil
+	}
+	o, err := r.ctx.Dereference(o)
+	if err != nil {
+		return err
+	}
+	sd, ok := o.(types.StreamDict)
+	if !ok {
+		// Identity
+		return nil
+	}
+	if err := sd.Decode(); err != nil {
+		return err
+	}
+	rf.cidToGID = sd.Content
+	return nil
+}
+
+// fontResource returns the font named name of the current resources.
+func (r *renderer) fontResource(name string) (*renderFont, error) {
+	d, err := r.ctx.DereferenceDict(r.gs.resources["Font"])
+	if err != nil || d == nil {
+		return nil, err
+	}
+
+	o, found := d.Find(name)
+	if !found {
+		return nil, nil
+	}
+
+	objNr := -1
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr = indRef.ObjectNumber.Value()
+		if rf, ok := r.fonts[objNr]; ok {
+			return rf, nil
+		}
+	}
+
+	fd, err := r.ctx.DereferenceDict(o)
+	if err != nil || fd == nil {
+		return nil, err
+	}
+
+	rf, err := r.newRenderFont(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	if objNr >= 0 {
+		r.fonts[objNr] = rf
+	}
+
+	return rf, nil
+}
+
+func (r *renderer) setFont(operands []types.Object) error {
+	ff, ok := numbers(operands, 1)
+	if !ok || len(operands) < 2 {
+		return nil
+	}
+	n, ok := operands[len(operands)-2].(types.Name)
+	if !ok {
+		return nil
+	}
+	rf, err := r.fontResource(n.Value())
+	if err != nil {
+		if log.DebugEnabled() {
+			log.Debug.Printf("render: font %s: %v\n", n.Value(), err)
+		}
+		rf = nil
+	}
+	r.gs.text.font = rf
+	r.gs.text.fontSize = ff[0]
+	return nil
+}
+
+func firstRune(s string) (rune, bool) {
+	if s == "" {
+		return 0, false
+	}
+	c, _ := utf8.DecodeRuneInString(s)
+	return c, c != utf8.RuneError
+}
+
+// unicodeGlyph maps a glyph to a glyph index via the (3,1) cmap of a font program.
+func (rf *renderFont) unicodeGlyph(g pdffont.Glyph) (uint16, bool) {
+	var s string
+	if g.Name != "" {
+		s, _ = pdffont.GlyphNameToString(g.Name)
+	}
+	if s == "" {
+		s = g.Text
+	}
+	c, ok := firstRune(s)
+	if !ok {
+		return 0, false
+	}
+	if gid, ok := rf.tt.GlyphIndex(3, 1, uint32(c)); ok {
+		return gid, true
+	}
+	return rf.tt.GlyphIndex(3, 10, uint32(c))
+}
+
+// symbolicTrueTypeGlyph maps a character code to a glyph index according to 9.6.6.4.
+func (rf *renderFont) symbolicTrueTypeGlyph(c uint32) (uint16, bool) {
+	if rf.tt.HasCmap(3, 0) {
+		for _, off := range []uint32{0, 0xF000, 0xF100, 0xF200} {
+			if gid, ok := rf.tt.GlyphIndex(3, 0, off+c); ok {
+				return gid, true
+			}
+		}
+	}
+	if rf.tt.HasCmap(1, 0) {
+		if gid, ok := rf.tt.GlyphIndex(1, 0, c); ok {
+			return gid, true
+		}
+	}
+	return 0, false
+}
+
+// simpleTrueTypeGlyph selects the glyph of a simple TrueType font, see 9.6.6.4.
+func (rf *renderFont) simpleTrueTypeGlyph(g pdffont.Glyph) uint16 {
+	if rf.substitute {
+		gid, _ := rf.unicodeGlyph(g)
+		return gid
+	}
+
+	if !rf.tt.HasCmaps() {
+		return uint16(g.Code)
+	}
+
+	if rf.symbolic || !rf.hasEncoding {
+		if gid, ok := rf.symbolicTrueTypeGlyph(g.Code); ok {
+			return gid
+		}
+	}
+
+	if rf.tt.HasCmap(3, 1) {
+		if gid, ok := rf.unicodeGlyph(g); ok {
+			return gid
+		}
+	}
+
+	if rf.tt.HasCmap(1, 0) && g.Name != "" {
+		if s, ok := pdffont.GlyphNameToString(g.Name); ok {
+			if c, ok := firstRune(s); ok {
+				if b, ok := charmap.Macintosh.EncodeRune(c); ok {
+					if gid, ok := rf.tt.GlyphIndex(1, 0, uint32(b)); ok {
+						return gid
+					}
+				}
+			}
+		}
+	}
+
+	if g.Name != "" {
+		if gid, ok := rf.tt.GlyphIndexForName(g.Name); ok {
+			return gid
+		}
+	}
+
+	if gid, ok := rf.symbolicTrueTypeGlyph(g.Code); ok {
+		return gid
+	}
+
+	return 0
+}
+
+func (rf *renderFont) cidGlyph(cid uint32) uint16 {
+	if rf.substitute {
+		return 0
+	}
+	if rf.cidToGID != nil {
+		i := 2 * int(cid)
+		if i+1 < len(rf.cidToGID) {
+			return uint16(rf.cidToGID[i])<<8 | uint16(rf.cidToGID[i+1])
+		}
+		return 0
+	}
+	return uint16(cid)
+}
+
+func (rf *renderFont) type1Outline(g pdffont.Glyph) ([]pdffont.Segment, error) {
+	name := g.Name
+	if rf.t1.Encoding != nil && (!rf.hasEncoding || !rf.t1.HasGlyph(name)) {
+		if n := rf.t1.Encoding[g.Code&0xFF]; n != "" && n != ".notdef" {
+			name = n
+		}
+	}
+	if !rf.t1.HasGlyph(name) {
+		return nil, nil
+	}
+	return rf.t1.Outline(name)
+}
+
+func (rf *renderFont) cffOutline(g pdffont.Glyph) ([]pdffont.Segment, error) {
+	if rf.dec.Subtype == "Type0" {
+		if rf.cff.IsCID {
+			gid, ok := rf.cff.GlyphIndexForCID(g.CID)
+			if !ok {
+				return nil, nil
+			}
+			return rf.cff.Outline(gid)
+		}
+		return rf.cff.Outline(uint16(g.CID))
+	}
+
+	gid, ok := uint16(0), false
+	if rf.hasEncoding && g.Name != "" {
+		gid, ok = rf.cff.GlyphIndexForName(g.Name)
+	}
+	if !ok {
+		gid, ok = rf.cff.GlyphIndexForCode(byte(g.Code))
+	}
+	if !ok && g.Name != "" {
+		gid, ok = rf.cff.GlyphIndexForName(g.Name)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return rf.cff.Outline(gid)
+}
+
+// outline returns the outline of g in glyph space.
+func (rf *renderFont) outline(g pdffont.Glyph) []pdffont.Segment {
+	key := g.Code
+	if segs, ok := rf.glyphs[key]; ok {
+		return segs
+	}
+
+	var (
+		segs []pdffont.Segment
+		err  error
+	)
+
+	switch {
+	case rf.t1 != nil:
+		segs, err = rf.type1Outline(g)
+	case rf.cff != nil:
+		segs, err = rf.cffOutline(g)
+	case rf.tt != nil:
+		var gid uint16
+		if rf.dec.Subtype == "Type0" {
+			gid = rf.cidGlyph(g.CID)
+			if rf.substitute {
+				gid, _ = rf.unicodeGlyph(g)
+			}
+		} else {
+			gid = rf.simpleTrueTypeGlyph(g)
+		}
+		segs, err = rf.tt.Outline(gid)
+	}
+
+	if err != nil {
+		if log.DebugEnabled() {
+			log.Debug.Printf("render: font %s: glyph %d: %v\n", rf.dec.Name, g.Code, err)
+		}
+		segs = nil
+	}
+
+	rf.glyphs[key] = segs
+
+	return segs
+}
+
+// showGlyphs paints the glyphs of a string according to 9.4.4.
+func (r *renderer) showGlyphs(bb []byte) error {
+	ts := &r.gs.text
+	rf := ts.font
+	if rf == nil {
+		return nil
+	}
+	dec := rf.dec
+
+	tfs, th := ts.fontSize, ts.hScale
+
+	for _, g := range dec.Decode(bb) {
+		trm := pdfMatrix(tfs*th, 0, 0, tfs, 0, ts.rise).Multiply(r.tm).Multiply(r.gs.ctm)
+
+		if dec.Vertical {
+			// Glyph origins of vertical fonts are at the top center of the glyph.
+			trm = pdfMatrix(1, 0, 0, 1, -g.Width/2, -0.88).Multiply(trm)
+		}
+
+		if !r.hidden() {
+			if err := r.paintGlyph(rf, g, trm); err != nil {
+				return err
+			}
+		}
+
+		wordSep := g.Len == 1 && g.Code == 32
+
+		var tx, ty float64
+		if dec.Vertical {
+			ty = -tfs + ts.charSpace
+			if wordSep {
+				ty += ts.wordSpace
+			}
+		} else {
+			tx = g.Width*tfs + ts.charSpace
+			if wordSep {
+				tx += ts.wordSpace
+			}
+			tx *= th
+		}
+
+		r.tm = pdfMatrix(1, 0, 0, 1, tx, ty).Multiply(r.tm)
+	}
+
+	return nil
+}
+
+func (r *renderer) paintGlyph(rf *renderFont, g pdffont.Glyph, trm matrix.Matrix) error {
+	if rf.charProcs != nil {
+		return r.paintType3Glyph(rf, g, trm)
+	}
+
+	mode := r.gs.text.mode
+	if mode == trInvisible {
+		return nil
+	}
+
+	segs := rf.outline(g)
+	if len(segs) == 0 {
+		return nil
+	}
+
+	p := outlinePath(segs, rf.fontMatrix.Multiply(trm))
+
+	switch mode {
+	case trFill, trFillClip:
+		r.fillPath(p, false)
+	case trStroke, trStrokeClip:
+		r.strokePath(p)
+	case trFillStroke, trFillStrokeClip:
+		r.fillPath(p, false)
+		r.strokePath(p)
+	}
+
+	if mode >= trFillClip {
+		r.textClip = append(r.textClip, p.polygons()...)
+	}
+
+	return nil
+}
+
+func (r *renderer) paintType3Glyph(rf *renderFont, g pdffont.Glyph, trm matrix.Matrix) error {
+	if r.gs.text.mode == trInvisible || r.gs.text.mode == trClip {
+		return nil
+	}
+
+	o, found := rf.charProcs.Find(g.Name)
+	if !found {
+		return nil
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok {
+		return nil
+	}
+	objNr := indRef.ObjectNumber.Value()
+	if r.forms[objNr] || len(r.forms) > maxFormNesting {
+		return nil
+	}
+
+	sd, _, err := r.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return err
+	}
+
+	content, err := r.streamContent(sd)
+	if err != nil {
+		return err
+	}
+
+	r.forms[objNr] = true
+	defer delete(r.forms, objNr)
+
+	res := rf.resources
+	if res == nil {
+		res = r.gs.resources
+	}
+
+	r.saveGraphicsState()
+	defer r.restoreGraphicsState()
+
+	tm, tlm, forced := r.tm, r.tlm, r.forcedColor
+	defer func() { r.tm, r.tlm, r.forcedColor = tm, tlm, forced }()
+
+	r.gs.ctm = rf.fontMatrix.Multiply(trm)
+	r.gs.resources = res
+	r.inType3 = true
+	defer func() { r.inType3 = false }()
+
+	return r.processContent(content)
+}
+
+func (r *renderer) beginText() {
+	r.tm, r.tlm = matrix.IdentMatrix, matrix.IdentMatrix
+	r.textClip = nil
+}
+
+func (r *renderer) endText() {
+	if r.gs.text.mode >= trFillClip && len(r.textClip) > 0 {
+		r.gs.clip = intersectMasks(r.gs.clip, coverageMask(r.textClip, false, r.dst.Rect))
+		if r.gs.clip == nil {
+			r.gs.clip = emptyMask()
+		}
+	}
+	r.textClip = nil
+}
+
+func (r *renderer) showText(o types.Object) error {
+	bb, err := types.StringBytes(o)
+	if err != nil {
+		return nil
+	}
+	return r.showGlyphs(bb)
+}
+
+func (r *renderer) showTextArray(operands []types.Object) error {
+	if len(operands) == 0 {
+		return nil
+	}
+	a, ok := operands[len(operands)-1].(types.Array)
+	if !ok {
+		return nil
+	}
+	for _, o := range a {
+		switch o := o.(type) {
+		case types.Integer:
+			r.adjust(float64(o.Value()))
+		case types.Float:
+			r.adjust(o.Value())
+		default:
+			if err := r.showText(o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// adjust applies a TJ position adjustment expressed in thousandths of a unit of text space.
+func (r *renderer) adjust(f float64) {
+	ts := r.gs.text
+	d := -f / 1000 * ts.fontSize
+	if ts.font != nil && ts.font.dec.Vertical {
+		r.tm = pdfMatrix(1, 0, 0, 1, 0, d).Multiply(r.tm)
+		return
+	}
+	r.tm = pdfMatrix(1, 0, 0, 1, d*ts.hScale, 0).Multiply(r.tm)
+}
+
+func (r *renderer) moveTextPosition(tx, ty float64) {
+	r.tlm = pdfMatrix(1, 0, 0, 1, tx, ty).Multiply(r.tlm)
+	r.tm = r.tlm
+}
+
+func (r *renderer) nextLine() {
+	r.moveTextPosition(0, -r.gs.text.leading)
+}
+
+func (r *renderer) processTextStateOperator(op model.Operator, operands []types.Object) (bool, error) {
+	ts := &r.gs.text
+
+	switch op {
+
+	case model.OpSetCharSpacing:
+		if ff, ok := numbers(operands, 1); ok {
+			ts.charSpace = ff[0]
+		}
+
+	case model.OpSetWordSpacing:
+		if ff, ok := numbers(operands, 1); ok {
+			ts.wordSpace = ff[0]
+		}
+
+	case model.OpSetHorizScaling:
+		if ff, ok := numbers(operands, 1); ok {
+			ts.hScale = ff[0] / 100
+		}
+
+	case model.OpSetLeading:
+		if ff, ok := numbers(operands, 1); ok {
+			ts.leading = ff[0]
+		}
+
+	case model.OpSetTextRise:
+		if ff, ok := numbers(operands, 1); ok {
+			ts.rise = ff[0]
+		}
+
+	case model.OpSetTextRender:
+		if ff, ok := numbers(operands, 1); ok && ff[0] >= 0 && ff[0] <= 7 {
+			ts.mode = int(ff[0])
+		}
+
+	case model.OpSetFont:
+		return true, r.setFont(operands)
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *renderer) processTextOperator(op model.Operator, operands []types.Object) (bool, error) {
+	switch op {
+
+	case model.OpBeginText:
+		r.beginText()
+
+	case model.OpEndText:
+		r.endText()
+
+	case model.OpMoveText:
+		if ff, ok := numbers(operands, 2); ok {
+			r.moveTextPosition(ff[0], ff[1])
+		}
+
+	case model.OpMoveTextSetLeading:
+		if ff, ok := numbers(operands, 2); ok {
+			r.gs.text.leading = -ff[1]
+			r.moveTextPosition(ff[0], ff[1])
+		}
+
+	case model.OpSetTextMatrix:
+		if ff, ok := numbers(operands, 6); ok {
+			r.tlm = pdfMatrix(ff[0], ff[1], ff[2], ff[3], ff[4], ff[5])
+			r.tm = r.tlm
+		}
+
+	case model.OpNextLine:
+		r.nextLine()
+
+	case model.OpShowText:
+		if len(operands) > 0 {
+			return true, r.showText(operands[len(operands)-1])
+		}
+
+	case model.OpShowTextArray:
+		return true, r.showTextArray(operands)
+
+	case model.OpMoveShowText:
+		r.nextLine()
+		if len(operands) > 0 {
+			return true, r.showText(operands[len(operands)-1])
+		}
+
+	case model.OpMoveSetShowText:
+		if len(operands) == 3 {
+			if ff, ok := numbers(operands[:2], 2); ok {
+				r.gs.text.wordSpace, r.gs.text.charSpace = ff[0], ff[1]
+			}
+		}
+		r.nextLine()
+		if len(operands) > 0 {
+			return true, r.showText(operands[len(operands)-1])
+		}
+
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
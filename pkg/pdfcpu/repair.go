@@ -0,0 +1,596 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/log"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	scanChunkSize = 1024 * 1024
+	scanOverlap   = 64
+)
+
+// Matches "objNr genNr obj" headers of indirect objects and trailer keywords.
+var scanMarkerRegexp = regexp.MustCompile(`(\d{1,10})[\x00\t\n\f\r ]+(\d{1,5})[\x00\t\n\f\r ]+obj|trailer`)
+
+// scanMarker is an indirect object header or a trailer keyword found by scanning the file.
+type scanMarker struct {
+	offset  int64
+	objNr   int
+	genNr   int
+	trailer bool
+}
+
+func isRegularChar(b byte) bool {
+	return !bytes.ContainsRune([]byte("\x00\t\n\f\r ()<>[]{}/%"), rune(b))
+}
+
+// scanMarkers scans the whole file for indirect object headers and trailer keywords.
+func scanMarkers(c context.Context, ctx *model.Context) ([]scanMarker, error) {
+	var (
+		mm    []scanMarker
+		buf   []byte
+		base  int64
+		chunk = make([]byte, scanChunkSize)
+	)
+
+	rs := ctx.Read.RS
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	for eof := false; !eof; {
+
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := fillBuffer(rs, chunk)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		eof = n < len(chunk)
+		buf = append(buf, chunk[:n]...)
+
+		limit := len(buf)
+		if !eof {
+			limit -= scanOverlap
+		}
+
+		for _, m := range scanMarkerRegexp.FindAllSubmatchIndex(buf, -1) {
+			if m[0] >= limit {
+				break
+			}
+			if base > 0 && m[0] == 0 {
+				// Already processed as part of the preceding chunk.
+				continue
+			}
+			if m[1] < len(buf) && isRegularChar(buf[m[1]]) {
+				// eg. "objects" or "trailers"
+				continue
+			}
+			sm := scanMarker{offset: base + int64(m[0]), trailer: m[2] < 0}
+			if !sm.trailer {
+				sm.objNr, _ = strconv.Atoi(string(buf[m[2]:m[3]]))
+				sm.genNr, _ = strconv.Atoi(string(buf[m[4]:m[5]]))
+			}
+			mm = append(mm, sm)
+		}
+
+		if eof {
+			break
+		}
+
+		// Keep the tail for markers spanning chunk boundaries.
+		k := limit - 1
+		buf = append(buf[:0], buf[k:]...)
+		base += int64(k)
+	}
+
+	return mm, nil
+}
+
+// offsetOfEndstream returns the offset of the first "endstream" at or behind offset.
+func offsetOfEndstream(ctx *model.Context, offset int64) (int64, bool, error) {
+	rd, err := newPositionedReader(ctx.Read.RS, &offset)
+	if err != nil {
+		return 0, false, err
+	}
+
+	marker := []byte("endstream")
+	chunk := make([]byte, defaultBufSize)
+	var buf []byte
+
+	for {
+		n, err := fillBuffer(rd, chunk)
+		if err != nil && err != io.EOF {
+			return 0, false, err
+		}
+		buf = append(buf, chunk[:n]...)
+
+		if i := bytes.Index(buf, marker); i >= 0 {
+			return offset + int64(i), true, nil
+		}
+
+		if n < len(chunk) {
+			return 0, false, nil
+		}
+
+		// Keep the tail for a marker spanning chunk boundaries.
+		k := len(buf) - len(marker) + 1
+		buf = append(buf[:0], buf[k:]...)
+		offset += int64(k)
+	}
+}
+
+// streamEnd returns the offset behind the stream data of sd.
+func streamEnd(ctx *model.Context, sd types.StreamDict) (int64, bool, error) {
+	if sd.StreamLength != nil {
+		off := sd.StreamOffset + *sd.StreamLength
+		ok, err := endstreamAt(ctx, off)
+		if err != nil || ok {
+			return off, ok, err
+		}
+	}
+
+	return offsetOfEndstream(ctx, sd.StreamOffset)
+}
+
+// streamLengthObject returns the value of the indirect stream length objNr or nil if objNr is no integer.
+// Unlike int64Object this does not cache objects which may turn out to be streams.
+func streamLengthObject(c context.Context, ctx *model.Context, objNr int) *int64 {
+	entry, ok := ctx.Find(objNr)
+	if !ok || entry.Free {
+		return nil
+	}
+
+	if entry.Compressed || entry.Object != nil {
+		// Objects within object streams are never streams.
+		l, err := int64Object(c, ctx, objNr)
+		if err != nil {
+			return nil
+		}
+		return l
+	}
+
+	if entry.Offset == nil || entry.Generation == nil {
+		return nil
+	}
+
+	o, err := ParseObjectWithContext(c, ctx, *entry.Offset, objNr, *entry.Generation)
+	if err != nil {
+		return nil
+	}
+
+	i, ok := o.(types.Integer)
+	if !ok {
+		return nil
+	}
+
+	l := int64(i.Value())
+	return &l
+}
+
+// loadRepairedStreamContent loads sd's encoded stream content.
+// Stream content gets read up to "endstream" if sd's length is unresolvable or does not fit.
+func loadRepairedStreamContent(c context.Context, ctx *model.Context, sd *types.StreamDict, objNr int) error {
+	if sd.Raw != nil {
+		return nil
+	}
+
+	if sd.StreamLength == nil && sd.StreamLengthObjNr != nil {
+		sd.StreamLength = streamLengthObject(c, ctx, *sd.StreamLengthObjNr)
+	}
+
+	if sd.StreamLength != nil {
+		ok, err := endstreamAt(ctx, sd.StreamOffset+*sd.StreamLength)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return loadEncodedStreamContent(c, ctx, sd, false)
+		}
+	}
+
+	off, ok, err := offsetOfEndstream(ctx, sd.StreamOffset)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("pdfcpu: repair: missing endstream for obj#%d", objNr)
+	}
+
+	// Stream data is followed by an EOL marker.
+	l, err := eolTrimmedLength(ctx, sd.StreamOffset, off)
+	if err != nil {
+		return err
+	}
+	sd.StreamLength = &l
+	sd.StreamLengthObjNr = nil
+	sd.Dict["Length"] = types.Integer(l)
+
+	if err := loadEncodedStreamContent(c, ctx, sd, false); err != nil {
+		return err
+	}
+
+	model.ShowRepaired(fmt.Sprintf("obj#%d stream length", objNr))
+
+	return nil
+}
+
+// eolTrimmedLength returns the length of the stream data between from and the EOL marker in front of to.
+func eolTrimmedLength(ctx *model.Context, from, to int64) (int64, error) {
+	if to-from < 2 {
+		return max(to-from, 0), nil
+	}
+
+	off := to - 2
+	rd, err := newPositionedReader(ctx.Read.RS, &off)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(rd, buf); err != nil {
+		return 0, err
+	}
+
+	l := to - from
+	switch {
+	case buf[0] == '\r' && buf[1] == '\n':
+		l -= 2
+	case buf[1] == '\n' || buf[1] == '\r':
+		l--
+	}
+
+	return l, nil
+}
+
+// trailerDictAt parses the trailer dict following the trailer keyword at offset.
+func trailerDictAt(c context.Context, ctx *model.Context, offset int64) (types.Dict, error) {
+	offset += int64(len("trailer"))
+
+	rd, err := newPositionedReader(ctx.Read.RS, &offset)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4*defaultBufSize)
+	n, err := fillBuffer(rd, buf)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	s := string(buf[:n])
+	o, err := model.ParseObjectContext(c, &s)
+	if err != nil {
+		return nil, err
+	}
+
+	d, ok := o.(types.Dict)
+	if !ok {
+		return nil, errors.New("pdfcpu: trailerDictAt: corrupt trailer dict")
+	}
+
+	return d, nil
+}
+
+// catalogCandidate is a document catalog found while scanning.
+type catalogCandidate struct {
+	objNr int
+	entry *model.XRefTableEntry // Gets replaced if the object is redefined.
+}
+
+func isCatalog(o types.Object) bool {
+	d, ok := o.(types.Dict)
+	return ok && d.Type() != nil && *d.Type() == "Catalog"
+}
+
+// objectStreamObjects registers compressed xRefTable entries for all objects of the object stream objNr.
+// It returns any catalogs found.
+func objectStreamObjects(c context.Context, ctx *model.Context, sd types.StreamDict, objNr int) ([]catalogCandidate, error) {
+	if err := loadRepairedStreamContent(c, ctx, &sd, objNr); err != nil {
+		return nil, err
+	}
+
+	osd, err := decodeObjectStreamObjects(c, &sd, objNr)
+	if err != nil {
+		return nil, err
+	}
+
+	prolog, err := osd.DecodeLength(int64(osd.FirstObjOffset))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(string(bytes.ReplaceAll(prolog, []byte{0x00}, []byte{0x20})))
+
+	var catalogs []catalogCandidate
+
+	for i := 0; i+1 < len(fields) && i/2 < len(osd.ObjArray); i += 2 {
+		objNr1, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		if objNr1 == objNr {
+			continue
+		}
+		delete(ctx.Read.ObjectStreams, objNr1)
+
+		osNr, ind := objNr, i/2
+		entry := &model.XRefTableEntry{
+			Compressed:      true,
+			ObjectStream:    &osNr,
+			ObjectStreamInd: &ind,
+		}
+		ctx.Table[objNr1] = entry
+
+		if l, ok := osd.ObjArray[ind].(types.LazyObjectStreamObject); ok {
+			if o, err := l.DecodedObject(c); err == nil && isCatalog(o) {
+				catalogs = append(catalogs, catalogCandidate{objNr1, entry})
+			}
+		}
+	}
+
+	ctx.Read.ObjectStreams[objNr] = true
+	ctx.Read.UsingObjectStreams = true
+
+	return catalogs, nil
+}
+
+// scanObject registers the xRefTable entry for the indirect object at m.
+// It returns the offset behind this object's stream data.
+func scanObject(c context.Context, ctx *model.Context, m scanMarker, trailerDicts *[]types.Dict, catalogs *[]catalogCandidate) (int64, error) {
+	offset, genNr := m.offset, m.genNr
+	entry := &model.XRefTableEntry{Offset: &offset, Generation: &genNr}
+
+	// A redefined object replaces any previous definition.
+	register := func() {
+		ctx.Table[m.objNr] = entry
+		delete(ctx.Read.ObjectStreams, m.objNr)
+	}
+
+	o, err := ParseObjectWithContext(c, ctx, m.offset, m.objNr, m.genNr)
+	if err != nil {
+		// Might be resolvable once the xRefTable is complete.
+		register()
+		return 0, nil
+	}
+
+	if o == nil {
+		return 0, nil
+	}
+
+	if isCatalog(o) {
+		*catalogs = append(*catalogs, catalogCandidate{m.objNr, entry})
+	}
+
+	sd, ok := o.(types.StreamDict)
+	if !ok {
+		register()
+		return 0, nil
+	}
+
+	end, ok, err := streamEnd(ctx, sd)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		model.ShowRepaired(fmt.Sprintf("truncated obj#%d", m.objNr))
+		return ctx.Read.FileSize, nil
+	}
+
+	if t := sd.Type(); t != nil && *t == "XRef" {
+		// Cross reference streams get recreated on write.
+		*trailerDicts = append(*trailerDicts, sd.Dict)
+		return end, nil
+	}
+
+	register()
+
+	if sd.IsObjStm() {
+		cc, err := objectStreamObjects(c, ctx, sd, m.objNr)
+		if err != nil {
+			model.ShowSkipped(fmt.Sprintf("objects of object stream obj#%d: %v", m.objNr, err))
+		}
+		*catalogs = append(*catalogs, cc...)
+	}
+
+	return end, nil
+}
+
+// recoverTrailer sets up Root, Info, Encrypt, ID and Size using the trailers found.
+// A missing Root gets replaced by the most recent catalog.
+func recoverTrailer(ctx *model.Context, trailerDicts []types.Dict, candidates []catalogCandidate) error {
+	xRefTable := ctx.XRefTable
+
+	// Skip catalogs that got redefined or dropped along with their object stream.
+	var catalogs []int
+	for _, cc := range candidates {
+		if xRefTable.Table[cc.objNr] == cc.entry {
+			catalogs = append(catalogs, cc.objNr)
+		}
+	}
+
+	exists := func(ir *types.IndirectRef) bool {
+		if ir == nil {
+			return false
+		}
+		_, ok := xRefTable.Table[ir.ObjectNumber.Value()]
+		return ok
+	}
+
+	isCatalogObj := types.IntSet{}
+	for _, objNr := range catalogs {
+		isCatalogObj[objNr] = true
+	}
+
+	// The most recent trailer wins.
+	for i := len(trailerDicts) - 1; i >= 0; i-- {
+		if ir := trailerDicts[i].IndirectRefEntry("Encrypt"); xRefTable.Encrypt == nil && exists(ir) {
+			xRefTable.Encrypt = ir
+		}
+	}
+
+	for i := len(trailerDicts) - 1; i >= 0; i-- {
+		d := trailerDicts[i]
+
+		// Catalogs within encrypted object streams can't be identified.
+		if ir := d.IndirectRefEntry("Root"); xRefTable.Root == nil && exists(ir) &&
+			(isCatalogObj[ir.ObjectNumber.Value()] || xRefTable.Encrypt != nil) {
+			xRefTable.Root = ir
+		}
+
+		if ir := d.IndirectRefEntry("Info"); xRefTable.Info == nil && exists(ir) {
+			xRefTable.Info = ir
+		}
+
+		if xRefTable.ID == nil {
+			if err := parseTrailerID(xRefTable, d); err != nil {
+				xRefTable.ID = nil
+			}
+		}
+	}
+
+	if len(trailerDicts) == 0 {
+		model.ShowRepaired("trailer")
+	}
+
+	if xRefTable.Root == nil {
+		if len(catalogs) == 0 {
+			return errors.New("pdfcpu: repair: unable to locate the document catalog")
+		}
+		objNr, genNr := catalogs[len(catalogs)-1], 0
+		if entry := xRefTable.Table[objNr]; entry != nil && entry.Generation != nil {
+			genNr = *entry.Generation
+		}
+		xRefTable.Root = types.NewIndirectRef(objNr, genNr)
+		model.ShowRepaired("trailer root")
+	}
+
+	maxObjNr := 0
+	for objNr := range xRefTable.Table {
+		if objNr > maxObjNr {
+			maxObjNr = objNr
+		}
+	}
+	size := maxObjNr + 1
+	xRefTable.Size = &size
+
+	return nil
+}
+
+// rebuildXRefTable reconstructs the xRefTable by scanning the whole file for indirect objects,
+// object streams and trailers, ignoring any cross reference sections or streams.
+func rebuildXRefTable(c context.Context, ctx *model.Context) error {
+	if log.ReadEnabled() {
+		log.Read.Println("rebuildXRefTable: begin")
+	}
+
+	hv, eolCount, _, err := headerVersion(ctx.Read.RS)
+	if err != nil {
+		v := model.V17
+		hv, eolCount = &v, 1
+		model.ShowRepaired("header version")
+	}
+	ctx.HeaderVersion = hv
+	ctx.Read.EolCount = eolCount
+	ctx.Read.XRefRebuilt = true
+
+	mm, err := scanMarkers(c, ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		trailerDicts []types.Dict
+		catalogs     []catalogCandidate
+		skipUntil    int64
+	)
+
+	for _, m := range mm {
+
+		if err := c.Err(); err != nil {
+			return err
+		}
+
+		if m.offset < skipUntil {
+			// Marker within stream data.
+			continue
+		}
+
+		if m.trailer {
+			if d, err := trailerDictAt(c, ctx, m.offset); err == nil {
+				trailerDicts = append(trailerDicts, d)
+			}
+			continue
+		}
+
+		end, err := scanObject(c, ctx, m, &trailerDicts, &catalogs)
+		if err != nil {
+			return err
+		}
+		if end > skipUntil {
+			skipUntil = end
+		}
+	}
+
+	// Drop objects of object streams that got redefined later on.
+	for objNr, entry := range ctx.Table {
+		if entry.Compressed && !ctx.Read.ObjectStreams[*entry.ObjectStream] {
+			delete(ctx.Table, objNr)
+		}
+	}
+
+	if len(ctx.Table) == 0 {
+		return errors.New("pdfcpu: repair: no objects found")
+	}
+
+	if err := recoverTrailer(ctx, trailerDicts, catalogs); err != nil {
+		return err
+	}
+
+	if log.ReadEnabled() {
+		objNrs := make([]int, 0, len(ctx.Table))
+		for objNr := range ctx.Table {
+			objNrs = append(objNrs, objNr)
+		}
+		sort.Ints(objNrs)
+		log.Read.Printf("rebuildXRefTable: recovered objects: %v\n", objNrs)
+	}
+
+	model.ShowRepaired("xreftable")
+
+	if log.ReadEnabled() {
+		log.Read.Println("rebuildXRefTable: end")
+	}
+
+	return ctx.EnsureValidFreeList()
+}
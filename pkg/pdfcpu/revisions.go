@@ -0,0 +1,426 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pkg/errors"
+)
+
+// A PDF file grows by appending incremental updates, each terminated by a trailer and %%EOF.
+// Every prefix of the file ending at one of these markers is a complete document: a revision.
+
+var (
+	reEOF        = regexp.MustCompile(`startxref[ \t\r\n\f\x00]+(\d+)[ \t\r\n\f\x00]*%%EOF[ \t]*(\r\n|\r|\n)?`)
+	reXRefStart  = regexp.MustCompile(`^[ \t\r\n\f\x00]*(xref|\d+[ \t\r\n\f\x00]+\d+[ \t\r\n\f\x00]*obj)`)
+	reByteRange  = regexp.MustCompile(`/ByteRange[ \t\r\n\f\x00]*\[[ \t\r\n\f\x00]*0[ \t\r\n\f\x00]+\d+[ \t\r\n\f\x00]+(\d+)[ \t\r\n\f\x00]+(\d+)[ \t\r\n\f\x00]*\]`)
+	reLinHintOff = regexp.MustCompile(`/Linearized[^>]*?/H[ \t\r\n\f\x00]*\[[ \t\r\n\f\x00]*(\d+)|/H[ \t\r\n\f\x00]*\[[ \t\r\n\f\x00]*(\d+)[^>]*?/Linearized`)
+)
+
+// Revision represents the original document or one of its incremental updates.
+type Revision struct {
+	Nr         int   // 1 = original document
+	XRefOffset int64 // Offset of the last cross reference section.
+	Size       int64 // File size of this revision including %%EOF.
+	XRefStream bool  // The cross reference section is a stream.
+	Signed     bool  // A signature covers exactly this revision.
+	Added      []int // Objects added by this revision.
+	Changed    []int // Objects rewritten by this revision.
+	Freed      []int // Objects freed by this revision.
+}
+
+// linearizationLimit returns the offset of the primary hint stream of a linearized file.
+// The first page trailer preceding it does not terminate a revision.
+func linearizationLimit(head []byte) int {
+	m := reLinHintOff.FindSubmatch(head)
+	if m == nil {
+		return 0
+	}
+	s := m[1]
+	if len(s) == 0 {
+		s = m[2]
+	}
+	i, _ := strconv.Atoi(string(s))
+	return i
+}
+
+const (
+	revisionScanChunk   = 1 << 20
+	revisionScanOverlap = 4096 // Covers matches spanning two chunks.
+)
+
+// eofMarker is a startxref section terminating a potential revision.
+type eofMarker struct {
+	start, end, eof, xrefOff int64
+}
+
+// revisionScanner collects the eof markers and signed sizes of a file read chunk by chunk.
+type revisionScanner struct {
+	markers            []eofMarker
+	signed             map[int64]bool
+	nextEOF, nextRange int64 // Offsets up to which matches have been recorded.
+}
+
+// scan records the matches within bb located at file offset base.
+// Unless last is set, matches reaching the end of bb are left for the next chunk.
+func (sc *revisionScanner) scan(bb []byte, base int64, last bool) {
+	for _, m := range reEOF.FindAllSubmatchIndex(bb, -1) {
+		if !last && m[1] == len(bb) {
+			break
+		}
+		if base+int64(m[0]) < sc.nextEOF {
+			continue
+		}
+		sc.nextEOF = base + int64(m[1])
+
+		off, err := strconv.ParseInt(string(bb[m[2]:m[3]]), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		em := eofMarker{start: base + int64(m[0]), end: base + int64(m[1]), eof: base + int64(m[1]), xrefOff: off}
+		if m[4] >= 0 {
+			// Exclude the trailing eol.
+			em.eof = base + int64(m[4])
+		}
+		sc.markers = append(sc.markers, em)
+	}
+
+	for _, m := range reByteRange.FindAllSubmatchIndex(bb, -1) {
+		if !last && m[1] == len(bb) {
+			break
+		}
+		if base+int64(m[0]) < sc.nextRange {
+			continue
+		}
+		sc.nextRange = base + int64(m[1])
+
+		off, err1 := strconv.ParseInt(string(bb[m[2]:m[3]]), 10, 64)
+		l, err2 := strconv.ParseInt(string(bb[m[4]:m[5]]), 10, 64)
+		if err1 == nil && err2 == nil {
+			sc.signed[off+l] = true
+		}
+	}
+}
+
+// xrefStart returns the beginning of the cross reference section at off if there is one.
+func xrefStart(rs io.ReadSeeker, off, end int64) ([][]byte, error) {
+	if _, err := rs.Seek(off, io.SeekStart); err != nil {
+		return nil, err
+	}
+	bb := make([]byte, min(end-off, 256))
+	if _, err := io.ReadFull(rs, bb); err != nil {
+		return nil, err
+	}
+	return reXRefStart.FindSubmatch(bb), nil
+}
+
+// scanRevisions locates the end of each revision.
+func scanRevisions(rs io.ReadSeeker) ([]Revision, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	sc := &revisionScanner{signed: map[int64]bool{}}
+
+	var head []byte
+
+	buf := make([]byte, revisionScanChunk+revisionScanOverlap)
+	l := 0 // Number of bytes in buf.
+
+	for base := int64(0); ; {
+		n, err := io.ReadFull(rs, buf[l:])
+		l += n
+		last := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !last {
+			return nil, err
+		}
+
+		if head == nil {
+			head = bytes.Clone(buf[:min(l, 1024)])
+		}
+
+		sc.scan(buf[:l], base, last)
+		if last {
+			break
+		}
+
+		// Keep the tail for matches spanning chunks.
+		copy(buf, buf[l-revisionScanOverlap:l])
+		base += int64(l - revisionScanOverlap)
+		l = revisionScanOverlap
+	}
+
+	offExtra := int64(max(bytes.Index(head, []byte("%PDF-")), 0))
+	limit := int64(linearizationLimit(head))
+
+	rr := []Revision{}
+
+	for _, em := range sc.markers {
+		if em.eof <= limit || em.xrefOff+offExtra >= em.start {
+			continue
+		}
+
+		sm, err := xrefStart(rs, em.xrefOff+offExtra, em.start)
+		if err != nil {
+			return nil, err
+		}
+		if sm == nil {
+			// Not a cross reference section, eg. the first page trailer of a linearized file.
+			continue
+		}
+
+		rr = append(rr, Revision{
+			Nr:         len(rr) + 1,
+			XRefOffset: em.xrefOff,
+			Size:       em.end,
+			XRefStream: string(sm[1]) != "xref",
+			Signed:     sc.signed[em.end] || sc.signed[em.eof],
+		})
+	}
+
+	return rr, nil
+}
+
+// revisionReader reads the first size bytes of rs.
+type revisionReader struct {
+	rs   io.ReadSeeker
+	off  int64
+	size int64
+}
+
+func (r *revisionReader) Read(p []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	if _, err := r.rs.Seek(r.off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := r.rs.Read(p[:min(int64(len(p)), r.size-r.off)])
+	r.off += int64(n)
+	return n, err
+}
+
+func (r *revisionReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.Errorf("pdfcpu: invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("pdfcpu: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
+
+func revisionTable(rs io.ReadSeeker, size int64, conf *model.Configuration) (map[int]*model.XRefTableEntry, error) {
+	ctx, err := model.NewContext(&revisionReader{rs: rs, size: size}, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := readXRefTable(context.Background(), ctx); err != nil {
+		return nil, err
+	}
+
+	return ctx.Table, nil
+}
+
+func inUse(e *model.XRefTableEntry) bool {
+	return e != nil && !e.Free
+}
+
+func intVal(i *int) int {
+	if i == nil {
+		return -1
+	}
+	return *i
+}
+
+func sameLocation(e1, e2 *model.XRefTableEntry) bool {
+	if e1.Compressed != e2.Compressed || intVal(e1.Generation) != intVal(e2.Generation) {
+		return false
+	}
+	if e1.Compressed {
+		return intVal(e1.ObjectStream) == intVal(e2.ObjectStream) && intVal(e1.ObjectStreamInd) == intVal(e2.ObjectStreamInd)
+	}
+	return e1.Offset != nil && e2.Offset != nil && *e1.Offset == *e2.Offset
+}
+
+// changed returns true if objNr got rewritten between t1 and t2.
+func changed(t1, t2 map[int]*model.XRefTableEntry, objNr int) bool {
+	e1, e2 := t1[objNr], t2[objNr]
+	if !sameLocation(e1, e2) {
+		return true
+	}
+	if !e2.Compressed {
+		return false
+	}
+	// A compressed object is rewritten along with its object stream.
+	osNr := intVal(e2.ObjectStream)
+	return inUse(t1[osNr]) && inUse(t2[osNr]) && !sameLocation(t1[osNr], t2[osNr])
+}
+
+// compareTables records the changes from t1 to t2 in r.
+func compareTables(t1, t2 map[int]*model.XRefTableEntry, r *Revision) {
+	objNrs := make([]int, 0, len(t2))
+	for objNr := range t2 {
+		if objNr > 0 {
+			objNrs = append(objNrs, objNr)
+		}
+	}
+	for objNr := range t1 {
+		if _, ok := t2[objNr]; !ok && objNr > 0 {
+			objNrs = append(objNrs, objNr)
+		}
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		e1, e2 := t1[objNr], t2[objNr]
+		switch {
+		case inUse(e2) && !inUse(e1):
+			r.Added = append(r.Added, objNr)
+		case inUse(e2) && changed(t1, t2, objNr):
+			r.Changed = append(r.Changed, objNr)
+		case !inUse(e2) && inUse(e1):
+			r.Freed = append(r.Freed, objNr)
+		}
+	}
+}
+
+// Revisions returns all revisions of rs along with the objects each of them added, changed or freed.
+func Revisions(rs io.ReadSeeker, conf *model.Configuration) ([]Revision, error) {
+	rr, err := scanRevisions(rs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rr) == 0 {
+		return nil, errors.New("pdfcpu: no revisions found")
+	}
+
+	t1 := map[int]*model.XRefTableEntry{}
+
+	for i := range rr {
+		t2, err := revisionTable(rs, rr[i].Size, conf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pdfcpu: revision %d", rr[i].Nr)
+		}
+		compareTables(t1, t2, &rr[i])
+		t1 = t2
+	}
+
+	return rr, nil
+}
+
+func revision(rr []Revision, nr int) (*Revision, error) {
+	if nr < 1 || nr > len(rr) {
+		return nil, errors.Errorf("pdfcpu: invalid revision: %d (available: 1-%d)", nr, len(rr))
+	}
+	return &rr[nr-1], nil
+}
+
+// RevisionReader returns a reader for the document of rs as it was at revision nr.
+// Its content is a byte identical prefix of rs, so signatures covering this revision stay intact.
+func RevisionReader(rs io.ReadSeeker, nr int) (io.ReadSeeker, error) {
+	rr, err := scanRevisions(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := revision(rr, nr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revisionReader{rs: rs, size: r.Size}, nil
+}
+
+// ListRevisions returns a list representation of rr.
+func ListRevisions(rr []Revision) []string {
+	ss := []string{"Rev  xref offset        size  xref    added  changed  freed  signed"}
+	ss = append(ss, "=================================================================")
+
+	for _, r := range rr {
+		typ, signed := "table", ""
+		if r.XRefStream {
+			typ = "stream"
+		}
+		if r.Signed {
+			signed = "yes"
+		}
+		ss = append(ss, fmt.Sprintf("%3d  %11d  %10d  %-6s  %5d  %7d  %5d  %s",
+			r.Nr, r.XRefOffset, r.Size, typ, len(r.Added), len(r.Changed), len(r.Freed), signed))
+	}
+
+	return ss
+}
+
+// ListRevisionChanges returns a list representation of the objects revision nr added, changed or freed.
+// nr = 0 lists the changes of all incremental updates.
+func ListRevisionChanges(rr []Revision, nr int) ([]string, error) {
+	revs := rr[min(1, len(rr)):]
+	if nr > 0 {
+		r, err := revision(rr, nr)
+		if err != nil {
+			return nil, err
+		}
+		revs = []Revision{*r}
+	}
+
+	if len(revs) == 0 {
+		return []string{"no incremental updates available"}, nil
+	}
+
+	objs := func(ii []int) string {
+		if len(ii) == 0 {
+			return "-"
+		}
+		return pageRanges(ii)
+	}
+
+	ss := []string{}
+
+	for _, r := range revs {
+		if len(ss) > 0 {
+			ss = append(ss, "")
+		}
+		s := fmt.Sprintf("Revision %d:", r.Nr)
+		if r.Signed {
+			s += " (signed)"
+		}
+		ss = append(ss, s,
+			fmt.Sprintf("    added: %s", objs(r.Added)),
+			fmt.Sprintf("  changed: %s", objs(r.Changed)),
+			fmt.Sprintf("    freed: %s", objs(r.Freed)))
+	}
+
+	return ss, nil
+}
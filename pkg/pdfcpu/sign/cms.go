@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Cryptographic Message Syntax, see RFC 5652.
+
+var (
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+	oidAttributeContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttributeMessageDigest        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttributeSigningCertificateV2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type essCertIDv2 struct {
+	HashAlgorithm pkix.AlgorithmIdentifier `asn1:"optional"` // default: SHA-256
+	CertHash      []byte
+	IssuerSerial  issuerSerial
+}
+
+type issuerSerial struct {
+	Issuer       []asn1.RawValue // GeneralNames
+	SerialNumber *big.Int
+}
+
+type signingCertificateV2 struct {
+	Certs []essCertIDv2
+}
+
+// contextTagged returns bb wrapped as constructed context specific element with given tag.
+func contextTagged(tag int, bb []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: tag, IsCompound: true, Bytes: bb}
+}
+
+func newAttribute(typ asn1.ObjectIdentifier, val any) (attribute, error) {
+	bb, err := asn1.Marshal(val)
+	if err != nil {
+		return attribute{}, err
+	}
+	return attribute{
+		Type:   typ,
+		Values: asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: bb},
+	}, nil
+}
+
+// marshalAttributes returns the DER encoded content of a SET OF attrs.
+func marshalAttributes(attrs []attribute) ([]byte, error) {
+	var ss [][]byte
+	for _, attr := range attrs {
+		bb, err := asn1.Marshal(attr)
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, bb)
+	}
+
+	// DER requires the elements of a SET OF to be sorted by their encoding.
+	sort.Slice(ss, func(i, j int) bool { return bytes.Compare(ss[i], ss[j]) < 0 })
+
+	return bytes.Join(ss, nil), nil
+}
+
+func signingCertificateAttribute(cert *x509.Certificate) (attribute, error) {
+	h := sha256.Sum256(cert.Raw)
+	return newAttribute(oidAttributeSigningCertificateV2, signingCertificateV2{
+		Certs: []essCertIDv2{{
+			CertHash: h[:],
+			IssuerSerial: issuerSerial{
+				Issuer:       []asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: cert.RawIssuer}},
+				SerialNumber: cert.SerialNumber,
+			},
+		}},
+	})
+}
+
+func signatureAlgorithm(signer crypto.Signer) (pkix.AlgorithmIdentifier, error) {
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, nil
+	}
+	return pkix.AlgorithmIdentifier{}, errors.Errorf("pdfcpu: sign: unsupported key type %T", signer.Public())
+}
+
+// CMS returns a DER encoded detached CMS signature (CAdES-BES) over a content with SHA-256 digest.
+func CMS(digest []byte, cred *Credentials) ([]byte, error) {
+	sigAlg, err := signatureAlgorithm(cred.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	digestAlg := pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+
+	// Signed attributes
+	attrs := make([]attribute, 3)
+	if attrs[0], err = newAttribute(oidAttributeContentType, oidData); err != nil {
+		return nil, err
+	}
+	if attrs[1], err = newAttribute(oidAttributeMessageDigest, digest); err != nil {
+		return nil, err
+	}
+	if attrs[2], err = signingCertificateAttribute(cred.Certificate); err != nil {
+		return nil, err
+	}
+
+	signedAttrs, err := marshalAttributes(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The signature covers the signed attributes DER encoded as SET OF.
+	bb, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: signedAttrs})
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(bb)
+
+	sig, err := cred.Signer.Sign(rand.Reader, h[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	sid, err := asn1.Marshal(issuerAndSerialNumber{
+		Issuer:       asn1.RawValue{FullBytes: cred.Certificate.RawIssuer},
+		SerialNumber: cred.Certificate.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []byte
+	for _, c := range cred.certificates() {
+		certs = append(certs, c.Raw...)
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: oidData},
+		Certificates:     contextTagged(0, certs),
+		SignerInfos: []signerInfo{{
+			Version:            1,
+			SID:                asn1.RawValue{FullBytes: sid},
+			DigestAlgorithm:    digestAlg,
+			SignedAttrs:        contextTagged(0, signedAttrs),
+			SignatureAlgorithm: sigAlg,
+			Signature:          sig,
+		}},
+	}
+
+	if bb, err = asn1.Marshal(sd); err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(contentInfo{ContentType: oidSignedData, Content: contextTagged(0, bb)})
+}
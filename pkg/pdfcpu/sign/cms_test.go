@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"path/filepath"
+	"testing"
+)
+
+func TestCMS(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "sign")
+
+	for _, fn := range []string{"test.p12", "ec.pem"} {
+		cred, err := LoadCredentials("test", filepath.Join(dir, fn))
+		if err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+
+		digest := sha256.Sum256([]byte("pdfcpu"))
+
+		bb, err := CMS(digest[:], cred)
+		if err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+
+		var ci contentInfo
+		if _, err := asn1.Unmarshal(bb, &ci); err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+
+		var sd signedData
+		if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+
+		certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil || len(certs) != 1 || !certs[0].Equal(cred.Certificate) {
+			t.Fatalf("%s: missing signing certificate\n", fn)
+		}
+
+		if len(sd.SignerInfos) != 1 {
+			t.Fatalf("%s: want 1 signer info, got %d\n", fn, len(sd.SignerInfos))
+		}
+		si := sd.SignerInfos[0]
+
+		var attrs []attribute
+		if _, err := asn1.UnmarshalWithParams(si.SignedAttrs.FullBytes, &attrs, "set,tag:0"); err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+
+		var md []byte
+		for _, attr := range attrs {
+			if attr.Type.Equal(oidAttributeMessageDigest) {
+				if _, err := asn1.Unmarshal(attr.Values.Bytes, &md); err != nil {
+					t.Fatalf("%s: %v\n", fn, err)
+				}
+			}
+		}
+		if !bytes.Equal(md, digest[:]) {
+			t.Fatalf("%s: message digest mismatch\n", fn)
+		}
+
+		signed, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes})
+		if err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+		h := sha256.Sum256(signed)
+
+		switch pub := cred.Certificate.PublicKey.(type) {
+		case *rsa.PublicKey:
+			err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, h[:], si.Signature)
+		case *ecdsa.PublicKey:
+			if !ecdsa.VerifyASN1(pub, h[:], si.Signature) {
+				err = rsa.ErrVerification
+			}
+		}
+		if err != nil {
+			t.Fatalf("%s: %v\n", fn, err)
+		}
+	}
+}
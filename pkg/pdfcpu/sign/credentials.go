@@ -0,0 +1,246 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Credentials represent a private key together with its certificate and an optional certificate chain.
+type Credentials struct {
+	Signer      crypto.Signer
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate // Intermediate certificates to be embedded.
+}
+
+func (cred *Credentials) certificates() []*x509.Certificate {
+	return append([]*x509.Certificate{cred.Certificate}, cred.Chain...)
+}
+
+type publicKey interface {
+	Equal(x crypto.PublicKey) bool
+}
+
+// newCredentials picks the first usable private key and its matching certificate.
+func newCredentials(keys []any, certs []*x509.Certificate) (*Credentials, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("pdfcpu: sign: missing private key")
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("pdfcpu: sign: missing certificate")
+	}
+
+	for _, k := range keys {
+		signer, ok := k.(crypto.Signer)
+		if !ok {
+			continue
+		}
+		pub, ok := signer.Public().(publicKey)
+		if !ok {
+			continue
+		}
+		for i, cert := range certs {
+			if !pub.Equal(cert.PublicKey) {
+				continue
+			}
+			if _, err := signatureAlgorithm(signer); err != nil {
+				return nil, err
+			}
+			chain := append(append([]*x509.Certificate{}, certs[:i]...), certs[i+1:]...)
+			return &Credentials{Signer: signer, Certificate: cert, Chain: chain}, nil
+		}
+	}
+
+	return nil, errors.New("pdfcpu: sign: no certificate matching private key")
+}
+
+func isPKCS12(bb []byte) bool {
+	var pfx pfxPdu
+	_, err := asn1.Unmarshal(bb, &pfx)
+	return err == nil
+}
+
+func parsePrivateKey(block *pem.Block, password string) (any, error) {
+	switch block.Type {
+
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+
+	case "ENCRYPTED PRIVATE KEY":
+		var epki encryptedPrivateKeyInfo
+		if _, err := asn1.Unmarshal(block.Bytes, &epki); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: sign: corrupt encrypted private key")
+		}
+		bb, err := pbeDecrypt(epki.Algorithm, epki.EncryptedData, password)
+		if err != nil {
+			return nil, err
+		}
+		return x509.ParsePKCS8PrivateKey(bb)
+	}
+
+	return nil, nil
+}
+
+func parsePEM(bb []byte, password string, keys *[]any, certs *[]*x509.Certificate) error {
+	for {
+		var block *pem.Block
+		if block, bb = pem.Decode(bb); block == nil {
+			return nil
+		}
+
+		if strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+			return errors.New("pdfcpu: sign: legacy PEM encryption not supported, use PKCS#8")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return err
+			}
+			*certs = append(*certs, cert)
+			continue
+		}
+
+		key, err := parsePrivateKey(block, password)
+		if err != nil {
+			return err
+		}
+		if key != nil {
+			*keys = append(*keys, key)
+		}
+	}
+}
+
+// ParseCredentials returns the credentials contained in a PKCS#12 file or in a sequence of PEM files.
+// password is used for PKCS#12 files and encrypted PKCS#8 private keys.
+func ParseCredentials(password string, files ...[]byte) (*Credentials, error) {
+	var (
+		keys  []any
+		certs []*x509.Certificate
+	)
+
+	for _, bb := range files {
+
+		if bytes.Contains(bb, []byte("-----BEGIN ")) {
+			if err := parsePEM(bb, password, &keys, &certs); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isPKCS12(bb) {
+			kk, cc, err := decodePKCS12(bb, password)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, kk...)
+			certs = append(certs, cc...)
+			continue
+		}
+
+		// DER encoded certificate
+		cert, err := x509.ParseCertificate(bb)
+		if err != nil {
+			return nil, errors.New("pdfcpu: sign: unsupported key or certificate file")
+		}
+		certs = append(certs, cert)
+	}
+
+	return newCredentials(keys, certs)
+}
+
+// LoadCredentials reads the signing credentials from a PKCS#12 file (.p12, .pfx)
+// or from PEM encoded key and certificate files.
+func LoadCredentials(password string, fileNames ...string) (*Credentials, error) {
+	var files [][]byte
+	for _, fn := range fileNames {
+		bb, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bb)
+	}
+
+	return ParseCredentials(password, files...)
+}
+
+// IsKeyFile returns true for file names referring to a PKCS#12 file, a key or a certificate file.
+func IsKeyFile(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".p12", ".pfx", ".pem", ".key", ".crt", ".cer", ".der":
+		return true
+	}
+	return false
+}
+
+// LoadCertificates reads all certificates from PEM or DER encoded files.
+func LoadCertificates(fileNames ...string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for _, fn := range fileNames {
+		bb, err := os.ReadFile(fn)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Contains(bb, []byte("-----BEGIN ")) {
+			var cc []*x509.Certificate
+			for {
+				var block *pem.Block
+				if block, bb = pem.Decode(bb); block == nil {
+					break
+				}
+				if block.Type != "CERTIFICATE" {
+					continue
+				}
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, errors.Wrapf(err, "pdfcpu: %s", fn)
+				}
+				cc = append(cc, cert)
+			}
+			if len(cc) == 0 {
+				return nil, errors.Errorf("pdfcpu: no certificates found in %s", fn)
+			}
+			certs = append(certs, cc...)
+			continue
+		}
+
+		cc, err := x509.ParseCertificates(bb)
+		if err != nil {
+			return nil, errors.Wrapf(err, "pdfcpu: %s", fn)
+		}
+		certs = append(certs, cc...)
+	}
+
+	return certs, nil
+}
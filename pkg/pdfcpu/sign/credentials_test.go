@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+func TestRC2(t *testing.T) {
+	// Test vectors from RFC 2268.
+	for _, tt := range []struct {
+		key, plain, cipher string
+		t1                 int
+	}{
+		{"0000000000000000", "0000000000000000", "ebb773f993278eff", 63},
+		{"ffffffffffffffff", "ffffffffffffffff", "278b27e42e2f0d49", 64},
+		{"3000000000000000", "1000000000000001", "30649edf9be7d2c2", 64},
+		{"88", "0000000000000000", "61a8a244adacccf0", 64},
+		{"88bca90e90875a7f0f79c384627bafb2", "0000000000000000", "2269552ab0f85ca6", 128},
+	} {
+		key, _ := hex.DecodeString(tt.key)
+		plain, _ := hex.DecodeString(tt.plain)
+		want, _ := hex.DecodeString(tt.cipher)
+
+		c := newRC2Cipher(key, tt.t1)
+		got := make([]byte, 8)
+
+		c.Encrypt(got, plain)
+		if !bytes.Equal(got, want) {
+			t.Errorf("encrypt key=%s: want %x, got %x\n", tt.key, want, got)
+		}
+
+		c.Decrypt(got, want)
+		if !bytes.Equal(got, plain) {
+			t.Errorf("decrypt key=%s: want %x, got %x\n", tt.key, plain, got)
+		}
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "sign")
+
+	for _, tt := range []struct {
+		files []string
+		rsa   bool
+	}{
+		{[]string{"test.p12"}, true},   // PBES2, AES-256, HMAC-SHA256
+		{[]string{"legacy.p12"}, true}, // RC2-40, 3DES, HMAC-SHA1
+		{[]string{"key.pem", "cert.pem"}, true},
+		{[]string{"key_enc.pem", "cert.pem"}, true},
+		{[]string{"ec.pem"}, false},
+	} {
+		var fileNames []string
+		for _, fn := range tt.files {
+			fileNames = append(fileNames, filepath.Join(dir, fn))
+		}
+
+		cred, err := LoadCredentials("test", fileNames...)
+		if err != nil {
+			t.Fatalf("%v: %v\n", tt.files, err)
+		}
+
+		switch cred.Signer.(type) {
+		case *rsa.PrivateKey:
+			if !tt.rsa {
+				t.Errorf("%v: unexpected RSA key\n", tt.files)
+			}
+		case *ecdsa.PrivateKey:
+			if tt.rsa {
+				t.Errorf("%v: unexpected EC key\n", tt.files)
+			}
+		}
+
+		if cred.Certificate == nil || len(cred.Chain) != 0 {
+			t.Errorf("%v: unexpected certificates\n", tt.files)
+		}
+	}
+
+	if _, err := LoadCredentials("wrong", filepath.Join(dir, "test.p12")); err != ErrWrongPassword {
+		t.Errorf("want ErrWrongPassword, got %v\n", err)
+	}
+}
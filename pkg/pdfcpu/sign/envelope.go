@@ -0,0 +1,276 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	oidEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidRC2CBC        = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 2}
+)
+
+// ErrNotRecipient indicates a CMS envelope not addressed to the supplied key.
+var ErrNotRecipient = errors.New("pdfcpu: not a recipient of this envelope")
+
+type envelopedData struct {
+	Version              int
+	OriginatorInfo       asn1.RawValue   `asn1:"optional,tag:0"`
+	RecipientInfos       []asn1.RawValue `asn1:"set"`
+	EncryptedContentInfo encryptedContentInfo
+	UnprotectedAttrs     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type keyTransRecipientInfo struct {
+	Version                int
+	RID                    asn1.RawValue
+	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedKey           []byte
+}
+
+type rc2CBCParams struct {
+	Version int `asn1:"optional,default:-1"`
+	IV      []byte
+}
+
+func pkcs7Pad(bb []byte, blockSize int) []byte {
+	n := blockSize - len(bb)%blockSize
+	return append(bb, bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func keyTransRecipient(cert *x509.Certificate, key []byte) (asn1.RawValue, error) {
+	var ri asn1.RawValue
+
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ri, errors.Errorf("pdfcpu: unsupported recipient key type %T (RSA only)", cert.PublicKey)
+	}
+
+	encKey, err := rsa.EncryptPKCS1v15(rand.Reader, pub, key)
+	if err != nil {
+		return ri, err
+	}
+
+	sid, err := asn1.Marshal(issuerAndSerialNumber{Issuer: asn1.RawValue{FullBytes: cert.RawIssuer}, SerialNumber: cert.SerialNumber})
+	if err != nil {
+		return ri, err
+	}
+
+	bb, err := asn1.Marshal(keyTransRecipientInfo{
+		RID:                    asn1.RawValue{FullBytes: sid},
+		KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue},
+		EncryptedKey:           encKey,
+	})
+	if err != nil {
+		return ri, err
+	}
+
+	ri.FullBytes = bb
+	return ri, nil
+}
+
+// Envelope returns a CMS EnvelopedData structure holding data encrypted with AES-256-CBC for all recipients.
+func Envelope(data []byte, recipients ...*x509.Certificate) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("pdfcpu: missing recipients")
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	bb := pkcs7Pad(append([]byte{}, data...), aes.BlockSize)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(bb, bb)
+
+	params, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	ed := envelopedData{
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType: oidData,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+				Algorithm:  oidAES256CBC,
+				Parameters: asn1.RawValue{FullBytes: params},
+			},
+			EncryptedContent: bb,
+		},
+	}
+
+	for _, cert := range recipients {
+		ri, err := keyTransRecipient(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		ed.RecipientInfos = append(ed.RecipientInfos, ri)
+	}
+
+	if bb, err = asn1.Marshal(ed); err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(contentInfo{ContentType: oidEnvelopedData, Content: contextTagged(0, bb)})
+}
+
+func isRecipient(rid asn1.RawValue, cert *x509.Certificate) bool {
+	if cert == nil {
+		// Try any recipient.
+		return true
+	}
+
+	if rid.Class == asn1.ClassContextSpecific && rid.Tag == 0 {
+		return bytes.Equal(rid.Bytes, cert.SubjectKeyId)
+	}
+
+	var ias issuerAndSerialNumber
+	if _, err := asn1.Unmarshal(rid.FullBytes, &ias); err != nil {
+		return false
+	}
+
+	return bytes.Equal(ias.Issuer.FullBytes, cert.RawIssuer) && ias.SerialNumber.Cmp(cert.SerialNumber) == 0
+}
+
+func contentKey(ed *envelopedData, key crypto.Decrypter, cert *x509.Certificate) ([]byte, error) {
+	for _, raw := range ed.RecipientInfos {
+		if raw.Class != asn1.ClassUniversal || raw.Tag != asn1.TagSequence {
+			// Skip all but key transport recipients.
+			continue
+		}
+
+		var ri keyTransRecipientInfo
+		if _, err := asn1.Unmarshal(raw.FullBytes, &ri); err != nil {
+			return nil, errors.Wrap(err, "pdfcpu: corrupt recipient info")
+		}
+
+		if !isRecipient(ri.RID, cert) || !ri.KeyEncryptionAlgorithm.Algorithm.Equal(oidRSAEncryption) {
+			continue
+		}
+
+		k, err := key.Decrypt(rand.Reader, ri.EncryptedKey, nil)
+		if err == nil {
+			return k, nil
+		}
+	}
+
+	return nil, ErrNotRecipient
+}
+
+func rc2EffectiveKeyBits(version int) int {
+	switch version {
+	case -1:
+		return 32
+	case 160:
+		return 40
+	case 120:
+		return 64
+	case 58:
+		return 128
+	}
+	if version < 256 {
+		return 0
+	}
+	return version
+}
+
+func contentCipher(alg pkix.AlgorithmIdentifier, key []byte) (cipher.Block, []byte, error) {
+	if alg.Algorithm.Equal(oidRC2CBC) {
+		var params rc2CBCParams
+		if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+			return nil, nil, errors.Wrap(err, "pdfcpu: corrupt RC2 parameters")
+		}
+		bits := rc2EffectiveKeyBits(params.Version)
+		if bits == 0 {
+			return nil, nil, errors.New("pdfcpu: corrupt RC2 parameters")
+		}
+		return newRC2Cipher(key, bits), params.IV, nil
+	}
+
+	keyLen, newCipher, err := cbcCipher(alg.Algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(key) != keyLen {
+		return nil, nil, errors.New("pdfcpu: invalid content encryption key")
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &iv); err != nil {
+		return nil, nil, errors.Wrap(err, "pdfcpu: corrupt encryption parameters")
+	}
+
+	block, err := newCipher(key)
+	return block, iv, err
+}
+
+// OpenEnvelope decrypts the content of a CMS EnvelopedData structure using key.
+// If cert is not nil only the matching recipient info is considered.
+func OpenEnvelope(bb []byte, key crypto.Decrypter, cert *x509.Certificate) ([]byte, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(bb, &ci); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt CMS envelope")
+	}
+	if !ci.ContentType.Equal(oidEnvelopedData) {
+		return nil, errors.New("pdfcpu: CMS: missing enveloped data")
+	}
+
+	var ed envelopedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: corrupt CMS enveloped data")
+	}
+
+	k, err := contentKey(&ed, key, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	block, iv, err := contentCipher(ed.EncryptedContentInfo.ContentEncryptionAlgorithm, k)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := cbcDecrypt(block, iv, ed.EncryptedContentInfo.EncryptedContent)
+	if err != nil {
+		// PKCS#1 v1.5 decryption of a foreign recipient info may yield a random key.
+		return nil, ErrNotRecipient
+	}
+
+	return data, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "sign")
+
+	cred, err := LoadCredentials("test", filepath.Join(dir, "test.p12"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	other, err := LoadCredentials("", filepath.Join(dir, "recipient.pem"))
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	data := []byte("01234567890123456789\xff\xff\xff\xfc")
+
+	bb, err := Envelope(data, other.Certificate, cred.Certificate)
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	key := cred.Signer.(crypto.Decrypter)
+
+	for _, c := range []*x509.Certificate{cred.Certificate, nil} {
+		got, err := OpenEnvelope(bb, key, c)
+		if err != nil {
+			t.Fatalf("%v\n", err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Fatalf("want %x, got %x\n", data, got)
+		}
+	}
+
+	// Not a recipient.
+	if bb, err = Envelope(data, other.Certificate); err != nil {
+		t.Fatalf("%v\n", err)
+	}
+	if _, err := OpenEnvelope(bb, key, cred.Certificate); err != ErrNotRecipient {
+		t.Fatalf("want ErrNotRecipient, got %v\n", err)
+	}
+}
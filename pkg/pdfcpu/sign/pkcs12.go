@@ -0,0 +1,509 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// PKCS#12 key stores, see RFC 7292.
+
+var (
+	oidPBEWithSHAAnd128BitRC2CBC  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40BitRC2CBC   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidPBEWithSHAAnd3KeyTripleDES = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd2KeyTripleDES = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 4}
+
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA224 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 8}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+
+	oidEncryptedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+)
+
+// ErrWrongPassword signals a PKCS#12 file or an encrypted private key that could not be opened with the given password.
+var ErrWrongPassword = errors.New("pdfcpu: sign: wrong password for key file")
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue `asn1:"tag:0,explicit"`
+	Attributes asn1.RawValue `asn1:"optional"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// bmpPassword returns password as null terminated BMPString as required by the PKCS#12 key derivation.
+func bmpPassword(password string) []byte {
+	u := utf16.Encode([]rune(password))
+	bb := make([]byte, 2*len(u)+2)
+	for i, r := range u {
+		binary.BigEndian.PutUint16(bb[2*i:], r)
+	}
+	return bb
+}
+
+// pkcs12KDF derives size bytes of key material for purpose id (1=key, 2=iv, 3=mac), see RFC 7292 Appendix B.2.
+func pkcs12KDF(h func() hash.Hash, v int, password, salt []byte, iterations int, id byte, size int) []byte {
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		n := v * ((len(b) + v - 1) / v)
+		bb := make([]byte, n)
+		for i := range bb {
+			bb[i] = b[i%len(b)]
+		}
+		return bb
+	}
+
+	d := bytes.Repeat([]byte{id}, v)
+	i := append(fill(salt), fill(password)...)
+
+	one := big.NewInt(1)
+	var out []byte
+
+	for len(out) < size {
+		hh := h()
+		hh.Write(d)
+		hh.Write(i)
+		a := hh.Sum(nil)
+		for j := 1; j < iterations; j++ {
+			hh.Reset()
+			hh.Write(a)
+			a = hh.Sum(a[:0])
+		}
+		out = append(out, a...)
+
+		if len(out) >= size {
+			break
+		}
+
+		b := new(big.Int).SetBytes(fill(a)[:v])
+		b.Add(b, one)
+		for j := 0; j < len(i); j += v {
+			ij := new(big.Int).SetBytes(i[j : j+v])
+			ij.Add(ij, b)
+			bb := ij.Bytes()
+			if len(bb) > v {
+				bb = bb[len(bb)-v:]
+			}
+			for k := 0; k < v; k++ {
+				i[j+k] = 0
+			}
+			copy(i[j+v-len(bb):j+v], bb)
+		}
+	}
+
+	return out[:size]
+}
+
+// pbkdf2Key implements PBKDF2 as specified in RFC 8018.
+func pbkdf2Key(h func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(h, password)
+	hLen := prf.Size()
+	n := (keyLen + hLen - 1) / hLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, n*hLen)
+	u := make([]byte, hLen)
+
+	for block := 1; block <= n; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		prf.Write(buf[:])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hLen:]
+		copy(u, t)
+
+		for i := 2; i <= iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for x := range u {
+				t[x] ^= u[x]
+			}
+		}
+	}
+
+	return dk[:keyLen]
+}
+
+func hashForOID(oid asn1.ObjectIdentifier) (func() hash.Hash, int, error) {
+	switch {
+	case oid.Equal(oidSHA1), oid.Equal(oidHMACWithSHA1):
+		return sha1.New, 64, nil
+	case oid.Equal(oidHMACWithSHA224):
+		return sha256.New224, 64, nil
+	case oid.Equal(oidSHA256), oid.Equal(oidHMACWithSHA256):
+		return sha256.New, 64, nil
+	case oid.Equal(oidSHA384), oid.Equal(oidHMACWithSHA384):
+		return sha512.New384, 128, nil
+	case oid.Equal(oidSHA512), oid.Equal(oidHMACWithSHA512):
+		return sha512.New, 128, nil
+	}
+	return nil, 0, errors.Errorf("pdfcpu: sign: unsupported digest algorithm %s", oid)
+}
+
+func pkcs7Unpad(bb []byte, blockSize int) ([]byte, error) {
+	if len(bb) == 0 || len(bb)%blockSize != 0 {
+		return nil, ErrWrongPassword
+	}
+	n := int(bb[len(bb)-1])
+	if n == 0 || n > blockSize {
+		return nil, ErrWrongPassword
+	}
+	for _, b := range bb[len(bb)-n:] {
+		if int(b) != n {
+			return nil, ErrWrongPassword
+		}
+	}
+	return bb[:len(bb)-n], nil
+}
+
+func cbcDecrypt(block cipher.Block, iv, data []byte) ([]byte, error) {
+	if len(data) == 0 || len(data)%block.BlockSize() != 0 || len(iv) != block.BlockSize() {
+		return nil, errors.New("pdfcpu: sign: corrupt encrypted data")
+	}
+	bb := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(bb, data)
+	return pkcs7Unpad(bb, block.BlockSize())
+}
+
+// cbcCipher returns key length and block cipher constructor for a CBC mode encryption algorithm.
+func cbcCipher(enc asn1.ObjectIdentifier) (int, func(key []byte) (cipher.Block, error), error) {
+	switch {
+	case enc.Equal(oidAES128CBC):
+		return 16, aes.NewCipher, nil
+	case enc.Equal(oidAES192CBC):
+		return 24, aes.NewCipher, nil
+	case enc.Equal(oidAES256CBC):
+		return 32, aes.NewCipher, nil
+	case enc.Equal(oidDESEDE3CBC):
+		return 24, des.NewTripleDESCipher, nil
+	}
+	return 0, nil, errors.Errorf("pdfcpu: sign: unsupported encryption algorithm %s", enc)
+}
+
+func pbeDecrypt(alg pkix.AlgorithmIdentifier, data []byte, password string) ([]byte, error) {
+	if alg.Algorithm.Equal(oidPBES2) {
+		return pbes2Decrypt(alg, data, password)
+	}
+
+	var params pbeParams
+	if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: sign: corrupt PBE parameters")
+	}
+
+	pw := bmpPassword(password)
+
+	derive := func(id byte, size int) []byte {
+		return pkcs12KDF(sha1.New, 64, pw, params.Salt, params.Iterations, id, size)
+	}
+
+	var (
+		block cipher.Block
+		err   error
+	)
+
+	switch {
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd3KeyTripleDES):
+		block, err = des.NewTripleDESCipher(derive(1, 24))
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd2KeyTripleDES):
+		k := derive(1, 16)
+		block, err = des.NewTripleDESCipher(append(k, k[:8]...))
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+		block = newRC2Cipher(derive(1, 16), 128)
+	case alg.Algorithm.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+		block = newRC2Cipher(derive(1, 5), 40)
+	default:
+		return nil, errors.Errorf("pdfcpu: sign: unsupported encryption algorithm %s", alg.Algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cbcDecrypt(block, derive(2, block.BlockSize()), data)
+}
+
+func pbes2Decrypt(alg pkix.AlgorithmIdentifier, data []byte, password string) ([]byte, error) {
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(alg.Parameters.FullBytes, &params); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: sign: corrupt PBES2 parameters")
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.Errorf("pdfcpu: sign: unsupported key derivation function %s", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: sign: corrupt PBKDF2 parameters")
+	}
+
+	prf := oidHMACWithSHA1
+	if len(kdf.PRF.Algorithm) > 0 {
+		prf = kdf.PRF.Algorithm
+	}
+	h, _, err := hashForOID(prf)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLen, newCipher, err := cbcCipher(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errors.Wrap(err, "pdfcpu: sign: corrupt PBES2 parameters")
+	}
+
+	block, err := newCipher(pbkdf2Key(h, []byte(password), kdf.Salt, kdf.IterationCount, keyLen))
+	if err != nil {
+		return nil, err
+	}
+
+	return cbcDecrypt(block, iv, data)
+}
+
+func verifyMac(md *macData, content []byte, password string) error {
+	h, v, err := hashForOID(md.Mac.Algorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	mac := func(pw []byte) []byte {
+		key := pkcs12KDF(h, v, pw, md.MacSalt, md.Iterations, 3, h().Size())
+		m := hmac.New(h, key)
+		m.Write(content)
+		return m.Sum(nil)
+	}
+
+	if hmac.Equal(mac(bmpPassword(password)), md.Mac.Digest) {
+		return nil
+	}
+
+	// Some implementations use an empty byte sequence for the empty password.
+	if password == "" && hmac.Equal(mac(nil), md.Mac.Digest) {
+		return nil
+	}
+
+	return ErrWrongPassword
+}
+
+func parseSafeBags(bb []byte, password string, keys *[]any, certs *[]*x509.Certificate) error {
+	var bags []safeBag
+	if _, err := asn1.Unmarshal(bb, &bags); err != nil {
+		return errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 safe contents")
+	}
+
+	for _, bag := range bags {
+		switch {
+
+		case bag.ID.Equal(oidKeyBag):
+			key, err := x509.ParsePKCS8PrivateKey(bag.Value.Bytes)
+			if err != nil {
+				return err
+			}
+			*keys = append(*keys, key)
+
+		case bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+			var epki encryptedPrivateKeyInfo
+			if _, err := asn1.Unmarshal(bag.Value.Bytes, &epki); err != nil {
+				return errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 key bag")
+			}
+			bb, err := pbeDecrypt(epki.Algorithm, epki.EncryptedData, password)
+			if err != nil {
+				return err
+			}
+			key, err := x509.ParsePKCS8PrivateKey(bb)
+			if err != nil {
+				return err
+			}
+			*keys = append(*keys, key)
+
+		case bag.ID.Equal(oidCertBag):
+			var cb certBag
+			if _, err := asn1.Unmarshal(bag.Value.Bytes, &cb); err != nil {
+				return errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 certificate bag")
+			}
+			if !cb.ID.Equal(oidX509Certificate) {
+				continue
+			}
+			cert, err := x509.ParseCertificate(cb.Data)
+			if err != nil {
+				return err
+			}
+			*certs = append(*certs, cert)
+		}
+	}
+
+	return nil
+}
+
+// decodePKCS12 returns all private keys and certificates contained in the PKCS#12 file bb.
+func decodePKCS12(bb []byte, password string) ([]any, []*x509.Certificate, error) {
+	var pfx pfxPdu
+	if _, err := asn1.Unmarshal(bb, &pfx); err != nil {
+		return nil, nil, errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 file")
+	}
+
+	if pfx.Version != 3 {
+		return nil, nil, errors.Errorf("pdfcpu: sign: unsupported PKCS#12 version %d", pfx.Version)
+	}
+
+	if !pfx.AuthSafe.ContentType.Equal(oidData) {
+		return nil, nil, errors.New("pdfcpu: sign: only password integrity mode supported for PKCS#12")
+	}
+
+	var content []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &content); err != nil {
+		return nil, nil, errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 file")
+	}
+
+	if len(pfx.MacData.Mac.Algorithm.Algorithm) > 0 {
+		if err := verifyMac(&pfx.MacData, content, password); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var authSafe []contentInfo
+	if _, err := asn1.Unmarshal(content, &authSafe); err != nil {
+		return nil, nil, errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 authenticated safe")
+	}
+
+	var (
+		keys  []any
+		certs []*x509.Certificate
+	)
+
+	for _, ci := range authSafe {
+		var bb []byte
+
+		switch {
+
+		case ci.ContentType.Equal(oidData):
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &bb); err != nil {
+				return nil, nil, errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 safe contents")
+			}
+
+		case ci.ContentType.Equal(oidEncryptedData):
+			var ed encryptedData
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+				return nil, nil, errors.Wrap(err, "pdfcpu: sign: corrupt PKCS#12 encrypted data")
+			}
+			eci := ed.EncryptedContentInfo
+			var err error
+			if bb, err = pbeDecrypt(eci.ContentEncryptionAlgorithm, eci.EncryptedContent, password); err != nil {
+				return nil, nil, err
+			}
+
+		default:
+			return nil, nil, errors.New("pdfcpu: sign: only password privacy mode supported for PKCS#12")
+		}
+
+		if err := parseSafeBags(bb, password, &keys, &certs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return keys, certs, nil
+}
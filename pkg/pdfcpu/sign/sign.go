@@ -0,0 +1,510 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sign implements digital signatures for PDF files (PAdES B-B).
+package sign
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	byteRangePlaceholder = "[0 9999999999 9999999999 9999999999]"
+
+	// Reserved space for the CMS structure excluding embedded certificates.
+	contentsSize = 8192
+)
+
+// Details represent the optional signature dict entries and the signature field to be used.
+type Details struct {
+	Field    string // Fully qualified name of the signature field to be filled or created.
+	Name     string // Name of the signer, defaults to the common name of the signing certificate.
+	Reason   string
+	Location string
+	Contact  string
+	Page     int // Page for a new invisible signature field, defaults to 1.
+}
+
+type detailsParamMap map[string]func(string, *Details) error
+
+var paramMap = detailsParamMap{
+	"field":    func(s string, d *Details) error { d.Field = s; return nil },
+	"name":     func(s string, d *Details) error { d.Name = s; return nil },
+	"reason":   func(s string, d *Details) error { d.Reason = s; return nil },
+	"location": func(s string, d *Details) error { d.Location = s; return nil },
+	"contact":  func(s string, d *Details) error { d.Contact = s; return nil },
+	"page": func(s string, d *Details) error {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 {
+			return errors.Errorf("pdfcpu: sign: invalid page number: %s", s)
+		}
+		d.Page = i
+		return nil
+	},
+}
+
+// Handle applies parameter completion and on success parse parameter values into details.
+func (m detailsParamMap) Handle(paramPrefix, paramValueStr string, details *Details) error {
+	var param string
+
+	// Completion support
+	for k := range m {
+		if !strings.HasPrefix(k, strings.ToLower(paramPrefix)) {
+			continue
+		}
+		if len(param) > 0 {
+			return errors.Errorf("pdfcpu: ambiguous parameter prefix \"%s\"", paramPrefix)
+		}
+		param = k
+	}
+
+	if param == "" {
+		return errors.Errorf("pdfcpu: unknown parameter prefix \"%s\"", paramPrefix)
+	}
+
+	return m[param](paramValueStr, details)
+}
+
+// ParseDetails parses a signature description string into an internal structure.
+func ParseDetails(s string) (*Details, error) {
+	details := &Details{}
+
+	if s == "" {
+		return details, nil
+	}
+
+	for _, s := range strings.Split(s, ",") {
+
+		ss := strings.SplitN(s, ":", 2)
+		if len(ss) != 2 {
+			return nil, errors.New("pdfcpu: Invalid signature configuration string. Please consult pdfcpu help sign")
+		}
+
+		paramPrefix := strings.TrimSpace(ss[0])
+		paramValueStr := strings.TrimSpace(ss[1])
+
+		if err := paramMap.Handle(paramPrefix, paramValueStr, details); err != nil {
+			return nil, err
+		}
+	}
+
+	return details, nil
+}
+
+func textString(s string) (types.StringLiteral, error) {
+	s1, err := types.EscapedUTF16String(s)
+	if err != nil {
+		return "", err
+	}
+	return types.StringLiteral(*s1), nil
+}
+
+func signatureDict(cred *Credentials, details *Details) (types.Dict, error) {
+	d := types.Dict(map[string]types.Object{
+		"Type":      types.Name("Sig"),
+		"Filter":    types.Name("Adobe.PPKLite"),
+		"SubFilter": types.Name("ETSI.CAdES.detached"),
+		"M":         types.StringLiteral(types.DateString(time.Now())),
+		"ByteRange": types.NewIntegerArray(0, 9999999999, 9999999999, 9999999999),
+	})
+
+	size := contentsSize
+	for _, cert := range cred.certificates() {
+		size += len(cert.Raw)
+	}
+	d["Contents"] = types.HexLiteral(strings.Repeat("0", 2*size))
+
+	name := details.Name
+	if name == "" {
+		name = cred.Certificate.Subject.CommonName
+	}
+
+	for k, v := range map[string]string{
+		"Name":        name,
+		"Reason":      details.Reason,
+		"Location":    details.Location,
+		"ContactInfo": details.Contact,
+	} {
+		if v == "" {
+			continue
+		}
+		sl, err := textString(v)
+		if err != nil {
+			return nil, err
+		}
+		d[k] = sl
+	}
+
+	return d, nil
+}
+
+// ensureAcroForm returns the AcroForm dict and the number of the object holding it.
+func ensureAcroForm(ctx *model.Context) (types.Dict, int, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	o, found := rootDict.Find("AcroForm")
+	if !found {
+		d := types.Dict(map[string]types.Object{"Fields": types.Array{}})
+		indRef, err := ctx.IndRefForNewObject(d)
+		if err != nil {
+			return nil, 0, err
+		}
+		rootDict["AcroForm"] = *indRef
+		ctx.Write.IncrementWithObjNr(ctx.Root.ObjectNumber.Value())
+		return d, indRef.ObjectNumber.Value(), nil
+	}
+
+	objNr := ctx.Root.ObjectNumber.Value()
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr = indRef.ObjectNumber.Value()
+	}
+
+	d, err := ctx.DereferenceDict(o)
+	if err != nil {
+		return nil, 0, err
+	}
+	if d == nil {
+		return nil, 0, errors.New("pdfcpu: sign: corrupt AcroForm")
+	}
+
+	return d, objNr, nil
+}
+
+// appendToArrayEntry appends indRef to the array entry key of d.
+// objNr is the object number of d and gets marked as part of the increment unless the array is an indirect object.
+func appendToArrayEntry(ctx *model.Context, d types.Dict, objNr int, key string, indRef types.IndirectRef) error {
+	o, found := d.Find(key)
+	if !found {
+		d[key] = types.Array{indRef}
+		ctx.Write.IncrementWithObjNr(objNr)
+		return nil
+	}
+
+	if ir, ok := o.(types.IndirectRef); ok {
+		a, err := ctx.DereferenceArray(ir)
+		if err != nil {
+			return err
+		}
+		ctx.XRefTable.Table[ir.ObjectNumber.Value()].Object = append(a, indRef)
+		ctx.Write.IncrementWithObjNr(ir.ObjectNumber.Value())
+		return nil
+	}
+
+	a, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return err
+	}
+	d[key] = append(a, indRef)
+	ctx.Write.IncrementWithObjNr(objNr)
+
+	return nil
+}
+
+type sigField struct {
+	name   string
+	indRef types.IndirectRef
+	d      types.Dict
+	signed bool
+}
+
+// fieldKids returns the kids of a non terminal field.
+// Kids representing widget annotations only make d a terminal field.
+func fieldKids(ctx *model.Context, d types.Dict) (types.Array, error) {
+	o, found := d.Find("Kids")
+	if !found {
+		return nil, nil
+	}
+
+	kids, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range kids {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := d.Find("T"); found {
+			return kids, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// signatureFields returns all terminal signature fields of the field tree a.
+func signatureFields(ctx *model.Context, a types.Array, parentName string, parentFT *types.Name, ff *[]sigField) error {
+	for _, o := range a {
+		indRef, ok := o.(types.IndirectRef)
+		if !ok {
+			continue
+		}
+
+		d, err := ctx.DereferenceDict(indRef)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+
+		name := parentName
+		s, err := d.StringOrHexLiteralEntry("T")
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			if name != "" {
+				name += "."
+			}
+			name += *s
+		}
+
+		ft := parentFT
+		if n := d.NameEntry("FT"); n != nil {
+			nm := types.Name(*n)
+			ft = &nm
+		}
+
+		kids, err := fieldKids(ctx, d)
+		if err != nil {
+			return err
+		}
+		if len(kids) > 0 {
+			if err := signatureFields(ctx, kids, name, ft, ff); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ft == nil || *ft != "Sig" {
+			continue
+		}
+
+		_, signed := d.Find("V")
+		*ff = append(*ff, sigField{name: name, indRef: indRef, d: d, signed: signed})
+	}
+
+	return nil
+}
+
+func uniqueFieldName(ff []sigField) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("Signature%d", i)
+		found := false
+		for _, f := range ff {
+			if f.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return name
+		}
+	}
+}
+
+func addSignatureField(ctx *model.Context, acroForm types.Dict, acroFormObjNr int, name string, pageNr int, sigIndRef types.IndirectRef) error {
+	if pageNr == 0 {
+		pageNr = 1
+	}
+	if pageNr > ctx.PageCount {
+		return errors.Errorf("pdfcpu: sign: invalid page number: %d", pageNr)
+	}
+
+	pageIndRef, err := ctx.PageDictIndRef(pageNr)
+	if err != nil {
+		return err
+	}
+
+	pageDict, err := ctx.DereferenceDict(*pageIndRef)
+	if err != nil {
+		return err
+	}
+
+	t, err := textString(name)
+	if err != nil {
+		return err
+	}
+
+	// Invisible signature widget: zero size rectangle, flags: Print, Locked.
+	d := types.Dict(map[string]types.Object{
+		"FT":      types.Name("Sig"),
+		"T":       t,
+		"V":       sigIndRef,
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Widget"),
+		"Rect":    types.NewIntegerArray(0, 0, 0, 0),
+		"F":       types.Integer(model.AnnPrint + model.AnnLocked),
+		"P":       *pageIndRef,
+	})
+
+	indRef, err := ctx.IndRefForNewObject(d)
+	if err != nil {
+		return err
+	}
+	ctx.Write.IncrementWithObjNr(indRef.ObjectNumber.Value())
+
+	if err := appendToArrayEntry(ctx, pageDict, pageIndRef.ObjectNumber.Value(), "Annots", *indRef); err != nil {
+		return err
+	}
+
+	return appendToArrayEntry(ctx, acroForm, acroFormObjNr, "Fields", *indRef)
+}
+
+// Prepare modifies ctx for an incremental update containing a signature dict with placeholders for
+// /ByteRange and /Contents referenced by an existing empty or a new invisible signature field.
+func Prepare(ctx *model.Context, cred *Credentials, details *Details) error {
+	if details == nil {
+		details = &Details{}
+	}
+
+	ctx.Write.Increment = true
+	ctx.Write.Offset = ctx.Read.FileSize
+
+	sd, err := signatureDict(cred, details)
+	if err != nil {
+		return err
+	}
+
+	sigIndRef, err := ctx.IndRefForNewObject(sd)
+	if err != nil {
+		return err
+	}
+	ctx.Write.IncrementWithObjNr(sigIndRef.ObjectNumber.Value())
+
+	acroForm, acroFormObjNr, err := ensureAcroForm(ctx)
+	if err != nil {
+		return err
+	}
+
+	var fields types.Array
+	if o, found := acroForm.Find("Fields"); found {
+		if fields, err = ctx.DereferenceArray(o); err != nil {
+			return err
+		}
+	}
+
+	var ff []sigField
+	if err := signatureFields(ctx, fields, "", nil, &ff); err != nil {
+		return err
+	}
+
+	var field *sigField
+	for i, f := range ff {
+		if details.Field == "" && !f.signed || details.Field != "" && f.name == details.Field {
+			field = &ff[i]
+			break
+		}
+	}
+
+	if field != nil {
+		if field.signed {
+			return errors.Errorf("pdfcpu: sign: field \"%s\" already signed", field.name)
+		}
+		field.d["V"] = *sigIndRef
+		ctx.Write.IncrementWithObjNr(field.indRef.ObjectNumber.Value())
+	} else {
+		name := details.Field
+		if name == "" {
+			name = uniqueFieldName(ff)
+		}
+		if err := addSignatureField(ctx, acroForm, acroFormObjNr, name, details.Page, *sigIndRef); err != nil {
+			return err
+		}
+	}
+
+	// SignaturesExist, AppendOnly
+	sigFlags := 3
+	if i := acroForm.IntEntry("SigFlags"); i != nil {
+		sigFlags |= *i
+	}
+	acroForm["SigFlags"] = types.Integer(sigFlags)
+	ctx.Write.IncrementWithObjNr(acroFormObjNr)
+
+	ctx.SignatureExist = true
+	ctx.AppendOnly = true
+
+	return nil
+}
+
+// Embed computes the /ByteRange of the signature dict written by the increment starting at offset
+// and embeds a CMS signature over the covered bytes of bb.
+func Embed(bb []byte, offset int64, cred *Credentials) error {
+	i := bytes.Index(bb[offset:], []byte("/ByteRange"+byteRangePlaceholder))
+	if i < 0 {
+		return errors.New("pdfcpu: sign: missing signature placeholder")
+	}
+	brStart := int(offset) + i + len("/ByteRange")
+
+	// Locate /Contents within the signature dict object regardless of the order its entries got written in.
+	objStart := bytes.LastIndex(bb[offset:brStart], []byte("obj"))
+	objEnd := bytes.Index(bb[brStart:], []byte("endobj"))
+	if objStart < 0 || objEnd < 0 {
+		return errors.New("pdfcpu: sign: corrupt signature dict")
+	}
+	objStart += int(offset)
+	objEnd += brStart
+
+	i = bytes.Index(bb[objStart:objEnd], []byte("/Contents<"))
+	if i < 0 {
+		return errors.New("pdfcpu: sign: missing signature placeholder")
+	}
+	start := objStart + i + len("/Contents")
+
+	i = bytes.IndexByte(bb[start:], '>')
+	if i < 0 {
+		return errors.New("pdfcpu: sign: missing signature placeholder")
+	}
+	end := start + i + 1
+
+	br := fmt.Sprintf("[0 %d %d %d]", start, end, len(bb)-end)
+	if len(br) > len(byteRangePlaceholder) {
+		return errors.New("pdfcpu: sign: file too large")
+	}
+	copy(bb[brStart:], br+strings.Repeat(" ", len(byteRangePlaceholder)-len(br)))
+
+	h := sha256.New()
+	h.Write(bb[:start])
+	h.Write(bb[end:])
+
+	cms, err := CMS(h.Sum(nil), cred)
+	if err != nil {
+		return err
+	}
+
+	s := hex.EncodeToString(cms)
+	if len(s) > end-start-2 {
+		return errors.New("pdfcpu: sign: signature exceeds reserved space")
+	}
+	copy(bb[start+1:], s)
+
+	return nil
+}
@@ -0,0 +1,653 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	oidAttributeSigningTime = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidTSTInfo              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+
+	oidSHA1WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidRSASSAPSS       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+	oidECPublicKey     = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// Supported values for the signature dict entry SubFilter.
+const (
+	SubFilterPKCS7Detached = "adbe.pkcs7.detached"
+	SubFilterPKCS7SHA1     = "adbe.pkcs7.sha1"
+	SubFilterX509RSASHA1   = "adbe.x509.rsa_sha1"
+	SubFilterCAdES         = "ETSI.CAdES.detached"
+	SubFilterRFC3161       = "ETSI.RFC3161"
+)
+
+// Signature represents a signature field and the result of its validation.
+type Signature struct {
+	Field    string
+	Signed   bool
+	Name     string
+	Reason   string
+	Location string
+	Contact  string
+	Time     time.Time // Signing time as claimed by the signature or the signature dict.
+
+	SubFilter       string
+	Signer          *x509.Certificate
+	ByteRange       []int64
+	WholeFile       bool  // true if the signature covers the whole file.
+	ModifiedObjects []int // Objects of the signed revision redefined or deleted by later incremental updates.
+	Verified        bool  // true if Verify has been called.
+	DigestOK        bool  // true if the covered bytes match the signed digest.
+	SignatureOK     bool  // true if the signature matches the signer certificate.
+	Trusted         bool  // true if the signer certificate chains up to the trust store.
+	Problems        []string
+
+	contents []byte
+	certs    [][]byte // adbe.x509.rsa_sha1 only
+}
+
+// Valid returns true for a verified signature with unaltered covered bytes by a trusted signer.
+func (sig *Signature) Valid() bool {
+	return sig.Verified && sig.DigestOK && sig.SignatureOK && sig.Trusted
+}
+
+// SignedRevisionEnd returns the file offset of the end of the revision covered by sig.
+func (sig *Signature) SignedRevisionEnd() int64 {
+	if len(sig.ByteRange) != 4 {
+		return 0
+	}
+	return sig.ByteRange[2] + sig.ByteRange[3]
+}
+
+func (sig *Signature) problem(format string, a ...any) {
+	sig.Problems = append(sig.Problems, fmt.Sprintf(format, a...))
+}
+
+func stringEntry(d types.Dict, key string) string {
+	s, err := d.StringOrHexLiteralEntry(key)
+	if err != nil || s == nil {
+		return ""
+	}
+	return *s
+}
+
+func newSignature(ctx *model.Context, f sigField) (*Signature, error) {
+	sig := &Signature{Field: f.name, Signed: f.signed}
+	if !f.signed {
+		return sig, nil
+	}
+
+	d, err := ctx.DereferenceDict(f.d["V"])
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		sig.Signed = false
+		return sig, nil
+	}
+
+	sig.Name = stringEntry(d, "Name")
+	sig.Reason = stringEntry(d, "Reason")
+	sig.Location = stringEntry(d, "Location")
+	sig.Contact = stringEntry(d, "ContactInfo")
+
+	if s := stringEntry(d, "M"); s != "" {
+		if t, ok := types.DateTime(s, true); ok {
+			sig.Time = t
+		}
+	}
+
+	if n := d.NameEntry("SubFilter"); n != nil {
+		sig.SubFilter = *n
+	}
+
+	if o, found := d.Find("ByteRange"); found {
+		a, err := ctx.DereferenceArray(o)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range a {
+			o, err := ctx.Dereference(o)
+			if err != nil {
+				return nil, err
+			}
+			i, ok := o.(types.Integer)
+			if !ok {
+				return nil, errors.New("pdfcpu: signature: corrupt ByteRange")
+			}
+			sig.ByteRange = append(sig.ByteRange, int64(i))
+		}
+	}
+
+	if sig.contents, err = d.StringEntryBytes("Contents"); err != nil {
+		return nil, err
+	}
+
+	if o, found := d.Find("Cert"); found {
+		o, err := ctx.Dereference(o)
+		if err != nil {
+			return nil, err
+		}
+		a, ok := o.(types.Array)
+		if !ok {
+			a = types.Array{o}
+		}
+		for _, o := range a {
+			bb, err := types.StringBytes(o)
+			if err != nil {
+				continue
+			}
+			sig.certs = append(sig.certs, bb)
+		}
+	}
+
+	return sig, nil
+}
+
+// Signatures returns all signature fields of ctx.
+func Signatures(ctx *model.Context) ([]*Signature, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	o, found := rootDict.Find("AcroForm")
+	if !found {
+		return nil, nil
+	}
+
+	acroForm, err := ctx.DereferenceDict(o)
+	if err != nil || acroForm == nil {
+		return nil, err
+	}
+
+	o, found = acroForm.Find("Fields")
+	if !found {
+		return nil, nil
+	}
+
+	fields, err := ctx.DereferenceArray(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var ff []sigField
+	if err := signatureFields(ctx, fields, "", nil, &ff); err != nil {
+		return nil, err
+	}
+
+	var sigs []*Signature
+	for _, f := range ff {
+		sig, err := newSignature(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+
+	return sigs, nil
+}
+
+// CheckByteRange checks whether sig's ByteRange covers the whole revision of bb except for /Contents.
+func (sig *Signature) CheckByteRange(bb []byte) bool {
+	br := sig.ByteRange
+	if len(br) != 4 {
+		sig.problem("corrupt byte range")
+		return false
+	}
+
+	n := int64(len(bb))
+	if br[0] != 0 || br[1] <= 0 || br[2] <= br[1] || br[3] < 0 || br[2]+br[3] > n {
+		sig.problem("invalid byte range %v", br)
+		return false
+	}
+
+	if bb[br[1]] != '<' || bb[br[2]-1] != '>' {
+		sig.problem("byte range gap does not match /Contents")
+		return false
+	}
+
+	sig.WholeFile = br[2]+br[3] == n
+
+	return true
+}
+
+func (sig *Signature) signedData(bb []byte) []byte {
+	br := sig.ByteRange
+	data := make([]byte, 0, br[1]+br[3])
+	data = append(data, bb[:br[1]]...)
+	return append(data, bb[br[2]:br[2]+br[3]]...)
+}
+
+// LoadTrustStore returns a certificate pool containing all certificates found in PEM or DER encoded files.
+// Directories are searched for files with extension .pem, .crt, .cer or .der.
+func LoadTrustStore(fileNames ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+
+	add := func(fileName string) error {
+		certs, err := LoadCertificates(fileName)
+		if err != nil {
+			return err
+		}
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+		return nil
+	}
+
+	for _, fn := range fileNames {
+		fi, err := os.Stat(fn)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			if err := add(fn); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		entries, err := os.ReadDir(fn)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			switch strings.ToLower(filepath.Ext(e.Name())) {
+			case ".pem", ".crt", ".cer", ".der":
+			default:
+				continue
+			}
+			if err := add(filepath.Join(fn, e.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return pool, nil
+}
+
+// Verify checks the signature against the covered bytes of bb and the signer certificate against roots.
+// If roots is nil, the system trust store is used.
+func (sig *Signature) Verify(bb []byte, roots *x509.CertPool) {
+	sig.Verified = true
+
+	if !sig.Signed || !sig.CheckByteRange(bb) {
+		return
+	}
+
+	data := sig.signedData(bb)
+
+	var (
+		chain []*x509.Certificate
+		err   error
+	)
+
+	switch sig.SubFilter {
+	case SubFilterPKCS7Detached, SubFilterCAdES, SubFilterPKCS7SHA1, SubFilterRFC3161:
+		chain, err = sig.verifyCMS(data)
+	case SubFilterX509RSASHA1:
+		chain, err = sig.verifyX509RSASHA1(data)
+	default:
+		err = errors.Errorf("unsupported SubFilter: %s", sig.SubFilter)
+	}
+	if err != nil {
+		sig.problem("%v", err)
+	}
+
+	if len(chain) > 0 {
+		sig.Trusted = verifyChain(sig, chain, roots)
+	}
+}
+
+func verifyChain(sig *Signature, chain []*x509.Certificate, roots *x509.CertPool) bool {
+	if roots == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			sig.problem("%v", err)
+			return false
+		}
+		roots = pool
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if !sig.Time.IsZero() {
+		// Validate at signing time.
+		opts.CurrentTime = sig.Time
+	}
+
+	if _, err := chain[0].Verify(opts); err != nil {
+		sig.problem("%v", err)
+		return false
+	}
+
+	return true
+}
+
+func cryptoHash(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, nil
+	}
+	return 0, errors.Errorf("unsupported digest algorithm %s", oid)
+}
+
+func digest(h crypto.Hash, bb []byte) []byte {
+	hh := h.New()
+	hh.Write(bb)
+	return hh.Sum(nil)
+}
+
+func verifySignature(cert *x509.Certificate, alg asn1.ObjectIdentifier, h crypto.Hash, signed, sig []byte) error {
+	switch pub := cert.PublicKey.(type) {
+
+	case *rsa.PublicKey:
+		switch {
+		case alg.Equal(oidRSASSAPSS):
+			return rsa.VerifyPSS(pub, h, digest(h, signed), sig, nil)
+		case alg.Equal(oidRSAEncryption), alg.Equal(oidSHA1WithRSA), alg.Equal(oidSHA256WithRSA),
+			alg.Equal(oidSHA384WithRSA), alg.Equal(oidSHA512WithRSA):
+			return rsa.VerifyPKCS1v15(pub, h, digest(h, signed), sig)
+		}
+
+	case *ecdsa.PublicKey:
+		switch {
+		case alg.Equal(oidECPublicKey), alg.Equal(oidECDSAWithSHA1), alg.Equal(oidECDSAWithSHA256),
+			alg.Equal(oidECDSAWithSHA384), alg.Equal(oidECDSAWithSHA512):
+			if !ecdsa.VerifyASN1(pub, digest(h, signed), sig) {
+				return errors.New("ecdsa: verification error")
+			}
+			return nil
+		}
+
+	case ed25519.PublicKey:
+		if alg.Equal(oidEd25519) {
+			if !ed25519.Verify(pub, signed, sig) {
+				return errors.New("ed25519: verification error")
+			}
+			return nil
+		}
+	}
+
+	return errors.Errorf("unsupported signature algorithm %s", alg)
+}
+
+func signerCertificate(si *signerInfo, certs []*x509.Certificate) (*x509.Certificate, error) {
+	if si.SID.Class == asn1.ClassContextSpecific && si.SID.Tag == 0 {
+		// subjectKeyIdentifier
+		for _, cert := range certs {
+			if bytes.Equal(cert.SubjectKeyId, si.SID.Bytes) {
+				return cert, nil
+			}
+		}
+		return nil, errors.New("missing signer certificate")
+	}
+
+	var ias issuerAndSerialNumber
+	if _, err := asn1.Unmarshal(si.SID.FullBytes, &ias); err != nil {
+		return nil, errors.Wrap(err, "corrupt signer info")
+	}
+
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawIssuer, ias.Issuer.FullBytes) && cert.SerialNumber.Cmp(ias.SerialNumber) == 0 {
+			return cert, nil
+		}
+	}
+
+	return nil, errors.New("missing signer certificate")
+}
+
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time `asn1:"generalized"`
+}
+
+func parseSignedAttributes(si *signerInfo) ([]attribute, error) {
+	var attrs []attribute
+	if _, err := asn1.UnmarshalWithParams(si.SignedAttrs.FullBytes, &attrs, "set,tag:0"); err != nil {
+		return nil, errors.Wrap(err, "corrupt signed attributes")
+	}
+	return attrs, nil
+}
+
+// verifyContent checks the digest of the signed content against the data covered by the signature.
+func (sig *Signature) verifyContent(sd *signedData, data []byte) ([]byte, error) {
+	if len(sd.EncapContentInfo.EContent.Bytes) == 0 {
+		// detached
+		return data, nil
+	}
+
+	var content []byte
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent.Bytes, &content); err != nil {
+		return nil, errors.Wrap(err, "corrupt encapsulated content")
+	}
+
+	switch {
+
+	case sd.EncapContentInfo.EContentType.Equal(oidTSTInfo):
+		var tst tstInfo
+		if _, err := asn1.Unmarshal(content, &tst); err != nil {
+			return nil, errors.Wrap(err, "corrupt timestamp token")
+		}
+		h, err := cryptoHash(tst.MessageImprint.HashAlgorithm.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		sig.DigestOK = bytes.Equal(digest(h, data), tst.MessageImprint.HashedMessage)
+		sig.Time = tst.GenTime
+
+	default:
+		// adbe.pkcs7.sha1
+		sig.DigestOK = bytes.Equal(digest(crypto.SHA1, data), content)
+	}
+
+	if !sig.DigestOK {
+		sig.problem("digest mismatch")
+	}
+
+	return content, nil
+}
+
+func (sig *Signature) verifyCMS(data []byte) ([]*x509.Certificate, error) {
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(sig.contents, &ci); err != nil {
+		return nil, errors.Wrap(err, "corrupt CMS")
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		return nil, errors.New("CMS: missing signed data")
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, errors.Wrap(err, "corrupt CMS signed data")
+	}
+
+	if len(sd.SignerInfos) == 0 {
+		return nil, errors.New("CMS: missing signer info")
+	}
+	si := &sd.SignerInfos[0]
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "corrupt certificates")
+	}
+
+	cert, err := signerCertificate(si, certs)
+	if err != nil {
+		return nil, err
+	}
+	sig.Signer = cert
+
+	chain := []*x509.Certificate{cert}
+	for _, c := range certs {
+		if c != cert {
+			chain = append(chain, c)
+		}
+	}
+
+	h, err := cryptoHash(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return chain, err
+	}
+
+	content, err := sig.verifyContent(&sd, data)
+	if err != nil {
+		return chain, err
+	}
+
+	signed := digest(h, content)
+
+	if len(si.SignedAttrs.Bytes) > 0 {
+		attrs, err := parseSignedAttributes(si)
+		if err != nil {
+			return chain, err
+		}
+
+		var md []byte
+		for _, attr := range attrs {
+			switch {
+			case attr.Type.Equal(oidAttributeMessageDigest):
+				if _, err := asn1.Unmarshal(attr.Values.Bytes, &md); err != nil {
+					return chain, errors.Wrap(err, "corrupt message digest")
+				}
+			case attr.Type.Equal(oidAttributeSigningTime):
+				var t time.Time
+				if _, err := asn1.Unmarshal(attr.Values.Bytes, &t); err == nil && sig.SubFilter != SubFilterRFC3161 {
+					sig.Time = t
+				}
+			}
+		}
+
+		if !bytes.Equal(md, signed) {
+			if len(sd.EncapContentInfo.EContent.Bytes) == 0 {
+				sig.problem("digest mismatch")
+			} else {
+				sig.problem("message digest mismatch")
+				sig.DigestOK = false
+			}
+		} else if len(sd.EncapContentInfo.EContent.Bytes) == 0 {
+			sig.DigestOK = true
+		}
+
+		// The signature covers the DER encoding of the signed attributes.
+		if signed, err = asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: si.SignedAttrs.Bytes}); err != nil {
+			return chain, err
+		}
+	} else {
+		if len(sd.EncapContentInfo.EContent.Bytes) == 0 {
+			// The digest is implicitly checked by the signature.
+			sig.DigestOK = true
+		}
+		signed = content
+	}
+
+	if err := verifySignature(cert, si.SignatureAlgorithm.Algorithm, h, signed, si.Signature); err != nil {
+		if len(si.SignedAttrs.Bytes) == 0 {
+			sig.DigestOK = false
+		}
+		return chain, err
+	}
+	sig.SignatureOK = true
+
+	return chain, nil
+}
+
+func (sig *Signature) verifyX509RSASHA1(data []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for _, bb := range sig.certs {
+		cert, err := x509.ParseCertificate(bb)
+		if err != nil {
+			return nil, errors.Wrap(err, "corrupt certificate")
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("missing signer certificate")
+	}
+	sig.Signer = chain[0]
+
+	var s []byte
+	if _, err := asn1.Unmarshal(sig.contents, &s); err != nil {
+		return chain, errors.Wrap(err, "corrupt signature")
+	}
+
+	// The digest is implicitly checked by the signature.
+	pub, ok := chain[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return chain, errors.New("adbe.x509.rsa_sha1: missing RSA key")
+	}
+
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		if rsa.VerifyPKCS1v15(pub, h, digest(h, data), s) == nil {
+			sig.DigestOK, sig.SignatureOK = true, true
+			return chain, nil
+		}
+	}
+
+	return chain, errors.New("signature or digest mismatch")
+}
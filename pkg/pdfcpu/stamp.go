@@ -44,7 +44,6 @@ const stampWithBBox = false
 
 var (
 	errNoWatermark        = errors.New("pdfcpu: no watermarks found")
-	errCorruptOCGs        = errors.New("pdfcpu: OCProperties: corrupt OCGs element")
 	ErrUnsupportedVersion = errors.New("pdfcpu: PDF 2.0 unsupported for this operation")
 )
 
@@ -102,6 +101,7 @@ var wmParamMap = watermarkParamMap{
 	"diagonal":        parseDiagonal,
 	"fillcolor":       parseFillColor,
 	"fontname":        parseFontName,
+	"layer":           parseLayer,
 	"scriptname":      parseScriptName,
 	"margins":         parseMargins,
 	"mode":            parseRenderMode,
@@ -109,12 +109,14 @@ var wmParamMap = watermarkParamMap{
 	"opacity":         parseOpacity,
 	"points":          parseFontSize,
 	"position":        parsePositionAnchorWM,
+	"print":           parsePrint,
 	"rendermode":      parseRenderMode,
 	"rtl":             parseRightToLeft,
 	"rotation":        parseRotation,
 	"scalefactor":     parseScaleFactorWM,
 	"strokecolor":     parseStrokeColor,
 	"url":             parseURL,
+	"view":            parseView,
 }
 
 func parseTextHorAlignment(s string, wm *model.Watermark) error {
@@ -277,6 +279,45 @@ func parseRightToLeft(s string, wm *model.Watermark) error {
 	return nil
 }
 
+func parseLayer(s string, wm *model.Watermark) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return errors.New("pdfcpu: layer: please provide a name")
+	}
+	wm.Layer = s
+	return nil
+}
+
+func parseOnOff(s, param string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true", "t":
+		return true, nil
+	case "off", "false", "f":
+		return false, nil
+	}
+	return false, errors.Errorf("pdfcpu: %s, please provide one of: on/off true/false t/f", param)
+}
+
+func parsePrint(s string, wm *model.Watermark) error {
+	on, err := parseOnOff(s, "print")
+	if err != nil {
+		return err
+	}
+	wm.PrintOff = !on
+	wm.UserLayerUsage = true
+	return nil
+}
+
+func parseView(s string, wm *model.Watermark) error {
+	on, err := parseOnOff(s, "view")
+	if err != nil {
+		return err
+	}
+	wm.ViewOff = !on
+	wm.UserLayerUsage = true
+	return nil
+}
+
 func parseStrokeColor(s string, wm *model.Watermark) error {
 	c, err := color.ParseColor(s)
 	if err != nil {
@@ -750,14 +791,42 @@ func createImageResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
 	return err
 }
 
-func createFontResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
-	// TODO Reuse font dict.
-	if font.IsUserFont(wm.FontName) {
+// resolveFallbackFonts narrows down the fallback fonts of wm to the ones actually used by its text.
+func resolveFallbackFonts(ctx *model.Context, wm *model.Watermark) {
+	fallbackFonts := wm.FallbackFonts
+	if fallbackFonts == nil {
+		fallbackFonts = ctx.Configuration.FallbackFonts
+	}
+	wm.FallbackFonts = model.UsedFallbackFonts(wm.TextString, wm.FontName, fallbackFonts)
+	wm.FallbackFontRes = map[string]*types.IndirectRef{}
+}
+
+// prerenderText collects the glyphs used by wm for font subsetting.
+func prerenderText(ctx *model.Context, wm *model.Watermark) {
+	if font.IsUserFont(wm.FontName) || len(wm.FallbackFonts) > 0 {
 		td, _ := setupTextDescriptor(*wm, "", 123456789, 0)
 		model.WriteMultiLine(ctx.XRefTable, new(bytes.Buffer), types.RectForFormat("A4"), nil, td)
 	}
+}
+
+func createFontResForWM(ctx *model.Context, wm *model.Watermark) (err error) {
+	// TODO Reuse font dict.
+	resolveFallbackFonts(ctx, wm)
+	prerenderText(ctx, wm)
 	wm.Font, err = pdffont.EnsureFontDict(ctx.XRefTable, wm.FontName, "", wm.ScriptName, false, nil)
-	return err
+	if err != nil {
+		return err
+	}
+	for _, fontName := range wm.FallbackFonts {
+		if wm.FallbackFontRes[fontName], err = pdffont.EnsureFontDict(ctx.XRefTable, fontName, "", "", false, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fallbackFontKey(i int) string {
+	return fmt.Sprintf("F%d", i+2)
 }
 
 func createResourcesForWM(ctx *model.Context, wm *model.Watermark) error {
@@ -770,62 +839,98 @@ func createResourcesForWM(ctx *model.Context, wm *model.Watermark) error {
 	return createFontResForWM(ctx, wm)
 }
 
-func ensureOCG(ctx *model.Context, onTop bool) (*types.IndirectRef, error) {
-	name := "Background"
-	subt := "BG"
-	if onTop {
-		name = "Watermark"
-		subt = "FG"
+func ocState(on bool) types.Name {
+	if on {
+		return types.Name("ON")
+	}
+	return types.Name("OFF")
+}
+
+func ocgUsageDict(subt string, view, print bool) types.Dict {
+	return types.Dict(
+		map[string]types.Object{
+			"PageElement": types.Dict(map[string]types.Object{"Subtype": types.Name(subt)}),
+			"View":        types.Dict(map[string]types.Object{"ViewState": ocState(view)}),
+			"Print":       types.Dict(map[string]types.Object{"PrintState": ocState(print)}),
+			"Export":      types.Dict(map[string]types.Object{"ExportState": types.Name("ON")}),
+		},
+	)
+}
+
+func ocgNameAndSubtype(wm *model.Watermark) (string, string) {
+	name, subt := "Background", "BG"
+	if wm.OnTop {
+		name, subt = "Watermark", "FG"
+	}
+	if wm.Layer != "" {
+		name = wm.Layer
 	}
+	return name, subt
+}
+
+func ensureOCG(ctx *model.Context, wm *model.Watermark) (*types.IndirectRef, error) {
+	name, subt := ocgNameAndSubtype(wm)
 
 	d := types.Dict(
 		map[string]types.Object{
-			"Name": types.StringLiteral(name),
-			"Type": types.Name("OCG"),
-			"Usage": types.Dict(
-				map[string]types.Object{
-					"PageElement": types.Dict(map[string]types.Object{"Subtype": types.Name(subt)}),
-					"View":        types.Dict(map[string]types.Object{"ViewState": types.Name("ON")}),
-					"Print":       types.Dict(map[string]types.Object{"PrintState": types.Name("ON")}),
-					"Export":      types.Dict(map[string]types.Object{"ExportState": types.Name("ON")}),
-				},
-			),
+			"Name":  types.StringLiteral(name),
+			"Type":  types.Name("OCG"),
+			"Usage": ocgUsageDict(subt, true, true),
 		},
 	)
 
 	return ctx.IndRefForNewObject(d)
 }
 
-func prepareOCPropertiesInRoot(ctx *model.Context, onTop bool) (*types.IndirectRef, error) {
-	rootDict, err := ctx.Catalog()
+// ensureLayerForWM returns the named optional content group for wm honoring its view and print settings.
+// The usage of an existing layer only changes if view or print is set.
+func ensureLayerForWM(ctx *model.Context, wm *model.Watermark) (*types.IndirectRef, error) {
+	name, subt := ocgNameAndSubtype(wm)
+
+	ir, err := findLayer(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
-	if o, ok := rootDict.Find("OCProperties"); ok {
-
-		d, err := ctx.DereferenceDict(o)
-		if err != nil {
+	if ir == nil {
+		if ir, err = AddLayer(ctx, name, !wm.ViewOff); err != nil {
 			return nil, err
 		}
-
-		o, found := d.Find("OCGs")
-		if found {
-			a, err := ctx.DereferenceArray(o)
-			if err != nil {
-				return nil, errCorruptOCGs
-			}
-			if len(a) > 0 {
-				ir, ok := a[0].(types.IndirectRef)
-				if !ok {
-					return nil, errCorruptOCGs
-				}
-				return &ir, nil
-			}
+	} else {
+		if !wm.UserLayerUsage && !wm.ViewOff && !wm.PrintOff {
+			// Keep the usage of an existing layer.
+			return ir, nil
 		}
+		if err := SetLayerVisibility(ctx, []string{name}, !wm.ViewOff); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := ctx.DereferenceDict(*ir)
+	if err != nil {
+		return nil, err
+	}
+	d["Usage"] = ocgUsageDict(subt, !wm.ViewOff, !wm.PrintOff)
+
+	if err := addUsageApplications(ctx, *ir); err != nil {
+		return nil, err
+	}
+
+	return ir, nil
+}
+
+// prepareOCPropertiesInRoot returns the optional content group for wm.
+func prepareOCPropertiesInRoot(ctx *model.Context, wm *model.Watermark) (*types.IndirectRef, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
 	}
 
-	ir, err := ensureOCG(ctx, onTop)
+	if _, ok := rootDict.Find("OCProperties"); ok || wm.Layer != "" || wm.ViewOff || wm.PrintOff {
+		return ensureLayerForWM(ctx, wm)
+	}
+
+	ir, err := ensureOCG(ctx, wm)
 	if err != nil {
 		return nil, err
 	}
@@ -888,9 +993,14 @@ func createFormResDict(ctx *model.Context, pageNr int, wm *model.Watermark) (*ty
 		return ctx.IndRefForNewObject(d)
 	}
 
+	fontDict := types.Dict(map[string]types.Object{"F1": *wm.Font})
+	for i, fontName := range wm.FallbackFonts {
+		fontDict[fallbackFontKey(i)] = *wm.FallbackFontRes[fontName]
+	}
+
 	d := types.Dict(
 		map[string]types.Object{
-			"Font":    types.Dict(map[string]types.Object{"F1": *wm.Font}),
+			"Font":    fontDict,
 			"ProcSet": types.NewNameArray("PDF", "Text", "ImageB", "ImageC", "ImageI"),
 		},
 	)
@@ -959,6 +1069,15 @@ func setupTextDescriptor(wm model.Watermark, timestampFormat string, pageNr, pag
 	td, unique := textDescriptor(wm, timestampFormat, pageNr, pageCount)
 	td.X, td.Y, td.HAlign, td.VAlign, td.FontKey = x, y, hAlign, vAlign, "F1"
 
+	// Set fallback fonts.
+	if len(wm.FallbackFonts) > 0 {
+		td.FallbackFonts = wm.FallbackFonts
+		td.FallbackFontKeys = map[string]string{}
+		for i, fontName := range wm.FallbackFonts {
+			td.FallbackFontKeys[fontName] = fallbackFontKey(i)
+		}
+	}
+
 	// Set right to left rendering.
 	td.RTL = wm.RTL
 
@@ -1458,16 +1577,16 @@ func createResourcesForPageNr(
 
 	// Text watermark
 
-	if font.IsUserFont(wm.FontName) {
-		td, _ := setupTextDescriptor(*wm, "", 123456789, 0)
-		model.WriteMultiLine(ctx.XRefTable, new(bytes.Buffer), types.RectForFormat("A4"), nil, td)
-	}
+	resolveFallbackFonts(ctx, wm)
+	prerenderText(ctx, wm)
 
-	pageSet, found := fm[wm.FontName]
-	if !found {
-		fm[wm.FontName] = types.IntSet{pageNr: true}
-	} else {
-		pageSet[pageNr] = true
+	for _, fontName := range append([]string{wm.FontName}, wm.FallbackFonts...) {
+		pageSet, found := fm[fontName]
+		if !found {
+			fm[fontName] = types.IntSet{pageNr: true}
+		} else {
+			pageSet[pageNr] = true
+		}
 	}
 
 	return nil
@@ -1509,19 +1628,34 @@ func createResourcesForWMSliceMap(
 	return fm, nil
 }
 
+// setFontResForWM assigns the font resource ir to text watermarks using fontName.
+func setFontResForWM(wm *model.Watermark, fontName string, ir *types.IndirectRef) {
+	if !wm.IsText() {
+		return
+	}
+	if wm.FontName == fontName {
+		wm.Font = ir
+	}
+	if types.MemberOf(fontName, wm.FallbackFonts) {
+		wm.FallbackFontRes[fontName] = ir
+	}
+}
+
 // AddWatermarksMap adds watermarks in m to corresponding pages.
 func AddWatermarksMap(ctx *model.Context, m map[int]*model.Watermark) error {
 	var (
+		wm0     *model.Watermark
 		onTop   bool
 		opacity float64
 	)
 	for _, wm := range m {
+		wm0 = wm
 		onTop = wm.OnTop
 		opacity = wm.Opacity
 		break
 	}
 
-	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop)
+	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, wm0)
 	if err != nil {
 		return err
 	}
@@ -1546,10 +1680,7 @@ func AddWatermarksMap(ctx *model.Context, m map[int]*model.Watermark) error {
 			if !v {
 				continue
 			}
-			wm := m[pageNr]
-			if wm.IsText() && wm.FontName == fontName {
-				m[pageNr].Font = ir
-			}
+			setFontResForWM(m[pageNr], fontName, ir)
 		}
 	}
 
@@ -1566,16 +1697,18 @@ func AddWatermarksMap(ctx *model.Context, m map[int]*model.Watermark) error {
 // AddWatermarksSliceMap adds watermarks in m to corresponding pages.
 func AddWatermarksSliceMap(ctx *model.Context, m map[int][]*model.Watermark) error {
 	var (
+		wm0     *model.Watermark
 		onTop   bool
 		opacity float64
 	)
 	for _, wms := range m {
+		wm0 = wms[0]
 		onTop = wms[0].OnTop
 		opacity = wms[0].Opacity
 		break
 	}
 
-	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, onTop)
+	ocgIndRef, err := prepareOCPropertiesInRoot(ctx, wm0)
 	if err != nil {
 		return err
 	}
@@ -1601,9 +1734,7 @@ func AddWatermarksSliceMap(ctx *model.Context, m map[int][]*model.Watermark) err
 				continue
 			}
 			for _, wm := range m[pageNr] {
-				if wm.IsText() && wm.FontName == fontName {
-					wm.Font = ir
-				}
+				setFontResForWM(wm, fontName, ir)
 			}
 		}
 	}
@@ -1626,7 +1757,7 @@ func AddWatermarks(ctx *model.Context, selectedPages types.IntSet, wm *model.Wat
 		log.Debug.Printf("AddWatermarks wm:\n%s\n", wm)
 	}
 	var err error
-	if wm.Ocg, err = prepareOCPropertiesInRoot(ctx, wm.OnTop); err != nil {
+	if wm.Ocg, err = prepareOCPropertiesInRoot(ctx, wm); err != nil {
 		return err
 	}
 
@@ -1687,6 +1818,31 @@ func removeForms(ctx *model.Context, d types.Dict, ids []string, i int) error {
 	return removeResDictEntry(ctx, d, "XObject", ids, i)
 }
 
+const watermarkArtifact = "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC"
+
+// artifactResources returns the ids of the extGState and the form used by the watermark artifact t.
+func artifactResources(t string) (extGState, form string) {
+	i := strings.Index(t, "/GS")
+	if i > 0 {
+		j := i + 3
+		k := strings.Index(t[j:], " gs")
+		if k > 0 {
+			extGState = "GS" + t[j:j+k]
+		}
+	}
+
+	i = strings.Index(t, "/Fm")
+	if i > 0 {
+		j := i + 3
+		k := strings.Index(t[j:], " Do")
+		if k > 0 {
+			form = "Fm" + t[j:j+k]
+		}
+	}
+
+	return extGState, form
+}
+
 func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string, forms []string, err error) {
 	err = sd.Decode()
 	if err == filter.ErrUnsupportedFilter {
@@ -1705,7 +1861,7 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 
 	for {
 		s := string(sd.Content)
-		beg := strings.Index(s, "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
+		beg := strings.Index(s, watermarkArtifact)
 		if beg < 0 {
 			break
 		}
@@ -1716,24 +1872,12 @@ func removeArtifacts(sd *types.StreamDict, i int) (ok bool, extGStates []string,
 		}
 
 		// Check for usage of resources.
-		t := s[beg : beg+end]
-
-		i := strings.Index(t, "/GS")
-		if i > 0 {
-			j := i + 3
-			k := strings.Index(t[j:], " gs")
-			if k > 0 {
-				extGStates = append(extGStates, "GS"+t[j:j+k])
-			}
+		gs, fm := artifactResources(s[beg : beg+end])
+		if gs != "" {
+			extGStates = append(extGStates, gs)
 		}
-
-		i = strings.Index(t, "/Fm")
-		if i > 0 {
-			j := i + 3
-			k := strings.Index(t[j:], " Do")
-			if k > 0 {
-				forms = append(forms, "Fm"+t[j:j+k])
-			}
+		if fm != "" {
+			forms = append(forms, fm)
 		}
 
 		// TODO Remove whitespace until 0x0a
@@ -1925,37 +2069,6 @@ func locateOCGs(ctx *model.Context) (types.Array, error) {
 	return ctx.DereferenceArray(o)
 }
 
-func detectStampOCG(ctx *model.Context, arr types.Array) error {
-	for _, o := range arr {
-
-		d, err := ctx.DereferenceDict(o)
-		if err != nil {
-			return err
-		}
-
-		if o == nil {
-			continue
-		}
-
-		if *d.Type() != "OCG" {
-			continue
-		}
-
-		n := d.StringEntry("Name")
-		if n == nil {
-			continue
-		}
-
-		if *n != "Background" && *n != "Watermark" {
-			continue
-		}
-
-		return nil
-	}
-
-	return errNoWatermark
-}
-
 func removePageWatermarks(ctx *model.Context, selectedPages types.IntSet) error {
 	var removed bool
 
@@ -1988,28 +2101,108 @@ func RemoveWatermarks(ctx *model.Context, selectedPages types.IntSet) error {
 		log.Debug.Printf("RemoveWatermarks\n")
 	}
 
-	arr, err := locateOCGs(ctx)
-	if err != nil {
+	if err := DetectWatermarks(ctx); err != nil {
 		return err
 	}
 
-	if err := detectStampOCG(ctx, arr); err != nil {
-		return err
+	if !ctx.Watermarked {
+		return errNoWatermark
 	}
 
 	return removePageWatermarks(ctx, selectedPages)
 }
 
-func detectArtifacts(sd *types.StreamDict) (bool, error) {
+// artifactForms returns the ids of the forms painted by the watermark artifacts of sd.
+func artifactForms(sd *types.StreamDict) ([]string, error) {
 	if err := sd.Decode(); err != nil {
-		return false, err
+		return nil, err
 	}
+
+	var forms []string
+
 	// Watermarks may begin or end the content stream.
-	i := strings.Index(string(sd.Content), "/Artifact <</Subtype /Watermark /Type /Pagination >>BDC")
-	return i >= 0, nil
+	s := string(sd.Content)
+	for {
+		beg := strings.Index(s, watermarkArtifact)
+		if beg < 0 {
+			break
+		}
+		s = s[beg:]
+		end := strings.Index(s, "EMC")
+		if end < 0 {
+			break
+		}
+		if _, fm := artifactResources(s[:end]); fm != "" {
+			forms = append(forms, fm)
+		}
+		s = s[end:]
+	}
+
+	return forms, nil
+}
+
+// isStampForm returns true if the form o belongs to one of the optional content groups ocgs, see createForm.
+func isStampForm(ctx *model.Context, o types.Object, ocgs types.Array) (bool, error) {
+	sd, _, err := ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return false, err
+	}
+
+	ir := sd.IndirectRefEntry("OC")
+	if ir == nil {
+		return false, nil
+	}
+
+	for _, o := range ocgs {
+		if ir1, ok := o.(types.IndirectRef); ok && ir1.ObjectNumber == ir.ObjectNumber {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// findStampForms returns true if one of forms in the resources of pageDict is a stamp form.
+func findStampForms(ctx *model.Context, pageDict types.Dict, forms []string, ocgs types.Array) (bool, error) {
+	if len(forms) == 0 {
+		return false, nil
+	}
+
+	o, found := pageDict.Find("Resources")
+	if !found {
+		return false, nil
+	}
+
+	resDict, err := ctx.DereferenceDict(o)
+	if err != nil || resDict == nil {
+		return false, err
+	}
+
+	o, found = resDict.Find("XObject")
+	if !found {
+		return false, nil
+	}
+
+	xObjDict, err := ctx.DereferenceDict(o)
+	if err != nil || xObjDict == nil {
+		return false, err
+	}
+
+	for _, id := range forms {
+		o, found := xObjDict.Find(id)
+		if !found {
+			continue
+		}
+		ok, err := isStampForm(ctx, o, ocgs)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
 }
 
-func findPageWatermarks(ctx *model.Context, pageDictIndRef *types.IndirectRef) (bool, error) {
+func findPageWatermarks(ctx *model.Context, pageDictIndRef *types.IndirectRef, ocgs types.Array) (bool, error) {
 	d, err := ctx.DereferenceDict(*pageDictIndRef)
 	if err != nil {
 		return false, err
@@ -2030,47 +2223,39 @@ func findPageWatermarks(ctx *model.Context, pageDictIndRef *types.IndirectRef) (
 		o = entry.Object
 	}
 
+	var forms []string
+
 	switch o := o.(type) {
 
 	case types.StreamDict:
-		return detectArtifacts(&o)
-
-	case types.Array:
-		// Get stream dict for first element.
-		if len(o) == 0 {
-			return false, nil
-		}
-		o1 := o[0]
-		ir, _ := o1.(types.IndirectRef)
-		objNr := ir.ObjectNumber.Value()
-		genNr := ir.GenerationNumber.Value()
-		entry, _ := ctx.FindTableEntry(objNr, genNr)
-		sd, _ := (entry.Object).(types.StreamDict)
-		ok, err := detectArtifacts(&sd)
-		if err != nil {
+		if forms, err = artifactForms(&o); err != nil {
 			return false, err
 		}
-		if ok {
-			return true, nil
-		}
 
-		if len(o) > 1 {
-			// Get stream dict for last element.
-			o1 := o[len(o)-1]
+	case types.Array:
+		// Watermarks live in the first or last content stream.
+		for i, o1 := range o {
+			if i > 0 && i < len(o)-1 {
+				continue
+			}
 			ir, _ := o1.(types.IndirectRef)
-			objNr = ir.ObjectNumber.Value()
+			objNr := ir.ObjectNumber.Value()
 			genNr := ir.GenerationNumber.Value()
 			entry, _ := ctx.FindTableEntry(objNr, genNr)
 			sd, _ := (entry.Object).(types.StreamDict)
-			return detectArtifacts(&sd)
+			ff, err := artifactForms(&sd)
+			if err != nil {
+				return false, err
+			}
+			forms = append(forms, ff...)
 		}
 
 	}
 
-	return false, nil
+	return findStampForms(ctx, d, forms, ocgs)
 }
 
-func detectPageTreeWatermarks(ctx *model.Context, root *types.IndirectRef) error {
+func detectPageTreeWatermarks(ctx *model.Context, root *types.IndirectRef, ocgs types.Array) error {
 	d, err := ctx.DereferenceDict(*root)
 	if err != nil {
 		return err
@@ -2106,12 +2291,12 @@ func detectPageTreeWatermarks(ctx *model.Context, root *types.IndirectRef) error
 
 		case "Pages":
 			// Recurse over sub pagetree.
-			if err := detectPageTreeWatermarks(ctx, &ir); err != nil {
+			if err := detectPageTreeWatermarks(ctx, &ir, ocgs); err != nil {
 				return err
 			}
 
 		case "Page":
-			found, err := findPageWatermarks(ctx, &ir)
+			found, err := findPageWatermarks(ctx, &ir, ocgs)
 			if err != nil {
 				return err
 			}
@@ -2129,58 +2314,26 @@ func detectPageTreeWatermarks(ctx *model.Context, root *types.IndirectRef) error
 // DetectPageTreeWatermarks checks xRefTable's page tree for watermarks
 // and records the result to xRefTable.Watermarked.
 func DetectPageTreeWatermarks(ctx *model.Context) error {
-	root, err := ctx.Pages()
-	if err != nil {
-		return err
-	}
-	return detectPageTreeWatermarks(ctx, root)
-}
-
-// DetectWatermarks checks ctx for watermarks
-// and records the result to xRefTable.Watermarked.
-func DetectWatermarks(ctx *model.Context) error {
-	a, err := locateOCGs(ctx)
+	ocgs, err := locateOCGs(ctx)
 	if err != nil {
 		if err == errNoWatermark {
-			ctx.Watermarked = false
 			return nil
 		}
 		return err
 	}
 
-	found := false
-
-	for _, o := range a {
-		d, err := ctx.DereferenceDict(o)
-		if err != nil {
-			return err
-		}
-
-		if o == nil {
-			continue
-		}
-
-		if *d.Type() != "OCG" {
-			continue
-		}
-
-		n := d.StringEntry("Name")
-		if n == nil {
-			continue
-		}
-
-		if *n != "Background" && *n != "Watermark" {
-			continue
-		}
-
-		found = true
-		break
+	root, err := ctx.Pages()
+	if err != nil {
+		return err
 	}
 
-	if !found {
-		ctx.Watermarked = false
-		return nil
-	}
+	return detectPageTreeWatermarks(ctx, root, ocgs)
+}
 
+// DetectWatermarks checks ctx for watermarks
+// and records the result to xRefTable.Watermarked.
+// Watermarks are stamps painting a form that belongs to an optional content group.
+func DetectWatermarks(ctx *model.Context) error {
+	ctx.Watermarked = false
 	return DetectPageTreeWatermarks(ctx)
 }
@@ -0,0 +1,578 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pdfcpu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// Guard against cyclic or excessively deep structure trees.
+const maxStructDepth = 100
+
+// MarkedContent references a marked-content sequence by MCID.
+type MarkedContent struct {
+	Page   int `json:"page,omitempty"`
+	MCID   int `json:"mcid"`
+	Stream int `json:"stream,omitempty"` // object number of a content stream other than the page content.
+}
+
+// StructObject references a PDF object like an annotation or XObject.
+type StructObject struct {
+	Page  int    `json:"page,omitempty"`
+	ObjNr int    `json:"obj"`
+	Type  string `json:"type,omitempty"`
+}
+
+// StructElem represents a structure element of a tagged PDF.
+type StructElem struct {
+	Type       string                       `json:"type"`
+	Role       string                       `json:"role,omitempty"` // standard structure type mapped to by the role map.
+	ObjNr      int                          `json:"obj,omitempty"`
+	ID         string                       `json:"id,omitempty"`
+	Title      string                       `json:"title,omitempty"`
+	Lang       string                       `json:"lang,omitempty"`
+	Alt        string                       `json:"alt,omitempty"`
+	ActualText string                       `json:"actualText,omitempty"`
+	Expansion  string                       `json:"expansion,omitempty"`
+	Page       int                          `json:"page,omitempty"`
+	Classes    []string                     `json:"classes,omitempty"`
+	Attributes map[string]map[string]string `json:"attributes,omitempty"` // attributes by owner.
+	Content    []MarkedContent              `json:"content,omitempty"`
+	Objects    []StructObject               `json:"objects,omitempty"`
+	Kids       []*StructElem                `json:"kids,omitempty"`
+}
+
+// StructTree represents the logical structure of a tagged PDF.
+type StructTree struct {
+	Header   Header            `json:"header"`
+	Marked   bool              `json:"marked"`
+	Lang     string            `json:"lang,omitempty"`
+	RoleMap  map[string]string `json:"roleMap,omitempty"`
+	Elements []*StructElem     `json:"elements"`
+}
+
+type structReader struct {
+	ctx     *model.Context
+	pageNrs map[int]int // page number by page object number.
+	roleMap types.Dict
+	visited types.IntSet
+}
+
+func newStructReader(ctx *model.Context) (*structReader, error) {
+	sr := &structReader{ctx: ctx, pageNrs: map[int]int{}, visited: types.IntSet{}}
+
+	for i := 1; i <= ctx.PageCount; i++ {
+		_, indRef, _, err := ctx.PageDict(i, false)
+		if err != nil {
+			return nil, err
+		}
+		if indRef != nil {
+			sr.pageNrs[indRef.ObjectNumber.Value()] = i
+		}
+	}
+
+	return sr, nil
+}
+
+func (sr *structReader) pageNr(o types.Object) int {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		return sr.pageNrs[indRef.ObjectNumber.Value()]
+	}
+	return 0
+}
+
+func (sr *structReader) text(d types.Dict, key string) string {
+	o, found := d.Find(key)
+	if !found {
+		return ""
+	}
+	s, err := sr.ctx.DereferenceText(o)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+func (sr *structReader) value(o types.Object) string {
+	o, err := sr.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return ""
+	}
+
+	switch o := o.(type) {
+	case types.Name:
+		return o.Value()
+	case types.Float:
+		return strconv.FormatFloat(o.Value(), 'f', -1, 64)
+	case types.StringLiteral, types.HexLiteral:
+		s, err := model.Text(o)
+		if err == nil {
+			return s
+		}
+	}
+
+	return o.PDFString()
+}
+
+// standardType returns the structure type s is mapped to by the role map.
+func (sr *structReader) standardType(s string) string {
+	s1 := s
+	for i := 0; i < maxStructDepth; i++ {
+		n := sr.roleMap.NameEntry(s1)
+		if n == nil || *n == s1 {
+			break
+		}
+		s1 = *n
+	}
+	if s1 == s {
+		return ""
+	}
+	return s1
+}
+
+func (sr *structReader) addAttributes(e *StructElem, o types.Object) {
+	o, err := sr.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return
+	}
+
+	switch o := o.(type) {
+
+	case types.Dict:
+		owner := "unknown"
+		if n := o.NameEntry("O"); n != nil {
+			owner = *n
+		}
+		for k, v := range o {
+			if k == "O" {
+				continue
+			}
+			if e.Attributes == nil {
+				e.Attributes = map[string]map[string]string{}
+			}
+			if e.Attributes[owner] == nil {
+				e.Attributes[owner] = map[string]string{}
+			}
+			e.Attributes[owner][k] = sr.value(v)
+		}
+
+	case types.Array:
+		// Attribute objects optionally followed by revision numbers.
+		for _, o1 := range o {
+			if _, ok := o1.(types.Integer); !ok {
+				sr.addAttributes(e, o1)
+			}
+		}
+	}
+}
+
+func (sr *structReader) addClasses(e *StructElem, o types.Object) {
+	o, err := sr.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return
+	}
+
+	switch o := o.(type) {
+
+	case types.Name:
+		e.Classes = append(e.Classes, o.Value())
+
+	case types.Array:
+		// Class names optionally followed by revision numbers.
+		for _, o1 := range o {
+			if n, ok := o1.(types.Name); ok {
+				e.Classes = append(e.Classes, n.Value())
+			}
+		}
+	}
+}
+
+func (sr *structReader) objectType(o types.Object) string {
+	d, err := sr.ctx.DereferenceDict(o)
+	if err != nil || d == nil {
+		return ""
+	}
+	if n := d.NameEntry("Subtype"); n != nil {
+		return *n
+	}
+	if t := d.Type(); t != nil {
+		return *t
+	}
+	return ""
+}
+
+func (sr *structReader) addKid(e *StructElem, o types.Object, pageNr, depth int) error {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		objNr := indRef.ObjectNumber.Value()
+		if sr.visited[objNr] {
+			return nil
+		}
+		sr.visited[objNr] = true
+	}
+
+	o1, err := sr.ctx.Dereference(o)
+	if err != nil || o1 == nil {
+		return err
+	}
+
+	switch o1 := o1.(type) {
+
+	case types.Integer:
+		if e != nil {
+			e.Content = append(e.Content, MarkedContent{Page: pageNr, MCID: o1.Value()})
+		}
+
+	case types.Dict:
+		t := o1.Type()
+
+		if t != nil && *t == "MCR" {
+			mcid := o1.IntEntry("MCID")
+			if mcid == nil || e == nil {
+				return nil
+			}
+			mc := MarkedContent{Page: pageNr, MCID: *mcid}
+			if pg := sr.pageNr(o1["Pg"]); pg > 0 {
+				mc.Page = pg
+			}
+			if indRef := o1.IndirectRefEntry("Stm"); indRef != nil {
+				mc.Stream = indRef.ObjectNumber.Value()
+			}
+			e.Content = append(e.Content, mc)
+			return nil
+		}
+
+		if t != nil && *t == "OBJR" {
+			indRef := o1.IndirectRefEntry("Obj")
+			if indRef == nil || e == nil {
+				return nil
+			}
+			so := StructObject{Page: pageNr, ObjNr: indRef.ObjectNumber.Value(), Type: sr.objectType(*indRef)}
+			if pg := sr.pageNr(o1["Pg"]); pg > 0 {
+				so.Page = pg
+			}
+			e.Objects = append(e.Objects, so)
+			return nil
+		}
+
+		kid, err := sr.structElem(o1, o, pageNr, depth+1)
+		if err != nil || kid == nil {
+			return err
+		}
+		e.Kids = append(e.Kids, kid)
+	}
+
+	return nil
+}
+
+func (sr *structReader) addKids(e *StructElem, o types.Object, pageNr, depth int) error {
+	o, err := sr.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return err
+	}
+
+	switch o := o.(type) {
+
+	case types.Integer:
+		e.Content = append(e.Content, MarkedContent{Page: pageNr, MCID: o.Value()})
+
+	case types.Array:
+		for _, o1 := range o {
+			if err := sr.addKid(e, o1, pageNr, depth); err != nil {
+				return err
+			}
+		}
+
+	case types.Dict:
+		return sr.addKid(e, o, pageNr, depth)
+	}
+
+	return nil
+}
+
+func (sr *structReader) structElem(d types.Dict, o types.Object, pageNr, depth int) (*StructElem, error) {
+	if depth > maxStructDepth {
+		return nil, errors.New("pdfcpu: structure tree too deep")
+	}
+
+	e := &StructElem{}
+
+	if n := d.NameEntry("S"); n != nil {
+		e.Type = *n
+		e.Role = sr.standardType(*n)
+	}
+
+	if indRef, ok := o.(types.IndirectRef); ok {
+		e.ObjNr = indRef.ObjectNumber.Value()
+	}
+
+	e.ID = sr.text(d, "ID")
+	e.Title = sr.text(d, "T")
+	e.Lang = sr.text(d, "Lang")
+	e.Alt = sr.text(d, "Alt")
+	e.ActualText = sr.text(d, "ActualText")
+	e.Expansion = sr.text(d, "E")
+
+	if pg := sr.pageNr(d["Pg"]); pg > 0 {
+		e.Page = pg
+		pageNr = pg
+	}
+
+	if o, found := d.Find("A"); found {
+		sr.addAttributes(e, o)
+	}
+
+	if o, found := d.Find("C"); found {
+		sr.addClasses(e, o)
+	}
+
+	if o, found := d.Find("K"); found {
+		if err := sr.addKids(e, o, pageNr, depth); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// StructureTree returns the logical structure of ctx.
+func StructureTree(ctx *model.Context) (*StructTree, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ctx.DereferenceDict(rootDict["StructTreeRoot"])
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, nil
+	}
+
+	sr, err := newStructReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &StructTree{}
+
+	if d1, err := ctx.DereferenceDict(rootDict["MarkInfo"]); err == nil && d1 != nil {
+		if b := d1.BooleanEntry("Marked"); b != nil {
+			st.Marked = *b
+		}
+	}
+
+	st.Lang = sr.text(rootDict, "Lang")
+
+	if sr.roleMap, err = ctx.DereferenceDict(d["RoleMap"]); err != nil {
+		return nil, err
+	}
+
+	for k, v := range sr.roleMap {
+		if st.RoleMap == nil {
+			st.RoleMap = map[string]string{}
+		}
+		st.RoleMap[k] = sr.value(v)
+	}
+
+	o, found := d.Find("K")
+	if !found {
+		return st, nil
+	}
+
+	// The structure tree root is not a structure element itself.
+	root := &StructElem{}
+	if err := sr.addKids(root, o, 0, 0); err != nil {
+		return nil, err
+	}
+	st.Elements = root.Kids
+
+	return st, nil
+}
+
+func intList(ii []int) string {
+	ss := make([]string, len(ii))
+	for i, v := range ii {
+		ss[i] = strconv.Itoa(v)
+	}
+	return strings.Join(ss, ",")
+}
+
+func (e *StructElem) listEntry() string {
+	var sb strings.Builder
+
+	sb.WriteString(e.Type)
+	if e.Role != "" {
+		sb.WriteString(" -> " + e.Role)
+	}
+
+	add := func(k, v string) {
+		if v != "" {
+			fmt.Fprintf(&sb, " %s=%q", k, v)
+		}
+	}
+
+	add("id", e.ID)
+	add("title", e.Title)
+	add("lang", e.Lang)
+	add("alt", e.Alt)
+	add("actualText", e.ActualText)
+	add("expansion", e.Expansion)
+
+	if len(e.Classes) > 0 {
+		fmt.Fprintf(&sb, " classes=%s", strings.Join(e.Classes, ","))
+	}
+
+	owners := make([]string, 0, len(e.Attributes))
+	for owner := range e.Attributes {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		m := e.Attributes[owner]
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&sb, " %s/%s=%s", owner, k, m[k])
+		}
+	}
+
+	if e.Page > 0 {
+		fmt.Fprintf(&sb, " page=%d", e.Page)
+	}
+
+	// Group MCIDs by page and stream.
+	var mcids []int
+	var page, stream int
+	flush := func() {
+		if len(mcids) == 0 {
+			return
+		}
+		if stream > 0 {
+			fmt.Fprintf(&sb, " mcid=%s(p%d,obj#%d)", intList(mcids), page, stream)
+		} else if page != e.Page {
+			fmt.Fprintf(&sb, " mcid=%s(p%d)", intList(mcids), page)
+		} else {
+			fmt.Fprintf(&sb, " mcid=%s", intList(mcids))
+		}
+		mcids = nil
+	}
+	for _, mc := range e.Content {
+		if mc.Page != page || mc.Stream != stream {
+			flush()
+			page, stream = mc.Page, mc.Stream
+		}
+		mcids = append(mcids, mc.MCID)
+	}
+	flush()
+
+	for _, so := range e.Objects {
+		fmt.Fprintf(&sb, " obj#%d", so.ObjNr)
+		if so.Type != "" {
+			fmt.Fprintf(&sb, "(%s)", so.Type)
+		}
+		if so.Page > 0 && so.Page != e.Page {
+			fmt.Fprintf(&sb, "(p%d)", so.Page)
+		}
+	}
+
+	return sb.String()
+}
+
+func structElemList(ee []*StructElem, level int) []string {
+	pre := strings.Repeat("    ", level)
+	ss := []string{}
+	for _, e := range ee {
+		ss = append(ss, pre+e.listEntry())
+		ss = append(ss, structElemList(e.Kids, level+1)...)
+	}
+	return ss
+}
+
+// StructureList returns a list representation of the logical structure of ctx.
+func StructureList(ctx *model.Context) ([]string, error) {
+	st, err := StructureTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if st == nil {
+		return []string{"no structure tree available"}, nil
+	}
+
+	ss := []string{fmt.Sprintf("Marked: %t", st.Marked)}
+	if st.Lang != "" {
+		ss = append(ss, "Lang: "+st.Lang)
+	}
+
+	if len(st.RoleMap) > 0 {
+		ss = append(ss, "RoleMap:")
+		keys := make([]string, 0, len(st.RoleMap))
+		for k := range st.RoleMap {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ss = append(ss, fmt.Sprintf("    %s -> %s", k, st.RoleMap[k]))
+		}
+	}
+
+	ss = append(ss, "StructTreeRoot:")
+
+	return append(ss, structElemList(st.Elements, 1)...), nil
+}
+
+// ExportStructure returns the logical structure of ctx along with a header.
+func ExportStructure(ctx *model.Context, source string) (*StructTree, error) {
+	st, err := StructureTree(ctx)
+	if err != nil || st == nil {
+		return nil, err
+	}
+
+	st.Header = header(ctx.XRefTable, source)
+
+	return st, nil
+}
+
+// ExportStructureJSON writes a JSON representation of the logical structure of ctx to w.
+func ExportStructureJSON(ctx *model.Context, source string, w io.Writer) (bool, error) {
+	st, err := ExportStructure(ctx, source)
+	if err != nil || st == nil {
+		return false, err
+	}
+
+	bb, err := json.MarshalIndent(st, "", "\t")
+	if err != nil {
+		return false, err
+	}
+
+	_, err = w.Write(bb)
+
+	return true, err
+}
@@ -34,6 +34,12 @@ type PDFFilter struct {
 	DecodeParms Dict
 }
 
+// StreamSource locates the encoded content of a stream within the file it has been read from.
+type StreamSource struct {
+	RS     io.ReadSeeker
+	Offset int64
+}
+
 // StreamDict represents a PDF stream dict object.
 type StreamDict struct {
 	Dict
@@ -41,8 +47,9 @@ type StreamDict struct {
 	StreamLength      *int64
 	StreamLengthObjNr *int
 	FilterPipeline    []PDFFilter
-	Raw               []byte // Encoded
-	Content           []byte // Decoded
+	Raw               []byte        // Encoded
+	Content           []byte        // Decoded
+	Source            *StreamSource // Location of Raw if not loaded.
 	//DCTImage          image.Image
 	IsPageContent bool
 	CSComponents  int
@@ -58,6 +65,7 @@ func NewStreamDict(d Dict, streamOffset int64, streamLength *int64, streamLength
 		filterPipeline,
 		nil,
 		nil,
+		nil,
 		//nil,
 		false,
 		0,
@@ -81,6 +89,44 @@ func (sd StreamDict) Clone() Object {
 	return sd1
 }
 
+// Detached returns true if sd's encoded content has not been loaded and is still located in its source file.
+func (sd StreamDict) Detached() bool {
+	return sd.Raw == nil && sd.Source != nil && sd.StreamLength != nil
+}
+
+// RawContent returns sd's encoded content and reads it from sd's source if necessary.
+func (sd StreamDict) RawContent() ([]byte, error) {
+	if !sd.Detached() {
+		return sd.Raw, nil
+	}
+
+	if _, err := sd.Source.RS.Seek(sd.Source.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, *sd.StreamLength)
+	if _, err := io.ReadFull(sd.Source.RS, buf); err != nil {
+		return nil, errors.Wrapf(err, "pdfcpu: RawContent: failed to read stream content at offset %d", sd.Source.Offset)
+	}
+
+	return buf, nil
+}
+
+// CopyRawContent writes sd's encoded content to w.
+// A detached stream gets copied from its source file without being held in memory.
+func (sd StreamDict) CopyRawContent(w io.Writer) (int64, error) {
+	if !sd.Detached() {
+		n, err := w.Write(sd.Raw)
+		return int64(n), err
+	}
+
+	if _, err := sd.Source.RS.Seek(sd.Source.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return io.CopyN(w, sd.Source.RS, *sd.StreamLength)
+}
+
 // HasSoleFilterNamed returns true if sd has a
 // filterPipeline with 1 filter named filterName.
 func (sd StreamDict) HasSoleFilterNamed(filterName string) bool {
@@ -237,7 +283,7 @@ func parmsForFilter(d Dict) map[string]int {
 
 // Encode applies sd's filter pipeline to sd.Content in order to produce sd.Raw.
 func (sd *StreamDict) Encode() error {
-	if sd.Content == nil && sd.Raw != nil {
+	if sd.Content == nil && (sd.Raw != nil || sd.Detached()) {
 		// Not decoded yet, no need to encode.
 		return nil
 	}
@@ -320,22 +366,23 @@ func fixParms(f PDFFilter, parms map[string]int, sd *StreamDict) error {
 }
 
 // Decode applies sd's filter pipeline to sd.Raw in order to produce sd.Content.
-func (sd *StreamDict) Decode() error {
-	_, err := sd.DecodeLength(-1)
+func (sd *StreamDict) Decode(opts ...filter.Option) error {
+	_, err := sd.DecodeLength(-1, opts...)
 	return err
 }
 
-func (sd *StreamDict) decodeLength(maxLen int64) ([]byte, error) {
+func (sd *StreamDict) decodeLength(maxLen int64, opts ...filter.Option) ([]byte, error) {
+	raw, err := sd.RawContent()
+	if err != nil {
+		return nil, err
+	}
+
 	var b, c io.Reader
-	b = bytes.NewReader(sd.Raw)
+	b = bytes.NewReader(raw)
 
 	// Apply each filter in the pipeline to result of preceding filter.
 	for idx, f := range sd.FilterPipeline {
 
-		if f.Name == filter.JPX {
-			break
-		}
-
 		if f.Name == filter.DCT {
 			if sd.CSComponents != 4 {
 				break
@@ -358,7 +405,7 @@ func (sd *StreamDict) decodeLength(maxLen int64) ([]byte, error) {
 			return nil, err
 		}
 
-		fi, err := filter.NewFilter(f.Name, parms)
+		fi, err := filter.NewFilter(f.Name, parms, opts...)
 		if err != nil {
 			return nil, err
 		}
@@ -396,7 +443,7 @@ func (sd *StreamDict) decodeLength(maxLen int64) ([]byte, error) {
 	return data[:maxLen], nil
 }
 
-func (sd *StreamDict) DecodeLength(maxLen int64) ([]byte, error) {
+func (sd *StreamDict) DecodeLength(maxLen int64, opts ...filter.Option) ([]byte, error) {
 	if sd.Content != nil {
 		// This stream has already been decoded.
 		if maxLen < 0 {
@@ -408,9 +455,13 @@ func (sd *StreamDict) DecodeLength(maxLen int64) ([]byte, error) {
 
 	fpl := sd.FilterPipeline
 
-	// No filter or sole filter DTC && !CMYK or JPX - nothing to decode.
-	if fpl == nil || len(fpl) == 1 && ((fpl[0].Name == filter.DCT && sd.CSComponents != 4) || fpl[0].Name == filter.JPX) {
-		sd.Content = sd.Raw
+	// No filter or sole filter DTC && !CMYK - nothing to decode.
+	if fpl == nil || len(fpl) == 1 && fpl[0].Name == filter.DCT && sd.CSComponents != 4 {
+		raw, err := sd.RawContent()
+		if err != nil {
+			return nil, err
+		}
+		sd.Content = raw
 		//fmt.Printf("decodedStream returning %d(#%02x)bytes: \n%s\n", len(sd.Content), len(sd.Content), hex.Dump(sd.Content))
 		if maxLen < 0 {
 			return sd.Content, nil
@@ -421,7 +472,7 @@ func (sd *StreamDict) DecodeLength(maxLen int64) ([]byte, error) {
 
 	//fmt.Printf("decodedStream before:\n%s\n", hex.Dump(sd.Raw))
 
-	return sd.decodeLength(maxLen)
+	return sd.decodeLength(maxLen, opts...)
 }
 
 // IndexedObject returns the object at given index from a ObjectStreamDict.
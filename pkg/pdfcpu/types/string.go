@@ -105,6 +105,17 @@ func regularChar(c byte, esc bool) bool {
 	return c != 0x5c && !esc
 }
 
+// StringBytes returns the raw bytes of a string literal or hex literal.
+func StringBytes(o Object) ([]byte, error) {
+	switch o := o.(type) {
+	case StringLiteral:
+		return Unescape(o.Value())
+	case HexLiteral:
+		return o.Bytes()
+	}
+	return nil, errors.New("pdfcpu: expected StringLiteral or HexLiteral")
+}
+
 // Unescape resolves all escape sequences of s.
 func Unescape(s string) ([]byte, error) {
 	var esc bool
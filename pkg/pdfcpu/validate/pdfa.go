@@ -0,0 +1,1160 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pkg/errors"
+)
+
+// PDF/A conformance checking on top of ISO 32000 validation.
+//
+// Rule ids refer to the clauses of ISO 19005-1 (PDF/A-1) and ISO 19005-2 (PDF/A-2, PDF/A-3).
+
+const (
+	pdfaNamespace = "http://www.aiim.org/pdfa/ns/id/"
+
+	// Limit the number of violations recorded per rule.
+	maxViolations = 100
+)
+
+// PDFARule is a PDF/A requirement and its violations.
+type PDFARule struct {
+	ID          string   // clause of ISO 19005
+	Description string   // requirement
+	Violations  []string // empty for passed rules
+	Skipped     int      // number of violations not recorded
+}
+
+// Passed returns true if there are no violations of r.
+func (r PDFARule) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// PDFAReport is the result of checking a document for PDF/A conformance.
+type PDFAReport struct {
+	Part        int    // 1, 2 or 3
+	Conformance string // A, B or U
+	Claimed     bool   // true if the document carries a PDF/A identification
+	Rules       []*PDFARule
+}
+
+// Conformant returns true if no rule is violated.
+func (r PDFAReport) Conformant() bool {
+	for _, rule := range r.Rules {
+		if !rule.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Level returns the PDF/A conformance level being checked eg. PDF/A-1b.
+func (r PDFAReport) Level() string {
+	return fmt.Sprintf("PDF/A-%d%s", r.Part, strings.ToLower(r.Conformance))
+}
+
+func (r PDFAReport) String() string {
+	var sb strings.Builder
+
+	s := r.Level()
+	if !r.Claimed {
+		s += " (assumed)"
+	}
+	fmt.Fprintf(&sb, "%s:\n", s)
+
+	maxLen := 0
+	for _, rule := range r.Rules {
+		maxLen = max(maxLen, len(rule.Description))
+	}
+
+	for _, rule := range r.Rules {
+		status := "ok"
+		if !rule.Passed() {
+			status = "FAILED"
+		}
+		dots := strings.Repeat(".", maxLen-len(rule.Description)+3)
+		fmt.Fprintf(&sb, "%-8s %s %s %s\n", rule.ID, rule.Description, dots, status)
+		for _, v := range rule.Violations {
+			fmt.Fprintf(&sb, "%8s   %s\n", "", v)
+		}
+		if rule.Skipped > 0 {
+			fmt.Fprintf(&sb, "%8s   ... and %d more\n", "", rule.Skipped)
+		}
+	}
+
+	return sb.String()
+}
+
+type pdfaRuleID int
+
+const (
+	ruleTrailer pdfaRuleID = iota
+	ruleStreams
+	ruleFilters
+	ruleOptionalContent
+	ruleEmbeddedFiles
+	ruleOutputIntent
+	ruleColorSpaces
+	ruleImages
+	ruleXObjects
+	ruleFonts
+	ruleTransparency
+	ruleAnnotations
+	ruleActions
+	ruleMetadata
+	ruleIdentification
+	ruleForms
+)
+
+type pdfaRuleDef struct {
+	id1, id2    string // clauses of PDF/A-1 and PDF/A-2/3, empty if not applicable.
+	description string
+}
+
+var pdfaRules = map[pdfaRuleID]pdfaRuleDef{
+	ruleTrailer:         {"6.1.3", "6.1.3", "file identifier present and no encryption"},
+	ruleStreams:         {"6.1.7", "6.1.7.1", "no external stream content"},
+	ruleFilters:         {"6.1.10", "6.1.7.2", "no LZWDecode or Crypt filters"},
+	ruleOptionalContent: {"6.1.13", "", "no optional content"},
+	ruleEmbeddedFiles:   {"6.1.11", "6.8", "embedded files"},
+	ruleOutputIntent:    {"6.2.2", "6.2.3", "PDF/A OutputIntent with valid ICC profile"},
+	ruleColorSpaces:     {"6.2.3", "6.2.4", "device independent color or matching OutputIntent"},
+	ruleImages:          {"6.2.4", "6.2.8", "no image Alternates, OPI or Interpolate"},
+	ruleXObjects:        {"6.2.5", "6.2.9", "no PostScript or reference XObjects"},
+	ruleFonts:           {"6.3.4", "6.2.11.4", "all fonts embedded"},
+	ruleTransparency:    {"6.4", "", "no transparency"},
+	ruleAnnotations:     {"6.5.3", "6.3.2", "annotations printable and visible"},
+	ruleActions:         {"6.6.1", "6.5.1", "no JavaScript or forbidden actions"},
+	ruleMetadata:        {"6.7.2", "6.6.2.1", "XMP metadata in catalog"},
+	ruleIdentification:  {"6.7.11", "6.6.4", "PDF/A identification schema"},
+	ruleForms:           {"6.9", "6.4.1", "no NeedAppearances and no XFA"},
+}
+
+type pdfaChecker struct {
+	ctx     *model.Context
+	report  *PDFAReport
+	rules   map[pdfaRuleID]*PDFARule
+	visited types.IntSet
+
+	// Device color spaces used without a corresponding default color space.
+	deviceCS map[string]string // color space => first usage
+}
+
+func (c *pdfaChecker) violation(id pdfaRuleID, format string, args ...interface{}) {
+	rule, ok := c.rules[id]
+	if !ok {
+		// Rule does not apply for this part.
+		return
+	}
+	if len(rule.Violations) >= maxViolations {
+		rule.Skipped++
+		return
+	}
+	rule.Violations = append(rule.Violations, fmt.Sprintf(format, args...))
+}
+
+func (c *pdfaChecker) applies(id pdfaRuleID) bool {
+	_, ok := c.rules[id]
+	return ok
+}
+
+// pdfaIdentification returns the PDF/A part and conformance level claimed by XMP metadata.
+func pdfaIdentification(bb []byte) (int, string, error) {
+	var part, conformance string
+
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+	var elem string
+
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, "", err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			elem = ""
+			if t.Name.Space == pdfaNamespace {
+				elem = t.Name.Local
+			}
+			for _, a := range t.Attr {
+				if a.Name.Space != pdfaNamespace {
+					continue
+				}
+				switch a.Name.Local {
+				case "part":
+					part = a.Value
+				case "conformance":
+					conformance = a.Value
+				}
+			}
+		case xml.CharData:
+			switch elem {
+			case "part":
+				part += strings.TrimSpace(string(t))
+			case "conformance":
+				conformance += strings.TrimSpace(string(t))
+			}
+		case xml.EndElement:
+			elem = ""
+		}
+	}
+
+	if part == "" {
+		return 0, "", nil
+	}
+
+	i, err := strconv.Atoi(part)
+	if err != nil {
+		return 0, "", errors.Errorf("pdfcpu: invalid pdfaid:part: %s", part)
+	}
+
+	return i, conformance, nil
+}
+
+func (c *pdfaChecker) catalogMetadata(rootDict types.Dict) ([]byte, error) {
+	o, found := rootDict.Find("Metadata")
+	if !found {
+		return nil, nil
+	}
+	sd, _, err := c.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return nil, err
+	}
+	if c.report.Part == 1 {
+		if _, found := sd.Find("Filter"); found {
+			c.violation(ruleMetadata, "metadata stream must not be filtered")
+		}
+	}
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	return sd.Content, nil
+}
+
+func (c *pdfaChecker) checkMetadata(rootDict types.Dict) {
+	bb, err := c.catalogMetadata(rootDict)
+	if err != nil {
+		c.violation(ruleMetadata, "corrupt metadata stream: %v", err)
+		return
+	}
+	if bb == nil {
+		c.violation(ruleMetadata, "missing catalog metadata")
+		c.violation(ruleIdentification, "missing pdfaid:part")
+		return
+	}
+
+	part, conformance, err := pdfaIdentification(bb)
+	if err != nil {
+		c.violation(ruleMetadata, "corrupt XMP: %v", err)
+		return
+	}
+
+	if part == 0 {
+		c.violation(ruleIdentification, "missing pdfaid:part")
+		return
+	}
+
+	if conformance == "" {
+		c.violation(ruleIdentification, "missing pdfaid:conformance")
+	}
+}
+
+func pdfaDefs(part int) map[pdfaRuleID]*PDFARule {
+	m := map[pdfaRuleID]*PDFARule{}
+	for id, def := range pdfaRules {
+		clause := def.id2
+		if part == 1 {
+			clause = def.id1
+		}
+		if clause == "" {
+			continue
+		}
+		m[id] = &PDFARule{ID: clause, Description: def.description}
+	}
+	return m
+}
+
+// clauseLess compares clause numbers like 6.1.3 and 6.1.10.
+func clauseLess(s1, s2 string) bool {
+	ss1, ss2 := strings.Split(s1, "."), strings.Split(s2, ".")
+	for i := 0; i < len(ss1) && i < len(ss2); i++ {
+		i1, _ := strconv.Atoi(ss1[i])
+		i2, _ := strconv.Atoi(ss2[i])
+		if i1 != i2 {
+			return i1 < i2
+		}
+	}
+	return len(ss1) < len(ss2)
+}
+
+func newPDFAChecker(ctx *model.Context, part int, conformance string, claimed bool) *pdfaChecker {
+	c := &pdfaChecker{
+		ctx:      ctx,
+		report:   &PDFAReport{Part: part, Conformance: conformance, Claimed: claimed},
+		rules:    pdfaDefs(part),
+		visited:  types.IntSet{},
+		deviceCS: map[string]string{},
+	}
+
+	for _, rule := range c.rules {
+		c.report.Rules = append(c.report.Rules, rule)
+	}
+	sort.Slice(c.report.Rules, func(i, j int) bool {
+		return clauseLess(c.report.Rules[i].ID, c.report.Rules[j].ID)
+	})
+
+	return c
+}
+
+func (c *pdfaChecker) checkTrailer() {
+	if c.ctx.Encrypt != nil || c.ctx.E != nil {
+		c.violation(ruleTrailer, "document is encrypted")
+	}
+	if len(c.ctx.ID) != 2 {
+		c.violation(ruleTrailer, "missing file identifier")
+	}
+}
+
+func (c *pdfaChecker) checkStreamDict(sd types.StreamDict, objNr int) {
+	for _, k := range []string{"F", "FFilter", "FDecodeParms"} {
+		if _, found := sd.Find(k); found {
+			c.violation(ruleStreams, "obj#%d: stream dict contains %s", objNr, k)
+		}
+	}
+	for _, f := range sd.FilterPipeline {
+		switch f.Name {
+		case filter.LZW:
+			c.violation(ruleFilters, "obj#%d: LZWDecode", objNr)
+		case "Crypt":
+			if c.report.Part == 1 || f.DecodeParms == nil || f.DecodeParms.NameEntry("Name") == nil || *f.DecodeParms.NameEntry("Name") != "Identity" {
+				c.violation(ruleFilters, "obj#%d: Crypt filter", objNr)
+			}
+		}
+	}
+}
+
+var (
+	actionTypes = []string{
+		"GoTo", "GoToR", "GoToE", "GoToDp", "Launch", "Thread", "URI", "Sound", "Movie", "Hide", "Named", "SubmitForm",
+		"ResetForm", "ImportData", "SetOCGState", "Rendition", "Trans", "GoTo3DView", "JavaScript", "RichMediaExecute"}
+
+	forbiddenActions = []string{
+		"Launch", "Sound", "Movie", "ResetForm", "ImportData", "JavaScript", "Hide",
+		"SetOCGState", "Rendition", "Trans", "GoTo3DView", "RichMediaExecute"}
+)
+
+func (c *pdfaChecker) checkAction(d types.Dict, objNr int) {
+	s := d.NameEntry("S")
+	if s == nil {
+		return
+	}
+	if types.MemberOf(*s, forbiddenActions) {
+		c.violation(ruleActions, "obj#%d: %s action", objNr, *s)
+	}
+	if *s == "Named" {
+		if n := d.NameEntry("N"); n != nil && !types.MemberOf(*n, []string{"NextPage", "PrevPage", "FirstPage", "LastPage"}) {
+			c.violation(ruleActions, "obj#%d: named action %s", objNr, *n)
+		}
+	}
+}
+
+func (c *pdfaChecker) checkFileSpec(d types.Dict, objNr int) {
+	if c.report.Part == 1 {
+		c.violation(ruleEmbeddedFiles, "obj#%d: embedded file", objNr)
+		return
+	}
+
+	ef, err := c.ctx.DereferenceDict(d["EF"])
+	if err != nil || ef == nil {
+		return
+	}
+	sd, _, err := c.ctx.DereferenceStreamDict(ef["F"])
+	if err != nil || sd == nil {
+		return
+	}
+
+	subtype := ""
+	if st := sd.Subtype(); st != nil {
+		subtype = *st
+	}
+
+	if c.report.Part == 2 {
+		if subtype != "application/pdf" {
+			c.violation(ruleEmbeddedFiles, "obj#%d: embedded file is not a PDF/A file", objNr)
+		}
+		return
+	}
+
+	// PDF/A-3
+	if subtype == "" {
+		c.violation(ruleEmbeddedFiles, "obj#%d: embedded file without MIME type", objNr)
+	}
+	if d.NameEntry("AFRelationship") == nil {
+		c.violation(ruleEmbeddedFiles, "obj#%d: file spec without AFRelationship", objNr)
+	}
+}
+
+// checkObject checks object o and all its direct objects.
+func (c *pdfaChecker) checkObject(o types.Object, objNr int, depth int) {
+	if depth > 32 {
+		return
+	}
+
+	var d types.Dict
+
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.StreamDict:
+		c.checkStreamDict(o, objNr)
+		d = o.Dict
+	case types.Array:
+		for _, o1 := range o {
+			c.checkObject(o1, objNr, depth+1)
+		}
+		return
+	default:
+		return
+	}
+
+	if s := d.NameEntry("S"); s != nil && types.MemberOf(*s, actionTypes) {
+		c.checkAction(d, objNr)
+	}
+
+	if _, found := d.Find("EF"); found {
+		c.checkFileSpec(d, objNr)
+	}
+
+	for _, o1 := range d {
+		c.checkObject(o1, objNr, depth+1)
+	}
+}
+
+func (c *pdfaChecker) checkObjects() {
+	var objNrs []int
+	for objNr, e := range c.ctx.Table {
+		if e == nil || e.Free || e.Object == nil {
+			continue
+		}
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		c.checkObject(c.ctx.Table[objNr].Object, objNr, 0)
+	}
+}
+
+func (c *pdfaChecker) checkCatalog(rootDict types.Dict) {
+	if c.applies(ruleOptionalContent) {
+		if _, found := rootDict.Find("OCProperties"); found {
+			c.violation(ruleOptionalContent, "catalog contains OCProperties")
+		}
+	}
+
+	if _, found := rootDict.Find("AA"); found {
+		c.violation(ruleActions, "catalog contains additional actions")
+	}
+
+	if names, err := c.ctx.DereferenceDict(rootDict["Names"]); err == nil && names != nil {
+		if _, found := names.Find("JavaScript"); found {
+			c.violation(ruleActions, "name dictionary contains JavaScript")
+		}
+		if c.report.Part == 1 {
+			if _, found := names.Find("EmbeddedFiles"); found {
+				c.violation(ruleEmbeddedFiles, "name dictionary contains EmbeddedFiles")
+			}
+		}
+	}
+
+	if acroForm, err := c.ctx.DereferenceDict(rootDict["AcroForm"]); err == nil && acroForm != nil {
+		if b := acroForm.BooleanEntry("NeedAppearances"); b != nil && *b {
+			c.violation(ruleForms, "NeedAppearances is true")
+		}
+		if _, found := acroForm.Find("XFA"); found && c.report.Part > 1 {
+			c.violation(ruleForms, "AcroForm contains XFA")
+		}
+	}
+}
+
+// outputIntentColorSpace checks the PDF/A output intents and returns the color space of the output profile.
+func (c *pdfaChecker) outputIntentColorSpace(rootDict types.Dict) string {
+	// An OutputIntent is only required for using device dependent color, see checkDeviceColorUsage.
+	a, err := c.ctx.DereferenceArray(rootDict["OutputIntents"])
+	if err != nil || len(a) == 0 {
+		return ""
+	}
+
+	var (
+		cs      string
+		profile *types.IndirectRef
+	)
+
+	for _, o := range a {
+		d, err := c.ctx.DereferenceDict(o)
+		if err != nil || d == nil {
+			continue
+		}
+		s := d.NameEntry("S")
+		if s == nil || *s != "GTS_PDFA1" {
+			continue
+		}
+
+		indRef := d.IndirectRefEntry("DestOutputProfile")
+		if indRef == nil {
+			c.violation(ruleOutputIntent, "GTS_PDFA1 OutputIntent without DestOutputProfile")
+			continue
+		}
+		if profile != nil && profile.ObjectNumber != indRef.ObjectNumber {
+			c.violation(ruleOutputIntent, "OutputIntents with different DestOutputProfiles")
+		}
+		profile = indRef
+
+		info, err := c.iccProfile(*indRef)
+		if err != nil {
+			c.violation(ruleOutputIntent, "obj#%d: %v", indRef.ObjectNumber.Value(), err)
+			continue
+		}
+		if info.Class != "prtr" && info.Class != "mntr" {
+			c.violation(ruleOutputIntent, "obj#%d: ICC profile device class %s", indRef.ObjectNumber.Value(), info.Class)
+		}
+		cs = info.ColorSpace
+	}
+
+	return cs
+}
+
+func (c *pdfaChecker) iccProfile(o types.Object) (*pdfcpu.ICCProfileInfo, error) {
+	sd, _, err := c.ctx.DereferenceStreamDict(o)
+	if err != nil {
+		return nil, err
+	}
+	if sd == nil {
+		return nil, errors.New("missing ICC profile")
+	}
+	if err := sd.Decode(); err != nil {
+		return nil, err
+	}
+	info, err := pdfcpu.ParseICCProfile(sd.Content)
+	if err != nil {
+		return nil, err
+	}
+	maxMajor := 4
+	if c.report.Part == 1 {
+		maxMajor = 2
+	}
+	if info.Major > maxMajor {
+		return nil, errors.Errorf("ICC profile version %s not allowed", info.Version)
+	}
+	if n := sd.IntEntry("N"); n != nil && info.N > 0 && *n != info.N {
+		return nil, errors.Errorf("ICC profile: N=%d does not match color space %s", *n, info.ColorSpace)
+	}
+	return info, nil
+}
+
+// checkColorSpace records device color space usage and checks ICC profiles.
+func (c *pdfaChecker) checkColorSpace(o types.Object, defaults types.StringSet, where string, depth int) {
+	if depth > 8 {
+		return
+	}
+
+	o1, err := c.ctx.Dereference(o)
+	if err != nil || o1 == nil {
+		return
+	}
+
+	switch o1 := o1.(type) {
+
+	case types.Name:
+		c.deviceColor(o1.Value(), defaults, where)
+
+	case types.Array:
+		if len(o1) == 0 {
+			return
+		}
+		n, ok := o1[0].(types.Name)
+		if !ok {
+			return
+		}
+		switch n.Value() {
+		case "ICCBased":
+			if len(o1) < 2 {
+				return
+			}
+			if indRef, ok := o1[1].(types.IndirectRef); ok {
+				if c.visited[indRef.ObjectNumber.Value()] {
+					return
+				}
+				c.visited[indRef.ObjectNumber.Value()] = true
+			}
+			if _, err := c.iccProfile(o1[1]); err != nil {
+				c.violation(ruleColorSpaces, "%s: %v", where, err)
+			}
+		case "Indexed":
+			if len(o1) > 1 {
+				c.checkColorSpace(o1[1], defaults, where, depth+1)
+			}
+		case "Separation", "DeviceN":
+			if len(o1) > 2 {
+				c.checkColorSpace(o1[2], defaults, where, depth+1)
+			}
+		case "Pattern":
+			if len(o1) > 1 {
+				c.checkColorSpace(o1[1], defaults, where, depth+1)
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) deviceColor(cs string, defaults types.StringSet, where string) {
+	switch cs {
+	case "DeviceGray", "G":
+		cs = "Gray"
+	case "DeviceRGB", "RGB":
+		cs = "RGB"
+	case "DeviceCMYK", "CMYK":
+		cs = "CMYK"
+	default:
+		return
+	}
+	if defaults["Default"+cs] {
+		return
+	}
+	if _, ok := c.deviceCS[cs]; !ok {
+		c.deviceCS[cs] = where
+	}
+}
+
+func (c *pdfaChecker) checkDeviceColorUsage(intentCS string) {
+	var ss []string
+	for cs := range c.deviceCS {
+		ss = append(ss, cs)
+	}
+	sort.Strings(ss)
+
+	for _, cs := range ss {
+		where := c.deviceCS[cs]
+		switch cs {
+		case "Gray":
+			if intentCS == "" {
+				c.violation(ruleColorSpaces, "%s: DeviceGray used without OutputIntent", where)
+			}
+		case "RGB", "CMYK":
+			if intentCS != cs {
+				c.violation(ruleColorSpaces, "%s: Device%s used without matching OutputIntent", where, cs)
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) checkExtGState(d types.Dict, where string) {
+	if !c.applies(ruleTransparency) {
+		return
+	}
+	if o, found := d.Find("SMask"); found {
+		if n, ok := o.(types.Name); !ok || n.Value() != "None" {
+			c.violation(ruleTransparency, "%s: ExtGState with soft mask", where)
+		}
+	}
+	for _, k := range []string{"CA", "ca"} {
+		if o, found := d.Find(k); found {
+			if f, err := c.ctx.DereferenceNumber(o); err == nil && f != 1 {
+				c.violation(ruleTransparency, "%s: ExtGState %s=%.2f", where, k, f)
+			}
+		}
+	}
+	if n := d.NameEntry("BM"); n != nil && *n != "Normal" && *n != "Compatible" {
+		c.violation(ruleTransparency, "%s: blend mode %s", where, *n)
+	}
+	if _, found := d.Find("TR"); found {
+		c.violation(ruleTransparency, "%s: ExtGState with transfer function", where)
+	}
+}
+
+func (c *pdfaChecker) checkGroup(d types.Dict, where string) {
+	if !c.applies(ruleTransparency) {
+		return
+	}
+	g, err := c.ctx.DereferenceDict(d["Group"])
+	if err != nil || g == nil {
+		return
+	}
+	if s := g.NameEntry("S"); s != nil && *s == "Transparency" {
+		c.violation(ruleTransparency, "%s: transparency group", where)
+	}
+}
+
+func (c *pdfaChecker) checkFont(d types.Dict, where string) {
+	st := d.Subtype()
+	if st == nil {
+		return
+	}
+
+	name := ""
+	if n := d.NameEntry("BaseFont"); n != nil {
+		name = *n
+	}
+
+	switch *st {
+
+	case "Type3":
+		if res, err := c.ctx.DereferenceDict(d["Resources"]); err == nil && res != nil {
+			c.checkResources(res, where+", Type3 font "+name)
+		}
+		return
+
+	case "Type0":
+		a, err := c.ctx.DereferenceArray(d["DescendantFonts"])
+		if err != nil || len(a) == 0 {
+			c.violation(ruleFonts, "%s: font %s: missing descendant font", where, name)
+			return
+		}
+		if d, err = c.ctx.DereferenceDict(a[0]); err != nil || d == nil {
+			c.violation(ruleFonts, "%s: font %s: missing descendant font", where, name)
+			return
+		}
+	}
+
+	fd, err := c.ctx.DereferenceDict(d["FontDescriptor"])
+	if err != nil || fd == nil {
+		c.violation(ruleFonts, "%s: font %s not embedded", where, name)
+		return
+	}
+
+	for _, k := range []string{"FontFile", "FontFile2", "FontFile3"} {
+		if _, found := fd.Find(k); found {
+			return
+		}
+	}
+
+	c.violation(ruleFonts, "%s: font %s not embedded", where, name)
+}
+
+func (c *pdfaChecker) checkImage(sd *types.StreamDict, defaults types.StringSet, where string) {
+	if _, found := sd.Find("Alternates"); found {
+		c.violation(ruleImages, "%s: image with Alternates", where)
+	}
+	if _, found := sd.Find("OPI"); found {
+		c.violation(ruleImages, "%s: image with OPI", where)
+	}
+	if b := sd.BooleanEntry("Interpolate"); b != nil && *b {
+		c.violation(ruleImages, "%s: image with Interpolate", where)
+	}
+	if c.applies(ruleTransparency) {
+		if _, found := sd.Find("SMask"); found {
+			c.violation(ruleTransparency, "%s: image with soft mask", where)
+		}
+	}
+	if o, found := sd.Find("ColorSpace"); found {
+		c.checkColorSpace(o, defaults, where, 0)
+	}
+}
+
+func (c *pdfaChecker) checkForm(sd *types.StreamDict, where string) {
+	if _, found := sd.Find("OPI"); found {
+		c.violation(ruleXObjects, "%s: form XObject with OPI", where)
+	}
+	if _, found := sd.Find("Ref"); found {
+		c.violation(ruleXObjects, "%s: reference XObject", where)
+	}
+	if n := sd.NameEntry("Subtype2"); n != nil && *n == "PS" {
+		c.violation(ruleXObjects, "%s: PostScript XObject", where)
+	}
+	if _, found := sd.Find("PS"); found {
+		c.violation(ruleXObjects, "%s: form XObject with PS", where)
+	}
+	c.checkGroup(sd.Dict, where)
+
+	res, err := c.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil {
+		return
+	}
+	c.checkContent(sd, res, where)
+	if res != nil {
+		c.checkResources(res, where)
+	}
+}
+
+func (c *pdfaChecker) checkXObject(o types.Object, defaults types.StringSet, where string) {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		if c.visited[indRef.ObjectNumber.Value()] {
+			return
+		}
+		c.visited[indRef.ObjectNumber.Value()] = true
+		where = fmt.Sprintf("%s, obj#%d", where, indRef.ObjectNumber.Value())
+	}
+
+	sd, _, err := c.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return
+	}
+
+	st := sd.Subtype()
+	if st == nil {
+		return
+	}
+
+	switch *st {
+	case "Image":
+		c.checkImage(sd, defaults, where)
+	case "Form":
+		c.checkForm(sd, where)
+	case "PS":
+		c.violation(ruleXObjects, "%s: PostScript XObject", where)
+	}
+}
+
+// defaultColorSpaces returns the default color spaces defined in res.
+func (c *pdfaChecker) defaultColorSpaces(res types.Dict) types.StringSet {
+	ss := types.StringSet{}
+	d, err := c.ctx.DereferenceDict(res["ColorSpace"])
+	if err != nil || d == nil {
+		return ss
+	}
+	for _, k := range []string{"DefaultGray", "DefaultRGB", "DefaultCMYK"} {
+		if _, found := d.Find(k); found {
+			ss[k] = true
+		}
+	}
+	return ss
+}
+
+func (c *pdfaChecker) checkResources(res types.Dict, where string) {
+	defaults := c.defaultColorSpaces(res)
+
+	if d, err := c.ctx.DereferenceDict(res["Font"]); err == nil {
+		for _, o := range d {
+			if indRef, ok := o.(types.IndirectRef); ok {
+				if c.visited[indRef.ObjectNumber.Value()] {
+					continue
+				}
+				c.visited[indRef.ObjectNumber.Value()] = true
+			}
+			if fd, err := c.ctx.DereferenceDict(o); err == nil && fd != nil {
+				c.checkFont(fd, where)
+			}
+		}
+	}
+
+	if d, err := c.ctx.DereferenceDict(res["XObject"]); err == nil {
+		for _, o := range d {
+			c.checkXObject(o, defaults, where)
+		}
+	}
+
+	if d, err := c.ctx.DereferenceDict(res["ExtGState"]); err == nil {
+		for _, o := range d {
+			if gs, err := c.ctx.DereferenceDict(o); err == nil && gs != nil {
+				c.checkExtGState(gs, where)
+			}
+		}
+	}
+
+	if d, err := c.ctx.DereferenceDict(res["ColorSpace"]); err == nil {
+		for _, o := range d {
+			c.checkColorSpace(o, defaults, where, 0)
+		}
+	}
+
+	if d, err := c.ctx.DereferenceDict(res["Shading"]); err == nil {
+		for _, o := range d {
+			if sh, err := c.ctx.DereferenceDict(o); err == nil && sh != nil {
+				c.checkColorSpace(sh["ColorSpace"], defaults, where, 0)
+			} else if sd, _, err := c.ctx.DereferenceStreamDict(o); err == nil && sd != nil {
+				c.checkColorSpace(sd.Dict["ColorSpace"], defaults, where, 0)
+			}
+		}
+	}
+
+	if d, err := c.ctx.DereferenceDict(res["Pattern"]); err == nil {
+		for _, o := range d {
+			c.checkPattern(o, defaults, where)
+		}
+	}
+}
+
+func (c *pdfaChecker) checkPattern(o types.Object, defaults types.StringSet, where string) {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		if c.visited[indRef.ObjectNumber.Value()] {
+			return
+		}
+		c.visited[indRef.ObjectNumber.Value()] = true
+	}
+
+	o1, err := c.ctx.Dereference(o)
+	if err != nil {
+		return
+	}
+
+	switch o1 := o1.(type) {
+	case types.Dict:
+		// Shading pattern
+		if sh, err := c.ctx.DereferenceDict(o1["Shading"]); err == nil && sh != nil {
+			c.checkColorSpace(sh["ColorSpace"], defaults, where, 0)
+		} else if sd, _, err := c.ctx.DereferenceStreamDict(o1["Shading"]); err == nil && sd != nil {
+			c.checkColorSpace(sd.Dict["ColorSpace"], defaults, where, 0)
+		}
+		if gs, err := c.ctx.DereferenceDict(o1["ExtGState"]); err == nil && gs != nil {
+			c.checkExtGState(gs, where)
+		}
+	case types.StreamDict:
+		// Tiling pattern
+		res, err := c.ctx.DereferenceDict(o1.Dict["Resources"])
+		if err != nil {
+			return
+		}
+		c.checkContent(&o1, res, where)
+		if res != nil {
+			c.checkResources(res, where)
+		}
+	}
+}
+
+// checkContent records device color space usage by content stream operators.
+func (c *pdfaChecker) checkContent(sd *types.StreamDict, res types.Dict, where string) {
+	if err := sd.Decode(); err != nil {
+		return
+	}
+	c.checkContentBytes(sd.Content, res, where)
+}
+
+func (c *pdfaChecker) checkContentBytes(bb []byte, res types.Dict, where string) {
+	defaults := c.defaultColorSpaces(res)
+
+	p := model.NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err != nil {
+			return
+		}
+		switch op.Operator {
+		case model.OpSetFillGray, model.OpSetStrokeGray:
+			c.deviceColor("DeviceGray", defaults, where)
+		case model.OpSetFillRGB, model.OpSetStrokeRGB:
+			c.deviceColor("DeviceRGB", defaults, where)
+		case model.OpSetFillCMYK, model.OpSetStrokeCMYK:
+			c.deviceColor("DeviceCMYK", defaults, where)
+		case model.OpSetFillColorSpace, model.OpSetStrokeColorSpace:
+			if len(op.Operands) == 1 {
+				if n, ok := op.Operands[0].(types.Name); ok {
+					c.deviceColor(n.Value(), defaults, where)
+				}
+			}
+		case model.OpBeginInlineImage:
+			if op.Image == nil {
+				continue
+			}
+			d := op.Image.Dict
+			for _, k := range []string{"CS", "ColorSpace"} {
+				if n := d.NameEntry(k); n != nil {
+					c.deviceColor(*n, defaults, where)
+				}
+			}
+			for _, k := range []string{"I", "Interpolate"} {
+				if b := d.BooleanEntry(k); b != nil && *b {
+					c.violation(ruleImages, "%s: inline image with Interpolate", where)
+				}
+			}
+		}
+	}
+}
+
+func (c *pdfaChecker) checkAnnotation(d types.Dict, where string) {
+	st := d.Subtype()
+	if st == nil {
+		return
+	}
+
+	forbidden := []string{"Sound", "Movie", "Screen", "3D", "RichMedia"}
+	if c.report.Part == 1 {
+		forbidden = append(forbidden, "FileAttachment")
+	}
+	if types.MemberOf(*st, forbidden) {
+		c.violation(ruleAnnotations, "%s: %s annotation", where, *st)
+	}
+
+	if c.applies(ruleTransparency) {
+		if o, found := d.Find("CA"); found {
+			if f, err := c.ctx.DereferenceNumber(o); err == nil && f != 1 {
+				c.violation(ruleTransparency, "%s: %s annotation with CA=%.2f", where, *st, f)
+			}
+		}
+	}
+
+	if *st == "Widget" {
+		if _, found := d.Find("AA"); found {
+			c.violation(ruleActions, "%s: widget annotation with additional actions", where)
+		}
+	}
+
+	if *st != "Popup" {
+		f := 0
+		if i := d.IntEntry("F"); i != nil {
+			f = *i
+		}
+		flags := model.AnnotationFlags(f)
+		if flags&model.AnnPrint == 0 {
+			c.violation(ruleAnnotations, "%s: %s annotation not printable", where, *st)
+		}
+		if flags&(model.AnnInvisible|model.AnnHidden|model.AnnNoView|model.AnnToggleNoView) != 0 {
+			c.violation(ruleAnnotations, "%s: %s annotation hidden", where, *st)
+		}
+	}
+
+	ap, err := c.ctx.DereferenceDict(d["AP"])
+	if err != nil || ap == nil {
+		return
+	}
+	if c.report.Part > 1 {
+		for _, k := range []string{"R", "D"} {
+			if _, found := ap.Find(k); found && *st != "Widget" {
+				c.violation(ruleAnnotations, "%s: %s annotation with /%s appearance", where, *st, k)
+			}
+		}
+	}
+
+	o, found := ap.Find("N")
+	if !found {
+		return
+	}
+	o1, err := c.ctx.Dereference(o)
+	if err != nil {
+		return
+	}
+	if states, ok := o1.(types.Dict); ok {
+		for _, o := range states {
+			c.checkXObject(o, nil, where)
+		}
+		return
+	}
+	c.checkXObject(o, nil, where)
+}
+
+func (c *pdfaChecker) checkPage(pageNr int) error {
+	d, _, inhPAttrs, err := c.ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return nil
+	}
+
+	where := fmt.Sprintf("page %d", pageNr)
+
+	if _, found := d.Find("AA"); found {
+		c.violation(ruleActions, "%s: page with additional actions", where)
+	}
+
+	c.checkGroup(d, where)
+
+	res := inhPAttrs.Resources
+
+	bb, err := c.ctx.PageContent(d)
+	if err == nil {
+		c.checkContentBytes(bb, res, where)
+	} else if err != model.ErrNoContent {
+		return err
+	}
+
+	if res != nil {
+		c.checkResources(res, where)
+	}
+
+	a, err := c.ctx.DereferenceArray(d["Annots"])
+	if err != nil {
+		return nil
+	}
+	for _, o := range a {
+		if ad, err := c.ctx.DereferenceDict(o); err == nil && ad != nil {
+			c.checkAnnotation(ad, where)
+		}
+	}
+
+	return nil
+}
+
+// PDFA checks a validated document for PDF/A conformance.
+// Unless part > 0 the document is checked against the PDF/A part it claims to conform to.
+// Documents without PDF/A identification are checked against PDF/A-1b.
+func PDFA(ctx *model.Context, part int) (*PDFAReport, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	claimedPart, conformance := 0, ""
+	if o, found := rootDict.Find("Metadata"); found {
+		if sd, _, err := ctx.DereferenceStreamDict(o); err == nil && sd != nil && sd.Decode() == nil {
+			claimedPart, conformance, _ = pdfaIdentification(sd.Content)
+		}
+	}
+
+	claimed := claimedPart > 0
+	if part == 0 {
+		part = claimedPart
+	}
+	if part < 1 || part > 3 {
+		part = 1
+	}
+
+	conformance = strings.ToUpper(conformance)
+	if conformance == "" || part == 1 && conformance == "U" {
+		conformance = "B"
+	}
+
+	c := newPDFAChecker(ctx, part, conformance, claimed)
+
+	if claimed && claimedPart != part {
+		c.violation(ruleIdentification, "document claims PDF/A-%d", claimedPart)
+	}
+	if claimed && !types.MemberOf(conformance, []string{"A", "B", "U"}) {
+		c.violation(ruleIdentification, "invalid pdfaid:conformance %s", conformance)
+	}
+
+	c.checkTrailer()
+	c.checkCatalog(rootDict)
+	c.checkMetadata(rootDict)
+	c.checkObjects()
+
+	intentCS := c.outputIntentColorSpace(rootDict)
+
+	for i := 1; i <= ctx.PageCount; i++ {
+		if err := c.checkPage(i); err != nil {
+			return nil, err
+		}
+	}
+
+	c.checkDeviceColorUsage(intentCS)
+
+	return c.report, nil
+}
@@ -0,0 +1,753 @@
+/*
+Copyright 2026 The pdfcpu Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PDF/UA and tagged PDF accessibility checking on top of ISO 32000 validation.
+//
+// Rule ids refer to the clauses of ISO 14289-1 (PDF/UA-1).
+
+const (
+	pdfuaNamespace = "http://www.aiim.org/pdfua/ns/id/"
+	dcNamespace    = "http://purl.org/dc/elements/1.1/"
+
+	// Limit the nesting of form XObjects and role map lookups.
+	maxPDFUADepth = 20
+)
+
+// PDFUARule is a PDF/UA requirement and its violations.
+type PDFUARule struct {
+	ID          string   // clause of ISO 14289-1
+	Description string   // requirement
+	Violations  []string // empty for passed rules
+	Skipped     int      // number of violations not recorded
+}
+
+// Passed returns true if there are no violations of r.
+func (r PDFUARule) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// PDFUAReport is the result of checking a document for PDF/UA conformance.
+type PDFUAReport struct {
+	Claimed bool // true if the document carries a PDF/UA identification
+	Rules   []*PDFUARule
+}
+
+// Conformant returns true if no rule is violated.
+func (r PDFUAReport) Conformant() bool {
+	for _, rule := range r.Rules {
+		if !rule.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r PDFUAReport) String() string {
+	var sb strings.Builder
+
+	s := "PDF/UA-1"
+	if !r.Claimed {
+		s += " (assumed)"
+	}
+	fmt.Fprintf(&sb, "%s:\n", s)
+
+	maxLen := 0
+	for _, rule := range r.Rules {
+		maxLen = max(maxLen, len(rule.Description))
+	}
+
+	for _, rule := range r.Rules {
+		status := "ok"
+		if !rule.Passed() {
+			status = "FAILED"
+		}
+		dots := strings.Repeat(".", maxLen-len(rule.Description)+3)
+		fmt.Fprintf(&sb, "%-8s %s %s %s\n", rule.ID, rule.Description, dots, status)
+		for _, v := range rule.Violations {
+			fmt.Fprintf(&sb, "%8s   %s\n", "", v)
+		}
+		if rule.Skipped > 0 {
+			fmt.Fprintf(&sb, "%8s   ... and %d more\n", "", rule.Skipped)
+		}
+	}
+
+	return sb.String()
+}
+
+type pdfuaRuleID int
+
+const (
+	uaRuleIdentification pdfuaRuleID = iota
+	uaRuleTagged
+	uaRuleContent
+	uaRuleMarkedContent
+	uaRuleTitle
+	uaRuleLanguage
+	uaRuleFigures
+	uaRuleHeadings
+)
+
+var pdfuaRules = []struct {
+	id          pdfuaRuleID
+	clause      string
+	description string
+}{
+	{uaRuleIdentification, "5", "PDF/UA identification schema"},
+	{uaRuleTagged, "7.1", "marked as tagged PDF with structure tree"},
+	{uaRuleContent, "7.1", "content tagged or marked as artifact"},
+	{uaRuleMarkedContent, "7.1", "marked content and structure tree match"},
+	{uaRuleTitle, "7.1", "document title displayed"},
+	{uaRuleLanguage, "7.2", "document language specified"},
+	{uaRuleFigures, "7.3", "figures with alternate text"},
+	{uaRuleHeadings, "7.4.2", "headings properly nested"},
+}
+
+// standardStructureTypes are the standard structure types of ISO 32000-1 14.8.4.
+var standardStructureTypes = types.StringSet{
+	"Document": true, "Part": true, "Art": true, "Sect": true, "Div": true, "BlockQuote": true, "Caption": true,
+	"TOC": true, "TOCI": true, "Index": true, "NonStruct": true, "Private": true,
+	"P": true, "H": true, "H1": true, "H2": true, "H3": true, "H4": true, "H5": true, "H6": true,
+	"L": true, "LI": true, "Lbl": true, "LBody": true,
+	"Table": true, "TR": true, "TH": true, "TD": true, "THead": true, "TBody": true, "TFoot": true,
+	"Span": true, "Quote": true, "Note": true, "Reference": true, "BibEntry": true, "Code": true,
+	"Link": true, "Annot": true, "Ruby": true, "RB": true, "RT": true, "RP": true,
+	"Warichu": true, "WT": true, "WP": true, "Figure": true, "Formula": true, "Form": true,
+}
+
+// markedContent records the MCIDs found in a content stream.
+type markedContent struct {
+	where string
+	mcids types.IntSet
+}
+
+type pdfuaChecker struct {
+	ctx    *model.Context
+	report *PDFUAReport
+	rules  map[pdfuaRuleID]*PDFUARule
+
+	roleMap types.Dict
+
+	// Content streams by object number of the page or form XObject.
+	content map[int]*markedContent
+
+	// Forms already scanned for untagged content.
+	scannedForms types.IntSet
+
+	// MCIDs referenced by the structure tree by object number of their content stream.
+	referenced map[int]types.IntSet
+
+	visited types.IntSet
+
+	lastHeading int
+}
+
+func (c *pdfuaChecker) violation(id pdfuaRuleID, format string, args ...interface{}) {
+	rule := c.rules[id]
+	if len(rule.Violations) >= maxViolations {
+		rule.Skipped++
+		return
+	}
+	rule.Violations = append(rule.Violations, fmt.Sprintf(format, args...))
+}
+
+func newPDFUAChecker(ctx *model.Context) *pdfuaChecker {
+	c := &pdfuaChecker{
+		ctx:          ctx,
+		report:       &PDFUAReport{},
+		rules:        map[pdfuaRuleID]*PDFUARule{},
+		content:      map[int]*markedContent{},
+		scannedForms: types.IntSet{},
+		referenced:   map[int]types.IntSet{},
+		visited:      types.IntSet{},
+	}
+
+	for _, def := range pdfuaRules {
+		rule := &PDFUARule{ID: def.clause, Description: def.description}
+		c.rules[def.id] = rule
+		c.report.Rules = append(c.report.Rules, rule)
+	}
+
+	return c
+}
+
+// xmpProperties returns the PDF/UA part and the document title found in XMP metadata.
+func xmpProperties(bb []byte) (part, title string, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(bb))
+
+	var elem string
+	inTitle := false
+
+	for {
+		t, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		switch t := t.(type) {
+		case xml.StartElement:
+			elem = ""
+			if t.Name.Space == pdfuaNamespace && t.Name.Local == "part" {
+				elem = "part"
+			}
+			if t.Name.Space == dcNamespace && t.Name.Local == "title" {
+				inTitle = true
+			}
+			for _, a := range t.Attr {
+				if a.Name.Space == pdfuaNamespace && a.Name.Local == "part" {
+					part = a.Value
+				}
+			}
+		case xml.CharData:
+			s := strings.TrimSpace(string(t))
+			if elem == "part" {
+				part += s
+			}
+			if inTitle {
+				title += s
+			}
+		case xml.EndElement:
+			elem = ""
+			if t.Name.Space == dcNamespace && t.Name.Local == "title" {
+				inTitle = false
+			}
+		}
+	}
+
+	return part, title, nil
+}
+
+func (c *pdfuaChecker) checkMetadata(rootDict types.Dict) {
+	var bb []byte
+	if o, found := rootDict.Find("Metadata"); found {
+		sd, _, err := c.ctx.DereferenceStreamDict(o)
+		if err == nil && sd != nil && sd.Decode() == nil {
+			bb = sd.Content
+		}
+	}
+
+	if bb == nil {
+		c.violation(uaRuleIdentification, "missing catalog metadata")
+		c.violation(uaRuleTitle, "missing dc:title")
+		return
+	}
+
+	part, title, err := xmpProperties(bb)
+	if err != nil {
+		c.violation(uaRuleIdentification, "corrupt XMP: %v", err)
+		return
+	}
+
+	switch part {
+	case "":
+		c.violation(uaRuleIdentification, "missing pdfuaid:part")
+	case "1":
+		c.report.Claimed = true
+	default:
+		c.report.Claimed = true
+		c.violation(uaRuleIdentification, "invalid pdfuaid:part %s", part)
+	}
+
+	if title == "" {
+		c.violation(uaRuleTitle, "missing dc:title")
+	}
+}
+
+func (c *pdfuaChecker) checkCatalog(rootDict types.Dict) {
+	d, err := c.ctx.DereferenceDict(rootDict["MarkInfo"])
+	if err != nil || d == nil {
+		c.violation(uaRuleTagged, "missing MarkInfo")
+	} else {
+		if b := d.BooleanEntry("Marked"); b == nil || !*b {
+			c.violation(uaRuleTagged, "MarkInfo: Marked is not true")
+		}
+		if b := d.BooleanEntry("Suspects"); b != nil && *b {
+			c.violation(uaRuleTagged, "MarkInfo: Suspects is true")
+		}
+	}
+
+	if _, found := rootDict.Find("StructTreeRoot"); !found {
+		c.violation(uaRuleTagged, "missing StructTreeRoot")
+	}
+
+	if s, err := c.ctx.DereferenceText(rootDict["Lang"]); err != nil || strings.TrimSpace(s) == "" {
+		c.violation(uaRuleLanguage, "missing catalog Lang")
+	}
+
+	d, err = c.ctx.DereferenceDict(rootDict["ViewerPreferences"])
+	if err != nil || d == nil {
+		c.violation(uaRuleTitle, "missing ViewerPreferences")
+		return
+	}
+	if b := d.BooleanEntry("DisplayDocTitle"); b == nil || !*b {
+		c.violation(uaRuleTitle, "ViewerPreferences: DisplayDocTitle is not true")
+	}
+}
+
+// markedContentID returns the MCID of the properties of a BDC operator.
+func (c *pdfuaChecker) markedContentID(o types.Object, res types.Dict) (int, bool) {
+	var d types.Dict
+	switch o := o.(type) {
+	case types.Dict:
+		d = o
+	case types.Name:
+		props, err := c.ctx.DereferenceDict(res["Properties"])
+		if err != nil || props == nil {
+			return 0, false
+		}
+		if d, err = c.ctx.DereferenceDict(props[o.Value()]); err != nil || d == nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	i := d.IntEntry("MCID")
+	if i == nil {
+		return 0, false
+	}
+	return *i, true
+}
+
+// scanContent records the MCIDs of the content stream identified by objNr
+// and counts painting operators outside of tagged content and artifacts.
+func (c *pdfuaChecker) scanContent(bb []byte, res types.Dict, objNr int, where string, tagged bool, depth int) int {
+	// Record MCIDs of rescanned forms only once.
+	mc := &markedContent{where: where, mcids: types.IntSet{}}
+	if c.content[objNr] == nil {
+		c.content[objNr] = mc
+	}
+
+	// For each level of marked content: true if tagged or an artifact.
+	var stack []bool
+	untagged := 0
+
+	p := model.NewContentParser(bb)
+	for {
+		op, err := p.Next()
+		if err != nil {
+			break
+		}
+
+		inTag := tagged || len(stack) > 0 && stack[len(stack)-1]
+
+		switch op.Operator {
+
+		case model.OpBeginMarkedContent:
+			isArtifact := false
+			if len(op.Operands) == 1 {
+				if n, ok := op.Operands[0].(types.Name); ok {
+					isArtifact = n.Value() == "Artifact"
+				}
+			}
+			stack = append(stack, inTag || isArtifact)
+
+		case model.OpBeginMarkedContentProps:
+			t := inTag
+			if len(op.Operands) == 2 {
+				if n, ok := op.Operands[0].(types.Name); ok && n.Value() == "Artifact" {
+					t = true
+				}
+				if mcid, ok := c.markedContentID(op.Operands[1], res); ok {
+					if mc.mcids[mcid] {
+						c.violation(uaRuleMarkedContent, "%s: duplicate MCID %d", where, mcid)
+					}
+					mc.mcids[mcid] = true
+					t = true
+				}
+			}
+			stack = append(stack, t)
+
+		case model.OpEndMarkedContent:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case model.OpPaintXObject:
+			if len(op.Operands) == 1 {
+				if n, ok := op.Operands[0].(types.Name); ok {
+					untagged += c.scanXObject(n.Value(), res, where, inTag, depth)
+				}
+			}
+
+		default:
+			if !inTag && op.Operator.Paints() {
+				untagged++
+			}
+		}
+	}
+
+	return untagged
+}
+
+// scanXObject returns the number of untagged painting operations caused by invoking XObject name.
+func (c *pdfuaChecker) scanXObject(name string, res types.Dict, where string, tagged bool, depth int) int {
+	d, err := c.ctx.DereferenceDict(res["XObject"])
+	if err != nil || d == nil {
+		return 0
+	}
+
+	o, found := d.Find(name)
+	if !found {
+		return 0
+	}
+
+	sd, _, err := c.ctx.DereferenceStreamDict(o)
+	if err != nil || sd == nil {
+		return 0
+	}
+
+	st := sd.Subtype()
+	if st == nil || *st != "Form" {
+		if tagged {
+			return 0
+		}
+		return 1
+	}
+
+	indRef, ok := o.(types.IndirectRef)
+	if !ok || depth >= maxPDFUADepth {
+		return 0
+	}
+
+	objNr := indRef.ObjectNumber.Value()
+
+	// Scan each form once in untagged context for content and once for its MCIDs.
+	if c.scannedForms[objNr] || tagged && c.content[objNr] != nil {
+		return 0
+	}
+	if !tagged {
+		c.scannedForms[objNr] = true
+	}
+
+	if err := sd.Decode(); err != nil {
+		return 0
+	}
+
+	formRes, err := c.ctx.DereferenceDict(sd.Dict["Resources"])
+	if err != nil || formRes == nil {
+		formRes = res
+	}
+
+	where = fmt.Sprintf("%s, obj#%d", where, objNr)
+
+	return c.scanContent(sd.Content, formRes, objNr, where, tagged, depth+1)
+}
+
+func (c *pdfuaChecker) checkPage(pageNr int) error {
+	d, indRef, inhPAttrs, err := c.ctx.PageDict(pageNr, false)
+	if err != nil {
+		return err
+	}
+	if d == nil || indRef == nil {
+		return nil
+	}
+
+	where := fmt.Sprintf("page %d", pageNr)
+
+	bb, err := c.ctx.PageContent(d)
+	if err == model.ErrNoContent {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	objNr := indRef.ObjectNumber.Value()
+
+	if n := c.scanContent(bb, inhPAttrs.Resources, objNr, where, false, 0); n > 0 {
+		c.violation(uaRuleContent, "%s: %d untagged content operations", where, n)
+	}
+
+	if len(c.content[objNr].mcids) > 0 && d.IntEntry("StructParents") == nil {
+		c.violation(uaRuleMarkedContent, "%s: missing StructParents", where)
+	}
+
+	return nil
+}
+
+// structureType returns the standard structure type s is mapped to by the role map.
+func (c *pdfuaChecker) structureType(s string) string {
+	for i := 0; i < maxPDFUADepth && !standardStructureTypes[s]; i++ {
+		n := c.roleMap.NameEntry(s)
+		if n == nil {
+			break
+		}
+		s = *n
+	}
+	return s
+}
+
+func (c *pdfuaChecker) reference(pageObjNr, mcid int, where string) {
+	if pageObjNr == 0 {
+		c.violation(uaRuleMarkedContent, "%s: MCID %d without page", where, mcid)
+		return
+	}
+
+	mcids := c.referenced[pageObjNr]
+	if mcids == nil {
+		mcids = types.IntSet{}
+		c.referenced[pageObjNr] = mcids
+	}
+
+	if mcids[mcid] {
+		c.violation(uaRuleMarkedContent, "%s: MCID %d referenced more than once", where, mcid)
+	}
+	mcids[mcid] = true
+}
+
+func objectNumber(o types.Object) int {
+	if indRef, ok := o.(types.IndirectRef); ok {
+		return indRef.ObjectNumber.Value()
+	}
+	return 0
+}
+
+func (c *pdfuaChecker) checkHeading(s, where string) {
+	if len(s) != 2 || s[0] != 'H' || s[1] < '1' || s[1] > '6' {
+		return
+	}
+
+	level := int(s[1] - '0')
+
+	if c.lastHeading == 0 && level != 1 {
+		c.violation(uaRuleHeadings, "%s: first heading is %s", where, s)
+	}
+	if c.lastHeading > 0 && level > c.lastHeading+1 {
+		c.violation(uaRuleHeadings, "%s: %s follows H%d", where, s, c.lastHeading)
+	}
+
+	c.lastHeading = level
+}
+
+// checkKids processes the K entry of a structure element.
+func (c *pdfuaChecker) checkKids(o types.Object, pageObjNr int, where string, depth int) {
+	o, err := c.ctx.Dereference(o)
+	if err != nil || o == nil {
+		return
+	}
+
+	switch o := o.(type) {
+
+	case types.Integer:
+		c.reference(pageObjNr, o.Value(), where)
+
+	case types.Array:
+		for _, o1 := range o {
+			c.checkKid(o1, pageObjNr, where, depth)
+		}
+
+	case types.Dict:
+		c.checkKid(o, pageObjNr, where, depth)
+	}
+}
+
+func (c *pdfuaChecker) checkKid(o types.Object, pageObjNr int, where string, depth int) {
+	if objNr := objectNumber(o); objNr > 0 {
+		if c.visited[objNr] {
+			return
+		}
+		c.visited[objNr] = true
+	}
+
+	o1, err := c.ctx.Dereference(o)
+	if err != nil || o1 == nil {
+		return
+	}
+
+	switch o1 := o1.(type) {
+
+	case types.Integer:
+		c.reference(pageObjNr, o1.Value(), where)
+
+	case types.Dict:
+		t := o1.Type()
+
+		if t != nil && *t == "MCR" {
+			mcid := o1.IntEntry("MCID")
+			if mcid == nil {
+				return
+			}
+			objNr := pageObjNr
+			if pg := objectNumber(o1["Pg"]); pg > 0 {
+				objNr = pg
+			}
+			if stm := objectNumber(o1["Stm"]); stm > 0 {
+				objNr = stm
+			}
+			c.reference(objNr, *mcid, where)
+			return
+		}
+
+		if t != nil && *t == "OBJR" {
+			return
+		}
+
+		c.checkStructElem(o1, objectNumber(o), pageObjNr, depth+1)
+	}
+}
+
+func (c *pdfuaChecker) checkStructElem(d types.Dict, objNr, pageObjNr int, depth int) {
+	if depth > maxPDFUADepth*10 {
+		return
+	}
+
+	s := ""
+	if n := d.NameEntry("S"); n != nil {
+		s = c.structureType(*n)
+	}
+
+	where := s
+	if objNr > 0 {
+		where = fmt.Sprintf("%s (obj#%d)", s, objNr)
+	}
+
+	if s == "Figure" {
+		_, hasAlt := d.Find("Alt")
+		_, hasActualText := d.Find("ActualText")
+		if !hasAlt && !hasActualText {
+			c.violation(uaRuleFigures, "%s: missing Alt", where)
+		}
+	}
+
+	c.checkHeading(s, where)
+
+	if pg := objectNumber(d["Pg"]); pg > 0 {
+		pageObjNr = pg
+	}
+
+	if o, found := d.Find("K"); found {
+		c.checkKids(o, pageObjNr, where, depth)
+	}
+}
+
+func (c *pdfuaChecker) checkStructTree(rootDict types.Dict) {
+	d, err := c.ctx.DereferenceDict(rootDict["StructTreeRoot"])
+	if err != nil || d == nil {
+		return
+	}
+
+	if c.roleMap, err = c.ctx.DereferenceDict(d["RoleMap"]); err != nil {
+		c.roleMap = nil
+	}
+
+	if o, found := d.Find("K"); found {
+		c.checkKids(o, 0, "StructTreeRoot", 0)
+	}
+}
+
+// scanReferencedStreams collects the MCIDs of form XObjects referenced by the structure tree
+// which are not invoked by page content, eg. annotation appearances.
+func (c *pdfuaChecker) scanReferencedStreams() {
+	for objNr := range c.referenced {
+		if c.content[objNr] != nil {
+			continue
+		}
+		sd, _, err := c.ctx.DereferenceStreamDict(*types.NewIndirectRef(objNr, 0))
+		if err != nil || sd == nil || sd.Decode() != nil {
+			continue
+		}
+		res, _ := c.ctx.DereferenceDict(sd.Dict["Resources"])
+		c.scanContent(sd.Content, res, objNr, fmt.Sprintf("obj#%d", objNr), true, 0)
+	}
+}
+
+func sortedKeys(ss types.IntSet) []int {
+	ii := make([]int, 0, len(ss))
+	for i := range ss {
+		ii = append(ii, i)
+	}
+	sort.Ints(ii)
+	return ii
+}
+
+// crossCheckMarkedContent compares the MCIDs found in content streams with the MCIDs referenced by the structure tree.
+func (c *pdfuaChecker) crossCheckMarkedContent() {
+	objNrs := types.IntSet{}
+	for objNr := range c.content {
+		objNrs[objNr] = true
+	}
+	for objNr := range c.referenced {
+		objNrs[objNr] = true
+	}
+
+	for _, objNr := range sortedKeys(objNrs) {
+		mc := c.content[objNr]
+		referenced := c.referenced[objNr]
+
+		if mc == nil {
+			for _, mcid := range sortedKeys(referenced) {
+				c.violation(uaRuleMarkedContent, "obj#%d: structure tree references MCID %d of unknown content", objNr, mcid)
+			}
+			continue
+		}
+
+		for _, mcid := range sortedKeys(mc.mcids) {
+			if !referenced[mcid] {
+				c.violation(uaRuleMarkedContent, "%s: MCID %d not referenced by structure tree", mc.where, mcid)
+			}
+		}
+
+		for _, mcid := range sortedKeys(referenced) {
+			if !mc.mcids[mcid] {
+				c.violation(uaRuleMarkedContent, "%s: structure tree references missing MCID %d", mc.where, mcid)
+			}
+		}
+	}
+}
+
+// PDFUA checks a validated document for PDF/UA-1 conformance.
+// The report covers tagging of page content, consistency of marked content and structure tree,
+// alternate text for figures, document language, document title and heading order.
+func PDFUA(ctx *model.Context) (*PDFUAReport, error) {
+	rootDict, err := ctx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	c := newPDFUAChecker(ctx)
+
+	c.checkCatalog(rootDict)
+	c.checkMetadata(rootDict)
+
+	for i := 1; i <= ctx.PageCount; i++ {
+		if err := c.checkPage(i); err != nil {
+			return nil, err
+		}
+	}
+
+	c.checkStructTree(rootDict)
+	c.scanReferencedStreams()
+	c.crossCheckMarkedContent()
+
+	return c.report, nil
+}
@@ -90,6 +90,70 @@ func XRefTable(ctx *model.Context) error {
 	return nil
 }
 
+// XRefTableLazily validates the document catalog entries needed by read only commands of a lazily read ctx.
+// The page tree, resources and content streams get parsed and loaded on first access only.
+func XRefTableLazily(ctx *model.Context) error {
+	if log.ValidateEnabled() {
+		log.Validate.Println("*** validateXRefTableLazily begin ***")
+	}
+
+	xRefTable := ctx.XRefTable
+
+	if err := validateDocumentInfoObject(xRefTable); err != nil {
+		return err
+	}
+
+	d, err := xRefTable.Catalog()
+	if err != nil {
+		return err
+	}
+
+	if _, err = validateNameEntry(xRefTable, d, "rootDict", "Type", REQUIRED, model.V10, func(s string) bool { return s == "Catalog" }); err != nil {
+		return err
+	}
+
+	if err := xRefTable.EnsurePageCount(); err != nil {
+		return err
+	}
+
+	for _, f := range []struct {
+		validate     func(xRefTable *model.XRefTable, d types.Dict, required bool, sinceVersion model.Version) (err error)
+		sinceVersion model.Version
+	}{
+		{validateRootVersion, model.V14},
+		{validateNames, model.V12},
+		{validateNamedDestinations, model.V11},
+		{validateViewerPreferences, model.V12},
+		{validatePageLayout, model.V10},
+		{validatePageMode, model.V10},
+		{validateOutlines, model.V10},
+		{validateMarkInfo, model.V14},
+		{validateLang, model.V10},
+	} {
+		if xRefTable.Version() < f.sinceVersion {
+			continue
+		}
+		if err = f.validate(xRefTable, d, OPTIONAL, f.sinceVersion); err != nil {
+			return err
+		}
+	}
+
+	// Recognize AcroForms without validating their field trees.
+	acroForm, err := xRefTable.DereferenceDict(d["AcroForm"])
+	if err != nil {
+		return err
+	}
+	if _, ok := acroForm.Find("Fields"); ok {
+		xRefTable.Form = acroForm
+	}
+
+	if log.ValidateEnabled() {
+		log.Validate.Println("*** validateXRefTableLazily end ***")
+	}
+
+	return nil
+}
+
 func fixInfoDict(xRefTable *model.XRefTable, rootDict types.Dict) error {
 	indRef := rootDict.IndirectRefEntry("Metadata")
 	ok, err := model.EqualObjects(*indRef, *xRefTable.Info, xRefTable)
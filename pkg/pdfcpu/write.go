@@ -123,21 +123,15 @@ func Write(ctx *model.Context) (err error) {
 		log.Write.Printf("offset after writeHeader: %d\n", ctx.Write.Offset)
 	}
 
-	if err := writeObjects(ctx); err != nil {
-		return err
-	}
-
-	// Mark redundant objects as free.
-	// eg. duplicate resources, compressed objects, linearization dicts..
-	deleteRedundantObjects(ctx)
-
-	if err = writeXRef(ctx); err != nil {
-		return err
-	}
-
-	// Write pdf trailer.
-	if err = writeTrailer(ctx.Write); err != nil {
-		return err
+	if ctx.Linearize {
+		// Write objects and cross reference sections for Fast Web View.
+		if err = writeLinearized(ctx); err != nil {
+			return err
+		}
+	} else {
+		if err = writeBody(ctx); err != nil {
+			return err
+		}
 	}
 
 	if err = setFileSizeOfWrittenFile(ctx.Write); err != nil {
@@ -153,6 +147,23 @@ func Write(ctx *model.Context) (err error) {
 	return nil
 }
 
+func writeBody(ctx *model.Context) error {
+	if err := writeObjects(ctx); err != nil {
+		return err
+	}
+
+	// Mark redundant objects as free.
+	// eg. duplicate resources, compressed objects, linearization dicts..
+	deleteRedundantObjects(ctx)
+
+	if err := writeXRef(ctx); err != nil {
+		return err
+	}
+
+	// Write pdf trailer.
+	return writeTrailer(ctx.Write)
+}
+
 // WriteIncrement writes a PDF increment..
 func WriteIncrement(ctx *model.Context) error {
 	// Write all modified objects that are part of this increment.
@@ -170,6 +181,11 @@ func WriteIncrement(ctx *model.Context) error {
 }
 
 func prepareContextForWriting(ctx *model.Context) error {
+	// Lazily read files need all objects in place.
+	if err := LoadLazyObjects(ctx); err != nil {
+		return err
+	}
+
 	if err := ensureInfoDictAndFileID(ctx); err != nil {
 		return err
 	}
@@ -909,13 +925,9 @@ func writeEncryptDict(ctx *model.Context) error {
 	return writeObject(ctx, objNumber, genNumber, d.PDFString())
 }
 
-func setupEncryption(ctx *model.Context) error {
+func setupStandardEncryption(ctx *model.Context) (types.Dict, error) {
 	var err error
 
-	if ok := validateAlgorithm(ctx); !ok {
-		return errors.New("pdfcpu: unsupported encryption algorithm (PDF 2.0 assumes AES/256)")
-	}
-
 	d := newEncryptDict(
 		ctx.XRefTable.Version(),
 		ctx.EncryptUsingAES,
@@ -924,22 +936,44 @@ func setupEncryption(ctx *model.Context) error {
 	)
 
 	if ctx.E, err = supportedEncryption(ctx, d); err != nil {
-		return err
+		return nil, err
 	}
 
 	if ctx.ID == nil {
-		return errors.New("pdfcpu: encrypt: missing ID")
+		return nil, errors.New("pdfcpu: encrypt: missing ID")
 	}
 
 	if ctx.E.ID, err = ctx.IDFirstElement(); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err = calcOAndU(ctx, d); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err = writePermissions(ctx, d); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func setupEncryption(ctx *model.Context) error {
+	var (
+		d   types.Dict
+		err error
+	)
+
+	if ok := validateAlgorithm(ctx); !ok {
+		return errors.New("pdfcpu: unsupported encryption algorithm (PDF 2.0 assumes AES/256)")
+	}
+
+	if len(ctx.Recipients) > 0 {
+		d, err = setupPubSecEncryption(ctx)
+	} else {
+		d, err = setupStandardEncryption(ctx)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -961,6 +995,10 @@ func updateEncryption(ctx *model.Context) error {
 		return errors.New("pdfcpu: This file is not encrypted - nothing written.")
 	}
 
+	if ctx.E.Recipients != nil {
+		return errors.New("pdfcpu: passwords and permissions not applicable for public-key encryption")
+	}
+
 	d, err := ctx.EncryptDict()
 	if err != nil {
 		return err
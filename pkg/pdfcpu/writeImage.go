@@ -18,7 +18,10 @@ package pdfcpu
 
 import (
 	"bytes"
+	"compress/zlib"
+	"encoding/binary"
 	"encoding/gob"
+	"hash/crc32"
 	"image"
 	"image/color"
 	"image/png"
@@ -192,15 +195,12 @@ func streamBytes(sd *types.StreamDict) ([]byte, error) {
 
 	switch f {
 
-	case filter.DCT, filter.Flate, filter.CCITTFax, filter.ASCII85, filter.RunLength:
+	case filter.DCT, filter.Flate, filter.CCITTFax, filter.JBIG2, filter.ASCII85, filter.RunLength, filter.JPX:
 		// If color space is CMYK then write .tif else write .png
 		if err := sd.Decode(); err != nil {
 			return nil, err
 		}
 
-	case filter.JPX:
-		//imageObj.Extension = "jpx"
-
 	default:
 		if log.DebugEnabled() {
 			log.Debug.Printf("streamBytes: skip img, filter %s unsupported\n", filters)
@@ -829,14 +829,18 @@ func renderDeviceN(xRefTable *model.XRefTable, im *PDFImage, resourceName string
 }
 
 func renderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
-	// If color space is CMYK then write .tif else write .png
-
 	pdfImage, err := pdfImage(xRefTable, sd, thumb, objNr)
 	if err != nil {
 		return nil, "", err
 	}
 
-	o, err := xRefTable.DereferenceDictEntry(sd.Dict, "ColorSpace")
+	return renderPDFImage(xRefTable, pdfImage, resourceName, objNr)
+}
+
+func renderPDFImage(xRefTable *model.XRefTable, pdfImage *PDFImage, resourceName string, objNr int) (io.Reader, string, error) {
+	// If color space is CMYK then write .tif else write .png
+
+	o, err := xRefTable.DereferenceDictEntry(pdfImage.sd.Dict, "ColorSpace")
 	if err != nil {
 		return nil, "", err
 	}
@@ -941,6 +945,154 @@ func renderDCTToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool
 	return renderCMYKToPng(im, resourceName)
 }
 
+func renderJPXCMYKToPNG(im *PDFImage) (io.Reader, string, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, im.w, im.h))
+	b := im.sd.Content
+	i := 0
+
+	for y := 0; y < im.h; y++ {
+		for x := 0; x < im.w; x++ {
+			r, g, bl := color.CMYKToRGB(b[i], b[i+1], b[i+2], b[i+3])
+			alpha := uint8(255)
+			if im.softMask != nil {
+				alpha = im.softMask[y*im.w+x]
+			}
+			img.SetNRGBA(x, y, color.NRGBA{r, g, bl, alpha})
+			i += 4
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, "png", nil
+}
+
+// jpxData returns the JPEG 2000 data of sd which is the input of its last filter.
+func jpxData(sd *types.StreamDict) ([]byte, error) {
+	if len(sd.FilterPipeline) < 2 {
+		return sd.RawContent()
+	}
+
+	sd1 := *sd
+	sd1.Content = nil
+	sd1.FilterPipeline = sd.FilterPipeline[:len(sd.FilterPipeline)-1]
+
+	return sd1.DecodeLength(-1)
+}
+
+// embedICCProfile inserts an iCCP chunk right after the IHDR chunk of a PNG image.
+// The profile is ignored unless its colour space matches the colour type of the image.
+func embedICCProfile(bb []byte, icc []byte, cs string) []byte {
+	const ihdrEnd = 8 + 8 + 13 + 4
+
+	if len(bb) < ihdrEnd || string(bb[12:16]) != "IHDR" {
+		return bb
+	}
+
+	gray := bb[25] == 0 || bb[25] == 4
+	if gray != (cs == "GRAY") || !gray && cs != "RGB" {
+		return bb
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("iCCP")
+	buf.WriteString("ICC Profile")
+	buf.Write([]byte{0, 0}) // keyword terminator, compression method deflate
+	zw := zlib.NewWriter(&buf)
+	zw.Write(icc)
+	zw.Close()
+	data := buf.Bytes()
+
+	chunk := make([]byte, 4, len(data)+8)
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)-4))
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(data))
+
+	bb1 := make([]byte, 0, len(bb)+len(chunk))
+	bb1 = append(bb1, bb[:ihdrEnd]...)
+	bb1 = append(bb1, chunk...)
+	return append(bb1, bb[ihdrEnd:]...)
+}
+
+func renderJPXToPNG(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
+	bb, err := jpxData(sd)
+	if err != nil {
+		return nil, "", err
+	}
+
+	jpx, err := filter.DecodeJPX(bytes.NewReader(bb))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Render a copy of sd holding the decoded samples.
+	// The image dictionary's ColorSpace takes precedence over the colour space of the JP2 header.
+	sd1 := *sd
+	sd1.Dict = sd.Dict.Clone().(types.Dict)
+	sd1.Content = jpx.Samples
+	sd1.Update("BitsPerComponent", types.Integer(8))
+
+	// The ICC profile of the JP2 header, if any.
+	var icc *ICCProfileInfo
+
+	if _, found := sd1.Find("ColorSpace"); !found {
+		cs := jpx.ColorSpace
+		if jpx.ICCProfile != nil {
+			if info, err := ParseICCProfile(jpx.ICCProfile); err == nil && info.N == jpx.NumComps {
+				icc = info
+			}
+		}
+		if cs == "" {
+			switch jpx.NumComps {
+			case 1:
+				cs = model.DeviceGrayCS
+			case 3:
+				cs = model.DeviceRGBCS
+			case 4:
+				cs = model.DeviceCMYKCS
+			default:
+				return nil, "", errors.Errorf("pdfcpu: renderJPXToPNG: objNr=%d, unsupported number of components: %d", objNr, jpx.NumComps)
+			}
+		}
+		sd1.InsertName("ColorSpace", cs)
+	}
+
+	im, err := pdfImage(xRefTable, &sd1, thumb, objNr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// SMaskInData applies unless an SMask entry is present.
+	if smid := sd.IntEntry("SMaskInData"); smid != nil && *smid > 0 && im.softMask == nil {
+		if _, found := sd.Find("SMask"); !found {
+			im.softMask = jpx.Alpha
+		}
+	}
+
+	if len(im.sd.Content) < im.comp*im.w*im.h {
+		return nil, "", errors.Errorf("pdfcpu: renderJPXToPNG: objNr=%d, corrupt image object", objNr)
+	}
+
+	if im.comp == 4 {
+		return renderJPXCMYKToPNG(im)
+	}
+
+	r, t, err := renderPDFImage(xRefTable, im, resourceName, objNr)
+	if err != nil || icc == nil || t != "png" {
+		return r, t, err
+	}
+
+	buf, ok := r.(*bytes.Buffer)
+	if !ok {
+		return r, t, nil
+	}
+
+	return bytes.NewBuffer(embedICCProfile(buf.Bytes(), jpx.ICCProfile, icc.ColorSpace)), t, nil
+}
+
 // RenderImage returns a reader for a decoded image stream.
 func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, resourceName string, objNr int) (io.Reader, string, error) {
 	// Image compression is the last filter in the pipeline.
@@ -953,7 +1105,7 @@ func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, r
 
 	switch f {
 
-	case filter.Flate, filter.LZW, filter.CCITTFax, filter.RunLength:
+	case filter.Flate, filter.LZW, filter.CCITTFax, filter.JBIG2, filter.RunLength:
 		return renderImage(xRefTable, sd, thumb, resourceName, objNr)
 
 	case filter.DCT:
@@ -963,7 +1115,19 @@ func RenderImage(xRefTable *model.XRefTable, sd *types.StreamDict, thumb bool, r
 		return bytes.NewReader(sd.Content), "jpg", nil
 
 	case filter.JPX:
-		return bytes.NewReader(sd.Content), "jpx", nil
+		r, t, err := renderJPXToPNG(xRefTable, sd, thumb, resourceName, objNr)
+		if err != nil {
+			// Fall back to the raw JPEG 2000 data.
+			if log.InfoEnabled() {
+				log.Info.Printf("RenderImage: objNr=%d, %v\n", objNr, err)
+			}
+			bb, err := jpxData(sd)
+			if err != nil {
+				return nil, "", err
+			}
+			return bytes.NewReader(bb), "jpx", nil
+		}
+		return r, t, nil
 	}
 
 	return nil, "", nil
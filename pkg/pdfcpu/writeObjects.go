@@ -399,11 +399,12 @@ func writeStream(w *model.WriteContext, sd types.StreamDict) (int64, error) {
 		return 0, errors.Wrapf(err, "writeStream: failed to write raw content")
 	}
 
-	c, err := w.Write(sd.Raw)
+	// Detached streams get copied from their source file.
+	c, err := sd.CopyRawContent(w)
 	if err != nil {
 		return 0, errors.Wrapf(err, "writeStream: failed to write raw content")
 	}
-	if int64(c) != *sd.StreamLength {
+	if c != *sd.StreamLength {
 		return 0, errors.Errorf("writeStream: failed to write raw content: %d bytes written - streamlength:%d", c, *sd.StreamLength)
 	}
 
@@ -489,7 +490,12 @@ func writeStreamDictObject(ctx *model.Context, objNr, genNr int, sd types.Stream
 		!isXRefStreamDict &&
 		!(len(sd.FilterPipeline) == 1 && sd.FilterPipeline[0].Name == "Crypt") {
 
-		if sd.Raw, err = encryptStream(sd.Raw, objNr, genNr, ctx.EncKey, ctx.AES4Streams, ctx.E.R); err != nil {
+		raw, err := sd.RawContent()
+		if err != nil {
+			return err
+		}
+
+		if sd.Raw, err = encryptStream(raw, objNr, genNr, ctx.EncKey, ctx.AES4Streams, ctx.E.R); err != nil {
 			return err
 		}
 